@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/notifier"
+	"github.com/my-backend-project/internal/task/repository"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// MongoDB接続設定
+	mongoClient, err := connectMongoDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer mongoClient.Disconnect(context.Background())
+
+	// インデックスの作成
+	if err := repository.EnsureIndexes(context.Background(), mongoClient.Database("task")); err != nil {
+		log.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	// リポジトリの初期化
+	taskRepo := repository.NewTaskRepository(mongoClient.Database("task"))
+	reminderRepo := repository.NewReminderRepository(mongoClient.Database("task"))
+	mentionRepo := repository.NewMentionRepository(mongoClient.Database("task"))
+	userDirectory := repository.NewUserDirectory(mongoClient.Database(os.Getenv("MONGO_DB_NAME")))
+
+	// 通知先の初期化（設定がある通知先のみ有効）
+	var channels []notifier.Channel
+	if url := os.Getenv("USER_SERVICE_URL"); url != "" {
+		channels = append(channels, notifier.NewInboxChannel(url, os.Getenv("INTERNAL_API_TOKEN"), nil))
+	}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		var auth smtp.Auth
+		if username := os.Getenv("SMTP_USERNAME"); username != "" {
+			host := addr
+			if i := strings.LastIndex(addr, ":"); i >= 0 {
+				host = addr[:i]
+			}
+			auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+		}
+		recipients := notifier.NewMongoRecipientLookup(mongoClient.Database(os.Getenv("MONGO_DB_NAME")))
+		channels = append(channels, notifier.NewEmailChannel(addr, os.Getenv("SMTP_FROM"), auth, recipients))
+	}
+	if url := os.Getenv("NOTIFIER_WEBHOOK_URL"); url != "" {
+		channels = append(channels, notifier.NewWebhookChannel(url, []byte(os.Getenv("NOTIFIER_WEBHOOK_SECRET")), nil))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Invalid notifier config: %v", err)
+	}
+
+	scheduler := notifier.NewScheduler(reminderRepo, mentionRepo, taskRepo, userDirectory, channels, cfg)
+	log.Printf("Starting notifier with %d channel(s)", len(channels))
+	if err := scheduler.Run(ctx); err != nil {
+		log.Fatalf("Notifier stopped: %v", err)
+	}
+	log.Printf("Notifier stopped")
+}
+
+func loadConfig() (notifier.Config, error) {
+	cfg := notifier.Config{Owner: os.Getenv("NOTIFIER_ID")}
+
+	if v := os.Getenv("NOTIFIER_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return cfg, fmt.Errorf("NOTIFIER_POLL_INTERVAL: %v", err)
+		}
+		cfg.PollInterval = d
+	}
+	if v := os.Getenv("NOTIFIER_LEASE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return cfg, fmt.Errorf("NOTIFIER_LEASE: %v", err)
+		}
+		cfg.Lease = d
+	}
+	if v := os.Getenv("NOTIFIER_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return cfg, fmt.Errorf("NOTIFIER_MAX_ATTEMPTS: %v", err)
+		}
+		cfg.MaxAttempts = n
+	}
+	if v := os.Getenv("NOTIFIER_DEFAULT_CHANNELS"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			cfg.DefaultChannels = append(cfg.DefaultChannels, model.NotificationChannel(strings.TrimSpace(name)))
+		}
+		if err := model.ValidateReminders([]model.Reminder{{Channels: cfg.DefaultChannels}}); err != nil {
+			return cfg, fmt.Errorf("NOTIFIER_DEFAULT_CHANNELS: %v", err)
+		}
+	}
+	return cfg, nil
+}
+
+func connectMongoDB() (*mongo.Client, error) {
+	ctx := context.Background()
+	mongoURI := os.Getenv("MONGODB_URI")
+	if mongoURI == "" {
+		mongoURI = "mongodb://localhost:27017"
+	}
+
+	clientOptions := options.Client().ApplyURI(mongoURI)
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %v", err)
+	}
+
+	// 接続確認
+	err = client.Ping(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ping MongoDB: %v", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/importer"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// task-import は CSV や JSON Lines のファイルからタスクを取り込みます。
+//
+//	task-import -file tasks.csv -map "title=Name,due_date=Due Date" -dry-run
+//	task-import -file tasks.jsonl -job migration-1
+//
+// 中断した場合は、表示されたジョブIDを -job に指定して実行し直すと続きから取り込みます
+func main() {
+	addr := flag.String("addr", envOr("TASK_SERVICE_ADDR", "localhost:50051"), "タスクサービスのアドレス")
+	token := flag.String("token", os.Getenv("TASK_SERVICE_TOKEN"), "認証トークン（既定値は TASK_SERVICE_TOKEN）")
+	file := flag.String("file", "", "取り込むファイル")
+	format := flag.String("format", "", "ファイルの形式（csv / jsonl）。省略時は拡張子から判断します")
+	columns := flag.String("map", "", "CSV の列の対応（例: title=Name,due_date=Due Date）")
+	jobID := flag.String("job", "", "取り込みジョブID。同じIDで実行し直すと続きから取り込みます")
+	dryRun := flag.Bool("dry-run", false, "検証だけを行い、タスクを作成しません")
+	batchSize := flag.Int("batch-size", 0, "一度に書き込む行数（最大 500）")
+	labelSep := flag.String("label-sep", ";", "CSV でラベルを区切る文字")
+	timezone := flag.String("timezone", "UTC", "時刻を含まない期限を解釈するタイムゾーン")
+	flag.Parse()
+
+	if *file == "" || *token == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		log.Fatalf("Invalid timezone: %v", err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open file: %v", err)
+	}
+	defer f.Close()
+	reader, err := newReader(f, *format, *file, *columns, importer.Options{Location: loc, LabelSeparator: *labelSep})
+	if err != nil {
+		log.Fatalf("Failed to read file: %v", err)
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewImportServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", *token)
+
+	if *jobID == "" {
+		*jobID = primitive.NewObjectID().Hex()
+	}
+	var lastRow int64
+	if !*dryRun {
+		log.Printf("Import job: %s (re-run with -job %s to resume)", *jobID, *jobID)
+		job, err := client.GetImportJob(ctx, &pb.GetImportJobRequest{JobId: *jobID})
+		switch {
+		case status.Code(err) == codes.NotFound:
+		case err != nil:
+			log.Fatalf("Failed to get import job: %v", err)
+		default:
+			lastRow = job.LastRow
+			if lastRow > 0 {
+				log.Printf("Resuming after row %d", lastRow)
+			}
+		}
+	}
+
+	resp, localErrors, err := run(ctx, client, reader, &pb.ImportOptions{
+		JobId:     *jobID,
+		DryRun:    *dryRun,
+		BatchSize: int32(*batchSize),
+	}, lastRow)
+	for _, rowErr := range localErrors {
+		fmt.Fprintf(os.Stderr, "row %d: %s\n", rowErr.RowNumber, rowErr.Message)
+	}
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+	for _, rowErr := range resp.Errors {
+		fmt.Fprintf(os.Stderr, "row %d: %s\n", rowErr.RowNumber, rowErr.Message)
+	}
+	if int64(len(resp.Errors)) < resp.FailedCount {
+		fmt.Fprintf(os.Stderr, "... and %d more errors\n", resp.FailedCount-int64(len(resp.Errors)))
+	}
+
+	fmt.Printf("received: %d, skipped: %d, imported: %d, failed: %d, last row: %d\n",
+		resp.ReceivedCount, resp.SkippedCount, resp.ImportedCount, resp.FailedCount+int64(len(localErrors)), resp.LastRow)
+	if *dryRun {
+		fmt.Println("dry run: no tasks were created")
+	}
+	if resp.FailedCount > 0 || len(localErrors) > 0 {
+		os.Exit(1)
+	}
+}
+
+// run は lastRow より後の行をストリームで送ります。ファイルの読み取りや変換に失敗した行は送らずに返します
+func run(ctx context.Context, client pb.ImportServiceClient, reader importer.Reader, options *pb.ImportOptions, lastRow int64) (*pb.ImportTasksResponse, []*pb.ImportRowError, error) {
+	stream, err := client.ImportTasks(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := stream.Send(&pb.ImportTasksRequest{Payload: &pb.ImportTasksRequest_Options{Options: options}}); err != nil {
+		return nil, nil, err
+	}
+
+	var localErrors []*pb.ImportRowError
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, localErrors, err
+		}
+		if row.Number <= lastRow {
+			continue
+		}
+		if row.Err != nil {
+			localErrors = append(localErrors, &pb.ImportRowError{RowNumber: row.Number, Message: row.Err.Error()})
+			continue
+		}
+		err = stream.Send(&pb.ImportTasksRequest{Payload: &pb.ImportTasksRequest_Row{Row: &pb.ImportRow{RowNumber: row.Number, Task: row.Task}}})
+		if errors.Is(err, io.EOF) {
+			// サーバーがストリームを閉じた理由は CloseAndRecv で受け取る
+			break
+		}
+		if err != nil {
+			return nil, localErrors, err
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	return resp, localErrors, err
+}
+
+func newReader(r io.Reader, format, name, columns string, opts importer.Options) (importer.Reader, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	}
+	switch format {
+	case "csv":
+		mapping, err := importer.ParseColumnMapping(columns)
+		if err != nil {
+			return nil, err
+		}
+		return importer.NewCSVReader(r, mapping, opts)
+	case "jsonl", "ndjson":
+		return importer.NewJSONLReader(r, opts), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q (use -format csv or jsonl)", format)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -2,19 +2,30 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 
 	"log"
 	"net"
+	"net/http"
 
 	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/i18n"
+	"github.com/my-backend-project/internal/pkg/logger"
+	"github.com/my-backend-project/internal/pkg/pagination"
+	"github.com/my-backend-project/internal/task/blob"
 	"github.com/my-backend-project/internal/task/handler"
 	"github.com/my-backend-project/internal/task/interceptor"
 	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
 	"github.com/my-backend-project/internal/task/service"
+	"github.com/my-backend-project/internal/task/watch"
 	"github.com/my-backend-project/internal/user/auth"
 
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc"
@@ -27,11 +38,31 @@ func main() {
 		log.Fatal("JWT_SECRET_KEY is not set")
 	}
 
+	// ロガーの初期化（ハンドラーの内部エラーを記録する）
+	logLevel := os.Getenv("LOG_LEVEL")
+	if logLevel == "" {
+		logLevel = "info"
+	}
+	if err := logger.Init(&logger.Config{Level: logLevel, Console: true}); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
 	grpcPort := os.Getenv("GRPC_PORT")
 	if grpcPort == "" {
 		grpcPort = "50051"
 	}
 
+	// カレンダーフィードを配信する HTTP サーバーのポートと、発行する URL の先頭
+	feedPort := os.Getenv("FEED_HTTP_PORT")
+	if feedPort == "" {
+		feedPort = "8081"
+	}
+	feedBaseURL := os.Getenv("CALENDAR_FEED_BASE_URL")
+	if feedBaseURL == "" {
+		feedBaseURL = "http://localhost:" + feedPort
+	}
+
 	// MongoDB接続設定
 	mongoClient, err := connectMongoDB()
 	if err != nil {
@@ -39,11 +70,96 @@ func main() {
 	}
 	defer mongoClient.Disconnect(context.Background())
 
+	// インデックスの作成
+	if err := repository.EnsureIndexes(context.Background(), mongoClient.Database("task")); err != nil {
+		log.Fatalf("Failed to create indexes: %v", err)
+	}
+
 	// リポジトリの初期化
 	taskRepo := repository.NewTaskRepository(mongoClient.Database("task"))
+	labelRepo := repository.NewLabelRepository(mongoClient.Database("task"))
+	projectRepo := repository.NewProjectRepository(mongoClient.Database("task"))
+	fieldRepo := repository.NewCustomFieldRepository(mongoClient.Database("task"))
+	depRepo := repository.NewDependencyRepository(mongoClient.Database("task"))
+	workflowRepo := repository.NewWorkflowRepository(mongoClient.Database("task"))
+	reminderRepo := repository.NewReminderRepository(mongoClient.Database("task"))
+	commentRepo := repository.NewCommentRepository(mongoClient.Database("task"))
+	mentionRepo := repository.NewMentionRepository(mongoClient.Database("task"))
+	attachmentRepo := repository.NewAttachmentRepository(mongoClient.Database("task"))
+	importJobRepo := repository.NewImportJobRepository(mongoClient.Database("task"))
+	calendarFeedRepo := repository.NewCalendarFeedRepository(mongoClient.Database("task"))
+	timeEntryRepo := repository.NewTimeEntryRepository(mongoClient.Database("task"))
+	templateRepo := repository.NewTemplateRepository(mongoClient.Database("task"))
+	// メンションの解決にはユーザーサービスのデータベースを参照する
+	userDBName := os.Getenv("MONGO_DB_NAME")
+	if userDBName == "" {
+		log.Fatalf("MONGO_DB_NAME is required")
+	}
+	userDirectory := repository.NewUserDirectory(mongoClient.Database(userDBName))
+
+	// タスクの変更の配信元（レプリカセットでは変更ストリーム、それ以外ではプロセス内のイベントバス）
+	watcher := newWatchSource(mongoClient)
+	if bus, ok := watcher.(*watch.Bus); ok {
+		taskRepo = watch.NewPublishingRepository(taskRepo, bus)
+	}
+
+	// ページトークンの署名鍵（未設定の場合はJWTの鍵を流用）
+	pageTokenSecret := os.Getenv("PAGE_TOKEN_SECRET")
+	if pageTokenSecret == "" {
+		pageTokenSecret = jwtSecretKey
+	}
+
+	// 検索バックエンドの初期化（SEARCH_BACKEND=memory で MongoDB のテキストインデックスを使わない）
+	var searcher search.Backend
+	if os.Getenv("SEARCH_BACKEND") == "memory" {
+		searcher = search.NewMemoryBackend(search.DefaultBoosts)
+	} else {
+		if err := search.EnsureTextIndex(context.Background(), mongoClient.Database("task"), search.DefaultBoosts); err != nil {
+			log.Fatalf("Failed to create text index: %v", err)
+		}
+		searcher = search.NewMongoBackend(mongoClient.Database("task"))
+	}
+
+	// 未完了のサブタスクを持つタスクを完了にしたときの振る舞い（block / warn / cascade）
+	completionPolicy, err := service.ParseSubtaskCompletionPolicy(os.Getenv("SUBTASK_COMPLETION_POLICY"))
+	if err != nil {
+		log.Fatalf("Invalid SUBTASK_COMPLETION_POLICY: %v", err)
+	}
+
+	// 添付ファイルの保存先（BLOB_STORE=local でローカルのディレクトリ、それ以外は GridFS）
+	blobs, err := newBlobStore(mongoClient.Database("task"))
+	if err != nil {
+		log.Fatalf("Failed to initialize blob store: %v", err)
+	}
+	attachmentLimits, err := loadAttachmentLimits()
+	if err != nil {
+		log.Fatalf("Invalid attachment limits: %v", err)
+	}
+
+	// タスクの完了時に実行中のタイマーを止めるか（STOP_TIMER_ON_COMPLETE=false で止めない）
+	stopTimerOnComplete := true
+	if v := os.Getenv("STOP_TIMER_ON_COMPLETE"); v != "" {
+		if stopTimerOnComplete, err = strconv.ParseBool(v); err != nil {
+			log.Fatalf("Invalid STOP_TIMER_ON_COMPLETE: %v", err)
+		}
+	}
 
 	// サービスの初期化
-	taskService := service.NewTaskService(taskRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo, taskRepo, blobs, attachmentLimits)
+	timeTrackingService := service.NewTimeTrackingService(timeEntryRepo, taskRepo)
+	var timerStopper service.TimerStopper
+	if stopTimerOnComplete {
+		timerStopper = timeTrackingService
+	}
+	taskService := service.NewTaskService(taskRepo, fieldRepo, depRepo, workflowRepo, reminderRepo, projectRepo, attachmentService, timerStopper, userDirectory, repository.NewTransactor(mongoClient), pagination.NewCodec([]byte(pageTokenSecret)), searcher, watcher, completionPolicy)
+	labelService := service.NewLabelService(labelRepo)
+	projectService := service.NewProjectService(projectRepo, taskService)
+	templateService := service.NewTemplateService(templateRepo, taskService)
+	fieldService := service.NewCustomFieldService(fieldRepo)
+	workflowService := service.NewWorkflowService(workflowRepo)
+	importService := service.NewImportService(importJobRepo, workflowRepo, taskService)
+	calendarFeedService := service.NewCalendarFeedService(calendarFeedRepo, taskRepo)
+	commentService := service.NewCommentService(commentRepo, taskRepo, mentionRepo, userDirectory, pagination.NewCodec([]byte(pageTokenSecret)))
 
 	// JWT サービスの初期化
 	jwtService := auth.NewJWTService(jwtSecretKey)
@@ -51,14 +167,49 @@ func main() {
 	// 認証インターセプターの初期化
 	authInterceptor := interceptor.NewAuthInterceptor(jwtService)
 
-	// gRPCサーバーの初期化
+	// リクエストの検証インターセプターの初期化
+	translator := i18n.GetTranslator()
+	if err := translator.LoadDefaultMessages(); err != nil {
+		log.Fatalf("Failed to load messages: %v", err)
+	}
+	requestValidator, err := handler.NewRequestValidator(translator)
+	if err != nil {
+		log.Fatalf("Failed to create request validator: %v", err)
+	}
+	validationInterceptor := interceptor.NewValidationInterceptor(requestValidator)
+
+	// gRPCサーバーの初期化（認証してから検証する）
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(authInterceptor.Unary()),
+		grpc.ChainUnaryInterceptor(authInterceptor.Unary(), validationInterceptor.Unary()),
+		grpc.ChainStreamInterceptor(authInterceptor.Stream(), validationInterceptor.Stream()),
 	)
 
 	// タスクハンドラーの登録
 	taskHandler := handler.NewTaskHandler(taskService)
 	pb.RegisterTaskServiceServer(server, taskHandler)
+	pb.RegisterLabelServiceServer(server, handler.NewLabelHandler(labelService))
+	pb.RegisterProjectServiceServer(server, handler.NewProjectHandler(projectService))
+	pb.RegisterTemplateServiceServer(server, handler.NewTemplateHandler(templateService))
+	pb.RegisterCustomFieldServiceServer(server, handler.NewCustomFieldHandler(fieldService))
+	pb.RegisterWorkflowServiceServer(server, handler.NewWorkflowHandler(workflowService))
+	pb.RegisterCommentServiceServer(server, handler.NewCommentHandler(commentService))
+	pb.RegisterAttachmentServiceServer(server, handler.NewAttachmentHandler(attachmentService))
+	pb.RegisterImportServiceServer(server, handler.NewImportHandler(importService))
+	pb.RegisterTimeTrackingServiceServer(server, handler.NewTimeTrackingHandler(timeTrackingService))
+	calendarFeedHandler := handler.NewCalendarFeedHandler(calendarFeedService, feedBaseURL)
+	pb.RegisterCalendarFeedServiceServer(server, calendarFeedHandler)
+
+	// カレンダーフィードの HTTP サーバーの起動（カレンダーアプリは JWT を送れないため gRPC とは別に配信する）
+	feedServer := echo.New()
+	feedServer.HideBanner = true
+	feedServer.Use(middleware.Recover())
+	feedServer.GET(handler.CalendarFeedRoute, calendarFeedHandler.ServeFeed)
+	go func() {
+		log.Printf("Starting calendar feed server on :%s", feedPort)
+		if err := feedServer.Start(":" + feedPort); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to serve calendar feed: %v", err)
+		}
+	}()
 
 	// サーバーの起動
 	lis, err := net.Listen("tcp", ":"+grpcPort)
@@ -72,6 +223,55 @@ func main() {
 	}
 }
 
+func newWatchSource(client *mongo.Client) watch.Source {
+	ctx := context.Background()
+	supported, err := watch.SupportsChangeStreams(ctx, client)
+	if err != nil {
+		log.Printf("Failed to detect change stream support, using in-process event bus: %v", err)
+		return watch.NewBus(0)
+	}
+	if !supported {
+		log.Printf("MongoDB is not a replica set, using in-process event bus for WatchTasks")
+		return watch.NewBus(0)
+	}
+	// 削除したタスクの所有者を判定するため変更前イメージが必要（MongoDB 6.0 以降）
+	if err := watch.EnablePreImages(ctx, client.Database("task")); err != nil {
+		log.Printf("Failed to enable change stream pre-images, using in-process event bus: %v", err)
+		return watch.NewBus(0)
+	}
+	return watch.NewChangeStreamSource(client.Database("task"))
+}
+
+func newBlobStore(db *mongo.Database) (blob.Store, error) {
+	if os.Getenv("BLOB_STORE") == "local" {
+		dir := os.Getenv("BLOB_LOCAL_DIR")
+		if dir == "" {
+			dir = "data/attachments"
+		}
+		return blob.NewFileStore(dir)
+	}
+	return blob.NewGridFSStore(db, "attachments")
+}
+
+func loadAttachmentLimits() (service.AttachmentLimits, error) {
+	var limits service.AttachmentLimits
+	if v := os.Getenv("ATTACHMENT_MAX_SIZE"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return limits, fmt.Errorf("ATTACHMENT_MAX_SIZE: %v", err)
+		}
+		limits.MaxSize = n
+	}
+	if v := os.Getenv("ATTACHMENT_QUOTA"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return limits, fmt.Errorf("ATTACHMENT_QUOTA: %v", err)
+		}
+		limits.Quota = n
+	}
+	return limits, nil
+}
+
 func connectMongoDB() (*mongo.Client, error) {
 	ctx := context.Background()
 	mongoURI := os.Getenv("MONGODB_URI")
@@ -5,7 +5,10 @@ import (
 	"log"
 	"os"
 	"time"
+	// プロフィールのタイムゾーンを検証するため、コンテナにタイムゾーンのデータベースがなくても IANA のタイムゾーンを扱えるようにする
+	_ "time/tzdata"
 
+	"github.com/my-backend-project/internal/pkg/pagination"
 	"github.com/my-backend-project/internal/pkg/validator"
 	"github.com/my-backend-project/internal/user/auth"
 	"github.com/my-backend-project/internal/user/handler"
@@ -43,12 +46,29 @@ func main() {
 	// データベースとコレクションの初期化
 	db := client.Database(os.Getenv("MONGO_DB_NAME"))
 
+	// インデックスの作成
+	if err := repository.EnsureIndexes(ctx, db); err != nil {
+		log.Fatalf("Failed to create indexes: %v", err)
+	}
+
 	// 依存関係の初期化
 	userRepo := repository.NewUserRepository(db)
 	jwtService := auth.NewJWTService(os.Getenv("JWT_SECRET_KEY"))
 	userService := service.NewUserService(userRepo, jwtService)
 	userHandler := handler.NewUserHandler(userService, jwtService)
 
+	// ページトークンの署名鍵（未設定の場合はJWTの鍵を流用）
+	pageTokenSecret := os.Getenv("PAGE_TOKEN_SECRET")
+	if pageTokenSecret == "" {
+		pageTokenSecret = os.Getenv("JWT_SECRET_KEY")
+	}
+	notificationService := service.NewNotificationService(
+		repository.NewNotificationRepository(db),
+		repository.NewNotificationPreferenceRepository(db),
+		pagination.NewCodec([]byte(pageTokenSecret)),
+	)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
 	// Echoインスタンスの作成
 	e := echo.New()
 
@@ -71,7 +91,23 @@ func main() {
 	api := e.Group("/api")
 	api.Use(userHandler.AuthMiddleware)
 	{
-		// 認証が必要なエンドポイントをここに追加
+		api.GET("/profile", userHandler.GetProfile)
+		api.PUT("/profile", userHandler.UpdateProfile)
+		api.GET("/notifications", notificationHandler.List)
+		api.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+		api.POST("/notifications/:id/read", notificationHandler.MarkRead)
+		api.DELETE("/notifications/:id", notificationHandler.Delete)
+		api.GET("/notification-preferences", notificationHandler.GetPreferences)
+		api.PUT("/notification-preferences", notificationHandler.UpdatePreferences)
+	}
+
+	// 他のサービスから呼び出す内部API（INTERNAL_API_TOKEN が未設定の場合は無効）
+	if internalToken := os.Getenv("INTERNAL_API_TOKEN"); internalToken != "" {
+		internal := e.Group("/internal")
+		internal.Use(handler.InternalAuthMiddleware(internalToken))
+		internal.POST("/notifications", notificationHandler.Create)
+	} else {
+		log.Printf("Warning: INTERNAL_API_TOKEN is not set, internal API is disabled")
 	}
 
 	// サーバーの起動
@@ -0,0 +1,211 @@
+package apperrors
+
+import (
+	"errors"
+	"maps"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain は google.rpc.ErrorInfo の domain です。Reason はこのドメインの中で一意です
+const Domain = "my-backend-project"
+
+// defaultLocale は Locale を指定していないメッセージの言語です
+const defaultLocale = "ja"
+
+// WithReason は Reason を設定した複製を返します。パッケージ変数のエラーから作っても元のエラーは変わりません
+func (e *AppError) WithReason(reason string) *AppError {
+	c := e.clone()
+	c.Reason = reason
+	return c
+}
+
+// WithMetadata は Metadata に key と value を加えた複製を返します
+func (e *AppError) WithMetadata(key, value string) *AppError {
+	c := e.clone()
+	c.Metadata[key] = value
+	return c
+}
+
+// WithViolations は項目ごとの違反を加えた複製を返します
+func (e *AppError) WithViolations(violations ...*ValidationError) *AppError {
+	c := e.clone()
+	c.Violations = append(c.Violations, violations...)
+	return c
+}
+
+// WithRetryAfter はやり直すまで待つ時間を設定した複製を返します
+func (e *AppError) WithRetryAfter(d time.Duration) *AppError {
+	c := e.clone()
+	c.RetryAfter = d
+	return c
+}
+
+// WithLocale は Message の言語を設定した複製を返します
+func (e *AppError) WithLocale(locale string) *AppError {
+	c := e.clone()
+	c.Locale = locale
+	return c
+}
+
+func (e *AppError) clone() *AppError {
+	c := *e
+	c.Metadata = maps.Clone(e.Metadata)
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]string)
+	}
+	c.Violations = append([]*ValidationError(nil), e.Violations...)
+	return &c
+}
+
+// ReasonCode はエラーの理由を返します。Reason を設定していない場合は Type を大文字にしたもの（"NOT_FOUND" など）です
+func (e *AppError) ReasonCode() string {
+	if e.Reason != "" {
+		return e.Reason
+	}
+	if e.Type == "" {
+		return strings.ToUpper(string(Internal))
+	}
+	return strings.ToUpper(string(e.Type))
+}
+
+// GRPCCode は gRPC のステータスコードを返します
+func (e *AppError) GRPCCode() codes.Code {
+	if e.Code != codes.OK {
+		return e.Code
+	}
+	switch e.Type {
+	case NotFound:
+		return codes.NotFound
+	case InvalidInput:
+		return codes.InvalidArgument
+	case AlreadyExists:
+		return codes.AlreadyExists
+	case FailedPrecondition:
+		return codes.FailedPrecondition
+	case Unauthorized:
+		return codes.Unauthenticated
+	case PermissionDenied:
+		return codes.PermissionDenied
+	case ResourceExhausted:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// GRPCStatus は google.rpc.Status の詳細を付けた gRPC のステータスを返します。メッセージは Message だけで、
+// 内部の原因 Err は含めません。詳細は ErrorInfo と LocalizedMessage を常に付け、
+// 項目ごとの違反がある場合は BadRequest、やり直せる場合は RetryInfo を付けます
+func (e *AppError) GRPCStatus() *status.Status {
+	st := status.New(e.GRPCCode(), e.Message)
+
+	locale := e.Locale
+	if locale == "" {
+		locale = defaultLocale
+	}
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   e.ReasonCode(),
+			Domain:   Domain,
+			Metadata: e.Metadata,
+		},
+		&errdetails.LocalizedMessage{
+			Locale:  locale,
+			Message: e.Message,
+		},
+	}
+	if len(e.Violations) > 0 {
+		badRequest := &errdetails.BadRequest{
+			FieldViolations: make([]*errdetails.BadRequest_FieldViolation, len(e.Violations)),
+		}
+		for i, violation := range e.Violations {
+			badRequest.FieldViolations[i] = &errdetails.BadRequest_FieldViolation{
+				Field:       violation.Field,
+				Description: violation.Message,
+			}
+		}
+		details = append(details, badRequest)
+	}
+	if e.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)})
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// FromStatus は GRPCStatus で返したステータスのエラーから AppError を組み立て直します。クライアントで使います。
+// ステータスでないエラーは内部エラーとし、nil の場合は nil を返します
+func FromStatus(err error) *AppError {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return NewInternalError(err.Error(), err)
+	}
+
+	appErr := &AppError{
+		Type:    typeOf(st.Code()),
+		Message: st.Message(),
+		Code:    st.Code(),
+	}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			appErr.Reason = d.Reason
+			appErr.Metadata = d.Metadata
+		case *errdetails.LocalizedMessage:
+			appErr.Message = d.Message
+			appErr.Locale = d.Locale
+		case *errdetails.BadRequest:
+			for _, violation := range d.FieldViolations {
+				appErr.Violations = append(appErr.Violations, &ValidationError{
+					Field:   violation.Field,
+					Message: violation.Description,
+				})
+			}
+		case *errdetails.RetryInfo:
+			appErr.RetryAfter = d.RetryDelay.AsDuration()
+		}
+	}
+	return appErr
+}
+
+// typeOf は gRPC のステータスコードに対応するエラーの種類を返します
+func typeOf(code codes.Code) ErrorType {
+	switch code {
+	case codes.NotFound:
+		return NotFound
+	case codes.InvalidArgument, codes.OutOfRange:
+		return InvalidInput
+	case codes.AlreadyExists:
+		return AlreadyExists
+	case codes.FailedPrecondition, codes.Aborted:
+		return FailedPrecondition
+	case codes.Unauthenticated:
+		return Unauthorized
+	case codes.PermissionDenied:
+		return PermissionDenied
+	case codes.ResourceExhausted:
+		return ResourceExhausted
+	default:
+		return Internal
+	}
+}
+
+// IsRetryable はやり直すと成功する可能性のあるエラーかを返します
+func IsRetryable(err error) bool {
+	var appErr *AppError
+	return errors.As(err, &appErr) && appErr.RetryAfter > 0
+}
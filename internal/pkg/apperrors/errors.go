@@ -3,25 +3,45 @@ package apperrors
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 type ErrorType string
 
 const (
-	NotFound     ErrorType = "not_found"
-	InvalidInput ErrorType = "invalid_input"
-	Internal     ErrorType = "internal"
-	Unauthorized ErrorType = "unauthorized"
+	NotFound      ErrorType = "not_found"
+	InvalidInput  ErrorType = "invalid_input"
+	AlreadyExists ErrorType = "already_exists"
+	// FailedPrecondition は入力は正しいが現在の状態では操作を実行できないことを表します
+	FailedPrecondition ErrorType = "failed_precondition"
+	Internal           ErrorType = "internal"
+	Unauthorized       ErrorType = "unauthorized"
+	// PermissionDenied は認証済みだがリソースを操作する権限がないことを表します
+	PermissionDenied ErrorType = "permission_denied"
+	// ResourceExhausted はユーザーに割り当てられた容量などの上限を超えることを表します
+	ResourceExhausted ErrorType = "resource_exhausted"
 )
 
+// AppError はアプリケーションのエラーです。Message はクライアントに返すメッセージで、
+// Err は内部の原因です。gRPC のステータスにするときは Err をクライアントに返しません
 type AppError struct {
 	Type    ErrorType
 	Message string
 	Err     error
-	Code    codes.Code
+	// Code は gRPC のステータスコードです。OK の場合は Type から決めます
+	Code codes.Code
+	// Reason はエラーの理由を表す機械可読な識別子（UPPER_SNAKE_CASE）です。空の場合は Type から決めます
+	Reason string
+	// Metadata は理由に関する補足情報です
+	Metadata map[string]string
+	// Violations は不正な入力の項目ごとの違反です
+	Violations []*ValidationError
+	// RetryAfter はやり直すまで待つ時間です。0 の場合はやり直しても成功しません
+	RetryAfter time.Duration
+	// Locale は Message の言語（BCP 47）です。空の場合は日本語です
+	Locale string
 }
 
 func (e *AppError) Error() string {
@@ -35,21 +55,6 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
-func (e *AppError) GRPCStatus() *status.Status {
-	var code codes.Code
-	switch e.Type {
-	case NotFound:
-		code = codes.NotFound
-	case InvalidInput:
-		code = codes.InvalidArgument
-	case Unauthorized:
-		code = codes.Unauthenticated
-	default:
-		code = codes.Internal
-	}
-	return status.New(code, e.Error())
-}
-
 func NewNotFoundError(message string, err error) *AppError {
 	return &AppError{
 		Type:    NotFound,
@@ -66,6 +71,22 @@ func NewInvalidInputError(message string, err error) *AppError {
 	}
 }
 
+func NewAlreadyExistsError(message string, err error) *AppError {
+	return &AppError{
+		Type:    AlreadyExists,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func NewFailedPreconditionError(message string, err error) *AppError {
+	return &AppError{
+		Type:    FailedPrecondition,
+		Message: message,
+		Err:     err,
+	}
+}
+
 func NewInternalError(message string, err error) *AppError {
 	return &AppError{
 		Type:    Internal,
@@ -82,6 +103,22 @@ func NewUnauthorizedError(message string, err error) *AppError {
 	}
 }
 
+func NewPermissionDeniedError(message string, err error) *AppError {
+	return &AppError{
+		Type:    PermissionDenied,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func NewResourceExhaustedError(message string, err error) *AppError {
+	return &AppError{
+		Type:    ResourceExhausted,
+		Message: message,
+		Err:     err,
+	}
+}
+
 func As(err error, target interface{}) bool {
 	return errors.As(err, target)
 }
@@ -108,6 +145,28 @@ func IsInvalidInput(err error) bool {
 	return false
 }
 
+func IsAlreadyExists(err error) bool {
+	var appErr *AppError
+	if err == nil {
+		return false
+	}
+	if As(err, &appErr) {
+		return appErr.Type == AlreadyExists
+	}
+	return false
+}
+
+func IsFailedPrecondition(err error) bool {
+	var appErr *AppError
+	if err == nil {
+		return false
+	}
+	if As(err, &appErr) {
+		return appErr.Type == FailedPrecondition
+	}
+	return false
+}
+
 func IsInternal(err error) bool {
 	var appErr *AppError
 	if err == nil {
@@ -118,3 +177,25 @@ func IsInternal(err error) bool {
 	}
 	return false
 }
+
+func IsPermissionDenied(err error) bool {
+	var appErr *AppError
+	if err == nil {
+		return false
+	}
+	if As(err, &appErr) {
+		return appErr.Type == PermissionDenied
+	}
+	return false
+}
+
+func IsResourceExhausted(err error) bool {
+	var appErr *AppError
+	if err == nil {
+		return false
+	}
+	if As(err, &appErr) {
+		return appErr.Type == ResourceExhausted
+	}
+	return false
+}
@@ -3,8 +3,11 @@ package apperrors
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -57,11 +60,31 @@ func TestAppError_GRPCStatus(t *testing.T) {
 			err:      NewNotFoundError("not found", nil),
 			wantCode: codes.NotFound,
 		},
+		{
+			name:     "already exists error",
+			err:      NewAlreadyExistsError("already exists", nil),
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "failed precondition error",
+			err:      NewFailedPreconditionError("failed precondition", nil),
+			wantCode: codes.FailedPrecondition,
+		},
 		{
 			name:     "unauthorized error",
 			err:      NewUnauthorizedError("unauthorized", nil),
 			wantCode: codes.Unauthenticated,
 		},
+		{
+			name:     "permission denied error",
+			err:      NewPermissionDeniedError("permission denied", nil),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "resource exhausted error",
+			err:      NewResourceExhaustedError("quota exceeded", nil),
+			wantCode: codes.ResourceExhausted,
+		},
 		{
 			name:     "internal error",
 			err:      NewInternalError("internal error", nil),
@@ -76,3 +99,87 @@ func TestAppError_GRPCStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestAppError_GRPCStatusDetails(t *testing.T) {
+	t.Run("hides_cause", func(t *testing.T) {
+		err := NewInternalError("タスクの取得に失敗しました", errors.New("connection(localhost:27017) incomplete read"))
+		st := status.Convert(err)
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "タスクの取得に失敗しました", st.Message())
+
+		require.Len(t, st.Details(), 2)
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, "INTERNAL", info.Reason)
+		assert.Equal(t, Domain, info.Domain)
+		localized := st.Details()[1].(*errdetails.LocalizedMessage)
+		assert.Equal(t, "ja", localized.Locale)
+		assert.Equal(t, "タスクの取得に失敗しました", localized.Message)
+	})
+
+	t.Run("all_details", func(t *testing.T) {
+		err := NewInvalidInputError("入力が不正です", nil).
+			WithReason("INVALID_REQUEST").
+			WithMetadata("task_id", "abc").
+			WithViolations(&ValidationError{Field: "title", Message: "titleは必須です"}).
+			WithRetryAfter(2 * time.Second)
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+
+		require.Len(t, st.Details(), 4)
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, "INVALID_REQUEST", info.Reason)
+		assert.Equal(t, map[string]string{"task_id": "abc"}, info.Metadata)
+		badRequest := st.Details()[2].(*errdetails.BadRequest)
+		assert.Equal(t, "title", badRequest.FieldViolations[0].Field)
+		assert.Equal(t, "titleは必須です", badRequest.FieldViolations[0].Description)
+		retry := st.Details()[3].(*errdetails.RetryInfo)
+		assert.Equal(t, 2*time.Second, retry.RetryDelay.AsDuration())
+	})
+
+	t.Run("with_does_not_modify_original", func(t *testing.T) {
+		base := NewNotFoundError("見つかりません", nil).WithReason("TASK_NOT_FOUND")
+		_ = base.WithMetadata("task_id", "abc").WithReason("OTHER")
+		assert.Equal(t, "TASK_NOT_FOUND", base.ReasonCode())
+		assert.Empty(t, base.Metadata)
+	})
+}
+
+func TestFromStatus(t *testing.T) {
+	t.Run("round_trip", func(t *testing.T) {
+		original := NewAlreadyExistsError("同じ並び順のタスクが既にあります", errors.New("E11000 duplicate key")).
+			WithReason("RANK_CONFLICT").
+			WithMetadata("column", "todo").
+			WithViolations(&ValidationError{Field: "board.before_id", Message: "conflict"}).
+			WithRetryAfter(100 * time.Millisecond).
+			WithLocale("en")
+		// クライアントが受け取るのはステータスのエラーだけ
+		received := status.Convert(original).Err()
+
+		got := FromStatus(received)
+		assert.Equal(t, &AppError{
+			Type:       AlreadyExists,
+			Message:    "同じ並び順のタスクが既にあります",
+			Code:       codes.AlreadyExists,
+			Reason:     "RANK_CONFLICT",
+			Metadata:   map[string]string{"column": "todo"},
+			Violations: []*ValidationError{{Field: "board.before_id", Message: "conflict"}},
+			RetryAfter: 100 * time.Millisecond,
+			Locale:     "en",
+		}, got)
+		assert.True(t, IsAlreadyExists(got))
+		assert.True(t, IsRetryable(got))
+		assert.Equal(t, codes.AlreadyExists, status.Code(got))
+	})
+
+	t.Run("status_without_details", func(t *testing.T) {
+		got := FromStatus(status.Error(codes.Unauthenticated, "invalid token"))
+		assert.Equal(t, Unauthorized, got.Type)
+		assert.Equal(t, "invalid token", got.Message)
+		assert.Equal(t, "UNAUTHORIZED", got.ReasonCode())
+		assert.False(t, IsRetryable(got))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, FromStatus(nil))
+	})
+}
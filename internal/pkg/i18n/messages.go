@@ -1,13 +1,20 @@
 package i18n
 
 import (
+	"embed"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"sync"
 )
 
+// defaultMessages はバイナリに埋め込んだ全言語のメッセージファイルです
+//
+//go:embed messages_*.json
+var defaultMessages embed.FS
+
 // Language は対応言語を定義します
 type Language string
 
@@ -36,6 +43,20 @@ const (
 	ErrorInternal MessageKey = "error.internal"
 )
 
+// ParseAcceptLanguage は Accept-Language の値から対応言語を選びます。
+// 品質値は考慮せず先頭から順に探し、対応言語がない場合は日本語を返します
+func ParseAcceptLanguage(header string) Language {
+	for _, tag := range strings.Split(header, ",") {
+		tag, _, _ = strings.Cut(strings.TrimSpace(tag), ";")
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		switch Language(base) {
+		case LanguageJa, LanguageEn:
+			return Language(base)
+		}
+	}
+	return LanguageJa
+}
+
 var (
 	instance *Translator
 	once     sync.Once
@@ -64,6 +85,10 @@ func (t *Translator) LoadMessages(lang Language, filePath string) error {
 		return fmt.Errorf("failed to read message file: %w", err)
 	}
 
+	return t.loadMessages(lang, data)
+}
+
+func (t *Translator) loadMessages(lang Language, data []byte) error {
 	var messages map[MessageKey]string
 	if err := json.Unmarshal(data, &messages); err != nil {
 		return fmt.Errorf("failed to unmarshal messages: %w", err)
@@ -76,6 +101,20 @@ func (t *Translator) LoadMessages(lang Language, filePath string) error {
 	return nil
 }
 
+// LoadDefaultMessages はバイナリに埋め込んだ全言語のメッセージを読み込みます
+func (t *Translator) LoadDefaultMessages() error {
+	for _, lang := range []Language{LanguageJa, LanguageEn} {
+		data, err := defaultMessages.ReadFile(fmt.Sprintf("messages_%s.json", lang))
+		if err != nil {
+			return fmt.Errorf("failed to read messages for %s: %w", lang, err)
+		}
+		if err := t.loadMessages(lang, data); err != nil {
+			return fmt.Errorf("failed to load messages for %s: %w", lang, err)
+		}
+	}
+	return nil
+}
+
 // LoadAllMessages は指定されたディレクトリから全言語のメッセージを読み込みます
 func (t *Translator) LoadAllMessages(dir string) error {
 	languages := []Language{LanguageJa, LanguageEn}
@@ -0,0 +1,76 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// DefaultPageSize はページサイズが指定されなかった場合の件数です
+	DefaultPageSize int32 = 20
+	// MaxPageSize は1ページで返す最大件数です
+	MaxPageSize int32 = 100
+)
+
+// ErrInvalidToken はページトークンが改ざんされているか解読できない場合のエラーです
+var ErrInvalidToken = errors.New("invalid page token")
+
+// Codec はカーソルを署名付きの不透明なページトークンに変換します
+type Codec struct {
+	secret []byte
+}
+
+// NewCodec は新しいCodecを作成します
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret: secret}
+}
+
+// Encode はカーソルをBSONにシリアライズし、HMAC-SHA256で署名したトークンを返します
+func (c *Codec) Encode(cursor interface{}) (string, error) {
+	payload, err := bson.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	token := append(payload, c.sign(payload)...)
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// Decode はトークンの署名を検証し、カーソルに復元します
+func (c *Codec) Decode(token string, cursor interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) <= sha256.Size {
+		return ErrInvalidToken
+	}
+
+	payload, mac := raw[:len(raw)-sha256.Size], raw[len(raw)-sha256.Size:]
+	if !hmac.Equal(mac, c.sign(payload)) {
+		return ErrInvalidToken
+	}
+
+	if err := bson.Unmarshal(payload, cursor); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// PageSize は要求されたページサイズを既定値と上限の範囲に収めます
+func PageSize(requested int32) int32 {
+	switch {
+	case requested <= 0:
+		return DefaultPageSize
+	case requested > MaxPageSize:
+		return MaxPageSize
+	default:
+		return requested
+	}
+}
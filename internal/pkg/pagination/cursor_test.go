@@ -0,0 +1,71 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type testCursor struct {
+	Value interface{}        `bson:"v"`
+	ID    primitive.ObjectID `bson:"i"`
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	id := primitive.NewObjectID()
+
+	token, err := codec.Encode(&testCursor{Value: now, ID: id})
+	assert.NoError(t, err)
+	assert.NotContains(t, token, id.Hex())
+
+	var got testCursor
+	assert.NoError(t, codec.Decode(token, &got))
+	assert.Equal(t, primitive.NewDateTimeFromTime(now), got.Value)
+	assert.Equal(t, id, got.ID)
+}
+
+func TestCodec_Decode_Invalid(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	token, err := codec.Encode(&testCursor{Value: "title", ID: primitive.NewObjectID()})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+		codec *Codec
+	}{
+		{name: "not base64", token: "!!!", codec: codec},
+		{name: "too short", token: "AAAA", codec: codec},
+		{name: "tampered", token: tamper(token), codec: codec},
+		{name: "other secret", token: token, codec: NewCodec([]byte("other"))},
+		{name: "object id hex", token: primitive.NewObjectID().Hex(), codec: codec},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got testCursor
+			assert.ErrorIs(t, tt.codec.Decode(tt.token, &got), ErrInvalidToken)
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	assert.Equal(t, DefaultPageSize, PageSize(0))
+	assert.Equal(t, DefaultPageSize, PageSize(-5))
+	assert.Equal(t, int32(10), PageSize(10))
+	assert.Equal(t, MaxPageSize, PageSize(MaxPageSize+1))
+}
+
+func tamper(token string) string {
+	b := []byte(token)
+	if b[len(b)/2] == 'A' {
+		b[len(b)/2] = 'B'
+	} else {
+		b[len(b)/2] = 'A'
+	}
+	return string(b)
+}
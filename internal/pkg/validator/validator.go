@@ -2,6 +2,8 @@ package validator
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/my-backend-project/internal/pkg/apperrors"
@@ -14,14 +16,25 @@ type Validator struct {
 	translator *i18n.Translator
 }
 
-// New は新しいValidatorを作成します
+// New は新しいValidatorを作成します。json タグのある項目はエラーの項目名にタグの名前を使います
 func New(translator *i18n.Translator) *Validator {
+	v := validator.New()
+	v.RegisterTagNameFunc(jsonFieldName)
 	return &Validator{
-		validator:  validator.New(),
+		validator:  v,
 		translator: translator,
 	}
 }
 
+// jsonFieldName は json タグの名前を返します。タグがない場合は空文字で、構造体の項目名を使います
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
 // Validate は構造体のバリデーションを行います
 func (v *Validator) Validate(s interface{}, lang i18n.Language) error {
 	if err := v.validator.Struct(s); err != nil {
@@ -56,6 +69,113 @@ func (v *Validator) ValidateAll(s interface{}, lang i18n.Language) error {
 	return v.validateCrossField(s)
 }
 
+// RegisterRules は validate タグを書けない型（生成したコードの構造体など）に、項目名からルールへの対応でルールを登録します
+func (v *Validator) RegisterRules(rules map[string]string, types ...interface{}) {
+	v.validator.RegisterStructValidationMapRules(rules, types...)
+}
+
+// RegisterCheck は独自のルールを登録します。check は項目の値を受け取り、正しい場合に true を返します
+func (v *Validator) RegisterCheck(tag string, check func(field reflect.Value) bool) error {
+	return v.validator.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		return check(fl.Field())
+	})
+}
+
+// Violations は構造体のすべてのバリデーションエラーを、lang で翻訳したメッセージとともに返します。
+// 項目名は入れ子の項目を "." でつなぎ、配列の要素は "labels[0]" のように書きます
+func (v *Validator) Violations(s interface{}, lang i18n.Language) ([]*apperrors.ValidationError, error) {
+	err := v.validator.Struct(s)
+	if err == nil {
+		return nil, nil
+	}
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil, err
+	}
+
+	violations := make([]*apperrors.ValidationError, len(validationErrors))
+	for i, fieldErr := range validationErrors {
+		field := fieldErr.Namespace()
+		// 先頭は構造体の型名のため除く
+		if _, rest, ok := strings.Cut(field, "."); ok {
+			field = rest
+		}
+		violations[i] = &apperrors.ValidationError{
+			Field:   field,
+			Message: v.violationMessage(lang, reflect.TypeOf(s), field, fieldErr),
+		}
+	}
+	return violations, nil
+}
+
+// violationMessage は "validation.field." から始まるキーのメッセージを、項目名とルールの引数で組み立てます。
+// 文字列と配列の min・max は文字数と件数のメッセージを使い、メッセージのないルールは汎用のメッセージにします
+func (v *Validator) violationMessage(lang i18n.Language, root reflect.Type, field string, fieldErr validator.FieldError) string {
+	tag := fieldErr.Tag()
+	if tag == "min" || tag == "max" {
+		switch fieldErr.Kind() {
+		case reflect.String:
+			tag += "_length"
+		case reflect.Slice, reflect.Map:
+			tag += "_items"
+		}
+	}
+	param := fieldErr.Param()
+	if tag == "required_without" {
+		param = siblingFieldName(root, fieldErr, param)
+	}
+
+	key := i18n.MessageKey("validation.field." + tag)
+	if message := v.translator.Translate(lang, key, field, param); message != string(key) {
+		return message
+	}
+	return v.translator.Translate(lang, "validation.field.invalid", field, param)
+}
+
+// siblingFieldName は fieldErr の項目と同じ構造体にある項目 goName の、エラーで使う項目名を返します
+func siblingFieldName(root reflect.Type, fieldErr validator.FieldError, goName string) string {
+	// 構造体の名前空間（"CreateTaskRequest.Tasks[0].DueDate" など）をたどって親の型を求める
+	parent := root
+	segments := strings.Split(fieldErr.StructNamespace(), ".")
+	for _, segment := range segments[1 : len(segments)-1] {
+		parent = elemType(parent)
+		name, _, _ := strings.Cut(segment, "[")
+		field, ok := parent.FieldByName(name)
+		if !ok {
+			return goName
+		}
+		parent = field.Type
+		if strings.Contains(segment, "[") {
+			parent = elemType(parent).Elem()
+		}
+	}
+	field, ok := elemType(parent).FieldByName(goName)
+	if !ok {
+		return goName
+	}
+	name := jsonFieldName(field)
+	if name == "" {
+		name = goName
+	}
+
+	namespace := fieldErr.Namespace()
+	if i := strings.LastIndex(namespace, "."); i >= 0 {
+		namespace = namespace[:i]
+	}
+	if _, prefix, ok := strings.Cut(namespace, "."); ok {
+		return prefix + "." + name
+	}
+	return name
+}
+
+// elemType はポインターをたどった型を返します
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
 // CustomValidator はEcho用のバリデーターラッパーです
 type CustomValidator struct {
 	validator *validator.Validate
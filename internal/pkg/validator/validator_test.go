@@ -160,3 +160,77 @@ func TestValidationError_Error(t *testing.T) {
 	}
 	assert.Equal(t, "error message", err.Error())
 }
+
+// untaggedRequest は validate タグのない、生成したコードのような構造体です
+type untaggedRequest struct {
+	Title   string          `json:"title,omitempty"`
+	DueDate *string         `json:"due_date,omitempty"`
+	DueDay  *string         `json:"due_day,omitempty"`
+	Labels  []string        `json:"labels,omitempty"`
+	Items   []*untaggedItem `json:"items,omitempty"`
+	Main    *untaggedItem   `json:"main,omitempty"`
+}
+
+type untaggedItem struct {
+	Text  string `json:"text,omitempty"`
+	Start *int   `json:"start,omitempty"`
+	End   *int   `json:"end,omitempty"`
+}
+
+func setupRulesTest(t *testing.T) *Validator {
+	translator := i18n.GetTranslator()
+	assert.NoError(t, translator.LoadDefaultMessages())
+	v := New(translator)
+	v.RegisterRules(map[string]string{
+		"Title":   "required,max=5",
+		"DueDate": "required_without=DueDay",
+		"Labels":  "max=2,dive,required",
+		"Items":   "dive",
+	}, untaggedRequest{})
+	v.RegisterRules(map[string]string{
+		"Text": "required",
+		"End":  "required_without=Start",
+	}, untaggedItem{})
+	return v
+}
+
+func TestValidator_Violations(t *testing.T) {
+	v := setupRulesTest(t)
+	day := "2024-03-10"
+
+	t.Run("valid", func(t *testing.T) {
+		violations, err := v.Violations(&untaggedRequest{Title: "買い物", DueDay: &day}, i18n.LanguageJa)
+		assert.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+
+	t.Run("every_field", func(t *testing.T) {
+		violations, err := v.Violations(&untaggedRequest{
+			Labels: []string{"a", ""},
+			Items:  []*untaggedItem{{Text: "ok", Start: new(int)}, {}},
+			Main:   &untaggedItem{Text: "ok"},
+		}, i18n.LanguageJa)
+		assert.NoError(t, err)
+		assert.Equal(t, []*apperrors.ValidationError{
+			{Field: "title", Message: "titleは必須です"},
+			{Field: "due_date", Message: "due_dateかdue_dayのどちらかは必須です"},
+			{Field: "labels[1]", Message: "labels[1]は必須です"},
+			{Field: "items[1].text", Message: "items[1].textは必須です"},
+			{Field: "items[1].end", Message: "items[1].endかitems[1].startのどちらかは必須です"},
+			{Field: "main.end", Message: "main.endかmain.startのどちらかは必須です"},
+		}, violations)
+	})
+
+	t.Run("localized_with_params", func(t *testing.T) {
+		violations, err := v.Violations(&untaggedRequest{
+			Title:  "too long title",
+			DueDay: &day,
+			Labels: []string{"a", "b", "c"},
+		}, i18n.LanguageEn)
+		assert.NoError(t, err)
+		assert.Equal(t, []*apperrors.ValidationError{
+			{Field: "title", Message: "title must be at most 5 characters long"},
+			{Field: "labels", Message: "labels must have at most 2 items"},
+		}, violations)
+	})
+}
@@ -0,0 +1,68 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	t.Run("put_open_delete", func(t *testing.T) {
+		data := bytes.Repeat([]byte("attachment"), 1000)
+		n, err := store.Put(ctx, "key1", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), n)
+
+		r, err := store.Open(ctx, "key1")
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		assert.Equal(t, data, got)
+
+		require.NoError(t, store.Delete(ctx, "key1"))
+		_, err = store.Open(ctx, "key1")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, store.Delete(ctx, "key1"), "存在しないキーの削除は成功とする")
+	})
+
+	t.Run("failed_read_leaves_nothing", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+		_, err := store.Put(ctx, "key2", io.MultiReader(bytes.NewReader([]byte("partial")), &failingReader{err: readErr}))
+		assert.ErrorIs(t, err, readErr)
+
+		_, err = store.Open(ctx, "key2")
+		assert.ErrorIs(t, err, ErrNotFound)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries, "一時ファイルを残さない")
+	})
+
+	t.Run("invalid_key", func(t *testing.T) {
+		for _, key := range []string{"", "../escape", "a/b", ".hidden"} {
+			_, err := store.Put(ctx, key, bytes.NewReader(nil))
+			assert.Error(t, err, key)
+			_, err = store.Open(ctx, key)
+			assert.Error(t, err, key)
+		}
+	})
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
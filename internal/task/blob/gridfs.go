@@ -0,0 +1,88 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// gridFSChunkSize は GridFS に保存するチャンクの大きさです
+const gridFSChunkSize = 255 * 1024
+
+// GridFSStore は MongoDB の GridFS にデータを保存します。キーはファイルの _id として使います
+type GridFSStore struct {
+	bucket *gridfs.Bucket
+}
+
+// NewGridFSStore は db の bucket という名前の GridFS バケットにデータを保存する GridFSStore を作成します
+func NewGridFSStore(db *mongo.Database, bucket string) (*GridFSStore, error) {
+	b, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(bucket).SetChunkSizeBytes(gridFSChunkSize))
+	if err != nil {
+		return nil, err
+	}
+	return &GridFSStore{bucket: b}, nil
+}
+
+func (s *GridFSStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	if err := ValidateKey(key); err != nil {
+		return 0, err
+	}
+
+	// Bucket.UploadFromStream はバケットの読み込みバッファを共有するため、同時に複数のアップロードを扱えるよう
+	// アップロードストリームへ直接書き込む
+	us, err := s.bucket.OpenUploadStreamWithID(key, key)
+	if err != nil {
+		return 0, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := us.SetWriteDeadline(deadline); err != nil {
+			us.Abort()
+			return 0, err
+		}
+	}
+	n, err := io.Copy(us, &contextReader{ctx: ctx, r: r})
+	if err != nil {
+		// 書き込み済みのチャンクは Abort で削除される
+		us.Abort()
+		return 0, err
+	}
+	if err := us.Close(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *GridFSStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+	ds, err := s.bucket.OpenDownloadStream(key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := ds.SetReadDeadline(deadline); err != nil {
+			ds.Close()
+			return nil, err
+		}
+	}
+	return ds, nil
+}
+
+func (s *GridFSStore) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	err := s.bucket.DeleteContext(ctx, key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil
+	}
+	return err
+}
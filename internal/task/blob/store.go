@@ -0,0 +1,38 @@
+// Package blob は添付ファイルの本体を保存するストレージです。
+//
+// メタデータ（ファイル名やサイズ）は呼び出し側が管理し、ここではキーとバイト列の対応だけを扱います。
+// 実装にはローカルのファイルシステムを使う FileStore と MongoDB の GridFS を使う GridFSStore があります。
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// ErrNotFound はキーに対応するデータが存在しないことを表します
+var ErrNotFound = errors.New("blob not found")
+
+// Store はキーを指定してデータを保存・取得・削除します
+type Store interface {
+	// Put は r の内容を key に保存し、保存したバイト数を返します。
+	// r の読み込みに失敗した場合は途中まで書き込んだデータを残しません
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open は key のデータを読み込むリーダーを返します。存在しない場合は ErrNotFound を返します
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete は key のデータを削除します。存在しない場合も成功とします
+	Delete(ctx context.Context, key string) error
+}
+
+// keyPattern はキーとして使える文字列です。ファイルシステム上のパスとして安全な文字に限ります
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]{0,254}$`)
+
+// ValidateKey はキーを検証します
+func ValidateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+type csvEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+// writeHeader はタスクが0件でも取り込めるファイルになるよう、最初の Encode か Close でヘッダーを書き出します
+func (e *csvEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.w.Write(Columns)
+}
+
+func (e *csvEncoder) Encode(task *model.Task) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.w.Write([]string{
+		task.ID.Hex(),
+		task.Title,
+		task.Description,
+		statusName(task.Status),
+		task.WorkflowStatus,
+		priorityName(task.Priority),
+		strings.Join(task.Labels, LabelSeparator),
+		formatTime(task.DueDate),
+		formatTime(task.CreatedAt),
+		formatTime(task.UpdatedAt),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,95 @@
+// Package exporter はタスクを JSON Lines・CSV・iCalendar (VTODO) に書き出します。
+//
+// JSON Lines と CSV の項目名と値の表記は importer と揃えているため、書き出したファイルはそのまま取り込めます。
+// 取り込み先ではタスクIDが変わるため、親タスクは書き出しません。
+// 作業時間のタイムシートも JSON Lines と CSV に書き出します
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// Format は書き出しの形式です
+type Format string
+
+const (
+	FormatJSONL     Format = "jsonl"
+	FormatCSV       Format = "csv"
+	FormatICalendar Format = "ics"
+)
+
+// ContentType は形式に対応する MIME タイプを返します
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatICalendar:
+		return "text/calendar; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// Extension はファイル名に付ける拡張子を "." なしで返します
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// Encoder はタスクを1件ずつ書き出します。すべて書き出したら Close を呼び出してください
+type Encoder interface {
+	Encode(task *model.Task) error
+	// Close は形式に必要な末尾を書き出します。w は閉じません
+	Close() error
+}
+
+// NewEncoder は format の形式で w に書き出す Encoder を返します
+func NewEncoder(w io.Writer, format Format) (Encoder, error) {
+	switch format {
+	case FormatJSONL:
+		return newJSONLEncoder(w), nil
+	case FormatCSV:
+		return newCSVEncoder(w), nil
+	case FormatICalendar:
+		return NewICalendarEncoder(w, ICalendarOptions{}), nil
+	}
+	return nil, fmt.Errorf("未対応の形式です: %s", format)
+}
+
+// Columns は JSON Lines のキーと CSV のヘッダーです。id・created_at・updated_at は取り込みでは読み飛ばします
+var Columns = []string{
+	"id", "title", "description", "status", "workflow_status", "priority", "labels", "due_date", "created_at", "updated_at",
+}
+
+// LabelSeparator は CSV の1つの列に複数のラベルを書くときの区切り文字で、importer の既定値と同じです
+const LabelSeparator = ";"
+
+// statusName は "TASK_STATUS_PENDING" を "pending" のように表記します
+func statusName(status model.TaskStatus) string {
+	return strings.ToLower(strings.TrimPrefix(string(status), "TASK_STATUS_"))
+}
+
+var priorityNames = map[model.TaskPriority]string{
+	model.TaskPriorityLow:    "low",
+	model.TaskPriorityMedium: "medium",
+	model.TaskPriorityHigh:   "high",
+	model.TaskPriorityUrgent: "urgent",
+}
+
+// priorityName は優先度の名前を返します。優先度が未設定の場合は空文字列です
+func priorityName(priority model.TaskPriority) string {
+	return priorityNames[priority]
+}
+
+// formatTime は UTC の RFC 3339 で表記します。ゼロ値は空文字列です
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,202 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/importer"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func exportTasks() []*model.Task {
+	created := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	return []*model.Task{
+		{
+			ID:             primitive.NewObjectID(),
+			Title:          "設計, レビュー",
+			Description:    "1行目\n2行目 \"引用\"",
+			Status:         model.TaskStatusActive,
+			WorkflowStatus: "in_review",
+			Priority:       model.TaskPriorityHigh,
+			Labels:         []string{"backend", "api"},
+			DueDate:        time.Date(2024, 5, 1, 9, 30, 0, 0, time.FixedZone("JST", 9*60*60)),
+			CreatedAt:      created,
+			UpdatedAt:      created.Add(time.Hour),
+		},
+		{
+			ID:        primitive.NewObjectID(),
+			Title:     "<html> & 記号",
+			Status:    model.TaskStatusComplete,
+			DueDate:   time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+			CreatedAt: created,
+			UpdatedAt: created,
+		},
+	}
+}
+
+func encodeAll(t *testing.T, format Format, tasks []*model.Task) string {
+	t.Helper()
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, format)
+	require.NoError(t, err)
+	for _, task := range tasks {
+		require.NoError(t, enc.Encode(task))
+	}
+	require.NoError(t, enc.Close())
+	return buf.String()
+}
+
+func readAll(t *testing.T, r importer.Reader) []*importer.Row {
+	t.Helper()
+	var rows []*importer.Row
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		require.NoError(t, row.Err)
+		rows = append(rows, row)
+	}
+}
+
+// assertRoundTrip は取り込んだ行が書き出したタスクと同じ内容かを検証します
+func assertRoundTrip(t *testing.T, tasks []*model.Task, rows []*importer.Row) {
+	t.Helper()
+	require.Len(t, rows, len(tasks))
+	for i, task := range tasks {
+		got := rows[i].Task
+		assert.Equal(t, task.Title, got.Title)
+		assert.Equal(t, task.Description, got.Description)
+		assert.Equal(t, string(task.Status), got.Status.String())
+		assert.Equal(t, task.WorkflowStatus, got.WorkflowStatus)
+		assert.Equal(t, pb.TaskPriority(task.Priority), got.Priority)
+		assert.Equal(t, len(task.Labels), len(got.Labels))
+		for j := range task.Labels {
+			assert.Equal(t, task.Labels[j], got.Labels[j])
+		}
+		assert.True(t, task.DueDate.Equal(got.DueDate.AsTime()))
+		assert.Empty(t, got.ParentId)
+	}
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+	tasks := exportTasks()
+	out := encodeAll(t, FormatJSONL, tasks)
+
+	assert.Equal(t, 2, strings.Count(out, "\n"))
+	assert.Contains(t, out, `"id":"`+tasks[0].ID.Hex()+`"`)
+	assert.Contains(t, out, `<html> & 記号`)
+
+	assertRoundTrip(t, tasks, readAll(t, importer.NewJSONLReader(strings.NewReader(out), importer.Options{})))
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	tasks := exportTasks()
+	out := encodeAll(t, FormatCSV, tasks)
+
+	assert.True(t, strings.HasPrefix(out, strings.Join(Columns, ",")+"\n"))
+
+	r, err := importer.NewCSVReader(strings.NewReader(out), nil, importer.Options{})
+	require.NoError(t, err)
+	assertRoundTrip(t, tasks, readAll(t, r))
+}
+
+func TestCSVEncoder_Empty(t *testing.T) {
+	out := encodeAll(t, FormatCSV, nil)
+
+	r, err := importer.NewCSVReader(strings.NewReader(out), nil, importer.Options{})
+	require.NoError(t, err)
+	assert.Empty(t, readAll(t, r))
+}
+
+func TestICalendarEncoder(t *testing.T) {
+	tasks := exportTasks()
+	tasks[0].ParentID = primitive.NewObjectID()
+	tasks[0].Description = strings.Repeat("長い説明文です。", 10)
+	tasks[0].Recurrence = &model.Recurrence{
+		RRule:    "FREQ=WEEKLY;BYDAY=MO",
+		TimeZone: "Asia/Tokyo",
+		Start:    time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+	}
+	out := encodeAll(t, FormatICalendar, tasks)
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VTODO\r\n"))
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets, line)
+	}
+
+	// 折り返しを戻してから内容を確認する
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "UID:"+tasks[0].ID.Hex()+"@"+uidDomain+"\r\n")
+	assert.Contains(t, unfolded, "SUMMARY:設計\\, レビュー\r\n")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+tasks[0].Description+"\r\n")
+	assert.Contains(t, unfolded, "STATUS:IN-PROCESS\r\n")
+	assert.Contains(t, unfolded, "PRIORITY:3\r\n")
+	assert.Contains(t, unfolded, "CATEGORIES:backend,api\r\n")
+	assert.Contains(t, unfolded, "DUE:20240501T003000Z\r\n")
+	assert.Contains(t, unfolded, "RELATED-TO;RELTYPE=PARENT:"+tasks[0].ParentID.Hex()+"@"+uidDomain+"\r\n")
+	assert.Contains(t, unfolded, "STATUS:COMPLETED\r\n")
+	assert.Contains(t, unfolded, "DTSTART;TZID=Asia/Tokyo:20240506T090000\r\nRRULE:FREQ=WEEKLY;BYDAY=MO\r\n")
+	assert.NotContains(t, unfolded, "X-WR-CALNAME")
+}
+
+func TestICalendarEncoder_Event(t *testing.T) {
+	tasks := exportTasks()
+	tasks[1].Recurrence = &model.Recurrence{RRule: "FREQ=DAILY", TimeZone: "UTC", Start: tasks[1].DueDate}
+	var buf bytes.Buffer
+	enc := NewICalendarEncoder(&buf, ICalendarOptions{Component: ComponentEvent, Name: "タスク"})
+	for _, task := range tasks {
+		require.NoError(t, enc.Encode(task))
+	}
+	require.NoError(t, enc.Close())
+	out := buf.String()
+
+	assert.Contains(t, out, "X-WR-CALNAME:タスク\r\n")
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT\r\n"))
+	assert.NotContains(t, out, "VTODO")
+	assert.NotContains(t, out, "STATUS:")
+	assert.Contains(t, out, "DTSTART:20240501T003000Z\r\n")
+	assert.Contains(t, out, "SUMMARY:"+completedMark+"<html> & 記号\r\n")
+	assert.Equal(t, 2, strings.Count(out, "TRANSP:TRANSPARENT\r\n"))
+	// 完了したタスクには繰り返しを付けない
+	assert.NotContains(t, out, "RRULE")
+	assert.Contains(t, out, "DTSTART:20240502T000000Z\r\n")
+}
+
+func TestICalendarEncoder_AllDay(t *testing.T) {
+	tasks := exportTasks()
+	tasks[0].AllDay = true
+	tasks[0].DueDay = "2024-05-01"
+	tasks[0].TimeZone = "America/New_York"
+	tasks[0].DueDate = time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC)
+
+	todo := encodeAll(t, FormatICalendar, tasks[:1])
+	assert.Contains(t, todo, "DUE;VALUE=DATE:20240501\r\n")
+	assert.NotContains(t, todo, "DUE:")
+
+	var buf bytes.Buffer
+	enc := NewICalendarEncoder(&buf, ICalendarOptions{Component: ComponentEvent})
+	require.NoError(t, enc.Encode(tasks[0]))
+	require.NoError(t, enc.Close())
+	assert.Contains(t, buf.String(), "DTSTART;VALUE=DATE:20240501\r\n")
+}
+
+func TestEscapeText(t *testing.T) {
+	assert.Equal(t, `a\\b\;c\,d\ne\nf`, escapeText("a\\b;c,d\ne\r\nf"))
+}
+
+func TestNewEncoder_UnsupportedFormat(t *testing.T) {
+	_, err := NewEncoder(io.Discard, Format("xml"))
+	assert.Error(t, err)
+}
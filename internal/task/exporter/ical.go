@@ -0,0 +1,219 @@
+package exporter
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+const (
+	// ProductID は VCALENDAR の PRODID です
+	ProductID = "-//my-backend-project//task-service//JA"
+	// uidDomain は VTODO の UID でタスクIDの後ろに付けるドメインです
+	uidDomain = "task-service.my-backend-project"
+	// maxLineOctets は RFC 5545 で折り返す1行の最大オクテット数です（改行を除く）
+	maxLineOctets = 75
+	icalDateTime  = "20060102T150405Z"
+	icalLocalTime = "20060102T150405"
+)
+
+var icalStatuses = map[model.TaskStatus]string{
+	model.TaskStatusPending:  "NEEDS-ACTION",
+	model.TaskStatusActive:   "IN-PROCESS",
+	model.TaskStatusComplete: "COMPLETED",
+}
+
+// icalPriorities は RFC 5545 の PRIORITY（1 が最高、9 が最低）への対応です
+var icalPriorities = map[model.TaskPriority]int{
+	model.TaskPriorityUrgent: 1,
+	model.TaskPriorityHigh:   3,
+	model.TaskPriorityMedium: 5,
+	model.TaskPriorityLow:    7,
+}
+
+// Component はタスクを書き出す iCalendar のコンポーネントです
+type Component string
+
+const (
+	// ComponentTodo は VTODO です。ステータスと期限をそのまま表せますが、VTODO を表示しないカレンダーアプリもあります
+	ComponentTodo Component = "VTODO"
+	// ComponentEvent は期限の時刻に置いた VEVENT です。完了したタスクは件名の先頭に印を付けます
+	ComponentEvent Component = "VEVENT"
+)
+
+// completedMark は VEVENT で完了したタスクの件名の先頭に付ける印です。VEVENT には完了を表すステータスがありません
+const completedMark = "✓ "
+
+// ICalendarOptions は iCalendar の書き出しの設定です
+type ICalendarOptions struct {
+	// Component が空の場合は VTODO です
+	Component Component
+	// Name はカレンダーアプリに表示するカレンダー名です (X-WR-CALNAME)
+	Name string
+}
+
+type icalEncoder struct {
+	w       *bufio.Writer
+	opts    ICalendarOptions
+	started bool
+	// err は最初の書き込みエラーです。以降の書き込みは bufio.Writer が同じエラーで失敗させます
+	err error
+}
+
+// NewICalendarEncoder はタスクを VTODO または VEVENT として1つの VCALENDAR に書き出す Encoder を返します
+func NewICalendarEncoder(w io.Writer, opts ICalendarOptions) Encoder {
+	if opts.Component == "" {
+		opts.Component = ComponentTodo
+	}
+	return &icalEncoder{w: bufio.NewWriter(w), opts: opts}
+}
+
+func (e *icalEncoder) begin() {
+	if e.started {
+		return
+	}
+	e.started = true
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + ProductID)
+	e.line("CALSCALE:GREGORIAN")
+	if e.opts.Name != "" {
+		e.line("X-WR-CALNAME:" + escapeText(e.opts.Name))
+	}
+}
+
+func (e *icalEncoder) Encode(task *model.Task) error {
+	e.begin()
+
+	event := e.opts.Component == ComponentEvent
+	stamp := task.UpdatedAt
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	e.line("BEGIN:" + string(e.opts.Component))
+	e.line("UID:" + taskUID(task.ID.Hex()))
+	e.line("DTSTAMP:" + stamp.UTC().Format(icalDateTime))
+	if !task.CreatedAt.IsZero() {
+		e.line("CREATED:" + task.CreatedAt.UTC().Format(icalDateTime))
+	}
+	if !task.UpdatedAt.IsZero() {
+		e.line("LAST-MODIFIED:" + task.UpdatedAt.UTC().Format(icalDateTime))
+	}
+	summary := task.Title
+	if event && task.Status == model.TaskStatusComplete {
+		summary = completedMark + summary
+	}
+	e.line("SUMMARY:" + escapeText(summary))
+	if task.Description != "" {
+		e.line("DESCRIPTION:" + escapeText(task.Description))
+	}
+	if status, ok := icalStatuses[task.Status]; ok && !event {
+		e.line("STATUS:" + status)
+	}
+	if priority, ok := icalPriorities[task.Priority]; ok {
+		e.line("PRIORITY:" + strconv.Itoa(priority))
+	}
+	if len(task.Labels) > 0 {
+		categories := make([]string, len(task.Labels))
+		for i, label := range task.Labels {
+			categories[i] = escapeText(label)
+		}
+		e.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+
+	// 完了したタスクの次の回は別のタスクとして作られるため、繰り返しは未完了のタスクにだけ付ける
+	r := task.Recurrence
+	recurring := r != nil && !r.Start.IsZero() && task.Status != model.TaskStatusComplete
+	switch {
+	case event && recurring:
+		e.recurrence(r)
+	case event && !task.DueDate.IsZero():
+		e.due("DTSTART", task)
+	case !event:
+		if !task.DueDate.IsZero() {
+			e.due("DUE", task)
+		}
+		if recurring {
+			e.recurrence(r)
+		}
+	}
+	if event {
+		// タスクの期限で予定が埋まっていると見なされないようにする
+		e.line("TRANSP:TRANSPARENT")
+	}
+
+	if !task.ParentID.IsZero() {
+		e.line("RELATED-TO;RELTYPE=PARENT:" + taskUID(task.ParentID.Hex()))
+	}
+	e.line("END:" + string(e.opts.Component))
+	return e.err
+}
+
+// due は期限を name のプロパティとして書き出します。終日のタスクは時刻のない日付 (VALUE=DATE) にします
+func (e *icalEncoder) due(name string, task *model.Task) {
+	if task.AllDay && task.DueDay != "" {
+		e.line(name + ";VALUE=DATE:" + strings.ReplaceAll(task.DueDay, "-", ""))
+		return
+	}
+	e.line(name + ":" + task.DueDate.UTC().Format(icalDateTime))
+}
+
+// recurrence は繰り返しの起点を DTSTART、ルールを RRULE として書き出します
+func (e *icalEncoder) recurrence(r *model.Recurrence) {
+	// RRULE の BYDAY などは繰り返しのタイムゾーンの暦で解釈するため、起点もそのタイムゾーンで書き出す
+	if r.TimeZone == "" || r.TimeZone == "UTC" {
+		e.line("DTSTART:" + r.Start.UTC().Format(icalDateTime))
+	} else if loc, err := time.LoadLocation(r.TimeZone); err == nil {
+		e.line("DTSTART;TZID=" + r.TimeZone + ":" + r.Start.In(loc).Format(icalLocalTime))
+	} else {
+		e.line("DTSTART:" + r.Start.UTC().Format(icalDateTime))
+	}
+	e.line("RRULE:" + strings.TrimPrefix(r.RRule, "RRULE:"))
+}
+
+func (e *icalEncoder) Close() error {
+	e.begin()
+	e.line("END:VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// line は1行を 75 オクテットで折り返して CRLF で書き出します。折り返した行は空白1文字で始めます
+func (e *icalEncoder) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		// UTF-8 の文字の途中で折り返さない
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		e.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1
+	}
+	e.write(s + "\r\n")
+}
+
+func (e *icalEncoder) write(s string) {
+	if _, err := e.w.WriteString(s); err != nil && e.err == nil {
+		e.err = err
+	}
+}
+
+func taskUID(id string) string {
+	return id + "@" + uidDomain
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeText は TEXT 型の値の \ ; , と改行をエスケープします
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// jsonlRecord は JSON Lines の1行です。キーは Columns と同じです
+type jsonlRecord struct {
+	ID             string   `json:"id"`
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	Status         string   `json:"status"`
+	WorkflowStatus string   `json:"workflow_status,omitempty"`
+	Priority       string   `json:"priority,omitempty"`
+	Labels         []string `json:"labels,omitempty"`
+	DueDate        string   `json:"due_date,omitempty"`
+	CreatedAt      string   `json:"created_at"`
+	UpdatedAt      string   `json:"updated_at"`
+}
+
+type jsonlEncoder struct {
+	encoder *json.Encoder
+}
+
+func newJSONLEncoder(w io.Writer) *jsonlEncoder {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &jsonlEncoder{encoder: encoder}
+}
+
+func (e *jsonlEncoder) Encode(task *model.Task) error {
+	return e.encoder.Encode(jsonlRecord{
+		ID:             task.ID.Hex(),
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         statusName(task.Status),
+		WorkflowStatus: task.WorkflowStatus,
+		Priority:       priorityName(task.Priority),
+		Labels:         task.Labels,
+		DueDate:        formatTime(task.DueDate),
+		CreatedAt:      formatTime(task.CreatedAt),
+		UpdatedAt:      formatTime(task.UpdatedAt),
+	})
+}
+
+func (e *jsonlEncoder) Close() error {
+	return nil
+}
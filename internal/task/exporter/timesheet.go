@@ -0,0 +1,104 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// TimesheetColumns はタイムシートの JSON Lines のキーと CSV のヘッダーです
+var TimesheetColumns = []string{"date", "task_id", "task", "start", "end", "hours", "note"}
+
+// TimesheetEncoder は作業時間を1件ずつタイムシートの行として書き出します。すべて書き出したら Close を呼び出してください
+type TimesheetEncoder interface {
+	Encode(row *model.TimesheetRow) error
+	// Close は書き出しを終えます。w は閉じません
+	Close() error
+}
+
+// NewTimesheetEncoder は format の形式で w に書き出す TimesheetEncoder を返します。
+// 日付は loc のタイムゾーンで表記します。iCalendar には対応しません
+func NewTimesheetEncoder(w io.Writer, format Format, loc *time.Location) (TimesheetEncoder, error) {
+	switch format {
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &jsonlTimesheetEncoder{encoder: encoder, loc: loc}, nil
+	case FormatCSV:
+		return &csvTimesheetEncoder{w: csv.NewWriter(w), loc: loc}, nil
+	}
+	return nil, fmt.Errorf("タイムシートは未対応の形式です: %s", format)
+}
+
+// timesheetRecord はタイムシートの1行です。キーは TimesheetColumns と同じです
+type timesheetRecord struct {
+	Date   string `json:"date"`
+	TaskID string `json:"task_id"`
+	Task   string `json:"task"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Hours  string `json:"hours"`
+	Note   string `json:"note,omitempty"`
+}
+
+func newTimesheetRecord(row *model.TimesheetRow, loc *time.Location) timesheetRecord {
+	return timesheetRecord{
+		Date:   row.Entry.Start.In(loc).Format(model.StatsDateLayout),
+		TaskID: row.Entry.TaskID.Hex(),
+		Task:   row.TaskTitle,
+		Start:  formatTime(row.Entry.Start),
+		End:    formatTime(row.Entry.End),
+		// 表計算ソフトでそのまま合計できるよう、時間を小数で表記する
+		Hours: strconv.FormatFloat(row.Entry.Duration().Hours(), 'f', 2, 64),
+		Note:  row.Entry.Note,
+	}
+}
+
+type jsonlTimesheetEncoder struct {
+	encoder *json.Encoder
+	loc     *time.Location
+}
+
+func (e *jsonlTimesheetEncoder) Encode(row *model.TimesheetRow) error {
+	return e.encoder.Encode(newTimesheetRecord(row, e.loc))
+}
+
+func (e *jsonlTimesheetEncoder) Close() error {
+	return nil
+}
+
+type csvTimesheetEncoder struct {
+	w             *csv.Writer
+	loc           *time.Location
+	headerWritten bool
+}
+
+// writeHeader は作業時間が0件でもヘッダーだけのファイルになるよう、最初の Encode か Close でヘッダーを書き出します
+func (e *csvTimesheetEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.w.Write(TimesheetColumns)
+}
+
+func (e *csvTimesheetEncoder) Encode(row *model.TimesheetRow) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	r := newTimesheetRecord(row, e.loc)
+	return e.w.Write([]string{r.Date, r.TaskID, r.Task, r.Start, r.End, r.Hours, r.Note})
+}
+
+func (e *csvTimesheetEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTimesheetEncoder(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	start := time.Date(2024, 4, 8, 16, 30, 0, 0, time.UTC)
+	row := &model.TimesheetRow{
+		Entry: &model.TimeEntry{
+			TaskID: primitive.NewObjectID(),
+			Start:  start,
+			End:    start.Add(90 * time.Minute),
+			Note:   "設計, レビュー",
+		},
+		TaskTitle: "API",
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc, err := NewTimesheetEncoder(&buf, FormatCSV, tokyo)
+		require.NoError(t, err)
+		require.NoError(t, enc.Encode(row))
+		require.NoError(t, enc.Close())
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, strings.Join(TimesheetColumns, ","), lines[0])
+		assert.Equal(t, "2024-04-09,"+row.Entry.TaskID.Hex()+",API,2024-04-08T16:30:00Z,2024-04-08T18:00:00Z,1.50,\"設計, レビュー\"", lines[1])
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc, err := NewTimesheetEncoder(&buf, FormatJSONL, tokyo)
+		require.NoError(t, err)
+		require.NoError(t, enc.Encode(row))
+		require.NoError(t, enc.Close())
+
+		var record map[string]string
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "2024-04-09", record["date"])
+		assert.Equal(t, "1.50", record["hours"])
+	})
+
+	t.Run("empty_csv_has_header", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc, err := NewTimesheetEncoder(&buf, FormatCSV, time.UTC)
+		require.NoError(t, err)
+		require.NoError(t, enc.Close())
+		assert.Equal(t, strings.Join(TimesheetColumns, ",")+"\n", buf.String())
+	})
+
+	t.Run("icalendar_unsupported", func(t *testing.T) {
+		_, err := NewTimesheetEncoder(&bytes.Buffer{}, FormatICalendar, time.UTC)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AttachmentHandler struct {
+	pb.UnimplementedAttachmentServiceServer
+	attachmentService service.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+func (h *AttachmentHandler) UploadAttachment(stream pb.AttachmentService_UploadAttachmentServer) error {
+	userID, ok := interceptor.UserIDFromContext(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "metadata is required")
+		}
+		return err
+	}
+	metadata := first.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "the first message must contain metadata")
+	}
+
+	attachment, err := h.attachmentService.UploadAttachment(stream.Context(), userID, &model.AttachmentUpload{
+		TaskID:      metadata.TaskId,
+		Name:        metadata.Name,
+		ContentType: metadata.ContentType,
+		Size:        metadata.Size,
+		Checksum:    metadata.Checksum,
+	}, &uploadReader{stream: stream})
+	if err != nil {
+		return convertErrorToGRPCStatus(stream.Context(), err)
+	}
+	return stream.SendAndClose(convertAttachmentToProto(attachment))
+}
+
+func (h *AttachmentHandler) DownloadAttachment(req *pb.DownloadAttachmentRequest, stream pb.AttachmentService_DownloadAttachmentServer) error {
+	userID, ok := interceptor.UserIDFromContext(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	attachment, content, err := h.attachmentService.OpenAttachment(stream.Context(), req.AttachmentId, userID)
+	if err != nil {
+		return convertErrorToGRPCStatus(stream.Context(), err)
+	}
+	defer content.Close()
+
+	if err := stream.Send(&pb.DownloadAttachmentResponse{
+		Data: &pb.DownloadAttachmentResponse_Attachment{Attachment: convertAttachmentToProto(attachment)},
+	}); err != nil {
+		return err
+	}
+	buf := make([]byte, model.AttachmentChunkSize)
+	for {
+		n, err := io.ReadFull(content, buf)
+		if n > 0 {
+			if err := stream.Send(&pb.DownloadAttachmentResponse{
+				Data: &pb.DownloadAttachmentResponse_Chunk{Chunk: buf[:n]},
+			}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Internal, "failed to read attachment")
+		}
+	}
+}
+
+func (h *AttachmentHandler) ListAttachments(ctx context.Context, req *pb.ListAttachmentsRequest) (*pb.ListAttachmentsResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	attachments, err := h.attachmentService.ListAttachments(ctx, req.TaskId, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.ListAttachmentsResponse{Attachments: make([]*pb.Attachment, len(attachments))}
+	for i, attachment := range attachments {
+		resp.Attachments[i] = convertAttachmentToProto(attachment)
+	}
+	return resp, nil
+}
+
+func (h *AttachmentHandler) DeleteAttachment(ctx context.Context, req *pb.DeleteAttachmentRequest) (*pb.Empty, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	if err := h.attachmentService.DeleteAttachment(ctx, req.AttachmentId, userID); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (h *AttachmentHandler) GetAttachmentQuota(ctx context.Context, req *pb.GetAttachmentQuotaRequest) (*pb.AttachmentQuota, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	quota, err := h.attachmentService.GetQuota(ctx, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return &pb.AttachmentQuota{UsedBytes: quota.UsedBytes, LimitBytes: quota.LimitBytes}, nil
+}
+
+// uploadReader はアップロードのストリームで受信したチャンクを順に読み込むリーダーです
+type uploadReader struct {
+	stream pb.AttachmentService_UploadAttachmentServer
+	buf    []byte
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if msg.GetMetadata() != nil {
+			return 0, apperrors.NewInvalidInputError("metadata は最初のメッセージでのみ送信できます", nil)
+		}
+		r.buf = msg.GetChunk()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func convertAttachmentToProto(attachment *model.Attachment) *pb.Attachment {
+	return &pb.Attachment{
+		AttachmentId: attachment.ID.Hex(),
+		TaskId:       attachment.TaskID.Hex(),
+		Name:         attachment.Name,
+		Size:         attachment.Size,
+		ContentType:  attachment.ContentType,
+		Checksum:     attachment.Checksum,
+		CreatedAt:    timestamppb.New(attachment.CreatedAt),
+	}
+}
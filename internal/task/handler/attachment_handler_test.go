@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockAttachmentService struct {
+	mock.Mock
+}
+
+func (m *mockAttachmentService) UploadAttachment(ctx context.Context, userID string, upload *model.AttachmentUpload, content io.Reader) (*model.Attachment, error) {
+	args := m.Called(ctx, userID, upload, content)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Attachment), args.Error(1)
+}
+
+func (m *mockAttachmentService) ListAttachments(ctx context.Context, taskID string, userID string) ([]*model.Attachment, error) {
+	args := m.Called(ctx, taskID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Attachment), args.Error(1)
+}
+
+func (m *mockAttachmentService) OpenAttachment(ctx context.Context, attachmentID string, userID string) (*model.Attachment, io.ReadCloser, error) {
+	args := m.Called(ctx, attachmentID, userID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.Attachment), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+func (m *mockAttachmentService) DeleteAttachment(ctx context.Context, attachmentID string, userID string) error {
+	args := m.Called(ctx, attachmentID, userID)
+	return args.Error(0)
+}
+
+func (m *mockAttachmentService) GetQuota(ctx context.Context, userID string) (*model.AttachmentQuota, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AttachmentQuota), args.Error(1)
+}
+
+func (m *mockAttachmentService) DeleteTaskAttachments(ctx context.Context, taskID primitive.ObjectID) error {
+	args := m.Called(ctx, taskID)
+	return args.Error(0)
+}
+
+// fakeUploadStream は受信するメッセージを順に返すアップロードのストリームです
+type fakeUploadStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []*pb.UploadAttachmentRequest
+	response *pb.Attachment
+}
+
+func (s *fakeUploadStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeUploadStream) Recv() (*pb.UploadAttachmentRequest, error) {
+	if len(s.messages) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	return msg, nil
+}
+
+func (s *fakeUploadStream) SendAndClose(resp *pb.Attachment) error {
+	s.response = resp
+	return nil
+}
+
+// fakeDownloadStream は送信したメッセージを記録するダウンロードのストリームです
+type fakeDownloadStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pb.DownloadAttachmentResponse
+}
+
+func (s *fakeDownloadStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeDownloadStream) Send(resp *pb.DownloadAttachmentResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func chunkMessage(data string) *pb.UploadAttachmentRequest {
+	return &pb.UploadAttachmentRequest{Data: &pb.UploadAttachmentRequest_Chunk{Chunk: []byte(data)}}
+}
+
+func TestAttachmentHandler_UploadAttachment(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	taskID := primitive.NewObjectID()
+	metadata := &pb.UploadAttachmentRequest{Data: &pb.UploadAttachmentRequest_Metadata{Metadata: &pb.AttachmentMetadata{
+		TaskId: taskID.Hex(), Name: "notes.txt", ContentType: "text/plain",
+	}}}
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mockAttachmentService)
+		handler := NewAttachmentHandler(mockService)
+		var received []byte
+		mockService.On("UploadAttachment", ctx, "user1", &model.AttachmentUpload{TaskID: taskID.Hex(), Name: "notes.txt", ContentType: "text/plain"}, mock.Anything).
+			Run(func(args mock.Arguments) {
+				received, _ = io.ReadAll(args.Get(3).(io.Reader))
+			}).
+			Return(&model.Attachment{ID: primitive.NewObjectID(), TaskID: taskID, Name: "notes.txt", Size: 11}, nil).Once()
+
+		stream := &fakeUploadStream{ctx: ctx, messages: []*pb.UploadAttachmentRequest{metadata, chunkMessage("hello "), chunkMessage(""), chunkMessage("world")}}
+		require.NoError(t, handler.UploadAttachment(stream))
+		assert.Equal(t, "hello world", string(received))
+		assert.Equal(t, int64(11), stream.response.Size)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("metadata_must_come_first", func(t *testing.T) {
+		handler := NewAttachmentHandler(new(mockAttachmentService))
+		stream := &fakeUploadStream{ctx: ctx, messages: []*pb.UploadAttachmentRequest{chunkMessage("hello")}}
+		assert.Equal(t, codes.InvalidArgument, status.Code(handler.UploadAttachment(stream)))
+	})
+
+	t.Run("quota_exceeded", func(t *testing.T) {
+		mockService := new(mockAttachmentService)
+		handler := NewAttachmentHandler(mockService)
+		mockService.On("UploadAttachment", ctx, "user1", mock.Anything, mock.Anything).
+			Return(nil, apperrors.NewResourceExhaustedError("添付ファイルの容量の上限を超えています", nil)).Once()
+
+		stream := &fakeUploadStream{ctx: ctx, messages: []*pb.UploadAttachmentRequest{metadata, chunkMessage("hello")}}
+		assert.Equal(t, codes.ResourceExhausted, status.Code(handler.UploadAttachment(stream)))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewAttachmentHandler(new(mockAttachmentService))
+		stream := &fakeUploadStream{ctx: context.Background(), messages: []*pb.UploadAttachmentRequest{metadata}}
+		assert.Equal(t, codes.Unauthenticated, status.Code(handler.UploadAttachment(stream)))
+	})
+}
+
+func TestAttachmentHandler_DownloadAttachment(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	mockService := new(mockAttachmentService)
+	handler := NewAttachmentHandler(mockService)
+	data := bytes.Repeat([]byte("x"), model.AttachmentChunkSize+10)
+	attachment := &model.Attachment{ID: primitive.NewObjectID(), Name: "big.bin", Size: int64(len(data))}
+	mockService.On("OpenAttachment", ctx, attachment.ID.Hex(), "user1").
+		Return(attachment, io.NopCloser(bytes.NewReader(data)), nil).Once()
+
+	stream := &fakeDownloadStream{ctx: ctx}
+	require.NoError(t, handler.DownloadAttachment(&pb.DownloadAttachmentRequest{AttachmentId: attachment.ID.Hex()}, stream))
+
+	require.Len(t, stream.sent, 3)
+	assert.Equal(t, "big.bin", stream.sent[0].GetAttachment().Name)
+	assert.Len(t, stream.sent[1].GetChunk(), model.AttachmentChunkSize)
+	assert.Len(t, stream.sent[2].GetChunk(), 10)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *TaskHandler) BatchCreateTasks(ctx context.Context, req *pb.BatchCreateTasksRequest) (*pb.BatchTasksResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	tasks := make([]*model.Task, len(req.Tasks))
+	for i, taskReq := range req.Tasks {
+		task, err := convertCreateRequestToTask(taskReq, userID)
+		if err != nil {
+			return nil, convertErrorToGRPCStatus(ctx, apperrors.NewInvalidInputError(fmt.Sprintf("%d件目のタスクが不正です", i+1), err))
+		}
+		tasks[i] = task
+	}
+
+	result, err := h.taskService.BatchCreateTasks(ctx, userID, tasks, req.Atomic)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertBatchResultToProto(ctx, result), nil
+}
+
+func (h *TaskHandler) BatchUpdateTasks(ctx context.Context, req *pb.BatchUpdateTasksRequest) (*pb.BatchTasksResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	selector, err := convertTaskSelector(req.TaskIds, req.Filter)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	result, err := h.taskService.BatchUpdateTasks(ctx, userID, selector, convertTaskPatchFromProto(req.Patch), req.Atomic)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertBatchResultToProto(ctx, result), nil
+}
+
+func (h *TaskHandler) BatchDeleteTasks(ctx context.Context, req *pb.BatchDeleteTasksRequest) (*pb.BatchTasksResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	selector, err := convertTaskSelector(req.TaskIds, req.Filter)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	result, err := h.taskService.BatchDeleteTasks(ctx, userID, selector, req.Atomic)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertBatchResultToProto(ctx, result), nil
+}
+
+func convertTaskSelector(taskIDs []string, filter *pb.ListTasksRequest) (*model.TaskSelector, error) {
+	selector := &model.TaskSelector{TaskIDs: taskIDs}
+	if filter != nil {
+		converted, err := convertListRequestToFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		selector.Filter = converted
+	}
+	return selector, nil
+}
+
+func convertTaskPatchFromProto(patch *pb.TaskPatch) *model.TaskPatch {
+	if patch == nil {
+		return nil
+	}
+	result := &model.TaskPatch{
+		AddLabels:    patch.AddLabels,
+		RemoveLabels: patch.RemoveLabels,
+	}
+	if patch.Status != pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		result.Status = model.TaskStatus(patch.Status.String())
+	}
+	if patch.Priority != pb.TaskPriority_TASK_PRIORITY_UNSPECIFIED {
+		priority := model.TaskPriority(patch.Priority)
+		result.Priority = &priority
+	}
+	if patch.DueDate != nil {
+		dueDate := patch.DueDate.AsTime()
+		result.DueDate = &dueDate
+	}
+	if patch.DueDay != nil {
+		result.DueDay = convertDateFromProto(patch.DueDay)
+	}
+	return result
+}
+
+func convertBatchResultToProto(ctx context.Context, result *model.BatchResult) *pb.BatchTasksResponse {
+	resp := &pb.BatchTasksResponse{
+		Results:        make([]*pb.BatchTaskResult, len(result.Items)),
+		SucceededCount: int32(result.SucceededCount()),
+		FailedCount:    int32(result.FailedCount()),
+	}
+	for i, item := range result.Items {
+		r := &pb.BatchTaskResult{
+			Index: int32(item.Index),
+			Code:  int32(codes.OK),
+		}
+		if !item.TaskID.IsZero() {
+			r.TaskId = item.TaskID.Hex()
+		}
+		if item.Task != nil {
+			r.Task = convertTaskToProto(item.Task)
+		}
+		if item.Err != nil {
+			st := status.Convert(convertErrorToGRPCStatus(ctx, item.Err))
+			r.Code = int32(st.Code())
+			r.ErrorMessage = st.Message()
+		}
+		resp.Results[i] = r
+	}
+	return resp
+}
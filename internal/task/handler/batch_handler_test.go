@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTaskHandler_BatchCreateTasks(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	t.Run("per_item_results", func(t *testing.T) {
+		mockService := new(mockTaskService)
+		handler := NewTaskHandler(mockService)
+		created := &model.Task{ID: primitive.NewObjectID(), UserID: "user1", Title: "a"}
+		mockService.On("BatchCreateTasks", ctx, "user1", mock.MatchedBy(func(tasks []*model.Task) bool {
+			return len(tasks) == 2 && tasks[0].Title == "a"
+		}), true).Return(&model.BatchResult{Items: []*model.BatchItemResult{
+			{Index: 0, TaskID: created.ID, Task: created},
+			{Index: 1, Err: apperrors.NewInvalidInputError("タイトルは必須です", nil)},
+		}}, nil)
+
+		resp, err := handler.BatchCreateTasks(ctx, &pb.BatchCreateTasksRequest{
+			Tasks:  []*pb.CreateTaskRequest{{Title: "a"}, {}},
+			Atomic: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), resp.SucceededCount)
+		assert.Equal(t, int32(1), resp.FailedCount)
+		assert.Equal(t, created.ID.Hex(), resp.Results[0].TaskId)
+		assert.Equal(t, int32(codes.OK), resp.Results[0].Code)
+		assert.Equal(t, int32(codes.InvalidArgument), resp.Results[1].Code)
+		assert.Empty(t, resp.Results[1].TaskId)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid_parent_id", func(t *testing.T) {
+		handler := NewTaskHandler(new(mockTaskService))
+
+		_, err := handler.BatchCreateTasks(ctx, &pb.BatchCreateTasksRequest{
+			Tasks: []*pb.CreateTaskRequest{{Title: "a", ParentId: "invalid"}},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewTaskHandler(new(mockTaskService))
+
+		_, err := handler.BatchCreateTasks(context.Background(), &pb.BatchCreateTasksRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestTaskHandler_BatchUpdateTasks(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+
+	mockService.On("BatchUpdateTasks", ctx, "user1",
+		mock.MatchedBy(func(selector *model.TaskSelector) bool {
+			return selector.TaskIDs == nil && selector.Filter.DuePreset == model.DueDateOverdue
+		}),
+		mock.MatchedBy(func(patch *model.TaskPatch) bool {
+			return patch.Status == model.TaskStatusComplete && patch.Priority == nil && patch.DueDate == nil
+		}),
+		false,
+	).Return(&model.BatchResult{}, nil)
+
+	resp, err := handler.BatchUpdateTasks(ctx, &pb.BatchUpdateTasksRequest{
+		Filter: &pb.ListTasksRequest{DueFilter: pb.DueDateFilter_DUE_DATE_FILTER_OVERDUE},
+		Patch:  &pb.TaskPatch{Status: pb.TaskStatus_TASK_STATUS_COMPLETE},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Results)
+	mockService.AssertExpectations(t)
+}
+
+func TestTaskHandler_BatchDeleteTasks(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+
+	mockService.On("BatchDeleteTasks", ctx, "user1", &model.TaskSelector{TaskIDs: []string{"a"}}, false).
+		Return(nil, apperrors.NewInvalidInputError("一括操作の対象が不正です", nil))
+
+	_, err := handler.BatchDeleteTasks(ctx, &pb.BatchDeleteTasksRequest{TaskIds: []string{"a"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
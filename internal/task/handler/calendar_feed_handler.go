@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/exporter"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// CalendarFeedRoute はフィードを配信する HTTP のルートです
+	CalendarFeedRoute = "/calendar/:token"
+	// calendarFeedName はカレンダーアプリに表示するカレンダー名です
+	calendarFeedName = "タスク"
+	// calendarFeedMaxAge はカレンダーアプリなどにフィードをキャッシュさせる秒数です
+	calendarFeedMaxAge = "300"
+)
+
+type CalendarFeedHandler struct {
+	pb.UnimplementedCalendarFeedServiceServer
+	feedService service.CalendarFeedService
+	// baseURL はフィードの URL の先頭（例: https://tasks.example.com）です
+	baseURL string
+}
+
+func NewCalendarFeedHandler(feedService service.CalendarFeedService, baseURL string) *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		feedService: feedService,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (h *CalendarFeedHandler) GetCalendarFeed(ctx context.Context, req *pb.GetCalendarFeedRequest) (*pb.CalendarFeed, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	feed, err := h.feedService.GetCalendarFeed(ctx, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertCalendarFeedToProto(feed, ""), nil
+}
+
+func (h *CalendarFeedHandler) RotateCalendarFeedToken(ctx context.Context, req *pb.RotateCalendarFeedTokenRequest) (*pb.CalendarFeed, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	feed, token, err := h.feedService.RotateCalendarFeedToken(ctx, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertCalendarFeedToProto(feed, h.baseURL+"/calendar/"+token+".ics"), nil
+}
+
+func (h *CalendarFeedHandler) RevokeCalendarFeed(ctx context.Context, req *pb.RevokeCalendarFeedRequest) (*pb.Empty, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	if err := h.feedService.RevokeCalendarFeed(ctx, userID); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return &pb.Empty{}, nil
+}
+
+// ServeFeed は GET /calendar/:token を処理します。トークンの後ろの ".ics" は省略できます。
+// 既定では VEVENT で配信し、?component=vtodo を指定すると VTODO で配信します
+func (h *CalendarFeedHandler) ServeFeed(c echo.Context) error {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	opts := exporter.ICalendarOptions{Component: exporter.ComponentEvent, Name: calendarFeedName}
+	switch strings.ToLower(c.QueryParam("component")) {
+	case "", "vevent":
+	case "vtodo":
+		opts.Component = exporter.ComponentTodo
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid component")
+	}
+
+	// ETag を内容から計算するため、一度バッファに書き出す
+	var buf bytes.Buffer
+	if err := h.feedService.WriteCalendarFeed(c.Request().Context(), token, opts, &buf); err != nil {
+		if apperrors.IsNotFound(err) {
+			return echo.NewHTTPError(http.StatusNotFound, "Calendar feed not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate calendar feed")
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "private, max-age="+calendarFeedMaxAge)
+	if etagMatches(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, exporter.FormatICalendar.ContentType(), buf.Bytes())
+}
+
+// etagMatches は If-None-Match のいずれかのタグが etag と一致するかを弱い比較で判定します
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func convertCalendarFeedToProto(feed *model.CalendarFeed, url string) *pb.CalendarFeed {
+	return &pb.CalendarFeed{
+		Url:       url,
+		CreatedAt: timestamppb.New(feed.CreatedAt),
+		RotatedAt: timestamppb.New(feed.RotatedAt),
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/exporter"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockCalendarFeedService struct {
+	mock.Mock
+}
+
+func (m *mockCalendarFeedService) GetCalendarFeed(ctx context.Context, userID string) (*model.CalendarFeed, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CalendarFeed), args.Error(1)
+}
+
+func (m *mockCalendarFeedService) RotateCalendarFeedToken(ctx context.Context, userID string) (*model.CalendarFeed, string, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*model.CalendarFeed), args.String(1), args.Error(2)
+}
+
+func (m *mockCalendarFeedService) RevokeCalendarFeed(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *mockCalendarFeedService) WriteCalendarFeed(ctx context.Context, token string, opts exporter.ICalendarOptions, w io.Writer) error {
+	args := m.Called(ctx, token, opts, w)
+	return args.Error(0)
+}
+
+func TestCalendarFeedHandler_RotateCalendarFeedToken(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mockCalendarFeedService)
+		handler := NewCalendarFeedHandler(mockService, "https://tasks.example.com/")
+		mockService.On("RotateCalendarFeedToken", ctx, "user1").
+			Return(&model.CalendarFeed{UserID: "user1", CreatedAt: time.Now(), RotatedAt: time.Now()}, "secret", nil)
+
+		feed, err := handler.RotateCalendarFeedToken(ctx, &pb.RotateCalendarFeedTokenRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "https://tasks.example.com/calendar/secret.ics", feed.Url)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewCalendarFeedHandler(new(mockCalendarFeedService), "")
+
+		_, err := handler.RotateCalendarFeedToken(context.Background(), &pb.RotateCalendarFeedTokenRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestCalendarFeedHandler_GetCalendarFeed(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	mockService := new(mockCalendarFeedService)
+	handler := NewCalendarFeedHandler(mockService, "https://tasks.example.com")
+	mockService.On("GetCalendarFeed", ctx, "user1").Return(nil, repository.ErrCalendarFeedNotFound)
+
+	_, err := handler.GetCalendarFeed(ctx, &pb.GetCalendarFeedRequest{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// serveFeed は GET /calendar/{token}{query} のリクエストで ServeFeed を呼び出します
+func serveFeed(handler *CalendarFeedHandler, token string, query string, ifNoneMatch string) (*httptest.ResponseRecorder, error) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/calendar/"+token+query, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath(CalendarFeedRoute)
+	c.SetParamNames("token")
+	c.SetParamValues(token)
+	return rec, handler.ServeFeed(c)
+}
+
+func TestCalendarFeedHandler_ServeFeed(t *testing.T) {
+	const body = "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"
+	writeBody := func(args mock.Arguments) {
+		io.WriteString(args.Get(3).(io.Writer), body)
+	}
+
+	t.Run("etag", func(t *testing.T) {
+		mockService := new(mockCalendarFeedService)
+		handler := NewCalendarFeedHandler(mockService, "")
+		mockService.On("WriteCalendarFeed", mock.Anything, "secret", exporter.ICalendarOptions{Component: exporter.ComponentEvent, Name: calendarFeedName}, mock.Anything).
+			Run(writeBody).Return(nil)
+
+		rec, err := serveFeed(handler, "secret.ics", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, body, rec.Body.String())
+		assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+		etag := rec.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		rec, err = serveFeed(handler, "secret.ics", "", `"other", W/`+etag)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("vtodo", func(t *testing.T) {
+		mockService := new(mockCalendarFeedService)
+		handler := NewCalendarFeedHandler(mockService, "")
+		mockService.On("WriteCalendarFeed", mock.Anything, "secret", exporter.ICalendarOptions{Component: exporter.ComponentTodo, Name: calendarFeedName}, mock.Anything).
+			Run(writeBody).Return(nil)
+
+		rec, err := serveFeed(handler, "secret", "?component=VTODO", "")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("invalid_component", func(t *testing.T) {
+		handler := NewCalendarFeedHandler(new(mockCalendarFeedService), "")
+
+		_, err := serveFeed(handler, "secret", "?component=vjournal", "")
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("unknown_token", func(t *testing.T) {
+		mockService := new(mockCalendarFeedService)
+		handler := NewCalendarFeedHandler(mockService, "")
+		mockService.On("WriteCalendarFeed", mock.Anything, "revoked", mock.Anything, mock.Anything).Return(repository.ErrCalendarFeedNotFound)
+
+		_, err := serveFeed(handler, "revoked.ics", "", "")
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type CommentHandler struct {
+	pb.UnimplementedCommentServiceServer
+	commentService service.CommentService
+}
+
+func NewCommentHandler(commentService service.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+func (h *CommentHandler) AddComment(ctx context.Context, req *pb.AddCommentRequest) (*pb.Comment, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	comment, err := h.commentService.AddComment(ctx, req.TaskId, userID, req.Body)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertCommentToProto(comment), nil
+}
+
+func (h *CommentHandler) ListComments(ctx context.Context, req *pb.ListCommentsRequest) (*pb.ListCommentsResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	page, err := h.commentService.ListComments(ctx, req.TaskId, userID, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.ListCommentsResponse{
+		Comments:      make([]*pb.Comment, len(page.Comments)),
+		NextPageToken: page.NextPageToken,
+	}
+	for i, comment := range page.Comments {
+		resp.Comments[i] = convertCommentToProto(comment)
+	}
+	return resp, nil
+}
+
+func (h *CommentHandler) EditComment(ctx context.Context, req *pb.EditCommentRequest) (*pb.Comment, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	comment, err := h.commentService.EditComment(ctx, req.CommentId, userID, req.Body)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertCommentToProto(comment), nil
+}
+
+func (h *CommentHandler) DeleteComment(ctx context.Context, req *pb.DeleteCommentRequest) (*pb.Empty, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	if err := h.commentService.DeleteComment(ctx, req.CommentId, userID); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func convertCommentToProto(comment *model.Comment) *pb.Comment {
+	resp := &pb.Comment{
+		CommentId: comment.ID.Hex(),
+		TaskId:    comment.TaskID.Hex(),
+		AuthorId:  comment.AuthorID,
+		Body:      comment.Body,
+		Mentions:  comment.Mentions,
+		Revisions: make([]*pb.CommentRevision, len(comment.Revisions)),
+		CreatedAt: timestamppb.New(comment.CreatedAt),
+		UpdatedAt: timestamppb.New(comment.UpdatedAt),
+	}
+	if !comment.EditedAt.IsZero() {
+		resp.EditedAt = timestamppb.New(comment.EditedAt)
+	}
+	for i, revision := range comment.Revisions {
+		resp.Revisions[i] = &pb.CommentRevision{
+			Body:     revision.Body,
+			EditedAt: timestamppb.New(revision.EditedAt),
+		}
+	}
+	return resp
+}
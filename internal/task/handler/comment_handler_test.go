@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockCommentService struct {
+	mock.Mock
+}
+
+func (m *mockCommentService) AddComment(ctx context.Context, taskID string, authorID string, body string) (*model.Comment, error) {
+	args := m.Called(ctx, taskID, authorID, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Comment), args.Error(1)
+}
+
+func (m *mockCommentService) ListComments(ctx context.Context, taskID string, userID string, pageSize int32, pageToken string) (*model.CommentPage, error) {
+	args := m.Called(ctx, taskID, userID, pageSize, pageToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CommentPage), args.Error(1)
+}
+
+func (m *mockCommentService) EditComment(ctx context.Context, commentID string, userID string, body string) (*model.Comment, error) {
+	args := m.Called(ctx, commentID, userID, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Comment), args.Error(1)
+}
+
+func (m *mockCommentService) DeleteComment(ctx context.Context, commentID string, userID string) error {
+	args := m.Called(ctx, commentID, userID)
+	return args.Error(0)
+}
+
+func TestCommentHandler_AddComment(t *testing.T) {
+	mockService := new(mockCommentService)
+	handler := NewCommentHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	taskID := primitive.NewObjectID()
+
+	t.Run("success", func(t *testing.T) {
+		comment := &model.Comment{
+			ID:        primitive.NewObjectID(),
+			TaskID:    taskID,
+			AuthorID:  "user1",
+			Body:      "@alice@example.com 確認お願いします",
+			Mentions:  []string{"alice"},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		mockService.On("AddComment", ctx, taskID.Hex(), "user1", comment.Body).Return(comment, nil).Once()
+
+		resp, err := handler.AddComment(ctx, &pb.AddCommentRequest{TaskId: taskID.Hex(), Body: comment.Body})
+		assert.NoError(t, err)
+		assert.Equal(t, comment.ID.Hex(), resp.CommentId)
+		assert.Equal(t, []string{"alice"}, resp.Mentions)
+		assert.Nil(t, resp.EditedAt)
+	})
+
+	t.Run("permission_denied", func(t *testing.T) {
+		mockService.On("AddComment", ctx, taskID.Hex(), "user1", "コメント").
+			Return(nil, apperrors.NewPermissionDeniedError("このタスクのコメントを操作する権限がありません", nil)).Once()
+
+		_, err := handler.AddComment(ctx, &pb.AddCommentRequest{TaskId: taskID.Hex(), Body: "コメント"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := handler.AddComment(context.Background(), &pb.AddCommentRequest{TaskId: taskID.Hex(), Body: "コメント"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+	mockService.AssertExpectations(t)
+}
+
+func TestCommentHandler_EditComment(t *testing.T) {
+	mockService := new(mockCommentService)
+	handler := NewCommentHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	editedAt := time.Now()
+	comment := &model.Comment{
+		ID:        primitive.NewObjectID(),
+		TaskID:    primitive.NewObjectID(),
+		AuthorID:  "user1",
+		Body:      "修正後",
+		Revisions: []model.CommentRevision{{Body: "修正前", EditedAt: editedAt}},
+		EditedAt:  editedAt,
+	}
+	mockService.On("EditComment", ctx, comment.ID.Hex(), "user1", "修正後").Return(comment, nil).Once()
+
+	resp, err := handler.EditComment(ctx, &pb.EditCommentRequest{CommentId: comment.ID.Hex(), Body: "修正後"})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Revisions, 1)
+	assert.Equal(t, "修正前", resp.Revisions[0].Body)
+	assert.NotNil(t, resp.EditedAt)
+	mockService.AssertExpectations(t)
+}
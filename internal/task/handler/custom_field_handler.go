@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type CustomFieldHandler struct {
+	pb.UnimplementedCustomFieldServiceServer
+	fieldService service.CustomFieldService
+}
+
+func NewCustomFieldHandler(fieldService service.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		fieldService: fieldService,
+	}
+}
+
+func (h *CustomFieldHandler) CreateCustomField(ctx context.Context, req *pb.CreateCustomFieldRequest) (*pb.CustomFieldDefinition, error) {
+	ownerID, err := callerID(ctx, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	field, err := h.fieldService.CreateCustomField(ctx, &model.CustomFieldDefinition{
+		OwnerID:  ownerID,
+		Name:     req.Name,
+		Type:     convertCustomFieldTypeFromProto(req.Type),
+		Options:  req.Options,
+		Required: req.Required,
+	})
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return convertCustomFieldToProto(field), nil
+}
+
+func (h *CustomFieldHandler) ListCustomFields(ctx context.Context, req *pb.ListCustomFieldsRequest) (*pb.ListCustomFieldsResponse, error) {
+	ownerID, err := callerID(ctx, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := h.fieldService.ListCustomFields(ctx, ownerID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.ListCustomFieldsResponse{Fields: make([]*pb.CustomFieldDefinition, len(fields))}
+	for i, field := range fields {
+		resp.Fields[i] = convertCustomFieldToProto(field)
+	}
+	return resp, nil
+}
+
+func (h *CustomFieldHandler) UpdateCustomField(ctx context.Context, req *pb.UpdateCustomFieldRequest) (*pb.CustomFieldDefinition, error) {
+	ownerID, err := callerID(ctx, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(req.FieldId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, apperrors.NewInvalidInputError("無効なIDです", err))
+	}
+
+	field, err := h.fieldService.UpdateCustomField(ctx, &model.CustomFieldDefinition{
+		ID:       id,
+		OwnerID:  ownerID,
+		Name:     req.Name,
+		Options:  req.Options,
+		Required: req.Required,
+	})
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return convertCustomFieldToProto(field), nil
+}
+
+func (h *CustomFieldHandler) DeleteCustomField(ctx context.Context, req *pb.DeleteCustomFieldRequest) (*pb.Empty, error) {
+	ownerID, err := callerID(ctx, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.fieldService.DeleteCustomField(ctx, ownerID, req.FieldId); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func convertCustomFieldTypeFromProto(t pb.CustomFieldType) model.CustomFieldType {
+	switch t {
+	case pb.CustomFieldType_CUSTOM_FIELD_TYPE_TEXT:
+		return model.CustomFieldText
+	case pb.CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER:
+		return model.CustomFieldNumber
+	case pb.CustomFieldType_CUSTOM_FIELD_TYPE_DATE:
+		return model.CustomFieldDate
+	case pb.CustomFieldType_CUSTOM_FIELD_TYPE_SINGLE_SELECT:
+		return model.CustomFieldSingleSelect
+	default:
+		return ""
+	}
+}
+
+func convertCustomFieldTypeToProto(t model.CustomFieldType) pb.CustomFieldType {
+	switch t {
+	case model.CustomFieldText:
+		return pb.CustomFieldType_CUSTOM_FIELD_TYPE_TEXT
+	case model.CustomFieldNumber:
+		return pb.CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER
+	case model.CustomFieldDate:
+		return pb.CustomFieldType_CUSTOM_FIELD_TYPE_DATE
+	case model.CustomFieldSingleSelect:
+		return pb.CustomFieldType_CUSTOM_FIELD_TYPE_SINGLE_SELECT
+	default:
+		return pb.CustomFieldType_CUSTOM_FIELD_TYPE_UNSPECIFIED
+	}
+}
+
+func convertCustomFieldToProto(field *model.CustomFieldDefinition) *pb.CustomFieldDefinition {
+	return &pb.CustomFieldDefinition{
+		FieldId:   field.ID.Hex(),
+		OwnerId:   field.OwnerID,
+		Name:      field.Name,
+		Type:      convertCustomFieldTypeToProto(field.Type),
+		Options:   field.Options,
+		Required:  field.Required,
+		CreatedAt: timestamppb.New(field.CreatedAt),
+		UpdatedAt: timestamppb.New(field.UpdatedAt),
+	}
+}
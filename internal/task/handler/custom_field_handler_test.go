@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockCustomFieldService struct {
+	mock.Mock
+}
+
+func (m *mockCustomFieldService) CreateCustomField(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error) {
+	args := m.Called(ctx, field)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomFieldDefinition), args.Error(1)
+}
+
+func (m *mockCustomFieldService) ListCustomFields(ctx context.Context, ownerID string) ([]*model.CustomFieldDefinition, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.CustomFieldDefinition), args.Error(1)
+}
+
+func (m *mockCustomFieldService) UpdateCustomField(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error) {
+	args := m.Called(ctx, field)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomFieldDefinition), args.Error(1)
+}
+
+func (m *mockCustomFieldService) DeleteCustomField(ctx context.Context, ownerID string, id string) error {
+	args := m.Called(ctx, ownerID, id)
+	return args.Error(0)
+}
+
+func TestCustomFieldHandler_UsesAuthenticatedUser(t *testing.T) {
+	mockService := new(mockCustomFieldService)
+	handler := NewCustomFieldHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	fieldID := primitive.NewObjectID()
+
+	t.Run("update_without_owner_id", func(t *testing.T) {
+		mockService.On("UpdateCustomField", ctx, mock.MatchedBy(func(f *model.CustomFieldDefinition) bool {
+			return f.ID == fieldID && f.OwnerID == "user1"
+		})).Return(&model.CustomFieldDefinition{ID: fieldID, OwnerID: "user1", Name: "見積", Type: model.CustomFieldNumber}, nil).Once()
+
+		resp, err := handler.UpdateCustomField(ctx, &pb.UpdateCustomFieldRequest{FieldId: fieldID.Hex(), Name: "見積"})
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", resp.OwnerId)
+	})
+
+	t.Run("delete_same_owner", func(t *testing.T) {
+		mockService.On("DeleteCustomField", ctx, "user1", fieldID.Hex()).Return(nil).Once()
+
+		_, err := handler.DeleteCustomField(ctx, &pb.DeleteCustomFieldRequest{OwnerId: "user1", FieldId: fieldID.Hex()})
+		assert.NoError(t, err)
+	})
+
+	t.Run("mismatched_owner", func(t *testing.T) {
+		_, err := handler.CreateCustomField(ctx, &pb.CreateCustomFieldRequest{OwnerId: "user2", Name: "見積", Type: pb.CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = handler.ListCustomFields(ctx, &pb.ListCustomFieldsRequest{OwnerId: "user2"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = handler.UpdateCustomField(ctx, &pb.UpdateCustomFieldRequest{OwnerId: "user2", FieldId: fieldID.Hex(), Name: "見積"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = handler.DeleteCustomField(ctx, &pb.DeleteCustomFieldRequest{OwnerId: "user2", FieldId: fieldID.Hex()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockService.AssertExpectations(t)
+	})
+}
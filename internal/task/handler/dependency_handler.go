@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/model"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *TaskHandler) AddDependency(ctx context.Context, req *pb.AddDependencyRequest) (*pb.TaskDependency, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	dep, err := h.taskService.AddDependency(ctx, req.TaskId, userID, req.BlockerId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return &pb.TaskDependency{
+		TaskId:    dep.TaskID.Hex(),
+		BlockerId: dep.BlockerID.Hex(),
+		CreatedAt: timestamppb.New(dep.CreatedAt),
+	}, nil
+}
+
+func (h *TaskHandler) RemoveDependency(ctx context.Context, req *pb.RemoveDependencyRequest) (*pb.Empty, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.taskService.RemoveDependency(ctx, req.TaskId, userID, req.BlockerId); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (h *TaskHandler) ListDependencies(ctx context.Context, req *pb.ListDependenciesRequest) (*pb.ListDependenciesResponse, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := h.taskService.ListDependencies(ctx, req.TaskId, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return &pb.ListDependenciesResponse{
+		BlockedBy: convertTasksToProto(deps.BlockedBy),
+		Blocks:    convertTasksToProto(deps.Blocks),
+	}, nil
+}
+
+func (h *TaskHandler) ListNextTasks(ctx context.Context, req *pb.ListNextTasksRequest) (*pb.ListNextTasksResponse, error) {
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := h.taskService.ListNextTasks(ctx, userID, req.PageSize)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.ListNextTasksResponse{Tasks: make([]*pb.NextTask, len(next))}
+	for i, n := range next {
+		blockerIDs := make([]string, len(n.OpenBlockerIDs))
+		for j, id := range n.OpenBlockerIDs {
+			blockerIDs[j] = id.Hex()
+		}
+		resp.Tasks[i] = &pb.NextTask{
+			Task:           convertTaskToProto(n.Task),
+			OpenBlockerIds: blockerIDs,
+		}
+	}
+	return resp, nil
+}
+
+func convertTasksToProto(tasks []*model.Task) []*pb.Task {
+	result := make([]*pb.Task, len(tasks))
+	for i, task := range tasks {
+		result[i] = convertTaskToProto(task)
+	}
+	return result
+}
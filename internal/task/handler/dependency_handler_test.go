@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTaskHandler_AddDependency(t *testing.T) {
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	taskID := primitive.NewObjectID()
+	blockerID := primitive.NewObjectID()
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("AddDependency", ctx, taskID.Hex(), "user1", blockerID.Hex()).
+			Return(&model.TaskDependency{TaskID: taskID, BlockerID: blockerID}, nil).Once()
+
+		resp, err := handler.AddDependency(ctx, &pb.AddDependencyRequest{TaskId: taskID.Hex(), BlockerId: blockerID.Hex()})
+		assert.NoError(t, err)
+		assert.Equal(t, taskID.Hex(), resp.TaskId)
+		assert.Equal(t, blockerID.Hex(), resp.BlockerId)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		mockService.On("AddDependency", ctx, blockerID.Hex(), "user1", taskID.Hex()).
+			Return(nil, apperrors.NewInvalidInputError("依存関係が循環します", nil)).Once()
+
+		_, err := handler.AddDependency(ctx, &pb.AddDependencyRequest{TaskId: blockerID.Hex(), BlockerId: taskID.Hex()})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		mockService.On("AddDependency", ctx, taskID.Hex(), "user1", taskID.Hex()).
+			Return(nil, apperrors.NewAlreadyExistsError("同じ依存関係が既に存在します", nil)).Once()
+
+		_, err := handler.AddDependency(ctx, &pb.AddDependencyRequest{TaskId: taskID.Hex(), BlockerId: taskID.Hex()})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("blocker_of_other_user", func(t *testing.T) {
+		mockService.On("AddDependency", ctx, taskID.Hex(), "user1", blockerID.Hex()).
+			Return(nil, apperrors.NewPermissionDeniedError("このタスクを操作する権限がありません", nil)).Once()
+
+		_, err := handler.AddDependency(ctx, &pb.AddDependencyRequest{TaskId: taskID.Hex(), BlockerId: blockerID.Hex()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := handler.AddDependency(context.Background(), &pb.AddDependencyRequest{TaskId: taskID.Hex(), BlockerId: blockerID.Hex()})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+	mockService.AssertExpectations(t)
+}
+
+func TestTaskHandler_ListNextTasks(t *testing.T) {
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	ready := &model.Task{ID: primitive.NewObjectID(), Title: "ready"}
+	waiting := &model.Task{ID: primitive.NewObjectID(), Title: "waiting"}
+	mockService.On("ListNextTasks", ctx, "user1", int32(5)).Return([]*model.NextTask{
+		{Task: ready},
+		{Task: waiting, OpenBlockerIDs: []primitive.ObjectID{ready.ID}},
+	}, nil).Once()
+
+	resp, err := handler.ListNextTasks(ctx, &pb.ListNextTasksRequest{UserId: "user1", PageSize: 5})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Tasks, 2)
+	assert.Empty(t, resp.Tasks[0].OpenBlockerIds)
+	assert.Equal(t, []string{ready.ID.Hex()}, resp.Tasks[1].OpenBlockerIds)
+
+	_, err = handler.ListNextTasks(ctx, &pb.ListNextTasksRequest{UserId: "user2", PageSize: 5})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/exporter"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var exportFormats = map[pb.ExportFormat]exporter.Format{
+	pb.ExportFormat_EXPORT_FORMAT_JSONL:     exporter.FormatJSONL,
+	pb.ExportFormat_EXPORT_FORMAT_CSV:       exporter.FormatCSV,
+	pb.ExportFormat_EXPORT_FORMAT_ICALENDAR: exporter.FormatICalendar,
+}
+
+func (h *TaskHandler) ExportTasks(req *pb.ExportTasksRequest, stream pb.TaskService_ExportTasksServer) error {
+	userID, ok := interceptor.UserIDFromContext(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	format, ok := exportFormats[req.Format]
+	if !ok {
+		return status.Error(codes.InvalidArgument, "export format is required")
+	}
+	listReq := req.Filter
+	if listReq == nil {
+		listReq = &pb.ListTasksRequest{}
+	}
+	filter, err := convertListRequestToFilter(listReq)
+	if err != nil {
+		return convertErrorToGRPCStatus(stream.Context(), err)
+	}
+
+	if err := stream.Send(&pb.ExportTasksResponse{
+		Data: &pb.ExportTasksResponse_Metadata{Metadata: &pb.ExportMetadata{
+			ContentType:   format.ContentType(),
+			FileExtension: format.Extension(),
+		}},
+	}); err != nil {
+		return err
+	}
+
+	chunks := &exportChunkWriter{stream: stream}
+	out := bufio.NewWriterSize(chunks, model.AttachmentChunkSize)
+	encoder, err := exporter.NewEncoder(out, format)
+	if err != nil {
+		return status.Error(codes.Internal, "failed to create encoder")
+	}
+	if err := h.taskService.ExportTasks(stream.Context(), userID, filter, encoder.Encode); err != nil {
+		if chunks.err != nil {
+			return chunks.err
+		}
+		return convertErrorToGRPCStatus(stream.Context(), err)
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// exportStream は ExportTasksResponse を送るストリームです。タスクの書き出しとタイムシートの書き出しで共有します
+type exportStream interface {
+	Send(*pb.ExportTasksResponse) error
+}
+
+// exportChunkWriter は書き込まれた内容を AttachmentChunkSize 以下の chunk に分けて送ります
+type exportChunkWriter struct {
+	stream exportStream
+	// err は送信のエラーです。クライアントの切断などをサービスのエラーと区別するために保持します
+	err error
+}
+
+func (w *exportChunkWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	for sent := 0; sent < len(p); {
+		n := min(len(p)-sent, model.AttachmentChunkSize)
+		// bufio.Writer は戻った後にバッファを再利用するため、送るメッセージにはコピーを渡す
+		if err := w.stream.Send(&pb.ExportTasksResponse{
+			Data: &pb.ExportTasksResponse_Chunk{Chunk: bytes.Clone(p[sent : sent+n])},
+		}); err != nil {
+			w.err = err
+			return sent, err
+		}
+		sent += n
+	}
+	return len(p), nil
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/importer"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeExportStream は送信したメッセージを記録します。failAfter 件を送った後は sendErr を返します
+type fakeExportStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	messages  []*pb.ExportTasksResponse
+	failAfter int
+	sendErr   error
+}
+
+func (s *fakeExportStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeExportStream) Send(resp *pb.ExportTasksResponse) error {
+	if s.sendErr != nil && len(s.messages) >= s.failAfter {
+		return s.sendErr
+	}
+	s.messages = append(s.messages, resp)
+	return nil
+}
+
+func (s *fakeExportStream) content() string {
+	var buf bytes.Buffer
+	for _, msg := range s.messages[1:] {
+		buf.Write(msg.GetChunk())
+	}
+	return buf.String()
+}
+
+// exportTasksWith は ExportTasks のモックが tasks を順に渡すよう設定します
+func exportTasksWith(mockService *mockTaskService, ctx context.Context, tasks []*model.Task) *mock.Call {
+	return mockService.On("ExportTasks", ctx, "user1", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(3).(func(*model.Task) error)
+			for _, task := range tasks {
+				if err := fn(task); err != nil {
+					return
+				}
+			}
+		})
+}
+
+func TestTaskHandler_ExportTasks(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	due := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("jsonl", func(t *testing.T) {
+		mockService := new(mockTaskService)
+		handler := NewTaskHandler(mockService)
+		var tasks []*model.Task
+		for i := 0; i < 300; i++ {
+			tasks = append(tasks, &model.Task{
+				ID:          primitive.NewObjectID(),
+				Title:       "task",
+				Description: strings.Repeat("説明", 100),
+				Status:      model.TaskStatusPending,
+				DueDate:     due,
+			})
+		}
+		exportTasksWith(mockService, ctx, tasks).Return(nil)
+
+		stream := &fakeExportStream{ctx: ctx}
+		err := handler.ExportTasks(&pb.ExportTasksRequest{
+			Filter: &pb.ListTasksRequest{Statuses: []pb.TaskStatus{pb.TaskStatus_TASK_STATUS_PENDING}},
+			Format: pb.ExportFormat_EXPORT_FORMAT_JSONL,
+		}, stream)
+		require.NoError(t, err)
+
+		assert.Equal(t, "application/x-ndjson", stream.messages[0].GetMetadata().ContentType)
+		assert.Equal(t, "jsonl", stream.messages[0].GetMetadata().FileExtension)
+		assert.Greater(t, len(stream.messages), 2)
+		for _, msg := range stream.messages[1:] {
+			assert.LessOrEqual(t, len(msg.GetChunk()), model.AttachmentChunkSize)
+		}
+		filter := mockService.Calls[0].Arguments.Get(2).(*model.TaskFilter)
+		assert.Equal(t, []model.TaskStatus{model.TaskStatusPending}, filter.Statuses)
+
+		reader := importer.NewJSONLReader(strings.NewReader(stream.content()), importer.Options{})
+		count := 0
+		for {
+			row, err := reader.Next()
+			if err != nil {
+				break
+			}
+			require.NoError(t, row.Err)
+			assert.Equal(t, "task", row.Task.Title)
+			count++
+		}
+		assert.Equal(t, 300, count)
+	})
+
+	t.Run("icalendar_without_filter", func(t *testing.T) {
+		mockService := new(mockTaskService)
+		handler := NewTaskHandler(mockService)
+		exportTasksWith(mockService, ctx, []*model.Task{{ID: primitive.NewObjectID(), Title: "a", Status: model.TaskStatusComplete, DueDate: due}}).Return(nil)
+
+		stream := &fakeExportStream{ctx: ctx}
+		require.NoError(t, handler.ExportTasks(&pb.ExportTasksRequest{Format: pb.ExportFormat_EXPORT_FORMAT_ICALENDAR}, stream))
+		assert.Contains(t, stream.content(), "STATUS:COMPLETED\r\n")
+		assert.True(t, strings.HasSuffix(stream.content(), "END:VCALENDAR\r\n"))
+	})
+
+	t.Run("send_error", func(t *testing.T) {
+		mockService := new(mockTaskService)
+		handler := NewTaskHandler(mockService)
+		sendErr := status.Error(codes.Canceled, "context canceled")
+		var tasks []*model.Task
+		for i := 0; i < 300; i++ {
+			tasks = append(tasks, &model.Task{ID: primitive.NewObjectID(), Title: strings.Repeat("a", 1000), Status: model.TaskStatusPending})
+		}
+		mockService.On("ExportTasks", ctx, "user1", mock.Anything, mock.Anything).
+			Return(errors.New("送信に失敗しました")).
+			Run(func(args mock.Arguments) {
+				fn := args.Get(3).(func(*model.Task) error)
+				for _, task := range tasks {
+					if fn(task) != nil {
+						return
+					}
+				}
+			})
+
+		err := handler.ExportTasks(&pb.ExportTasksRequest{Format: pb.ExportFormat_EXPORT_FORMAT_CSV},
+			&fakeExportStream{ctx: ctx, failAfter: 1, sendErr: sendErr})
+		assert.Equal(t, codes.Canceled, status.Code(err))
+	})
+
+	t.Run("format_required", func(t *testing.T) {
+		handler := NewTaskHandler(new(mockTaskService))
+
+		err := handler.ExportTasks(&pb.ExportTasksRequest{}, &fakeExportStream{ctx: ctx})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewTaskHandler(new(mockTaskService))
+
+		err := handler.ExportTasks(&pb.ExportTasksRequest{Format: pb.ExportFormat_EXPORT_FORMAT_CSV}, &fakeExportStream{ctx: context.Background()})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/task/interceptor"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// callerID は認証済みのユーザーIDを返します。リクエストでユーザーIDを指定している場合は、
+// 認証済みのユーザーと一致しなければ他人のデータを操作させないよう PERMISSION_DENIED を返します
+func callerID(ctx context.Context, requested string) (string, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	if requested != "" && requested != userID {
+		return "", status.Error(codes.PermissionDenied, "user id does not match the authenticated user")
+	}
+	return userID, nil
+}
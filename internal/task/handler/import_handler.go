@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ImportHandler struct {
+	pb.UnimplementedImportServiceServer
+	importService service.ImportService
+}
+
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+func (h *ImportHandler) ImportTasks(stream pb.ImportService_ImportTasksServer) error {
+	userID, ok := interceptor.UserIDFromContext(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "options are required")
+		}
+		return err
+	}
+	options := first.GetOptions()
+	if options == nil {
+		return status.Error(codes.InvalidArgument, "the first message must contain options")
+	}
+
+	result, err := h.importService.ImportTasks(stream.Context(), userID, model.ImportOptions{
+		JobID:     options.JobId,
+		DryRun:    options.DryRun,
+		BatchSize: int(options.BatchSize),
+	}, &importRowSource{stream: stream, userID: userID})
+	if err != nil {
+		return convertErrorToGRPCStatus(stream.Context(), err)
+	}
+
+	resp := &pb.ImportTasksResponse{
+		JobId:         result.JobID,
+		DryRun:        result.DryRun,
+		ReceivedCount: result.Received,
+		SkippedCount:  result.Skipped,
+		ImportedCount: result.Imported,
+		FailedCount:   result.Failed,
+		LastRow:       result.LastRow,
+		Errors:        make([]*pb.ImportRowError, len(result.Errors)),
+	}
+	for i, rowErr := range result.Errors {
+		resp.Errors[i] = &pb.ImportRowError{RowNumber: rowErr.Row, Message: rowErr.Message}
+	}
+	return stream.SendAndClose(resp)
+}
+
+func (h *ImportHandler) GetImportJob(ctx context.Context, req *pb.GetImportJobRequest) (*pb.ImportJob, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	job, err := h.importService.GetImportJob(ctx, userID, req.JobId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return &pb.ImportJob{
+		JobId:         job.JobID,
+		Completed:     job.Status == model.ImportJobCompleted,
+		LastRow:       job.LastRow,
+		ImportedCount: job.Imported,
+		FailedCount:   job.Failed,
+		CreatedAt:     timestamppb.New(job.CreatedAt),
+		UpdatedAt:     timestamppb.New(job.UpdatedAt),
+	}, nil
+}
+
+// importRowSource は取り込みのストリームで受信した行を、userID のタスクとして順に返します
+type importRowSource struct {
+	stream pb.ImportService_ImportTasksServer
+	userID string
+}
+
+func (s *importRowSource) Next() (*model.ImportRow, error) {
+	msg, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	row := msg.GetRow()
+	if row == nil {
+		return nil, apperrors.NewInvalidInputError("options は最初のメッセージでのみ送信できます", nil)
+	}
+	if row.Task == nil {
+		return &model.ImportRow{Number: row.RowNumber, Err: errors.New("タスクが指定されていません")}, nil
+	}
+	task, err := convertCreateRequestToTask(row.Task, s.userID)
+	if err != nil {
+		return &model.ImportRow{Number: row.RowNumber, Err: err}, nil
+	}
+	// ステータスを指定していない行は、通常の作成と同じくワークフローの初期ステータスにするためゼロ値にする
+	if row.Task.Status == pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		task.Status = ""
+	}
+	return &model.ImportRow{Number: row.RowNumber, Task: task}, nil
+}
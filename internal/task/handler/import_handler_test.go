@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type mockImportService struct {
+	mock.Mock
+}
+
+func (m *mockImportService) ImportTasks(ctx context.Context, userID string, opts model.ImportOptions, rows service.ImportRowSource) (*model.ImportResult, error) {
+	args := m.Called(ctx, userID, opts, rows)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportResult), args.Error(1)
+}
+
+func (m *mockImportService) GetImportJob(ctx context.Context, userID string, jobID string) (*model.ImportJob, error) {
+	args := m.Called(ctx, userID, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportJob), args.Error(1)
+}
+
+// fakeImportStream は受信するメッセージを順に返す取り込みのストリームです
+type fakeImportStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []*pb.ImportTasksRequest
+	response *pb.ImportTasksResponse
+}
+
+func (s *fakeImportStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeImportStream) Recv() (*pb.ImportTasksRequest, error) {
+	if len(s.messages) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	return msg, nil
+}
+
+func (s *fakeImportStream) SendAndClose(resp *pb.ImportTasksResponse) error {
+	s.response = resp
+	return nil
+}
+
+func rowMessage(number int64, task *pb.CreateTaskRequest) *pb.ImportTasksRequest {
+	return &pb.ImportTasksRequest{Payload: &pb.ImportTasksRequest_Row{Row: &pb.ImportRow{RowNumber: number, Task: task}}}
+}
+
+func TestImportHandler_ImportTasks(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	t.Run("rows", func(t *testing.T) {
+		mockService := new(mockImportService)
+		handler := NewImportHandler(mockService)
+		var rows []*model.ImportRow
+		mockService.On("ImportTasks", ctx, "user1", model.ImportOptions{JobID: "job1", BatchSize: 10}, mock.Anything).
+			Run(func(args mock.Arguments) {
+				source := args.Get(3).(service.ImportRowSource)
+				for {
+					row, err := source.Next()
+					if err != nil {
+						return
+					}
+					rows = append(rows, row)
+				}
+			}).
+			Return(&model.ImportResult{JobID: "job1", Received: 3, Imported: 1, Failed: 2, LastRow: 3, Errors: []model.ImportRowError{{Row: 2, Message: "期限は必須です"}}}, nil)
+
+		stream := &fakeImportStream{ctx: ctx, messages: []*pb.ImportTasksRequest{
+			{Payload: &pb.ImportTasksRequest_Options{Options: &pb.ImportOptions{JobId: "job1", BatchSize: 10}}},
+			rowMessage(1, &pb.CreateTaskRequest{Title: "a", Status: pb.TaskStatus_TASK_STATUS_PENDING, DueDate: timestamppb.Now()}),
+			rowMessage(2, &pb.CreateTaskRequest{Title: "b"}),
+			rowMessage(3, &pb.CreateTaskRequest{Title: "c", ParentId: "invalid"}),
+		}}
+		require.NoError(t, handler.ImportTasks(stream))
+
+		require.Len(t, rows, 3)
+		assert.Equal(t, "a", rows[0].Task.Title)
+		assert.Equal(t, model.TaskStatus(""), rows[1].Task.Status)
+		assert.True(t, rows[1].Task.DueDate.IsZero())
+		assert.Error(t, rows[2].Err)
+		assert.Equal(t, int64(2), stream.response.FailedCount)
+		assert.Equal(t, int64(2), stream.response.Errors[0].RowNumber)
+	})
+
+	t.Run("options_required", func(t *testing.T) {
+		handler := NewImportHandler(new(mockImportService))
+
+		err := handler.ImportTasks(&fakeImportStream{ctx: ctx, messages: []*pb.ImportTasksRequest{rowMessage(1, nil)}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		err = handler.ImportTasks(&fakeImportStream{ctx: ctx})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewImportHandler(new(mockImportService))
+
+		err := handler.ImportTasks(&fakeImportStream{ctx: context.Background()})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
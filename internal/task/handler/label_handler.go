@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type LabelHandler struct {
+	pb.UnimplementedLabelServiceServer
+	labelService service.LabelService
+}
+
+func NewLabelHandler(labelService service.LabelService) *LabelHandler {
+	return &LabelHandler{
+		labelService: labelService,
+	}
+}
+
+func (h *LabelHandler) CreateLabel(ctx context.Context, req *pb.CreateLabelRequest) (*pb.Label, error) {
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	label, err := h.labelService.CreateLabel(ctx, &model.Label{
+		UserID: userID,
+		Name:   req.Name,
+		Color:  req.Color,
+	})
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return convertLabelToProto(label), nil
+}
+
+func (h *LabelHandler) ListLabels(ctx context.Context, req *pb.ListLabelsRequest) (*pb.ListLabelsResponse, error) {
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := h.labelService.ListLabels(ctx, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.ListLabelsResponse{Labels: make([]*pb.Label, len(labels))}
+	for i, label := range labels {
+		resp.Labels[i] = convertLabelToProto(label)
+	}
+	return resp, nil
+}
+
+func (h *LabelHandler) UpdateLabel(ctx context.Context, req *pb.UpdateLabelRequest) (*pb.Label, error) {
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	label, err := h.labelService.UpdateLabel(ctx, userID, req.LabelId, req.Name, req.Color)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return convertLabelToProto(label), nil
+}
+
+func (h *LabelHandler) DeleteLabel(ctx context.Context, req *pb.DeleteLabelRequest) (*pb.Empty, error) {
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.labelService.DeleteLabel(ctx, userID, req.LabelId); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func convertLabelToProto(label *model.Label) *pb.Label {
+	return &pb.Label{
+		LabelId:   label.ID.Hex(),
+		UserId:    label.UserID,
+		Name:      label.Name,
+		Color:     label.Color,
+		CreatedAt: timestamppb.New(label.CreatedAt),
+		UpdatedAt: timestamppb.New(label.UpdatedAt),
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockLabelService struct {
+	mock.Mock
+}
+
+func (m *mockLabelService) CreateLabel(ctx context.Context, label *model.Label) (*model.Label, error) {
+	args := m.Called(ctx, label)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Label), args.Error(1)
+}
+
+func (m *mockLabelService) ListLabels(ctx context.Context, userID string) ([]*model.Label, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Label), args.Error(1)
+}
+
+func (m *mockLabelService) UpdateLabel(ctx context.Context, userID string, id string, name string, color string) (*model.Label, error) {
+	args := m.Called(ctx, userID, id, name, color)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Label), args.Error(1)
+}
+
+func (m *mockLabelService) DeleteLabel(ctx context.Context, userID string, id string) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestLabelHandler_UsesAuthenticatedUser(t *testing.T) {
+	mockService := new(mockLabelService)
+	handler := NewLabelHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	labelID := primitive.NewObjectID()
+
+	t.Run("create_without_user_id", func(t *testing.T) {
+		mockService.On("CreateLabel", ctx, mock.MatchedBy(func(l *model.Label) bool {
+			return l.UserID == "user1" && l.Name == "仕事"
+		})).Return(&model.Label{ID: labelID, UserID: "user1", Name: "仕事"}, nil).Once()
+
+		resp, err := handler.CreateLabel(ctx, &pb.CreateLabelRequest{Name: "仕事"})
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", resp.UserId)
+	})
+
+	t.Run("list_same_user", func(t *testing.T) {
+		mockService.On("ListLabels", ctx, "user1").Return([]*model.Label{{ID: labelID, UserID: "user1", Name: "仕事"}}, nil).Once()
+
+		resp, err := handler.ListLabels(ctx, &pb.ListLabelsRequest{UserId: "user1"})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Labels, 1)
+	})
+
+	t.Run("mismatched_user", func(t *testing.T) {
+		_, err := handler.CreateLabel(ctx, &pb.CreateLabelRequest{UserId: "user2", Name: "仕事"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = handler.ListLabels(ctx, &pb.ListLabelsRequest{UserId: "user2"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = handler.UpdateLabel(ctx, &pb.UpdateLabelRequest{UserId: "user2", LabelId: labelID.Hex(), Name: "私用"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = handler.DeleteLabel(ctx, &pb.DeleteLabelRequest{UserId: "user2", LabelId: labelID.Hex()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := handler.ListLabels(context.Background(), &pb.ListLabelsRequest{UserId: "user1"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
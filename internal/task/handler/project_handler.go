@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ProjectHandler struct {
+	pb.UnimplementedProjectServiceServer
+	projectService service.ProjectService
+}
+
+func NewProjectHandler(projectService service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+	}
+}
+
+func (h *ProjectHandler) CreateProject(ctx context.Context, req *pb.CreateProjectRequest) (*pb.Project, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	project, err := h.projectService.CreateProject(ctx, &model.Project{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+	})
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertProjectToProto(project), nil
+}
+
+func (h *ProjectHandler) GetProject(ctx context.Context, req *pb.GetProjectRequest) (*pb.Project, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	project, err := h.projectService.GetProject(ctx, userID, req.ProjectId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertProjectToProto(project), nil
+}
+
+func (h *ProjectHandler) ListProjects(ctx context.Context, req *pb.ListProjectsRequest) (*pb.ListProjectsResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	projects, err := h.projectService.ListProjects(ctx, userID, req.IncludeArchived)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.ListProjectsResponse{Projects: make([]*pb.Project, len(projects))}
+	for i, project := range projects {
+		resp.Projects[i] = convertProjectToProto(project)
+	}
+	return resp, nil
+}
+
+func (h *ProjectHandler) UpdateProject(ctx context.Context, req *pb.UpdateProjectRequest) (*pb.Project, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	project, err := h.projectService.UpdateProject(ctx, userID, req.ProjectId, req.Name, req.Description, req.Color)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertProjectToProto(project), nil
+}
+
+func (h *ProjectHandler) ArchiveProject(ctx context.Context, req *pb.ArchiveProjectRequest) (*pb.Project, error) {
+	return h.setArchived(ctx, req.ProjectId, true)
+}
+
+func (h *ProjectHandler) UnarchiveProject(ctx context.Context, req *pb.UnarchiveProjectRequest) (*pb.Project, error) {
+	return h.setArchived(ctx, req.ProjectId, false)
+}
+
+func (h *ProjectHandler) setArchived(ctx context.Context, id string, archived bool) (*pb.Project, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	project, err := h.projectService.SetProjectArchived(ctx, userID, id, archived)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertProjectToProto(project), nil
+}
+
+func (h *ProjectHandler) DeleteProject(ctx context.Context, req *pb.DeleteProjectRequest) (*pb.Empty, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	mode := model.ProjectDeleteMoveToInbox
+	switch req.Mode {
+	case pb.ProjectDeleteMode_PROJECT_DELETE_MODE_UNSPECIFIED, pb.ProjectDeleteMode_PROJECT_DELETE_MODE_MOVE_TO_INBOX:
+	case pb.ProjectDeleteMode_PROJECT_DELETE_MODE_CASCADE:
+		mode = model.ProjectDeleteCascade
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid delete mode")
+	}
+
+	if err := h.projectService.DeleteProject(ctx, userID, req.ProjectId, mode); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return &pb.Empty{}, nil
+}
+
+// parseProjectID はリクエストのプロジェクトIDを変換します。空の場合はインボックスを表すゼロ値です
+func parseProjectID(id string) (primitive.ObjectID, error) {
+	if id == "" {
+		return primitive.NilObjectID, nil
+	}
+	projectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, apperrors.NewInvalidInputError("無効なプロジェクトIDです", err)
+	}
+	return projectID, nil
+}
+
+func convertProjectToProto(project *model.Project) *pb.Project {
+	var archivedAt *timestamppb.Timestamp
+	if !project.ArchivedAt.IsZero() {
+		archivedAt = timestamppb.New(project.ArchivedAt)
+	}
+
+	return &pb.Project{
+		ProjectId:   project.ID.Hex(),
+		Name:        project.Name,
+		Description: project.Description,
+		Color:       project.Color,
+		Archived:    project.Archived,
+		ArchivedAt:  archivedAt,
+		CreatedAt:   timestamppb.New(project.CreatedAt),
+		UpdatedAt:   timestamppb.New(project.UpdatedAt),
+	}
+}
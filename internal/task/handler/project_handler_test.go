@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockProjectService struct {
+	mock.Mock
+}
+
+func (m *mockProjectService) CreateProject(ctx context.Context, project *model.Project) (*model.Project, error) {
+	args := m.Called(ctx, project)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Project), args.Error(1)
+}
+
+func (m *mockProjectService) GetProject(ctx context.Context, userID string, id string) (*model.Project, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Project), args.Error(1)
+}
+
+func (m *mockProjectService) ListProjects(ctx context.Context, userID string, includeArchived bool) ([]*model.Project, error) {
+	args := m.Called(ctx, userID, includeArchived)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Project), args.Error(1)
+}
+
+func (m *mockProjectService) UpdateProject(ctx context.Context, userID string, id string, name string, description string, color string) (*model.Project, error) {
+	args := m.Called(ctx, userID, id, name, description, color)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Project), args.Error(1)
+}
+
+func (m *mockProjectService) SetProjectArchived(ctx context.Context, userID string, id string, archived bool) (*model.Project, error) {
+	args := m.Called(ctx, userID, id, archived)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Project), args.Error(1)
+}
+
+func (m *mockProjectService) DeleteProject(ctx context.Context, userID string, id string, mode model.ProjectDeleteMode) error {
+	args := m.Called(ctx, userID, id, mode)
+	return args.Error(0)
+}
+
+func TestProjectHandler_ArchiveProject(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	mockService := new(mockProjectService)
+	handler := NewProjectHandler(mockService)
+	projectID := primitive.NewObjectID()
+	archivedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("SetProjectArchived", ctx, "user1", projectID.Hex(), true).
+		Return(&model.Project{ID: projectID, UserID: "user1", Name: "Work", Archived: true, ArchivedAt: archivedAt}, nil)
+
+	project, err := handler.ArchiveProject(ctx, &pb.ArchiveProjectRequest{ProjectId: projectID.Hex()})
+	require.NoError(t, err)
+	assert.True(t, project.Archived)
+	assert.True(t, archivedAt.Equal(project.ArchivedAt.AsTime()))
+}
+
+func TestProjectHandler_DeleteProject(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	tests := []struct {
+		name string
+		mode pb.ProjectDeleteMode
+		want model.ProjectDeleteMode
+	}{
+		{name: "default", mode: pb.ProjectDeleteMode_PROJECT_DELETE_MODE_UNSPECIFIED, want: model.ProjectDeleteMoveToInbox},
+		{name: "cascade", mode: pb.ProjectDeleteMode_PROJECT_DELETE_MODE_CASCADE, want: model.ProjectDeleteCascade},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockProjectService)
+			handler := NewProjectHandler(mockService)
+			mockService.On("DeleteProject", ctx, "user1", "p1", tt.want).Return(nil)
+
+			_, err := handler.DeleteProject(ctx, &pb.DeleteProjectRequest{ProjectId: "p1", Mode: tt.mode})
+			require.NoError(t, err)
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewProjectHandler(new(mockProjectService))
+
+		_, err := handler.DeleteProject(context.Background(), &pb.DeleteProjectRequest{ProjectId: "p1"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
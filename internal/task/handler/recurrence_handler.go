@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/model"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (h *TaskHandler) PreviewRecurrence(ctx context.Context, req *pb.PreviewRecurrenceRequest) (*pb.PreviewRecurrenceResponse, error) {
+	recurrence := &model.Recurrence{
+		RRule:    req.Rrule,
+		TimeZone: req.TimeZone,
+		Start:    model.ProtoTimestampToTime(req.Start),
+	}
+
+	occurrences, err := h.taskService.PreviewRecurrence(ctx, recurrence, model.ProtoTimestampToTime(req.After), req.PageSize)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.PreviewRecurrenceResponse{Occurrences: make([]*timestamppb.Timestamp, len(occurrences))}
+	for i, occurrence := range occurrences {
+		resp.Occurrences[i] = timestamppb.New(occurrence)
+	}
+	return resp, nil
+}
+
+func convertRecurrenceFromProto(recurrence *pb.Recurrence) *model.Recurrence {
+	if recurrence == nil || recurrence.Rrule == "" {
+		return nil
+	}
+	return &model.Recurrence{
+		RRule:    recurrence.Rrule,
+		TimeZone: recurrence.TimeZone,
+		Start:    model.ProtoTimestampToTime(recurrence.Start),
+	}
+}
+
+func convertRecurrenceToProto(recurrence *model.Recurrence) *pb.Recurrence {
+	if recurrence == nil {
+		return nil
+	}
+	resp := &pb.Recurrence{
+		Rrule:    recurrence.RRule,
+		TimeZone: recurrence.TimeZone,
+		Start:    model.TimeToProtoTimestamp(recurrence.Start),
+	}
+	if !recurrence.NextTaskID.IsZero() {
+		resp.NextTaskId = recurrence.NextTaskID.Hex()
+	}
+	return resp
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTaskHandler_PreviewRecurrence(t *testing.T) {
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+	ctx := context.Background()
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		occurrences := []time.Time{start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)}
+		mockService.On("PreviewRecurrence", ctx, &model.Recurrence{RRule: "FREQ=WEEKLY", TimeZone: "UTC", Start: start}, time.Time{}, int32(2)).
+			Return(occurrences, nil).Once()
+
+		resp, err := handler.PreviewRecurrence(ctx, &pb.PreviewRecurrenceRequest{
+			Rrule:    "FREQ=WEEKLY",
+			TimeZone: "UTC",
+			Start:    timestamppb.New(start),
+			PageSize: 2,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Occurrences, 2)
+		assert.True(t, resp.Occurrences[0].AsTime().Equal(occurrences[0]))
+	})
+
+	t.Run("invalid_rule", func(t *testing.T) {
+		mockService.On("PreviewRecurrence", ctx, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, apperrors.NewInvalidInputError("繰り返しの設定が不正です", nil)).Once()
+
+		_, err := handler.PreviewRecurrence(ctx, &pb.PreviewRecurrenceRequest{Rrule: "FREQ=YEARLY"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestConvertRecurrence(t *testing.T) {
+	assert.Nil(t, convertRecurrenceFromProto(nil))
+	assert.Nil(t, convertRecurrenceFromProto(&pb.Recurrence{TimeZone: "UTC"}), "rrule がなければ繰り返しなし")
+	assert.Nil(t, convertRecurrenceToProto(nil))
+
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	recurrence := convertRecurrenceFromProto(&pb.Recurrence{Rrule: "FREQ=DAILY", TimeZone: "Asia/Tokyo", Start: timestamppb.New(start)})
+	assert.Equal(t, &model.Recurrence{RRule: "FREQ=DAILY", TimeZone: "Asia/Tokyo", Start: start}, recurrence)
+
+	nextID := primitive.NewObjectID()
+	recurrence.NextTaskID = nextID
+	resp := convertRecurrenceToProto(recurrence)
+	assert.Equal(t, nextID.Hex(), resp.NextTaskId)
+	assert.True(t, resp.Start.AsTime().Equal(start))
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/model"
+)
+
+var notificationChannelFromProto = map[pb.NotificationChannel]model.NotificationChannel{
+	pb.NotificationChannel_NOTIFICATION_CHANNEL_EMAIL:   model.ChannelEmail,
+	pb.NotificationChannel_NOTIFICATION_CHANNEL_WEBHOOK: model.ChannelWebhook,
+	pb.NotificationChannel_NOTIFICATION_CHANNEL_INBOX:   model.ChannelInbox,
+}
+
+var notificationChannelToProto = map[model.NotificationChannel]pb.NotificationChannel{
+	model.ChannelEmail:   pb.NotificationChannel_NOTIFICATION_CHANNEL_EMAIL,
+	model.ChannelWebhook: pb.NotificationChannel_NOTIFICATION_CHANNEL_WEBHOOK,
+	model.ChannelInbox:   pb.NotificationChannel_NOTIFICATION_CHANNEL_INBOX,
+}
+
+// convertRemindersFromProto はリマインダーを変換します。未知の通知先は空文字になり、サービスの検証で拒否されます
+func convertRemindersFromProto(reminders []*pb.Reminder) []model.Reminder {
+	if len(reminders) == 0 {
+		return nil
+	}
+	result := make([]model.Reminder, len(reminders))
+	for i, r := range reminders {
+		result[i] = model.Reminder{
+			ID:            r.ReminderId,
+			MinutesBefore: int(r.MinutesBefore),
+		}
+		for _, channel := range r.Channels {
+			result[i].Channels = append(result[i].Channels, notificationChannelFromProto[channel])
+		}
+	}
+	return result
+}
+
+func convertRemindersToProto(reminders []model.Reminder) []*pb.Reminder {
+	if len(reminders) == 0 {
+		return nil
+	}
+	result := make([]*pb.Reminder, len(reminders))
+	for i, r := range reminders {
+		result[i] = &pb.Reminder{
+			ReminderId:    r.ID,
+			MinutesBefore: int32(r.MinutesBefore),
+		}
+		for _, channel := range r.Channels {
+			result[i].Channels = append(result[i].Channels, notificationChannelToProto[channel])
+		}
+	}
+	return result
+}
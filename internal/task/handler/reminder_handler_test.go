@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertReminders(t *testing.T) {
+	assert.Nil(t, convertRemindersFromProto(nil))
+	assert.Nil(t, convertRemindersToProto(nil))
+
+	reminders := convertRemindersFromProto([]*pb.Reminder{
+		{MinutesBefore: 60},
+		{ReminderId: "r2", MinutesBefore: 1440, Channels: []pb.NotificationChannel{
+			pb.NotificationChannel_NOTIFICATION_CHANNEL_EMAIL,
+			pb.NotificationChannel_NOTIFICATION_CHANNEL_WEBHOOK,
+		}},
+	})
+	assert.Equal(t, []model.Reminder{
+		{MinutesBefore: 60},
+		{ID: "r2", MinutesBefore: 1440, Channels: []model.NotificationChannel{model.ChannelEmail, model.ChannelWebhook}},
+	}, reminders)
+
+	unknown := convertRemindersFromProto([]*pb.Reminder{{Channels: []pb.NotificationChannel{pb.NotificationChannel_NOTIFICATION_CHANNEL_UNSPECIFIED}}})
+	assert.Error(t, model.ValidateReminders(unknown), "未知の通知先はサービスの検証で拒否される")
+
+	resp := convertRemindersToProto(reminders)
+	assert.Len(t, resp, 2)
+	assert.Equal(t, "r2", resp[1].ReminderId)
+	assert.Equal(t, int32(1440), resp[1].MinutesBefore)
+	assert.Equal(t, []pb.NotificationChannel{pb.NotificationChannel_NOTIFICATION_CHANNEL_EMAIL, pb.NotificationChannel_NOTIFICATION_CHANNEL_WEBHOOK}, resp[1].Channels)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/recurrence"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *TaskHandler) GetTaskStats(ctx context.Context, req *pb.GetTaskStatsRequest) (*pb.TaskStats, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	query, err := parseTimeRange(ctx, req.FromDate, req.ToDate, req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := h.taskService.GetTaskStats(ctx, userID, query)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTaskStatsToProto(stats), nil
+}
+
+// parseTimeRange は集計期間の最初と最後の日とタイムゾーンを解釈します
+func parseTimeRange(ctx context.Context, fromDate string, toDate string, timeZone string) (model.TaskStatsQuery, error) {
+	loc, err := recurrence.LoadLocation(timeZone)
+	if err != nil {
+		return model.TaskStatsQuery{}, convertErrorToGRPCStatus(ctx, apperrors.NewInvalidInputError("タイムゾーンが不正です", err).
+			WithViolations(&apperrors.ValidationError{Field: "time_zone", Message: err.Error()}))
+	}
+	query, err := model.NewTaskStatsQuery(fromDate, toDate, loc, time.Now())
+	if err != nil {
+		// 期間の検証のメッセージはクライアント向けのため、そのまま返す
+		return model.TaskStatsQuery{}, convertErrorToGRPCStatus(ctx, apperrors.NewInvalidInputError(err.Error(), nil).WithReason("INVALID_TIME_RANGE"))
+	}
+	return query, nil
+}
+
+func convertTaskStatsToProto(stats *model.TaskStats) *pb.TaskStats {
+	resp := &pb.TaskStats{
+		StatusCounts:           make([]*pb.StatusCount, 0, len(stats.CountsByStatus)),
+		TotalCount:             stats.Total,
+		OverdueCount:           stats.Overdue,
+		CreatedCount:           stats.Created,
+		CompletionRate:         stats.CompletionRate(),
+		CompletedCount:         stats.Completed,
+		AverageLeadTimeSeconds: int64(stats.AverageLeadTime / time.Second),
+		DailyCompletions:       make([]*pb.DailyCompletionCount, len(stats.DailyCompletions)),
+	}
+	for s, count := range stats.CountsByStatus {
+		resp.StatusCounts = append(resp.StatusCounts, &pb.StatusCount{Status: convertStatusToProto(s), Count: count})
+	}
+	sort.Slice(resp.StatusCounts, func(i, j int) bool {
+		return resp.StatusCounts[i].Status < resp.StatusCounts[j].Status
+	})
+	for i, day := range stats.DailyCompletions {
+		resp.DailyCompletions[i] = &pb.DailyCompletionCount{Date: day.Date, Count: day.Count}
+	}
+	return resp
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTaskHandler_GetTaskStats(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mockTaskService)
+		handler := NewTaskHandler(mockService)
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		if err != nil {
+			t.Skip("タイムゾーンのデータがありません")
+		}
+		mockService.On("GetTaskStats", ctx, "user1", mock.MatchedBy(func(q model.TaskStatsQuery) bool {
+			return q.Start.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, tokyo)) && q.End.Equal(time.Date(2024, 3, 3, 0, 0, 0, 0, tokyo))
+		})).Return(&model.TaskStats{
+			CountsByStatus: map[model.TaskStatus]int64{
+				model.TaskStatusComplete: 1,
+				model.TaskStatusPending:  2,
+			},
+			Total:            3,
+			Overdue:          1,
+			Created:          2,
+			CreatedCompleted: 1,
+			Completed:        1,
+			AverageLeadTime:  90 * time.Minute,
+			DailyCompletions: []model.DailyCount{{Date: "2024-03-01", Count: 0}, {Date: "2024-03-02", Count: 1}},
+		}, nil)
+
+		resp, err := handler.GetTaskStats(ctx, &pb.GetTaskStatsRequest{FromDate: "2024-03-01", ToDate: "2024-03-02", TimeZone: "Asia/Tokyo"})
+		require.NoError(t, err)
+		assert.Equal(t, []*pb.StatusCount{
+			{Status: pb.TaskStatus_TASK_STATUS_PENDING, Count: 2},
+			{Status: pb.TaskStatus_TASK_STATUS_COMPLETE, Count: 1},
+		}, resp.StatusCounts)
+		assert.Equal(t, int64(3), resp.TotalCount)
+		assert.Equal(t, 0.5, resp.CompletionRate)
+		assert.Equal(t, int64(5400), resp.AverageLeadTimeSeconds)
+		require.Len(t, resp.DailyCompletions, 2)
+		assert.Equal(t, "2024-03-02", resp.DailyCompletions[1].Date)
+	})
+
+	t.Run("invalid_request", func(t *testing.T) {
+		handler := NewTaskHandler(new(mockTaskService))
+		for _, req := range []*pb.GetTaskStatsRequest{
+			{TimeZone: "Mars/Olympus"},
+			{FromDate: "2024/03/01"},
+			{FromDate: "2024-03-02", ToDate: "2024-03-01"},
+			{FromDate: "2023-01-01", ToDate: "2024-03-01"},
+		} {
+			_, err := handler.GetTaskStats(ctx, req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), req.String())
+		}
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewTaskHandler(new(mockTaskService))
+
+		_, err := handler.GetTaskStats(context.Background(), &pb.GetTaskStatsRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
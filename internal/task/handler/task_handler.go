@@ -2,13 +2,21 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/my-backend-project/internal/pb"
 	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/pkg/logger"
 	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/recurrence"
 	"github.com/my-backend-project/internal/task/service"
 
-	"google.golang.org/grpc/codes"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -16,26 +24,30 @@ import (
 type TaskHandler struct {
 	pb.UnimplementedTaskServiceServer
 	taskService service.TaskService
+	// heartbeatInterval は WatchTasks で変更がない間に heartbeat を送る間隔です
+	heartbeatInterval time.Duration
 }
 
 func NewTaskHandler(taskService service.TaskService) *TaskHandler {
 	return &TaskHandler{
-		taskService: taskService,
+		taskService:       taskService,
+		heartbeatInterval: DefaultHeartbeatInterval,
 	}
 }
 
 func (h *TaskHandler) CreateTask(ctx context.Context, req *pb.CreateTaskRequest) (*pb.CreateTaskResponse, error) {
-	task := &model.Task{
-		UserID:      req.UserId,
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      model.TaskStatus(req.Status.String()),
-		DueDate:     req.DueDate.AsTime(),
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	task, err := convertCreateRequestToTask(req, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
 	}
 
 	createdTask, err := h.taskService.CreateTask(ctx, task)
 	if err != nil {
-		return nil, convertErrorToGRPCStatus(err)
+		return nil, convertErrorToGRPCStatus(ctx, err)
 	}
 
 	return &pb.CreateTaskResponse{
@@ -46,7 +58,7 @@ func (h *TaskHandler) CreateTask(ctx context.Context, req *pb.CreateTaskRequest)
 func (h *TaskHandler) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.GetTaskResponse, error) {
 	task, err := h.taskService.GetTask(ctx, req.TaskId)
 	if err != nil {
-		return nil, convertErrorToGRPCStatus(err)
+		return nil, convertErrorToGRPCStatus(ctx, err)
 	}
 
 	return &pb.GetTaskResponse{
@@ -55,93 +67,495 @@ func (h *TaskHandler) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.
 }
 
 func (h *TaskHandler) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
-	var taskStatus *model.TaskStatus
-	if req.Status != pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
-		status := model.TaskStatus(req.Status.String())
-		taskStatus = &status
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
 	}
-
-	tasks, total, err := h.taskService.ListTasks(ctx, req.UserId, taskStatus, req.PageSize, req.PageToken)
+	filter, err := convertListRequestToFilter(req)
 	if err != nil {
-		return nil, convertErrorToGRPCStatus(err)
+		return nil, convertErrorToGRPCStatus(ctx, err)
 	}
 
-	var nextPageToken string
-	if len(tasks) > 0 {
-		nextPageToken = tasks[len(tasks)-1].ID.Hex()
+	page, err := h.taskService.ListTasks(ctx, userID, filter, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
 	}
 
-	taskResponses := make([]*pb.Task, len(tasks))
-	for i, task := range tasks {
+	taskResponses := make([]*pb.Task, len(page.Tasks))
+	for i, task := range page.Tasks {
 		taskResponses[i] = convertTaskToProto(task)
 	}
 
 	return &pb.ListTasksResponse{
 		Tasks:         taskResponses,
-		NextPageToken: nextPageToken,
-		TotalCount:    total,
+		NextPageToken: page.NextPageToken,
+		TotalCount:    page.TotalCount,
 	}, nil
 }
 
 func (h *TaskHandler) UpdateTask(ctx context.Context, req *pb.UpdateTaskRequest) (*pb.UpdateTaskResponse, error) {
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	customFields, err := convertCustomFieldsFromProto(req.CustomFields)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	projectID, err := parseProjectID(req.ProjectId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
 	task := &model.Task{
-		UserID:      req.UserId,
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      model.TaskStatus(req.Status.String()),
-		DueDate:     req.DueDate.AsTime(),
+		UserID:         userID,
+		ProjectID:      projectID,
+		Title:          req.Title,
+		Description:    req.Description,
+		Status:         model.TaskStatus(req.Status.String()),
+		Priority:       model.TaskPriority(req.Priority),
+		Labels:         req.Labels,
+		CustomFields:   customFields,
+		Checklist:      convertChecklistFromProto(req.Checklist),
+		WorkflowStatus: req.WorkflowStatus,
+		Recurrence:     convertRecurrenceFromProto(req.Recurrence),
+		Reminders:      convertRemindersFromProto(req.Reminders),
+	}
+	if err := convertDueFromProto(task, req.DueDate, req.DueDay, req.TimeZone); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
 	}
 
 	updatedTask, err := h.taskService.UpdateTask(ctx, req.TaskId, task)
 	if err != nil {
-		return nil, convertErrorToGRPCStatus(err)
+		return nil, convertErrorToGRPCStatus(ctx, err)
 	}
 
-	return &pb.UpdateTaskResponse{
+	resp := &pb.UpdateTaskResponse{
 		Task: convertTaskToProto(updatedTask),
-	}, nil
+	}
+	if updatedTask.Status == model.TaskStatusComplete && updatedTask.Progress.HasOpenSubtasks() {
+		resp.Warnings = append(resp.Warnings, "未完了のサブタスクがあります")
+	}
+	return resp, nil
 }
 
 func (h *TaskHandler) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.Empty, error) {
 	err := h.taskService.DeleteTask(ctx, req.TaskId)
 	if err != nil {
-		return nil, convertErrorToGRPCStatus(err)
+		return nil, convertErrorToGRPCStatus(ctx, err)
 	}
 
 	return &pb.Empty{}, nil
 }
 
-func convertTaskToProto(task *model.Task) *pb.Task {
-	var status pb.TaskStatus
-	switch task.Status {
+func (h *TaskHandler) ListSubtasks(ctx context.Context, req *pb.ListSubtasksRequest) (*pb.ListSubtasksResponse, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := h.taskService.ListSubtasks(ctx, req.TaskId, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	taskResponses := make([]*pb.Task, len(tasks))
+	for i, task := range tasks {
+		taskResponses[i] = convertTaskToProto(task)
+	}
+
+	return &pb.ListSubtasksResponse{
+		Tasks: taskResponses,
+	}, nil
+}
+
+func (h *TaskHandler) MoveTask(ctx context.Context, req *pb.MoveTaskRequest) (*pb.MoveTaskResponse, error) {
+	if req.Board != nil {
+		return h.moveTaskOnBoard(ctx, req)
+	}
+
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := h.taskService.MoveTask(ctx, req.TaskId, userID, req.ParentId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return &pb.MoveTaskResponse{
+		Task: convertTaskToProto(task),
+	}, nil
+}
+
+func (h *TaskHandler) moveTaskOnBoard(ctx context.Context, req *pb.MoveTaskRequest) (*pb.MoveTaskResponse, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := h.taskService.MoveTaskOnBoard(ctx, req.TaskId, userID, model.BoardMove{
+		WorkflowStatus: req.Board.WorkflowStatus,
+		BeforeID:       req.Board.BeforeId,
+		AfterID:        req.Board.AfterId,
+	})
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return &pb.MoveTaskResponse{
+		Task: convertTaskToProto(task),
+	}, nil
+}
+
+func (h *TaskHandler) SearchTasks(ctx context.Context, req *pb.SearchTasksRequest) (*pb.SearchTasksResponse, error) {
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := h.taskService.SearchTasks(ctx, userID, req.Query, req.PageSize)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.SearchTasksResponse{Hits: make([]*pb.SearchHit, len(hits))}
+	for i, hit := range hits {
+		highlights := make([]*pb.SearchHighlight, len(hit.Highlights))
+		for j, hl := range hit.Highlights {
+			highlights[j] = &pb.SearchHighlight{Field: hl.Field, Snippet: hl.Snippet}
+		}
+		resp.Hits[i] = &pb.SearchHit{
+			Task:       convertTaskToProto(hit.Task),
+			Score:      hit.Score,
+			Highlights: highlights,
+		}
+	}
+	return resp, nil
+}
+
+func convertCreateRequestToTask(req *pb.CreateTaskRequest, userID string) (*model.Task, error) {
+	customFields, err := convertCustomFieldsFromProto(req.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentID primitive.ObjectID
+	if req.ParentId != "" {
+		parentID, err = primitive.ObjectIDFromHex(req.ParentId)
+		if err != nil {
+			return nil, apperrors.NewInvalidInputError("無効な親タスクIDです", err)
+		}
+	}
+	projectID, err := parseProjectID(req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &model.Task{
+		UserID:         userID,
+		ParentID:       parentID,
+		ProjectID:      projectID,
+		Title:          req.Title,
+		Description:    req.Description,
+		Status:         model.TaskStatus(req.Status.String()),
+		Priority:       model.TaskPriority(req.Priority),
+		Labels:         req.Labels,
+		CustomFields:   customFields,
+		Checklist:      convertChecklistFromProto(req.Checklist),
+		WorkflowStatus: req.WorkflowStatus,
+		Recurrence:     convertRecurrenceFromProto(req.Recurrence),
+		Reminders:      convertRemindersFromProto(req.Reminders),
+	}
+	if err := convertDueFromProto(task, req.DueDate, req.DueDay, req.TimeZone); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// convertDueFromProto は期限の日時または期限日をタスクに設定します。どちらも指定しない場合は期限なしにします
+func convertDueFromProto(task *model.Task, dueDate *timestamppb.Timestamp, dueDay *pb.Date, timeZone string) error {
+	if dueDate != nil && dueDay != nil {
+		return apperrors.NewInvalidInputError("期限の日時と期限日は同時に指定できません", nil)
+	}
+	task.DueDate = model.ProtoTimestampToTime(dueDate)
+	task.TimeZone = timeZone
+	if dueDay != nil {
+		task.AllDay = true
+		task.DueDay = convertDateFromProto(dueDay)
+	}
+	return nil
+}
+
+// convertDateFromProto は日付を YYYY-MM-DD にします。存在しない日付は期限をそろえるときにエラーになります
+func convertDateFromProto(date *pb.Date) string {
+	return fmt.Sprintf("%04d-%02d-%02d", date.Year, date.Month, date.Day)
+}
+
+func convertDateToProto(day string) *pb.Date {
+	if day == "" {
+		return nil
+	}
+	t, err := time.Parse(model.DueDayLayout, day)
+	if err != nil {
+		return nil
+	}
+	return &pb.Date{Year: int32(t.Year()), Month: int32(t.Month()), Day: int32(t.Day())}
+}
+
+func convertStatusToProto(status model.TaskStatus) pb.TaskStatus {
+	switch status {
 	case model.TaskStatusPending:
-		status = pb.TaskStatus_TASK_STATUS_PENDING
+		return pb.TaskStatus_TASK_STATUS_PENDING
 	case model.TaskStatusActive:
-		status = pb.TaskStatus_TASK_STATUS_ACTIVE
+		return pb.TaskStatus_TASK_STATUS_ACTIVE
 	case model.TaskStatusComplete:
-		status = pb.TaskStatus_TASK_STATUS_COMPLETE
+		return pb.TaskStatus_TASK_STATUS_COMPLETE
 	default:
-		status = pb.TaskStatus_TASK_STATUS_UNSPECIFIED
+		return pb.TaskStatus_TASK_STATUS_UNSPECIFIED
+	}
+}
+
+func convertTaskToProto(task *model.Task) *pb.Task {
+	var parentID string
+	if task.HasParent() {
+		parentID = task.ParentID.Hex()
+	}
+
+	var projectID string
+	if task.HasProject() {
+		projectID = task.ProjectID.Hex()
+	}
+
+	var completedAt *timestamppb.Timestamp
+	if !task.CompletedAt.IsZero() {
+		completedAt = timestamppb.New(task.CompletedAt)
 	}
 
 	return &pb.Task{
-		TaskId:      task.ID.Hex(),
-		UserId:      task.UserID,
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      status,
-		DueDate:     timestamppb.New(task.DueDate),
-		CreatedAt:   timestamppb.New(task.CreatedAt),
-		UpdatedAt:   timestamppb.New(task.UpdatedAt),
+		TaskId:         task.ID.Hex(),
+		UserId:         task.UserID,
+		ParentId:       parentID,
+		ProjectId:      projectID,
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         convertStatusToProto(task.Status),
+		WorkflowStatus: task.WorkflowStatus,
+		Priority:       pb.TaskPriority(task.Priority),
+		Labels:         task.Labels,
+		CustomFields:   convertCustomFieldsToProto(task.CustomFields),
+		Checklist:      convertChecklistToProto(task.Checklist),
+		Progress:       convertProgressToProto(task.Progress),
+		Recurrence:     convertRecurrenceToProto(task.Recurrence),
+		Reminders:      convertRemindersToProto(task.Reminders),
+		DueDate:        model.TimeToProtoTimestamp(task.DueDate),
+		DueDay:         convertDateToProto(task.DueDay),
+		TimeZone:       task.TimeZone,
+		CreatedAt:      timestamppb.New(task.CreatedAt),
+		UpdatedAt:      timestamppb.New(task.UpdatedAt),
+		CompletedAt:    completedAt,
+		Rank:           task.Rank,
+	}
+}
+
+func convertChecklistFromProto(items []*pb.ChecklistItem) []model.ChecklistItem {
+	if len(items) == 0 {
+		return nil
+	}
+	result := make([]model.ChecklistItem, len(items))
+	for i, item := range items {
+		result[i] = model.ChecklistItem{ID: item.ItemId, Text: item.Text, Done: item.Done}
+	}
+	return result
+}
+
+func convertChecklistToProto(items []model.ChecklistItem) []*pb.ChecklistItem {
+	result := make([]*pb.ChecklistItem, len(items))
+	for i, item := range items {
+		result[i] = &pb.ChecklistItem{ItemId: item.ID, Text: item.Text, Done: item.Done}
+	}
+	return result
+}
+
+func convertProgressToProto(progress *model.TaskProgress) *pb.TaskProgress {
+	if progress == nil {
+		return nil
+	}
+	return &pb.TaskProgress{
+		CompletedSubtasks:       progress.Subtasks.Completed,
+		TotalSubtasks:           progress.Subtasks.Total,
+		CompletedChecklistItems: progress.Checklist.Completed,
+		TotalChecklistItems:     progress.Checklist.Total,
+	}
+}
+
+func convertCustomFieldsFromProto(values []*pb.CustomFieldValue) (map[string]model.CustomFieldValue, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]model.CustomFieldValue, len(values))
+	for _, v := range values {
+		var value model.CustomFieldValue
+		switch x := v.Value.(type) {
+		case *pb.CustomFieldValue_TextValue:
+			value = model.CustomFieldValue{Type: model.CustomFieldText, Text: x.TextValue}
+		case *pb.CustomFieldValue_NumberValue:
+			number := x.NumberValue
+			value = model.CustomFieldValue{Type: model.CustomFieldNumber, Number: &number}
+		case *pb.CustomFieldValue_DateValue:
+			date := model.ProtoTimestampToTime(x.DateValue)
+			value = model.CustomFieldValue{Type: model.CustomFieldDate, Date: &date}
+		case *pb.CustomFieldValue_SelectValue:
+			value = model.CustomFieldValue{Type: model.CustomFieldSingleSelect, Option: x.SelectValue}
+		default:
+			return nil, apperrors.NewInvalidInputError("カスタムフィールドの値が指定されていません", nil)
+		}
+		if _, err := primitive.ObjectIDFromHex(v.FieldId); err != nil {
+			return nil, apperrors.NewInvalidInputError("無効なカスタムフィールドIDです", err)
+		}
+		if _, ok := result[v.FieldId]; ok {
+			return nil, apperrors.NewInvalidInputError("カスタムフィールドが重複しています", nil)
+		}
+		result[v.FieldId] = value
+	}
+	return result, nil
+}
+
+func convertCustomFieldsToProto(values map[string]model.CustomFieldValue) []*pb.CustomFieldValue {
+	ids := make([]string, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := make([]*pb.CustomFieldValue, 0, len(values))
+	for _, id := range ids {
+		v := values[id]
+		value := &pb.CustomFieldValue{FieldId: id}
+		switch v.Type {
+		case model.CustomFieldText:
+			value.Value = &pb.CustomFieldValue_TextValue{TextValue: v.Text}
+		case model.CustomFieldNumber:
+			if v.Number != nil {
+				value.Value = &pb.CustomFieldValue_NumberValue{NumberValue: *v.Number}
+			}
+		case model.CustomFieldDate:
+			if v.Date != nil {
+				value.Value = &pb.CustomFieldValue_DateValue{DateValue: timestamppb.New(*v.Date)}
+			}
+		case model.CustomFieldSingleSelect:
+			value.Value = &pb.CustomFieldValue_SelectValue{SelectValue: v.Option}
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+func convertListRequestToFilter(req *pb.ListTasksRequest) (*model.TaskFilter, error) {
+	customFields, err := convertCustomFieldsFromProto(req.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &model.TaskFilter{
+		Labels:         req.Labels,
+		CustomFields:   customFields,
+		DueDateRange:   convertTimeRange(req.DueDateRange),
+		CreatedAtRange: convertTimeRange(req.CreatedAtRange),
+		UpdatedAtRange: convertTimeRange(req.UpdatedAtRange),
+		Query:          req.Query,
+		Descending:     req.Direction == pb.SortDirection_SORT_DIRECTION_DESC,
+
+		WorkflowStatuses: req.WorkflowStatuses,
+		Inbox:            req.Inbox,
+		IncludeArchived:  req.IncludeArchived,
+	}
+	if req.TimeZone != "" {
+		if _, err := recurrence.LoadLocation(req.TimeZone); err != nil {
+			return nil, apperrors.NewInvalidInputError("タイムゾーンが不正です", err)
+		}
+		filter.TimeZone = req.TimeZone
+	}
+	if filter.ProjectID, err = parseProjectID(req.ProjectId); err != nil {
+		return nil, err
+	}
+	if !filter.ProjectID.IsZero() && filter.Inbox {
+		return nil, apperrors.NewInvalidInputError("プロジェクトとインボックスは同時に指定できません", nil)
+	}
+
+	if req.Status != pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		filter.Statuses = append(filter.Statuses, model.TaskStatus(req.Status.String()))
+	}
+	for _, s := range req.Statuses {
+		if s != pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+			filter.Statuses = append(filter.Statuses, model.TaskStatus(s.String()))
+		}
+	}
+
+	for _, p := range req.Priorities {
+		filter.Priorities = append(filter.Priorities, model.TaskPriority(p))
+	}
+
+	switch req.DueFilter {
+	case pb.DueDateFilter_DUE_DATE_FILTER_OVERDUE:
+		filter.DuePreset = model.DueDateOverdue
+	case pb.DueDateFilter_DUE_DATE_FILTER_DUE_TODAY:
+		filter.DuePreset = model.DueDateDueToday
+	case pb.DueDateFilter_DUE_DATE_FILTER_DUE_THIS_WEEK:
+		filter.DuePreset = model.DueDateDueThisWeek
+	}
+
+	switch req.OrderBy {
+	case pb.TaskSortField_TASK_SORT_FIELD_DUE_DATE:
+		filter.OrderBy = model.TaskSortByDueDate
+	case pb.TaskSortField_TASK_SORT_FIELD_UPDATED_AT:
+		filter.OrderBy = model.TaskSortByUpdatedAt
+	case pb.TaskSortField_TASK_SORT_FIELD_TITLE:
+		filter.OrderBy = model.TaskSortByTitle
+	case pb.TaskSortField_TASK_SORT_FIELD_PRIORITY:
+		filter.OrderBy = model.TaskSortByPriority
+	case pb.TaskSortField_TASK_SORT_FIELD_RANK:
+		filter.OrderBy = model.TaskSortByRank
+	default:
+		filter.OrderBy = model.TaskSortByCreatedAt
+	}
+
+	return filter, nil
+}
+
+func convertTimeRange(r *pb.TimeRange) model.TimeRange {
+	if r == nil {
+		return model.TimeRange{}
+	}
+	return model.TimeRange{
+		Start: model.ProtoTimestampToTime(r.Start),
+		End:   model.ProtoTimestampToTime(r.End),
 	}
 }
 
-func convertErrorToGRPCStatus(err error) error {
-	if apperrors.IsNotFound(err) {
-		return status.Error(codes.NotFound, err.Error())
+// convertErrorToGRPCStatus はエラーを詳細付きの gRPC のステータスにします。クライアントには AppError の Message だけを返し、
+// 内部エラーの原因と AppError でないエラーは、理由と RPC のメソッド名を付けてログに記録します
+func convertErrorToGRPCStatus(ctx context.Context, err error) error {
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
+		}
+		appErr = apperrors.NewInternalError("内部エラーが発生しました", err)
 	}
-	if apperrors.IsInvalidInput(err) {
-		return status.Error(codes.InvalidArgument, err.Error())
+	if appErr.Type == apperrors.Internal && appErr.Err != nil {
+		method, _ := grpc.Method(ctx)
+		logger.Error("internal error",
+			zap.String("method", method),
+			zap.String("reason", appErr.ReasonCode()),
+			zap.Error(err),
+		)
 	}
-	return status.Error(codes.Internal, err.Error())
+	return appErr.GRPCStatus().Err()
 }
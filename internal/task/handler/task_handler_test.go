@@ -2,16 +2,24 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/my-backend-project/internal/pb"
 	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
 	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+	"github.com/my-backend-project/internal/task/watch"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -35,12 +43,12 @@ func (m *mockTaskService) GetTask(ctx context.Context, id string) (*model.Task,
 	return args.Get(0).(*model.Task), args.Error(1)
 }
 
-func (m *mockTaskService) ListTasks(ctx context.Context, userID string, status *model.TaskStatus, limit int32, offset string) ([]*model.Task, int32, error) {
-	args := m.Called(ctx, userID, status, limit, offset)
+func (m *mockTaskService) ListTasks(ctx context.Context, userID string, filter *model.TaskFilter, pageSize int32, pageToken string) (*model.TaskPage, error) {
+	args := m.Called(ctx, userID, filter, pageSize, pageToken)
 	if args.Get(0) == nil {
-		return nil, 0, args.Error(2)
+		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*model.Task), args.Get(1).(int32), args.Error(2)
+	return args.Get(0).(*model.TaskPage), args.Error(1)
 }
 
 func (m *mockTaskService) UpdateTask(ctx context.Context, id string, task *model.Task) (*model.Task, error) {
@@ -56,12 +64,142 @@ func (m *mockTaskService) DeleteTask(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+func (m *mockTaskService) SearchTasks(ctx context.Context, userID string, query string, pageSize int32) ([]*search.Hit, error) {
+	args := m.Called(ctx, userID, query, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*search.Hit), args.Error(1)
+}
+
+func (m *mockTaskService) ListSubtasks(ctx context.Context, id string, userID string) ([]*model.Task, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Task), args.Error(1)
+}
+
+func (m *mockTaskService) MoveTask(ctx context.Context, id string, userID string, parentID string) (*model.Task, error) {
+	args := m.Called(ctx, id, userID, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Task), args.Error(1)
+}
+
+func (m *mockTaskService) AddDependency(ctx context.Context, taskID string, userID string, blockerID string) (*model.TaskDependency, error) {
+	args := m.Called(ctx, taskID, userID, blockerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TaskDependency), args.Error(1)
+}
+
+func (m *mockTaskService) RemoveDependency(ctx context.Context, taskID string, userID string, blockerID string) error {
+	args := m.Called(ctx, taskID, userID, blockerID)
+	return args.Error(0)
+}
+
+func (m *mockTaskService) ListDependencies(ctx context.Context, taskID string, userID string) (*model.TaskDependencies, error) {
+	args := m.Called(ctx, taskID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TaskDependencies), args.Error(1)
+}
+
+func (m *mockTaskService) ListNextTasks(ctx context.Context, userID string, pageSize int32) ([]*model.NextTask, error) {
+	args := m.Called(ctx, userID, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.NextTask), args.Error(1)
+}
+
+func (m *mockTaskService) TransitionTask(ctx context.Context, id string, userID string, to string, reason string) (*model.Task, *model.TaskTransition, error) {
+	args := m.Called(ctx, id, userID, to, reason)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.Task), args.Get(1).(*model.TaskTransition), args.Error(2)
+}
+
+func (m *mockTaskService) ListTaskTransitions(ctx context.Context, id string, userID string) ([]*model.TaskTransition, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.TaskTransition), args.Error(1)
+}
+
+func (m *mockTaskService) PreviewRecurrence(ctx context.Context, recurrence *model.Recurrence, after time.Time, limit int32) ([]time.Time, error) {
+	args := m.Called(ctx, recurrence, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *mockTaskService) WatchTasks(ctx context.Context, userID string, resumeToken string) (*watch.Subscription, error) {
+	args := m.Called(ctx, userID, resumeToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*watch.Subscription), args.Error(1)
+}
+
+func (m *mockTaskService) BatchCreateTasks(ctx context.Context, userID string, tasks []*model.Task, atomic bool) (*model.BatchResult, error) {
+	args := m.Called(ctx, userID, tasks, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BatchResult), args.Error(1)
+}
+
+func (m *mockTaskService) BatchUpdateTasks(ctx context.Context, userID string, selector *model.TaskSelector, patch *model.TaskPatch, atomic bool) (*model.BatchResult, error) {
+	args := m.Called(ctx, userID, selector, patch, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BatchResult), args.Error(1)
+}
+
+func (m *mockTaskService) BatchDeleteTasks(ctx context.Context, userID string, selector *model.TaskSelector, atomic bool) (*model.BatchResult, error) {
+	args := m.Called(ctx, userID, selector, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BatchResult), args.Error(1)
+}
+
+func (m *mockTaskService) ExportTasks(ctx context.Context, userID string, filter *model.TaskFilter, fn func(*model.Task) error) error {
+	args := m.Called(ctx, userID, filter, fn)
+	return args.Error(0)
+}
+
+func (m *mockTaskService) MoveTaskOnBoard(ctx context.Context, id string, userID string, move model.BoardMove) (*model.Task, error) {
+	args := m.Called(ctx, id, userID, move)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Task), args.Error(1)
+}
+
+func (m *mockTaskService) GetTaskStats(ctx context.Context, userID string, query model.TaskStatsQuery) (*model.TaskStats, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TaskStats), args.Error(1)
+}
+
 func TestTaskHandler_CreateTask(t *testing.T) {
 	mockService := new(mockTaskService)
 	handler := NewTaskHandler(mockService)
 
 	t.Run("success", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
 		dueDate := timestamppb.Now()
 		req := &pb.CreateTaskRequest{
 			UserId:      "user1",
@@ -92,7 +230,7 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 	})
 
 	t.Run("service_error", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
 		dueDate := timestamppb.Now()
 		req := &pb.CreateTaskRequest{
 			UserId:      "user1",
@@ -109,6 +247,43 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 		assert.Nil(t, resp)
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("other_user", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		_, err := handler.CreateTask(ctx, &pb.CreateTaskRequest{UserId: "user2", Title: "Test Task", DueDate: timestamppb.Now()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = handler.CreateTask(context.Background(), &pb.CreateTaskRequest{Title: "Test Task", DueDate: timestamppb.Now()})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestTaskHandler_CreateSubtask(t *testing.T) {
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	parentID := primitive.NewObjectID()
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("CreateTask", ctx, mock.MatchedBy(func(task *model.Task) bool {
+			return task.ParentID == parentID && len(task.Checklist) == 2 && task.Checklist[1].Text == "b"
+		})).Return(&model.Task{ID: primitive.NewObjectID()}, nil).Once()
+
+		_, err := handler.CreateTask(ctx, &pb.CreateTaskRequest{
+			UserId:    "user1",
+			Title:     "child",
+			DueDate:   timestamppb.Now(),
+			ParentId:  parentID.Hex(),
+			Checklist: []*pb.ChecklistItem{{Text: "a"}, {Text: "b"}},
+		})
+		assert.NoError(t, err)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid_parent_id", func(t *testing.T) {
+		_, err := handler.CreateTask(ctx, &pb.CreateTaskRequest{UserId: "user1", Title: "child", ParentId: "invalid"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
 }
 
 func TestTaskHandler_GetTask(t *testing.T) {
@@ -163,7 +338,7 @@ func TestTaskHandler_ListTasks(t *testing.T) {
 	handler := NewTaskHandler(mockService)
 
 	t.Run("success", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
 		req := &pb.ListTasksRequest{
 			UserId:    "user1",
 			Status:    pb.TaskStatus_TASK_STATUS_PENDING,
@@ -194,18 +369,23 @@ func TestTaskHandler_ListTasks(t *testing.T) {
 			},
 		}
 
-		mockService.On("ListTasks", ctx, "user1", mock.AnythingOfType("*model.TaskStatus"), int32(10), "").Return(expectedTasks, int32(2), nil).Once()
+		mockService.On("ListTasks", ctx, "user1", mock.AnythingOfType("*model.TaskFilter"), int32(10), "").Return(&model.TaskPage{
+			Tasks:         expectedTasks,
+			TotalCount:    int32(5),
+			NextPageToken: "next-token",
+		}, nil).Once()
 
 		resp, err := handler.ListTasks(ctx, req)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 		assert.Len(t, resp.Tasks, 2)
-		assert.Equal(t, int32(2), resp.TotalCount)
+		assert.Equal(t, int32(5), resp.TotalCount)
+		assert.Equal(t, "next-token", resp.NextPageToken)
 		mockService.AssertExpectations(t)
 	})
 
 	t.Run("service_error", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
 		req := &pb.ListTasksRequest{
 			UserId:    "user1",
 			Status:    pb.TaskStatus_TASK_STATUS_PENDING,
@@ -213,13 +393,190 @@ func TestTaskHandler_ListTasks(t *testing.T) {
 			PageToken: "",
 		}
 
-		mockService.On("ListTasks", ctx, "user1", mock.AnythingOfType("*model.TaskStatus"), int32(10), "").Return(nil, int32(0), apperrors.NewInternalError("service error", nil)).Once()
+		mockService.On("ListTasks", ctx, "user1", mock.AnythingOfType("*model.TaskFilter"), int32(10), "").Return(nil, apperrors.NewInternalError("service error", nil)).Once()
 
 		resp, err := handler.ListTasks(ctx, req)
 		assert.Error(t, err)
 		assert.Nil(t, resp)
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("other_user", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		_, err := handler.ListTasks(ctx, &pb.ListTasksRequest{UserId: "user2"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = handler.ListTasks(context.Background(), &pb.ListTasksRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("defaults_to_caller", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		mockService.On("ListTasks", ctx, "user1", mock.AnythingOfType("*model.TaskFilter"), int32(0), "").Return(&model.TaskPage{}, nil).Once()
+
+		_, err := handler.ListTasks(ctx, &pb.ListTasksRequest{})
+		assert.NoError(t, err)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestConvertListRequestToFilter(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := &pb.ListTasksRequest{
+		Status:         pb.TaskStatus_TASK_STATUS_PENDING,
+		Statuses:       []pb.TaskStatus{pb.TaskStatus_TASK_STATUS_ACTIVE},
+		DueFilter:      pb.DueDateFilter_DUE_DATE_FILTER_OVERDUE,
+		CreatedAtRange: &pb.TimeRange{Start: timestamppb.New(start)},
+		Query:          "report",
+		OrderBy:        pb.TaskSortField_TASK_SORT_FIELD_TITLE,
+		Direction:      pb.SortDirection_SORT_DIRECTION_DESC,
+	}
+
+	filter, err := convertListRequestToFilter(req)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TaskStatus{model.TaskStatusPending, model.TaskStatusActive}, filter.Statuses)
+	assert.Equal(t, model.DueDateOverdue, filter.DuePreset)
+	assert.Equal(t, start, filter.CreatedAtRange.Start)
+	assert.True(t, filter.CreatedAtRange.End.IsZero())
+	assert.True(t, filter.DueDateRange.IsZero())
+	assert.Equal(t, "report", filter.Query)
+	assert.Equal(t, model.TaskSortByTitle, filter.OrderBy)
+	assert.True(t, filter.Descending)
+
+	defaults, err := convertListRequestToFilter(&pb.ListTasksRequest{})
+	assert.NoError(t, err)
+	assert.Empty(t, defaults.Statuses)
+	assert.Equal(t, model.TaskSortByCreatedAt, defaults.OrderBy)
+	assert.False(t, defaults.Descending)
+}
+
+func TestConvertListRequestToFilter_PriorityLabelsAndCustomFields(t *testing.T) {
+	fieldID := primitive.NewObjectID().Hex()
+	req := &pb.ListTasksRequest{
+		Priorities: []pb.TaskPriority{pb.TaskPriority_TASK_PRIORITY_HIGH, pb.TaskPriority_TASK_PRIORITY_URGENT},
+		Labels:     []string{"backend"},
+		CustomFields: []*pb.CustomFieldValue{
+			{FieldId: fieldID, Value: &pb.CustomFieldValue_SelectValue{SelectValue: "P1"}},
+		},
+	}
+
+	filter, err := convertListRequestToFilter(req)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TaskPriority{model.TaskPriorityHigh, model.TaskPriorityUrgent}, filter.Priorities)
+	assert.Equal(t, []string{"backend"}, filter.Labels)
+	assert.Equal(t, model.CustomFieldValue{Type: model.CustomFieldSingleSelect, Option: "P1"}, filter.CustomFields[fieldID])
+
+	_, err = convertListRequestToFilter(&pb.ListTasksRequest{
+		CustomFields: []*pb.CustomFieldValue{{FieldId: fieldID}},
+	})
+	assert.True(t, apperrors.IsInvalidInput(err))
+}
+
+func TestConvertListRequestToFilter_Project(t *testing.T) {
+	projectID := primitive.NewObjectID()
+
+	filter, err := convertListRequestToFilter(&pb.ListTasksRequest{ProjectId: projectID.Hex()})
+	assert.NoError(t, err)
+	assert.Equal(t, projectID, filter.ProjectID)
+	assert.False(t, filter.HidesArchivedProjects())
+
+	_, err = convertListRequestToFilter(&pb.ListTasksRequest{ProjectId: projectID.Hex(), Inbox: true})
+	assert.True(t, apperrors.IsInvalidInput(err))
+
+	_, err = convertListRequestToFilter(&pb.ListTasksRequest{ProjectId: "invalid"})
+	assert.True(t, apperrors.IsInvalidInput(err))
+}
+
+func TestConvertListRequestToFilter_TimeZone(t *testing.T) {
+	filter, err := convertListRequestToFilter(&pb.ListTasksRequest{
+		DueFilter: pb.DueDateFilter_DUE_DATE_FILTER_DUE_TODAY,
+		TimeZone:  "America/New_York",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "America/New_York", filter.TimeZone)
+
+	_, err = convertListRequestToFilter(&pb.ListTasksRequest{TimeZone: "Nowhere/City"})
+	assert.True(t, apperrors.IsInvalidInput(err))
+}
+
+func TestConvertCreateRequestToTask_Due(t *testing.T) {
+	t.Run("missing_due_is_zero", func(t *testing.T) {
+		task, err := convertCreateRequestToTask(&pb.CreateTaskRequest{Title: "no due"}, "user1")
+		assert.NoError(t, err)
+		assert.True(t, task.DueDate.IsZero(), "nil の期限を 1970-01-01 にしない")
+		assert.False(t, task.AllDay)
+	})
+
+	t.Run("date_only", func(t *testing.T) {
+		task, err := convertCreateRequestToTask(&pb.CreateTaskRequest{
+			DueDay:   &pb.Date{Year: 2024, Month: 3, Day: 10},
+			TimeZone: "America/New_York",
+		}, "user1")
+		assert.NoError(t, err)
+		assert.True(t, task.AllDay)
+		assert.Equal(t, "2024-03-10", task.DueDay)
+		assert.Equal(t, "America/New_York", task.TimeZone)
+	})
+
+	t.Run("date_and_datetime", func(t *testing.T) {
+		_, err := convertCreateRequestToTask(&pb.CreateTaskRequest{
+			DueDate: timestamppb.Now(),
+			DueDay:  &pb.Date{Year: 2024, Month: 3, Day: 10},
+		}, "user1")
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+}
+
+func TestConvertTaskToProto_Due(t *testing.T) {
+	allDay := convertTaskToProto(&model.Task{
+		AllDay:   true,
+		DueDay:   "2024-03-10",
+		TimeZone: "Asia/Tokyo",
+		DueDate:  time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC),
+	})
+	assert.Equal(t, &pb.Date{Year: 2024, Month: 3, Day: 10}, allDay.DueDay)
+	assert.Equal(t, "Asia/Tokyo", allDay.TimeZone)
+	assert.True(t, allDay.DueDate.AsTime().Equal(time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC)))
+
+	timed := convertTaskToProto(&model.Task{})
+	assert.Nil(t, timed.DueDay)
+	assert.Nil(t, timed.DueDate)
+}
+
+func TestConvertCustomFields_RoundTrip(t *testing.T) {
+	textID := primitive.NewObjectID().Hex()
+	numberID := primitive.NewObjectID().Hex()
+	dateID := primitive.NewObjectID().Hex()
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	values := []*pb.CustomFieldValue{
+		{FieldId: textID, Value: &pb.CustomFieldValue_TextValue{TextValue: "memo"}},
+		{FieldId: numberID, Value: &pb.CustomFieldValue_NumberValue{NumberValue: 0}},
+		{FieldId: dateID, Value: &pb.CustomFieldValue_DateValue{DateValue: timestamppb.New(date)}},
+	}
+
+	converted, err := convertCustomFieldsFromProto(values)
+	assert.NoError(t, err)
+	assert.Equal(t, "memo", converted[textID].Text)
+	assert.Equal(t, 0.0, *converted[numberID].Number)
+	assert.Equal(t, date, *converted[dateID].Date)
+
+	back := convertCustomFieldsToProto(converted)
+	assert.Len(t, back, 3)
+	for _, v := range back {
+		switch v.FieldId {
+		case textID:
+			assert.Equal(t, "memo", v.GetTextValue())
+		case numberID:
+			assert.Equal(t, 0.0, v.GetNumberValue())
+		case dateID:
+			assert.Equal(t, date, v.GetDateValue().AsTime())
+		}
+	}
+
+	_, err = convertCustomFieldsFromProto([]*pb.CustomFieldValue{
+		{FieldId: "invalid", Value: &pb.CustomFieldValue_TextValue{TextValue: "memo"}},
+	})
+	assert.True(t, apperrors.IsInvalidInput(err))
 }
 
 func TestTaskHandler_UpdateTask(t *testing.T) {
@@ -227,7 +584,7 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 	handler := NewTaskHandler(mockService)
 
 	t.Run("success", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
 		taskID := primitive.NewObjectID()
 		dueDate := timestamppb.Now()
 		req := &pb.UpdateTaskRequest{
@@ -260,7 +617,7 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		ctx := context.Background()
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
 		taskID := primitive.NewObjectID()
 		dueDate := timestamppb.Now()
 		req := &pb.UpdateTaskRequest{
@@ -279,6 +636,151 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 		assert.Nil(t, resp)
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("completed_with_open_subtasks", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		taskID := primitive.NewObjectID()
+		req := &pb.UpdateTaskRequest{
+			TaskId:    taskID.Hex(),
+			UserId:    "user1",
+			Title:     "Parent",
+			Status:    pb.TaskStatus_TASK_STATUS_COMPLETE,
+			DueDate:   timestamppb.Now(),
+			Checklist: []*pb.ChecklistItem{{Text: "step", Done: true}},
+		}
+		expectedTask := &model.Task{
+			ID:        taskID,
+			UserID:    "user1",
+			Title:     "Parent",
+			Status:    model.TaskStatusComplete,
+			Checklist: []model.ChecklistItem{{ID: "item1", Text: "step", Done: true}},
+			Progress: &model.TaskProgress{
+				Subtasks:  model.SubtaskCount{Total: 3, Completed: 1},
+				Checklist: model.SubtaskCount{Total: 1, Completed: 1},
+			},
+		}
+
+		mockService.On("UpdateTask", ctx, taskID.Hex(), mock.MatchedBy(func(task *model.Task) bool {
+			return len(task.Checklist) == 1 && task.Checklist[0].Done
+		})).Return(expectedTask, nil).Once()
+
+		resp, err := handler.UpdateTask(ctx, req)
+		assert.NoError(t, err)
+		assert.Len(t, resp.Warnings, 1)
+		assert.Equal(t, int32(3), resp.Task.Progress.TotalSubtasks)
+		assert.Equal(t, int32(1), resp.Task.Progress.CompletedSubtasks)
+		assert.Equal(t, "item1", resp.Task.Checklist[0].ItemId)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("blocked_by_open_subtasks", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		taskID := primitive.NewObjectID()
+		req := &pb.UpdateTaskRequest{TaskId: taskID.Hex(), UserId: "user1", Title: "Parent", Status: pb.TaskStatus_TASK_STATUS_COMPLETE}
+
+		mockService.On("UpdateTask", ctx, taskID.Hex(), mock.AnythingOfType("*model.Task")).
+			Return(nil, apperrors.NewFailedPreconditionError("未完了のサブタスクがあります", nil)).Once()
+
+		_, err := handler.UpdateTask(ctx, req)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("other_user", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		taskID := primitive.NewObjectID()
+		_, err := handler.UpdateTask(ctx, &pb.UpdateTaskRequest{TaskId: taskID.Hex(), UserId: "user2", Title: "Updated Task"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockService.AssertNotCalled(t, "UpdateTask", mock.Anything, taskID.Hex(), mock.Anything)
+	})
+
+	t.Run("uses_caller", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		taskID := primitive.NewObjectID()
+		mockService.On("UpdateTask", ctx, taskID.Hex(), mock.MatchedBy(func(task *model.Task) bool {
+			return task.UserID == "user1"
+		})).Return(&model.Task{ID: taskID, UserID: "user1"}, nil).Once()
+
+		_, err := handler.UpdateTask(ctx, &pb.UpdateTaskRequest{TaskId: taskID.Hex(), Title: "Updated Task"})
+		assert.NoError(t, err)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestTaskHandler_MoveTask(t *testing.T) {
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	taskID := primitive.NewObjectID()
+	parentID := primitive.NewObjectID()
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("MoveTask", ctx, taskID.Hex(), "user1", parentID.Hex()).
+			Return(&model.Task{ID: taskID, ParentID: parentID}, nil).Once()
+
+		resp, err := handler.MoveTask(ctx, &pb.MoveTaskRequest{TaskId: taskID.Hex(), ParentId: parentID.Hex()})
+		assert.NoError(t, err)
+		assert.Equal(t, parentID.Hex(), resp.Task.ParentId)
+	})
+
+	t.Run("to_root", func(t *testing.T) {
+		mockService.On("MoveTask", ctx, taskID.Hex(), "user1", "").Return(&model.Task{ID: taskID}, nil).Once()
+
+		resp, err := handler.MoveTask(ctx, &pb.MoveTaskRequest{TaskId: taskID.Hex()})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Task.ParentId)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		mockService.On("MoveTask", ctx, parentID.Hex(), "user1", taskID.Hex()).
+			Return(nil, apperrors.NewInvalidInputError("循環します", nil)).Once()
+
+		_, err := handler.MoveTask(ctx, &pb.MoveTaskRequest{TaskId: parentID.Hex(), ParentId: taskID.Hex()})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := handler.MoveTask(context.Background(), &pb.MoveTaskRequest{TaskId: taskID.Hex(), ParentId: parentID.Hex()})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("board", func(t *testing.T) {
+		afterID := primitive.NewObjectID()
+		move := model.BoardMove{WorkflowStatus: "review", AfterID: afterID.Hex()}
+		mockService.On("MoveTaskOnBoard", ctx, taskID.Hex(), "user1", move).
+			Return(&model.Task{ID: taskID, WorkflowStatus: "review", Rank: "V"}, nil).Once()
+
+		resp, err := handler.MoveTask(ctx, &pb.MoveTaskRequest{
+			TaskId: taskID.Hex(),
+			Board:  &pb.BoardPosition{WorkflowStatus: "review", AfterId: afterID.Hex()},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "V", resp.Task.Rank)
+
+		_, err = handler.MoveTask(context.Background(), &pb.MoveTaskRequest{TaskId: taskID.Hex(), Board: &pb.BoardPosition{}})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+	mockService.AssertExpectations(t)
+}
+
+func TestTaskHandler_ListSubtasks(t *testing.T) {
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	parentID := primitive.NewObjectID()
+	children := []*model.Task{
+		{ID: primitive.NewObjectID(), ParentID: parentID, Title: "a"},
+		{ID: primitive.NewObjectID(), ParentID: parentID, Title: "b"},
+	}
+	mockService.On("ListSubtasks", ctx, parentID.Hex(), "user1").Return(children, nil).Once()
+
+	resp, err := handler.ListSubtasks(ctx, &pb.ListSubtasksRequest{TaskId: parentID.Hex()})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Tasks, 2)
+	assert.Equal(t, parentID.Hex(), resp.Tasks[0].ParentId)
+
+	_, err = handler.ListSubtasks(context.Background(), &pb.ListSubtasksRequest{TaskId: parentID.Hex()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	mockService.AssertExpectations(t)
 }
 
 func TestTaskHandler_DeleteTask(t *testing.T) {
@@ -316,3 +818,79 @@ func TestTaskHandler_DeleteTask(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestTaskHandler_SearchTasks(t *testing.T) {
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+
+	t.Run("success", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		req := &pb.SearchTasksRequest{UserId: "user1", Query: "report", PageSize: 5}
+		task := &model.Task{ID: primitive.NewObjectID(), UserID: "user1", Title: "Weekly report", Status: model.TaskStatusPending}
+		hits := []*search.Hit{{
+			Task:       task,
+			Score:      1.5,
+			Highlights: []search.Highlight{{Field: search.FieldTitle, Snippet: "Weekly <em>report</em>"}},
+		}}
+
+		mockService.On("SearchTasks", ctx, "user1", "report", int32(5)).Return(hits, nil).Once()
+
+		resp, err := handler.SearchTasks(ctx, req)
+		assert.NoError(t, err)
+		assert.Len(t, resp.Hits, 1)
+		assert.Equal(t, task.ID.Hex(), resp.Hits[0].Task.TaskId)
+		assert.Equal(t, 1.5, resp.Hits[0].Score)
+		assert.Equal(t, "title", resp.Hits[0].Highlights[0].Field)
+		assert.Equal(t, "Weekly <em>report</em>", resp.Hits[0].Highlights[0].Snippet)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid_query", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		req := &pb.SearchTasksRequest{Query: "  "}
+
+		mockService.On("SearchTasks", ctx, "user1", "  ", int32(0)).Return(nil, apperrors.NewInvalidInputError("検索語は必須です", nil)).Once()
+
+		resp, err := handler.SearchTasks(ctx, req)
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("other_user", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+		_, err := handler.SearchTasks(ctx, &pb.SearchTasksRequest{UserId: "user2", Query: "report"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockService.AssertNotCalled(t, "SearchTasks", mock.Anything, "user2", "report", int32(0))
+	})
+}
+
+func TestConvertErrorToGRPCStatus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hides_internal_causes", func(t *testing.T) {
+		err := convertErrorToGRPCStatus(ctx, fmt.Errorf("find: %w", errors.New("server selection error: context deadline exceeded")))
+		st := status.Convert(err)
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "内部エラーが発生しました", st.Message())
+
+		err = convertErrorToGRPCStatus(ctx, apperrors.NewNotFoundError("タスクが見つかりません", errors.New("mongo: no documents in result")))
+		assert.Equal(t, "タスクが見つかりません", status.Convert(err).Message())
+	})
+
+	t.Run("details", func(t *testing.T) {
+		err := convertErrorToGRPCStatus(ctx, fmt.Errorf("move: %w", repository.ErrRankConflict))
+		appErr := apperrors.FromStatus(err)
+		assert.Equal(t, codes.AlreadyExists, appErr.GRPCCode())
+		assert.Equal(t, "RANK_CONFLICT", appErr.Reason)
+		assert.True(t, apperrors.IsRetryable(appErr))
+	})
+
+	t.Run("codes", func(t *testing.T) {
+		assert.Equal(t, codes.Unauthenticated, status.Code(convertErrorToGRPCStatus(ctx, apperrors.NewUnauthorizedError("認証が必要です", nil))))
+		assert.Equal(t, codes.Canceled, status.Code(convertErrorToGRPCStatus(ctx, context.Canceled)))
+		assert.Equal(t, codes.Unavailable, status.Code(convertErrorToGRPCStatus(ctx, status.Error(codes.Unavailable, "unavailable"))))
+	})
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type TemplateHandler struct {
+	pb.UnimplementedTemplateServiceServer
+	templateService service.TemplateService
+}
+
+func NewTemplateHandler(templateService service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+func (h *TemplateHandler) CreateTemplate(ctx context.Context, req *pb.CreateTemplateRequest) (*pb.TaskTemplate, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	if req.Template == nil {
+		return nil, status.Error(codes.InvalidArgument, "template is required")
+	}
+
+	template := convertTemplateFromProto(req.Template)
+	template.UserID = userID
+	created, err := h.templateService.CreateTemplate(ctx, template)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTemplateToProto(created), nil
+}
+
+func (h *TemplateHandler) GetTemplate(ctx context.Context, req *pb.GetTemplateRequest) (*pb.TaskTemplate, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	template, err := h.templateService.GetTemplate(ctx, userID, req.TemplateId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTemplateToProto(template), nil
+}
+
+func (h *TemplateHandler) ListTemplates(ctx context.Context, req *pb.ListTemplatesRequest) (*pb.ListTemplatesResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	templates, err := h.templateService.ListTemplates(ctx, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.ListTemplatesResponse{Templates: make([]*pb.TaskTemplate, len(templates))}
+	for i, template := range templates {
+		resp.Templates[i] = convertTemplateToProto(template)
+	}
+	return resp, nil
+}
+
+func (h *TemplateHandler) UpdateTemplate(ctx context.Context, req *pb.UpdateTemplateRequest) (*pb.TaskTemplate, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	if req.Template == nil {
+		return nil, status.Error(codes.InvalidArgument, "template is required")
+	}
+	id, err := primitive.ObjectIDFromHex(req.Template.TemplateId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, apperrors.NewInvalidInputError("無効なテンプレートIDです", err))
+	}
+
+	template := convertTemplateFromProto(req.Template)
+	template.ID = id
+	template.UserID = userID
+	updated, err := h.templateService.UpdateTemplate(ctx, template)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTemplateToProto(updated), nil
+}
+
+func (h *TemplateHandler) DeleteTemplate(ctx context.Context, req *pb.DeleteTemplateRequest) (*pb.Empty, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	if err := h.templateService.DeleteTemplate(ctx, userID, req.TemplateId); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (h *TemplateHandler) InstantiateTemplate(ctx context.Context, req *pb.InstantiateTemplateRequest) (*pb.InstantiateTemplateResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	base := time.Now()
+	if req.BaseDate != nil {
+		base = req.BaseDate.AsTime()
+	}
+
+	task, subtasks, err := h.templateService.InstantiateTemplate(ctx, userID, req.TemplateId, req.Variables, base)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.InstantiateTemplateResponse{
+		Task:     convertTaskToProto(task),
+		Subtasks: make([]*pb.Task, len(subtasks)),
+	}
+	for i, subtask := range subtasks {
+		resp.Subtasks[i] = convertTaskToProto(subtask)
+	}
+	return resp, nil
+}
+
+func convertTemplateFromProto(template *pb.TaskTemplate) *model.TaskTemplate {
+	var status model.TaskStatus
+	if template.Status != pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		status = model.TaskStatus(template.Status.String())
+	}
+
+	subtasks := make([]model.TemplateSubtask, len(template.Subtasks))
+	for i, subtask := range template.Subtasks {
+		subtasks[i] = model.TemplateSubtask{
+			Title:         subtask.Title,
+			Description:   subtask.Description,
+			Priority:      model.TaskPriority(subtask.Priority),
+			Checklist:     subtask.Checklist,
+			DueOffsetDays: int(subtask.DueOffsetDays),
+		}
+	}
+
+	return &model.TaskTemplate{
+		Name:          template.Name,
+		Title:         template.Title,
+		Description:   template.Description,
+		Status:        status,
+		Priority:      model.TaskPriority(template.Priority),
+		Labels:        template.Labels,
+		Checklist:     template.Checklist,
+		DueOffsetDays: int(template.DueOffsetDays),
+		Subtasks:      subtasks,
+	}
+}
+
+func convertTemplateToProto(template *model.TaskTemplate) *pb.TaskTemplate {
+	subtasks := make([]*pb.TemplateSubtask, len(template.Subtasks))
+	for i, subtask := range template.Subtasks {
+		subtasks[i] = &pb.TemplateSubtask{
+			Title:         subtask.Title,
+			Description:   subtask.Description,
+			Priority:      pb.TaskPriority(subtask.Priority),
+			Checklist:     subtask.Checklist,
+			DueOffsetDays: int32(subtask.DueOffsetDays),
+		}
+	}
+
+	return &pb.TaskTemplate{
+		TemplateId:    template.ID.Hex(),
+		Name:          template.Name,
+		Title:         template.Title,
+		Description:   template.Description,
+		Status:        convertStatusToProto(template.Status),
+		Priority:      pb.TaskPriority(template.Priority),
+		Labels:        template.Labels,
+		Checklist:     template.Checklist,
+		DueOffsetDays: int32(template.DueOffsetDays),
+		Subtasks:      subtasks,
+		Placeholders:  template.Placeholders(),
+		CreatedAt:     timestamppb.New(template.CreatedAt),
+		UpdatedAt:     timestamppb.New(template.UpdatedAt),
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type mockTemplateService struct {
+	mock.Mock
+}
+
+func (m *mockTemplateService) template(args mock.Arguments) (*model.TaskTemplate, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TaskTemplate), args.Error(1)
+}
+
+func (m *mockTemplateService) CreateTemplate(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error) {
+	return m.template(m.Called(ctx, template))
+}
+
+func (m *mockTemplateService) GetTemplate(ctx context.Context, userID string, id string) (*model.TaskTemplate, error) {
+	return m.template(m.Called(ctx, userID, id))
+}
+
+func (m *mockTemplateService) ListTemplates(ctx context.Context, userID string) ([]*model.TaskTemplate, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.TaskTemplate), args.Error(1)
+}
+
+func (m *mockTemplateService) UpdateTemplate(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error) {
+	return m.template(m.Called(ctx, template))
+}
+
+func (m *mockTemplateService) DeleteTemplate(ctx context.Context, userID string, id string) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *mockTemplateService) InstantiateTemplate(ctx context.Context, userID string, id string, variables map[string]string, base time.Time) (*model.Task, []*model.Task, error) {
+	args := m.Called(ctx, userID, id, variables, base)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.Task), args.Get(1).([]*model.Task), args.Error(2)
+}
+
+func TestTemplateHandler_CreateTemplate(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mockTemplateService)
+		handler := NewTemplateHandler(mockService)
+		mockService.On("CreateTemplate", ctx, mock.MatchedBy(func(tmpl *model.TaskTemplate) bool {
+			return tmpl.UserID == "user1" && tmpl.Status == "" && len(tmpl.Subtasks) == 1 && tmpl.Subtasks[0].DueOffsetDays == 2
+		})).Return(&model.TaskTemplate{ID: primitive.NewObjectID(), UserID: "user1", Name: "onboarding", Title: "{{name}} さん"}, nil)
+
+		resp, err := handler.CreateTemplate(ctx, &pb.CreateTemplateRequest{Template: &pb.TaskTemplate{
+			Name:     "onboarding",
+			Title:    "{{name}} さん",
+			Subtasks: []*pb.TemplateSubtask{{Title: "面談", DueOffsetDays: 2}},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"name"}, resp.Placeholders)
+		assert.Equal(t, pb.TaskStatus_TASK_STATUS_UNSPECIFIED, resp.Status)
+	})
+
+	t.Run("missing_template", func(t *testing.T) {
+		handler := NewTemplateHandler(new(mockTemplateService))
+
+		_, err := handler.CreateTemplate(ctx, &pb.CreateTemplateRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestTemplateHandler_InstantiateTemplate(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	base := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	templateID := primitive.NewObjectID().Hex()
+	variables := map[string]string{"name": "佐藤"}
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mockTemplateService)
+		handler := NewTemplateHandler(mockService)
+		parent := &model.Task{ID: primitive.NewObjectID(), Title: "佐藤 さん", DueDate: base}
+		child := &model.Task{ID: primitive.NewObjectID(), ParentID: parent.ID, Title: "面談", DueDate: base}
+		mockService.On("InstantiateTemplate", ctx, "user1", templateID, variables, base).
+			Return(parent, []*model.Task{child}, nil)
+
+		resp, err := handler.InstantiateTemplate(ctx, &pb.InstantiateTemplateRequest{
+			TemplateId: templateID,
+			Variables:  variables,
+			BaseDate:   timestamppb.New(base),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, parent.ID.Hex(), resp.Task.TaskId)
+		require.Len(t, resp.Subtasks, 1)
+		assert.Equal(t, parent.ID.Hex(), resp.Subtasks[0].ParentId)
+	})
+
+	t.Run("missing_variables", func(t *testing.T) {
+		mockService := new(mockTemplateService)
+		handler := NewTemplateHandler(mockService)
+		mockService.On("InstantiateTemplate", ctx, "user1", templateID, map[string]string(nil), base).
+			Return(nil, nil, apperrors.NewInvalidInputError("テンプレートの変数が不足しています", nil))
+
+		_, err := handler.InstantiateTemplate(ctx, &pb.InstantiateTemplateRequest{TemplateId: templateID, BaseDate: timestamppb.New(base)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/exporter"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type TimeTrackingHandler struct {
+	pb.UnimplementedTimeTrackingServiceServer
+	timeService service.TimeTrackingService
+}
+
+func NewTimeTrackingHandler(timeService service.TimeTrackingService) *TimeTrackingHandler {
+	return &TimeTrackingHandler{
+		timeService: timeService,
+	}
+}
+
+func (h *TimeTrackingHandler) StartTimer(ctx context.Context, req *pb.StartTimerRequest) (*pb.TimeEntry, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	entry, err := h.timeService.StartTimer(ctx, userID, req.TaskId, req.Note)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTimeEntryToProto(entry), nil
+}
+
+func (h *TimeTrackingHandler) StopTimer(ctx context.Context, req *pb.StopTimerRequest) (*pb.TimeEntry, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	entry, err := h.timeService.StopTimer(ctx, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTimeEntryToProto(entry), nil
+}
+
+func (h *TimeTrackingHandler) GetRunningTimer(ctx context.Context, req *pb.GetRunningTimerRequest) (*pb.TimeEntry, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	entry, err := h.timeService.GetRunningTimer(ctx, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTimeEntryToProto(entry), nil
+}
+
+func (h *TimeTrackingHandler) AddTimeEntry(ctx context.Context, req *pb.AddTimeEntryRequest) (*pb.TimeEntry, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	taskID, err := primitive.ObjectIDFromHex(req.TaskId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, apperrors.NewInvalidInputError("無効なタスクIDです", err))
+	}
+
+	entry, err := h.timeService.AddTimeEntry(ctx, &model.TimeEntry{
+		UserID: userID,
+		TaskID: taskID,
+		Start:  convertOptionalTimestamp(req.Start),
+		End:    convertOptionalTimestamp(req.End),
+		Note:   req.Note,
+	})
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTimeEntryToProto(entry), nil
+}
+
+func (h *TimeTrackingHandler) UpdateTimeEntry(ctx context.Context, req *pb.UpdateTimeEntryRequest) (*pb.TimeEntry, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	entry, err := h.timeService.UpdateTimeEntry(ctx, userID, req.EntryId, convertOptionalTimestamp(req.Start), convertOptionalTimestamp(req.End), req.Note)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTimeEntryToProto(entry), nil
+}
+
+func (h *TimeTrackingHandler) DeleteTimeEntry(ctx context.Context, req *pb.DeleteTimeEntryRequest) (*pb.Empty, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	if err := h.timeService.DeleteTimeEntry(ctx, userID, req.EntryId); err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (h *TimeTrackingHandler) ListTimeEntries(ctx context.Context, req *pb.ListTimeEntriesRequest) (*pb.ListTimeEntriesResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	entries, total, err := h.timeService.ListTimeEntries(ctx, userID, req.TaskId)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.ListTimeEntriesResponse{
+		Entries:      make([]*pb.TimeEntry, len(entries)),
+		TotalSeconds: int64(total / time.Second),
+	}
+	for i, entry := range entries {
+		resp.Entries[i] = convertTimeEntryToProto(entry)
+	}
+	return resp, nil
+}
+
+func (h *TimeTrackingHandler) GetTimeReport(ctx context.Context, req *pb.GetTimeReportRequest) (*pb.TimeReport, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	query, err := parseTimeRange(ctx, req.FromDate, req.ToDate, req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := h.timeService.GetTimeReport(ctx, userID, query)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+	return convertTimeReportToProto(report), nil
+}
+
+func (h *TimeTrackingHandler) ExportTimesheet(req *pb.ExportTimesheetRequest, stream pb.TimeTrackingService_ExportTimesheetServer) error {
+	userID, ok := interceptor.UserIDFromContext(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	format, ok := exportFormats[req.Format]
+	if !ok || format == exporter.FormatICalendar {
+		return status.Error(codes.InvalidArgument, "timesheet format must be JSONL or CSV")
+	}
+	query, err := parseTimeRange(stream.Context(), req.FromDate, req.ToDate, req.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Send(&pb.ExportTasksResponse{
+		Data: &pb.ExportTasksResponse_Metadata{Metadata: &pb.ExportMetadata{
+			ContentType:   format.ContentType(),
+			FileExtension: format.Extension(),
+		}},
+	}); err != nil {
+		return err
+	}
+
+	chunks := &exportChunkWriter{stream: stream}
+	out := bufio.NewWriterSize(chunks, model.AttachmentChunkSize)
+	encoder, err := exporter.NewTimesheetEncoder(out, format, query.Location)
+	if err != nil {
+		return status.Error(codes.Internal, "failed to create encoder")
+	}
+	if err := h.timeService.ExportTimesheet(stream.Context(), userID, query, encoder.Encode); err != nil {
+		if chunks.err != nil {
+			return chunks.err
+		}
+		return convertErrorToGRPCStatus(stream.Context(), err)
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// convertOptionalTimestamp は未指定の日時をゼロ値にします
+func convertOptionalTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func convertTimeEntryToProto(entry *model.TimeEntry) *pb.TimeEntry {
+	var end *timestamppb.Timestamp
+	if !entry.End.IsZero() {
+		end = timestamppb.New(entry.End)
+	}
+
+	return &pb.TimeEntry{
+		EntryId:         entry.ID.Hex(),
+		TaskId:          entry.TaskID.Hex(),
+		Start:           timestamppb.New(entry.Start),
+		End:             end,
+		Running:         entry.Running,
+		DurationSeconds: int64(entry.Duration() / time.Second),
+		Note:            entry.Note,
+		CreatedAt:       timestamppb.New(entry.CreatedAt),
+		UpdatedAt:       timestamppb.New(entry.UpdatedAt),
+	}
+}
+
+func convertTimeReportToProto(report *model.TimeReport) *pb.TimeReport {
+	resp := &pb.TimeReport{
+		TotalSeconds: int64(report.Total / time.Second),
+		Tasks:        make([]*pb.TaskTimeTotal, len(report.ByTask)),
+		Daily:        make([]*pb.DailyTimeTotal, len(report.Daily)),
+	}
+	for i, total := range report.ByTask {
+		resp.Tasks[i] = &pb.TaskTimeTotal{
+			TaskId:       total.TaskID.Hex(),
+			TotalSeconds: int64(total.Duration / time.Second),
+			EntryCount:   total.Entries,
+		}
+	}
+	for i, day := range report.Daily {
+		resp.Daily[i] = &pb.DailyTimeTotal{Date: day.Date, TotalSeconds: int64(day.Duration / time.Second)}
+	}
+	return resp
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type mockTimeTrackingService struct {
+	mock.Mock
+}
+
+func (m *mockTimeTrackingService) entry(args mock.Arguments) (*model.TimeEntry, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TimeEntry), args.Error(1)
+}
+
+func (m *mockTimeTrackingService) StartTimer(ctx context.Context, userID string, taskID string, note string) (*model.TimeEntry, error) {
+	return m.entry(m.Called(ctx, userID, taskID, note))
+}
+
+func (m *mockTimeTrackingService) StopTimer(ctx context.Context, userID string) (*model.TimeEntry, error) {
+	return m.entry(m.Called(ctx, userID))
+}
+
+func (m *mockTimeTrackingService) GetRunningTimer(ctx context.Context, userID string) (*model.TimeEntry, error) {
+	return m.entry(m.Called(ctx, userID))
+}
+
+func (m *mockTimeTrackingService) AddTimeEntry(ctx context.Context, entry *model.TimeEntry) (*model.TimeEntry, error) {
+	return m.entry(m.Called(ctx, entry))
+}
+
+func (m *mockTimeTrackingService) UpdateTimeEntry(ctx context.Context, userID string, id string, start time.Time, end time.Time, note string) (*model.TimeEntry, error) {
+	return m.entry(m.Called(ctx, userID, id, start, end, note))
+}
+
+func (m *mockTimeTrackingService) DeleteTimeEntry(ctx context.Context, userID string, id string) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *mockTimeTrackingService) ListTimeEntries(ctx context.Context, userID string, taskID string) ([]*model.TimeEntry, time.Duration, error) {
+	args := m.Called(ctx, userID, taskID)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.TimeEntry), args.Get(1).(time.Duration), args.Error(2)
+}
+
+func (m *mockTimeTrackingService) GetTimeReport(ctx context.Context, userID string, query model.TaskStatsQuery) (*model.TimeReport, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TimeReport), args.Error(1)
+}
+
+func (m *mockTimeTrackingService) ExportTimesheet(ctx context.Context, userID string, query model.TaskStatsQuery, fn func(*model.TimesheetRow) error) error {
+	return m.Called(ctx, userID, query, fn).Error(0)
+}
+
+func (m *mockTimeTrackingService) StopTaskTimer(ctx context.Context, taskID primitive.ObjectID) error {
+	return m.Called(ctx, taskID).Error(0)
+}
+
+func TestTimeTrackingHandler_StartTimer(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	t.Run("already_running", func(t *testing.T) {
+		mockService := new(mockTimeTrackingService)
+		handler := NewTimeTrackingHandler(mockService)
+		taskID := primitive.NewObjectID().Hex()
+		mockService.On("StartTimer", ctx, "user1", taskID, "").
+			Return(nil, apperrors.NewFailedPreconditionError("既に実行中のタイマーがあります", nil))
+
+		_, err := handler.StartTimer(ctx, &pb.StartTimerRequest{TaskId: taskID})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewTimeTrackingHandler(new(mockTimeTrackingService))
+
+		_, err := handler.StartTimer(context.Background(), &pb.StartTimerRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestTimeTrackingHandler_AddTimeEntry(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	start := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mockTimeTrackingService)
+		handler := NewTimeTrackingHandler(mockService)
+		taskID := primitive.NewObjectID()
+		mockService.On("AddTimeEntry", ctx, mock.MatchedBy(func(e *model.TimeEntry) bool {
+			return e.UserID == "user1" && e.TaskID == taskID && e.Start.Equal(start) && e.End.Equal(start.Add(time.Hour))
+		})).Return(&model.TimeEntry{ID: primitive.NewObjectID(), TaskID: taskID, Start: start, End: start.Add(time.Hour)}, nil)
+
+		resp, err := handler.AddTimeEntry(ctx, &pb.AddTimeEntryRequest{
+			TaskId: taskID.Hex(),
+			Start:  timestamppb.New(start),
+			End:    timestamppb.New(start.Add(time.Hour)),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3600), resp.DurationSeconds)
+		assert.False(t, resp.Running)
+	})
+
+	t.Run("invalid_task_id", func(t *testing.T) {
+		handler := NewTimeTrackingHandler(new(mockTimeTrackingService))
+
+		_, err := handler.AddTimeEntry(ctx, &pb.AddTimeEntryRequest{TaskId: "invalid"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestTimeTrackingHandler_ExportTimesheet(t *testing.T) {
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	t.Run("csv", func(t *testing.T) {
+		mockService := new(mockTimeTrackingService)
+		handler := NewTimeTrackingHandler(mockService)
+		start := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+		mockService.On("ExportTimesheet", ctx, "user1", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				fn := args.Get(3).(func(*model.TimesheetRow) error)
+				_ = fn(&model.TimesheetRow{
+					Entry:     &model.TimeEntry{TaskID: primitive.NewObjectID(), Start: start, End: start.Add(45 * time.Minute)},
+					TaskTitle: "設計",
+				})
+			}).
+			Return(nil)
+
+		stream := &fakeExportStream{ctx: ctx}
+		err := handler.ExportTimesheet(&pb.ExportTimesheetRequest{FromDate: "2024-04-01", ToDate: "2024-04-01", Format: pb.ExportFormat_EXPORT_FORMAT_CSV}, stream)
+		require.NoError(t, err)
+		assert.Equal(t, "csv", stream.messages[0].GetMetadata().FileExtension)
+		lines := strings.Split(strings.TrimSpace(stream.content()), "\n")
+		require.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[1], "2024-04-01,"))
+		assert.True(t, strings.Contains(lines[1], ",設計,"))
+		assert.True(t, strings.Contains(lines[1], ",0.75,"))
+	})
+
+	t.Run("invalid_request", func(t *testing.T) {
+		handler := NewTimeTrackingHandler(new(mockTimeTrackingService))
+		for _, req := range []*pb.ExportTimesheetRequest{
+			{Format: pb.ExportFormat_EXPORT_FORMAT_ICALENDAR},
+			{},
+			{Format: pb.ExportFormat_EXPORT_FORMAT_CSV, TimeZone: "Mars/Olympus"},
+		} {
+			stream := &fakeExportStream{ctx: ctx}
+			err := handler.ExportTimesheet(req, stream)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), req.String())
+			assert.Empty(t, stream.messages)
+		}
+	})
+}
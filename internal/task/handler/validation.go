@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/i18n"
+	"github.com/my-backend-project/internal/pkg/validator"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/recurrence"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// taskIDRule はタスクなどのIDの項目のルールです
+const taskIDRule = "required,mongodb"
+
+// NewRequestValidator は TaskService のリクエストのルールを登録した validator を返します。
+// ValidationInterceptor で使い、ルールの項目名は生成したコードの構造体の項目名です
+func NewRequestValidator(translator *i18n.Translator) (*validator.Validator, error) {
+	v := validator.New(translator)
+	if err := v.RegisterCheck("enum", isKnownEnum); err != nil {
+		return nil, err
+	}
+	if err := v.RegisterCheck("time_zone", isTimeZone); err != nil {
+		return nil, err
+	}
+
+	task := map[string]string{
+		"Title":     "required",
+		"Status":    "enum",
+		"Priority":  "enum",
+		"DueDate":   "required_without=DueDay",
+		"Labels":    "dive,required",
+		"Checklist": "max=" + strconv.Itoa(model.MaxChecklistItems) + ",dive",
+		"Reminders": "max=" + strconv.Itoa(model.MaxRemindersPerTask) + ",dive",
+		"ProjectId": "omitempty,mongodb",
+		"TimeZone":  "omitempty,time_zone",
+	}
+	v.RegisterRules(withRules(task, map[string]string{"ParentId": "omitempty,mongodb"}), pb.CreateTaskRequest{})
+	// 更新ではステータスを省略できないため、UNSPECIFIED（0）も拒否する
+	v.RegisterRules(withRules(task, map[string]string{"TaskId": taskIDRule, "Status": "required,enum"}), pb.UpdateTaskRequest{})
+
+	v.RegisterRules(map[string]string{
+		"Year":  "min=1,max=9999",
+		"Month": "min=1,max=12",
+		"Day":   "min=1,max=31",
+	}, pb.Date{})
+	v.RegisterRules(map[string]string{
+		"Text": "required,max=" + strconv.Itoa(model.MaxChecklistItemLength),
+	}, pb.ChecklistItem{})
+	v.RegisterRules(map[string]string{
+		"MinutesBefore": "min=0,max=" + strconv.Itoa(model.MaxReminderMinutesBefore),
+		"Channels":      "dive,required,enum",
+	}, pb.Reminder{})
+	v.RegisterRules(map[string]string{
+		"Rrule":      "required",
+		"TimeZone":   "omitempty,time_zone",
+		"NextTaskId": "omitempty,mongodb",
+	}, pb.Recurrence{})
+
+	for _, req := range []interface{}{
+		pb.GetTaskRequest{},
+		pb.DeleteTaskRequest{},
+		pb.ListSubtasksRequest{},
+		pb.ListDependenciesRequest{},
+		pb.ListTaskTransitionsRequest{},
+	} {
+		v.RegisterRules(map[string]string{"TaskId": taskIDRule}, req)
+	}
+	v.RegisterRules(map[string]string{
+		"TaskId":    taskIDRule,
+		"BlockerId": taskIDRule,
+	}, pb.AddDependencyRequest{}, pb.RemoveDependencyRequest{})
+	v.RegisterRules(map[string]string{
+		"TaskId":   taskIDRule,
+		"ParentId": "omitempty,mongodb",
+	}, pb.MoveTaskRequest{})
+	v.RegisterRules(map[string]string{
+		"BeforeId": "omitempty,mongodb",
+		"AfterId":  "omitempty,mongodb",
+	}, pb.BoardPosition{})
+	v.RegisterRules(map[string]string{
+		"TaskId":   taskIDRule,
+		"ToStatus": "required",
+	}, pb.TransitionTaskRequest{})
+
+	v.RegisterRules(map[string]string{
+		"Status":     "enum",
+		"PageSize":   "min=0",
+		"Statuses":   "dive,enum",
+		"DueFilter":  "enum",
+		"OrderBy":    "enum",
+		"Direction":  "enum",
+		"Priorities": "dive,enum",
+		"ProjectId":  "omitempty,mongodb",
+		"TimeZone":   "omitempty,time_zone",
+	}, pb.ListTasksRequest{})
+	v.RegisterRules(map[string]string{
+		"Query":    "required",
+		"PageSize": "min=0",
+	}, pb.SearchTasksRequest{})
+	v.RegisterRules(map[string]string{
+		"PageSize": "min=0",
+	}, pb.ListNextTasksRequest{})
+	v.RegisterRules(map[string]string{
+		"Rrule":    "required",
+		"TimeZone": "omitempty,time_zone",
+		"PageSize": "min=0",
+	}, pb.PreviewRecurrenceRequest{})
+
+	v.RegisterRules(map[string]string{
+		"Tasks": "required,max=" + strconv.Itoa(model.MaxBatchSize) + ",dive",
+	}, pb.BatchCreateTasksRequest{})
+	v.RegisterRules(map[string]string{
+		"TaskIds": "max=" + strconv.Itoa(model.MaxBatchSize) + ",dive,mongodb",
+		"Patch":   "required",
+	}, pb.BatchUpdateTasksRequest{})
+	v.RegisterRules(map[string]string{
+		"TaskIds": "max=" + strconv.Itoa(model.MaxBatchSize) + ",dive,mongodb",
+	}, pb.BatchDeleteTasksRequest{})
+	v.RegisterRules(map[string]string{
+		"Status":       "enum",
+		"Priority":     "enum",
+		"AddLabels":    "dive,required",
+		"RemoveLabels": "dive,required",
+	}, pb.TaskPatch{})
+
+	v.RegisterRules(map[string]string{
+		"Format": "required,enum",
+	}, pb.ExportTasksRequest{})
+	v.RegisterRules(map[string]string{
+		"FromDate": "omitempty,datetime=" + model.DueDayLayout,
+		"ToDate":   "omitempty,datetime=" + model.DueDayLayout,
+		"TimeZone": "omitempty,time_zone",
+	}, pb.GetTaskStatsRequest{})
+
+	return v, nil
+}
+
+// withRules は base に extra を加えたルールを返します
+func withRules(base, extra map[string]string) map[string]string {
+	rules := make(map[string]string, len(base)+len(extra))
+	for field, rule := range base {
+		rules[field] = rule
+	}
+	for field, rule := range extra {
+		rules[field] = rule
+	}
+	return rules
+}
+
+// isKnownEnum は列挙型の値が proto で定義した値かを返します
+func isKnownEnum(field reflect.Value) bool {
+	enum, ok := field.Interface().(protoreflect.Enum)
+	if !ok {
+		return false
+	}
+	return enum.Descriptor().Values().ByNumber(enum.Number()) != nil
+}
+
+// isTimeZone は IANA のタイムゾーン名かを返します
+func isTimeZone(field reflect.Value) bool {
+	_, err := recurrence.LoadLocation(field.String())
+	return err == nil
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/i18n"
+	"github.com/my-backend-project/internal/task/interceptor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const validTaskID = "65f1a2b3c4d5e6f708091a2b"
+
+// validate はリクエストを検証インターセプターに通し、ハンドラーまで届いたかとエラーを返します
+func validate(t *testing.T, lang string, req interface{}) (bool, error) {
+	t.Helper()
+	translator := i18n.GetTranslator()
+	require.NoError(t, translator.LoadDefaultMessages())
+	v, err := NewRequestValidator(translator)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	if lang != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("accept-language", lang))
+	}
+	called := false
+	_, err = interceptor.NewValidationInterceptor(v).Unary()(ctx, req, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+	return called, err
+}
+
+// fieldViolations は INVALID_ARGUMENT の BadRequest の詳細を項目名から説明への対応にして返します
+func fieldViolations(t *testing.T, err error) map[string]string {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	violations := make(map[string]string)
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				violations[violation.Field] = violation.Description
+			}
+		}
+	}
+	return violations
+}
+
+func TestRequestValidator_CreateTask(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		called, err := validate(t, "", &pb.CreateTaskRequest{
+			Title:    "買い物",
+			Status:   pb.TaskStatus_TASK_STATUS_PENDING,
+			DueDay:   &pb.Date{Year: 2024, Month: 3, Day: 10},
+			TimeZone: "Asia/Tokyo",
+		})
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("every_invalid_field", func(t *testing.T) {
+		called, err := validate(t, "", &pb.CreateTaskRequest{
+			Status:    pb.TaskStatus(42),
+			Labels:    []string{"仕事", ""},
+			ParentId:  "parent",
+			TimeZone:  "Local",
+			Checklist: []*pb.ChecklistItem{{Text: "牛乳"}, {}},
+			Reminders: []*pb.Reminder{{MinutesBefore: -1}},
+		})
+		assert.False(t, called)
+		assert.Equal(t, map[string]string{
+			"title":                       "titleは必須です",
+			"status":                      "statusの値が不正です",
+			"due_date":                    "due_dateかdue_dayのどちらかは必須です",
+			"labels[1]":                   "labels[1]は必須です",
+			"checklist[1].text":           "checklist[1].textは必須です",
+			"reminders[0].minutes_before": "reminders[0].minutes_beforeは0以上である必要があります",
+			"parent_id":                   "parent_idはIDの形式ではありません",
+			"time_zone":                   "time_zoneはIANAのタイムゾーン名ではありません",
+		}, fieldViolations(t, err))
+	})
+
+	t.Run("localized", func(t *testing.T) {
+		_, err := validate(t, "en-US,en;q=0.9", &pb.CreateTaskRequest{
+			DueDay: &pb.Date{Year: 2024, Month: 13, Day: 10},
+		})
+		assert.Equal(t, map[string]string{
+			"title":         "title is required",
+			"due_day.month": "due_day.month must be at most 12",
+		}, fieldViolations(t, err))
+	})
+}
+
+func TestRequestValidator_UpdateTask(t *testing.T) {
+	// 未定義のステータスは書き込む前に拒否する
+	_, err := validate(t, "", &pb.UpdateTaskRequest{
+		TaskId:  validTaskID,
+		Title:   "買い物",
+		Status:  pb.TaskStatus(7),
+		DueDate: timestamppb.Now(),
+	})
+	assert.Equal(t, map[string]string{"status": "statusの値が不正です"}, fieldViolations(t, err))
+
+	_, err = validate(t, "", &pb.UpdateTaskRequest{Title: "買い物", Status: pb.TaskStatus_TASK_STATUS_ACTIVE, DueDate: timestamppb.Now()})
+	assert.Equal(t, map[string]string{"task_id": "task_idは必須です"}, fieldViolations(t, err))
+
+	// 更新ではステータスを省略できない
+	_, err = validate(t, "", &pb.UpdateTaskRequest{
+		TaskId:  validTaskID,
+		Title:   "買い物",
+		Status:  pb.TaskStatus_TASK_STATUS_UNSPECIFIED,
+		DueDate: timestamppb.Now(),
+	})
+	assert.Equal(t, map[string]string{"status": "statusは必須です"}, fieldViolations(t, err))
+}
+
+func TestRequestValidator_OtherRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		req  interface{}
+		want map[string]string
+	}{
+		{
+			name: "get_task",
+			req:  &pb.GetTaskRequest{TaskId: "abc"},
+			want: map[string]string{"task_id": "task_idはIDの形式ではありません"},
+		},
+		{
+			name: "list_tasks",
+			req: &pb.ListTasksRequest{
+				PageSize: -1,
+				Statuses: []pb.TaskStatus{pb.TaskStatus_TASK_STATUS_ACTIVE, pb.TaskStatus(9)},
+				TimeZone: "Mars/Olympus_Mons",
+			},
+			want: map[string]string{
+				"page_size":   "page_sizeは0以上である必要があります",
+				"statuses[1]": "statuses[1]の値が不正です",
+				"time_zone":   "time_zoneはIANAのタイムゾーン名ではありません",
+			},
+		},
+		{
+			name: "batch_create_validates_each_task",
+			req: &pb.BatchCreateTasksRequest{Tasks: []*pb.CreateTaskRequest{
+				{Title: "買い物", DueDate: timestamppb.Now()},
+				{DueDate: timestamppb.Now()},
+			}},
+			want: map[string]string{"tasks[1].title": "tasks[1].titleは必須です"},
+		},
+		{
+			name: "batch_update_filter",
+			req: &pb.BatchUpdateTasksRequest{
+				Filter: &pb.ListTasksRequest{Priorities: []pb.TaskPriority{pb.TaskPriority(8)}},
+			},
+			want: map[string]string{
+				"filter.priorities[0]": "filter.priorities[0]の値が不正です",
+				"patch":                "patchは必須です",
+			},
+		},
+		{
+			name: "stats_dates",
+			req:  &pb.GetTaskStatsRequest{FromDate: "2024/03/01"},
+			want: map[string]string{"from_date": "from_dateは2006-01-02の形式である必要があります"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called, err := validate(t, "", tt.req)
+			assert.False(t, called)
+			assert.Equal(t, tt.want, fieldViolations(t, err))
+		})
+	}
+
+	t.Run("requests_without_rules_pass", func(t *testing.T) {
+		called, err := validate(t, "", &pb.WatchTasksRequest{})
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+}
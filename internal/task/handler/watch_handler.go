@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/watch"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultHeartbeatInterval は WatchTasks で変更がない間に heartbeat を送る既定の間隔です。
+// ロードバランサーなどがアイドル状態のストリームを切断しないよう、一般的なアイドルタイムアウトより短くしています
+const DefaultHeartbeatInterval = 15 * time.Second
+
+func (h *TaskHandler) WatchTasks(req *pb.WatchTasksRequest, stream pb.TaskService_WatchTasksServer) error {
+	userID, ok := interceptor.UserIDFromContext(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+
+	sub, err := h.taskService.WatchTasks(stream.Context(), userID, req.ResumeToken)
+	if err != nil {
+		return convertErrorToGRPCStatus(stream.Context(), err)
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				if err := sub.Err(); err != nil {
+					return convertErrorToGRPCStatus(stream.Context(), err)
+				}
+				return nil
+			}
+			if err := stream.Send(&pb.WatchTasksResponse{
+				Payload: &pb.WatchTasksResponse_Event{Event: convertEventToProto(event)},
+			}); err != nil {
+				return err
+			}
+			heartbeat.Reset(h.heartbeatInterval)
+		case <-heartbeat.C:
+			if err := stream.Send(&pb.WatchTasksResponse{
+				Payload: &pb.WatchTasksResponse_Heartbeat{Heartbeat: &pb.Heartbeat{SentAt: timestamppb.Now()}},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func convertEventToProto(event watch.Event) *pb.TaskEvent {
+	resp := &pb.TaskEvent{
+		TaskId:      event.TaskID.Hex(),
+		ResumeToken: event.Token,
+		OccurredAt:  timestamppb.New(event.OccurredAt),
+	}
+	switch event.Type {
+	case watch.EventCreated:
+		resp.Type = pb.TaskEventType_TASK_EVENT_TYPE_CREATED
+	case watch.EventUpdated:
+		resp.Type = pb.TaskEventType_TASK_EVENT_TYPE_UPDATED
+	case watch.EventDeleted:
+		resp.Type = pb.TaskEventType_TASK_EVENT_TYPE_DELETED
+	}
+	if event.Task != nil {
+		resp.Task = convertTaskToProto(event.Task)
+	}
+	return resp
+}
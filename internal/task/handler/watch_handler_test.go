@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/watch"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeWatchStream は送信したメッセージをチャネルへ渡す WatchTasks のストリームです
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *pb.WatchTasksResponse
+}
+
+func (s *fakeWatchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeWatchStream) Send(resp *pb.WatchTasksResponse) error {
+	s.sent <- resp
+	return nil
+}
+
+func (s *fakeWatchStream) next(t *testing.T) *pb.WatchTasksResponse {
+	t.Helper()
+	select {
+	case resp := <-s.sent:
+		return resp
+	case <-time.After(time.Second):
+		t.Fatal("メッセージを受信できませんでした")
+		return nil
+	}
+}
+
+func TestTaskHandler_WatchTasks(t *testing.T) {
+	t.Run("events_and_heartbeats", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(interceptor.ContextWithUserID(context.Background(), "user1"))
+		defer cancel()
+		bus := watch.NewBus(0)
+		sub, err := bus.Subscribe(ctx, "user1", "")
+		require.NoError(t, err)
+
+		mockService := new(mockTaskService)
+		mockService.On("WatchTasks", ctx, "user1", "token").Return(sub, nil).Once()
+		handler := NewTaskHandler(mockService)
+		handler.heartbeatInterval = 20 * time.Millisecond
+
+		stream := &fakeWatchStream{ctx: ctx, sent: make(chan *pb.WatchTasksResponse, 10)}
+		done := make(chan error, 1)
+		go func() {
+			done <- handler.WatchTasks(&pb.WatchTasksRequest{ResumeToken: "token"}, stream)
+		}()
+
+		assert.NotNil(t, stream.next(t).GetHeartbeat(), "変更がない間は heartbeat を送る")
+
+		task := &model.Task{ID: primitive.NewObjectID(), UserID: "user1", Title: "新しいタスク"}
+		bus.Publish(watch.Event{Type: watch.EventCreated, TaskID: task.ID, UserID: "user1", Task: task})
+		var event *pb.TaskEvent
+		for event == nil {
+			event = stream.next(t).GetEvent()
+		}
+		assert.Equal(t, pb.TaskEventType_TASK_EVENT_TYPE_CREATED, event.Type)
+		assert.Equal(t, "新しいタスク", event.Task.Title)
+		assert.NotEmpty(t, event.ResumeToken)
+
+		bus.Publish(watch.Event{Type: watch.EventDeleted, TaskID: task.ID, UserID: "user1"})
+		event = nil
+		for event == nil {
+			event = stream.next(t).GetEvent()
+		}
+		assert.Equal(t, pb.TaskEventType_TASK_EVENT_TYPE_DELETED, event.Type)
+		assert.Nil(t, event.Task)
+
+		cancel()
+		assert.NoError(t, <-done)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("resume_token_expired", func(t *testing.T) {
+		ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+		mockService := new(mockTaskService)
+		mockService.On("WatchTasks", ctx, "user1", "old").Return(nil, watch.ErrResumeTokenExpired).Once()
+
+		err := NewTaskHandler(mockService).WatchTasks(&pb.WatchTasksRequest{ResumeToken: "old"}, &fakeWatchStream{ctx: ctx})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := NewTaskHandler(new(mockTaskService)).WatchTasks(&pb.WatchTasksRequest{}, &fakeWatchStream{ctx: context.Background()})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/service"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type WorkflowHandler struct {
+	pb.UnimplementedWorkflowServiceServer
+	workflowService service.WorkflowService
+}
+
+func NewWorkflowHandler(workflowService service.WorkflowService) *WorkflowHandler {
+	return &WorkflowHandler{
+		workflowService: workflowService,
+	}
+}
+
+func (h *WorkflowHandler) GetWorkflow(ctx context.Context, req *pb.GetWorkflowRequest) (*pb.Workflow, error) {
+	ownerID, err := callerID(ctx, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := h.workflowService.GetWorkflow(ctx, ownerID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return convertWorkflowToProto(workflow), nil
+}
+
+func (h *WorkflowHandler) PutWorkflow(ctx context.Context, req *pb.PutWorkflowRequest) (*pb.Workflow, error) {
+	ownerID, err := callerID(ctx, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow := &model.Workflow{
+		OwnerID:       ownerID,
+		Statuses:      make([]model.WorkflowStatus, len(req.Statuses)),
+		Transitions:   make([]model.WorkflowTransition, len(req.Transitions)),
+		InitialStatus: req.InitialStatus,
+	}
+	for i, s := range req.Statuses {
+		workflow.Statuses[i] = model.WorkflowStatus{
+			Key:      s.Key,
+			Name:     s.Name,
+			Category: statusCategoryFromProto[s.Category],
+		}
+	}
+	for i, t := range req.Transitions {
+		guards := make([]model.TransitionGuard, len(t.Guards))
+		for j, g := range t.Guards {
+			guards[j] = transitionGuardFromProto[g]
+		}
+		workflow.Transitions[i] = model.WorkflowTransition{From: t.From, To: t.To, Guards: guards}
+	}
+
+	saved, err := h.workflowService.PutWorkflow(ctx, workflow)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return convertWorkflowToProto(saved), nil
+}
+
+func (h *WorkflowHandler) ResetWorkflow(ctx context.Context, req *pb.ResetWorkflowRequest) (*pb.Workflow, error) {
+	ownerID, err := callerID(ctx, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := h.workflowService.ResetWorkflow(ctx, ownerID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return convertWorkflowToProto(workflow), nil
+}
+
+var statusCategoryFromProto = map[pb.StatusCategory]model.StatusCategory{
+	pb.StatusCategory_STATUS_CATEGORY_TODO:        model.StatusCategoryTodo,
+	pb.StatusCategory_STATUS_CATEGORY_IN_PROGRESS: model.StatusCategoryInProgress,
+	pb.StatusCategory_STATUS_CATEGORY_DONE:        model.StatusCategoryDone,
+}
+
+var transitionGuardFromProto = map[pb.TransitionGuard]model.TransitionGuard{
+	pb.TransitionGuard_TRANSITION_GUARD_REASON_REQUIRED:    model.GuardReasonRequired,
+	pb.TransitionGuard_TRANSITION_GUARD_NO_OPEN_SUBTASKS:   model.GuardNoOpenSubtasks,
+	pb.TransitionGuard_TRANSITION_GUARD_NO_OPEN_BLOCKERS:   model.GuardNoOpenBlockers,
+	pb.TransitionGuard_TRANSITION_GUARD_CHECKLIST_COMPLETE: model.GuardChecklistComplete,
+}
+
+var statusCategoryToProto = map[model.StatusCategory]pb.StatusCategory{
+	model.StatusCategoryTodo:       pb.StatusCategory_STATUS_CATEGORY_TODO,
+	model.StatusCategoryInProgress: pb.StatusCategory_STATUS_CATEGORY_IN_PROGRESS,
+	model.StatusCategoryDone:       pb.StatusCategory_STATUS_CATEGORY_DONE,
+}
+
+var transitionGuardToProto = map[model.TransitionGuard]pb.TransitionGuard{
+	model.GuardReasonRequired:    pb.TransitionGuard_TRANSITION_GUARD_REASON_REQUIRED,
+	model.GuardNoOpenSubtasks:    pb.TransitionGuard_TRANSITION_GUARD_NO_OPEN_SUBTASKS,
+	model.GuardNoOpenBlockers:    pb.TransitionGuard_TRANSITION_GUARD_NO_OPEN_BLOCKERS,
+	model.GuardChecklistComplete: pb.TransitionGuard_TRANSITION_GUARD_CHECKLIST_COMPLETE,
+}
+
+func convertWorkflowToProto(workflow *model.Workflow) *pb.Workflow {
+	resp := &pb.Workflow{
+		OwnerId:       workflow.OwnerID,
+		Statuses:      make([]*pb.WorkflowStatus, len(workflow.Statuses)),
+		Transitions:   make([]*pb.WorkflowTransition, len(workflow.Transitions)),
+		InitialStatus: workflow.InitialStatus,
+		IsDefault:     workflow.IsDefault(),
+	}
+	if !workflow.IsDefault() {
+		resp.CreatedAt = timestamppb.New(workflow.CreatedAt)
+		resp.UpdatedAt = timestamppb.New(workflow.UpdatedAt)
+	}
+	for i, s := range workflow.Statuses {
+		resp.Statuses[i] = &pb.WorkflowStatus{Key: s.Key, Name: s.Name, Category: statusCategoryToProto[s.Category]}
+	}
+	for i, t := range workflow.Transitions {
+		transition := &pb.WorkflowTransition{From: t.From, To: t.To}
+		for _, g := range t.Guards {
+			transition.Guards = append(transition.Guards, transitionGuardToProto[g])
+		}
+		resp.Transitions[i] = transition
+	}
+	return resp
+}
+
+func (h *TaskHandler) TransitionTask(ctx context.Context, req *pb.TransitionTaskRequest) (*pb.TransitionTaskResponse, error) {
+	userID, err := callerID(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	task, transition, err := h.taskService.TransitionTask(ctx, req.TaskId, userID, req.ToStatus, req.Reason)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	return &pb.TransitionTaskResponse{
+		Task:       convertTaskToProto(task),
+		Transition: convertTransitionToProto(transition),
+	}, nil
+}
+
+func (h *TaskHandler) ListTaskTransitions(ctx context.Context, req *pb.ListTaskTransitionsRequest) (*pb.ListTaskTransitionsResponse, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := h.taskService.ListTaskTransitions(ctx, req.TaskId, userID)
+	if err != nil {
+		return nil, convertErrorToGRPCStatus(ctx, err)
+	}
+
+	resp := &pb.ListTaskTransitionsResponse{Transitions: make([]*pb.TaskTransition, len(transitions))}
+	for i, transition := range transitions {
+		resp.Transitions[i] = convertTransitionToProto(transition)
+	}
+	return resp, nil
+}
+
+func convertTransitionToProto(transition *model.TaskTransition) *pb.TaskTransition {
+	return &pb.TaskTransition{
+		TaskId:     transition.TaskID.Hex(),
+		UserId:     transition.UserID,
+		FromStatus: transition.From,
+		ToStatus:   transition.To,
+		Reason:     transition.Reason,
+		CreatedAt:  timestamppb.New(transition.CreatedAt),
+	}
+}
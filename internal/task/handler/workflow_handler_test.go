@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pb"
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/interceptor"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockWorkflowService struct {
+	mock.Mock
+}
+
+func (m *mockWorkflowService) GetWorkflow(ctx context.Context, ownerID string) (*model.Workflow, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Workflow), args.Error(1)
+}
+
+func (m *mockWorkflowService) PutWorkflow(ctx context.Context, workflow *model.Workflow) (*model.Workflow, error) {
+	args := m.Called(ctx, workflow)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Workflow), args.Error(1)
+}
+
+func (m *mockWorkflowService) ResetWorkflow(ctx context.Context, ownerID string) (*model.Workflow, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Workflow), args.Error(1)
+}
+
+func TestWorkflowHandler_GetWorkflow(t *testing.T) {
+	mockService := new(mockWorkflowService)
+	handler := NewWorkflowHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("GetWorkflow", ctx, "user1").Return(model.DefaultWorkflow("user1"), nil).Once()
+
+		resp, err := handler.GetWorkflow(ctx, &pb.GetWorkflowRequest{OwnerId: "user1"})
+		assert.NoError(t, err)
+		assert.True(t, resp.IsDefault)
+		assert.Nil(t, resp.CreatedAt)
+		assert.Len(t, resp.Statuses, 3)
+		assert.Equal(t, pb.StatusCategory_STATUS_CATEGORY_DONE, resp.Statuses[2].Category)
+	})
+
+	t.Run("owner_from_context", func(t *testing.T) {
+		mockService.On("GetWorkflow", ctx, "user1").Return(model.DefaultWorkflow("user1"), nil).Once()
+
+		resp, err := handler.GetWorkflow(ctx, &pb.GetWorkflowRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", resp.OwnerId)
+	})
+
+	t.Run("other_owner", func(t *testing.T) {
+		_, err := handler.GetWorkflow(ctx, &pb.GetWorkflowRequest{OwnerId: "user2"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockService.AssertNotCalled(t, "GetWorkflow", mock.Anything, "user2")
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := handler.GetWorkflow(context.Background(), &pb.GetWorkflowRequest{OwnerId: "user1"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestWorkflowHandler_PutWorkflow(t *testing.T) {
+	mockService := new(mockWorkflowService)
+	handler := NewWorkflowHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+
+	req := &pb.PutWorkflowRequest{
+		OwnerId: "user1",
+		Statuses: []*pb.WorkflowStatus{
+			{Key: "OPEN", Name: "未着手", Category: pb.StatusCategory_STATUS_CATEGORY_TODO},
+			{Key: "CLOSED", Name: "完了", Category: pb.StatusCategory_STATUS_CATEGORY_DONE},
+		},
+		Transitions: []*pb.WorkflowTransition{
+			{From: "OPEN", To: "CLOSED", Guards: []pb.TransitionGuard{pb.TransitionGuard_TRANSITION_GUARD_REASON_REQUIRED}},
+		},
+		InitialStatus: "OPEN",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("PutWorkflow", ctx, mock.MatchedBy(func(w *model.Workflow) bool {
+			return w.OwnerID == "user1" &&
+				w.Statuses[1].Category == model.StatusCategoryDone &&
+				w.Transitions[0].Guards[0] == model.GuardReasonRequired
+		})).Return(&model.Workflow{
+			ID:            primitive.NewObjectID(),
+			OwnerID:       "user1",
+			Statuses:      []model.WorkflowStatus{{Key: "OPEN", Name: "未着手", Category: model.StatusCategoryTodo}, {Key: "CLOSED", Name: "完了", Category: model.StatusCategoryDone}},
+			Transitions:   []model.WorkflowTransition{{From: "OPEN", To: "CLOSED", Guards: []model.TransitionGuard{model.GuardReasonRequired}}},
+			InitialStatus: "OPEN",
+		}, nil).Once()
+
+		resp, err := handler.PutWorkflow(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, resp.IsDefault)
+		assert.Equal(t, []pb.TransitionGuard{pb.TransitionGuard_TRANSITION_GUARD_REASON_REQUIRED}, resp.Transitions[0].Guards)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		mockService.On("PutWorkflow", ctx, mock.Anything).
+			Return(nil, apperrors.NewInvalidInputError("初期ステータスが定義されていません", nil)).Once()
+
+		_, err := handler.PutWorkflow(ctx, req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("other_owner", func(t *testing.T) {
+		_, err := handler.PutWorkflow(ctx, &pb.PutWorkflowRequest{OwnerId: "user2", InitialStatus: "OPEN"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestTaskHandler_TransitionTask(t *testing.T) {
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	taskID := primitive.NewObjectID()
+
+	t.Run("success", func(t *testing.T) {
+		task := &model.Task{ID: taskID, UserID: "user1", Status: model.TaskStatusActive, WorkflowStatus: "REVIEW"}
+		transition := &model.TaskTransition{TaskID: taskID, UserID: "user1", From: "DOING", To: "REVIEW", Reason: "実装完了"}
+		mockService.On("TransitionTask", ctx, taskID.Hex(), "user1", "REVIEW", "実装完了").Return(task, transition, nil).Once()
+
+		resp, err := handler.TransitionTask(ctx, &pb.TransitionTaskRequest{TaskId: taskID.Hex(), UserId: "user1", ToStatus: "REVIEW", Reason: "実装完了"})
+		assert.NoError(t, err)
+		assert.Equal(t, "REVIEW", resp.Task.WorkflowStatus)
+		assert.Equal(t, "DOING", resp.Transition.FromStatus)
+		assert.Equal(t, "実装完了", resp.Transition.Reason)
+	})
+
+	t.Run("not_allowed", func(t *testing.T) {
+		mockService.On("TransitionTask", ctx, taskID.Hex(), "user1", "DONE", "").
+			Return(nil, nil, apperrors.NewFailedPreconditionError("ステータスを「作業中」から「完了」へ変更することはできません", nil)).Once()
+
+		_, err := handler.TransitionTask(ctx, &pb.TransitionTaskRequest{TaskId: taskID.Hex(), UserId: "user1", ToStatus: "DONE"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+	t.Run("other_user", func(t *testing.T) {
+		_, err := handler.TransitionTask(ctx, &pb.TransitionTaskRequest{TaskId: taskID.Hex(), UserId: "user2", ToStatus: "DONE"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockService.AssertNotCalled(t, "TransitionTask", mock.Anything, taskID.Hex(), "user2", "DONE", "")
+	})
+}
+
+func TestTaskHandler_ListTaskTransitions(t *testing.T) {
+	mockService := new(mockTaskService)
+	handler := NewTaskHandler(mockService)
+	ctx := interceptor.ContextWithUserID(context.Background(), "user1")
+	taskID := primitive.NewObjectID()
+
+	t.Run("success", func(t *testing.T) {
+		transitions := []*model.TaskTransition{{TaskID: taskID, UserID: "user1", From: "TODO", To: "DOING"}}
+		mockService.On("ListTaskTransitions", ctx, taskID.Hex(), "user1").Return(transitions, nil).Once()
+
+		resp, err := handler.ListTaskTransitions(ctx, &pb.ListTaskTransitionsRequest{TaskId: taskID.Hex()})
+		require.NoError(t, err)
+		require.Len(t, resp.Transitions, 1)
+		assert.Equal(t, "DOING", resp.Transitions[0].ToStatus)
+	})
+
+	t.Run("other_owner", func(t *testing.T) {
+		mockService.On("ListTaskTransitions", ctx, taskID.Hex(), "user1").
+			Return(nil, apperrors.NewPermissionDeniedError("このタスクを操作する権限がありません", nil)).Once()
+
+		_, err := handler.ListTaskTransitions(ctx, &pb.ListTaskTransitionsRequest{TaskId: taskID.Hex()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := handler.ListTaskTransitions(context.Background(), &pb.ListTaskTransitionsRequest{TaskId: taskID.Hex()})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
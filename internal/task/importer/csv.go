@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type csvReader struct {
+	r       *csv.Reader
+	columns map[Field]int
+	opts    Options
+	number  int64
+}
+
+// NewCSVReader は1行目をヘッダーとして読み込み、mapping に従って各項目の列を決めます。
+// タイトルの列は必須です。mapping で指定した列がヘッダーにない場合はエラーを返します
+func NewCSVReader(r io.Reader, mapping ColumnMapping, opts Options) (Reader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("ヘッダー行がありません")
+		}
+		return nil, fmt.Errorf("ヘッダー行を読み込めません: %w", err)
+	}
+	if len(header) > 0 {
+		// Excel などが先頭に付ける BOM を取り除く
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+	columns := make(map[Field]int)
+	for _, field := range Fields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = string(field)
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		switch {
+		case ok:
+			columns[field] = i
+		case mapped:
+			return nil, fmt.Errorf("列 %q がヘッダーにありません", name)
+		}
+	}
+	if _, ok := columns[FieldTitle]; !ok {
+		return nil, errors.New("タイトルの列がありません。-map title=列名 で指定してください")
+	}
+	return &csvReader{r: cr, columns: columns, opts: opts}, nil
+}
+
+func (r *csvReader) Next() (*Row, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return nil, err
+		}
+		r.number++
+		return &Row{Number: r.number, Err: err}, nil
+	}
+	r.number++
+
+	values := make(map[Field]string, len(r.columns))
+	for field, i := range r.columns {
+		if i < len(record) {
+			values[field] = strings.TrimSpace(record[i])
+		}
+	}
+	task, err := buildTask(values, splitLabels(values[FieldLabels], r.opts.labelSeparator()), r.opts)
+	if err != nil {
+		return &Row{Number: r.number, Err: err}, nil
+	}
+	return &Row{Number: r.number, Task: task}, nil
+}
@@ -0,0 +1,199 @@
+// Package importer は他のツールから書き出した CSV や JSON Lines を読み込み、
+// ImportTasks で送るタスクに変換します。task-import コマンドから使います
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Field は取り込むタスクの項目です
+type Field string
+
+const (
+	FieldTitle          Field = "title"
+	FieldDescription    Field = "description"
+	FieldStatus         Field = "status"
+	FieldWorkflowStatus Field = "workflow_status"
+	FieldPriority       Field = "priority"
+	FieldLabels         Field = "labels"
+	FieldDueDate        Field = "due_date"
+	FieldParentID       Field = "parent_id"
+)
+
+// Fields は取り込める項目の一覧です
+var Fields = []Field{
+	FieldTitle, FieldDescription, FieldStatus, FieldWorkflowStatus, FieldPriority, FieldLabels, FieldDueDate, FieldParentID,
+}
+
+// exportOnlyFields は ExportTasks が書き出すが取り込みには使わない項目です。書き出したファイルをそのまま取り込めるよう読み飛ばします
+var exportOnlyFields = map[string]bool{"id": true, "created_at": true, "updated_at": true}
+
+func isField(name string) bool {
+	for _, field := range Fields {
+		if string(field) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Row は読み込んだ1行です。変換に失敗した行は Task を nil にし、Err に理由を設定します
+type Row struct {
+	// Number は CSV ではヘッダーを除いた何件目か、JSON Lines では何行目かです
+	Number int64
+	Task   *pb.CreateTaskRequest
+	Err    error
+}
+
+// Reader は行を順に返します。すべて返し終えたら io.EOF を返します
+type Reader interface {
+	Next() (*Row, error)
+}
+
+// Options は値の解釈の設定です
+type Options struct {
+	// Location は時刻を含まない期限を解釈するタイムゾーンです。nil の場合は UTC です
+	Location *time.Location
+	// LabelSeparator は CSV の1つの列に複数のラベルを書くときの区切り文字です。空の場合は ";" です
+	LabelSeparator string
+}
+
+func (o Options) location() *time.Location {
+	if o.Location == nil {
+		return time.UTC
+	}
+	return o.Location
+}
+
+func (o Options) labelSeparator() string {
+	if o.LabelSeparator == "" {
+		return ";"
+	}
+	return o.LabelSeparator
+}
+
+// ColumnMapping は項目と CSV の列名の対応です。対応を指定しない項目は項目名と同じ列から読み込みます
+type ColumnMapping map[Field]string
+
+// ParseColumnMapping は "title=Name,due_date=Due Date" の形式の対応を解析します
+func ParseColumnMapping(s string) (ColumnMapping, error) {
+	mapping := make(ColumnMapping)
+	if strings.TrimSpace(s) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("列の対応 %q は 項目=列名 の形式で指定してください", pair)
+		}
+		if !isField(field) {
+			return nil, fmt.Errorf("不明な項目です: %s", field)
+		}
+		mapping[Field(field)] = column
+	}
+	return mapping, nil
+}
+
+// buildTask は項目ごとの文字列の値をタスクに変換します
+func buildTask(values map[Field]string, labels []string, opts Options) (*pb.CreateTaskRequest, error) {
+	task := &pb.CreateTaskRequest{
+		Title:          values[FieldTitle],
+		Description:    values[FieldDescription],
+		WorkflowStatus: values[FieldWorkflowStatus],
+		ParentId:       values[FieldParentID],
+		Labels:         labels,
+	}
+
+	status, err := parseStatus(values[FieldStatus])
+	if err != nil {
+		return nil, err
+	}
+	task.Status = status
+
+	priority, err := parsePriority(values[FieldPriority])
+	if err != nil {
+		return nil, err
+	}
+	task.Priority = priority
+
+	if due := values[FieldDueDate]; due != "" {
+		dueDate, err := parseDueDate(due, opts.location())
+		if err != nil {
+			return nil, err
+		}
+		task.DueDate = timestamppb.New(dueDate)
+	}
+	return task, nil
+}
+
+// parseStatus は pending・active・complete か TASK_STATUS_ で始まる名前を受け付けます
+func parseStatus(s string) (pb.TaskStatus, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return pb.TaskStatus_TASK_STATUS_UNSPECIFIED, nil
+	}
+	if !strings.HasPrefix(s, "TASK_STATUS_") {
+		s = "TASK_STATUS_" + s
+	}
+	value, ok := pb.TaskStatus_value[s]
+	if !ok || value == int32(pb.TaskStatus_TASK_STATUS_UNSPECIFIED) {
+		return 0, fmt.Errorf("不明なステータスです: %s", s)
+	}
+	return pb.TaskStatus(value), nil
+}
+
+// parsePriority は low・medium・high・urgent、TASK_PRIORITY_ で始まる名前、0 から 4 の数値を受け付けます
+func parsePriority(s string) (pb.TaskPriority, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return pb.TaskPriority_TASK_PRIORITY_UNSPECIFIED, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := pb.TaskPriority_name[int32(n)]; !ok {
+			return 0, fmt.Errorf("不明な優先度です: %s", s)
+		}
+		return pb.TaskPriority(n), nil
+	}
+	if !strings.HasPrefix(s, "TASK_PRIORITY_") {
+		s = "TASK_PRIORITY_" + s
+	}
+	value, ok := pb.TaskPriority_value[s]
+	if !ok {
+		return 0, fmt.Errorf("不明な優先度です: %s", s)
+	}
+	return pb.TaskPriority(value), nil
+}
+
+var dueDateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02", "2006/01/02"}
+
+// parseDueDate は RFC 3339 か、タイムゾーンを含まない日付・日時を受け付けます
+func parseDueDate(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range dueDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("期限の形式が不正です: %s", s)
+}
+
+func splitLabels(s string, sep string) []string {
+	var labels []string
+	for _, label := range strings.Split(s, sep) {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, r Reader) []*Row {
+	t.Helper()
+	var rows []*Row
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestParseColumnMapping(t *testing.T) {
+	mapping, err := ParseColumnMapping("title=Name, due_date = Due Date")
+	require.NoError(t, err)
+	assert.Equal(t, ColumnMapping{FieldTitle: "Name", FieldDueDate: "Due Date"}, mapping)
+
+	_, err = ParseColumnMapping("owner=Assignee")
+	assert.Error(t, err)
+	_, err = ParseColumnMapping("title")
+	assert.Error(t, err)
+}
+
+func TestCSVReader(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	input := "\ufeffName,Due Date,status,Priority,labels\n" +
+		"設計,2024-05-01,pending,high,backend; api\n" +
+		"実装,2024-05-02 09:30,TASK_STATUS_ACTIVE,2,\n" +
+		"レビュー,tomorrow,,,\n"
+	mapping, err := ParseColumnMapping("title=Name,due_date=Due Date")
+	require.NoError(t, err)
+
+	r, err := NewCSVReader(strings.NewReader(input), mapping, Options{Location: tokyo})
+	require.NoError(t, err)
+	rows := readAll(t, r)
+	require.Len(t, rows, 3)
+
+	first := rows[0]
+	assert.Equal(t, int64(1), first.Number)
+	assert.Equal(t, "設計", first.Task.Title)
+	assert.Equal(t, pb.TaskStatus_TASK_STATUS_PENDING, first.Task.Status)
+	assert.Equal(t, pb.TaskPriority_TASK_PRIORITY_HIGH, first.Task.Priority)
+	assert.Equal(t, []string{"backend", "api"}, first.Task.Labels)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, tokyo).Unix(), first.Task.DueDate.AsTime().Unix())
+
+	assert.Equal(t, pb.TaskStatus_TASK_STATUS_ACTIVE, rows[1].Task.Status)
+	assert.Equal(t, pb.TaskPriority_TASK_PRIORITY_MEDIUM, rows[1].Task.Priority)
+	assert.Empty(t, rows[1].Task.Labels)
+
+	assert.Equal(t, int64(3), rows[2].Number)
+	assert.Nil(t, rows[2].Task)
+	assert.Error(t, rows[2].Err)
+}
+
+func TestNewCSVReader_Errors(t *testing.T) {
+	_, err := NewCSVReader(strings.NewReader(""), nil, Options{})
+	assert.Error(t, err)
+
+	_, err = NewCSVReader(strings.NewReader("Name\n"), nil, Options{})
+	assert.Error(t, err, "タイトルの列が必須")
+
+	_, err = NewCSVReader(strings.NewReader("title\n"), ColumnMapping{FieldDueDate: "Due"}, Options{})
+	assert.Error(t, err, "対応を指定した列は必須")
+}
+
+func TestJSONLReader(t *testing.T) {
+	input := `{"title":"設計","due_date":"2024-05-01T09:00:00Z","priority":3,"labels":["backend"]}
+
+{"title":"実装","status":"complete"}
+{"title":"不明","owner":"alice"}
+not json
+`
+	rows := readAll(t, NewJSONLReader(strings.NewReader(input), Options{}))
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, int64(1), rows[0].Number)
+	assert.Equal(t, pb.TaskPriority_TASK_PRIORITY_HIGH, rows[0].Task.Priority)
+	assert.Equal(t, []string{"backend"}, rows[0].Task.Labels)
+	assert.Equal(t, time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), rows[0].Task.DueDate.AsTime())
+
+	assert.Equal(t, int64(3), rows[1].Number)
+	assert.Equal(t, pb.TaskStatus_TASK_STATUS_COMPLETE, rows[1].Task.Status)
+	assert.Nil(t, rows[1].Task.DueDate)
+
+	assert.Error(t, rows[2].Err)
+	assert.Equal(t, int64(5), rows[3].Number)
+	assert.Error(t, rows[3].Err)
+}
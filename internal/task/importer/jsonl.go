@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/my-backend-project/internal/pb"
+)
+
+// maxJSONLineSize は JSON Lines の1行の最大バイト数です
+const maxJSONLineSize = 1 << 20
+
+var errLabelsNotStrings = errors.New("labels は文字列の配列で指定してください")
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	opts    Options
+	number  int64
+}
+
+// NewJSONLReader は1行に1つのタスクを JSON のオブジェクトで書いたファイルを読み込みます。
+// キーは項目名で、labels は文字列の配列、priority は名前か数値で指定します。空行と ExportTasks が書き出す id などの項目は読み飛ばします
+func NewJSONLReader(r io.Reader, opts Options) Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLineSize)
+	return &jsonlReader{scanner: scanner, opts: opts}
+}
+
+func (r *jsonlReader) Next() (*Row, error) {
+	for r.scanner.Scan() {
+		r.number++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		task, err := r.parse(line)
+		if err != nil {
+			return &Row{Number: r.number, Err: err}, nil
+		}
+		return &Row{Number: r.number, Task: task}, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (r *jsonlReader) parse(line []byte) (*pb.CreateTaskRequest, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("JSON を解析できません: %w", err)
+	}
+
+	values := make(map[Field]string, len(object))
+	var labels []string
+	for key, value := range object {
+		if exportOnlyFields[key] {
+			continue
+		}
+		if !isField(key) {
+			return nil, fmt.Errorf("不明な項目です: %s", key)
+		}
+		if Field(key) == FieldLabels {
+			list, ok := value.([]interface{})
+			if !ok {
+				return nil, errLabelsNotStrings
+			}
+			for _, item := range list {
+				label, ok := item.(string)
+				if !ok {
+					return nil, errLabelsNotStrings
+				}
+				labels = append(labels, label)
+			}
+			continue
+		}
+		switch v := value.(type) {
+		case nil:
+		case string:
+			values[Field(key)] = v
+		case json.Number:
+			values[Field(key)] = v.String()
+		default:
+			return nil, fmt.Errorf("%s の値が不正です", key)
+		}
+	}
+	return buildTask(values, labels, r.opts)
+}
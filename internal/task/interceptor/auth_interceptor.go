@@ -11,6 +11,20 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// userIDKey は認証済みのユーザーIDをコンテキストに保持するキーです
+type userIDKey struct{}
+
+// ContextWithUserID は認証済みのユーザーIDを持つコンテキストを返します
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext は AuthInterceptor が検証したトークンのユーザーIDを返します
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok && userID != ""
+}
+
 type AuthInterceptor struct {
 	jwtService auth.JWTService
 }
@@ -28,23 +42,56 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "metadata is not provided")
-		}
-
-		values := md["authorization"]
-		if len(values) == 0 {
-			return nil, status.Error(codes.Unauthenticated, "authorization token is not provided")
+		ctx, err := i.authenticate(ctx)
+		if err != nil {
+			return nil, err
 		}
+		return handler(ctx, req)
+	}
+}
 
-		accessToken := values[0]
-		claims, err := i.jwtService.ValidateToken(accessToken)
+// Stream はストリーミングRPCの認証を行うインターセプターです。ハンドラーには認証済みのユーザーIDを持つコンテキストを渡します
+func (i *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := i.authenticate(stream.Context())
 		if err != nil {
-			return nil, status.Error(codes.Unauthenticated, "invalid token")
+			return err
 		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticate はメタデータのトークンを検証し、ユーザーIDを持つコンテキストを返します
+func (i *AuthInterceptor) authenticate(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "metadata is not provided")
+	}
+
+	values := md["authorization"]
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization token is not provided")
+	}
 
-		newCtx := context.WithValue(ctx, "user_id", claims.UserID)
-		return handler(newCtx, req)
+	accessToken := values[0]
+	claims, err := i.jwtService.ValidateToken(accessToken)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
+
+	return ContextWithUserID(ctx, claims.UserID), nil
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
 }
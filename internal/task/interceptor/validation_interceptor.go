@@ -0,0 +1,96 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/pkg/i18n"
+	"github.com/my-backend-project/internal/pkg/validator"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ValidationInterceptor はリクエストを validator に登録したルールで検証するインターセプターです。
+// 不正な項目がある場合はハンドラーを呼ばずに、すべての項目の違反を google.rpc.BadRequest の詳細に入れた INVALID_ARGUMENT を返します。
+// ErrorInfo の reason は INVALID_REQUEST で、メッセージは accept-language メタデータの言語で返します
+type ValidationInterceptor struct {
+	validator *validator.Validator
+}
+
+func NewValidationInterceptor(validator *validator.Validator) *ValidationInterceptor {
+	return &ValidationInterceptor{
+		validator: validator,
+	}
+}
+
+func (i *ValidationInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := i.validate(ctx, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream はストリーミングRPCで受け取るメッセージを1件ずつ検証するインターセプターです
+func (i *ValidationInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &validatingStream{ServerStream: stream, interceptor: i})
+	}
+}
+
+// validate は req を検証し、不正な項目がある場合は詳細付きのステータスを返します
+func (i *ValidationInterceptor) validate(ctx context.Context, req interface{}) error {
+	lang := languageFromContext(ctx)
+	violations, err := i.validator.Violations(req, lang)
+	if err != nil {
+		// 構造体でないメッセージなど、検証できないものはハンドラーに任せる
+		return nil
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	descriptions := make([]string, len(violations))
+	for n, violation := range violations {
+		descriptions[n] = violation.Message
+	}
+	return apperrors.NewInvalidInputError(strings.Join(descriptions, "; "), nil).
+		WithReason("INVALID_REQUEST").
+		WithViolations(violations...).
+		WithLocale(string(lang)).
+		GRPCStatus().Err()
+}
+
+// languageFromContext は accept-language メタデータからメッセージの言語を選びます
+func languageFromContext(ctx context.Context) i18n.Language {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return i18n.LanguageJa
+	}
+	return i18n.ParseAcceptLanguage(strings.Join(md.Get("accept-language"), ","))
+}
+
+type validatingStream struct {
+	grpc.ServerStream
+	interceptor *ValidationInterceptor
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.interceptor.validate(s.Context(), m)
+}
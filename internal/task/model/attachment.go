@@ -0,0 +1,105 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// AttachmentChunkSize はダウンロード時に1メッセージで送るバイト数です
+	AttachmentChunkSize = 64 * 1024
+	// DefaultMaxAttachmentSize は1ファイルの既定の最大サイズです
+	DefaultMaxAttachmentSize = 25 << 20
+	// DefaultAttachmentQuota はユーザーごとの添付ファイルの合計サイズの既定の上限です
+	DefaultAttachmentQuota = 500 << 20
+	// MaxAttachmentNameLength はファイル名の最大文字数です
+	MaxAttachmentNameLength = 255
+	// defaultAttachmentContentType はコンテンツタイプが指定されていない場合に使う値です
+	defaultAttachmentContentType = "application/octet-stream"
+)
+
+// Attachment はタスクの添付ファイルのメタデータです。ファイルの本体は blob.Store に StorageKey で保存します
+type Attachment struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID      primitive.ObjectID `bson:"task_id"`
+	UserID      string             `bson:"user_id"`
+	Name        string             `bson:"name"`
+	Size        int64              `bson:"size"`
+	ContentType string             `bson:"content_type"`
+	// Checksum はファイルの内容の SHA-256 の16進数表記です
+	Checksum   string    `bson:"checksum"`
+	StorageKey string    `bson:"storage_key"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+// AttachmentUpload はアップロードするファイルの情報です。Size と Checksum は指定された場合のみ受信した内容と照合します
+type AttachmentUpload struct {
+	TaskID      string
+	Name        string
+	ContentType string
+	Size        int64
+	Checksum    string
+}
+
+// Normalize はファイル名の前後の空白を除き、コンテンツタイプの既定値を補います
+func (u *AttachmentUpload) Normalize() {
+	u.Name = strings.TrimSpace(u.Name)
+	u.ContentType = strings.TrimSpace(u.ContentType)
+	if u.ContentType == "" {
+		u.ContentType = defaultAttachmentContentType
+	}
+	u.Checksum = strings.ToLower(strings.TrimSpace(u.Checksum))
+}
+
+// Validate はアップロードするファイルの情報を検証します
+func (u *AttachmentUpload) Validate() error {
+	if u.Name == "" {
+		return errors.New("ファイル名は必須です")
+	}
+	if utf8.RuneCountInString(u.Name) > MaxAttachmentNameLength {
+		return fmt.Errorf("ファイル名は%d文字以内で入力してください", MaxAttachmentNameLength)
+	}
+	// ダウンロード時にファイル名としてそのまま使えるよう、パスの区切りと制御文字を禁止する
+	if strings.ContainsAny(u.Name, `/\`) || strings.IndexFunc(u.Name, unicode.IsControl) >= 0 || u.Name == "." || u.Name == ".." {
+		return errors.New("ファイル名に使用できない文字が含まれています")
+	}
+	if u.Size < 0 {
+		return errors.New("サイズが不正です")
+	}
+	if u.Checksum != "" && !isSHA256Hex(u.Checksum) {
+		return errors.New("チェックサムは SHA-256 の16進数表記で指定してください")
+	}
+	return nil
+}
+
+func isSHA256Hex(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// AttachmentQuota はユーザーの添付ファイルの使用量と上限です
+type AttachmentQuota struct {
+	UsedBytes  int64
+	LimitBytes int64
+}
+
+// Remaining は追加で保存できるバイト数です
+func (q AttachmentQuota) Remaining() int64 {
+	if q.UsedBytes >= q.LimitBytes {
+		return 0
+	}
+	return q.LimitBytes - q.UsedBytes
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxBatchSize は一括操作で一度に扱えるタスクの上限です
+const MaxBatchSize = 500
+
+// TaskSelector は一括操作の対象のタスクです。TaskIDs と Filter のどちらか一方を指定します
+type TaskSelector struct {
+	TaskIDs []string
+	// Filter に一致するタスクのうち、操作したユーザーのものが対象です
+	Filter *TaskFilter
+}
+
+func (s *TaskSelector) Validate() error {
+	if s == nil || (len(s.TaskIDs) == 0 && s.Filter == nil) {
+		return errors.New("対象のタスクを指定してください")
+	}
+	if len(s.TaskIDs) > 0 && s.Filter != nil {
+		return errors.New("タスクIDと絞り込み条件は同時に指定できません")
+	}
+	if len(s.TaskIDs) > MaxBatchSize {
+		return fmt.Errorf("一度に操作できるタスクは%d件までです", MaxBatchSize)
+	}
+	return nil
+}
+
+// TaskPatch は一括更新で変更する項目です。ゼロ値の項目は変更しません
+type TaskPatch struct {
+	Status   TaskStatus
+	Priority *TaskPriority
+	DueDate  *time.Time
+	// DueDay は終日の期限日（YYYY-MM-DD）です。DueDate と同時には指定できません
+	DueDay string
+	// AddLabels は付けるラベル、RemoveLabels は外すラベルです。両方に含まれるラベルは外します
+	AddLabels    []string
+	RemoveLabels []string
+}
+
+func (p *TaskPatch) Validate() error {
+	if p == nil || p.IsEmpty() {
+		return errors.New("変更する項目を指定してください")
+	}
+	if p.Status != "" && !isValidStatus(p.Status) {
+		return errors.New("無効なステータスです")
+	}
+	if p.Priority != nil && (*p.Priority < TaskPriorityNone || *p.Priority > TaskPriorityUrgent) {
+		return errors.New("無効な優先度です")
+	}
+	if p.DueDate != nil && p.DueDate.IsZero() {
+		return errors.New("期限は必須です")
+	}
+	if p.DueDate != nil && p.DueDay != "" {
+		return errors.New("期限の日時と期限日は同時に指定できません")
+	}
+	for _, label := range append(append([]string{}, p.AddLabels...), p.RemoveLabels...) {
+		if err := ValidateLabelName(label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsEmpty は変更する項目がないかを返します
+func (p *TaskPatch) IsEmpty() bool {
+	return p.Status == "" && p.Priority == nil && p.DueDate == nil && p.DueDay == "" && len(p.AddLabels) == 0 && len(p.RemoveLabels) == 0
+}
+
+// Apply は task の複製に変更を適用して返します。ステータスを変える場合はワークフローのステータスを空にし、
+// 組み込みのステータスに対応するワークフローのステータスを改めて選ばせます。
+// 期限の日時を指定すると時刻のあるタスクに、期限日を指定すると終日のタスクにします
+func (p *TaskPatch) Apply(task *Task) (*Task, error) {
+	next := *task
+	if p.Status != "" && p.Status != task.Status {
+		next.Status = p.Status
+		next.WorkflowStatus = ""
+	}
+	if p.Priority != nil {
+		next.Priority = *p.Priority
+	}
+	if p.DueDate != nil {
+		next.DueDate = *p.DueDate
+		next.AllDay = false
+	}
+	if p.DueDay != "" {
+		next.AllDay = true
+		next.DueDay = p.DueDay
+	}
+	if len(p.AddLabels) > 0 || len(p.RemoveLabels) > 0 {
+		remove := make(map[string]bool, len(p.RemoveLabels))
+		for _, label := range p.RemoveLabels {
+			remove[label] = true
+		}
+		labels := make([]string, 0, len(task.Labels)+len(p.AddLabels))
+		seen := make(map[string]bool, cap(labels))
+		for _, label := range append(append([]string{}, task.Labels...), p.AddLabels...) {
+			if remove[label] || seen[label] {
+				continue
+			}
+			seen[label] = true
+			labels = append(labels, label)
+		}
+		next.Labels = labels
+		if err := validateLabels(next.Labels); err != nil {
+			return nil, err
+		}
+	}
+	return &next, nil
+}
+
+// BatchItemResult は一括操作の1件ごとの結果です。Err が nil の場合は成功です
+type BatchItemResult struct {
+	// Index は作成の場合はリクエスト内の位置、更新と削除の場合は対象のタスクの位置です
+	Index  int
+	TaskID primitive.ObjectID
+	// Task は作成・更新後のタスクです。削除の場合と失敗した場合は nil です
+	Task *Task
+	Err  error
+}
+
+// BatchResult は一括操作の結果です
+type BatchResult struct {
+	Items []*BatchItemResult
+}
+
+// FailedCount は失敗した項目の数を返します
+func (r *BatchResult) FailedCount() int {
+	count := 0
+	for _, item := range r.Items {
+		if item.Err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// SucceededCount は成功した項目の数を返します
+func (r *BatchResult) SucceededCount() int {
+	return len(r.Items) - r.FailedCount()
+}
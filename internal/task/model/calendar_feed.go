@@ -0,0 +1,27 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarFeed はユーザーのタスクを iCalendar で配信するフィードです。
+// フィードの URL に含めるトークンは発行時にだけ返し、保存するのはハッシュだけです
+type CalendarFeed struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    string             `bson:"user_id"`
+	TokenHash string             `bson:"token_hash"`
+	CreatedAt time.Time          `bson:"created_at"`
+	// RotatedAt は現在のトークンを発行した日時です
+	RotatedAt time.Time `bson:"rotated_at"`
+}
+
+// HashCalendarFeedToken はフィードのトークンを保存・照合に使うハッシュに変換します。
+// トークンは十分な長さの乱数のため、ソルトを付けない SHA-256 で照合できるようにしています
+func HashCalendarFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
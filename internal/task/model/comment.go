@@ -0,0 +1,142 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxCommentBodyLength はコメント本文（Markdown）の最大文字数です
+	MaxCommentBodyLength = 10000
+	// MaxCommentRevisions は保持する編集履歴の最大件数です。超えた分は古いものから削除します
+	MaxCommentRevisions = 20
+	// MaxMentionsPerComment は1件のコメントでメンションできる最大人数です
+	MaxMentionsPerComment = 20
+	// mentionExcerptLength は通知に含めるコメント本文の最大文字数です
+	mentionExcerptLength = 200
+)
+
+// Comment はタスクへのコメントです。本文は Markdown です
+type Comment struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID   primitive.ObjectID `bson:"task_id"`
+	AuthorID string             `bson:"author_id"`
+	Body     string             `bson:"body"`
+	// Mentions は本文でメンションされたユーザーのIDです
+	Mentions  []string          `bson:"mentions,omitempty"`
+	Revisions []CommentRevision `bson:"revisions,omitempty"`
+	CreatedAt time.Time         `bson:"created_at"`
+	UpdatedAt time.Time         `bson:"updated_at"`
+	// EditedAt は最後に本文を編集した日時です。編集していない場合はゼロ値です
+	EditedAt time.Time `bson:"edited_at,omitempty"`
+}
+
+// CommentRevision は編集前の本文です。EditedAt はこの本文が置き換えられた日時です
+type CommentRevision struct {
+	Body     string    `bson:"body"`
+	EditedAt time.Time `bson:"edited_at"`
+}
+
+// ValidateCommentBody はコメント本文を検証します
+func ValidateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return errors.New("本文は必須です")
+	}
+	if n := utf8.RuneCountInString(body); n > MaxCommentBodyLength {
+		return fmt.Errorf("本文は%d文字以内で入力してください（%d文字）", MaxCommentBodyLength, n)
+	}
+	return nil
+}
+
+// Edit は本文を置き換え、編集前の本文を履歴に残します。本文が変わらない場合は false を返します
+func (c *Comment) Edit(body string, now time.Time) bool {
+	if body == c.Body {
+		return false
+	}
+	c.Revisions = append(c.Revisions, CommentRevision{Body: c.Body, EditedAt: now})
+	if len(c.Revisions) > MaxCommentRevisions {
+		c.Revisions = c.Revisions[len(c.Revisions)-MaxCommentRevisions:]
+	}
+	c.Body = body
+	c.EditedAt = now
+	c.UpdatedAt = now
+	return true
+}
+
+// Excerpt は通知に含める本文の抜粋です
+func (c *Comment) Excerpt() string {
+	body := strings.TrimSpace(c.Body)
+	if utf8.RuneCountInString(body) <= mentionExcerptLength {
+		return body
+	}
+	return string([]rune(body)[:mentionExcerptLength]) + "…"
+}
+
+var (
+	// mentionPattern は "@" に続くメールアドレスまたはユーザーID（ObjectID の16進数表記）です。
+	// 直前が英数字の場合（メールアドレスの一部など）はメンションとみなしません
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}|[0-9a-fA-F]{24})\b`)
+	// codeFencePattern と codeSpanPattern は Markdown のコードです。コード中の "@" はメンションとみなしません
+	codeFencePattern = regexp.MustCompile("(?s)```.*?(?:```|$)")
+	codeSpanPattern  = regexp.MustCompile("`[^`\n]*`")
+)
+
+// ParseMentions は本文からメンションされたメールアドレスとユーザーIDを出現順に重複なく返します。
+// メールアドレスとユーザーIDは小文字に揃えます
+func ParseMentions(body string) []string {
+	body = codeFencePattern.ReplaceAllString(body, "")
+	body = codeSpanPattern.ReplaceAllString(body, "")
+
+	var handles []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		handle := strings.ToLower(match[1])
+		if !seen[handle] {
+			seen[handle] = true
+			handles = append(handles, handle)
+		}
+	}
+	return handles
+}
+
+// IsEmailHandle はメンションがメールアドレスかを返します
+func IsEmailHandle(handle string) bool {
+	return strings.Contains(handle, "@")
+}
+
+// CommentPage はコメントの1ページ分の結果です
+type CommentPage struct {
+	Comments      []*Comment
+	NextPageToken string
+}
+
+// CommentCursor はコメント一覧の前のページの最後のコメントです
+type CommentCursor struct {
+	TaskID primitive.ObjectID `bson:"task_id"`
+	LastID primitive.ObjectID `bson:"last_id"`
+}
+
+// MentionJob はメンションされたユーザーへの通知の送信予定です。状態とリースの扱いは ReminderJob と同じです。
+// TaskTitle はメンションされたユーザーがタスクを見られない場合は空です
+type MentionJob struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	CommentID   primitive.ObjectID `bson:"comment_id"`
+	TaskID      primitive.ObjectID `bson:"task_id"`
+	TaskTitle   string             `bson:"task_title"`
+	UserID      string             `bson:"user_id"`
+	AuthorID    string             `bson:"author_id"`
+	Excerpt     string             `bson:"excerpt"`
+	State       ReminderState      `bson:"state"`
+	AvailableAt time.Time          `bson:"available_at"`
+	LeaseOwner  string             `bson:"lease_owner,omitempty"`
+	Attempts    int                `bson:"attempts"`
+	LastError   string             `bson:"last_error,omitempty"`
+	FinishedAt  time.Time          `bson:"finished_at,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at"`
+}
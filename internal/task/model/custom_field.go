@@ -0,0 +1,180 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldText         CustomFieldType = "text"
+	CustomFieldNumber       CustomFieldType = "number"
+	CustomFieldDate         CustomFieldType = "date"
+	CustomFieldSingleSelect CustomFieldType = "single_select"
+)
+
+const (
+	// MaxCustomFieldOptions は単一選択フィールドの選択肢の上限です
+	MaxCustomFieldOptions = 50
+	// MaxCustomFieldTextLength はテキストフィールドの最大文字数です
+	MaxCustomFieldTextLength = 1000
+)
+
+// CustomFieldDefinition はユーザーまたはチームが定義するカスタムフィールドです。
+// OwnerID には定義の所有者（ユーザーIDまたはチームID）を保持します
+type CustomFieldDefinition struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	OwnerID   string             `bson:"owner_id"`
+	Name      string             `bson:"name"`
+	Type      CustomFieldType    `bson:"type"`
+	Options   []string           `bson:"options,omitempty"`
+	Required  bool               `bson:"required"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+// CustomFieldValue はタスクに設定されたカスタムフィールドの値です。Type に対応する値のみを持ちます
+type CustomFieldValue struct {
+	Type   CustomFieldType `bson:"type"`
+	Text   string          `bson:"text,omitempty"`
+	Number *float64        `bson:"number,omitempty"`
+	Date   *time.Time      `bson:"date,omitempty"`
+	Option string          `bson:"option,omitempty"`
+}
+
+func (d *CustomFieldDefinition) Validate() error {
+	if d.OwnerID == "" {
+		return errors.New("所有者IDは必須です")
+	}
+
+	if d.Name == "" {
+		return errors.New("フィールド名は必須です")
+	}
+
+	if !isValidCustomFieldType(d.Type) {
+		return errors.New("無効なフィールドの種類です")
+	}
+
+	if d.Type == CustomFieldSingleSelect {
+		if len(d.Options) == 0 {
+			return errors.New("選択肢は必須です")
+		}
+		if len(d.Options) > MaxCustomFieldOptions {
+			return errors.New("選択肢が多すぎます")
+		}
+		seen := make(map[string]bool, len(d.Options))
+		for _, option := range d.Options {
+			if option == "" || seen[option] {
+				return errors.New("選択肢が空または重複しています")
+			}
+			seen[option] = true
+		}
+	} else if len(d.Options) > 0 {
+		return errors.New("選択肢は単一選択フィールドにのみ指定できます")
+	}
+
+	return nil
+}
+
+// ValidateValue は値が定義の種類と選択肢に合っているかを検証します
+func (d *CustomFieldDefinition) ValidateValue(value CustomFieldValue) error {
+	if value.Type != d.Type {
+		return fmt.Errorf("%sの値の種類が正しくありません", d.Name)
+	}
+	if err := value.validate(); err != nil {
+		return err
+	}
+	if d.Type == CustomFieldSingleSelect {
+		for _, option := range d.Options {
+			if option == value.Option {
+				return nil
+			}
+		}
+		return fmt.Errorf("%sの選択肢に含まれない値です", d.Name)
+	}
+	return nil
+}
+
+// ValidateCustomFields はタスクの値を所有者の定義と照合し、未定義のフィールドと必須フィールドの欠落を検出します
+func ValidateCustomFields(values map[string]CustomFieldValue, definitions []*CustomFieldDefinition) error {
+	byID := make(map[string]*CustomFieldDefinition, len(definitions))
+	for _, d := range definitions {
+		byID[d.ID.Hex()] = d
+	}
+
+	for id, value := range values {
+		d, ok := byID[id]
+		if !ok {
+			return errors.New("未定義のカスタムフィールドです")
+		}
+		if err := d.ValidateValue(value); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range definitions {
+		if _, ok := values[d.ID.Hex()]; d.Required && !ok {
+			return fmt.Errorf("%sは必須です", d.Name)
+		}
+	}
+
+	return nil
+}
+
+// validate は Type に対応する値が1つだけ設定されているかを検証します
+func (v CustomFieldValue) validate() error {
+	set := 0
+	if v.Text != "" {
+		set++
+	}
+	if v.Number != nil {
+		set++
+	}
+	if v.Date != nil {
+		set++
+	}
+	if v.Option != "" {
+		set++
+	}
+	if set != 1 {
+		return errors.New("カスタムフィールドには値を1つだけ指定してください")
+	}
+
+	switch v.Type {
+	case CustomFieldText:
+		if v.Text == "" {
+			return errors.New("テキストの値が指定されていません")
+		}
+		if len([]rune(v.Text)) > MaxCustomFieldTextLength {
+			return errors.New("テキストの値が長すぎます")
+		}
+	case CustomFieldNumber:
+		if v.Number == nil {
+			return errors.New("数値の値が指定されていません")
+		}
+	case CustomFieldDate:
+		if v.Date == nil {
+			return errors.New("日付の値が指定されていません")
+		}
+	case CustomFieldSingleSelect:
+		if v.Option == "" {
+			return errors.New("選択肢の値が指定されていません")
+		}
+	default:
+		return errors.New("無効なフィールドの種類です")
+	}
+	return nil
+}
+
+func isValidCustomFieldType(t CustomFieldType) bool {
+	switch t {
+	case CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldSingleSelect:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskDependency は「BlockerID のタスクが完了するまで TaskID のタスクを開始できない」という依存関係です
+type TaskDependency struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    string             `bson:"user_id"`
+	TaskID    primitive.ObjectID `bson:"task_id"`
+	BlockerID primitive.ObjectID `bson:"blocker_id"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// NextTask は着手順に並べたタスクと、そのタスクをまだブロックしている未完了のタスクの ID です。
+// OpenBlockerIDs が空のタスクはすぐに着手できます
+type NextTask struct {
+	Task           *Task
+	OpenBlockerIDs []primitive.ObjectID
+}
+
+// IsOpen はタスクが未完了かを返します
+func (t *Task) IsOpen() bool {
+	return t.Status != TaskStatusComplete
+}
+
+// WouldCreateCycle は既存の依存関係に「blockerID が taskID をブロックする」を加えると循環するかを返します。
+// taskID から「ブロックしている先」をたどって blockerID に到達できれば循環です
+func WouldCreateCycle(dependencies []*TaskDependency, taskID, blockerID primitive.ObjectID) bool {
+	if taskID == blockerID {
+		return true
+	}
+
+	blocks := make(map[primitive.ObjectID][]primitive.ObjectID)
+	for _, dep := range dependencies {
+		blocks[dep.BlockerID] = append(blocks[dep.BlockerID], dep.TaskID)
+	}
+
+	visited := map[primitive.ObjectID]bool{taskID: true}
+	queue := []primitive.ObjectID{taskID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range blocks[current] {
+			if next == blockerID {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// OrderByDependencies は未完了のタスクを依存関係のトポロジカル順に並べます。
+// 同時に着手できるタスクの間では、優先度が高い順、期限が近い順（期限なしは最後）、作成日時が古い順に並べます。
+// 完了済みのブロッカーは無視し、循環している場合は循環に含まれるタスクを末尾にまとめて返します
+func OrderByDependencies(tasks []*Task, dependencies []*TaskDependency) []*NextTask {
+	open := make(map[primitive.ObjectID]*Task, len(tasks))
+	for _, task := range tasks {
+		if task.IsOpen() {
+			open[task.ID] = task
+		}
+	}
+
+	blockers := make(map[primitive.ObjectID][]primitive.ObjectID)
+	blocks := make(map[primitive.ObjectID][]primitive.ObjectID)
+	for _, dep := range dependencies {
+		if open[dep.TaskID] == nil || open[dep.BlockerID] == nil {
+			continue
+		}
+		blockers[dep.TaskID] = append(blockers[dep.TaskID], dep.BlockerID)
+		blocks[dep.BlockerID] = append(blocks[dep.BlockerID], dep.TaskID)
+	}
+
+	remaining := make(map[primitive.ObjectID]int, len(open))
+	ready := &taskHeap{}
+	for id, task := range open {
+		remaining[id] = len(blockers[id])
+		if remaining[id] == 0 {
+			heap.Push(ready, task)
+		}
+	}
+
+	result := make([]*NextTask, 0, len(open))
+	for ready.Len() > 0 {
+		task := heap.Pop(ready).(*Task)
+		delete(open, task.ID)
+		result = append(result, &NextTask{Task: task, OpenBlockerIDs: blockers[task.ID]})
+		for _, next := range blocks[task.ID] {
+			remaining[next]--
+			if remaining[next] == 0 {
+				heap.Push(ready, open[next])
+			}
+		}
+	}
+
+	// 循環に含まれるタスクは順序を決められないため、着手順の基準だけで並べる
+	cyclic := &taskHeap{}
+	for _, task := range open {
+		heap.Push(cyclic, task)
+	}
+	for cyclic.Len() > 0 {
+		task := heap.Pop(cyclic).(*Task)
+		result = append(result, &NextTask{Task: task, OpenBlockerIDs: blockers[task.ID]})
+	}
+	return result
+}
+
+// TopByWorkOrder は tasks を着手順の基準で並べ替え、先頭の n 件を返します
+func TopByWorkOrder(tasks []*Task, n int) []*Task {
+	sort.Slice(tasks, func(i, j int) bool { return workOrderLess(tasks[i], tasks[j]) })
+	if len(tasks) > n {
+		tasks = tasks[:n]
+	}
+	return tasks
+}
+
+// workOrderLess は同時に着手できるタスクの並び順です
+func workOrderLess(a, b *Task) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if !a.DueDate.Equal(b.DueDate) {
+		if a.DueDate.IsZero() || b.DueDate.IsZero() {
+			return b.DueDate.IsZero()
+		}
+		return a.DueDate.Before(b.DueDate)
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID.Hex() < b.ID.Hex()
+}
+
+type taskHeap []*Task
+
+func (h taskHeap) Len() int            { return len(h) }
+func (h taskHeap) Less(i, j int) bool  { return workOrderLess(h[i], h[j]) }
+func (h taskHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *taskHeap) Push(x interface{}) { *h = append(*h, x.(*Task)) }
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	task := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return task
+}
+
+// TaskDependencies はタスクをブロックしているタスクと、タスクがブロックしているタスクの一覧です
+type TaskDependencies struct {
+	BlockedBy []*Task
+	Blocks    []*Task
+}
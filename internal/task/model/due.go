@@ -0,0 +1,88 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/my-backend-project/internal/task/recurrence"
+)
+
+// DueDayLayout は終日のタスクの期限日の書式です
+const DueDayLayout = "2006-01-02"
+
+// NormalizeDue は期限に関する項目を検証してそろえます。終日のタスクは DueDay（未設定の場合は DueDate の日付）から
+// DueDate と DueEnd を求め、時刻のあるタスクは DueDay と DueEnd を消します。
+// userLoc は TimeZone を指定していない終日のタスクの期限日を区切るユーザーのタイムゾーンで、nil の場合は UTC です
+func (t *Task) NormalizeDue(userLoc *time.Location) error {
+	loc := userLoc
+	if loc == nil {
+		loc = time.UTC
+	}
+	if t.TimeZone != "" {
+		taskLoc, err := recurrence.LoadLocation(t.TimeZone)
+		if err != nil {
+			return err
+		}
+		loc = taskLoc
+	}
+
+	if !t.AllDay {
+		t.DueDay = ""
+		t.DueEnd = time.Time{}
+		return nil
+	}
+	if t.DueDay == "" {
+		if t.DueDate.IsZero() {
+			return errors.New("終日のタスクには期限日が必要です")
+		}
+		t.DueDay = t.DueDate.In(loc).Format(DueDayLayout)
+	}
+	day, err := time.ParseInLocation(DueDayLayout, t.DueDay, loc)
+	if err != nil {
+		return fmt.Errorf("期限日 %q が不正です", t.DueDay)
+	}
+	// 夏時間の切り替えがある日は1日が24時間ではないため、翌日は日付で進める
+	t.DueDate = day
+	t.DueEnd = time.Time{}
+	if t.TimeZone != "" {
+		t.DueEnd = day.AddDate(0, 0, 1)
+	}
+	return nil
+}
+
+// IsOverdue は now の時点で期限を過ぎた未完了のタスクかを返します。now のタイムゾーンをユーザーのタイムゾーンとし、
+// TimeZone を指定していない終日のタスクは期限日が今日より前の場合、指定した終日のタスクは DueEnd を過ぎた場合に期限切れです
+func (t *Task) IsOverdue(now time.Time) bool {
+	if t.Status == TaskStatusComplete {
+		return false
+	}
+	switch {
+	case t.AllDay && t.TimeZone == "":
+		return t.DueDay != "" && t.DueDay < now.Format(DueDayLayout)
+	case t.AllDay:
+		return !t.DueEnd.IsZero() && !now.Before(t.DueEnd)
+	default:
+		return !t.DueDate.IsZero() && t.DueDate.Before(now)
+	}
+}
+
+// MatchesDuePreset は定型の期限条件に一致するかを返します。now のタイムゾーンをユーザーのタイムゾーンとし、
+// 終日のタスクは期限日がユーザーのタイムゾーンでの期間の日付に含まれるかで判定します。
+// リポジトリのクエリと同じ条件のインメモリの実装です
+func (t *Task) MatchesDuePreset(preset DueDatePreset, now time.Time) bool {
+	if preset == DueDateOverdue {
+		return t.IsOverdue(now)
+	}
+	r := DuePresetRange(preset, now)
+	if t.AllDay {
+		start, end := r.Days()
+		return t.DueDay >= start && t.DueDay < end
+	}
+	return !t.DueDate.Before(r.Start) && t.DueDate.Before(r.End)
+}
+
+// Days は範囲の両端をそれぞれのタイムゾーンでの日付にして返します。終日のタスクの期限日との比較に使います
+func (r TimeRange) Days() (string, string) {
+	return r.Start.Format(DueDayLayout), r.End.Format(DueDayLayout)
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxImportErrors は取り込みの結果に含める行のエラーの上限です。超えた分は件数だけを数えます
+	MaxImportErrors = 1000
+	// MaxImportJobIDLength は取り込みジョブIDの最大文字数です
+	MaxImportJobIDLength = 64
+)
+
+var importJobIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidateImportJobID は取り込みジョブIDが英数字・ハイフン・アンダースコアだけからなり、最大文字数以内かを検証します
+func ValidateImportJobID(id string) error {
+	if id == "" {
+		return errors.New("取り込みジョブIDは必須です")
+	}
+	if len(id) > MaxImportJobIDLength {
+		return errors.New("取り込みジョブIDが長すぎます")
+	}
+	if !importJobIDPattern.MatchString(id) {
+		return errors.New("取り込みジョブIDに使用できない文字が含まれています")
+	}
+	return nil
+}
+
+type ImportJobStatus string
+
+const (
+	ImportJobRunning   ImportJobStatus = "running"
+	ImportJobCompleted ImportJobStatus = "completed"
+)
+
+// ImportJob はタスクの取り込みの進捗です。書き込みを終えた最後の行番号を記録し、
+// 同じジョブIDで取り込みを再開したときにそれ以前の行を読み飛ばします
+type ImportJob struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	JobID     string             `bson:"job_id"`
+	UserID    string             `bson:"user_id"`
+	Status    ImportJobStatus    `bson:"status"`
+	LastRow   int64              `bson:"last_row"`
+	Imported  int64              `bson:"imported"`
+	Failed    int64              `bson:"failed"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+// ImportOptions は取り込みの設定です
+type ImportOptions struct {
+	JobID string
+	// DryRun の場合は検証だけを行い、タスクもジョブの進捗も書き込みません
+	DryRun bool
+	// BatchSize は一度に書き込む行数です。0 の場合は MaxBatchSize です
+	BatchSize int
+}
+
+func (o *ImportOptions) Validate() error {
+	if err := ValidateImportJobID(o.JobID); err != nil {
+		return err
+	}
+	if o.BatchSize < 0 || o.BatchSize > MaxBatchSize {
+		return errors.New("バッチサイズが不正です")
+	}
+	return nil
+}
+
+// EffectiveBatchSize は一度に書き込む行数を返します
+func (o *ImportOptions) EffectiveBatchSize() int {
+	if o.BatchSize == 0 {
+		return MaxBatchSize
+	}
+	return o.BatchSize
+}
+
+// ImportRow は取り込む1行です。Number は元のファイルでの行番号で、ジョブの中で昇順に送ります。
+// 読み取りや変換に失敗した行は Task を nil にし、Err に理由を設定します
+type ImportRow struct {
+	Number int64
+	Task   *Task
+	Err    error
+}
+
+// ImportRowError は取り込めなかった行とその理由です
+type ImportRowError struct {
+	Row     int64
+	Message string
+}
+
+// ImportResult は1回の取り込みの結果です
+type ImportResult struct {
+	JobID  string
+	DryRun bool
+	// Received は受け取った行数、Skipped はそのうち前回までに取り込み済みのため読み飛ばした行数です
+	Received int64
+	Skipped  int64
+	Imported int64
+	Failed   int64
+	// LastRow は書き込みを終えた最後の行番号です。再開するときはこの次の行から送ります
+	LastRow int64
+	Errors  []ImportRowError
+}
+
+// AddError は行のエラーを記録します。message はクライアントに返すメッセージです。記録するのは MaxImportErrors 件までです
+func (r *ImportResult) AddError(row int64, message string) {
+	r.Failed++
+	if len(r.Errors) < MaxImportErrors {
+		r.Errors = append(r.Errors, ImportRowError{Row: row, Message: message})
+	}
+}
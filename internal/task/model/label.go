@@ -0,0 +1,37 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Label はユーザーごとに管理するラベルです。タスクにはラベル名で付与します
+type Label struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    string             `bson:"user_id"`
+	Name      string             `bson:"name"`
+	Color     string             `bson:"color"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+func (l *Label) Validate() error {
+	if l.UserID == "" {
+		return errors.New("ユーザーIDは必須です")
+	}
+
+	if err := ValidateLabelName(l.Name); err != nil {
+		return err
+	}
+
+	if l.Color != "" && !labelColorPattern.MatchString(l.Color) {
+		return errors.New("色は #RRGGBB 形式で指定してください")
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxProjectNameLength はプロジェクト名の最大文字数です
+	MaxProjectNameLength = 100
+	// MaxProjectDescriptionLength はプロジェクトの説明の最大文字数です
+	MaxProjectDescriptionLength = 2000
+)
+
+// Project はタスクをまとめるユーザーごとのプロジェクトです。どのプロジェクトにも属さないタスクはインボックスにあるものとして扱います
+type Project struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	UserID      string             `bson:"user_id"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description"`
+	Color       string             `bson:"color"`
+	// Archived はアーカイブしたかです。アーカイブしたプロジェクトのタスクは、プロジェクトを指定しない限り一覧に表示しません
+	Archived   bool      `bson:"archived"`
+	ArchivedAt time.Time `bson:"archived_at,omitempty"`
+	CreatedAt  time.Time `bson:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+}
+
+func (p *Project) Validate() error {
+	if p.UserID == "" {
+		return errors.New("ユーザーIDは必須です")
+	}
+
+	if p.Name == "" {
+		return errors.New("プロジェクト名は必須です")
+	}
+	if utf8.RuneCountInString(p.Name) > MaxProjectNameLength {
+		return errors.New("プロジェクト名が長すぎます")
+	}
+
+	if utf8.RuneCountInString(p.Description) > MaxProjectDescriptionLength {
+		return errors.New("プロジェクトの説明が長すぎます")
+	}
+
+	if p.Color != "" && !labelColorPattern.MatchString(p.Color) {
+		return errors.New("色は #RRGGBB 形式で指定してください")
+	}
+
+	return nil
+}
+
+// ProjectDeleteMode はプロジェクトを削除するときのタスクの扱いです
+type ProjectDeleteMode string
+
+const (
+	// ProjectDeleteMoveToInbox はプロジェクトのタスクをインボックスへ移します
+	ProjectDeleteMoveToInbox ProjectDeleteMode = "move_to_inbox"
+	// ProjectDeleteCascade はプロジェクトのタスクも削除します
+	ProjectDeleteCascade ProjectDeleteMode = "cascade"
+)
+
+// HasProject はタスクがプロジェクトに属しているかを返します
+func (t *Task) HasProject() bool {
+	return !t.ProjectID.IsZero()
+}
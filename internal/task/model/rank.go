@@ -0,0 +1,128 @@
+package model
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ランクはボード上の並び順を表す文字列で、辞書順に比較します。
+// 2つのランクの間には常に別のランクを作れるため、移動したタスクのランクだけを書き換えれば並び順を変えられます。
+// ランクはユーザーのタスク全体で一意にし、列（ワークフローのステータス）の中の並び順はその部分列として決まります
+const (
+	// rankDigits はランクに使う文字で、ASCII の順に並べています
+	rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	rankBase   = len(rankDigits)
+	// rankMidDigit は上限のない位置に置く中間の文字です
+	rankMidDigit = "V"
+	// AppendRankPrefix と AppendRankSuffix は作成したタスクのランクの前後に付ける文字です。並べ直したランクは AppendRankPrefix より前に置きます
+	AppendRankPrefix = "z"
+	AppendRankSuffix = rankMidDigit
+	// MaxRankLength はランクの最大の長さです。これより長くなる場合はユーザーのランクを並べ直します
+	MaxRankLength = 64
+	// maxRebalanceWidth は並べ直したランクの最大の長さです
+	maxRebalanceWidth = 10
+)
+
+// BoardMove はボード上でタスクを移動する先です。
+// BeforeID は移動後に直前に来るタスク、AfterID は直後に来るタスクです。どちらも空の場合は列の末尾に置きます
+type BoardMove struct {
+	WorkflowStatus string
+	BeforeID       string
+	AfterID        string
+}
+
+// AppendRank は作成したタスクのランクを返します。
+// ObjectID は作成した順に大きくなるため、作成したタスクは作成順に並び、並べ直したどのランクよりも後ろになります
+func AppendRank(id primitive.ObjectID) string {
+	return AppendRankPrefix + id.Hex() + AppendRankSuffix
+}
+
+// RankBetween は a より後ろで b より前のランクを返します。a が空の場合は先頭、b が空の場合は末尾として扱います。
+// b が空の場合は a の直後に置き、後から作成したタスクが a との間に入らないようにします。
+// 間にランクを作れない場合は false を返します
+func RankBetween(a, b string) (string, bool) {
+	if b == "" {
+		return a + rankMidDigit, true
+	}
+	if a >= b {
+		return "", false
+	}
+
+	var out []byte
+	for i := 0; i < len(b); i++ {
+		da := 0
+		if i < len(a) {
+			da = strings.IndexByte(rankDigits, a[i])
+		}
+		db := strings.IndexByte(rankDigits, b[i])
+		if da == db {
+			out = append(out, b[i])
+			continue
+		}
+		if db-da > 1 {
+			return string(append(out, rankDigits[(da+db+1)/2])), true
+		}
+		// 隣り合う文字の場合は、b がまだ続くなら b の途中まで、続かないなら a の残りより後ろの値にする
+		if i+1 < len(b) {
+			return b[:i+1], true
+		}
+		out = append(out, rankDigits[da])
+		var rest string
+		if i+1 < len(a) {
+			rest = a[i+1:]
+		}
+		return string(out) + rankAfter(rest), true
+	}
+	// b は a の末尾に最小の文字を付けただけなので間に置けない
+	return "", false
+}
+
+// rankAfter は s より後ろで、s の先頭の文字を大きくしない範囲のランクを返します
+func rankAfter(s string) string {
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(rankDigits, s[i])
+		if d < rankBase-1 {
+			return s[:i] + string(rankDigits[(d+rankBase+1)/2])
+		}
+	}
+	return s + rankMidDigit
+}
+
+// RebalanceRanks は n 件のタスクに等間隔の短いランクを先頭から順に割り当てます。
+// 並べ直しの途中で一意制約に違反しないよう、taken に含まれるランクは使いません
+func RebalanceRanks(n int, taken map[string]bool) []string {
+	if n == 0 {
+		return nil
+	}
+	// 各ランクの候補の範囲に taken のすべてが入っても空きが残るよう、間隔を n+len(taken)+1 以上にする
+	need := int64(n + len(taken) + 1)
+	width := 1
+	space := int64(rankBase - 1)
+	for space/int64(n+1) < need && width < maxRebalanceWidth {
+		width++
+		space *= int64(rankBase)
+	}
+	step := space / int64(n+1)
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		v := int64(i+1) * step
+		rank := encodeRank(v, width)
+		for j := int64(1); taken[rank] && j < step; j++ {
+			rank = encodeRank(v+j, width)
+		}
+		ranks[i] = rank
+	}
+	return ranks
+}
+
+// encodeRank は v を width 桁のランクにし、末尾の最小の文字を取り除きます。取り除いても大小関係は変わりません
+func encodeRank(v int64, width int) string {
+	b := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		b[i] = rankDigits[v%int64(rankBase)]
+		v /= int64(rankBase)
+	}
+	return strings.TrimRight(string(b), rankDigits[:1])
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/my-backend-project/internal/task/recurrence"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxRecurrencePreview は繰り返しの発生日時をプレビューできる件数の上限です
+const MaxRecurrencePreview = 100
+
+// Recurrence はタスクの繰り返しの設定です。RRule は RFC 5545 の RRULE で、TimeZone の壁時計で展開します
+type Recurrence struct {
+	RRule    string `bson:"rrule"`
+	TimeZone string `bson:"time_zone"`
+	// Start は繰り返しの起点です。COUNT はこの日時から数えます
+	Start time.Time `bson:"start"`
+	// NextTaskID は完了時に生成した次の回のタスクです。完了の取り消しと再完了で二重に生成しないために使います
+	NextTaskID primitive.ObjectID `bson:"next_task_id,omitempty"`
+}
+
+// Validate は RRULE とタイムゾーンを検証します
+func (r *Recurrence) Validate() error {
+	_, _, err := r.parse()
+	return err
+}
+
+// Next は after より後の次の発生日時を返します。繰り返しが終わっている場合は false を返します
+func (r *Recurrence) Next(after time.Time) (time.Time, bool, error) {
+	rule, loc, err := r.parse()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	next, ok := rule.Next(r.Start.In(loc), after)
+	return next, ok, nil
+}
+
+// Preview は after より後の発生日時を最大 limit 件返します
+func (r *Recurrence) Preview(after time.Time, limit int) ([]time.Time, error) {
+	rule, loc, err := r.parse()
+	if err != nil {
+		return nil, err
+	}
+	return rule.Occurrences(r.Start.In(loc), after, limit), nil
+}
+
+func (r *Recurrence) parse() (*recurrence.Rule, *time.Location, error) {
+	rule, err := recurrence.Parse(r.RRule)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := recurrence.LoadLocation(r.TimeZone)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.Start.IsZero() {
+		return nil, nil, errors.New("繰り返しの起点は必須です")
+	}
+	return rule, loc, nil
+}
+
+// IsRecurring は繰り返しが設定されているかを返します
+func (t *Task) IsRecurring() bool {
+	return t.Recurrence != nil
+}
+
+// NextOccurrence は繰り返しの次の回のタスクを返します。期限は次の発生日時で、
+// 内容・優先度・ラベル・カスタムフィールド・リマインダー・親タスクを引き継ぎ、チェックリストは未完了に戻します
+func (t *Task) NextOccurrence(due time.Time) *Task {
+	next := &Task{
+		UserID:       t.UserID,
+		ParentID:     t.ParentID,
+		Title:        t.Title,
+		Description:  t.Description,
+		Priority:     t.Priority,
+		Labels:       t.Labels,
+		CustomFields: t.CustomFields,
+		DueDate:      due,
+		AllDay:       t.AllDay,
+		TimeZone:     t.TimeZone,
+		Recurrence: &Recurrence{
+			RRule:    t.Recurrence.RRule,
+			TimeZone: t.Recurrence.TimeZone,
+			Start:    t.Recurrence.Start,
+		},
+	}
+	for _, item := range t.Checklist {
+		next.Checklist = append(next.Checklist, ChecklistItem{Text: item.Text})
+	}
+	for _, r := range t.Reminders {
+		next.Reminders = append(next.Reminders, Reminder{MinutesBefore: r.MinutesBefore, Channels: r.Channels})
+	}
+	return next
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationChannel は通知の送信先の種類です
+type NotificationChannel string
+
+const (
+	ChannelEmail   NotificationChannel = "email"
+	ChannelWebhook NotificationChannel = "webhook"
+	ChannelInbox   NotificationChannel = "inbox"
+)
+
+const (
+	// MaxRemindersPerTask は1つのタスクに設定できるリマインダーの上限です
+	MaxRemindersPerTask = 5
+	// MaxReminderMinutesBefore はリマインダーを期限の何分前まで設定できるかの上限（4週間）です
+	MaxReminderMinutesBefore = 4 * 7 * 24 * 60
+)
+
+// Reminder はタスクの期限の MinutesBefore 分前に通知する設定です
+type Reminder struct {
+	ID            string `bson:"id"`
+	MinutesBefore int    `bson:"minutes_before"`
+	// Channels は通知先です。空の場合は通知ワーカーの既定の通知先に送ります
+	Channels []NotificationChannel `bson:"channels,omitempty"`
+}
+
+// ValidateReminders はリマインダーの件数・時間・通知先と ID の重複を検証します
+func ValidateReminders(reminders []Reminder) error {
+	if len(reminders) > MaxRemindersPerTask {
+		return errors.New("リマインダーが多すぎます")
+	}
+	seen := make(map[string]bool, len(reminders))
+	for _, r := range reminders {
+		if r.MinutesBefore < 0 || r.MinutesBefore > MaxReminderMinutesBefore {
+			return errors.New("リマインダーの時間が不正です")
+		}
+		for _, channel := range r.Channels {
+			if !isValidChannel(channel) {
+				return fmt.Errorf("通知先 %q は使用できません", channel)
+			}
+		}
+		if r.ID != "" {
+			if seen[r.ID] {
+				return errors.New("リマインダーIDが重複しています")
+			}
+			seen[r.ID] = true
+		}
+	}
+	return nil
+}
+
+func isValidChannel(channel NotificationChannel) bool {
+	switch channel {
+	case ChannelEmail, ChannelWebhook, ChannelInbox:
+		return true
+	default:
+		return false
+	}
+}
+
+// AssignReminderIDs は ID のないリマインダーに ID を割り当てます
+func (t *Task) AssignReminderIDs() {
+	for i := range t.Reminders {
+		if t.Reminders[i].ID == "" {
+			t.Reminders[i].ID = primitive.NewObjectID().Hex()
+		}
+	}
+}
+
+// ReminderJobs はタスクのリマインダーから送信予定の通知を作ります。
+// 完了したタスクと期限を過ぎたタスクには作りません。通知時刻を過ぎていても期限前であれば作り、すぐに送信します
+func (t *Task) ReminderJobs(now time.Time) []*ReminderJob {
+	if t.Status == TaskStatusComplete || t.DueDate.IsZero() || !t.DueDate.After(now) {
+		return nil
+	}
+	jobs := make([]*ReminderJob, 0, len(t.Reminders))
+	for _, r := range t.Reminders {
+		fireAt := t.DueDate.Add(-time.Duration(r.MinutesBefore) * time.Minute)
+		jobs = append(jobs, &ReminderJob{
+			Key:         reminderJobKey(r, fireAt),
+			TaskID:      t.ID,
+			UserID:      t.UserID,
+			ReminderID:  r.ID,
+			Channels:    r.Channels,
+			FireAt:      fireAt,
+			DueDate:     t.DueDate,
+			State:       ReminderStatePending,
+			AvailableAt: fireAt,
+		})
+	}
+	return jobs
+}
+
+// reminderJobKey は通知の同一性を表すキーです。リマインダー・通知時刻・通知先のいずれかが変わると別の通知になります
+func reminderJobKey(r Reminder, fireAt time.Time) string {
+	channels := make([]string, len(r.Channels))
+	for i, c := range r.Channels {
+		channels[i] = string(c)
+	}
+	sort.Strings(channels)
+	return fmt.Sprintf("%s@%s/%s", r.ID, fireAt.UTC().Format(time.RFC3339), strings.Join(channels, ","))
+}
+
+// ReminderState は送信予定の通知の状態です
+type ReminderState string
+
+const (
+	ReminderStatePending ReminderState = "pending"
+	ReminderStateSent    ReminderState = "sent"
+	ReminderStateFailed  ReminderState = "failed"
+	// ReminderStateCancelled は送信前にタスクが完了・削除されたため送らなかった状態です
+	ReminderStateCancelled ReminderState = "cancelled"
+)
+
+// ReminderJob はリマインダーの送信予定です。通知ワーカーはリースを取得してから送信し、
+// 送信済みの通知先を Delivered に記録することで再起動や複数のレプリカでも二重に送らないようにします
+type ReminderJob struct {
+	ID         primitive.ObjectID    `bson:"_id,omitempty"`
+	Key        string                `bson:"key"`
+	TaskID     primitive.ObjectID    `bson:"task_id"`
+	UserID     string                `bson:"user_id"`
+	ReminderID string                `bson:"reminder_id"`
+	Channels   []NotificationChannel `bson:"channels,omitempty"`
+	FireAt     time.Time             `bson:"fire_at"`
+	DueDate    time.Time             `bson:"due_date"`
+	State      ReminderState         `bson:"state"`
+	// AvailableAt は次に取得できる日時です。リース中はリースの期限、再送待ちは再送の日時になります
+	AvailableAt time.Time             `bson:"available_at"`
+	LeaseOwner  string                `bson:"lease_owner,omitempty"`
+	Attempts    int                   `bson:"attempts"`
+	Delivered   []NotificationChannel `bson:"delivered,omitempty"`
+	LastError   string                `bson:"last_error,omitempty"`
+	FinishedAt  time.Time             `bson:"finished_at,omitempty"`
+	CreatedAt   time.Time             `bson:"created_at"`
+}
+
+// IsDelivered は通知先へ送信済みかを返します
+func (j *ReminderJob) IsDelivered(channel NotificationChannel) bool {
+	for _, c := range j.Delivered {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// 通知のイベント名です。ユーザーサービスの通知の設定でイベントごとに受け取るかを選べます
+const (
+	// EventDueSoon は期限が近づいたことを知らせる通知です
+	EventDueSoon = "due_soon"
+	// EventMentioned はコメントでメンションされたことを知らせる通知です
+	EventMentioned = "mentioned"
+)
+
+// Notification は通知先へ送る内容です。DeliveryKey は送信予定と通知先ごとに一意で、受信側の重複排除に使えます
+type Notification struct {
+	DeliveryKey string
+	UserID      string
+	TaskID      primitive.ObjectID
+	Channel     NotificationChannel
+	Event       string
+	Title       string
+	Body        string
+	DueDate     time.Time
+	CreatedAt   time.Time
+}
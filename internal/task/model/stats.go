@@ -0,0 +1,144 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// MaxStatsDays は統計の集計期間の最大日数です
+	MaxStatsDays = 366
+	// DefaultStatsDays は集計期間を指定しない場合に今日までさかのぼる日数です
+	DefaultStatsDays = 30
+	// StatsDateLayout は集計期間の日付と日ごとの件数の日付の書式です
+	StatsDateLayout = "2006-01-02"
+)
+
+// TaskStatsQuery は統計の集計期間です。Start から End の直前までを Location の暦の日ごとに集計します
+type TaskStatsQuery struct {
+	Start    time.Time
+	End      time.Time
+	Location *time.Location
+}
+
+// NewTaskStatsQuery は from から to まで（両端を含む）の日付を loc の暦で解釈した集計期間を返します。
+// from を省略した場合は to の DefaultStatsDays-1 日前、to を省略した場合は now の日付です
+func NewTaskStatsQuery(from, to string, loc *time.Location, now time.Time) (TaskStatsQuery, error) {
+	end := truncateToDay(now.In(loc))
+	if to != "" {
+		d, err := time.ParseInLocation(StatsDateLayout, to, loc)
+		if err != nil {
+			return TaskStatsQuery{}, errors.New("集計期間の終了日は YYYY-MM-DD で指定してください")
+		}
+		end = d
+	}
+	start := end.AddDate(0, 0, -(DefaultStatsDays - 1))
+	if from != "" {
+		d, err := time.ParseInLocation(StatsDateLayout, from, loc)
+		if err != nil {
+			return TaskStatsQuery{}, errors.New("集計期間の開始日は YYYY-MM-DD で指定してください")
+		}
+		start = d
+	}
+
+	q := TaskStatsQuery{Start: start, End: end.AddDate(0, 0, 1), Location: loc}
+	if !q.Start.Before(q.End) {
+		return TaskStatsQuery{}, errors.New("集計期間の開始日は終了日以前にしてください")
+	}
+	if len(q.Days()) > MaxStatsDays {
+		return TaskStatsQuery{}, errors.New("集計期間が長すぎます")
+	}
+	return q, nil
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Days は集計期間の日付を StatsDateLayout の書式で古い順に返します
+func (q TaskStatsQuery) Days() []string {
+	var days []string
+	for d := q.Start.In(q.Location); d.Before(q.End); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(StatsDateLayout))
+	}
+	return days
+}
+
+// Contains は t が集計期間に含まれるかを返します
+func (q TaskStatsQuery) Contains(t time.Time) bool {
+	return !t.Before(q.Start) && t.Before(q.End)
+}
+
+// DailyCount は1日の件数です
+type DailyCount struct {
+	Date  string
+	Count int64
+}
+
+// TaskStats はユーザーのタスクの統計です。
+// 件数は集計期間に関係なく現在のタスクを数え、完了率・平均リードタイム・日ごとの完了件数は集計期間について求めます
+type TaskStats struct {
+	CountsByStatus map[TaskStatus]int64
+	Total          int64
+	// Overdue は期限を過ぎた未完了のタスクの件数です
+	Overdue int64
+	// Created は集計期間に作成したタスクの件数で、CreatedCompleted はそのうち現在完了しているタスクの件数です
+	Created          int64
+	CreatedCompleted int64
+	// Completed は集計期間に完了したタスクの件数です。完了日時を記録する前に完了したタスクは含みません
+	Completed int64
+	// AverageLeadTime は集計期間に完了したタスクの作成から完了までの平均です
+	AverageLeadTime time.Duration
+	// DailyCompletions は集計期間の日ごとの完了件数です。完了がない日も 0 件として含みます
+	DailyCompletions []DailyCount
+}
+
+// CompletionRate は集計期間に作成したタスクのうち完了している割合を返します。作成したタスクがない場合は 0 です
+func (s *TaskStats) CompletionRate() float64 {
+	if s.Created == 0 {
+		return 0
+	}
+	return float64(s.CreatedCompleted) / float64(s.Created)
+}
+
+// FillDailyCompletions は日付ごとの完了件数を集計期間のすべての日の一覧にします
+func (q TaskStatsQuery) FillDailyCompletions(counts map[string]int64) []DailyCount {
+	days := q.Days()
+	result := make([]DailyCount, len(days))
+	for i, day := range days {
+		result[i] = DailyCount{Date: day, Count: counts[day]}
+	}
+	return result
+}
+
+// ComputeTaskStats は1人のユーザーのタスクから統計を求めます。
+// リポジトリの集計パイプラインと同じ結果になるインメモリの実装で、テストなどで使います
+func ComputeTaskStats(tasks []*Task, q TaskStatsQuery, now time.Time) *TaskStats {
+	stats := &TaskStats{CountsByStatus: make(map[TaskStatus]int64)}
+	daily := make(map[string]int64)
+	var leadTime time.Duration
+	for _, task := range tasks {
+		stats.Total++
+		stats.CountsByStatus[task.Status]++
+		complete := task.Status == TaskStatusComplete
+		if task.IsOverdue(now.In(q.Location)) {
+			stats.Overdue++
+		}
+		if q.Contains(task.CreatedAt) {
+			stats.Created++
+			if complete {
+				stats.CreatedCompleted++
+			}
+		}
+		if complete && q.Contains(task.CompletedAt) {
+			stats.Completed++
+			leadTime += task.CompletedAt.Sub(task.CreatedAt)
+			daily[task.CompletedAt.In(q.Location).Format(StatsDateLayout)]++
+		}
+	}
+	if stats.Completed > 0 {
+		stats.AverageLeadTime = leadTime / time.Duration(stats.Completed)
+	}
+	stats.DailyCompletions = q.FillDailyCompletions(daily)
+	return stats
+}
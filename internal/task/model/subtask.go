@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxSubtaskDepth はルートのタスクを1段目として数えたサブタスクの最大階層数です
+	MaxSubtaskDepth = 5
+	// MaxChecklistItems は1つのタスクに付けられるチェックリスト項目の上限です
+	MaxChecklistItems = 100
+	// MaxChecklistItemLength はチェックリスト項目の最大文字数です
+	MaxChecklistItemLength = 200
+)
+
+// ChecklistItem はタスク内の軽量なチェック項目です。サブタスクと違い、個別の期限やステータスは持ちません
+type ChecklistItem struct {
+	ID   string `bson:"id"`
+	Text string `bson:"text"`
+	Done bool   `bson:"done"`
+}
+
+// SubtaskCount は直下のサブタスクの件数と、そのうち完了済みの件数です
+type SubtaskCount struct {
+	Total     int32 `bson:"total"`
+	Completed int32 `bson:"completed"`
+}
+
+// TaskProgress はタスクの進捗（n 件中 m 件完了）です。保存はせず、取得時に集計します
+type TaskProgress struct {
+	Subtasks  SubtaskCount
+	Checklist SubtaskCount
+}
+
+// HasOpenSubtasks は未完了のサブタスクがあるかを返します
+func (p *TaskProgress) HasOpenSubtasks() bool {
+	return p != nil && p.Subtasks.Completed < p.Subtasks.Total
+}
+
+// HasParent はタスクが他のタスクのサブタスクかを返します
+func (t *Task) HasParent() bool {
+	return !t.ParentID.IsZero()
+}
+
+// ApplyProgress は直下のサブタスクの件数とチェックリストから進捗を設定します
+func (t *Task) ApplyProgress(subtasks SubtaskCount) {
+	progress := &TaskProgress{Subtasks: subtasks}
+	for _, item := range t.Checklist {
+		progress.Checklist.Total++
+		if item.Done {
+			progress.Checklist.Completed++
+		}
+	}
+	t.Progress = progress
+}
+
+// AssignChecklistIDs は ID が空のチェックリスト項目に新しい ID を割り当てます
+func (t *Task) AssignChecklistIDs() {
+	for i := range t.Checklist {
+		if t.Checklist[i].ID == "" {
+			t.Checklist[i].ID = primitive.NewObjectID().Hex()
+		}
+	}
+}
+
+// ValidateChecklist はチェックリストの件数・文字数と項目IDの重複を検証します
+func ValidateChecklist(items []ChecklistItem) error {
+	if len(items) > MaxChecklistItems {
+		return errors.New("チェックリストの項目が多すぎます")
+	}
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if strings.TrimSpace(item.Text) == "" {
+			return errors.New("チェックリストの項目は必須です")
+		}
+		if utf8.RuneCountInString(item.Text) > MaxChecklistItemLength {
+			return errors.New("チェックリストの項目が長すぎます")
+		}
+		if item.ID != "" {
+			if seen[item.ID] {
+				return errors.New("チェックリストの項目IDが重複しています")
+			}
+			seen[item.ID] = true
+		}
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package model
 
 import (
 	"errors"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -16,15 +18,57 @@ const (
 	TaskStatusComplete TaskStatus = "TASK_STATUS_COMPLETE"
 )
 
+// TaskPriority はタスクの優先度です。並び替えに使えるよう数値で保持します
+type TaskPriority int
+
+const (
+	TaskPriorityNone TaskPriority = iota
+	TaskPriorityLow
+	TaskPriorityMedium
+	TaskPriorityHigh
+	TaskPriorityUrgent
+)
+
+const (
+	// MaxLabelsPerTask は1つのタスクに付けられるラベルの上限です
+	MaxLabelsPerTask = 20
+	// MaxLabelNameLength はラベル名の最大文字数です
+	MaxLabelNameLength = 50
+)
+
 type Task struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty"`
-	UserID      string             `bson:"user_id"`
-	Title       string             `bson:"title"`
-	Description string             `bson:"description"`
-	Status      TaskStatus         `bson:"status"`
-	DueDate     time.Time          `bson:"due_date"`
-	CreatedAt   time.Time          `bson:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at"`
+	ID             primitive.ObjectID          `bson:"_id,omitempty"`
+	UserID         string                      `bson:"user_id"`
+	ParentID       primitive.ObjectID          `bson:"parent_id,omitempty"`
+	ProjectID      primitive.ObjectID          `bson:"project_id,omitempty"`
+	Title          string                      `bson:"title"`
+	Description    string                      `bson:"description"`
+	Status         TaskStatus                  `bson:"status"`
+	WorkflowStatus string                      `bson:"workflow_status,omitempty"`
+	Priority       TaskPriority                `bson:"priority"`
+	Labels         []string                    `bson:"labels,omitempty"`
+	CustomFields   map[string]CustomFieldValue `bson:"custom_fields,omitempty"`
+	Checklist      []ChecklistItem             `bson:"checklist,omitempty"`
+	// DueDate は期限の日時です。終日のタスクでは期限日の0時（TimeZone、未設定の場合はユーザーのタイムゾーン）です
+	DueDate time.Time `bson:"due_date"`
+	// AllDay は時刻のない期限日だけのタスクかで、DueDay が期限日です
+	AllDay bool   `bson:"all_day,omitempty"`
+	DueDay string `bson:"due_day,omitempty"`
+	// TimeZone はタスクのタイムゾーン（IANA）です。終日のタスクでは期限日を区切るタイムゾーンで、
+	// 未設定の場合は期限日はユーザーのタイムゾーンでの日付として扱います
+	TimeZone string `bson:"time_zone,omitempty"`
+	// DueEnd は TimeZone を指定した終日のタスクの期限日の翌日0時で、これを過ぎると期限切れです
+	DueEnd     time.Time   `bson:"due_end,omitempty"`
+	Recurrence *Recurrence `bson:"recurrence,omitempty"`
+	Reminders  []Reminder  `bson:"reminders,omitempty"`
+	CreatedAt  time.Time   `bson:"created_at"`
+	UpdatedAt  time.Time   `bson:"updated_at"`
+	// Rank はボード上の並び順です。作成時に末尾のランクを割り当て、ボード上の移動でだけ変更します
+	Rank string `bson:"rank,omitempty"`
+	// CompletedAt は完了にした日時です。未完了のタスクと、完了日時を記録する前に完了したタスクではゼロ値です
+	CompletedAt time.Time `bson:"completed_at,omitempty"`
+	// Progress は取得時に集計した進捗です。集計していない場合は nil です
+	Progress *TaskProgress `bson:"-"`
 }
 
 func (t *Task) Validate() error {
@@ -44,13 +88,103 @@ func (t *Task) Validate() error {
 		return errors.New("無効なステータスです")
 	}
 
-	if t.DueDate.IsZero() {
+	if t.DueDate.IsZero() && t.DueDay == "" {
 		return errors.New("期限は必須です")
 	}
 
+	if t.Priority < TaskPriorityNone || t.Priority > TaskPriorityUrgent {
+		return errors.New("無効な優先度です")
+	}
+
+	if err := validateLabels(t.Labels); err != nil {
+		return err
+	}
+
+	if err := ValidateChecklist(t.Checklist); err != nil {
+		return err
+	}
+
+	if err := ValidateReminders(t.Reminders); err != nil {
+		return err
+	}
+
+	if t.Recurrence != nil {
+		if err := t.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, value := range t.CustomFields {
+		if err := value.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// StampCompletion は previous からの変更に合わせて完了日時を設定します。完了になった場合は now にし、
+// 完了のままの場合は previous の完了日時を引き継ぎ、未完了の場合は消します。previous が nil の場合は作成として扱います
+func (t *Task) StampCompletion(previous *Task, now time.Time) {
+	switch {
+	case t.Status != TaskStatusComplete:
+		t.CompletedAt = time.Time{}
+	case previous != nil && previous.Status == TaskStatusComplete:
+		t.CompletedAt = previous.CompletedAt
+	default:
+		t.CompletedAt = now
+	}
+}
+
+func validateLabels(labels []string) error {
+	if len(labels) > MaxLabelsPerTask {
+		return errors.New("ラベルが多すぎます")
+	}
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		if err := ValidateLabelName(label); err != nil {
+			return err
+		}
+		if seen[label] {
+			return errors.New("ラベルが重複しています")
+		}
+		seen[label] = true
+	}
+	return nil
+}
+
+// ValidateLabelName はラベル名が空でなく、前後に空白を含まず、最大文字数以内かを検証します
+func ValidateLabelName(name string) error {
+	if name == "" {
+		return errors.New("ラベル名は必須です")
+	}
+	if strings.TrimSpace(name) != name {
+		return errors.New("ラベル名の前後に空白は使用できません")
+	}
+	if utf8.RuneCountInString(name) > MaxLabelNameLength {
+		return errors.New("ラベル名が長すぎます")
+	}
+	return nil
+}
+
+// SortValue は並び替えフィールドに対応する値を返します
+func (t *Task) SortValue(field TaskSortField) interface{} {
+	switch field {
+	case TaskSortByUpdatedAt:
+		return t.UpdatedAt
+	case TaskSortByDueDate:
+		return t.DueDate
+	case TaskSortByTitle:
+		return t.Title
+	case TaskSortByPriority:
+		return int32(t.Priority)
+	case TaskSortByRank:
+		return t.Rank
+	default:
+		return t.CreatedAt
+	}
+}
+
 func isValidStatus(status TaskStatus) bool {
 	switch status {
 	case TaskStatusPending, TaskStatusActive, TaskStatusComplete:
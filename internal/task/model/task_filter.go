@@ -0,0 +1,163 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/my-backend-project/internal/task/recurrence"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskSortField はタスク一覧の並び順に使うフィールドです
+type TaskSortField string
+
+const (
+	TaskSortByCreatedAt TaskSortField = "created_at"
+	TaskSortByUpdatedAt TaskSortField = "updated_at"
+	TaskSortByDueDate   TaskSortField = "due_date"
+	TaskSortByTitle     TaskSortField = "title"
+	TaskSortByPriority  TaskSortField = "priority"
+	// TaskSortByRank はボード上の並び順です。ワークフローのステータスで絞り込むと列の並び順になります
+	TaskSortByRank TaskSortField = "rank"
+)
+
+// DueDatePreset は期限に関する定型の絞り込み条件です
+type DueDatePreset string
+
+const (
+	DueDateOverdue     DueDatePreset = "overdue"
+	DueDateDueToday    DueDatePreset = "due_today"
+	DueDateDueThisWeek DueDatePreset = "due_this_week"
+)
+
+// TimeRange は半開区間 [Start, End) を表します。ゼロ値の端は無制限として扱います
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// IsZero は範囲が指定されていないかを返します
+func (r TimeRange) IsZero() bool {
+	return r.Start.IsZero() && r.End.IsZero()
+}
+
+// TaskFilter はタスク一覧取得時の絞り込み・並び替え条件です
+type TaskFilter struct {
+	Statuses     []TaskStatus
+	Priorities   []TaskPriority
+	Labels       []string
+	CustomFields map[string]CustomFieldValue
+	DuePreset    DueDatePreset
+	// TimeZone は DuePreset の「今日」を区切るタイムゾーン（IANA）です。サービスが未指定の場合にユーザーのタイムゾーンを設定します
+	TimeZone       string
+	DueDateRange   TimeRange
+	CreatedAtRange TimeRange
+	UpdatedAtRange TimeRange
+	Query          string
+	OrderBy        TaskSortField
+	Descending     bool
+
+	// WorkflowStatuses はワークフローのステータスの key での絞り込みです
+	WorkflowStatuses []string
+	// ProjectID はプロジェクトでの絞り込みです。Inbox の場合はどのプロジェクトにも属さないタスクだけを返します
+	ProjectID primitive.ObjectID
+	Inbox     bool
+	// IncludeArchived はアーカイブしたプロジェクトのタスクも返すかです。ProjectID を指定した場合は常に返します
+	IncludeArchived bool
+	// HiddenProjectIDs は返さないプロジェクトで、サービスがアーカイブしたプロジェクトを設定します。ページトークンの照合には含めません
+	HiddenProjectIDs []primitive.ObjectID `json:"-"`
+}
+
+// HidesArchivedProjects はアーカイブしたプロジェクトのタスクを除くかを返します
+func (f *TaskFilter) HidesArchivedProjects() bool {
+	return f == nil || (f.ProjectID.IsZero() && !f.Inbox && !f.IncludeArchived)
+}
+
+// Location は TimeZone のタイムゾーンを返します。未設定の場合と読み込めない場合は UTC です
+func (f *TaskFilter) Location() *time.Location {
+	if f == nil || f.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := recurrence.LoadLocation(f.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// SortField は並び順のフィールドを返します。未指定の場合は作成日時です
+func (f *TaskFilter) SortField() TaskSortField {
+	if f == nil || f.OrderBy == "" {
+		return TaskSortByCreatedAt
+	}
+	return f.OrderBy
+}
+
+// IsDescending は降順で並べるかを返します
+func (f *TaskFilter) IsDescending() bool {
+	return f != nil && f.Descending
+}
+
+// Fingerprint はユーザーと絞り込み・並び替え条件から決まるハッシュを返します。
+// ページトークンに埋め込み、別の条件で発行されたトークンの再利用を検出するために使います
+func (f *TaskFilter) Fingerprint(userID string) string {
+	var normalized TaskFilter
+	if f != nil {
+		normalized = *f
+	}
+	normalized.OrderBy = f.SortField()
+
+	data, _ := json.Marshal(struct {
+		UserID string
+		Filter TaskFilter
+	}{userID, normalized})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// TaskCursor はタスク一覧のページ位置です。直前のページ末尾のソートキーと ID を保持します
+type TaskCursor struct {
+	FilterHash string             `bson:"h"`
+	LastValue  interface{}        `bson:"v"`
+	LastID     primitive.ObjectID `bson:"i"`
+}
+
+// TaskPage はタスク一覧の1ページ分の結果です
+type TaskPage struct {
+	Tasks         []*Task
+	TotalCount    int32
+	NextPageToken string
+}
+
+// DuePresetRange は定型の期限条件を now 基準の期間に変換します。日付は now のタイムゾーンで区切ります。
+// 期限切れは [-∞, now)、今日は当日0時から翌日0時、今週は当日0時から次の月曜0時までです
+func DuePresetRange(preset DueDatePreset, now time.Time) TimeRange {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch preset {
+	case DueDateOverdue:
+		return TimeRange{End: now}
+	case DueDateDueToday:
+		return TimeRange{Start: startOfDay, End: startOfDay.AddDate(0, 0, 1)}
+	case DueDateDueThisWeek:
+		daysUntilMonday := (8 - int(startOfDay.Weekday())) % 7
+		if daysUntilMonday == 0 {
+			daysUntilMonday = 7
+		}
+		return TimeRange{Start: startOfDay, End: startOfDay.AddDate(0, 0, daysUntilMonday)}
+	default:
+		return TimeRange{}
+	}
+}
+
+// IsValidSortField は並び替えフィールドが有効かを返します
+func IsValidSortField(field TaskSortField) bool {
+	switch field {
+	case TaskSortByCreatedAt, TaskSortByUpdatedAt, TaskSortByDueDate, TaskSortByTitle, TaskSortByPriority, TaskSortByRank:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,196 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxTemplateNameLength はテンプレート名の最大文字数です
+	MaxTemplateNameLength = 100
+	// MaxTemplateSubtasks は1つのテンプレートに含められるサブタスクの上限です
+	MaxTemplateSubtasks = 50
+	// MaxTemplateDueOffsetDays は期限の基準日からのずれの上限（日）です
+	MaxTemplateDueOffsetDays = 3650
+)
+
+// placeholderPattern は "{{name}}" 形式のプレースホルダーです。名前は英数字とアンダースコアで、前後の空白は無視します
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// TaskTemplate は繰り返し作成するタスクのひな形です。タイトル・説明・チェックリストにはプレースホルダーを書けます
+type TaskTemplate struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	UserID      string             `bson:"user_id"`
+	Name        string             `bson:"name"`
+	Title       string             `bson:"title"`
+	Description string             `bson:"description"`
+	// Status は作成するタスクのステータスです。空の場合は未着手です
+	Status    TaskStatus   `bson:"status,omitempty"`
+	Priority  TaskPriority `bson:"priority"`
+	Labels    []string     `bson:"labels,omitempty"`
+	Checklist []string     `bson:"checklist,omitempty"`
+	// DueOffsetDays は作成時に指定する基準日から期限までの日数です
+	DueOffsetDays int               `bson:"due_offset_days"`
+	Subtasks      []TemplateSubtask `bson:"subtasks,omitempty"`
+	CreatedAt     time.Time         `bson:"created_at"`
+	UpdatedAt     time.Time         `bson:"updated_at"`
+}
+
+// TemplateSubtask はテンプレートから作成するサブタスクです。ステータスはテンプレートと同じです
+type TemplateSubtask struct {
+	Title         string       `bson:"title"`
+	Description   string       `bson:"description"`
+	Priority      TaskPriority `bson:"priority"`
+	Checklist     []string     `bson:"checklist,omitempty"`
+	DueOffsetDays int          `bson:"due_offset_days"`
+}
+
+func (t *TaskTemplate) Validate() error {
+	if t.UserID == "" {
+		return errors.New("ユーザーIDは必須です")
+	}
+
+	if t.Name == "" {
+		return errors.New("テンプレート名は必須です")
+	}
+	if utf8.RuneCountInString(t.Name) > MaxTemplateNameLength {
+		return errors.New("テンプレート名が長すぎます")
+	}
+
+	if t.Status != "" && !isValidStatus(t.Status) {
+		return errors.New("無効なステータスです")
+	}
+
+	if err := validateLabels(t.Labels); err != nil {
+		return err
+	}
+
+	if err := validateTemplateTask(t.Title, t.Priority, t.Checklist, t.DueOffsetDays); err != nil {
+		return err
+	}
+
+	if len(t.Subtasks) > MaxTemplateSubtasks {
+		return fmt.Errorf("サブタスクは%d件までです", MaxTemplateSubtasks)
+	}
+	for _, subtask := range t.Subtasks {
+		if err := validateTemplateTask(subtask.Title, subtask.Priority, subtask.Checklist, subtask.DueOffsetDays); err != nil {
+			return fmt.Errorf("サブタスク「%s」: %w", subtask.Title, err)
+		}
+	}
+
+	return nil
+}
+
+// validateTemplateTask はテンプレートとサブタスクに共通する項目を検証します。プレースホルダーを置き換える前の値を検証します
+func validateTemplateTask(title string, priority TaskPriority, checklist []string, dueOffsetDays int) error {
+	if strings.TrimSpace(title) == "" {
+		return errors.New("タイトルは必須です")
+	}
+	if priority < TaskPriorityNone || priority > TaskPriorityUrgent {
+		return errors.New("無効な優先度です")
+	}
+	if dueOffsetDays < -MaxTemplateDueOffsetDays || dueOffsetDays > MaxTemplateDueOffsetDays {
+		return fmt.Errorf("期限のずれは%d日までです", MaxTemplateDueOffsetDays)
+	}
+	return ValidateChecklist(newChecklist(checklist))
+}
+
+// Placeholders はテンプレートのプレースホルダーの名前を重複なく名前順に返します
+func (t *TaskTemplate) Placeholders() []string {
+	seen := make(map[string]bool)
+	collect := func(texts ...string) {
+		for _, text := range texts {
+			for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+				seen[match[1]] = true
+			}
+		}
+	}
+	collect(t.Title, t.Description)
+	collect(t.Checklist...)
+	for _, subtask := range t.Subtasks {
+		collect(subtask.Title, subtask.Description)
+		collect(subtask.Checklist...)
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Instantiate はテンプレートからタスクとサブタスクを組み立てます。プレースホルダーは variables の値で置き換え、
+// 期限は base に各タスクの日数を足した日時にします。値のないプレースホルダーがある場合はエラーを返します。
+// サブタスクの ParentID は設定しないため、親を作成した後に設定してください
+func (t *TaskTemplate) Instantiate(variables map[string]string, base time.Time) (*Task, []*Task, error) {
+	var missing []string
+	for _, name := range t.Placeholders() {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("変数が指定されていません: %s", strings.Join(missing, ", "))
+	}
+
+	status := t.Status
+	if status == "" {
+		status = TaskStatusPending
+	}
+	expand := func(text string) string {
+		return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+			return variables[placeholderPattern.FindStringSubmatch(match)[1]]
+		})
+	}
+	expandAll := func(texts []string) []ChecklistItem {
+		expanded := make([]string, len(texts))
+		for i, text := range texts {
+			expanded[i] = expand(text)
+		}
+		return newChecklist(expanded)
+	}
+
+	task := &Task{
+		UserID:      t.UserID,
+		Title:       expand(t.Title),
+		Description: expand(t.Description),
+		Status:      status,
+		Priority:    t.Priority,
+		Labels:      append([]string(nil), t.Labels...),
+		Checklist:   expandAll(t.Checklist),
+		DueDate:     base.AddDate(0, 0, t.DueOffsetDays),
+	}
+	subtasks := make([]*Task, len(t.Subtasks))
+	for i, subtask := range t.Subtasks {
+		subtasks[i] = &Task{
+			UserID:      t.UserID,
+			Title:       expand(subtask.Title),
+			Description: expand(subtask.Description),
+			Status:      status,
+			Priority:    subtask.Priority,
+			Checklist:   expandAll(subtask.Checklist),
+			DueDate:     base.AddDate(0, 0, subtask.DueOffsetDays),
+		}
+	}
+	return task, subtasks, nil
+}
+
+// newChecklist はテキストから未完了のチェックリストを作ります。項目IDは作成時に割り当てます
+func newChecklist(texts []string) []ChecklistItem {
+	if len(texts) == 0 {
+		return nil
+	}
+	items := make([]ChecklistItem, len(texts))
+	for i, text := range texts {
+		items[i] = ChecklistItem{Text: text}
+	}
+	return items
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxTimeEntryNoteLength は作業時間のメモの最大文字数です
+	MaxTimeEntryNoteLength = 1000
+	// MaxTimeEntryDuration は手動で記録できる1件の作業時間の上限です
+	MaxTimeEntryDuration = 24 * time.Hour
+)
+
+// TimeEntry はタスクの作業時間の記録です。タイマーで記録したものと手動で追加したものがあります
+type TimeEntry struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty"`
+	UserID string             `bson:"user_id"`
+	TaskID primitive.ObjectID `bson:"task_id"`
+	Start  time.Time          `bson:"start"`
+	// End は終了日時です。動いているタイマーではゼロ値です
+	End time.Time `bson:"end,omitempty"`
+	// Running は動いているタイマーかです。ユーザーごとに1件だけにするよう、一意インデックスの部分条件に使います
+	Running   bool      `bson:"running,omitempty"`
+	Note      string    `bson:"note"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// Validate は終了した作業時間を検証します
+func (e *TimeEntry) Validate() error {
+	if e.UserID == "" {
+		return errors.New("ユーザーIDは必須です")
+	}
+	if e.TaskID.IsZero() {
+		return errors.New("タスクIDは必須です")
+	}
+	if e.Start.IsZero() || e.End.IsZero() {
+		return errors.New("開始日時と終了日時は必須です")
+	}
+	if !e.End.After(e.Start) {
+		return errors.New("終了日時は開始日時より後にしてください")
+	}
+	if e.End.Sub(e.Start) > MaxTimeEntryDuration {
+		return errors.New("1件の作業時間は24時間までです")
+	}
+	return ValidateTimeEntryNote(e.Note)
+}
+
+// ValidateTimeEntryNote は作業時間のメモを検証します
+func ValidateTimeEntryNote(note string) error {
+	if utf8.RuneCountInString(note) > MaxTimeEntryNoteLength {
+		return errors.New("メモが長すぎます")
+	}
+	return nil
+}
+
+// Duration は作業時間を返します。動いているタイマーでは 0 です
+func (e *TimeEntry) Duration() time.Duration {
+	if e.Running || e.End.IsZero() {
+		return 0
+	}
+	return e.End.Sub(e.Start)
+}
+
+// TaskTimeTotal は1つのタスクの作業時間の合計です
+type TaskTimeTotal struct {
+	TaskID   primitive.ObjectID
+	Duration time.Duration
+	Entries  int64
+}
+
+// DailyDuration は1日の作業時間の合計です
+type DailyDuration struct {
+	Date     string
+	Duration time.Duration
+}
+
+// TimeReport は期間内の作業時間の集計です。作業時間は開始日時の属する期間・日に数え、動いているタイマーは含めません
+type TimeReport struct {
+	Total time.Duration
+	// ByTask は作業時間の長い順です
+	ByTask []TaskTimeTotal
+	// Daily は期間のすべての日の合計で、作業がない日も 0 として含みます
+	Daily []DailyDuration
+}
+
+// SortByTask は ByTask を作業時間の長い順に並べます。同じ場合はタスクIDの順です
+func (r *TimeReport) SortByTask() {
+	sort.Slice(r.ByTask, func(i, j int) bool {
+		if r.ByTask[i].Duration != r.ByTask[j].Duration {
+			return r.ByTask[i].Duration > r.ByTask[j].Duration
+		}
+		return bytes.Compare(r.ByTask[i].TaskID[:], r.ByTask[j].TaskID[:]) < 0
+	})
+}
+
+// FillDailyDurations は日付ごとの作業時間を期間のすべての日の一覧にします
+func (q TaskStatsQuery) FillDailyDurations(durations map[string]time.Duration) []DailyDuration {
+	days := q.Days()
+	result := make([]DailyDuration, len(days))
+	for i, day := range days {
+		result[i] = DailyDuration{Date: day, Duration: durations[day]}
+	}
+	return result
+}
+
+// ComputeTimeReport は1人のユーザーの作業時間から期間内の集計を求めます。
+// リポジトリの集計パイプラインと同じ結果になるインメモリの実装で、テストなどで使います
+func ComputeTimeReport(entries []*TimeEntry, q TaskStatsQuery) *TimeReport {
+	report := &TimeReport{}
+	byTask := make(map[primitive.ObjectID]*TaskTimeTotal)
+	daily := make(map[string]time.Duration)
+	for _, entry := range entries {
+		if entry.Running || !q.Contains(entry.Start) {
+			continue
+		}
+		d := entry.Duration()
+		report.Total += d
+		total, ok := byTask[entry.TaskID]
+		if !ok {
+			total = &TaskTimeTotal{TaskID: entry.TaskID}
+			byTask[entry.TaskID] = total
+		}
+		total.Duration += d
+		total.Entries++
+		daily[entry.Start.In(q.Location).Format(StatsDateLayout)] += d
+	}
+	for _, total := range byTask {
+		report.ByTask = append(report.ByTask, *total)
+	}
+	report.SortByTask()
+	report.Daily = q.FillDailyDurations(daily)
+	return report
+}
+
+// TimesheetRow はタイムシートの1行です。TaskTitle は削除したタスクでは空です
+type TimesheetRow struct {
+	Entry     *TimeEntry
+	TaskTitle string
+}
@@ -0,0 +1,240 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StatusCategory はワークフローのステータスの分類です。完了判定や期限切れの判定は分類に基づいて行います
+type StatusCategory string
+
+const (
+	StatusCategoryTodo       StatusCategory = "todo"
+	StatusCategoryInProgress StatusCategory = "in_progress"
+	StatusCategoryDone       StatusCategory = "done"
+)
+
+// TaskStatus は分類に対応する組み込みのステータスを返します
+func (c StatusCategory) TaskStatus() TaskStatus {
+	switch c {
+	case StatusCategoryTodo:
+		return TaskStatusPending
+	case StatusCategoryInProgress:
+		return TaskStatusActive
+	case StatusCategoryDone:
+		return TaskStatusComplete
+	default:
+		return ""
+	}
+}
+
+// CategoryOf は組み込みのステータスの分類を返します。組み込みでないステータスの場合は空文字です
+func CategoryOf(status TaskStatus) StatusCategory {
+	switch status {
+	case TaskStatusPending:
+		return StatusCategoryTodo
+	case TaskStatusActive:
+		return StatusCategoryInProgress
+	case TaskStatusComplete:
+		return StatusCategoryDone
+	default:
+		return ""
+	}
+}
+
+// TransitionGuard は遷移時に満たす必要がある条件です
+type TransitionGuard string
+
+const (
+	// GuardReasonRequired は遷移の理由の入力を必須にします
+	GuardReasonRequired TransitionGuard = "reason_required"
+	// GuardNoOpenSubtasks は未完了のサブタスクがないことを条件にします
+	GuardNoOpenSubtasks TransitionGuard = "no_open_subtasks"
+	// GuardNoOpenBlockers は未完了のブロッカーがないことを条件にします
+	GuardNoOpenBlockers TransitionGuard = "no_open_blockers"
+	// GuardChecklistComplete はチェックリストがすべて完了していることを条件にします
+	GuardChecklistComplete TransitionGuard = "checklist_complete"
+)
+
+const (
+	// MaxWorkflowStatuses はワークフローに定義できるステータスの上限です
+	MaxWorkflowStatuses = 30
+	// MaxWorkflowStatusNameLength はステータスの表示名の最大文字数です
+	MaxWorkflowStatusNameLength = 50
+)
+
+var workflowStatusKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,49}$`)
+
+// WorkflowStatus はワークフローで使えるステータスです。Key はタスクに保存する識別子です
+type WorkflowStatus struct {
+	Key      string         `bson:"key"`
+	Name     string         `bson:"name"`
+	Category StatusCategory `bson:"category"`
+}
+
+// WorkflowTransition は From から To への遷移の許可と、その際の条件です
+type WorkflowTransition struct {
+	From   string            `bson:"from"`
+	To     string            `bson:"to"`
+	Guards []TransitionGuard `bson:"guards,omitempty"`
+}
+
+// Workflow はユーザーまたはチームが定義するステータスと遷移の規則です。
+// OwnerID には定義の所有者（ユーザーIDまたはチームID）を保持します
+type Workflow struct {
+	ID            primitive.ObjectID   `bson:"_id,omitempty"`
+	OwnerID       string               `bson:"owner_id"`
+	Statuses      []WorkflowStatus     `bson:"statuses"`
+	Transitions   []WorkflowTransition `bson:"transitions"`
+	InitialStatus string               `bson:"initial_status"`
+	CreatedAt     time.Time            `bson:"created_at"`
+	UpdatedAt     time.Time            `bson:"updated_at"`
+}
+
+// DefaultWorkflow は独自のワークフローを定義していない所有者に適用するワークフローです。
+// ステータスは組み込みの3種類で、完了から未着手へ直接戻すことはできません
+func DefaultWorkflow(ownerID string) *Workflow {
+	pending, active, complete := string(TaskStatusPending), string(TaskStatusActive), string(TaskStatusComplete)
+	return &Workflow{
+		OwnerID: ownerID,
+		Statuses: []WorkflowStatus{
+			{Key: pending, Name: "未着手", Category: StatusCategoryTodo},
+			{Key: active, Name: "進行中", Category: StatusCategoryInProgress},
+			{Key: complete, Name: "完了", Category: StatusCategoryDone},
+		},
+		Transitions: []WorkflowTransition{
+			{From: pending, To: active},
+			{From: pending, To: complete},
+			{From: active, To: pending},
+			{From: active, To: complete},
+			{From: complete, To: active},
+		},
+		InitialStatus: pending,
+	}
+}
+
+// IsDefault は保存されていない既定のワークフローかを返します
+func (w *Workflow) IsDefault() bool {
+	return w.ID.IsZero()
+}
+
+// Status は key のステータスを返します
+func (w *Workflow) Status(key string) (WorkflowStatus, bool) {
+	for _, s := range w.Statuses {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// StatusForCategory は分類に属する最初のステータスの key を返します。該当がなければ空文字です
+func (w *Workflow) StatusForCategory(category StatusCategory) string {
+	if initial, ok := w.Status(w.InitialStatus); ok && initial.Category == category {
+		return initial.Key
+	}
+	for _, s := range w.Statuses {
+		if s.Category == category {
+			return s.Key
+		}
+	}
+	return ""
+}
+
+// CurrentStatus はタスクの現在のステータスの key を返します。
+// ワークフローのステータスが未設定か、ワークフローの変更で存在しなくなった場合は組み込みのステータスの分類から求めます
+func (w *Workflow) CurrentStatus(task *Task) string {
+	if _, ok := w.Status(task.WorkflowStatus); ok {
+		return task.WorkflowStatus
+	}
+	return w.StatusForCategory(CategoryOf(task.Status))
+}
+
+// Transition は from から to への遷移を返します。許可されていない場合は nil です
+func (w *Workflow) Transition(from, to string) *WorkflowTransition {
+	for i := range w.Transitions {
+		if w.Transitions[i].From == from && w.Transitions[i].To == to {
+			return &w.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// Validate はステータスの key の形式と重複、遷移が定義済みのステータスを参照していること、条件が有効なことを検証します
+func (w *Workflow) Validate() error {
+	if w.OwnerID == "" {
+		return errors.New("所有者IDは必須です")
+	}
+	if len(w.Statuses) == 0 {
+		return errors.New("ステータスを1つ以上定義してください")
+	}
+	if len(w.Statuses) > MaxWorkflowStatuses {
+		return errors.New("ステータスが多すぎます")
+	}
+
+	seen := make(map[string]bool, len(w.Statuses))
+	for _, s := range w.Statuses {
+		if !workflowStatusKeyPattern.MatchString(s.Key) {
+			return fmt.Errorf("ステータスのキー %q は英大文字・数字・アンダースコアで指定してください", s.Key)
+		}
+		if seen[s.Key] {
+			return fmt.Errorf("ステータスのキー %q が重複しています", s.Key)
+		}
+		seen[s.Key] = true
+		if s.Name == "" || utf8.RuneCountInString(s.Name) > MaxWorkflowStatusNameLength {
+			return fmt.Errorf("ステータス %q の表示名が不正です", s.Key)
+		}
+		if s.Category.TaskStatus() == "" {
+			return fmt.Errorf("ステータス %q の分類が不正です", s.Key)
+		}
+	}
+	if !seen[w.InitialStatus] {
+		return errors.New("初期ステータスが定義されていません")
+	}
+
+	pairs := make(map[[2]string]bool, len(w.Transitions))
+	for _, t := range w.Transitions {
+		if !seen[t.From] || !seen[t.To] {
+			return fmt.Errorf("遷移 %s → %s が未定義のステータスを参照しています", t.From, t.To)
+		}
+		if t.From == t.To {
+			return fmt.Errorf("遷移 %s → %s は同じステータスです", t.From, t.To)
+		}
+		pair := [2]string{t.From, t.To}
+		if pairs[pair] {
+			return fmt.Errorf("遷移 %s → %s が重複しています", t.From, t.To)
+		}
+		pairs[pair] = true
+		for _, g := range t.Guards {
+			if !isValidGuard(g) {
+				return fmt.Errorf("遷移 %s → %s の条件 %q が不正です", t.From, t.To, g)
+			}
+		}
+	}
+	return nil
+}
+
+func isValidGuard(g TransitionGuard) bool {
+	switch g {
+	case GuardReasonRequired, GuardNoOpenSubtasks, GuardNoOpenBlockers, GuardChecklistComplete:
+		return true
+	default:
+		return false
+	}
+}
+
+// TaskTransition はタスクのステータス遷移の履歴です
+type TaskTransition struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID    primitive.ObjectID `bson:"task_id"`
+	UserID    string             `bson:"user_id"`
+	From      string             `bson:"from"`
+	To        string             `bson:"to"`
+	Reason    string             `bson:"reason,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
@@ -0,0 +1,18 @@
+// Package notifier はリマインダーの送信予定を取得し、各通知先へ届ける通知ワーカーです。
+//
+// 送信予定の取得は MongoDB 上のリースで排他するため、複数のレプリカで動かしても同じ通知を同時に送りません。
+// 送信後にリースを失ったワーカーがいた場合などに限り同じ通知が再送されうるため（at-least-once）、
+// 通知先は Notification.DeliveryKey で重複を除けるようにしています。
+package notifier
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// Channel は通知の送信先です
+type Channel interface {
+	Name() model.NotificationChannel
+	Send(ctx context.Context, notification *model.Notification) error
+}
@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RecipientLookup はユーザーの通知先メールアドレスを返します
+type RecipientLookup interface {
+	Email(ctx context.Context, userID string) (string, error)
+}
+
+// EmailChannel は SMTP で通知をメール送信します
+type EmailChannel struct {
+	addr       string
+	from       string
+	auth       smtp.Auth
+	recipients RecipientLookup
+	send       func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailChannel は addr（host:port）の SMTP サーバーを使う EmailChannel を作成します。auth は nil でも構いません
+func NewEmailChannel(addr, from string, auth smtp.Auth, recipients RecipientLookup) *EmailChannel {
+	return &EmailChannel{addr: addr, from: from, auth: auth, recipients: recipients, send: smtp.SendMail}
+}
+
+func (c *EmailChannel) Name() model.NotificationChannel {
+	return model.ChannelEmail
+}
+
+func (c *EmailChannel) Send(ctx context.Context, notification *model.Notification) error {
+	to, err := c.recipients.Email(ctx, notification.UserID)
+	if err != nil {
+		return err
+	}
+	return c.send(c.addr, c.auth, c.from, []string{to}, buildMessage(c.from, to, notification))
+}
+
+// buildMessage は通知のメール本文を組み立てます。Message-ID に DeliveryKey を使い、受信側で重複を除けるようにします
+func buildMessage(from, to string, notification *model.Notification) []byte {
+	host := from
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		host = from[i+1:]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", notification.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", notification.CreatedAt.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s@%s>\r\n", strings.ReplaceAll(notification.DeliveryKey, ":", "."), host)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// MongoRecipientLookup は users コレクションからメールアドレスを取得します
+type MongoRecipientLookup struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRecipientLookup(db *mongo.Database) *MongoRecipientLookup {
+	return &MongoRecipientLookup{collection: db.Collection("users")}
+}
+
+func (l *MongoRecipientLookup) Email(ctx context.Context, userID string) (string, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", fmt.Errorf("invalid user id %q: %w", userID, err)
+	}
+	var user struct {
+		Email string `bson:"email"`
+	}
+	if err := l.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user); err != nil {
+		return "", fmt.Errorf("failed to find user %s: %w", userID, err)
+	}
+	if user.Email == "" {
+		return "", fmt.Errorf("user %s has no email address", userID)
+	}
+	return user.Email, nil
+}
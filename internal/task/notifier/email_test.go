@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticRecipients map[string]string
+
+func (r staticRecipients) Email(_ context.Context, userID string) (string, error) {
+	email, ok := r[userID]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return email, nil
+}
+
+func TestEmailChannel_Send(t *testing.T) {
+	var sentTo []string
+	var sentMsg string
+	channel := NewEmailChannel("smtp.example.com:587", "noreply@example.com", nil, staticRecipients{"user1": "user1@example.com"})
+	channel.send = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.Equal(t, "noreply@example.com", from)
+		sentTo = to
+		sentMsg = string(msg)
+		return nil
+	}
+
+	notification := testNotification()
+	require.NoError(t, channel.Send(context.Background(), notification))
+	assert.Equal(t, []string{"user1@example.com"}, sentTo)
+	assert.Contains(t, sentMsg, "To: user1@example.com\r\n")
+	assert.Contains(t, sentMsg, "Subject: =?UTF-8?b?")
+	assert.Contains(t, sentMsg, "Message-ID: <job1.webhook@example.com>\r\n")
+	assert.True(t, strings.HasSuffix(sentMsg, "\r\n\r\n"+notification.Body+"\r\n"))
+
+	notification.UserID = "unknown"
+	assert.Error(t, channel.Send(context.Background(), notification))
+}
@@ -0,0 +1,76 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// HeaderInternalToken はユーザーサービスの内部APIを呼び出すときに送るトークンのヘッダーです
+const HeaderInternalToken = "X-Internal-Token"
+
+// InboxChannel はユーザーサービスの内部API（POST /internal/notifications）を呼び出し、アプリ内の受信箱に通知を登録します
+type InboxChannel struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewInboxChannel は baseURL のユーザーサービスへ通知を登録する InboxChannel を作成します
+func NewInboxChannel(baseURL, token string, client *http.Client) *InboxChannel {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &InboxChannel{url: strings.TrimRight(baseURL, "/") + "/internal/notifications", token: token, client: client}
+}
+
+func (c *InboxChannel) Name() model.NotificationChannel {
+	return model.ChannelInbox
+}
+
+type inboxRequest struct {
+	UserID      string `json:"user_id"`
+	Event       string `json:"event"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	TaskID      string `json:"task_id"`
+	DeliveryKey string `json:"delivery_key"`
+}
+
+func (c *InboxChannel) Send(ctx context.Context, notification *model.Notification) error {
+	body, err := json.Marshal(inboxRequest{
+		UserID:      notification.UserID,
+		Event:       notification.Event,
+		Title:       notification.Title,
+		Body:        notification.Body,
+		TaskID:      notification.TaskID.Hex(),
+		DeliveryKey: notification.DeliveryKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderInternalToken, c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 204 はユーザーがイベントの通知を受け取らない設定のため登録されなかったことを表します
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("inbox returned status %d", resp.StatusCode)
+	}
+	return nil
+}
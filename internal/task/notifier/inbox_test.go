@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboxChannel_Send(t *testing.T) {
+	t.Run("posts_to_internal_api", func(t *testing.T) {
+		var received inboxRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/internal/notifications", r.URL.Path)
+			assert.Equal(t, "token", r.Header.Get(HeaderInternalToken))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		notification := testNotification()
+		notification.Event = model.EventDueSoon
+		require.NoError(t, NewInboxChannel(server.URL+"/", "token", server.Client()).Send(context.Background(), notification))
+		assert.Equal(t, "user1", received.UserID)
+		assert.Equal(t, model.EventDueSoon, received.Event)
+		assert.Equal(t, notification.TaskID.Hex(), received.TaskID)
+		assert.Equal(t, notification.DeliveryKey, received.DeliveryKey)
+	})
+
+	t.Run("suppressed_by_preferences", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		assert.NoError(t, NewInboxChannel(server.URL, "token", server.Client()).Send(context.Background(), testNotification()))
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		err := NewInboxChannel(server.URL, "wrong", server.Client()).Send(context.Background(), testNotification())
+		assert.ErrorContains(t, err, "401")
+	})
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+// runMentions は送信待ちのメンションがなくなるまで受信箱へ送信し、処理した件数を返します
+func (s *Scheduler) runMentions(ctx context.Context) (int, error) {
+	processed := 0
+	for ctx.Err() == nil {
+		job, err := s.mentions.Claim(ctx, s.cfg.Owner, s.now(), s.cfg.Lease)
+		if errors.Is(err, repository.ErrNoMentionDue) {
+			return processed, nil
+		}
+		if err != nil {
+			return processed, err
+		}
+		if err := s.processMention(ctx, job); err != nil {
+			if errors.Is(err, repository.ErrReminderLeaseLost) {
+				log.Printf("notifier: lease lost for mention %s", job.ID.Hex())
+			} else {
+				return processed, err
+			}
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// processMention はメンションされたユーザーの受信箱へ通知します。タスクが削除されている場合は送りません
+func (s *Scheduler) processMention(ctx context.Context, job *model.MentionJob) error {
+	if _, err := s.tasks.FindByID(ctx, job.TaskID.Hex()); err != nil {
+		if apperrors.IsNotFound(err) {
+			return s.mentions.Finish(ctx, job.ID, s.cfg.Owner, model.ReminderStateCancelled, "タスクが削除されています")
+		}
+		return s.retryMention(ctx, job, err)
+	}
+
+	inbox, ok := s.channels[model.ChannelInbox]
+	if !ok {
+		return s.retryMention(ctx, job, fmt.Errorf("%s: 通知先が設定されていません", model.ChannelInbox))
+	}
+
+	notification := &model.Notification{
+		DeliveryKey: "mention:" + job.ID.Hex(),
+		UserID:      job.UserID,
+		TaskID:      job.TaskID,
+		Channel:     model.ChannelInbox,
+		Event:       model.EventMentioned,
+		Title:       mentionTitle(job),
+		Body:        job.Excerpt,
+		CreatedAt:   s.now(),
+	}
+	if err := inbox.Send(ctx, notification); err != nil {
+		return s.retryMention(ctx, job, err)
+	}
+	return s.mentions.Finish(ctx, job.ID, s.cfg.Owner, model.ReminderStateSent, "")
+}
+
+func (s *Scheduler) retryMention(ctx context.Context, job *model.MentionJob, cause error) error {
+	if job.Attempts >= s.cfg.MaxAttempts {
+		return s.mentions.Finish(ctx, job.ID, s.cfg.Owner, model.ReminderStateFailed, cause.Error())
+	}
+	return s.mentions.Retry(ctx, job.ID, s.cfg.Owner, s.now().Add(retryDelay(job.Attempts)), cause.Error())
+}
+
+// mentionTitle は通知のタイトルです。タスクを見られないユーザーへの通知はタスクのタイトルを持たないため、タイトルを含めません
+func mentionTitle(job *model.MentionJob) string {
+	if job.TaskTitle == "" {
+		return "コメントでメンションされました"
+	}
+	return fmt.Sprintf("「%s」のコメントでメンションされました", job.TaskTitle)
+}
@@ -0,0 +1,247 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/recurrence"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+const (
+	DefaultPollInterval = 30 * time.Second
+	DefaultLease        = 2 * time.Minute
+	DefaultMaxAttempts  = 5
+
+	retryBaseDelay = time.Minute
+	retryMaxDelay  = time.Hour
+)
+
+// TaskFinder は通知の送信前にタスクの最新の状態を取得します
+type TaskFinder interface {
+	FindByID(ctx context.Context, id string) (*model.Task, error)
+}
+
+// TimeZoneLookup は期限を表示するユーザーのタイムゾーンを取得します。repository.UserDirectory が満たします
+type TimeZoneLookup interface {
+	TimeZone(ctx context.Context, userID string) (string, error)
+}
+
+// Config は通知ワーカーの設定です。ゼロ値の項目には既定値を使います
+type Config struct {
+	// Owner はリースの所有者として記録するワーカーの識別子です。レプリカごとに一意にします
+	Owner        string
+	PollInterval time.Duration
+	// Lease は1件の通知を送信する間に保持するリースの長さです。期限が切れると他のワーカーが取得できます
+	Lease       time.Duration
+	MaxAttempts int
+	// DefaultChannels は通知先を指定していないリマインダーの送信先です
+	DefaultChannels []model.NotificationChannel
+}
+
+// Scheduler は送信時刻を過ぎたリマインダーとコメントのメンションを取得して各通知先へ送信します
+type Scheduler struct {
+	jobs     repository.ReminderRepository
+	mentions repository.MentionRepository
+	tasks    TaskFinder
+	users    TimeZoneLookup
+	channels map[model.NotificationChannel]Channel
+	cfg      Config
+	now      func() time.Time
+}
+
+// NewScheduler は Scheduler を作成します。mentions が nil の場合はメンションを送信しません。
+// users が nil の場合、タイムゾーンを指定していないタスクの期限は UTC で表示します
+func NewScheduler(jobs repository.ReminderRepository, mentions repository.MentionRepository, tasks TaskFinder, users TimeZoneLookup, channels []Channel, cfg Config) *Scheduler {
+	if cfg.Owner == "" {
+		hostname, _ := os.Hostname()
+		cfg.Owner = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = DefaultLease
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if len(cfg.DefaultChannels) == 0 {
+		cfg.DefaultChannels = []model.NotificationChannel{model.ChannelInbox}
+	}
+
+	byName := make(map[model.NotificationChannel]Channel, len(channels))
+	for _, c := range channels {
+		byName[c.Name()] = c
+	}
+	return &Scheduler{jobs: jobs, mentions: mentions, tasks: tasks, users: users, channels: byName, cfg: cfg, now: time.Now}
+}
+
+// Run は ctx がキャンセルされるまで通知を送信し続けます
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("notifier: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce は送信時刻を過ぎた通知がなくなるまで送信し、処理した件数を返します
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	reminders, err := s.runReminders(ctx)
+	if err != nil || s.mentions == nil {
+		return reminders, err
+	}
+	mentions, err := s.runMentions(ctx)
+	return reminders + mentions, err
+}
+
+func (s *Scheduler) runReminders(ctx context.Context) (int, error) {
+	processed := 0
+	for ctx.Err() == nil {
+		job, err := s.jobs.Claim(ctx, s.cfg.Owner, s.now(), s.cfg.Lease)
+		if errors.Is(err, repository.ErrNoReminderDue) {
+			return processed, nil
+		}
+		if err != nil {
+			return processed, err
+		}
+		if err := s.process(ctx, job); err != nil {
+			if errors.Is(err, repository.ErrReminderLeaseLost) {
+				log.Printf("notifier: lease lost for reminder %s", job.ID.Hex())
+			} else {
+				return processed, err
+			}
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// process は取得した通知を送信し、結果を記録します。送信済みの通知先には送りません。
+// 送信できなかった通知先がある場合は送信済みにせず、その通知先だけを再送します
+func (s *Scheduler) process(ctx context.Context, job *model.ReminderJob) error {
+	task, err := s.tasks.FindByID(ctx, job.TaskID.Hex())
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return s.jobs.Finish(ctx, job.ID, s.cfg.Owner, model.ReminderStateCancelled, "タスクが削除されています")
+		}
+		return s.retry(ctx, job, err)
+	}
+	if !task.IsOpen() || !task.DueDate.Equal(job.DueDate) {
+		return s.jobs.Finish(ctx, job.ID, s.cfg.Owner, model.ReminderStateCancelled, "")
+	}
+	loc, err := s.dueLocation(ctx, job, task)
+	if err != nil {
+		return s.retry(ctx, job, err)
+	}
+
+	var failures []string
+	for _, name := range s.channelsFor(job) {
+		if job.IsDelivered(name) {
+			continue
+		}
+		channel, ok := s.channels[name]
+		if !ok {
+			// 設定されていない通知先は送信に失敗したものとして再送し、設定されないまま上限に達したら失敗として終了する
+			failures = append(failures, fmt.Sprintf("%s: 通知先が設定されていません", name))
+			continue
+		}
+		if err := channel.Send(ctx, s.notification(job, task, name, loc)); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if err := s.jobs.MarkDelivered(ctx, job.ID, s.cfg.Owner, name); err != nil {
+			return err
+		}
+	}
+
+	if len(failures) > 0 {
+		return s.retry(ctx, job, errors.New(strings.Join(failures, "; ")))
+	}
+	return s.jobs.Finish(ctx, job.ID, s.cfg.Owner, model.ReminderStateSent, "")
+}
+
+// retry は指数バックオフで再送を予約します。試行回数の上限に達した通知は失敗として終了します
+func (s *Scheduler) retry(ctx context.Context, job *model.ReminderJob, cause error) error {
+	if job.Attempts >= s.cfg.MaxAttempts {
+		return s.jobs.Finish(ctx, job.ID, s.cfg.Owner, model.ReminderStateFailed, cause.Error())
+	}
+	return s.jobs.Retry(ctx, job.ID, s.cfg.Owner, s.now().Add(retryDelay(job.Attempts)), cause.Error())
+}
+
+// retryDelay は attempts 回目の失敗後に再送するまでの待ち時間です
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+func (s *Scheduler) channelsFor(job *model.ReminderJob) []model.NotificationChannel {
+	if len(job.Channels) > 0 {
+		return job.Channels
+	}
+	return s.cfg.DefaultChannels
+}
+
+func (s *Scheduler) notification(job *model.ReminderJob, task *model.Task, channel model.NotificationChannel, loc *time.Location) *model.Notification {
+	return &model.Notification{
+		DeliveryKey: job.ID.Hex() + ":" + string(channel),
+		UserID:      job.UserID,
+		TaskID:      job.TaskID,
+		Channel:     channel,
+		Event:       model.EventDueSoon,
+		Title:       fmt.Sprintf("「%s」の期限が近づいています", task.Title),
+		Body:        fmt.Sprintf("タスク「%s」の期限は %s です。", task.Title, formatDueDate(task, loc)),
+		DueDate:     task.DueDate,
+		CreatedAt:   s.now(),
+	}
+}
+
+// dueLocation は期限を表示するタイムゾーンを返します。タスクのタイムゾーン、通知先のユーザーのタイムゾーン、UTC の順に使います
+func (s *Scheduler) dueLocation(ctx context.Context, job *model.ReminderJob, task *model.Task) (*time.Location, error) {
+	if task.TimeZone != "" {
+		if loc, err := recurrence.LoadLocation(task.TimeZone); err == nil {
+			return loc, nil
+		}
+	}
+	if s.users == nil {
+		return time.UTC, nil
+	}
+	name, err := s.users.TimeZone(ctx, job.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ユーザーのタイムゾーンの取得に失敗しました: %w", err)
+	}
+	loc, err := recurrence.LoadLocation(name)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// formatDueDate は期限を loc で表示します。終日のタスクは期限日だけを表示します
+func formatDueDate(task *model.Task, loc *time.Location) string {
+	if task.AllDay {
+		return task.DueDay
+	}
+	return task.DueDate.In(loc).Format("2006-01-02 15:04 MST")
+}
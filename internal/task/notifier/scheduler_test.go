@@ -0,0 +1,475 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryReminderRepository はリースを含めて MongoDB の実装と同じように振る舞うテスト用の実装です
+type memoryReminderRepository struct {
+	jobs []*model.ReminderJob
+}
+
+func (r *memoryReminderRepository) Sync(context.Context, primitive.ObjectID, []*model.ReminderJob) error {
+	return nil
+}
+
+func (r *memoryReminderRepository) DeleteByTask(context.Context, primitive.ObjectID) error {
+	return nil
+}
+
+func (r *memoryReminderRepository) Claim(_ context.Context, owner string, now time.Time, lease time.Duration) (*model.ReminderJob, error) {
+	var claimed *model.ReminderJob
+	for _, job := range r.jobs {
+		if job.State != model.ReminderStatePending || job.AvailableAt.After(now) {
+			continue
+		}
+		if claimed == nil || job.AvailableAt.Before(claimed.AvailableAt) {
+			claimed = job
+		}
+	}
+	if claimed == nil {
+		return nil, repository.ErrNoReminderDue
+	}
+	claimed.LeaseOwner = owner
+	claimed.AvailableAt = now.Add(lease)
+	claimed.Attempts++
+	copied := *claimed
+	copied.Delivered = append([]model.NotificationChannel(nil), claimed.Delivered...)
+	return &copied, nil
+}
+
+func (r *memoryReminderRepository) leased(id primitive.ObjectID, owner string) (*model.ReminderJob, error) {
+	for _, job := range r.jobs {
+		if job.ID == id && job.LeaseOwner == owner && job.State == model.ReminderStatePending {
+			return job, nil
+		}
+	}
+	return nil, repository.ErrReminderLeaseLost
+}
+
+func (r *memoryReminderRepository) MarkDelivered(_ context.Context, id primitive.ObjectID, owner string, channel model.NotificationChannel) error {
+	job, err := r.leased(id, owner)
+	if err != nil {
+		return err
+	}
+	if !job.IsDelivered(channel) {
+		job.Delivered = append(job.Delivered, channel)
+	}
+	return nil
+}
+
+func (r *memoryReminderRepository) Retry(_ context.Context, id primitive.ObjectID, owner string, retryAt time.Time, lastError string) error {
+	job, err := r.leased(id, owner)
+	if err != nil {
+		return err
+	}
+	job.AvailableAt = retryAt
+	job.LastError = lastError
+	job.LeaseOwner = ""
+	return nil
+}
+
+func (r *memoryReminderRepository) Finish(_ context.Context, id primitive.ObjectID, owner string, state model.ReminderState, lastError string) error {
+	job, err := r.leased(id, owner)
+	if err != nil {
+		return err
+	}
+	job.State = state
+	job.LastError = lastError
+	job.LeaseOwner = ""
+	return nil
+}
+
+// memoryMentionRepository はリースを含めて MongoDB の実装と同じように振る舞うテスト用の実装です
+type memoryMentionRepository struct {
+	jobs []*model.MentionJob
+}
+
+func (r *memoryMentionRepository) Create(_ context.Context, jobs []*model.MentionJob) error {
+	for _, job := range jobs {
+		job.ID = primitive.NewObjectID()
+		job.State = model.ReminderStatePending
+		r.jobs = append(r.jobs, job)
+	}
+	return nil
+}
+
+func (r *memoryMentionRepository) DeleteByComment(context.Context, primitive.ObjectID) error {
+	return nil
+}
+
+func (r *memoryMentionRepository) Claim(_ context.Context, owner string, now time.Time, lease time.Duration) (*model.MentionJob, error) {
+	for _, job := range r.jobs {
+		if job.State == model.ReminderStatePending && !job.AvailableAt.After(now) {
+			job.LeaseOwner = owner
+			job.AvailableAt = now.Add(lease)
+			job.Attempts++
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNoMentionDue
+}
+
+func (r *memoryMentionRepository) leased(id primitive.ObjectID, owner string) (*model.MentionJob, error) {
+	for _, job := range r.jobs {
+		if job.ID == id && job.LeaseOwner == owner && job.State == model.ReminderStatePending {
+			return job, nil
+		}
+	}
+	return nil, repository.ErrReminderLeaseLost
+}
+
+func (r *memoryMentionRepository) Retry(_ context.Context, id primitive.ObjectID, owner string, retryAt time.Time, lastError string) error {
+	job, err := r.leased(id, owner)
+	if err != nil {
+		return err
+	}
+	job.AvailableAt = retryAt
+	job.LastError = lastError
+	job.LeaseOwner = ""
+	return nil
+}
+
+func (r *memoryMentionRepository) Finish(_ context.Context, id primitive.ObjectID, owner string, state model.ReminderState, lastError string) error {
+	job, err := r.leased(id, owner)
+	if err != nil {
+		return err
+	}
+	job.State = state
+	job.LastError = lastError
+	job.LeaseOwner = ""
+	return nil
+}
+
+type memoryTaskFinder map[string]*model.Task
+
+func (f memoryTaskFinder) FindByID(_ context.Context, id string) (*model.Task, error) {
+	task, ok := f[id]
+	if !ok {
+		return nil, repository.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+type memoryTimeZones map[string]string
+
+func (z memoryTimeZones) TimeZone(_ context.Context, userID string) (string, error) {
+	return z[userID], nil
+}
+
+type fakeChannel struct {
+	name model.NotificationChannel
+	err  error
+	sent []*model.Notification
+}
+
+func (c *fakeChannel) Name() model.NotificationChannel { return c.name }
+
+func (c *fakeChannel) Send(_ context.Context, notification *model.Notification) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, notification)
+	return nil
+}
+
+type schedulerFixture struct {
+	scheduler *Scheduler
+	jobs      *memoryReminderRepository
+	mentions  *memoryMentionRepository
+	task      *model.Task
+	inbox     *fakeChannel
+	email     *fakeChannel
+	now       time.Time
+}
+
+func newSchedulerFixture(t *testing.T, channels ...model.NotificationChannel) *schedulerFixture {
+	t.Helper()
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	task := &model.Task{
+		ID:        primitive.NewObjectID(),
+		UserID:    "user1",
+		Title:     "提出",
+		Status:    model.TaskStatusPending,
+		DueDate:   now.Add(time.Hour),
+		Reminders: []model.Reminder{{ID: "r1", MinutesBefore: 60, Channels: channels}},
+	}
+	jobs := task.ReminderJobs(now.Add(-time.Minute))
+	require.Len(t, jobs, 1)
+	jobs[0].ID = primitive.NewObjectID()
+
+	f := &schedulerFixture{
+		jobs:     &memoryReminderRepository{jobs: jobs},
+		mentions: &memoryMentionRepository{},
+		task:     task,
+		inbox:    &fakeChannel{name: model.ChannelInbox},
+		email:    &fakeChannel{name: model.ChannelEmail},
+		now:      now,
+	}
+	f.scheduler = NewScheduler(f.jobs, f.mentions, memoryTaskFinder{task.ID.Hex(): task}, nil, []Channel{f.inbox, f.email}, Config{Owner: "worker1", MaxAttempts: 3})
+	f.scheduler.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *schedulerFixture) job() *model.ReminderJob {
+	return f.jobs.jobs[0]
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends_to_default_channels", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		processed, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		require.Len(t, f.inbox.sent, 1)
+		assert.Empty(t, f.email.sent)
+		notification := f.inbox.sent[0]
+		assert.Equal(t, f.job().ID.Hex()+":inbox", notification.DeliveryKey)
+		assert.Equal(t, "user1", notification.UserID)
+		assert.Equal(t, "「提出」の期限が近づいています", notification.Title)
+		assert.Contains(t, notification.Body, "2025-01-06 10:00 UTC")
+		assert.Equal(t, model.ReminderStateSent, f.job().State)
+	})
+
+	t.Run("formats_due_date_in_task_or_user_time_zone", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		f.scheduler.users = memoryTimeZones{"user1": "America/New_York"}
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		require.Len(t, f.inbox.sent, 1)
+		assert.Contains(t, f.inbox.sent[0].Body, "2025-01-06 05:00 EST")
+
+		// タスクのタイムゾーンはユーザーのタイムゾーンより優先する
+		f = newSchedulerFixture(t)
+		f.task.TimeZone = "Asia/Tokyo"
+		f.scheduler.users = memoryTimeZones{"user1": "America/New_York"}
+		_, err = f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		require.Len(t, f.inbox.sent, 1)
+		assert.Contains(t, f.inbox.sent[0].Body, "2025-01-06 19:00 JST")
+	})
+
+	t.Run("formats_all_day_due_date_without_time", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		f.task.AllDay = true
+		f.task.DueDay = "2025-01-06"
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		require.Len(t, f.inbox.sent, 1)
+		assert.Equal(t, "タスク「提出」の期限は 2025-01-06 です。", f.inbox.sent[0].Body)
+	})
+
+	t.Run("not_due_yet", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		f.now = f.now.Add(-2 * time.Minute)
+		processed, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+		assert.Empty(t, f.inbox.sent)
+	})
+
+	t.Run("sent_once", func(t *testing.T) {
+		f := newSchedulerFixture(t, model.ChannelEmail)
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+
+		f.now = f.now.Add(time.Hour)
+		processed, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+		assert.Len(t, f.email.sent, 1)
+	})
+
+	t.Run("cancelled_when_task_completed", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		f.task.Status = model.TaskStatusComplete
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, f.inbox.sent)
+		assert.Equal(t, model.ReminderStateCancelled, f.job().State)
+	})
+
+	t.Run("cancelled_when_task_deleted", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		f.scheduler.tasks = memoryTaskFinder{}
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, model.ReminderStateCancelled, f.job().State)
+	})
+
+	t.Run("retries_only_failed_channels", func(t *testing.T) {
+		f := newSchedulerFixture(t, model.ChannelInbox, model.ChannelEmail)
+		f.email.err = errors.New("smtp unavailable")
+
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, model.ReminderStatePending, f.job().State)
+		assert.Equal(t, []model.NotificationChannel{model.ChannelInbox}, f.job().Delivered)
+		assert.Contains(t, f.job().LastError, "smtp unavailable")
+		assert.Equal(t, f.now.Add(time.Minute), f.job().AvailableAt)
+
+		f.email.err = nil
+		f.now = f.now.Add(time.Minute)
+		_, err = f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Len(t, f.inbox.sent, 1, "送信済みの通知先には再送しない")
+		assert.Len(t, f.email.sent, 1)
+		assert.Equal(t, model.ReminderStateSent, f.job().State)
+	})
+
+	t.Run("unconfigured_channel_is_not_sent", func(t *testing.T) {
+		f := newSchedulerFixture(t, model.ChannelInbox, model.ChannelWebhook)
+
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Len(t, f.inbox.sent, 1)
+		assert.Equal(t, model.ReminderStatePending, f.job().State, "設定されていない通知先があるうちは送信済みにしない")
+		assert.Equal(t, []model.NotificationChannel{model.ChannelInbox}, f.job().Delivered)
+		assert.Contains(t, f.job().LastError, "webhook")
+
+		for i := 0; i < 2; i++ {
+			f.now = f.now.Add(time.Hour)
+			_, err = f.scheduler.RunOnce(ctx)
+			require.NoError(t, err)
+		}
+		assert.Len(t, f.inbox.sent, 1)
+		assert.Equal(t, model.ReminderStateFailed, f.job().State)
+	})
+
+	t.Run("fails_after_max_attempts", func(t *testing.T) {
+		f := newSchedulerFixture(t, model.ChannelEmail)
+		f.email.err = errors.New("smtp unavailable")
+
+		for i := 0; i < 3; i++ {
+			_, err := f.scheduler.RunOnce(ctx)
+			require.NoError(t, err)
+			f.now = f.now.Add(time.Hour)
+		}
+		assert.Equal(t, model.ReminderStateFailed, f.job().State)
+		assert.Equal(t, 3, f.job().Attempts)
+	})
+
+	t.Run("expired_lease_is_taken_over", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		_, err := f.jobs.Claim(ctx, "crashed-worker", f.now, time.Minute)
+		require.NoError(t, err)
+
+		processed, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, processed, "リース中の通知は取得しない")
+
+		f.now = f.now.Add(time.Minute)
+		processed, err = f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		assert.Equal(t, model.ReminderStateSent, f.job().State)
+	})
+}
+
+func TestScheduler_Mentions(t *testing.T) {
+	ctx := context.Background()
+
+	addMention := func(f *schedulerFixture) *model.MentionJob {
+		job := &model.MentionJob{
+			CommentID: primitive.NewObjectID(),
+			TaskID:    f.task.ID,
+			TaskTitle: f.task.Title,
+			UserID:    "user2",
+			AuthorID:  "user1",
+			Excerpt:   "@user2 確認お願いします",
+			// 期限のリマインダーより後に送る
+			AvailableAt: f.now.Add(time.Second),
+		}
+		require.NoError(t, f.mentions.Create(ctx, []*model.MentionJob{job}))
+		return job
+	}
+
+	t.Run("sent_to_inbox", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		job := addMention(f)
+		f.now = f.now.Add(time.Second)
+
+		processed, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, processed)
+		require.Len(t, f.inbox.sent, 2)
+
+		notification := f.inbox.sent[1]
+		assert.Equal(t, "mention:"+job.ID.Hex(), notification.DeliveryKey)
+		assert.Equal(t, "user2", notification.UserID)
+		assert.Equal(t, model.EventMentioned, notification.Event)
+		assert.Equal(t, "「提出」のコメントでメンションされました", notification.Title)
+		assert.Equal(t, job.Excerpt, notification.Body)
+		assert.Equal(t, model.ReminderStateSent, job.State)
+	})
+
+	t.Run("title_omitted_for_users_who_cannot_view_task", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		job := addMention(f)
+		job.TaskTitle = ""
+		f.now = f.now.Add(time.Second)
+
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		require.Len(t, f.inbox.sent, 2)
+		assert.Equal(t, "コメントでメンションされました", f.inbox.sent[1].Title)
+	})
+
+	t.Run("retried_on_failure", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		f.task.Status = model.TaskStatusComplete
+		job := addMention(f)
+		f.now = f.now.Add(time.Second)
+		f.inbox.err = errors.New("user service unavailable")
+
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, model.ReminderStatePending, job.State)
+		assert.Equal(t, f.now.Add(time.Minute), job.AvailableAt)
+	})
+
+	t.Run("retried_when_inbox_not_configured", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		delete(f.scheduler.channels, model.ChannelInbox)
+		job := addMention(f)
+		f.now = f.now.Add(time.Second)
+
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, model.ReminderStatePending, job.State)
+		assert.Contains(t, job.LastError, "inbox")
+	})
+
+	t.Run("cancelled_when_task_deleted", func(t *testing.T) {
+		f := newSchedulerFixture(t)
+		f.scheduler.tasks = memoryTaskFinder{}
+		job := addMention(f)
+		f.now = f.now.Add(time.Second)
+
+		_, err := f.scheduler.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, f.inbox.sent)
+		assert.Equal(t, model.ReminderStateCancelled, job.State)
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, retryDelay(1))
+	assert.Equal(t, 2*time.Minute, retryDelay(2))
+	assert.Equal(t, 8*time.Minute, retryDelay(4))
+	assert.Equal(t, time.Hour, retryDelay(10))
+}
@@ -0,0 +1,90 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+const (
+	// HeaderDeliveryID は通知の DeliveryKey を送るヘッダーです。受信側で重複を除くために使います
+	HeaderDeliveryID = "X-Delivery-Id"
+	// HeaderSignature は本文の HMAC-SHA256 署名を "sha256=<hex>" の形式で送るヘッダーです
+	HeaderSignature = "X-Signature"
+)
+
+// WebhookChannel は通知を JSON で URL へ POST します
+type WebhookChannel struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+func NewWebhookChannel(url string, secret []byte, client *http.Client) *WebhookChannel {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebhookChannel{url: url, secret: secret, client: client}
+}
+
+func (c *WebhookChannel) Name() model.NotificationChannel {
+	return model.ChannelWebhook
+}
+
+type webhookPayload struct {
+	DeliveryID string    `json:"delivery_id"`
+	UserID     string    `json:"user_id"`
+	TaskID     string    `json:"task_id"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	DueDate    time.Time `json:"due_date"`
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, notification *model.Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		DeliveryID: notification.DeliveryKey,
+		UserID:     notification.UserID,
+		TaskID:     notification.TaskID.Hex(),
+		Title:      notification.Title,
+		Body:       notification.Body,
+		DueDate:    notification.DueDate,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryID, notification.DeliveryKey)
+	if len(c.secret) > 0 {
+		req.Header.Set(HeaderSignature, "sha256="+Sign(c.secret, body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign は本文の HMAC-SHA256 署名を16進数で返します
+func Sign(secret, body []byte) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testNotification() *model.Notification {
+	return &model.Notification{
+		DeliveryKey: "job1:webhook",
+		UserID:      "user1",
+		TaskID:      primitive.NewObjectID(),
+		Channel:     model.ChannelWebhook,
+		Title:       "「提出」の期限が近づいています",
+		Body:        "タスク「提出」の期限は 2025-01-06 10:00 UTC です。",
+		DueDate:     time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookChannel_Send(t *testing.T) {
+	secret := []byte("secret")
+
+	t.Run("signed", func(t *testing.T) {
+		var received webhookPayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "sha256="+Sign(secret, body), r.Header.Get(HeaderSignature))
+			assert.Equal(t, "job1:webhook", r.Header.Get(HeaderDeliveryID))
+			assert.NoError(t, json.Unmarshal(body, &received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		notification := testNotification()
+		err := NewWebhookChannel(server.URL, secret, server.Client()).Send(context.Background(), notification)
+		require.NoError(t, err)
+		assert.Equal(t, notification.TaskID.Hex(), received.TaskID)
+		assert.Equal(t, notification.Title, received.Title)
+		assert.True(t, received.DueDate.Equal(notification.DueDate))
+	})
+
+	t.Run("error_status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		err := NewWebhookChannel(server.URL, nil, server.Client()).Send(context.Background(), testNotification())
+		assert.ErrorContains(t, err, "502")
+	})
+}
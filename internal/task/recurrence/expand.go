@@ -0,0 +1,261 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	// コンテナにタイムゾーンのデータベースがなくても IANA のタイムゾーンを扱えるようにする
+	_ "time/tzdata"
+)
+
+// maxEmptyPeriods は発生日のない期間が続いたときに展開を打ち切る上限です。
+// 2月を起点にした FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30 のように、二度と発生しないルールで探索を続けないためのものです
+const maxEmptyPeriods = 1000
+
+// LoadLocation は IANA のタイムゾーン名からタイムゾーンを読み込みます。空文字は UTC です。
+// サーバーの設定に依存する "Local" は受け付けません
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, errors.New("タイムゾーンに Local は指定できません")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("タイムゾーン %q が不正です", name)
+	}
+	return loc, nil
+}
+
+// Iterator は起点から順に発生日時を返します。起点は常に最初の発生日時として数えます (RFC 5545 3.8.5.3)
+type Iterator struct {
+	rule  *Rule
+	start time.Time
+	until time.Time
+
+	period  int
+	pending []time.Time
+	emitted int
+	started bool
+	done    bool
+}
+
+// Iterator は start を起点とする展開を返します。発生日時は start のタイムゾーンの壁時計で start と同じ時刻になります
+func (r *Rule) Iterator(start time.Time) *Iterator {
+	it := &Iterator{rule: r, start: start}
+	switch r.untilForm {
+	case untilUTC:
+		it.until = r.until
+	case untilFloating, untilDate:
+		it.until = localTime(r.until, r.until, start.Location())
+	}
+	return it
+}
+
+// Next は次の発生日時を返します。ルールの終わりに達した場合は false を返します
+func (it *Iterator) Next() (time.Time, bool) {
+	if it.done || (it.rule.Count > 0 && it.emitted >= it.rule.Count) {
+		it.done = true
+		return time.Time{}, false
+	}
+
+	next := it.start
+	if it.started {
+		for empty := 0; len(it.pending) == 0; empty++ {
+			if empty >= maxEmptyPeriods {
+				it.done = true
+				return time.Time{}, false
+			}
+			for _, t := range it.candidates(it.period) {
+				if t.After(it.start) {
+					it.pending = append(it.pending, t)
+				}
+			}
+			it.period++
+		}
+		next, it.pending = it.pending[0], it.pending[1:]
+	}
+	it.started = true
+
+	if !it.until.IsZero() && next.After(it.until) {
+		it.done = true
+		return time.Time{}, false
+	}
+	it.emitted++
+	return next, true
+}
+
+// Occurrences は start を起点とする発生日時のうち after より後のものを古い順に最大 limit 件返します
+func (r *Rule) Occurrences(start, after time.Time, limit int) []time.Time {
+	var result []time.Time
+	it := r.Iterator(start)
+	for len(result) < limit {
+		t, ok := it.Next()
+		if !ok {
+			break
+		}
+		if t.After(after) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// Next は start を起点とする発生日時のうち after より後の最初のものを返します
+func (r *Rule) Next(start, after time.Time) (time.Time, bool) {
+	occurrences := r.Occurrences(start, after, 1)
+	if len(occurrences) == 0 {
+		return time.Time{}, false
+	}
+	return occurrences[0], true
+}
+
+// candidates は period 番目の期間（日・週・月）に発生する日時を古い順に返します
+func (it *Iterator) candidates(period int) []time.Time {
+	r := it.rule
+	local := it.start
+	startDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := startDay.AddDate(0, 0, period*r.Interval)
+		if r.matchesDay(day) {
+			days = append(days, day)
+		}
+	case Weekly:
+		monday := startDay.AddDate(0, 0, -daysSinceMonday(startDay.Weekday())+7*period*r.Interval)
+		weekdays := []WeekdayNum{{Weekday: startDay.Weekday()}}
+		if len(r.ByDay) > 0 {
+			weekdays = r.ByDay
+		}
+		for _, d := range weekdays {
+			days = append(days, monday.AddDate(0, 0, daysSinceMonday(d.Weekday)))
+		}
+	case Monthly:
+		first := time.Date(startDay.Year(), startDay.Month()+time.Month(period*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+		days = r.monthDays(first, startDay.Day())
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	result := make([]time.Time, len(days))
+	for i, day := range days {
+		result[i] = localTime(day, local, local.Location())
+	}
+	return result
+}
+
+// matchesDay は FREQ=DAILY で BYDAY と BYMONTHDAY の条件に合う日かを返します
+func (r *Rule) matchesDay(day time.Time) bool {
+	if len(r.ByDay) > 0 {
+		matched := false
+		for _, d := range r.ByDay {
+			matched = matched || d.Weekday == day.Weekday()
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(r.ByMonthDay) > 0 {
+		return resolveMonthDays(r.ByMonthDay, daysInMonth(day))[day.Day()]
+	}
+	return true
+}
+
+// monthDays は first の月の発生日を返します。BYDAY と BYMONTHDAY を両方指定した場合は両方に合う日です。
+// どちらもなければ起点と同じ日で、その日がない月（31日に対する30日までの月など）は発生しません
+func (r *Rule) monthDays(first time.Time, startDay int) []time.Time {
+	dim := daysInMonth(first)
+
+	var matched map[int]bool
+	if len(r.ByMonthDay) > 0 {
+		matched = resolveMonthDays(r.ByMonthDay, dim)
+	}
+	if len(r.ByDay) > 0 {
+		byDay := make(map[int]bool)
+		for _, d := range r.ByDay {
+			var occurrences []int
+			for day := 1 + (int(d.Weekday)-int(first.Weekday())+7)%7; day <= dim; day += 7 {
+				occurrences = append(occurrences, day)
+			}
+			switch {
+			case d.Ordinal == 0:
+				for _, day := range occurrences {
+					byDay[day] = true
+				}
+			case d.Ordinal > 0 && d.Ordinal <= len(occurrences):
+				byDay[occurrences[d.Ordinal-1]] = true
+			case d.Ordinal < 0 && -d.Ordinal <= len(occurrences):
+				byDay[occurrences[len(occurrences)+d.Ordinal]] = true
+			}
+		}
+		if matched == nil {
+			matched = byDay
+		} else {
+			for day := range matched {
+				matched[day] = byDay[day]
+			}
+		}
+	}
+	if matched == nil && len(r.ByMonthDay) == 0 {
+		matched = map[int]bool{startDay: startDay <= dim}
+	}
+
+	var days []time.Time
+	for day, ok := range matched {
+		if ok {
+			days = append(days, first.AddDate(0, 0, day-1))
+		}
+	}
+	return days
+}
+
+// resolveMonthDays は BYMONTHDAY の値を月の日に変換します。負の値は末日から数え、その月にない日は含めません
+func resolveMonthDays(monthDays []int, dim int) map[int]bool {
+	days := make(map[int]bool, len(monthDays))
+	for _, d := range monthDays {
+		if d < 0 {
+			d = dim + 1 + d
+		}
+		if d >= 1 && d <= dim {
+			days[d] = true
+		}
+	}
+	return days
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysSinceMonday(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+// localTime は day の日付と clock の時刻を loc の壁時計として解釈した日時を返します。
+// 夏時間の開始で存在しない時刻は移行前のオフセットで解釈して後ろへずらし (RFC 5545 3.3.5)、
+// 夏時間の終了で2回ある時刻は早い方を選びます
+func localTime(day, clock time.Time, loc *time.Location) time.Time {
+	naive := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), time.UTC)
+	_, before := naive.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := naive.Add(24 * time.Hour).In(loc).Zone()
+
+	var result time.Time
+	for _, offset := range []int{before, after} {
+		t := naive.Add(-time.Duration(offset) * time.Second).In(loc)
+		if sameWallClock(t, naive) && (result.IsZero() || t.Before(result)) {
+			result = t
+		}
+	}
+	if result.IsZero() {
+		result = naive.Add(-time.Duration(before) * time.Second).In(loc)
+	}
+	return result
+}
+
+func sameWallClock(t, naive time.Time) bool {
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := naive.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2 &&
+		t.Hour() == naive.Hour() && t.Minute() == naive.Minute() && t.Second() == naive.Second()
+}
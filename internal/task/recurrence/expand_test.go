@@ -0,0 +1,307 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mustStart は zone の壁時計で value ("2006-01-02 15:04") の起点を返します
+func mustStart(t *testing.T, zone, value string) time.Time {
+	t.Helper()
+	loc, err := LoadLocation(zone)
+	require.NoError(t, err)
+	start, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	require.NoError(t, err)
+	return start
+}
+
+func expand(t *testing.T, rrule string, start time.Time, limit int) []string {
+	t.Helper()
+	rule, err := Parse(rrule)
+	require.NoError(t, err)
+
+	var result []string
+	it := rule.Iterator(start)
+	for len(result) < limit {
+		next, ok := it.Next()
+		if !ok {
+			break
+		}
+		result = append(result, next.Format(time.RFC3339))
+	}
+	return result
+}
+
+func TestIterator(t *testing.T) {
+	tests := []struct {
+		name  string
+		rrule string
+		zone  string
+		start string
+		limit int
+		want  []string
+	}{
+		{
+			name: "daily", rrule: "FREQ=DAILY", zone: "Asia/Tokyo", start: "2025-01-30 09:00", limit: 4,
+			want: []string{"2025-01-30T09:00:00+09:00", "2025-01-31T09:00:00+09:00", "2025-02-01T09:00:00+09:00", "2025-02-02T09:00:00+09:00"},
+		},
+		{
+			name: "daily_interval", rrule: "FREQ=DAILY;INTERVAL=10", zone: "UTC", start: "2024-02-20 00:00", limit: 3,
+			want: []string{"2024-02-20T00:00:00Z", "2024-03-01T00:00:00Z", "2024-03-11T00:00:00Z"},
+		},
+		{
+			name: "daily_byday", rrule: "FREQ=DAILY;BYDAY=MO,FR", zone: "UTC", start: "2025-01-01 08:00", limit: 4,
+			want: []string{"2025-01-01T08:00:00Z", "2025-01-03T08:00:00Z", "2025-01-06T08:00:00Z", "2025-01-10T08:00:00Z"},
+		},
+		{
+			name: "daily_bymonthday", rrule: "FREQ=DAILY;BYMONTHDAY=1,-1", zone: "UTC", start: "2025-01-15 08:00", limit: 4,
+			want: []string{"2025-01-15T08:00:00Z", "2025-01-31T08:00:00Z", "2025-02-01T08:00:00Z", "2025-02-28T08:00:00Z"},
+		},
+		{
+			name: "weekly_default_day", rrule: "FREQ=WEEKLY", zone: "UTC", start: "2025-01-01 10:00", limit: 3,
+			want: []string{"2025-01-01T10:00:00Z", "2025-01-08T10:00:00Z", "2025-01-15T10:00:00Z"},
+		},
+		{
+			name: "weekly_byday_interval", rrule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR,MO,WE", zone: "UTC", start: "2025-01-01 10:00", limit: 6,
+			want: []string{
+				"2025-01-01T10:00:00Z", "2025-01-03T10:00:00Z",
+				"2025-01-13T10:00:00Z", "2025-01-15T10:00:00Z", "2025-01-17T10:00:00Z",
+				"2025-01-27T10:00:00Z",
+			},
+		},
+		{
+			name: "weekly_sunday_belongs_to_week_starting_monday", rrule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU", zone: "UTC", start: "2025-01-06 10:00", limit: 4,
+			want: []string{"2025-01-06T10:00:00Z", "2025-01-12T10:00:00Z", "2025-01-20T10:00:00Z", "2025-01-26T10:00:00Z"},
+		},
+		{
+			name: "start_not_matching_rule_is_counted", rrule: "FREQ=WEEKLY;BYDAY=MO;COUNT=3", zone: "UTC", start: "2025-01-01 10:00", limit: 10,
+			want: []string{"2025-01-01T10:00:00Z", "2025-01-06T10:00:00Z", "2025-01-13T10:00:00Z"},
+		},
+		{
+			name: "monthly_skips_short_months", rrule: "FREQ=MONTHLY", zone: "UTC", start: "2025-01-31 12:00", limit: 4,
+			want: []string{"2025-01-31T12:00:00Z", "2025-03-31T12:00:00Z", "2025-05-31T12:00:00Z", "2025-07-31T12:00:00Z"},
+		},
+		{
+			name: "monthly_last_day", rrule: "FREQ=MONTHLY;BYMONTHDAY=-1", zone: "UTC", start: "2024-01-31 12:00", limit: 4,
+			want: []string{"2024-01-31T12:00:00Z", "2024-02-29T12:00:00Z", "2024-03-31T12:00:00Z", "2024-04-30T12:00:00Z"},
+		},
+		{
+			name: "monthly_last_friday", rrule: "FREQ=MONTHLY;BYDAY=-1FR", zone: "UTC", start: "2025-01-31 12:00", limit: 4,
+			want: []string{"2025-01-31T12:00:00Z", "2025-02-28T12:00:00Z", "2025-03-28T12:00:00Z", "2025-04-25T12:00:00Z"},
+		},
+		{
+			name: "monthly_second_and_fourth_monday", rrule: "FREQ=MONTHLY;BYDAY=4MO,2MO", zone: "UTC", start: "2025-01-13 12:00", limit: 4,
+			want: []string{"2025-01-13T12:00:00Z", "2025-01-27T12:00:00Z", "2025-02-10T12:00:00Z", "2025-02-24T12:00:00Z"},
+		},
+		{
+			name: "monthly_fifth_wednesday_only_when_present", rrule: "FREQ=MONTHLY;BYDAY=5WE", zone: "UTC", start: "2025-01-29 12:00", limit: 3,
+			want: []string{"2025-01-29T12:00:00Z", "2025-04-30T12:00:00Z", "2025-07-30T12:00:00Z"},
+		},
+		{
+			name: "monthly_every_friday_the_13th", rrule: "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13", zone: "UTC", start: "2025-01-01 00:00", limit: 4,
+			want: []string{"2025-01-01T00:00:00Z", "2025-06-13T00:00:00Z", "2026-02-13T00:00:00Z", "2026-03-13T00:00:00Z"},
+		},
+		{
+			name: "monthly_interval_crosses_year", rrule: "FREQ=MONTHLY;INTERVAL=5", zone: "UTC", start: "2025-10-15 12:00", limit: 3,
+			want: []string{"2025-10-15T12:00:00Z", "2026-03-15T12:00:00Z", "2026-08-15T12:00:00Z"},
+		},
+		{
+			name: "never_recurs_again", rrule: "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30", zone: "UTC", start: "2025-02-01 12:00", limit: 3,
+			want: []string{"2025-02-01T12:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := mustStart(t, tt.zone, tt.start)
+			assert.Equal(t, tt.want, expand(t, tt.rrule, start, tt.limit))
+		})
+	}
+}
+
+// TestIterator_DST は夏時間の切り替えをまたいでも壁時計の時刻が保たれ、
+// 存在しない時刻は後ろへずれ、2回ある時刻は早い方になることを確認します
+func TestIterator_DST(t *testing.T) {
+	tests := []struct {
+		name  string
+		rrule string
+		zone  string
+		start string
+		limit int
+		want  []string
+	}{
+		// America/New_York: 2025-03-09 02:00 EST → 03:00 EDT, 2025-11-02 02:00 EDT → 01:00 EST
+		{
+			name: "new_york_spring_keeps_wall_clock", rrule: "FREQ=DAILY", zone: "America/New_York", start: "2025-03-07 09:00", limit: 4,
+			want: []string{"2025-03-07T09:00:00-05:00", "2025-03-08T09:00:00-05:00", "2025-03-09T09:00:00-04:00", "2025-03-10T09:00:00-04:00"},
+		},
+		{
+			name: "new_york_spring_gap_shifts_forward", rrule: "FREQ=DAILY", zone: "America/New_York", start: "2025-03-08 02:30", limit: 3,
+			want: []string{"2025-03-08T02:30:00-05:00", "2025-03-09T03:30:00-04:00", "2025-03-10T02:30:00-04:00"},
+		},
+		{
+			name: "new_york_spring_gap_start_of_hour", rrule: "FREQ=DAILY", zone: "America/New_York", start: "2025-03-08 02:00", limit: 3,
+			want: []string{"2025-03-08T02:00:00-05:00", "2025-03-09T03:00:00-04:00", "2025-03-10T02:00:00-04:00"},
+		},
+		{
+			name: "new_york_spring_just_before_gap", rrule: "FREQ=DAILY", zone: "America/New_York", start: "2025-03-08 01:59", limit: 3,
+			want: []string{"2025-03-08T01:59:00-05:00", "2025-03-09T01:59:00-05:00", "2025-03-10T01:59:00-04:00"},
+		},
+		{
+			name: "new_york_fall_ambiguous_takes_first", rrule: "FREQ=DAILY", zone: "America/New_York", start: "2025-11-01 01:30", limit: 3,
+			want: []string{"2025-11-01T01:30:00-04:00", "2025-11-02T01:30:00-04:00", "2025-11-03T01:30:00-05:00"},
+		},
+		{
+			name: "new_york_fall_after_repeated_hour", rrule: "FREQ=DAILY", zone: "America/New_York", start: "2025-11-01 02:00", limit: 3,
+			want: []string{"2025-11-01T02:00:00-04:00", "2025-11-02T02:00:00-05:00", "2025-11-03T02:00:00-05:00"},
+		},
+		{
+			name: "new_york_weekly_sunday_gap", rrule: "FREQ=WEEKLY;BYDAY=SU", zone: "America/New_York", start: "2025-03-02 02:30", limit: 3,
+			want: []string{"2025-03-02T02:30:00-05:00", "2025-03-09T03:30:00-04:00", "2025-03-16T02:30:00-04:00"},
+		},
+		{
+			name: "new_york_weekly_sunday_overlap", rrule: "FREQ=WEEKLY;BYDAY=SU", zone: "America/New_York", start: "2025-10-26 01:30", limit: 3,
+			want: []string{"2025-10-26T01:30:00-04:00", "2025-11-02T01:30:00-04:00", "2025-11-09T01:30:00-05:00"},
+		},
+		{
+			name: "new_york_monthly_gap_does_not_drift", rrule: "FREQ=MONTHLY", zone: "America/New_York", start: "2025-02-09 02:30", limit: 3,
+			want: []string{"2025-02-09T02:30:00-05:00", "2025-03-09T03:30:00-04:00", "2025-04-09T02:30:00-04:00"},
+		},
+		// Europe/London: 2025-03-30 01:00 GMT → 02:00 BST, 2025-10-26 02:00 BST → 01:00 GMT
+		{
+			name: "london_monthly_gap", rrule: "FREQ=MONTHLY;BYMONTHDAY=30", zone: "Europe/London", start: "2025-01-30 01:30", limit: 3,
+			want: []string{"2025-01-30T01:30:00Z", "2025-03-30T02:30:00+01:00", "2025-04-30T01:30:00+01:00"},
+		},
+		{
+			name: "london_last_sunday_overlap", rrule: "FREQ=MONTHLY;BYDAY=-1SU", zone: "Europe/London", start: "2025-09-28 01:30", limit: 3,
+			want: []string{"2025-09-28T01:30:00+01:00", "2025-10-26T01:30:00+01:00", "2025-11-30T01:30:00Z"},
+		},
+		// Australia/Sydney: 2025-04-06 03:00 AEDT → 02:00 AEST, 2025-10-05 02:00 AEST → 03:00 AEDT
+		{
+			name: "sydney_autumn_overlap", rrule: "FREQ=DAILY", zone: "Australia/Sydney", start: "2025-04-05 02:30", limit: 3,
+			want: []string{"2025-04-05T02:30:00+11:00", "2025-04-06T02:30:00+11:00", "2025-04-07T02:30:00+10:00"},
+		},
+		{
+			name: "sydney_spring_gap", rrule: "FREQ=DAILY", zone: "Australia/Sydney", start: "2025-10-04 02:30", limit: 3,
+			want: []string{"2025-10-04T02:30:00+10:00", "2025-10-05T03:30:00+11:00", "2025-10-06T02:30:00+11:00"},
+		},
+		// Australia/Lord_Howe は30分だけずれる
+		{
+			name: "lord_howe_half_hour_gap", rrule: "FREQ=DAILY", zone: "Australia/Lord_Howe", start: "2025-10-04 02:15", limit: 3,
+			want: []string{"2025-10-04T02:15:00+10:30", "2025-10-05T02:45:00+11:00", "2025-10-06T02:15:00+11:00"},
+		},
+		{
+			name: "lord_howe_half_hour_overlap", rrule: "FREQ=DAILY", zone: "Australia/Lord_Howe", start: "2025-04-05 01:45", limit: 3,
+			want: []string{"2025-04-05T01:45:00+11:00", "2025-04-06T01:45:00+11:00", "2025-04-07T01:45:00+10:30"},
+		},
+		// America/Santiago は0時に切り替わるため、その日の0時台が存在しない
+		{
+			name: "santiago_midnight_gap", rrule: "FREQ=DAILY", zone: "America/Santiago", start: "2025-09-06 00:30", limit: 3,
+			want: []string{"2025-09-06T00:30:00-04:00", "2025-09-07T01:30:00-03:00", "2025-09-08T00:30:00-03:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := mustStart(t, tt.zone, tt.start)
+			assert.Equal(t, tt.want, expand(t, tt.rrule, start, tt.limit))
+		})
+	}
+}
+
+func TestIterator_Until(t *testing.T) {
+	tests := []struct {
+		name  string
+		rrule string
+		want  int
+	}{
+		{name: "utc_inclusive", rrule: "FREQ=DAILY;UNTIL=20250310T130000Z", want: 3},
+		{name: "utc_exclusive", rrule: "FREQ=DAILY;UNTIL=20250310T125959Z", want: 2},
+		// タイムゾーンのない UNTIL は繰り返しのタイムゾーンで解釈するため、夏時間に入った後も 09:00 が含まれる
+		{name: "floating_inclusive_after_dst", rrule: "FREQ=DAILY;UNTIL=20250310T090000", want: 3},
+		{name: "floating_exclusive", rrule: "FREQ=DAILY;UNTIL=20250310T085959", want: 2},
+		{name: "date_includes_whole_day", rrule: "FREQ=DAILY;UNTIL=20250310", want: 3},
+		{name: "before_start", rrule: "FREQ=DAILY;UNTIL=20250307", want: 0},
+	}
+
+	start := mustStart(t, "America/New_York", "2025-03-08 09:00")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, expand(t, tt.rrule, start, 10), tt.want)
+		})
+	}
+}
+
+func TestRule_Occurrences(t *testing.T) {
+	start := mustStart(t, "Asia/Tokyo", "2025-01-01 09:00")
+
+	t.Run("after", func(t *testing.T) {
+		rule, err := Parse("FREQ=DAILY")
+		require.NoError(t, err)
+		occurrences := rule.Occurrences(start, mustStart(t, "Asia/Tokyo", "2025-01-10 12:00"), 2)
+		require.Len(t, occurrences, 2)
+		assert.Equal(t, "2025-01-11T09:00:00+09:00", occurrences[0].Format(time.RFC3339))
+		assert.Equal(t, "2025-01-12T09:00:00+09:00", occurrences[1].Format(time.RFC3339))
+	})
+
+	t.Run("after_is_exclusive", func(t *testing.T) {
+		rule, err := Parse("FREQ=WEEKLY")
+		require.NoError(t, err)
+		next, ok := rule.Next(start, start)
+		require.True(t, ok)
+		assert.Equal(t, "2025-01-08T09:00:00+09:00", next.Format(time.RFC3339))
+	})
+
+	t.Run("count_exhausted", func(t *testing.T) {
+		rule, err := Parse("FREQ=DAILY;COUNT=3")
+		require.NoError(t, err)
+		_, ok := rule.Next(start, mustStart(t, "Asia/Tokyo", "2025-01-03 09:00"))
+		assert.False(t, ok)
+		next, ok := rule.Next(start, mustStart(t, "Asia/Tokyo", "2025-01-02 09:00"))
+		require.True(t, ok)
+		assert.Equal(t, "2025-01-03T09:00:00+09:00", next.Format(time.RFC3339))
+	})
+
+	t.Run("never_recurs_again", func(t *testing.T) {
+		rule, err := Parse("FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30")
+		require.NoError(t, err)
+		_, ok := rule.Next(mustStart(t, "UTC", "2025-02-01 00:00"), mustStart(t, "UTC", "2025-02-01 00:00"))
+		assert.False(t, ok)
+	})
+}
+
+func TestIterator_StrictlyIncreasing(t *testing.T) {
+	// 夏時間の切り替えを含む1年分で、発生日時が重複せず昇順であることを確認する
+	rules := []string{
+		"FREQ=DAILY",
+		"FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR,SA,SU",
+		"FREQ=MONTHLY;BYMONTHDAY=1,-1",
+		"FREQ=MONTHLY;BYDAY=SU",
+	}
+	zones := []string{"America/New_York", "Europe/London", "Australia/Sydney", "Australia/Lord_Howe", "America/Santiago"}
+	clocks := []string{"00:00", "00:30", "01:00", "01:30", "02:00", "02:30", "03:00", "23:59"}
+
+	for _, rrule := range rules {
+		rule, err := Parse(rrule)
+		require.NoError(t, err)
+		for _, zone := range zones {
+			for _, clock := range clocks {
+				start := mustStart(t, zone, "2025-01-01 "+clock)
+				it := rule.Iterator(start)
+				prev, _ := it.Next()
+				for i := 0; i < 370; i++ {
+					next, ok := it.Next()
+					require.True(t, ok)
+					if !next.After(prev) {
+						t.Fatalf("%s %s %s: %s が %s より後になっていません", rrule, zone, clock, next, prev)
+					}
+					assert.Equal(t, next.Location(), start.Location())
+					prev = next
+				}
+			}
+		}
+	}
+}
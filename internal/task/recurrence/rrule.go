@@ -0,0 +1,262 @@
+// Package recurrence は iCalendar (RFC 5545) の RRULE のうち、繰り返しタスクで使う範囲の解析と展開を行います。
+//
+// 対応する要素は FREQ (DAILY/WEEKLY/MONTHLY)、INTERVAL、BYDAY、BYMONTHDAY、COUNT、UNTIL、WKST=MO です。
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency は繰り返しの単位です
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// MaxInterval は INTERVAL に指定できる上限です
+const MaxInterval = 1000
+
+// WeekdayNum は BYDAY の1要素です。Ordinal は月の第何週か（負の値は末尾から）で、0 は毎週を表します
+type WeekdayNum struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+type untilForm int
+
+const (
+	untilNone untilForm = iota
+	// untilUTC は UTC で指定された日時です
+	untilUTC
+	// untilFloating はタイムゾーンを持たない日時で、繰り返しのタイムゾーンで解釈します
+	untilFloating
+	// untilDate は日付のみの指定で、その日の終わりまでを含みます
+	untilDate
+)
+
+// Rule は解析済みの RRULE です
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	// Count は起点を含む発生回数の上限です。0 は無制限です
+	Count int
+	// until は UNTIL の値です。untilFloating と untilDate では UTC の壁時計として保持します
+	until     time.Time
+	untilForm untilForm
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse は RRULE 文字列を解析します。先頭の "RRULE:" は省略できます
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("繰り返しルールが空です")
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("繰り返しルールの要素 %q が不正です", part)
+		}
+		key = strings.ToUpper(key)
+		if seen[key] {
+			return nil, fmt.Errorf("繰り返しルールの %s が重複しています", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq, err = parseFrequency(value)
+		case "INTERVAL":
+			rule.Interval, err = parseInt(key, value, 1, MaxInterval)
+		case "COUNT":
+			rule.Count, err = parseInt(key, value, 1, 1<<31-1)
+		case "UNTIL":
+			rule.until, rule.untilForm, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseByMonthDay(value)
+		case "WKST":
+			if strings.ToUpper(value) != "MO" {
+				err = errors.New("WKST は MO のみ対応しています")
+			}
+		default:
+			err = fmt.Errorf("繰り返しルールの %s には対応していません", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *Rule) validate() error {
+	if r.Freq == "" {
+		return errors.New("FREQ は必須です")
+	}
+	if r.Count > 0 && r.untilForm != untilNone {
+		return errors.New("COUNT と UNTIL は同時に指定できません")
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return errors.New("BYMONTHDAY は FREQ=WEEKLY では使用できません")
+	}
+	if r.Freq != Monthly {
+		for _, d := range r.ByDay {
+			if d.Ordinal != 0 {
+				return errors.New("BYDAY の序数は FREQ=MONTHLY でのみ使用できます")
+			}
+		}
+	}
+	return nil
+}
+
+func parseFrequency(value string) (Frequency, error) {
+	switch freq := Frequency(strings.ToUpper(value)); freq {
+	case Daily, Weekly, Monthly:
+		return freq, nil
+	default:
+		return "", fmt.Errorf("FREQ=%s には対応していません", value)
+	}
+}
+
+func parseInt(key, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%s の値 %q が不正です", key, value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, untilForm, error) {
+	switch {
+	case len(value) == len("20060102"):
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			break
+		}
+		return t.Add(24*time.Hour - time.Second), untilDate, nil
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			break
+		}
+		return t, untilUTC, nil
+	default:
+		t, err := time.Parse("20060102T150405", value)
+		if err != nil {
+			break
+		}
+		return t, untilFloating, nil
+	}
+	return time.Time{}, untilNone, fmt.Errorf("UNTIL の値 %q が不正です", value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	seen := make(map[WeekdayNum]bool)
+	for _, item := range strings.Split(strings.ToUpper(value), ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("BYDAY の値 %q が不正です", item)
+		}
+		weekday, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("BYDAY の値 %q が不正です", item)
+		}
+		day := WeekdayNum{Weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("BYDAY の値 %q が不正です", item)
+			}
+			day.Ordinal = n
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	seen := make(map[int]bool)
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n < -31 || n > 31 {
+			return nil, fmt.Errorf("BYMONTHDAY の値 %q が不正です", item)
+		}
+		if !seen[n] {
+			seen[n] = true
+			days = append(days, n)
+		}
+	}
+	sort.Ints(days)
+	return days, nil
+}
+
+// String は RRULE 文字列を返します。要素の順序は FREQ、INTERVAL、BYDAY、BYMONTHDAY、COUNT、UNTIL です
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = weekdayNames[d.Weekday]
+			if d.Ordinal != 0 {
+				days[i] = strconv.Itoa(d.Ordinal) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	switch r.untilForm {
+	case untilUTC:
+		parts = append(parts, "UNTIL="+r.until.Format("20060102T150405Z"))
+	case untilFloating:
+		parts = append(parts, "UNTIL="+r.until.Format("20060102T150405"))
+	case untilDate:
+		parts = append(parts, "UNTIL="+r.until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
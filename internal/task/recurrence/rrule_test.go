@@ -0,0 +1,92 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "daily", input: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "prefix_and_lowercase", input: "RRULE:freq=weekly;byday=mo,we", want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{name: "interval", input: "FREQ=DAILY;INTERVAL=3", want: "FREQ=DAILY;INTERVAL=3"},
+		{name: "interval_one_omitted", input: "FREQ=DAILY;INTERVAL=1", want: "FREQ=DAILY"},
+		{name: "count", input: "FREQ=WEEKLY;COUNT=10", want: "FREQ=WEEKLY;COUNT=10"},
+		{name: "until_utc", input: "FREQ=DAILY;UNTIL=20250331T150000Z", want: "FREQ=DAILY;UNTIL=20250331T150000Z"},
+		{name: "until_floating", input: "FREQ=DAILY;UNTIL=20250331T090000", want: "FREQ=DAILY;UNTIL=20250331T090000"},
+		{name: "until_date", input: "FREQ=DAILY;UNTIL=20250331", want: "FREQ=DAILY;UNTIL=20250331"},
+		{name: "monthly_ordinal", input: "FREQ=MONTHLY;BYDAY=-1FR,2MO", want: "FREQ=MONTHLY;BYDAY=-1FR,2MO"},
+		{name: "monthly_monthday", input: "FREQ=MONTHLY;BYMONTHDAY=-1,15,1", want: "FREQ=MONTHLY;BYMONTHDAY=-1,1,15"},
+		{name: "duplicate_day", input: "FREQ=WEEKLY;BYDAY=MO,MO", want: "FREQ=WEEKLY;BYDAY=MO"},
+		{name: "wkst", input: "FREQ=WEEKLY;WKST=MO", want: "FREQ=WEEKLY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rule.String())
+
+			reparsed, err := Parse(rule.String())
+			require.NoError(t, err)
+			assert.Equal(t, rule, reparsed)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{name: "missing_freq", input: "INTERVAL=2"},
+		{name: "unsupported_freq", input: "FREQ=YEARLY"},
+		{name: "malformed_part", input: "FREQ=DAILY;COUNT"},
+		{name: "duplicate_key", input: "FREQ=DAILY;FREQ=WEEKLY"},
+		{name: "zero_interval", input: "FREQ=DAILY;INTERVAL=0"},
+		{name: "interval_too_large", input: "FREQ=DAILY;INTERVAL=1001"},
+		{name: "zero_count", input: "FREQ=DAILY;COUNT=0"},
+		{name: "count_and_until", input: "FREQ=DAILY;COUNT=3;UNTIL=20250101"},
+		{name: "bad_until", input: "FREQ=DAILY;UNTIL=2025-01-01"},
+		{name: "bad_weekday", input: "FREQ=WEEKLY;BYDAY=XX"},
+		{name: "ordinal_out_of_range", input: "FREQ=MONTHLY;BYDAY=6MO"},
+		{name: "zero_ordinal", input: "FREQ=MONTHLY;BYDAY=0MO"},
+		{name: "ordinal_in_weekly", input: "FREQ=WEEKLY;BYDAY=1MO"},
+		{name: "monthday_in_weekly", input: "FREQ=WEEKLY;BYMONTHDAY=1"},
+		{name: "monthday_out_of_range", input: "FREQ=MONTHLY;BYMONTHDAY=32"},
+		{name: "zero_monthday", input: "FREQ=MONTHLY;BYMONTHDAY=0"},
+		{name: "unsupported_part", input: "FREQ=MONTHLY;BYSETPOS=-1"},
+		{name: "unsupported_wkst", input: "FREQ=WEEKLY;WKST=SU"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.input)
+			assert.Error(t, err)
+			assert.Nil(t, rule)
+		})
+	}
+}
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+
+	loc, err = LoadLocation("")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	_, err = LoadLocation("Local")
+	assert.Error(t, err)
+	_, err = LoadLocation("Mars/Olympus_Mons")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrAttachmentNotFound is returned when an attachment is not found
+var ErrAttachmentNotFound = apperrors.NewNotFoundError("添付ファイルが見つかりません", nil).WithReason("ATTACHMENT_NOT_FOUND")
+
+type AttachmentRepository interface {
+	// Create は添付ファイルのメタデータを保存します。ID が未設定の場合は採番します
+	Create(ctx context.Context, attachment *model.Attachment) (*model.Attachment, error)
+	FindByID(ctx context.Context, id string) (*model.Attachment, error)
+	// FindByTask はタスクの添付ファイルを古い順に返します
+	FindByTask(ctx context.Context, taskID primitive.ObjectID) ([]*model.Attachment, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// UsageByUser はユーザーの添付ファイルの合計サイズを返します
+	UsageByUser(ctx context.Context, userID string) (int64, error)
+}
+
+type mongoAttachmentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAttachmentRepository(db *mongo.Database) AttachmentRepository {
+	return &mongoAttachmentRepository{
+		collection: db.Collection("attachments"),
+	}
+}
+
+func (r *mongoAttachmentRepository) Create(ctx context.Context, attachment *model.Attachment) (*model.Attachment, error) {
+	if attachment.ID.IsZero() {
+		attachment.ID = primitive.NewObjectID()
+	}
+	attachment.CreatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, attachment); err != nil {
+		return nil, apperrors.NewInternalError("添付ファイルの保存に失敗しました", err)
+	}
+	return attachment, nil
+}
+
+func (r *mongoAttachmentRepository) FindByID(ctx context.Context, id string) (*model.Attachment, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	var attachment model.Attachment
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&attachment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, apperrors.NewInternalError("添付ファイルの取得に失敗しました", err)
+	}
+	return &attachment, nil
+}
+
+func (r *mongoAttachmentRepository) FindByTask(ctx context.Context, taskID primitive.ObjectID) ([]*model.Attachment, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"task_id": taskID}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("添付ファイルの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	var attachments []*model.Attachment
+	if err := cursor.All(ctx, &attachments); err != nil {
+		return nil, apperrors.NewInternalError("添付ファイルのデコードに失敗しました", err)
+	}
+	return attachments, nil
+}
+
+func (r *mongoAttachmentRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return apperrors.NewInternalError("添付ファイルの削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
+
+func (r *mongoAttachmentRepository) UsageByUser(ctx context.Context, userID string) (int64, error) {
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$size"}}}},
+	})
+	if err != nil {
+		return 0, apperrors.NewInternalError("添付ファイルの使用量の取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, apperrors.NewInternalError("添付ファイルの使用量のデコードに失敗しました", err)
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Total, nil
+}
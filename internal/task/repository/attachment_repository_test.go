@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoAttachmentRepository_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("keeps_preassigned_id", func(mt *mtest.T) {
+		repo := &mongoAttachmentRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		id := primitive.NewObjectID()
+
+		attachment, err := repo.Create(context.Background(), &model.Attachment{ID: id, Name: "spec.pdf", StorageKey: id.Hex()})
+		assert.NoError(t, err)
+		assert.Equal(t, id, attachment.ID)
+		assert.False(t, attachment.CreatedAt.IsZero())
+	})
+}
+
+func TestMongoAttachmentRepository_FindByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("invalid_id", func(mt *mtest.T) {
+		repo := &mongoAttachmentRepository{collection: mt.Coll}
+		_, err := repo.FindByID(context.Background(), "invalid")
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoAttachmentRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.attachments", mtest.FirstBatch))
+
+		_, err := repo.FindByID(context.Background(), primitive.NewObjectID().Hex())
+		assert.ErrorIs(t, err, ErrAttachmentNotFound)
+	})
+}
+
+func TestMongoAttachmentRepository_UsageByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("sum", func(mt *mtest.T) {
+		repo := &mongoAttachmentRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.attachments", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "total", Value: int64(3072)},
+		}))
+
+		used, err := repo.UsageByUser(context.Background(), "user1")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3072), used)
+
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(t, "user1", cmd.Lookup("pipeline").Array().Index(0).Value().Document().Lookup("$match", "user_id").StringValue())
+	})
+
+	mt.Run("no_attachments", func(mt *mtest.T) {
+		repo := &mongoAttachmentRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.attachments", mtest.FirstBatch))
+
+		used, err := repo.UsageByUser(context.Background(), "user1")
+		assert.NoError(t, err)
+		assert.Zero(t, used)
+	})
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCalendarFeedNotFound is returned when a calendar feed is not found
+var ErrCalendarFeedNotFound = apperrors.NewNotFoundError("カレンダーフィードが見つかりません", nil).WithReason("CALENDAR_FEED_NOT_FOUND")
+
+type CalendarFeedRepository interface {
+	// Rotate はユーザーのフィードのトークンを tokenHash に置き換えます。フィードがない場合は作成します
+	Rotate(ctx context.Context, userID string, tokenHash string) (*model.CalendarFeed, error)
+	FindByUserID(ctx context.Context, userID string) (*model.CalendarFeed, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.CalendarFeed, error)
+	// Delete はユーザーのフィードを削除し、それまでのトークンを使えなくします
+	Delete(ctx context.Context, userID string) error
+}
+
+type mongoCalendarFeedRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCalendarFeedRepository(db *mongo.Database) CalendarFeedRepository {
+	return &mongoCalendarFeedRepository{
+		collection: db.Collection("calendar_feeds"),
+	}
+}
+
+func (r *mongoCalendarFeedRepository) Rotate(ctx context.Context, userID string, tokenHash string) (*model.CalendarFeed, error) {
+	now := time.Now()
+	var feed model.CalendarFeed
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"user_id": userID},
+		bson.M{
+			"$setOnInsert": bson.M{"created_at": now},
+			"$set":         bson.M{"token_hash": tokenHash, "rotated_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&feed)
+	if err != nil {
+		return nil, apperrors.NewInternalError("カレンダーフィードのトークンの発行に失敗しました", err)
+	}
+	return &feed, nil
+}
+
+func (r *mongoCalendarFeedRepository) FindByUserID(ctx context.Context, userID string) (*model.CalendarFeed, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID})
+}
+
+func (r *mongoCalendarFeedRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.CalendarFeed, error) {
+	return r.findOne(ctx, bson.M{"token_hash": tokenHash})
+}
+
+func (r *mongoCalendarFeedRepository) findOne(ctx context.Context, filter bson.M) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	if err := r.collection.FindOne(ctx, filter).Decode(&feed); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCalendarFeedNotFound
+		}
+		return nil, apperrors.NewInternalError("カレンダーフィードの取得に失敗しました", err)
+	}
+	return &feed, nil
+}
+
+func (r *mongoCalendarFeedRepository) Delete(ctx context.Context, userID string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return apperrors.NewInternalError("カレンダーフィードの削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoCalendarFeedRepository_Rotate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("upsert", func(mt *mtest.T) {
+		repo := &mongoCalendarFeedRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "user_id", Value: "user1"},
+				{Key: "token_hash", Value: "hash2"},
+			}},
+		})
+
+		feed, err := repo.Rotate(context.Background(), "user1", "hash2")
+		require.NoError(t, err)
+		assert.Equal(t, "hash2", feed.TokenHash)
+
+		command := mt.GetStartedEvent().Command
+		assert.True(t, command.Lookup("upsert").Boolean())
+		assert.Equal(t, "hash2", command.Lookup("update", "$set", "token_hash").StringValue())
+	})
+}
+
+func TestMongoCalendarFeedRepository_FindByTokenHash(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoCalendarFeedRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.calendar_feeds", mtest.FirstBatch))
+
+		_, err := repo.FindByTokenHash(context.Background(), "hash")
+		assert.ErrorIs(t, err, ErrCalendarFeedNotFound)
+	})
+}
+
+func TestMongoCalendarFeedRepository_Delete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoCalendarFeedRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		err := repo.Delete(context.Background(), "user1")
+		assert.ErrorIs(t, err, ErrCalendarFeedNotFound)
+	})
+}
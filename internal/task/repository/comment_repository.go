@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCommentNotFound is returned when a comment is not found
+var ErrCommentNotFound = apperrors.NewNotFoundError("コメントが見つかりません", nil).WithReason("COMMENT_NOT_FOUND")
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *model.Comment) (*model.Comment, error)
+	FindByID(ctx context.Context, id string) (*model.Comment, error)
+	// FindByTask はタスクのコメントを古い順に返します。after を指定するとそれより後のコメントを返します
+	FindByTask(ctx context.Context, taskID primitive.ObjectID, after primitive.ObjectID, limit int32) ([]*model.Comment, error)
+	// Update は本文・メンション・編集履歴を更新します
+	Update(ctx context.Context, comment *model.Comment) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type mongoCommentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCommentRepository(db *mongo.Database) CommentRepository {
+	return &mongoCommentRepository{
+		collection: db.Collection("comments"),
+	}
+}
+
+func (r *mongoCommentRepository) Create(ctx context.Context, comment *model.Comment) (*model.Comment, error) {
+	comment.ID = primitive.NewObjectID()
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+
+	if _, err := r.collection.InsertOne(ctx, comment); err != nil {
+		return nil, apperrors.NewInternalError("コメントの作成に失敗しました", err)
+	}
+	return comment, nil
+}
+
+func (r *mongoCommentRepository) FindByID(ctx context.Context, id string) (*model.Comment, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	var comment model.Comment
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&comment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCommentNotFound
+		}
+		return nil, apperrors.NewInternalError("コメントの取得に失敗しました", err)
+	}
+	return &comment, nil
+}
+
+func (r *mongoCommentRepository) FindByTask(ctx context.Context, taskID primitive.ObjectID, after primitive.ObjectID, limit int32) ([]*model.Comment, error) {
+	filter := bson.M{"task_id": taskID}
+	if !after.IsZero() {
+		filter["_id"] = bson.M{"$gt": after}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, apperrors.NewInternalError("コメントの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	var comments []*model.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, apperrors.NewInternalError("コメントのデコードに失敗しました", err)
+	}
+	return comments, nil
+}
+
+func (r *mongoCommentRepository) Update(ctx context.Context, comment *model.Comment) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": comment.ID}, bson.M{"$set": bson.M{
+		"body":       comment.Body,
+		"mentions":   comment.Mentions,
+		"revisions":  comment.Revisions,
+		"updated_at": comment.UpdatedAt,
+		"edited_at":  comment.EditedAt,
+	}})
+	if err != nil {
+		return apperrors.NewInternalError("コメントの更新に失敗しました", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *mongoCommentRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return apperrors.NewInternalError("コメントの削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
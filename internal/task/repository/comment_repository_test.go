@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoCommentRepository_FindByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("invalid_id", func(mt *mtest.T) {
+		repo := &mongoCommentRepository{collection: mt.Coll}
+		_, err := repo.FindByID(context.Background(), "invalid")
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoCommentRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch))
+
+		_, err := repo.FindByID(context.Background(), primitive.NewObjectID().Hex())
+		assert.ErrorIs(t, err, ErrCommentNotFound)
+	})
+}
+
+func TestMongoCommentRepository_FindByTask(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("after_cursor", func(mt *mtest.T) {
+		repo := &mongoCommentRepository{collection: mt.Coll}
+		taskID := primitive.NewObjectID()
+		after := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.comments", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "task_id", Value: taskID},
+			{Key: "body", Value: "コメント"},
+		}))
+
+		comments, err := repo.FindByTask(context.Background(), taskID, after, 21)
+		assert.NoError(t, err)
+		assert.Len(t, comments, 1)
+
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(t, after, cmd.Lookup("filter", "_id", "$gt").ObjectID())
+		assert.Equal(t, int32(1), cmd.Lookup("sort", "_id").Int32())
+		assert.Equal(t, int64(21), cmd.Lookup("limit").Int64())
+	})
+}
+
+func TestMongoCommentRepository_Update(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoCommentRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		err := repo.Update(context.Background(), &model.Comment{ID: primitive.NewObjectID(), Body: "編集", UpdatedAt: time.Now()})
+		assert.ErrorIs(t, err, ErrCommentNotFound)
+	})
+}
+
+func TestMongoMentionRepository_Claim(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("nothing_due", func(mt *mtest.T) {
+		repo := &mongoMentionRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		job, err := repo.Claim(context.Background(), "worker1", time.Now(), time.Minute)
+		assert.Nil(t, job)
+		assert.ErrorIs(t, err, ErrNoMentionDue)
+	})
+}
+
+func TestMongoMentionRepository_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("empty", func(mt *mtest.T) {
+		repo := &mongoMentionRepository{collection: mt.Coll}
+		assert.NoError(t, repo.Create(context.Background(), nil))
+	})
+
+	mt.Run("pending", func(mt *mtest.T) {
+		repo := &mongoMentionRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		job := &model.MentionJob{UserID: "user2"}
+		assert.NoError(t, repo.Create(context.Background(), []*model.MentionJob{job}))
+		assert.Equal(t, model.ReminderStatePending, job.State)
+		assert.False(t, job.AvailableAt.IsZero())
+	})
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCustomFieldNotFound is returned when a custom field definition is not found
+var ErrCustomFieldNotFound = apperrors.NewNotFoundError("カスタムフィールドが見つかりません", nil).WithReason("CUSTOM_FIELD_NOT_FOUND")
+
+// ErrCustomFieldAlreadyExists is returned when a custom field with the same name already exists
+var ErrCustomFieldAlreadyExists = apperrors.NewAlreadyExistsError("同じ名前のカスタムフィールドが既に存在します", nil).WithReason("CUSTOM_FIELD_ALREADY_EXISTS")
+
+type CustomFieldRepository interface {
+	Create(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error)
+	FindByID(ctx context.Context, ownerID string, id string) (*model.CustomFieldDefinition, error)
+	FindByOwnerID(ctx context.Context, ownerID string) ([]*model.CustomFieldDefinition, error)
+	Update(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error)
+	// Delete は定義を削除し、所有者のタスクに設定された値も取り除きます
+	Delete(ctx context.Context, ownerID string, id string) error
+}
+
+type mongoCustomFieldRepository struct {
+	collection *mongo.Collection
+	tasks      *mongo.Collection
+}
+
+func NewCustomFieldRepository(db *mongo.Database) CustomFieldRepository {
+	return &mongoCustomFieldRepository{
+		collection: db.Collection("custom_fields"),
+		tasks:      db.Collection("tasks"),
+	}
+}
+
+func (r *mongoCustomFieldRepository) Create(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error) {
+	field.ID = primitive.NewObjectID()
+	field.CreatedAt = time.Now()
+	field.UpdatedAt = field.CreatedAt
+
+	_, err := r.collection.InsertOne(ctx, field)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrCustomFieldAlreadyExists
+		}
+		return nil, apperrors.NewInternalError("カスタムフィールドの作成に失敗しました", err)
+	}
+
+	return field, nil
+}
+
+func (r *mongoCustomFieldRepository) FindByID(ctx context.Context, ownerID string, id string) (*model.CustomFieldDefinition, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	var field model.CustomFieldDefinition
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "owner_id": ownerID}).Decode(&field)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCustomFieldNotFound
+		}
+		return nil, apperrors.NewInternalError("カスタムフィールドの取得に失敗しました", err)
+	}
+
+	return &field, nil
+}
+
+func (r *mongoCustomFieldRepository) FindByOwnerID(ctx context.Context, ownerID string) ([]*model.CustomFieldDefinition, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"owner_id": ownerID}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("カスタムフィールドの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	fields := []*model.CustomFieldDefinition{}
+	if err := cursor.All(ctx, &fields); err != nil {
+		return nil, apperrors.NewInternalError("カスタムフィールドの取得に失敗しました", err)
+	}
+
+	return fields, nil
+}
+
+func (r *mongoCustomFieldRepository) Update(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error) {
+	field.UpdatedAt = time.Now()
+
+	var updated model.CustomFieldDefinition
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": field.ID, "owner_id": field.OwnerID},
+		bson.M{"$set": bson.M{
+			"name":       field.Name,
+			"options":    field.Options,
+			"required":   field.Required,
+			"updated_at": field.UpdatedAt,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCustomFieldNotFound
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrCustomFieldAlreadyExists
+		}
+		return nil, apperrors.NewInternalError("カスタムフィールドの更新に失敗しました", err)
+	}
+
+	return &updated, nil
+}
+
+func (r *mongoCustomFieldRepository) Delete(ctx context.Context, ownerID string, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID, "owner_id": ownerID})
+	if err != nil {
+		return apperrors.NewInternalError("カスタムフィールドの削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrCustomFieldNotFound
+	}
+
+	valuePath := "custom_fields." + objectID.Hex()
+	_, err = r.tasks.UpdateMany(ctx,
+		bson.M{"user_id": ownerID, valuePath: bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{valuePath: ""}},
+	)
+	if err != nil {
+		return apperrors.NewInternalError("タスクのカスタムフィールドの削除に失敗しました", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrDependencyNotFound is returned when a dependency is not found
+var ErrDependencyNotFound = apperrors.NewNotFoundError("依存関係が見つかりません", nil).WithReason("DEPENDENCY_NOT_FOUND")
+
+// ErrDependencyAlreadyExists is returned when the same dependency already exists
+var ErrDependencyAlreadyExists = apperrors.NewAlreadyExistsError("同じ依存関係が既に存在します", nil).WithReason("DEPENDENCY_ALREADY_EXISTS")
+
+type DependencyRepository interface {
+	Create(ctx context.Context, dep *model.TaskDependency) (*model.TaskDependency, error)
+	Delete(ctx context.Context, taskID primitive.ObjectID, blockerID primitive.ObjectID) error
+	// FindBlockers は taskID をブロックしている依存関係を返します
+	FindBlockers(ctx context.Context, taskID primitive.ObjectID) ([]*model.TaskDependency, error)
+	// FindBlocked は blockerID がブロックしている依存関係を返します
+	FindBlocked(ctx context.Context, blockerID primitive.ObjectID) ([]*model.TaskDependency, error)
+	FindByUserID(ctx context.Context, userID string) ([]*model.TaskDependency, error)
+	// DeleteByTask はタスクが関係するすべての依存関係を削除します
+	DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error
+}
+
+type mongoDependencyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDependencyRepository(db *mongo.Database) DependencyRepository {
+	return &mongoDependencyRepository{
+		collection: db.Collection("task_dependencies"),
+	}
+}
+
+func (r *mongoDependencyRepository) Create(ctx context.Context, dep *model.TaskDependency) (*model.TaskDependency, error) {
+	dep.ID = primitive.NewObjectID()
+	dep.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, dep)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrDependencyAlreadyExists
+		}
+		return nil, apperrors.NewInternalError("依存関係の作成に失敗しました", err)
+	}
+
+	return dep, nil
+}
+
+func (r *mongoDependencyRepository) Delete(ctx context.Context, taskID primitive.ObjectID, blockerID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"task_id": taskID, "blocker_id": blockerID})
+	if err != nil {
+		return apperrors.NewInternalError("依存関係の削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrDependencyNotFound
+	}
+	return nil
+}
+
+func (r *mongoDependencyRepository) FindBlockers(ctx context.Context, taskID primitive.ObjectID) ([]*model.TaskDependency, error) {
+	return r.find(ctx, bson.M{"task_id": taskID})
+}
+
+func (r *mongoDependencyRepository) FindBlocked(ctx context.Context, blockerID primitive.ObjectID) ([]*model.TaskDependency, error) {
+	return r.find(ctx, bson.M{"blocker_id": blockerID})
+}
+
+func (r *mongoDependencyRepository) FindByUserID(ctx context.Context, userID string) ([]*model.TaskDependency, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+func (r *mongoDependencyRepository) find(ctx context.Context, filter bson.M) ([]*model.TaskDependency, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("依存関係の取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	deps := []*model.TaskDependency{}
+	if err := cursor.All(ctx, &deps); err != nil {
+		return nil, apperrors.NewInternalError("依存関係の取得に失敗しました", err)
+	}
+	return deps, nil
+}
+
+func (r *mongoDependencyRepository) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"task_id": taskID},
+		bson.M{"blocker_id": taskID},
+	}})
+	if err != nil {
+		return apperrors.NewInternalError("依存関係の削除に失敗しました", err)
+	}
+	return nil
+}
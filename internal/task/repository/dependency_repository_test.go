@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoDependencyRepository_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("duplicate", func(mt *mtest.T) {
+		repo := &mongoDependencyRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		dep, err := repo.Create(context.Background(), &model.TaskDependency{
+			UserID:    "user1",
+			TaskID:    primitive.NewObjectID(),
+			BlockerID: primitive.NewObjectID(),
+		})
+		assert.Nil(t, dep)
+		assert.True(t, apperrors.IsAlreadyExists(err))
+	})
+}
+
+func TestMongoDependencyRepository_Delete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoDependencyRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		err := repo.Delete(context.Background(), primitive.NewObjectID(), primitive.NewObjectID())
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+
+	mt.Run("by_task_matches_both_sides", func(mt *mtest.T) {
+		repo := &mongoDependencyRepository{collection: mt.Coll}
+		taskID := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		assert.NoError(t, repo.DeleteByTask(context.Background(), taskID))
+
+		q := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document().Lookup("q", "$or").Array()
+		assert.Equal(t, taskID, q.Index(0).Value().Document().Lookup("task_id").ObjectID())
+		assert.Equal(t, taskID, q.Index(1).Value().Document().Lookup("blocker_id").ObjectID())
+	})
+}
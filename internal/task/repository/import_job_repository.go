@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrImportJobNotFound is returned when an import job is not found
+var ErrImportJobNotFound = apperrors.NewNotFoundError("取り込みジョブが見つかりません", nil).WithReason("IMPORT_JOB_NOT_FOUND")
+
+type ImportJobRepository interface {
+	// Start はユーザーの取り込みジョブを返します。存在しない場合は作成します
+	Start(ctx context.Context, userID string, jobID string) (*model.ImportJob, error)
+	FindByJobID(ctx context.Context, userID string, jobID string) (*model.ImportJob, error)
+	// Advance は lastRow までの書き込みを終えたことを記録し、取り込んだ件数と失敗した件数を加算します
+	Advance(ctx context.Context, id primitive.ObjectID, lastRow int64, imported int64, failed int64) error
+	Complete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type mongoImportJobRepository struct {
+	collection *mongo.Collection
+}
+
+func NewImportJobRepository(db *mongo.Database) ImportJobRepository {
+	return &mongoImportJobRepository{
+		collection: db.Collection("import_jobs"),
+	}
+}
+
+func (r *mongoImportJobRepository) Start(ctx context.Context, userID string, jobID string) (*model.ImportJob, error) {
+	now := time.Now()
+	var job model.ImportJob
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"user_id": userID, "job_id": jobID},
+		bson.M{
+			"$setOnInsert": bson.M{
+				"status":     model.ImportJobRunning,
+				"last_row":   int64(0),
+				"imported":   int64(0),
+				"failed":     int64(0),
+				"created_at": now,
+			},
+			"$set": bson.M{"updated_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&job)
+	if err != nil {
+		return nil, apperrors.NewInternalError("取り込みジョブの開始に失敗しました", err)
+	}
+	return &job, nil
+}
+
+func (r *mongoImportJobRepository) FindByJobID(ctx context.Context, userID string, jobID string) (*model.ImportJob, error) {
+	var job model.ImportJob
+	if err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "job_id": jobID}).Decode(&job); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrImportJobNotFound
+		}
+		return nil, apperrors.NewInternalError("取り込みジョブの取得に失敗しました", err)
+	}
+	return &job, nil
+}
+
+func (r *mongoImportJobRepository) Advance(ctx context.Context, id primitive.ObjectID, lastRow int64, imported int64, failed int64) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$max": bson.M{"last_row": lastRow},
+			"$inc": bson.M{"imported": imported, "failed": failed},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return apperrors.NewInternalError("取り込みジョブの更新に失敗しました", err)
+	}
+	return nil
+}
+
+func (r *mongoImportJobRepository) Complete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": model.ImportJobCompleted, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return apperrors.NewInternalError("取り込みジョブの更新に失敗しました", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoImportJobRepository_FindByJobID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoImportJobRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.import_jobs", mtest.FirstBatch))
+
+		_, err := repo.FindByJobID(context.Background(), "user1", "job1")
+		assert.ErrorIs(t, err, ErrImportJobNotFound)
+	})
+}
+
+func TestMongoImportJobRepository_Advance(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("monotonic_last_row", func(mt *mtest.T) {
+		repo := &mongoImportJobRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		err := repo.Advance(context.Background(), primitive.NewObjectID(), 500, 498, 2)
+		assert.NoError(t, err)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u").Document()
+		assert.Equal(t, int64(500), update.Lookup("$max", "last_row").Int64())
+		assert.Equal(t, int64(2), update.Lookup("$inc", "failed").Int64())
+	})
+}
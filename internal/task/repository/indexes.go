@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// taskIndexes はタスク一覧の絞り込みと各並び順のページングを支えるインデックスです。
+// 並び順ごとに user_id を先頭、_id を末尾に置き、キーセットページングでも索引を使えるようにしています
+var taskIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "due_date", Value: 1}, {Key: "_id", Value: 1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "title", Value: 1}, {Key: "_id", Value: 1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "priority", Value: 1}, {Key: "_id", Value: 1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}, {Key: "due_date", Value: 1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "labels", Value: 1}}},
+	{Keys: bson.D{{Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	// ランクはユーザーのタスク全体で一意にし、同時に移動しても同じランクにならないようにする
+	{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "rank", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"rank": bson.M{"$type": "string"}}),
+	},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "workflow_status", Value: 1}, {Key: "rank", Value: 1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "project_id", Value: 1}, {Key: "created_at", Value: 1}}},
+}
+
+var labelIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+}
+
+// projectIndexes はユーザーごとにプロジェクト名を一意にするインデックスです
+var projectIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+}
+
+// templateIndexes はユーザーごとにテンプレート名を一意にするインデックスです
+var templateIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+}
+
+var customFieldIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+}
+
+// dependencyIndexes は同じ依存関係の重複を防ぎ、両方向からの参照を支えるインデックスです
+var dependencyIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "blocker_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	{Keys: bson.D{{Key: "blocker_id", Value: 1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}}},
+}
+
+var workflowIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "owner_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+}
+
+var transitionIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}}},
+}
+
+// reminderIndexes は通知の重複登録を防ぎ、送信時刻を過ぎた未送信の通知の取得を支えるインデックスです
+var reminderIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+	{Keys: bson.D{{Key: "state", Value: 1}, {Key: "available_at", Value: 1}}},
+}
+
+// commentIndexes はタスクのコメントを古い順に取得するためのインデックスです
+var commentIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "_id", Value: 1}}},
+}
+
+// mentionIndexes は送信待ちのメンションの取得とコメントの削除時の取り消しを支えるインデックスです
+var mentionIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "state", Value: 1}, {Key: "available_at", Value: 1}}},
+	{Keys: bson.D{{Key: "comment_id", Value: 1}}},
+}
+
+// attachmentIndexes はタスクの添付ファイルの一覧とユーザーごとの使用量の集計を支えるインデックスです
+var attachmentIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "_id", Value: 1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "size", Value: 1}}},
+}
+
+// importJobIndexes はユーザーごとに取り込みジョブIDを一意にするインデックスです
+var importJobIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "job_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+}
+
+// calendarFeedIndexes はユーザーごとにフィードを1つにし、トークンのハッシュからフィードを引くためのインデックスです
+var calendarFeedIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+}
+
+// timeEntryIndexes はユーザーごとに実行中のタイマーを1つにし、期間やタスクごとの作業時間の取得を支えるインデックスです
+var timeEntryIndexes = []mongo.IndexModel{
+	{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"running": true}),
+	},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "start", Value: 1}}},
+	{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "start", Value: 1}}},
+}
+
+// EnsureIndexes はタスクサービスが使用するコレクションのインデックスを作成します
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	collections := map[string][]mongo.IndexModel{
+		"tasks":             taskIndexes,
+		"labels":            labelIndexes,
+		"projects":          projectIndexes,
+		"task_templates":    templateIndexes,
+		"custom_fields":     customFieldIndexes,
+		"task_dependencies": dependencyIndexes,
+		"workflows":         workflowIndexes,
+		"task_transitions":  transitionIndexes,
+		"reminders":         reminderIndexes,
+		"comments":          commentIndexes,
+		"mentions":          mentionIndexes,
+		"attachments":       attachmentIndexes,
+		"import_jobs":       importJobIndexes,
+		"calendar_feeds":    calendarFeedIndexes,
+		"time_entries":      timeEntryIndexes,
+	}
+	for name, indexes := range collections {
+		if _, err := db.Collection(name).Indexes().CreateMany(ctx, indexes); err != nil {
+			return err
+		}
+	}
+	return backfillTaskRanks(ctx, db.Collection("tasks"))
+}
+
+// backfillTaskRanks はランクを導入する前に作成したタスクに、作成順に並ぶランクを割り当てます
+func backfillTaskRanks(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"rank": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"rank": bson.M{"$concat": bson.A{model.AppendRankPrefix, bson.M{"$toString": "$_id"}, model.AppendRankSuffix}},
+		}}}},
+	)
+	return err
+}
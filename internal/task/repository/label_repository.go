@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrLabelNotFound is returned when a label is not found
+var ErrLabelNotFound = apperrors.NewNotFoundError("ラベルが見つかりません", nil).WithReason("LABEL_NOT_FOUND")
+
+// ErrLabelAlreadyExists is returned when a label with the same name already exists
+var ErrLabelAlreadyExists = apperrors.NewAlreadyExistsError("同じ名前のラベルが既に存在します", nil).WithReason("LABEL_ALREADY_EXISTS")
+
+type LabelRepository interface {
+	Create(ctx context.Context, label *model.Label) (*model.Label, error)
+	FindByID(ctx context.Context, userID string, id string) (*model.Label, error)
+	FindByUserID(ctx context.Context, userID string) ([]*model.Label, error)
+	// Update はラベルを更新し、名前が変わった場合はタスクに付与されたラベル名も置き換えます
+	Update(ctx context.Context, label *model.Label, oldName string) (*model.Label, error)
+	// Delete はラベルを削除し、タスクからも取り除きます
+	Delete(ctx context.Context, label *model.Label) error
+}
+
+type mongoLabelRepository struct {
+	collection *mongo.Collection
+	tasks      *mongo.Collection
+}
+
+func NewLabelRepository(db *mongo.Database) LabelRepository {
+	return &mongoLabelRepository{
+		collection: db.Collection("labels"),
+		tasks:      db.Collection("tasks"),
+	}
+}
+
+func (r *mongoLabelRepository) Create(ctx context.Context, label *model.Label) (*model.Label, error) {
+	label.ID = primitive.NewObjectID()
+	label.CreatedAt = time.Now()
+	label.UpdatedAt = label.CreatedAt
+
+	_, err := r.collection.InsertOne(ctx, label)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrLabelAlreadyExists
+		}
+		return nil, apperrors.NewInternalError("ラベルの作成に失敗しました", err)
+	}
+
+	return label, nil
+}
+
+func (r *mongoLabelRepository) FindByID(ctx context.Context, userID string, id string) (*model.Label, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	var label model.Label
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "user_id": userID}).Decode(&label)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrLabelNotFound
+		}
+		return nil, apperrors.NewInternalError("ラベルの取得に失敗しました", err)
+	}
+
+	return &label, nil
+}
+
+func (r *mongoLabelRepository) FindByUserID(ctx context.Context, userID string) ([]*model.Label, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("ラベルの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	labels := []*model.Label{}
+	if err := cursor.All(ctx, &labels); err != nil {
+		return nil, apperrors.NewInternalError("ラベルの取得に失敗しました", err)
+	}
+
+	return labels, nil
+}
+
+func (r *mongoLabelRepository) Update(ctx context.Context, label *model.Label, oldName string) (*model.Label, error) {
+	label.UpdatedAt = time.Now()
+
+	var updated model.Label
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": label.ID, "user_id": label.UserID},
+		bson.M{"$set": bson.M{
+			"name":       label.Name,
+			"color":      label.Color,
+			"updated_at": label.UpdatedAt,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrLabelNotFound
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrLabelAlreadyExists
+		}
+		return nil, apperrors.NewInternalError("ラベルの更新に失敗しました", err)
+	}
+
+	if oldName != label.Name {
+		if err := r.renameOnTasks(ctx, label.UserID, oldName, label.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return &updated, nil
+}
+
+// renameOnTasks はタスクのラベル名を置き換えます。新しい名前が既に付いているタスクでは古い名前を取り除くだけにし、重複を防ぎます
+func (r *mongoLabelRepository) renameOnTasks(ctx context.Context, userID, oldName, newName string) error {
+	_, err := r.tasks.UpdateMany(ctx,
+		bson.M{"user_id": userID, "labels": bson.M{"$all": bson.A{oldName, newName}}},
+		bson.M{"$pull": bson.M{"labels": oldName}},
+	)
+	if err != nil {
+		return apperrors.NewInternalError("タスクのラベルの更新に失敗しました", err)
+	}
+
+	_, err = r.tasks.UpdateMany(ctx,
+		bson.M{"user_id": userID, "labels": oldName},
+		bson.M{"$set": bson.M{"labels.$": newName}},
+	)
+	if err != nil {
+		return apperrors.NewInternalError("タスクのラベルの更新に失敗しました", err)
+	}
+	return nil
+}
+
+func (r *mongoLabelRepository) Delete(ctx context.Context, label *model.Label) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": label.ID, "user_id": label.UserID})
+	if err != nil {
+		return apperrors.NewInternalError("ラベルの削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrLabelNotFound
+	}
+
+	_, err = r.tasks.UpdateMany(ctx,
+		bson.M{"user_id": label.UserID, "labels": label.Name},
+		bson.M{"$pull": bson.M{"labels": label.Name}},
+	)
+	if err != nil {
+		return apperrors.NewInternalError("タスクのラベルの削除に失敗しました", err)
+	}
+
+	return nil
+}
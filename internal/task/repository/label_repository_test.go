@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func updateManyResponse(n int32) bson.D {
+	return bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: n}, {Key: "nModified", Value: n}}
+}
+
+func TestMongoLabelRepository_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoLabelRepository{collection: mt.Coll, tasks: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		label, err := repo.Create(context.Background(), &model.Label{UserID: "user1", Name: "backend", Color: "#336699"})
+		assert.NoError(t, err)
+		assert.False(t, label.ID.IsZero())
+		assert.False(t, label.CreatedAt.IsZero())
+	})
+
+	mt.Run("duplicate", func(mt *mtest.T) {
+		repo := &mongoLabelRepository{collection: mt.Coll, tasks: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		label, err := repo.Create(context.Background(), &model.Label{UserID: "user1", Name: "backend"})
+		assert.Nil(t, label)
+		assert.True(t, apperrors.IsAlreadyExists(err))
+	})
+}
+
+func TestMongoLabelRepository_Update(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	labelResponse := func(label *model.Label) bson.D {
+		return bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: label.ID},
+				{Key: "user_id", Value: label.UserID},
+				{Key: "name", Value: label.Name},
+				{Key: "color", Value: label.Color},
+			}},
+		}
+	}
+
+	mt.Run("rename_cascades_to_tasks", func(mt *mtest.T) {
+		repo := &mongoLabelRepository{collection: mt.Coll, tasks: mt.Coll}
+		label := &model.Label{ID: primitive.NewObjectID(), UserID: "user1", Name: "frontend", Color: "#336699"}
+		mt.AddMockResponses(labelResponse(label), updateManyResponse(1), updateManyResponse(2))
+
+		updated, err := repo.Update(context.Background(), label, "front")
+		assert.NoError(t, err)
+		assert.Equal(t, "frontend", updated.Name)
+
+		events := mt.GetAllStartedEvents()
+		assert.Len(t, events, 3)
+		assert.Equal(t, "findAndModify", events[0].CommandName)
+
+		pull := events[1].Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "front", pull.Lookup("u", "$pull", "labels").StringValue())
+
+		set := events[2].Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "front", set.Lookup("q", "labels").StringValue())
+		assert.Equal(t, "frontend", set.Lookup("u", "$set", "labels.$").StringValue())
+	})
+
+	mt.Run("color_only_does_not_touch_tasks", func(mt *mtest.T) {
+		repo := &mongoLabelRepository{collection: mt.Coll, tasks: mt.Coll}
+		label := &model.Label{ID: primitive.NewObjectID(), UserID: "user1", Name: "frontend", Color: "#000000"}
+		mt.AddMockResponses(labelResponse(label))
+
+		_, err := repo.Update(context.Background(), label, "frontend")
+		assert.NoError(t, err)
+		assert.Len(t, mt.GetAllStartedEvents(), 1)
+	})
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoLabelRepository{collection: mt.Coll, tasks: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		updated, err := repo.Update(context.Background(), &model.Label{ID: primitive.NewObjectID(), UserID: "user1", Name: "x"}, "y")
+		assert.Nil(t, updated)
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestMongoLabelRepository_Delete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success_pulls_from_tasks", func(mt *mtest.T) {
+		repo := &mongoLabelRepository{collection: mt.Coll, tasks: mt.Coll}
+		label := &model.Label{ID: primitive.NewObjectID(), UserID: "user1", Name: "backend"}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}}, updateManyResponse(3))
+
+		err := repo.Delete(context.Background(), label)
+		assert.NoError(t, err)
+
+		events := mt.GetAllStartedEvents()
+		assert.Len(t, events, 2)
+		pull := events[1].Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "backend", pull.Lookup("u", "$pull", "labels").StringValue())
+	})
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoLabelRepository{collection: mt.Coll, tasks: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		err := repo.Delete(context.Background(), &model.Label{ID: primitive.NewObjectID(), UserID: "user1", Name: "backend"})
+		assert.True(t, apperrors.IsNotFound(err))
+		assert.Len(t, mt.GetAllStartedEvents(), 1)
+	})
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNoMentionDue is returned when no mention is waiting to be delivered
+var ErrNoMentionDue = apperrors.NewNotFoundError("送信待ちのメンションはありません", nil)
+
+// MentionRepository はメンションの通知の送信予定を扱います。取得とリースの扱いは ReminderRepository と同じです
+type MentionRepository interface {
+	Create(ctx context.Context, jobs []*model.MentionJob) error
+	// DeleteByComment はコメントの未送信の通知を削除します
+	DeleteByComment(ctx context.Context, commentID primitive.ObjectID) error
+	Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*model.MentionJob, error)
+	Retry(ctx context.Context, id primitive.ObjectID, owner string, retryAt time.Time, lastError string) error
+	Finish(ctx context.Context, id primitive.ObjectID, owner string, state model.ReminderState, lastError string) error
+}
+
+type mongoMentionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMentionRepository(db *mongo.Database) MentionRepository {
+	return &mongoMentionRepository{
+		collection: db.Collection("mentions"),
+	}
+}
+
+func (r *mongoMentionRepository) Create(ctx context.Context, jobs []*model.MentionJob) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	docs := make([]interface{}, len(jobs))
+	for i, job := range jobs {
+		job.ID = primitive.NewObjectID()
+		job.State = model.ReminderStatePending
+		job.AvailableAt = now
+		job.CreatedAt = now
+		docs[i] = job
+	}
+	if _, err := r.collection.InsertMany(ctx, docs); err != nil {
+		return apperrors.NewInternalError("メンションの通知の登録に失敗しました", err)
+	}
+	return nil
+}
+
+func (r *mongoMentionRepository) DeleteByComment(ctx context.Context, commentID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"comment_id": commentID, "state": model.ReminderStatePending})
+	if err != nil {
+		return apperrors.NewInternalError("メンションの通知の削除に失敗しました", err)
+	}
+	return nil
+}
+
+func (r *mongoMentionRepository) Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*model.MentionJob, error) {
+	var job model.MentionJob
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"state": model.ReminderStatePending, "available_at": bson.M{"$lte": now}},
+		bson.M{
+			"$set": bson.M{"lease_owner": owner, "available_at": now.Add(lease)},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "available_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNoMentionDue
+		}
+		return nil, apperrors.NewInternalError("メンションの通知の取得に失敗しました", err)
+	}
+	return &job, nil
+}
+
+func (r *mongoMentionRepository) Retry(ctx context.Context, id primitive.ObjectID, owner string, retryAt time.Time, lastError string) error {
+	return r.updateLeased(ctx, id, owner, bson.M{
+		"$set":   bson.M{"available_at": retryAt, "last_error": lastError},
+		"$unset": bson.M{"lease_owner": ""},
+	})
+}
+
+func (r *mongoMentionRepository) Finish(ctx context.Context, id primitive.ObjectID, owner string, state model.ReminderState, lastError string) error {
+	set := bson.M{"state": state, "finished_at": time.Now()}
+	if lastError != "" {
+		set["last_error"] = lastError
+	}
+	return r.updateLeased(ctx, id, owner, bson.M{"$set": set, "$unset": bson.M{"lease_owner": ""}})
+}
+
+// updateLeased は owner がリースを保持している未送信の通知だけを更新します
+func (r *mongoMentionRepository) updateLeased(ctx context.Context, id primitive.ObjectID, owner string, update bson.M) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "lease_owner": owner, "state": model.ReminderStatePending},
+		update,
+	)
+	if err != nil {
+		return apperrors.NewInternalError("メンションの通知の更新に失敗しました", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrReminderLeaseLost
+	}
+	return nil
+}
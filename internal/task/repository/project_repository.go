@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrProjectNotFound is returned when a project is not found
+var ErrProjectNotFound = apperrors.NewNotFoundError("プロジェクトが見つかりません", nil).WithReason("PROJECT_NOT_FOUND")
+
+// ErrProjectAlreadyExists is returned when a project with the same name already exists
+var ErrProjectAlreadyExists = apperrors.NewAlreadyExistsError("同じ名前のプロジェクトが既に存在します", nil).WithReason("PROJECT_ALREADY_EXISTS")
+
+type ProjectRepository interface {
+	Create(ctx context.Context, project *model.Project) (*model.Project, error)
+	FindByID(ctx context.Context, userID string, id string) (*model.Project, error)
+	// FindByUserID はプロジェクトを名前順に返します。includeArchived が false の場合はアーカイブしたプロジェクトを除きます
+	FindByUserID(ctx context.Context, userID string, includeArchived bool) ([]*model.Project, error)
+	// FindArchivedIDs はアーカイブしたプロジェクトのIDを返します
+	FindArchivedIDs(ctx context.Context, userID string) ([]primitive.ObjectID, error)
+	// Update はプロジェクトの名前・説明・色・アーカイブの状態を更新します
+	Update(ctx context.Context, project *model.Project) (*model.Project, error)
+	// Delete はプロジェクトを削除し、残っているタスクをインボックスへ移します
+	Delete(ctx context.Context, project *model.Project) error
+}
+
+type mongoProjectRepository struct {
+	collection *mongo.Collection
+	tasks      *mongo.Collection
+}
+
+func NewProjectRepository(db *mongo.Database) ProjectRepository {
+	return &mongoProjectRepository{
+		collection: db.Collection("projects"),
+		tasks:      db.Collection("tasks"),
+	}
+}
+
+func (r *mongoProjectRepository) Create(ctx context.Context, project *model.Project) (*model.Project, error) {
+	project.ID = primitive.NewObjectID()
+	project.CreatedAt = time.Now()
+	project.UpdatedAt = project.CreatedAt
+
+	_, err := r.collection.InsertOne(ctx, project)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrProjectAlreadyExists
+		}
+		return nil, apperrors.NewInternalError("プロジェクトの作成に失敗しました", err)
+	}
+
+	return project, nil
+}
+
+func (r *mongoProjectRepository) FindByID(ctx context.Context, userID string, id string) (*model.Project, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	var project model.Project
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "user_id": userID}).Decode(&project)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrProjectNotFound
+		}
+		return nil, apperrors.NewInternalError("プロジェクトの取得に失敗しました", err)
+	}
+
+	return &project, nil
+}
+
+func (r *mongoProjectRepository) FindByUserID(ctx context.Context, userID string, includeArchived bool) ([]*model.Project, error) {
+	query := bson.M{"user_id": userID}
+	if !includeArchived {
+		query["archived"] = false
+	}
+
+	cursor, err := r.collection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("プロジェクトの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	projects := []*model.Project{}
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, apperrors.NewInternalError("プロジェクトの取得に失敗しました", err)
+	}
+
+	return projects, nil
+}
+
+func (r *mongoProjectRepository) FindArchivedIDs(ctx context.Context, userID string) ([]primitive.ObjectID, error) {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"user_id": userID, "archived": true},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("プロジェクトの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, apperrors.NewInternalError("プロジェクトの取得に失敗しました", err)
+	}
+
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
+
+func (r *mongoProjectRepository) Update(ctx context.Context, project *model.Project) (*model.Project, error) {
+	project.UpdatedAt = time.Now()
+
+	set := bson.M{
+		"name":        project.Name,
+		"description": project.Description,
+		"color":       project.Color,
+		"archived":    project.Archived,
+		"updated_at":  project.UpdatedAt,
+	}
+	unset := bson.M{}
+	if project.ArchivedAt.IsZero() {
+		unset["archived_at"] = ""
+	} else {
+		set["archived_at"] = project.ArchivedAt
+	}
+
+	var updated model.Project
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": project.ID, "user_id": project.UserID},
+		bson.M{"$set": set, "$unset": unset},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrProjectNotFound
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrProjectAlreadyExists
+		}
+		return nil, apperrors.NewInternalError("プロジェクトの更新に失敗しました", err)
+	}
+
+	return &updated, nil
+}
+
+func (r *mongoProjectRepository) Delete(ctx context.Context, project *model.Project) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": project.ID, "user_id": project.UserID})
+	if err != nil {
+		return apperrors.NewInternalError("プロジェクトの削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrProjectNotFound
+	}
+
+	_, err = r.tasks.UpdateMany(ctx,
+		bson.M{"user_id": project.UserID, "project_id": project.ID},
+		bson.M{"$unset": bson.M{"project_id": ""}},
+	)
+	if err != nil {
+		return apperrors.NewInternalError("タスクのプロジェクトの解除に失敗しました", err)
+	}
+
+	return nil
+}
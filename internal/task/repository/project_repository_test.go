@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoProjectRepository_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("duplicate", func(mt *mtest.T) {
+		repo := &mongoProjectRepository{collection: mt.Coll, tasks: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		project, err := repo.Create(context.Background(), &model.Project{UserID: "user1", Name: "Work"})
+		assert.Nil(t, project)
+		assert.True(t, apperrors.IsAlreadyExists(err))
+	})
+}
+
+func TestMongoProjectRepository_FindArchivedIDs(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoProjectRepository{collection: mt.Coll, tasks: mt.Coll}
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{{Key: "_id", Value: id}}))
+
+		ids, err := repo.FindArchivedIDs(context.Background(), "user1")
+		assert.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{id}, ids)
+
+		started := mt.GetStartedEvent()
+		assert.True(t, started.Command.Lookup("filter", "archived").Boolean())
+	})
+}
+
+func TestMongoProjectRepository_Update(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("unarchive_unsets_archived_at", func(mt *mtest.T) {
+		repo := &mongoProjectRepository{collection: mt.Coll, tasks: mt.Coll}
+		project := &model.Project{ID: primitive.NewObjectID(), UserID: "user1", Name: "Work"}
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: project.ID},
+				{Key: "user_id", Value: project.UserID},
+				{Key: "name", Value: project.Name},
+				{Key: "archived", Value: false},
+			}},
+		})
+
+		updated, err := repo.Update(context.Background(), project)
+		assert.NoError(t, err)
+		assert.False(t, updated.Archived)
+
+		update := mt.GetStartedEvent().Command.Lookup("update")
+		assert.False(t, update.Document().Lookup("$set", "archived").Boolean())
+		_, err = update.Document().LookupErr("$unset", "archived_at")
+		assert.NoError(t, err)
+	})
+}
+
+func TestMongoProjectRepository_Delete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("moves_tasks_to_inbox", func(mt *mtest.T) {
+		repo := &mongoProjectRepository{collection: mt.Coll, tasks: mt.Coll}
+		project := &model.Project{ID: primitive.NewObjectID(), UserID: "user1", Name: "Work"}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}}, updateManyResponse(2))
+
+		err := repo.Delete(context.Background(), project)
+		assert.NoError(t, err)
+
+		events := mt.GetAllStartedEvents()
+		assert.Len(t, events, 2)
+		update := events[1].Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, project.ID, update.Lookup("q", "project_id").ObjectID())
+		_, err = update.LookupErr("u", "$unset", "project_id")
+		assert.NoError(t, err)
+	})
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoProjectRepository{collection: mt.Coll, tasks: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		err := repo.Delete(context.Background(), &model.Project{ID: primitive.NewObjectID(), UserID: "user1"})
+		assert.True(t, apperrors.IsNotFound(err))
+		assert.Len(t, mt.GetAllStartedEvents(), 1)
+	})
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrRankConflict は同時に移動した他のタスクと同じランクになったことを表します。隣のタスクを取得し直してやり直してください
+var ErrRankConflict = apperrors.NewAlreadyExistsError("同じ並び順のタスクが既にあります", nil).
+	WithReason("RANK_CONFLICT").
+	WithRetryAfter(100 * time.Millisecond)
+
+func (r *mongoTaskRepository) SetRank(ctx context.Context, id string, rank string) (*model.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	var updatedTask model.Task
+	err = r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"rank": rank, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updatedTask)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTaskNotFound
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrRankConflict
+		}
+		return nil, apperrors.NewInternalError("タスクの並び順の変更に失敗しました", err)
+	}
+	return &updatedTask, nil
+}
+
+func (r *mongoTaskRepository) FindAdjacentRank(ctx context.Context, userID string, rank string, after bool) (string, error) {
+	op, direction := "$lt", -1
+	if after {
+		op, direction = "$gt", 1
+	}
+	return r.findRank(ctx,
+		bson.M{"user_id": userID, "rank": bson.M{op: rank}},
+		options.FindOne().SetSort(bson.D{{Key: "rank", Value: direction}}))
+}
+
+func (r *mongoTaskRepository) FindLastRank(ctx context.Context, userID string, workflowStatus string) (string, error) {
+	return r.findRank(ctx,
+		bson.M{"user_id": userID, "workflow_status": workflowStatus, "rank": bson.M{"$type": "string"}},
+		options.FindOne().SetSort(bson.D{{Key: "rank", Value: -1}}))
+}
+
+func (r *mongoTaskRepository) findRank(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (string, error) {
+	var doc struct {
+		Rank string `bson:"rank"`
+	}
+	err := r.collection.FindOne(ctx, filter, opts.SetProjection(bson.M{"rank": 1})).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", nil
+		}
+		return "", apperrors.NewInternalError("タスクの並び順の取得に失敗しました", err)
+	}
+	return doc.Rank, nil
+}
+
+func (r *mongoTaskRepository) RebalanceRanks(ctx context.Context, userID string) ([]primitive.ObjectID, error) {
+	// ランクのないタスクは先頭に並ぶため、その並び順のまま割り当てる
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, options.Find().
+		SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"rank": 1}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("タスクの並び順の取得に失敗しました", err)
+	}
+	var docs []struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Rank string             `bson:"rank"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, apperrors.NewInternalError("タスクの並び順の取得に失敗しました", err)
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	taken := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		taken[doc.Rank] = true
+	}
+	ranks := model.RebalanceRanks(len(docs), taken)
+	var models []mongo.WriteModel
+	var ids []primitive.ObjectID
+	for i, doc := range docs {
+		if doc.Rank == ranks[i] {
+			continue
+		}
+		// 読み取った後に移動したタスクは書き換えない
+		filter := bson.M{"_id": doc.ID, "rank": doc.Rank}
+		if doc.Rank == "" {
+			filter["rank"] = bson.M{"$exists": false}
+		}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(bson.M{"$set": bson.M{"rank": ranks[i], "updated_at": time.Now()}}))
+		ids = append(ids, doc.ID)
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	_, err = r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err == nil {
+		return ids, nil
+	}
+	// 同時に移動したタスクとランクが重なった項目は元のランクのままにする
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		failed := make(map[int]bool, len(bulkErr.WriteErrors))
+		for _, writeErr := range bulkErr.WriteErrors {
+			if !mongo.IsDuplicateKeyError(writeErr) {
+				return nil, apperrors.NewInternalError("タスクの並び順の変更に失敗しました", err)
+			}
+			failed[writeErr.Index] = true
+		}
+		var changed []primitive.ObjectID
+		for i, id := range ids {
+			if !failed[i] {
+				changed = append(changed, id)
+			}
+		}
+		return changed, nil
+	}
+	return nil, apperrors.NewInternalError("タスクの並び順の変更に失敗しました", err)
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrNoReminderDue is returned when no reminder is ready to be delivered
+	ErrNoReminderDue = apperrors.NewNotFoundError("送信予定の通知はありません", nil)
+	// ErrReminderLeaseLost is returned when another worker took over the reminder after the lease expired
+	ErrReminderLeaseLost = apperrors.NewFailedPreconditionError("通知のリースが失効しています", nil)
+)
+
+type ReminderRepository interface {
+	// Sync はタスクの未送信の通知を jobs に置き換えます。送信済みの通知と同じキーの通知は作り直しません
+	Sync(ctx context.Context, taskID primitive.ObjectID, jobs []*model.ReminderJob) error
+	// DeleteByTask はタスクの未送信の通知を削除します
+	DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error
+	// Claim は送信時刻を過ぎた通知を1件取得し、owner のリースを lease の間だけ設定します
+	Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*model.ReminderJob, error)
+	// MarkDelivered は通知先へ送信済みであることを記録します
+	MarkDelivered(ctx context.Context, id primitive.ObjectID, owner string, channel model.NotificationChannel) error
+	// Retry はリースを解放し、retryAt 以降に再送できるようにします
+	Retry(ctx context.Context, id primitive.ObjectID, owner string, retryAt time.Time, lastError string) error
+	// Finish はリースを解放し、通知を終了状態にします
+	Finish(ctx context.Context, id primitive.ObjectID, owner string, state model.ReminderState, lastError string) error
+}
+
+type mongoReminderRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReminderRepository(db *mongo.Database) ReminderRepository {
+	return &mongoReminderRepository{
+		collection: db.Collection("reminders"),
+	}
+}
+
+func (r *mongoReminderRepository) Sync(ctx context.Context, taskID primitive.ObjectID, jobs []*model.ReminderJob) error {
+	keys := make([]string, len(jobs))
+	now := time.Now()
+	for i, job := range jobs {
+		keys[i] = job.Key
+		job.CreatedAt = now
+		_, err := r.collection.UpdateOne(
+			ctx,
+			bson.M{"task_id": taskID, "key": job.Key},
+			bson.M{"$setOnInsert": job},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return apperrors.NewInternalError("通知の登録に失敗しました", err)
+		}
+	}
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{
+		"task_id": taskID,
+		"state":   model.ReminderStatePending,
+		"key":     bson.M{"$nin": keys},
+	})
+	if err != nil {
+		return apperrors.NewInternalError("通知の削除に失敗しました", err)
+	}
+	return nil
+}
+
+func (r *mongoReminderRepository) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"task_id": taskID, "state": model.ReminderStatePending})
+	if err != nil {
+		return apperrors.NewInternalError("通知の削除に失敗しました", err)
+	}
+	return nil
+}
+
+func (r *mongoReminderRepository) Claim(ctx context.Context, owner string, now time.Time, lease time.Duration) (*model.ReminderJob, error) {
+	var job model.ReminderJob
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"state": model.ReminderStatePending, "available_at": bson.M{"$lte": now}},
+		bson.M{
+			"$set": bson.M{"lease_owner": owner, "available_at": now.Add(lease)},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "available_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNoReminderDue
+		}
+		return nil, apperrors.NewInternalError("通知の取得に失敗しました", err)
+	}
+	return &job, nil
+}
+
+func (r *mongoReminderRepository) MarkDelivered(ctx context.Context, id primitive.ObjectID, owner string, channel model.NotificationChannel) error {
+	return r.updateLeased(ctx, id, owner, bson.M{"$addToSet": bson.M{"delivered": channel}})
+}
+
+func (r *mongoReminderRepository) Retry(ctx context.Context, id primitive.ObjectID, owner string, retryAt time.Time, lastError string) error {
+	return r.updateLeased(ctx, id, owner, bson.M{
+		"$set":   bson.M{"available_at": retryAt, "last_error": lastError},
+		"$unset": bson.M{"lease_owner": ""},
+	})
+}
+
+func (r *mongoReminderRepository) Finish(ctx context.Context, id primitive.ObjectID, owner string, state model.ReminderState, lastError string) error {
+	set := bson.M{"state": state, "finished_at": time.Now()}
+	if lastError != "" {
+		set["last_error"] = lastError
+	}
+	return r.updateLeased(ctx, id, owner, bson.M{"$set": set, "$unset": bson.M{"lease_owner": ""}})
+}
+
+// updateLeased は owner がリースを保持している未送信の通知だけを更新します
+func (r *mongoReminderRepository) updateLeased(ctx context.Context, id primitive.ObjectID, owner string, update bson.M) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "lease_owner": owner, "state": model.ReminderStatePending},
+		update,
+	)
+	if err != nil {
+		return apperrors.NewInternalError("通知の更新に失敗しました", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrReminderLeaseLost
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoReminderRepository_Claim(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	mt.Run("nothing_due", func(mt *mtest.T) {
+		repo := &mongoReminderRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		job, err := repo.Claim(context.Background(), "worker1", now, time.Minute)
+		assert.Nil(t, job)
+		assert.ErrorIs(t, err, ErrNoReminderDue)
+	})
+
+	mt.Run("claimed", func(mt *mtest.T) {
+		repo := &mongoReminderRepository{collection: mt.Coll}
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: id},
+				{Key: "state", Value: model.ReminderStatePending},
+				{Key: "lease_owner", Value: "worker1"},
+				{Key: "attempts", Value: 1},
+			}},
+		})
+
+		job, err := repo.Claim(context.Background(), "worker1", now, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, id, job.ID)
+		assert.Equal(t, 1, job.Attempts)
+
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(t, string(model.ReminderStatePending), cmd.Lookup("query", "state").StringValue())
+		assert.Equal(t, "worker1", cmd.Lookup("update", "$set", "lease_owner").StringValue())
+		assert.Equal(t, now.Add(time.Minute), cmd.Lookup("update", "$set", "available_at").Time().UTC())
+	})
+}
+
+func TestMongoReminderRepository_Finish(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("lease_lost", func(mt *mtest.T) {
+		repo := &mongoReminderRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		err := repo.Finish(context.Background(), primitive.NewObjectID(), "worker1", model.ReminderStateSent, "")
+		assert.ErrorIs(t, err, ErrReminderLeaseLost)
+
+		cmd := mt.GetStartedEvent().Command
+		update := cmd.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "worker1", update.Lookup("q", "lease_owner").StringValue())
+	})
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoReminderRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		err := repo.Finish(context.Background(), primitive.NewObjectID(), "worker1", model.ReminderStateSent, "")
+		assert.NoError(t, err)
+	})
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"regexp"
+	"sort"
 	"time"
 
 	"github.com/my-backend-project/internal/pkg/apperrors"
@@ -14,14 +17,47 @@ import (
 )
 
 // ErrTaskNotFound is returned when a task is not found
-var ErrTaskNotFound = apperrors.NewNotFoundError("タスクが見つかりません", nil)
+var ErrTaskNotFound = apperrors.NewNotFoundError("タスクが見つかりません", nil).WithReason("TASK_NOT_FOUND")
+
+// exportBatchSize は ForEachByUserID が1回のやり取りで受け取るドキュメント数です
+const exportBatchSize = 200
 
 type TaskRepository interface {
 	Create(ctx context.Context, task *model.Task) (*model.Task, error)
 	FindByID(ctx context.Context, id string) (*model.Task, error)
-	FindByUserID(ctx context.Context, userID string, status *model.TaskStatus, limit int32, offset string) ([]*model.Task, int32, error)
+	FindByUserID(ctx context.Context, userID string, filter *model.TaskFilter, limit int32, after *model.TaskCursor) ([]*model.Task, int32, error)
+	// ForEachByUserID は FindByUserID と同じ条件と並び順でタスクを1件ずつ fn に渡します。
+	// カーソルで読み進めるため、件数が多くてもすべてをメモリに載せません。fn がエラーを返すと中断し、そのエラーをそのまま返します
+	ForEachByUserID(ctx context.Context, userID string, filter *model.TaskFilter, fn func(*model.Task) error) error
 	Update(ctx context.Context, id string, task *model.Task) (*model.Task, error)
 	Delete(ctx context.Context, id string) error
+	// FindChildren は直下のサブタスクを作成日時順に返します
+	FindChildren(ctx context.Context, parentID string) ([]*model.Task, error)
+	// CountSubtasks は指定したタスクごとに直下のサブタスクの件数と完了件数を返します
+	CountSubtasks(ctx context.Context, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]model.SubtaskCount, error)
+	// SetParent はタスクの親を変更します。parentID がゼロ値の場合はルートのタスクにします
+	SetParent(ctx context.Context, id string, parentID primitive.ObjectID) (*model.Task, error)
+	// ReparentChildren は fromID の直下のサブタスクを toID の直下へ移します
+	ReparentChildren(ctx context.Context, fromID primitive.ObjectID, toID primitive.ObjectID) error
+	// FindByIDs は指定したIDのタスクを返します。存在しないIDは結果に含めません
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*model.Task, error)
+	// BulkCreate は tasks を1回の BulkWrite で作成します。戻り値の errs[i] は tasks[i] の書き込みエラーです
+	BulkCreate(ctx context.Context, tasks []*model.Task) (errs []error, err error)
+	// BulkUpdate は tasks の各タスクを Update と同じ項目で1回の BulkWrite により更新します。
+	// 存在しないタスクはエラーにしないため、呼び出し側で事前に確認してください
+	BulkUpdate(ctx context.Context, tasks []*model.Task) (errs []error, err error)
+	// BulkDelete は ids のタスクを1回の BulkWrite で削除します
+	BulkDelete(ctx context.Context, ids []primitive.ObjectID) (errs []error, err error)
+	// SetRank はタスクのボード上の並び順を変更します。ユーザーの他のタスクと同じランクの場合は ErrRankConflict を返します
+	SetRank(ctx context.Context, id string, rank string) (*model.Task, error)
+	// FindAdjacentRank はユーザーのタスクのうち rank の直後（after が false の場合は直前）のランクを返します。ない場合は空文字です
+	FindAdjacentRank(ctx context.Context, userID string, rank string, after bool) (string, error)
+	// FindLastRank はワークフローのステータスが workflowStatus のタスクのうち最後のランクを返します。ない場合は空文字です
+	FindLastRank(ctx context.Context, userID string, workflowStatus string) (string, error)
+	// RebalanceRanks はユーザーのタスクの並び順を保ったまま短いランクを割り当て直し、ランクを書き換えたタスクの ID を返します
+	RebalanceRanks(ctx context.Context, userID string) ([]primitive.ObjectID, error)
+	// Stats はユーザーのタスクの統計を1回の集計パイプラインで求めます。期限切れは now を基準に、終日のタスクの期限日は query のタイムゾーンで判定します
+	Stats(ctx context.Context, userID string, query model.TaskStatsQuery, now time.Time) (*model.TaskStats, error)
 }
 
 type mongoTaskRepository struct {
@@ -36,6 +72,7 @@ func NewTaskRepository(db *mongo.Database) TaskRepository {
 
 func (r *mongoTaskRepository) Create(ctx context.Context, task *model.Task) (*model.Task, error) {
 	task.ID = primitive.NewObjectID()
+	task.Rank = model.AppendRank(task.ID)
 	task.CreatedAt = time.Now()
 	task.UpdatedAt = time.Now()
 
@@ -65,22 +102,28 @@ func (r *mongoTaskRepository) FindByID(ctx context.Context, id string) (*model.T
 	return &task, nil
 }
 
-func (r *mongoTaskRepository) FindByUserID(ctx context.Context, userID string, status *model.TaskStatus, limit int32, offset string) ([]*model.Task, int32, error) {
-	filter := bson.M{"user_id": userID}
-	if status != nil {
-		filter["status"] = *status
+func (r *mongoTaskRepository) FindByUserID(ctx context.Context, userID string, filter *model.TaskFilter, limit int32, after *model.TaskCursor) ([]*model.Task, int32, error) {
+	if !model.IsValidSortField(filter.SortField()) {
+		return nil, 0, apperrors.NewInvalidInputError("無効な並び替え条件です", nil)
 	}
 
-	opts := options.Find().SetLimit(int64(limit))
-	if offset != "" {
-		objectID, err := primitive.ObjectIDFromHex(offset)
-		if err != nil {
-			return nil, 0, apperrors.NewInvalidInputError("無効なオフセットです", err)
-		}
-		filter["_id"] = bson.M{"$gt": objectID}
+	query := buildTaskQuery(userID, filter, time.Now())
+	sortField := string(filter.SortField())
+	direction := 1
+	if filter.IsDescending() {
+		direction = -1
+	}
+
+	findQuery := query
+	if after != nil {
+		findQuery = bson.M{"$and": bson.A{query, keysetAfter(sortField, after.LastValue, after.LastID, direction)}}
 	}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, findQuery, opts)
 	if err != nil {
 		return nil, 0, apperrors.NewInternalError("タスクの取得に失敗しました", err)
 	}
@@ -91,7 +134,7 @@ func (r *mongoTaskRepository) FindByUserID(ctx context.Context, userID string, s
 		return nil, 0, apperrors.NewInternalError("タスクの取得に失敗しました", err)
 	}
 
-	total, err := r.collection.CountDocuments(ctx, filter)
+	total, err := r.collection.CountDocuments(ctx, query)
 	if err != nil {
 		return nil, 0, apperrors.NewInternalError("タスクの総数の取得に失敗しました", err)
 	}
@@ -99,6 +142,174 @@ func (r *mongoTaskRepository) FindByUserID(ctx context.Context, userID string, s
 	return tasks, int32(total), nil
 }
 
+func (r *mongoTaskRepository) ForEachByUserID(ctx context.Context, userID string, filter *model.TaskFilter, fn func(*model.Task) error) error {
+	if !model.IsValidSortField(filter.SortField()) {
+		return apperrors.NewInvalidInputError("無効な並び替え条件です", nil)
+	}
+
+	direction := 1
+	if filter.IsDescending() {
+		direction = -1
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: string(filter.SortField()), Value: direction}, {Key: "_id", Value: direction}}).
+		SetBatchSize(exportBatchSize)
+
+	cursor, err := r.collection.Find(ctx, buildTaskQuery(userID, filter, time.Now()), opts)
+	if err != nil {
+		return apperrors.NewInternalError("タスクの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var task model.Task
+		if err := cursor.Decode(&task); err != nil {
+			return apperrors.NewInternalError("タスクのデコードに失敗しました", err)
+		}
+		if err := fn(&task); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return apperrors.NewInternalError("タスクの取得に失敗しました", err)
+	}
+	return nil
+}
+
+// buildTaskQuery は絞り込み条件をMongoDBのクエリに変換します
+func buildTaskQuery(userID string, filter *model.TaskFilter, now time.Time) bson.M {
+	query := bson.M{"user_id": userID}
+	if filter == nil {
+		return query
+	}
+
+	var clauses bson.A
+	if len(filter.Statuses) > 0 {
+		clauses = append(clauses, bson.M{"status": bson.M{"$in": filter.Statuses}})
+	}
+	if len(filter.WorkflowStatuses) > 0 {
+		clauses = append(clauses, bson.M{"workflow_status": bson.M{"$in": filter.WorkflowStatuses}})
+	}
+	switch {
+	case !filter.ProjectID.IsZero():
+		clauses = append(clauses, bson.M{"project_id": filter.ProjectID})
+	case filter.Inbox:
+		clauses = append(clauses, bson.M{"project_id": bson.M{"$exists": false}})
+	case len(filter.HiddenProjectIDs) > 0:
+		clauses = append(clauses, bson.M{"project_id": bson.M{"$nin": filter.HiddenProjectIDs}})
+	}
+
+	if len(filter.Priorities) > 0 {
+		clauses = append(clauses, bson.M{"priority": bson.M{"$in": filter.Priorities}})
+	}
+	if len(filter.Labels) > 0 {
+		clauses = append(clauses, bson.M{"labels": bson.M{"$all": filter.Labels}})
+	}
+	for _, id := range sortedKeys(filter.CustomFields) {
+		field, value := customFieldQueryValue(filter.CustomFields[id])
+		clauses = append(clauses, bson.M{"custom_fields." + id + "." + field: value})
+	}
+
+	if filter.DuePreset != "" {
+		clauses = append(clauses, duePresetClause(filter.DuePreset, now.In(filter.Location())))
+	}
+	clauses = appendRangeClause(clauses, "due_date", filter.DueDateRange)
+	clauses = appendRangeClause(clauses, "created_at", filter.CreatedAtRange)
+	clauses = appendRangeClause(clauses, "updated_at", filter.UpdatedAtRange)
+
+	if filter.Query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Query), Options: "i"}
+		clauses = append(clauses, bson.M{"$or": bson.A{
+			bson.M{"title": pattern},
+			bson.M{"description": pattern},
+		}})
+	}
+
+	if len(clauses) > 0 {
+		query["$and"] = clauses
+	}
+	return query
+}
+
+// customFieldQueryValue はカスタムフィールドの値の種類に対応するサブフィールド名と値を返します
+func customFieldQueryValue(v model.CustomFieldValue) (string, interface{}) {
+	switch v.Type {
+	case model.CustomFieldNumber:
+		if v.Number != nil {
+			return "number", *v.Number
+		}
+	case model.CustomFieldDate:
+		if v.Date != nil {
+			return "date", *v.Date
+		}
+	case model.CustomFieldSingleSelect:
+		return "option", v.Option
+	}
+	return "text", v.Text
+}
+
+func sortedKeys(m map[string]model.CustomFieldValue) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// duePresetClause は定型の期限条件を表す条件を返します。now のタイムゾーンをユーザーのタイムゾーンとし、
+// 時刻のあるタスクは期限の日時、終日のタスクは期限日の日付で比べます (model.Task.MatchesDuePreset と同じ条件です)
+func duePresetClause(preset model.DueDatePreset, now time.Time) bson.M {
+	if preset == model.DueDateOverdue {
+		return overdueClause(now)
+	}
+	r := model.DuePresetRange(preset, now)
+	start, end := r.Days()
+	return bson.M{"$or": bson.A{
+		bson.M{"all_day": bson.M{"$ne": true}, "due_date": bson.M{"$gte": r.Start, "$lt": r.End}},
+		bson.M{"all_day": true, "due_day": bson.M{"$gte": start, "$lt": end}},
+	}}
+}
+
+// overdueClause は now の時点で期限を過ぎた未完了のタスクを表す条件を返します (model.Task.IsOverdue と同じ条件です)。
+// 期限未設定のタスクは期限切れとして扱いません
+func overdueClause(now time.Time) bson.M {
+	return bson.M{
+		"status": bson.M{"$ne": model.TaskStatusComplete},
+		"$or": bson.A{
+			bson.M{"all_day": bson.M{"$ne": true}, "due_date": bson.M{"$gt": time.Time{}, "$lt": now}},
+			bson.M{"all_day": true, "time_zone": bson.M{"$exists": false}, "due_day": bson.M{"$lt": now.Format(model.DueDayLayout)}},
+			bson.M{"all_day": true, "time_zone": bson.M{"$exists": true}, "due_end": bson.M{"$lte": now}},
+		},
+	}
+}
+
+func appendRangeClause(clauses bson.A, field string, r model.TimeRange) bson.A {
+	if r.IsZero() {
+		return clauses
+	}
+	cond := bson.M{}
+	if !r.Start.IsZero() {
+		cond["$gte"] = r.Start
+	}
+	if !r.End.IsZero() {
+		cond["$lt"] = r.End
+	}
+	return append(clauses, bson.M{field: cond})
+}
+
+// keysetAfter は (sortField, _id) の組で直前のページの末尾より後ろにあるタスクを表す条件を返します
+func keysetAfter(sortField string, value interface{}, id primitive.ObjectID, direction int) bson.M {
+	op := "$gt"
+	if direction < 0 {
+		op = "$lt"
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{sortField: bson.M{op: value}},
+		bson.M{sortField: value, "_id": bson.M{op: id}},
+	}}
+}
+
 func (r *mongoTaskRepository) Update(ctx context.Context, id string, task *model.Task) (*model.Task, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -107,21 +318,11 @@ func (r *mongoTaskRepository) Update(ctx context.Context, id string, task *model
 
 	task.UpdatedAt = time.Now()
 
-	update := bson.M{
-		"$set": bson.M{
-			"title":       task.Title,
-			"description": task.Description,
-			"status":      task.Status,
-			"due_date":    task.DueDate,
-			"updated_at":  task.UpdatedAt,
-		},
-	}
-
 	var updatedTask model.Task
 	err = r.collection.FindOneAndUpdate(
 		ctx,
 		bson.M{"_id": objectID},
-		update,
+		updateDocument(task),
 		options.FindOneAndUpdate().SetReturnDocument(options.After),
 	).Decode(&updatedTask)
 
@@ -135,6 +336,61 @@ func (r *mongoTaskRepository) Update(ctx context.Context, id string, task *model
 	return &updatedTask, nil
 }
 
+// updateDocument は Update で書き換える項目の更新ドキュメントです
+func updateDocument(task *model.Task) bson.M {
+	set := bson.M{
+		"title":           task.Title,
+		"description":     task.Description,
+		"status":          task.Status,
+		"workflow_status": task.WorkflowStatus,
+		"priority":        task.Priority,
+		"labels":          task.Labels,
+		"custom_fields":   task.CustomFields,
+		"checklist":       task.Checklist,
+		"reminders":       task.Reminders,
+		"due_date":        task.DueDate,
+		"updated_at":      task.UpdatedAt,
+	}
+	unset := bson.M{}
+	if task.Recurrence == nil {
+		unset["recurrence"] = ""
+	} else {
+		set["recurrence"] = task.Recurrence
+	}
+	if task.HasProject() {
+		set["project_id"] = task.ProjectID
+	} else {
+		unset["project_id"] = ""
+	}
+	if task.CompletedAt.IsZero() {
+		unset["completed_at"] = ""
+	} else {
+		set["completed_at"] = task.CompletedAt
+	}
+	// 終日・タイムゾーンの項目は期限切れの判定に使うため、消した場合も残さない
+	if task.AllDay {
+		set["all_day"] = true
+	} else {
+		unset["all_day"] = ""
+	}
+	if task.DueDay == "" {
+		unset["due_day"] = ""
+	} else {
+		set["due_day"] = task.DueDay
+	}
+	if task.TimeZone == "" {
+		unset["time_zone"] = ""
+	} else {
+		set["time_zone"] = task.TimeZone
+	}
+	if task.DueEnd.IsZero() {
+		unset["due_end"] = ""
+	} else {
+		set["due_end"] = task.DueEnd
+	}
+	return bson.M{"$set": set, "$unset": unset}
+}
+
 func (r *mongoTaskRepository) Delete(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -152,3 +408,279 @@ func (r *mongoTaskRepository) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+func (r *mongoTaskRepository) FindChildren(ctx context.Context, parentID string) ([]*model.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"parent_id": objectID}, opts)
+	if err != nil {
+		return nil, apperrors.NewInternalError("サブタスクの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []*model.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, apperrors.NewInternalError("サブタスクの取得に失敗しました", err)
+	}
+
+	return tasks, nil
+}
+
+func (r *mongoTaskRepository) CountSubtasks(ctx context.Context, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]model.SubtaskCount, error) {
+	counts := make(map[primitive.ObjectID]model.SubtaskCount, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"parent_id": bson.M{"$in": parentIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$parent_id",
+			"total": bson.M{"$sum": 1},
+			"completed": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$eq": bson.A{"$status", model.TaskStatusComplete}}, 1, 0},
+			}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, apperrors.NewInternalError("サブタスクの集計に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var row struct {
+			ParentID           primitive.ObjectID `bson:"_id"`
+			model.SubtaskCount `bson:",inline"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, apperrors.NewInternalError("サブタスクの集計に失敗しました", err)
+		}
+		counts[row.ParentID] = row.SubtaskCount
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, apperrors.NewInternalError("サブタスクの集計に失敗しました", err)
+	}
+
+	return counts, nil
+}
+
+func (r *mongoTaskRepository) SetParent(ctx context.Context, id string, parentID primitive.ObjectID) (*model.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	set := bson.M{"updated_at": time.Now()}
+	update := bson.M{"$set": set}
+	if parentID.IsZero() {
+		update["$unset"] = bson.M{"parent_id": ""}
+	} else {
+		set["parent_id"] = parentID
+	}
+
+	var updatedTask model.Task
+	err = r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": objectID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updatedTask)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTaskNotFound
+		}
+		return nil, apperrors.NewInternalError("タスクの移動に失敗しました", err)
+	}
+
+	return &updatedTask, nil
+}
+
+func (r *mongoTaskRepository) ReparentChildren(ctx context.Context, fromID primitive.ObjectID, toID primitive.ObjectID) error {
+	update := bson.M{"$set": bson.M{"parent_id": toID}}
+	if toID.IsZero() {
+		update = bson.M{"$unset": bson.M{"parent_id": ""}}
+	}
+
+	if _, err := r.collection.UpdateMany(ctx, bson.M{"parent_id": fromID}, update); err != nil {
+		return apperrors.NewInternalError("サブタスクの移動に失敗しました", err)
+	}
+	return nil
+}
+
+func (r *mongoTaskRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*model.Task, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, apperrors.NewInternalError("タスクの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []*model.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, apperrors.NewInternalError("タスクのデコードに失敗しました", err)
+	}
+	return tasks, nil
+}
+
+func (r *mongoTaskRepository) BulkCreate(ctx context.Context, tasks []*model.Task) ([]error, error) {
+	now := time.Now()
+	models := make([]mongo.WriteModel, len(tasks))
+	for i, task := range tasks {
+		task.ID = primitive.NewObjectID()
+		task.Rank = model.AppendRank(task.ID)
+		task.CreatedAt = now
+		task.UpdatedAt = now
+		models[i] = mongo.NewInsertOneModel().SetDocument(task)
+	}
+	return r.bulkWrite(ctx, models, "タスクの作成に失敗しました")
+}
+
+func (r *mongoTaskRepository) BulkUpdate(ctx context.Context, tasks []*model.Task) ([]error, error) {
+	now := time.Now()
+	models := make([]mongo.WriteModel, len(tasks))
+	for i, task := range tasks {
+		task.UpdatedAt = now
+		models[i] = mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": task.ID}).SetUpdate(updateDocument(task))
+	}
+	return r.bulkWrite(ctx, models, "タスクの更新に失敗しました")
+}
+
+func (r *mongoTaskRepository) BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]error, error) {
+	models := make([]mongo.WriteModel, len(ids))
+	for i, id := range ids {
+		models[i] = mongo.NewDeleteOneModel().SetFilter(bson.M{"_id": id})
+	}
+	return r.bulkWrite(ctx, models, "タスクの削除に失敗しました")
+}
+
+// bulkWrite は順序を問わずに models を書き込み、失敗した項目のエラーを models と同じ位置に返します
+func (r *mongoTaskRepository) bulkWrite(ctx context.Context, models []mongo.WriteModel, message string) ([]error, error) {
+	errs := make([]error, len(models))
+	if len(models) == 0 {
+		return errs, nil
+	}
+
+	_, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err == nil {
+		return errs, nil
+	}
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return nil, apperrors.NewInternalError(message, err)
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Index >= 0 && writeErr.Index < len(errs) {
+			errs[writeErr.Index] = apperrors.NewInternalError(message, writeErr)
+		}
+	}
+	return errs, nil
+}
+
+func (r *mongoTaskRepository) Stats(ctx context.Context, userID string, query model.TaskStatsQuery, now time.Time) (*model.TaskStats, error) {
+	createdInRange := bson.M{"created_at": bson.M{"$gte": query.Start, "$lt": query.End}}
+	completedInRange := bson.M{
+		"status":       model.TaskStatusComplete,
+		"completed_at": bson.M{"$gte": query.Start, "$lt": query.End},
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$facet", Value: bson.M{
+			"by_status": bson.A{
+				bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+			},
+			"overdue": bson.A{
+				bson.M{"$match": overdueClause(now.In(query.Location))},
+				bson.M{"$count": "count"},
+			},
+			"created": bson.A{
+				bson.M{"$match": createdInRange},
+				bson.M{"$group": bson.M{
+					"_id":   nil,
+					"count": bson.M{"$sum": 1},
+					"completed": bson.M{"$sum": bson.M{
+						"$cond": bson.A{bson.M{"$eq": bson.A{"$status", model.TaskStatusComplete}}, 1, 0},
+					}},
+				}},
+			},
+			"completed": bson.A{
+				bson.M{"$match": completedInRange},
+				bson.M{"$group": bson.M{
+					"_id":       nil,
+					"count":     bson.M{"$sum": 1},
+					"lead_time": bson.M{"$avg": bson.M{"$subtract": bson.A{"$completed_at", "$created_at"}}},
+				}},
+			},
+			"daily": bson.A{
+				bson.M{"$match": completedInRange},
+				bson.M{"$group": bson.M{
+					"_id": bson.M{"$dateToString": bson.M{
+						"format":   "%Y-%m-%d",
+						"date":     "$completed_at",
+						"timezone": query.Location.String(),
+					}},
+					"count": bson.M{"$sum": 1},
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, apperrors.NewInternalError("タスクの統計の集計に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	type group struct {
+		Key       string  `bson:"_id"`
+		Count     int64   `bson:"count"`
+		Completed int64   `bson:"completed"`
+		LeadTime  float64 `bson:"lead_time"`
+	}
+	var result struct {
+		ByStatus  []group `bson:"by_status"`
+		Overdue   []group `bson:"overdue"`
+		Created   []group `bson:"created"`
+		Completed []group `bson:"completed"`
+		Daily     []group `bson:"daily"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, apperrors.NewInternalError("タスクの統計の集計に失敗しました", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, apperrors.NewInternalError("タスクの統計の集計に失敗しました", err)
+	}
+
+	stats := &model.TaskStats{CountsByStatus: make(map[model.TaskStatus]int64, len(result.ByStatus))}
+	for _, g := range result.ByStatus {
+		stats.CountsByStatus[model.TaskStatus(g.Key)] = g.Count
+		stats.Total += g.Count
+	}
+	if len(result.Overdue) > 0 {
+		stats.Overdue = result.Overdue[0].Count
+	}
+	if len(result.Created) > 0 {
+		stats.Created = result.Created[0].Count
+		stats.CreatedCompleted = result.Created[0].Completed
+	}
+	if len(result.Completed) > 0 {
+		stats.Completed = result.Completed[0].Count
+		// 日時の差はミリ秒で求まる
+		stats.AverageLeadTime = time.Duration(result.Completed[0].LeadTime * float64(time.Millisecond))
+	}
+	daily := make(map[string]int64, len(result.Daily))
+	for _, g := range result.Daily {
+		daily[g.Key] = g.Count
+	}
+	stats.DailyCompletions = query.FillDailyCompletions(daily)
+	return stats, nil
+}
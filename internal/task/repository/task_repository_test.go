@@ -9,6 +9,7 @@ import (
 	"github.com/my-backend-project/internal/task/model"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
@@ -166,6 +167,72 @@ func TestMongoTaskRepository_Update(t *testing.T) {
 		assert.Equal(t, task.ID, result.ID)
 	})
 
+	updatedTask := func(taskID primitive.ObjectID) bson.D {
+		return bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{{Key: "_id", Value: taskID}, {Key: "user_id", Value: "user1"}}},
+			{Key: "lastErrorObject", Value: bson.D{{Key: "n", Value: 1}, {Key: "updatedExisting", Value: true}}},
+		}
+	}
+
+	mt.Run("sets_all_day_fields", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		taskID := primitive.NewObjectID()
+		start := time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC)
+		mt.AddMockResponses(updatedTask(taskID))
+
+		_, err := repo.Update(context.Background(), taskID.Hex(), &model.Task{
+			ID:       taskID,
+			UserID:   "user1",
+			Title:    "締め切り",
+			Status:   model.TaskStatusPending,
+			AllDay:   true,
+			DueDay:   "2024-03-10",
+			TimeZone: "Asia/Tokyo",
+			DueDate:  start,
+			DueEnd:   start.AddDate(0, 0, 1),
+		})
+		require.NoError(t, err)
+
+		update := mt.GetStartedEvent().Command.Lookup("update").Document()
+		set := update.Lookup("$set").Document()
+		assert.True(t, set.Lookup("all_day").Boolean())
+		assert.Equal(t, "2024-03-10", set.Lookup("due_day").StringValue())
+		assert.Equal(t, "Asia/Tokyo", set.Lookup("time_zone").StringValue())
+		assert.True(t, set.Lookup("due_end").Time().Equal(start.AddDate(0, 0, 1)))
+		unset := update.Lookup("$unset").Document()
+		for _, key := range []string{"all_day", "due_day", "time_zone", "due_end"} {
+			_, err := unset.LookupErr(key)
+			assert.Error(t, err, key)
+		}
+	})
+
+	mt.Run("unsets_cleared_all_day_fields", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		taskID := primitive.NewObjectID()
+		mt.AddMockResponses(updatedTask(taskID))
+
+		// 終日のタスクを時刻のあるタスクに戻した場合
+		_, err := repo.Update(context.Background(), taskID.Hex(), &model.Task{
+			ID:      taskID,
+			UserID:  "user1",
+			Title:   "締め切り",
+			Status:  model.TaskStatusPending,
+			DueDate: time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+
+		update := mt.GetStartedEvent().Command.Lookup("update").Document()
+		unset := update.Lookup("$unset").Document()
+		set := update.Lookup("$set").Document()
+		for _, key := range []string{"all_day", "due_day", "time_zone", "due_end"} {
+			_, err := unset.LookupErr(key)
+			assert.NoError(t, err, key)
+			_, err = set.LookupErr(key)
+			assert.Error(t, err, key)
+		}
+	})
+
 	mt.Run("not_found", func(mt *mtest.T) {
 		repo := &mongoTaskRepository{collection: mt.Coll}
 		taskID := primitive.NewObjectID()
@@ -276,8 +343,8 @@ func TestMongoTaskRepository_FindByUserID(t *testing.T) {
 		repo := &mongoTaskRepository{collection: mt.Coll}
 		userID := "user1"
 		status := model.TaskStatusPending
+		filter := &model.TaskFilter{Statuses: []model.TaskStatus{status}}
 		limit := int32(10)
-		offset := ""
 
 		task1ID := primitive.NewObjectID()
 		task2ID := primitive.NewObjectID()
@@ -313,7 +380,7 @@ func TestMongoTaskRepository_FindByUserID(t *testing.T) {
 
 		mt.AddMockResponses(first, second, killCursors, count)
 
-		tasks, total, err := repo.FindByUserID(context.Background(), userID, &status, limit, offset)
+		tasks, total, err := repo.FindByUserID(context.Background(), userID, filter, limit, nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, tasks)
 		assert.Equal(t, int32(2), total)
@@ -326,18 +393,493 @@ func TestMongoTaskRepository_FindByUserID(t *testing.T) {
 		repo := &mongoTaskRepository{collection: mt.Coll}
 		userID := "user1"
 		status := model.TaskStatusPending
+		filter := &model.TaskFilter{Statuses: []model.TaskStatus{status}}
 		limit := int32(10)
-		offset := ""
 
 		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
 			Code:    1,
 			Message: "internal error",
 		}))
 
-		tasks, total, err := repo.FindByUserID(context.Background(), userID, &status, limit, offset)
+		tasks, total, err := repo.FindByUserID(context.Background(), userID, filter, limit, nil)
 		assert.Error(t, err)
 		assert.Nil(t, tasks)
 		assert.Equal(t, int32(0), total)
 		assert.IsType(t, &apperrors.AppError{}, err)
 	})
 }
+
+func TestMongoTaskRepository_ForEachByUserID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	taskDoc := func(id primitive.ObjectID, title string) bson.D {
+		return bson.D{
+			{Key: "_id", Value: id},
+			{Key: "user_id", Value: "user1"},
+			{Key: "title", Value: title},
+			{Key: "status", Value: model.TaskStatusPending},
+		}
+	}
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		task1ID := primitive.NewObjectID()
+		task2ID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, taskDoc(task1ID, "Task 1")),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch, taskDoc(task2ID, "Task 2")),
+		)
+
+		var ids []primitive.ObjectID
+		err := repo.ForEachByUserID(context.Background(), "user1", nil, func(task *model.Task) error {
+			ids = append(ids, task.ID)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{task1ID, task2ID}, ids)
+
+		started := mt.GetStartedEvent()
+		assert.Equal(t, "find", started.CommandName)
+		assert.Equal(t, int32(exportBatchSize), started.Command.Lookup("batchSize").Int32())
+	})
+
+	mt.Run("callback_error", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch,
+			taskDoc(primitive.NewObjectID(), "Task 1"),
+			taskDoc(primitive.NewObjectID(), "Task 2"),
+		))
+
+		stop := apperrors.NewInternalError("送信に失敗しました", nil)
+		calls := 0
+		err := repo.ForEachByUserID(context.Background(), "user1", nil, func(*model.Task) error {
+			calls++
+			return stop
+		})
+		assert.Equal(t, stop, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	mt.Run("invalid_sort_field", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+
+		err := repo.ForEachByUserID(context.Background(), "user1", &model.TaskFilter{OrderBy: "owner"}, func(*model.Task) error { return nil })
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+}
+
+func TestMongoTaskRepository_FindByUserID_Pagination(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("keyset_after_cursor", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		dueDate := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+		filter := &model.TaskFilter{OrderBy: model.TaskSortByDueDate, Descending: true}
+		after := &model.TaskCursor{LastValue: dueDate, LastID: primitive.NewObjectID()}
+
+		page := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "user_id", Value: "user1"},
+			{Key: "due_date", Value: dueDate.AddDate(0, 0, -1)},
+		})
+		count := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "n", Value: int32(5)},
+		})
+		mt.AddMockResponses(page, count)
+
+		tasks, total, err := repo.FindByUserID(context.Background(), "user1", filter, 10, after)
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 1)
+		assert.Equal(t, int32(5), total)
+
+		find := mt.GetStartedEvent()
+		assert.Equal(t, "find", find.CommandName)
+		sort := find.Command.Lookup("sort").Document()
+		assert.Equal(t, int32(-1), sort.Lookup("due_date").Int32())
+		assert.Equal(t, int32(-1), sort.Lookup("_id").Int32())
+		assert.Contains(t, find.Command.Lookup("filter").String(), "$lt")
+
+		// 総数はカーソル条件を含まない
+		countEvent := mt.GetStartedEvent()
+		assert.Equal(t, "aggregate", countEvent.CommandName)
+		assert.NotContains(t, countEvent.Command.Lookup("pipeline").String(), "$lt")
+	})
+
+	mt.Run("invalid_sort_field", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		filter := &model.TaskFilter{OrderBy: model.TaskSortField("password")}
+
+		tasks, _, err := repo.FindByUserID(context.Background(), "user1", filter, 10, nil)
+		assert.Error(t, err)
+		assert.Nil(t, tasks)
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+}
+
+func TestBuildTaskQuery(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC) // 水曜日
+
+	t.Run("nil_filter", func(t *testing.T) {
+		query := buildTaskQuery("user1", nil, now)
+		assert.Equal(t, bson.M{"user_id": "user1"}, query)
+	})
+
+	t.Run("statuses_and_query", func(t *testing.T) {
+		filter := &model.TaskFilter{
+			Statuses: []model.TaskStatus{model.TaskStatusPending, model.TaskStatusActive},
+			Query:    "a.b",
+		}
+		query := buildTaskQuery("user1", filter, now)
+		clauses := query["$and"].(bson.A)
+		assert.Len(t, clauses, 2)
+		assert.Equal(t, bson.M{"status": bson.M{"$in": filter.Statuses}}, clauses[0])
+		pattern := primitive.Regex{Pattern: `a\.b`, Options: "i"}
+		assert.Equal(t, bson.M{"$or": bson.A{bson.M{"title": pattern}, bson.M{"description": pattern}}}, clauses[1])
+	})
+
+	t.Run("overdue", func(t *testing.T) {
+		filter := &model.TaskFilter{DuePreset: model.DueDateOverdue}
+		query := buildTaskQuery("user1", filter, now)
+		assert.Equal(t, bson.A{
+			bson.M{
+				"status": bson.M{"$ne": model.TaskStatusComplete},
+				"$or": bson.A{
+					bson.M{"all_day": bson.M{"$ne": true}, "due_date": bson.M{"$gt": time.Time{}, "$lt": now}},
+					bson.M{"all_day": true, "time_zone": bson.M{"$exists": false}, "due_day": bson.M{"$lt": "2024-05-15"}},
+					bson.M{"all_day": true, "time_zone": bson.M{"$exists": true}, "due_end": bson.M{"$lte": now}},
+				},
+			},
+		}, query["$and"])
+	})
+
+	t.Run("overdue_in_user_zone", func(t *testing.T) {
+		// UTC では 5月15日でも東京ではもう16日なので、15日が期限日の終日のタスクは期限切れ
+		late := time.Date(2024, 5, 15, 20, 0, 0, 0, time.UTC)
+		filter := &model.TaskFilter{DuePreset: model.DueDateOverdue, TimeZone: "Asia/Tokyo"}
+		query := buildTaskQuery("user1", filter, late)
+		clause := query["$and"].(bson.A)[0].(bson.M)
+		floating := clause["$or"].(bson.A)[1].(bson.M)
+		assert.Equal(t, bson.M{"$lt": "2024-05-16"}, floating["due_day"])
+	})
+
+	t.Run("due_this_week", func(t *testing.T) {
+		filter := &model.TaskFilter{DuePreset: model.DueDateDueThisWeek}
+		query := buildTaskQuery("user1", filter, now)
+		assert.Equal(t, bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"all_day": bson.M{"$ne": true}, "due_date": bson.M{
+					"$gte": time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
+					"$lt":  time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+				}},
+				bson.M{"all_day": true, "due_day": bson.M{"$gte": "2024-05-15", "$lt": "2024-05-20"}},
+			}},
+		}, query["$and"])
+	})
+
+	t.Run("due_today_across_dst", func(t *testing.T) {
+		// 2024年3月10日はニューヨークで夏時間が始まり、1日が23時間になる
+		dstStart := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+		filter := &model.TaskFilter{DuePreset: model.DueDateDueToday, TimeZone: "America/New_York"}
+		query := buildTaskQuery("user1", filter, dstStart)
+		or := query["$and"].(bson.A)[0].(bson.M)["$or"].(bson.A)
+
+		timed := or[0].(bson.M)["due_date"].(bson.M)
+		start, end := timed["$gte"].(time.Time), timed["$lt"].(time.Time)
+		assert.True(t, start.Equal(time.Date(2024, 3, 10, 5, 0, 0, 0, time.UTC)))
+		assert.True(t, end.Equal(time.Date(2024, 3, 11, 4, 0, 0, 0, time.UTC)))
+		assert.Equal(t, 23*time.Hour, end.Sub(start))
+		assert.Equal(t, bson.M{"$gte": "2024-03-10", "$lt": "2024-03-11"}, or[1].(bson.M)["due_day"])
+	})
+
+	t.Run("open_ended_ranges", func(t *testing.T) {
+		start := now.AddDate(0, -1, 0)
+		filter := &model.TaskFilter{
+			CreatedAtRange: model.TimeRange{Start: start},
+			UpdatedAtRange: model.TimeRange{End: now},
+		}
+		query := buildTaskQuery("user1", filter, now)
+		assert.Equal(t, bson.A{
+			bson.M{"created_at": bson.M{"$gte": start}},
+			bson.M{"updated_at": bson.M{"$lt": now}},
+		}, query["$and"])
+	})
+
+	t.Run("projects", func(t *testing.T) {
+		projectID := primitive.NewObjectID()
+		hidden := []primitive.ObjectID{primitive.NewObjectID()}
+
+		query := buildTaskQuery("user1", &model.TaskFilter{ProjectID: projectID, HiddenProjectIDs: hidden}, now)
+		assert.Equal(t, bson.A{bson.M{"project_id": projectID}}, query["$and"])
+
+		query = buildTaskQuery("user1", &model.TaskFilter{Inbox: true}, now)
+		assert.Equal(t, bson.A{bson.M{"project_id": bson.M{"$exists": false}}}, query["$and"])
+
+		query = buildTaskQuery("user1", &model.TaskFilter{HiddenProjectIDs: hidden}, now)
+		assert.Equal(t, bson.A{bson.M{"project_id": bson.M{"$nin": hidden}}}, query["$and"])
+	})
+}
+
+func TestMongoTaskRepository_CountSubtasks(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		parentA := primitive.NewObjectID()
+		parentB := primitive.NewObjectID()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: parentA}, {Key: "total", Value: int32(3)}, {Key: "completed", Value: int32(1)}},
+		))
+
+		counts, err := repo.CountSubtasks(context.Background(), []primitive.ObjectID{parentA, parentB})
+		assert.NoError(t, err)
+		assert.Equal(t, model.SubtaskCount{Total: 3, Completed: 1}, counts[parentA])
+		assert.Equal(t, model.SubtaskCount{}, counts[parentB])
+	})
+
+	mt.Run("no_ids", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+
+		counts, err := repo.CountSubtasks(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, counts)
+		assert.Empty(t, mt.GetAllStartedEvents())
+	})
+}
+
+func TestMongoTaskRepository_SetParent(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	taskResponse := func(id primitive.ObjectID) bson.D {
+		return bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{{Key: "_id", Value: id}, {Key: "user_id", Value: "user1"}}},
+		}
+	}
+
+	mt.Run("set", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		taskID := primitive.NewObjectID()
+		parentID := primitive.NewObjectID()
+		mt.AddMockResponses(taskResponse(taskID))
+
+		_, err := repo.SetParent(context.Background(), taskID.Hex(), parentID)
+		assert.NoError(t, err)
+
+		update := mt.GetStartedEvent().Command.Lookup("update").Document()
+		assert.Equal(t, parentID, update.Lookup("$set", "parent_id").ObjectID())
+		_, err = update.LookupErr("$unset")
+		assert.Error(t, err)
+	})
+
+	mt.Run("to_root", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		taskID := primitive.NewObjectID()
+		mt.AddMockResponses(taskResponse(taskID))
+
+		_, err := repo.SetParent(context.Background(), taskID.Hex(), primitive.NilObjectID)
+		assert.NoError(t, err)
+
+		update := mt.GetStartedEvent().Command.Lookup("update").Document()
+		_, err = update.LookupErr("$unset", "parent_id")
+		assert.NoError(t, err)
+	})
+}
+
+func TestMongoTaskRepository_BulkCreate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("write_errors", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		tasks := []*model.Task{{UserID: "user1", Title: "a"}, {UserID: "user1", Title: "b"}}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 1, Code: 11000, Message: "duplicate key error"}))
+
+		errs, err := repo.BulkCreate(context.Background(), tasks)
+		assert.NoError(t, err)
+		assert.NoError(t, errs[0])
+		assert.True(t, apperrors.IsInternal(errs[1]))
+		assert.False(t, tasks[0].ID.IsZero())
+
+		cmd := mt.GetStartedEvent().Command
+		assert.False(t, cmd.Lookup("ordered").Boolean())
+	})
+
+	mt.Run("command_error", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad"}))
+
+		_, err := repo.BulkCreate(context.Background(), []*model.Task{{UserID: "user1", Title: "a"}})
+		assert.True(t, apperrors.IsInternal(err))
+	})
+}
+
+func TestMongoTaskRepository_BulkDelete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("empty", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		errs, err := repo.BulkDelete(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, errs)
+	})
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		errs, err := repo.BulkDelete(context.Background(), ids)
+		assert.NoError(t, err)
+		assert.Equal(t, []error{nil, nil}, errs)
+
+		deletes := mt.GetStartedEvent().Command.Lookup("deletes").Array()
+		values, _ := deletes.Values()
+		assert.Len(t, values, 2)
+	})
+}
+
+func TestMongoTaskRepository_FindByIDs(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.tasks", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "user_id", Value: "user1"},
+		}))
+
+		tasks, err := repo.FindByIDs(context.Background(), []primitive.ObjectID{id, primitive.NewObjectID()})
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 1)
+
+		in := mt.GetStartedEvent().Command.Lookup("filter", "_id", "$in").Array()
+		values, _ := in.Values()
+		assert.Len(t, values, 2)
+	})
+}
+
+func TestMongoTaskRepository_Stats(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("タイムゾーンのデータがありません")
+	}
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	query, err := model.NewTaskStatsQuery("2024-03-08", "2024-03-10", tokyo, now)
+	assert.NoError(t, err)
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "by_status", Value: bson.A{
+				bson.D{{Key: "_id", Value: model.TaskStatusPending}, {Key: "count", Value: int32(3)}},
+				bson.D{{Key: "_id", Value: model.TaskStatusComplete}, {Key: "count", Value: int32(2)}},
+			}},
+			{Key: "overdue", Value: bson.A{bson.D{{Key: "count", Value: int32(1)}}}},
+			{Key: "created", Value: bson.A{bson.D{{Key: "_id", Value: nil}, {Key: "count", Value: int32(4)}, {Key: "completed", Value: int32(1)}}}},
+			{Key: "completed", Value: bson.A{bson.D{{Key: "_id", Value: nil}, {Key: "count", Value: int32(2)}, {Key: "lead_time", Value: float64(90 * time.Minute / time.Millisecond)}}}},
+			{Key: "daily", Value: bson.A{bson.D{{Key: "_id", Value: "2024-03-09"}, {Key: "count", Value: int32(2)}}}},
+		}))
+
+		stats, err := repo.Stats(context.Background(), "user1", query, now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), stats.Total)
+		assert.Equal(t, int64(3), stats.CountsByStatus[model.TaskStatusPending])
+		assert.Equal(t, int64(1), stats.Overdue)
+		assert.Equal(t, int64(4), stats.Created)
+		assert.Equal(t, 0.25, stats.CompletionRate())
+		assert.Equal(t, int64(2), stats.Completed)
+		assert.Equal(t, 90*time.Minute, stats.AverageLeadTime)
+		assert.Equal(t, []model.DailyCount{
+			{Date: "2024-03-08", Count: 0},
+			{Date: "2024-03-09", Count: 2},
+			{Date: "2024-03-10", Count: 0},
+		}, stats.DailyCompletions)
+
+		started := mt.GetStartedEvent()
+		assert.Equal(t, "aggregate", started.CommandName)
+		daily := started.Command.Lookup("pipeline", "1", "$facet", "daily", "1", "$group", "_id", "$dateToString")
+		assert.Equal(t, "Asia/Tokyo", daily.Document().Lookup("timezone").StringValue())
+	})
+
+	mt.Run("no_tasks", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "by_status", Value: bson.A{}},
+			{Key: "overdue", Value: bson.A{}},
+			{Key: "created", Value: bson.A{}},
+			{Key: "completed", Value: bson.A{}},
+			{Key: "daily", Value: bson.A{}},
+		}))
+
+		stats, err := repo.Stats(context.Background(), "user1", query, now)
+		assert.NoError(t, err)
+		assert.Zero(t, stats.Total)
+		assert.Zero(t, stats.CompletionRate())
+		assert.Len(t, stats.DailyCompletions, 3)
+	})
+
+	mt.Run("database_error", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    1,
+			Message: "database error",
+		}))
+
+		_, err := repo.Stats(context.Background(), "user1", query, now)
+		assert.True(t, apperrors.IsInternal(err))
+	})
+}
+
+func TestMongoTaskRepository_SetRank(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("conflict", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		task, err := repo.SetRank(context.Background(), primitive.NewObjectID().Hex(), "V")
+		assert.Nil(t, task)
+		assert.ErrorIs(t, err, ErrRankConflict)
+	})
+
+	mt.Run("invalid_id", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+
+		_, err := repo.SetRank(context.Background(), "invalid", "V")
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+}
+
+func TestMongoTaskRepository_FindAdjacentRank(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("found", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "rank", Value: "W"},
+		}))
+
+		rank, err := repo.FindAdjacentRank(context.Background(), "user1", "V", true)
+		assert.NoError(t, err)
+		assert.Equal(t, "W", rank)
+
+		started := mt.GetStartedEvent()
+		assert.Equal(t, "V", started.Command.Lookup("filter", "rank", "$gt").StringValue())
+	})
+
+	mt.Run("last", func(mt *mtest.T) {
+		repo := &mongoTaskRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch))
+
+		rank, err := repo.FindAdjacentRank(context.Background(), "user1", "V", false)
+		assert.NoError(t, err)
+		assert.Empty(t, rank)
+	})
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTemplateNotFound is returned when a task template is not found
+var ErrTemplateNotFound = apperrors.NewNotFoundError("テンプレートが見つかりません", nil).WithReason("TEMPLATE_NOT_FOUND")
+
+// ErrTemplateAlreadyExists is returned when a task template with the same name already exists
+var ErrTemplateAlreadyExists = apperrors.NewAlreadyExistsError("同じ名前のテンプレートが既に存在します", nil).WithReason("TEMPLATE_ALREADY_EXISTS")
+
+type TemplateRepository interface {
+	Create(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error)
+	FindByID(ctx context.Context, userID string, id string) (*model.TaskTemplate, error)
+	// FindByUserID はテンプレートを名前順に返します
+	FindByUserID(ctx context.Context, userID string) ([]*model.TaskTemplate, error)
+	// Update は作成日時以外のすべての項目を置き換えます
+	Update(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error)
+	Delete(ctx context.Context, userID string, id string) error
+}
+
+type mongoTemplateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTemplateRepository(db *mongo.Database) TemplateRepository {
+	return &mongoTemplateRepository{
+		collection: db.Collection("task_templates"),
+	}
+}
+
+func (r *mongoTemplateRepository) Create(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error) {
+	template.ID = primitive.NewObjectID()
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = template.CreatedAt
+
+	_, err := r.collection.InsertOne(ctx, template)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrTemplateAlreadyExists
+		}
+		return nil, apperrors.NewInternalError("テンプレートの作成に失敗しました", err)
+	}
+
+	return template, nil
+}
+
+func (r *mongoTemplateRepository) FindByID(ctx context.Context, userID string, id string) (*model.TaskTemplate, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	var template model.TaskTemplate
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "user_id": userID}).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, apperrors.NewInternalError("テンプレートの取得に失敗しました", err)
+	}
+
+	return &template, nil
+}
+
+func (r *mongoTemplateRepository) FindByUserID(ctx context.Context, userID string) ([]*model.TaskTemplate, error) {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("テンプレートの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	templates := []*model.TaskTemplate{}
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, apperrors.NewInternalError("テンプレートの取得に失敗しました", err)
+	}
+
+	return templates, nil
+}
+
+func (r *mongoTemplateRepository) Update(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error) {
+	template.UpdatedAt = time.Now()
+
+	var updated model.TaskTemplate
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": template.ID, "user_id": template.UserID},
+		bson.M{"$set": bson.M{
+			"name":            template.Name,
+			"title":           template.Title,
+			"description":     template.Description,
+			"status":          template.Status,
+			"priority":        template.Priority,
+			"labels":          template.Labels,
+			"checklist":       template.Checklist,
+			"due_offset_days": template.DueOffsetDays,
+			"subtasks":        template.Subtasks,
+			"updated_at":      template.UpdatedAt,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTemplateNotFound
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrTemplateAlreadyExists
+		}
+		return nil, apperrors.NewInternalError("テンプレートの更新に失敗しました", err)
+	}
+
+	return &updated, nil
+}
+
+func (r *mongoTemplateRepository) Delete(ctx context.Context, userID string, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID, "user_id": userID})
+	if err != nil {
+		return apperrors.NewInternalError("テンプレートの削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrTemplateNotFound
+	}
+
+	return nil
+}
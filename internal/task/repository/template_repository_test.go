@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoTemplateRepository_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("duplicate", func(mt *mtest.T) {
+		repo := &mongoTemplateRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		template, err := repo.Create(context.Background(), &model.TaskTemplate{UserID: "user1", Name: "onboarding", Title: "task"})
+		assert.Nil(t, template)
+		assert.True(t, apperrors.IsAlreadyExists(err))
+	})
+}
+
+func TestMongoTemplateRepository_Update(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("replaces_subtasks", func(mt *mtest.T) {
+		repo := &mongoTemplateRepository{collection: mt.Coll}
+		template := &model.TaskTemplate{
+			ID:       primitive.NewObjectID(),
+			UserID:   "user1",
+			Name:     "onboarding",
+			Title:    "task",
+			Subtasks: []model.TemplateSubtask{{Title: "subtask", DueOffsetDays: 3}},
+		}
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: template.ID},
+				{Key: "user_id", Value: template.UserID},
+				{Key: "name", Value: template.Name},
+				{Key: "subtasks", Value: bson.A{bson.D{{Key: "title", Value: "subtask"}, {Key: "due_offset_days", Value: 3}}}},
+			}},
+		})
+
+		updated, err := repo.Update(context.Background(), template)
+		require.NoError(t, err)
+		require.Len(t, updated.Subtasks, 1)
+		assert.Equal(t, 3, updated.Subtasks[0].DueOffsetDays)
+
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, "user1", command.Lookup("query", "user_id").StringValue())
+		_, err = command.Lookup("update").Document().LookupErr("$set", "subtasks")
+		assert.NoError(t, err)
+	})
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoTemplateRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		_, err := repo.Update(context.Background(), &model.TaskTemplate{ID: primitive.NewObjectID(), UserID: "user1"})
+		assert.Equal(t, ErrTemplateNotFound, err)
+	})
+}
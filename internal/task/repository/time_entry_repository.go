@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTimeEntryNotFound is returned when a time entry is not found
+var ErrTimeEntryNotFound = apperrors.NewNotFoundError("作業時間が見つかりません", nil).WithReason("TIME_ENTRY_NOT_FOUND")
+
+// ErrTimerNotRunning is returned when the user has no running timer
+var ErrTimerNotRunning = apperrors.NewNotFoundError("実行中のタイマーはありません", nil).WithReason("TIMER_NOT_RUNNING")
+
+// ErrTimerAlreadyRunning is returned when the user already has a running timer
+var ErrTimerAlreadyRunning = apperrors.NewFailedPreconditionError("既に実行中のタイマーがあります", nil).WithReason("TIMER_ALREADY_RUNNING")
+
+type TimeEntryRepository interface {
+	// Create は作業時間を作成します。Running の場合、ユーザーに実行中のタイマーが既にあれば ErrTimerAlreadyRunning を返します
+	Create(ctx context.Context, entry *model.TimeEntry) (*model.TimeEntry, error)
+	FindByID(ctx context.Context, userID string, id string) (*model.TimeEntry, error)
+	// FindRunning はユーザーの実行中のタイマーを返します。ない場合は ErrTimerNotRunning を返します
+	FindRunning(ctx context.Context, userID string) (*model.TimeEntry, error)
+	// StopRunning はユーザーの実行中のタイマーを end で止めます。ない場合は ErrTimerNotRunning を返します
+	StopRunning(ctx context.Context, userID string, end time.Time) (*model.TimeEntry, error)
+	// StopByTask はタスクの実行中のタイマーを end で止めます。ない場合は ErrTimerNotRunning を返します
+	StopByTask(ctx context.Context, taskID primitive.ObjectID, end time.Time) (*model.TimeEntry, error)
+	// Update は終了した作業時間の開始日時・終了日時・メモを更新します。実行中のタイマーは更新しません
+	Update(ctx context.Context, entry *model.TimeEntry) (*model.TimeEntry, error)
+	Delete(ctx context.Context, userID string, id string) error
+	// FindByTask はタスクの作業時間を開始日時順に返します
+	FindByTask(ctx context.Context, taskID primitive.ObjectID) ([]*model.TimeEntry, error)
+	// Report はユーザーの期間内の作業時間を1回の集計パイプラインで集計します
+	Report(ctx context.Context, userID string, query model.TaskStatsQuery) (*model.TimeReport, error)
+	// ForEachInRange は開始日時が期間内の終了した作業時間を開始日時順に1件ずつ fn に渡します。
+	// fn がエラーを返すと中断し、そのエラーをそのまま返します
+	ForEachInRange(ctx context.Context, userID string, query model.TaskStatsQuery, fn func(*model.TimeEntry) error) error
+}
+
+type mongoTimeEntryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTimeEntryRepository(db *mongo.Database) TimeEntryRepository {
+	return &mongoTimeEntryRepository{
+		collection: db.Collection("time_entries"),
+	}
+}
+
+func (r *mongoTimeEntryRepository) Create(ctx context.Context, entry *model.TimeEntry) (*model.TimeEntry, error) {
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = entry.CreatedAt
+
+	_, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrTimerAlreadyRunning
+		}
+		return nil, apperrors.NewInternalError("作業時間の作成に失敗しました", err)
+	}
+
+	return entry, nil
+}
+
+func (r *mongoTimeEntryRepository) FindByID(ctx context.Context, userID string, id string) (*model.TimeEntry, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	var entry model.TimeEntry
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "user_id": userID}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTimeEntryNotFound
+		}
+		return nil, apperrors.NewInternalError("作業時間の取得に失敗しました", err)
+	}
+
+	return &entry, nil
+}
+
+func (r *mongoTimeEntryRepository) FindRunning(ctx context.Context, userID string) (*model.TimeEntry, error) {
+	var entry model.TimeEntry
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "running": true}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTimerNotRunning
+		}
+		return nil, apperrors.NewInternalError("タイマーの取得に失敗しました", err)
+	}
+
+	return &entry, nil
+}
+
+func (r *mongoTimeEntryRepository) StopRunning(ctx context.Context, userID string, end time.Time) (*model.TimeEntry, error) {
+	return r.stop(ctx, bson.M{"user_id": userID, "running": true}, end)
+}
+
+func (r *mongoTimeEntryRepository) StopByTask(ctx context.Context, taskID primitive.ObjectID, end time.Time) (*model.TimeEntry, error) {
+	return r.stop(ctx, bson.M{"task_id": taskID, "running": true}, end)
+}
+
+func (r *mongoTimeEntryRepository) stop(ctx context.Context, filter bson.M, end time.Time) (*model.TimeEntry, error) {
+	var stopped model.TimeEntry
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		bson.M{
+			"$set":   bson.M{"end": end, "updated_at": end},
+			"$unset": bson.M{"running": ""},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&stopped)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTimerNotRunning
+		}
+		return nil, apperrors.NewInternalError("タイマーの停止に失敗しました", err)
+	}
+
+	return &stopped, nil
+}
+
+func (r *mongoTimeEntryRepository) Update(ctx context.Context, entry *model.TimeEntry) (*model.TimeEntry, error) {
+	entry.UpdatedAt = time.Now()
+
+	var updated model.TimeEntry
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": entry.ID, "user_id": entry.UserID, "running": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{
+			"start":      entry.Start,
+			"end":        entry.End,
+			"note":       entry.Note,
+			"updated_at": entry.UpdatedAt,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTimeEntryNotFound
+		}
+		return nil, apperrors.NewInternalError("作業時間の更新に失敗しました", err)
+	}
+
+	return &updated, nil
+}
+
+func (r *mongoTimeEntryRepository) Delete(ctx context.Context, userID string, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID, "user_id": userID})
+	if err != nil {
+		return apperrors.NewInternalError("作業時間の削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrTimeEntryNotFound
+	}
+
+	return nil
+}
+
+func (r *mongoTimeEntryRepository) FindByTask(ctx context.Context, taskID primitive.ObjectID) ([]*model.TimeEntry, error) {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"task_id": taskID},
+		options.Find().SetSort(bson.D{{Key: "start", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("作業時間の取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*model.TimeEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, apperrors.NewInternalError("作業時間の取得に失敗しました", err)
+	}
+
+	return entries, nil
+}
+
+// finishedInRange は開始日時が期間内の終了した作業時間の条件です
+func finishedInRange(userID string, query model.TaskStatsQuery) bson.M {
+	return bson.M{
+		"user_id": userID,
+		"running": bson.M{"$ne": true},
+		"start":   bson.M{"$gte": query.Start, "$lt": query.End},
+	}
+}
+
+func (r *mongoTimeEntryRepository) Report(ctx context.Context, userID string, query model.TaskStatsQuery) (*model.TimeReport, error) {
+	// 日時の差はミリ秒で求まる
+	duration := bson.M{"$subtract": bson.A{"$end", "$start"}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: finishedInRange(userID, query)}},
+		{{Key: "$facet", Value: bson.M{
+			"by_task": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":      "$task_id",
+					"duration": bson.M{"$sum": duration},
+					"count":    bson.M{"$sum": 1},
+				}},
+			},
+			"daily": bson.A{
+				bson.M{"$group": bson.M{
+					"_id": bson.M{"$dateToString": bson.M{
+						"format":   "%Y-%m-%d",
+						"date":     "$start",
+						"timezone": query.Location.String(),
+					}},
+					"duration": bson.M{"$sum": duration},
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, apperrors.NewInternalError("作業時間の集計に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	var result struct {
+		ByTask []struct {
+			TaskID   primitive.ObjectID `bson:"_id"`
+			Duration int64              `bson:"duration"`
+			Count    int64              `bson:"count"`
+		} `bson:"by_task"`
+		Daily []struct {
+			Date     string `bson:"_id"`
+			Duration int64  `bson:"duration"`
+		} `bson:"daily"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, apperrors.NewInternalError("作業時間の集計に失敗しました", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, apperrors.NewInternalError("作業時間の集計に失敗しました", err)
+	}
+
+	report := &model.TimeReport{}
+	for _, g := range result.ByTask {
+		d := time.Duration(g.Duration) * time.Millisecond
+		report.Total += d
+		report.ByTask = append(report.ByTask, model.TaskTimeTotal{TaskID: g.TaskID, Duration: d, Entries: g.Count})
+	}
+	report.SortByTask()
+	daily := make(map[string]time.Duration, len(result.Daily))
+	for _, g := range result.Daily {
+		daily[g.Date] = time.Duration(g.Duration) * time.Millisecond
+	}
+	report.Daily = query.FillDailyDurations(daily)
+	return report, nil
+}
+
+func (r *mongoTimeEntryRepository) ForEachInRange(ctx context.Context, userID string, query model.TaskStatsQuery, fn func(*model.TimeEntry) error) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "start", Value: 1}, {Key: "_id", Value: 1}}).
+		SetBatchSize(exportBatchSize)
+
+	cursor, err := r.collection.Find(ctx, finishedInRange(userID, query), opts)
+	if err != nil {
+		return apperrors.NewInternalError("作業時間の取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry model.TimeEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return apperrors.NewInternalError("作業時間のデコードに失敗しました", err)
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return apperrors.NewInternalError("作業時間の取得に失敗しました", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoTimeEntryRepository_Create(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("timer_already_running", func(mt *mtest.T) {
+		repo := &mongoTimeEntryRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		entry, err := repo.Create(context.Background(), &model.TimeEntry{UserID: "user1", TaskID: primitive.NewObjectID(), Start: time.Now(), Running: true})
+		assert.Nil(t, entry)
+		assert.True(t, apperrors.IsFailedPrecondition(err))
+	})
+}
+
+func TestMongoTimeEntryRepository_StopRunning(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoTimeEntryRepository{collection: mt.Coll}
+		start := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+		end := start.Add(time.Hour)
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "user_id", Value: "user1"},
+				{Key: "start", Value: start},
+				{Key: "end", Value: end},
+			}},
+		})
+
+		entry, err := repo.StopRunning(context.Background(), "user1", end)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, entry.Duration())
+
+		command := mt.GetStartedEvent().Command
+		assert.True(t, command.Lookup("query", "running").Boolean())
+		_, err = command.Lookup("update").Document().LookupErr("$unset", "running")
+		assert.NoError(t, err)
+	})
+
+	mt.Run("not_running", func(mt *mtest.T) {
+		repo := &mongoTimeEntryRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		_, err := repo.StopRunning(context.Background(), "user1", time.Now())
+		assert.Equal(t, ErrTimerNotRunning, err)
+	})
+}
+
+func TestMongoTimeEntryRepository_Report(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := &mongoTimeEntryRepository{collection: mt.Coll}
+		now := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+		query, err := model.NewTaskStatsQuery("2024-04-09", "2024-04-10", time.UTC, now)
+		require.NoError(t, err)
+		short, long := primitive.NewObjectID(), primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "by_task", Value: bson.A{
+				bson.D{{Key: "_id", Value: short}, {Key: "duration", Value: int64(30 * 60 * 1000)}, {Key: "count", Value: int64(1)}},
+				bson.D{{Key: "_id", Value: long}, {Key: "duration", Value: int64(2 * 60 * 60 * 1000)}, {Key: "count", Value: int64(2)}},
+			}},
+			{Key: "daily", Value: bson.A{
+				bson.D{{Key: "_id", Value: "2024-04-10"}, {Key: "duration", Value: int64(150 * 60 * 1000)}},
+			}},
+		}))
+
+		report, err := repo.Report(context.Background(), "user1", query)
+		require.NoError(t, err)
+		assert.Equal(t, 150*time.Minute, report.Total)
+		require.Len(t, report.ByTask, 2)
+		assert.Equal(t, long, report.ByTask[0].TaskID)
+		assert.Equal(t, []model.DailyDuration{
+			{Date: "2024-04-09"},
+			{Date: "2024-04-10", Duration: 150 * time.Minute},
+		}, report.Daily)
+	})
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// errCodeIllegalOperation はスタンドアロンの MongoDB でトランザクションを使おうとしたときのエラーコードです
+const errCodeIllegalOperation = 20
+
+// ErrTransactionsUnsupported は接続先の MongoDB がトランザクションに対応していないことを表します
+var ErrTransactionsUnsupported = apperrors.NewFailedPreconditionError("アトミックな一括操作にはレプリカセット構成の MongoDB が必要です", nil).WithReason("TRANSACTIONS_UNSUPPORTED")
+
+// Transactor は複数の書き込みを1つのトランザクションで実行します
+type Transactor interface {
+	// WithTransaction は fn をトランザクション内で実行します。fn が返すエラーでトランザクションを中止します。
+	// 一時的なエラーでは fn を再実行することがあるため、fn は何度呼ばれても同じ結果になるようにしてください
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type mongoTransactor struct {
+	client *mongo.Client
+}
+
+func NewTransactor(client *mongo.Client) Transactor {
+	return &mongoTransactor{client: client}
+}
+
+func (t *mongoTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return apperrors.NewInternalError("セッションの開始に失敗しました", err)
+	}
+	defer session.EndSession(context.Background())
+
+	// セッションのコンテキストを渡したリポジトリの操作はすべてこのトランザクションに含まれる
+	hooksCtx, hooks := WithCommitHooks(ctx)
+	_, err = session.WithTransaction(hooksCtx, func(sc mongo.SessionContext) (interface{}, error) {
+		// 再実行された場合は前回の実行で登録された処理を捨てる
+		hooks.Reset()
+		return nil, fn(sc)
+	})
+	if err == nil {
+		hooks.Run(ctx)
+		return nil
+	}
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(errCodeIllegalOperation) {
+		return ErrTransactionsUnsupported
+	}
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+	return apperrors.NewInternalError("トランザクションの実行に失敗しました", err)
+}
+
+type commitHooksKey struct{}
+
+// CommitHooks はトランザクションの確定後に実行する処理です。
+// 検索インデックスやイベントの配信のようにトランザクションに参加しない処理は、取り消された書き込みを反映しないよう確定後に実行します
+type CommitHooks struct {
+	fns []func(ctx context.Context)
+}
+
+// WithCommitHooks は AfterCommit で登録した処理を集める CommitHooks と、それを持つコンテキストを返します。
+// Transactor の実装が、fn に渡すコンテキストの元にします
+func WithCommitHooks(ctx context.Context) (context.Context, *CommitHooks) {
+	hooks := &CommitHooks{}
+	return context.WithValue(ctx, commitHooksKey{}, hooks), hooks
+}
+
+// Reset は登録された処理を捨てます
+func (h *CommitHooks) Reset() {
+	h.fns = nil
+}
+
+// Run は登録された順に処理を実行します。ctx にはトランザクションの外のコンテキストを渡してください
+func (h *CommitHooks) Run(ctx context.Context) {
+	fns := h.fns
+	h.fns = nil
+	for _, fn := range fns {
+		fn(ctx)
+	}
+}
+
+// AfterCommit は ctx がトランザクション内であれば確定後に fn を実行するよう登録し、そうでなければ直ちに実行します
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(commitHooksKey{}).(*CommitHooks); ok {
+		hooks.fns = append(hooks.fns, fn)
+		return
+	}
+	fn(ctx)
+}
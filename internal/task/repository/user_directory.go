@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UserDirectory はユーザーサービスが管理するユーザーを参照します
+type UserDirectory interface {
+	// ResolveUsers はメールアドレスまたはユーザーIDを、存在するユーザーのIDに対応付けます。見つからないものは含めません
+	ResolveUsers(ctx context.Context, handles []string) (map[string]string, error)
+	// TimeZone はユーザーのタイムゾーン（IANA）を返します。ユーザーが存在しない場合や設定していない場合は空文字です
+	TimeZone(ctx context.Context, userID string) (string, error)
+}
+
+type mongoUserDirectory struct {
+	collection *mongo.Collection
+}
+
+// NewUserDirectory はユーザーサービスのデータベースの users コレクションを参照する UserDirectory を作成します
+func NewUserDirectory(db *mongo.Database) UserDirectory {
+	return &mongoUserDirectory{
+		collection: db.Collection("users"),
+	}
+}
+
+func (d *mongoUserDirectory) ResolveUsers(ctx context.Context, handles []string) (map[string]string, error) {
+	var emails []string
+	var ids []primitive.ObjectID
+	for _, handle := range handles {
+		if strings.Contains(handle, "@") {
+			emails = append(emails, handle)
+		} else if id, err := primitive.ObjectIDFromHex(handle); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	resolved := make(map[string]string)
+	if len(emails) == 0 && len(ids) == 0 {
+		return resolved, nil
+	}
+
+	cursor, err := d.collection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"email": bson.M{"$in": emails}},
+		bson.M{"_id": bson.M{"$in": ids}},
+	}}, options.Find().SetProjection(bson.M{"email": 1}))
+	if err != nil {
+		return nil, apperrors.NewInternalError("ユーザーの取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Email string             `bson:"email"`
+	}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, apperrors.NewInternalError("ユーザーのデコードに失敗しました", err)
+	}
+	for _, user := range users {
+		resolved[user.ID.Hex()] = user.ID.Hex()
+		resolved[strings.ToLower(user.Email)] = user.ID.Hex()
+	}
+	return resolved, nil
+}
+
+func (d *mongoUserDirectory) TimeZone(ctx context.Context, userID string) (string, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", nil
+	}
+
+	var user struct {
+		TimeZone string `bson:"time_zone"`
+	}
+	err = d.collection.FindOne(ctx, bson.M{"_id": id}, options.FindOne().SetProjection(bson.M{"time_zone": 1})).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", apperrors.NewInternalError("ユーザーの取得に失敗しました", err)
+	}
+	return user.TimeZone, nil
+}
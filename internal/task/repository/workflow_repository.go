@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrWorkflowNotFound is returned when the owner has not defined a workflow
+var ErrWorkflowNotFound = apperrors.NewNotFoundError("ワークフローが見つかりません", nil).WithReason("WORKFLOW_NOT_FOUND")
+
+type WorkflowRepository interface {
+	FindByOwnerID(ctx context.Context, ownerID string) (*model.Workflow, error)
+	// Save は所有者のワークフローを作成または置き換えます
+	Save(ctx context.Context, workflow *model.Workflow) (*model.Workflow, error)
+	Delete(ctx context.Context, ownerID string) error
+	RecordTransition(ctx context.Context, transition *model.TaskTransition) (*model.TaskTransition, error)
+	// FindTransitions はタスクのステータス遷移の履歴を古い順に返します
+	FindTransitions(ctx context.Context, taskID primitive.ObjectID) ([]*model.TaskTransition, error)
+}
+
+type mongoWorkflowRepository struct {
+	collection  *mongo.Collection
+	transitions *mongo.Collection
+}
+
+func NewWorkflowRepository(db *mongo.Database) WorkflowRepository {
+	return &mongoWorkflowRepository{
+		collection:  db.Collection("workflows"),
+		transitions: db.Collection("task_transitions"),
+	}
+}
+
+func (r *mongoWorkflowRepository) FindByOwnerID(ctx context.Context, ownerID string) (*model.Workflow, error) {
+	var workflow model.Workflow
+	err := r.collection.FindOne(ctx, bson.M{"owner_id": ownerID}).Decode(&workflow)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrWorkflowNotFound
+		}
+		return nil, apperrors.NewInternalError("ワークフローの取得に失敗しました", err)
+	}
+
+	return &workflow, nil
+}
+
+func (r *mongoWorkflowRepository) Save(ctx context.Context, workflow *model.Workflow) (*model.Workflow, error) {
+	now := time.Now()
+	workflow.UpdatedAt = now
+
+	var saved model.Workflow
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"owner_id": workflow.OwnerID},
+		bson.M{
+			"$set": bson.M{
+				"statuses":       workflow.Statuses,
+				"transitions":    workflow.Transitions,
+				"initial_status": workflow.InitialStatus,
+				"updated_at":     now,
+			},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		return nil, apperrors.NewInternalError("ワークフローの保存に失敗しました", err)
+	}
+
+	return &saved, nil
+}
+
+func (r *mongoWorkflowRepository) Delete(ctx context.Context, ownerID string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"owner_id": ownerID})
+	if err != nil {
+		return apperrors.NewInternalError("ワークフローの削除に失敗しました", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrWorkflowNotFound
+	}
+	return nil
+}
+
+func (r *mongoWorkflowRepository) RecordTransition(ctx context.Context, transition *model.TaskTransition) (*model.TaskTransition, error) {
+	transition.ID = primitive.NewObjectID()
+	transition.CreatedAt = time.Now()
+
+	if _, err := r.transitions.InsertOne(ctx, transition); err != nil {
+		return nil, apperrors.NewInternalError("ステータス遷移の記録に失敗しました", err)
+	}
+	return transition, nil
+}
+
+func (r *mongoWorkflowRepository) FindTransitions(ctx context.Context, taskID primitive.ObjectID) ([]*model.TaskTransition, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.transitions.Find(ctx, bson.M{"task_id": taskID}, opts)
+	if err != nil {
+		return nil, apperrors.NewInternalError("ステータス遷移の取得に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	transitions := []*model.TaskTransition{}
+	if err := cursor.All(ctx, &transitions); err != nil {
+		return nil, apperrors.NewInternalError("ステータス遷移の取得に失敗しました", err)
+	}
+	return transitions, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoWorkflowRepository_FindByOwnerID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoWorkflowRepository{collection: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.workflows", mtest.FirstBatch))
+
+		workflow, err := repo.FindByOwnerID(context.Background(), "user1")
+		assert.Nil(t, workflow)
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestMongoWorkflowRepository_Save(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("upsert", func(mt *mtest.T) {
+		repo := &mongoWorkflowRepository{collection: mt.Coll}
+		workflow := model.DefaultWorkflow("user1")
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "owner_id", Value: "user1"},
+				{Key: "initial_status", Value: workflow.InitialStatus},
+			}},
+		})
+
+		saved, err := repo.Save(context.Background(), workflow)
+		assert.NoError(t, err)
+		assert.False(t, saved.IsDefault())
+
+		cmd := mt.GetStartedEvent().Command
+		assert.True(t, cmd.Lookup("upsert").Boolean())
+		assert.Equal(t, "user1", cmd.Lookup("query", "owner_id").StringValue())
+		_, err = cmd.LookupErr("update", "$setOnInsert", "created_at")
+		assert.NoError(t, err)
+	})
+}
+
+func TestMongoWorkflowRepository_Delete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not_found", func(mt *mtest.T) {
+		repo := &mongoWorkflowRepository{collection: mt.Coll}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		err := repo.Delete(context.Background(), "user1")
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
@@ -0,0 +1,137 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// posting は語が1つのタスクに出現する回数をフィールドごとに保持します
+type posting struct {
+	title       int
+	description int
+}
+
+// memoryBackend は転置インデックスによるインメモリの検索バックエンドです。
+// テストや MongoDB を使わない開発環境向けです
+type memoryBackend struct {
+	boosts FieldBoosts
+
+	mu       sync.RWMutex
+	tasks    map[string]*model.Task
+	terms    map[string][]string // タスクID -> インデックス済みの語
+	postings map[string]map[string]posting
+}
+
+// NewMemoryBackend はインメモリの検索バックエンドを作成します
+func NewMemoryBackend(boosts FieldBoosts) Backend {
+	return &memoryBackend{
+		boosts:   boosts,
+		tasks:    make(map[string]*model.Task),
+		terms:    make(map[string][]string),
+		postings: make(map[string]map[string]posting),
+	}
+}
+
+func (b *memoryBackend) Index(_ context.Context, task *model.Task) error {
+	id := task.ID.Hex()
+	copied := *task
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.removeLocked(id)
+
+	counts := make(map[string]posting)
+	for _, t := range tokenize(task.Title) {
+		p := counts[t.term]
+		p.title++
+		counts[t.term] = p
+	}
+	for _, t := range tokenize(task.Description) {
+		p := counts[t.term]
+		p.description++
+		counts[t.term] = p
+	}
+
+	terms := make([]string, 0, len(counts))
+	for term, p := range counts {
+		if b.postings[term] == nil {
+			b.postings[term] = make(map[string]posting)
+		}
+		b.postings[term][id] = p
+		terms = append(terms, term)
+	}
+	b.tasks[id] = &copied
+	b.terms[id] = terms
+	return nil
+}
+
+func (b *memoryBackend) Remove(_ context.Context, taskID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(taskID)
+	return nil
+}
+
+func (b *memoryBackend) removeLocked(id string) {
+	for _, term := range b.terms[id] {
+		delete(b.postings[term], id)
+		if len(b.postings[term]) == 0 {
+			delete(b.postings, term)
+		}
+	}
+	delete(b.terms, id)
+	delete(b.tasks, id)
+}
+
+// Search は検索語のいずれかを含むタスクを TF-IDF にフィールドの重みを掛けたスコアで並べます
+func (b *memoryBackend) Search(_ context.Context, userID string, query Query) ([]*Hit, error) {
+	terms := Terms(query.Text)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	scores := make(map[string]float64)
+	n := float64(len(b.tasks))
+	for indexed, docs := range b.postings {
+		if !matches(indexed, terms) {
+			continue
+		}
+		idf := math.Log(1 + n/float64(len(docs)))
+		for id, p := range docs {
+			if b.tasks[id].UserID != userID {
+				continue
+			}
+			scores[id] += idf * (b.boosts.Title*termFrequency(p.title) + b.boosts.Description*termFrequency(p.description))
+		}
+	}
+
+	hits := make([]*Hit, 0, len(scores))
+	for id, score := range scores {
+		task := *b.tasks[id]
+		hits = append(hits, &Hit{Task: &task, Score: score, Highlights: Highlights(&task, terms)})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Task.ID.Hex() < hits[j].Task.ID.Hex()
+	})
+
+	if query.Limit > 0 && int(query.Limit) < len(hits) {
+		hits = hits[:query.Limit]
+	}
+	return hits, nil
+}
+
+// termFrequency は出現回数の多さによるスコアの伸びを対数で抑えます
+func termFrequency(count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return 1 + math.Log(float64(count))
+}
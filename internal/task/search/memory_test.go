@@ -0,0 +1,82 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTask(userID, title, description string) *model.Task {
+	return &model.Task{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		Title:       title,
+		Description: description,
+	}
+}
+
+func TestMemoryBackend_Search(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryBackend(DefaultBoosts)
+
+	inTitle := newTask("user1", "Deploy release", "")
+	inDescription := newTask("user1", "Friday chores", "deploy the release to staging")
+	unrelated := newTask("user1", "Buy milk", "")
+	otherUser := newTask("user2", "Deploy release", "")
+	for _, task := range []*model.Task{inTitle, inDescription, unrelated, otherUser} {
+		assert.NoError(t, backend.Index(ctx, task))
+	}
+
+	t.Run("title_boost_ranks_first", func(t *testing.T) {
+		hits, err := backend.Search(ctx, "user1", Query{Text: "deploy"})
+		assert.NoError(t, err)
+		assert.Len(t, hits, 2)
+		assert.Equal(t, inTitle.ID, hits[0].Task.ID)
+		assert.Equal(t, inDescription.ID, hits[1].Task.ID)
+		assert.Greater(t, hits[0].Score, hits[1].Score)
+		assert.Equal(t, FieldTitle, hits[0].Highlights[0].Field)
+		assert.Equal(t, FieldDescription, hits[1].Highlights[0].Field)
+	})
+
+	t.Run("description_boost", func(t *testing.T) {
+		backend := NewMemoryBackend(FieldBoosts{Title: 1, Description: 10})
+		assert.NoError(t, backend.Index(ctx, inTitle))
+		assert.NoError(t, backend.Index(ctx, inDescription))
+
+		hits, err := backend.Search(ctx, "user1", Query{Text: "deploy"})
+		assert.NoError(t, err)
+		assert.Equal(t, inDescription.ID, hits[0].Task.ID)
+	})
+
+	t.Run("prefix_match_and_limit", func(t *testing.T) {
+		hits, err := backend.Search(ctx, "user1", Query{Text: "dep", Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, hits, 1)
+	})
+
+	t.Run("reindex_replaces_terms", func(t *testing.T) {
+		renamed := *unrelated
+		renamed.Title = "Buy bread"
+		assert.NoError(t, backend.Index(ctx, &renamed))
+
+		hits, err := backend.Search(ctx, "user1", Query{Text: "milk"})
+		assert.NoError(t, err)
+		assert.Empty(t, hits)
+		hits, err = backend.Search(ctx, "user1", Query{Text: "bread"})
+		assert.NoError(t, err)
+		assert.Len(t, hits, 1)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		assert.NoError(t, backend.Remove(ctx, inTitle.ID.Hex()))
+
+		hits, err := backend.Search(ctx, "user1", Query{Text: "deploy"})
+		assert.NoError(t, err)
+		assert.Len(t, hits, 1)
+		assert.Equal(t, inDescription.ID, hits[0].Task.ID)
+	})
+}
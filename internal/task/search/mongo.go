@@ -0,0 +1,91 @@
+package search
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// textIndexName はタスクコレクションのテキストインデックス名です
+const textIndexName = "task_text"
+
+// mongoBackend は MongoDB のテキストインデックスを使う検索バックエンドです
+// フィールドの重みはテキストインデックスの作成時に EnsureTextIndex で指定します
+type mongoBackend struct {
+	collection *mongo.Collection
+}
+
+// NewMongoBackend は MongoDB の検索バックエンドを作成します
+func NewMongoBackend(db *mongo.Database) Backend {
+	return &mongoBackend{
+		collection: db.Collection("tasks"),
+	}
+}
+
+// EnsureTextIndex はフィールドの重みを反映したテキストインデックスを作成します。
+// MongoDB ではテキストインデックスはコレクションに1つのため、重みを変える場合は既存のインデックスを削除してください
+func EnsureTextIndex(ctx context.Context, db *mongo.Database, boosts FieldBoosts) error {
+	_, err := db.Collection("tasks").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: FieldTitle, Value: "text"}, {Key: FieldDescription, Value: "text"}},
+		Options: options.Index().
+			SetName(textIndexName).
+			SetDefaultLanguage("none").
+			SetWeights(bson.D{
+				{Key: FieldTitle, Value: int32(boosts.Title)},
+				{Key: FieldDescription, Value: int32(boosts.Description)},
+			}),
+	})
+	return err
+}
+
+type scoredTask struct {
+	model.Task `bson:",inline"`
+	Score      float64 `bson:"score"`
+}
+
+func (b *mongoBackend) Search(ctx context.Context, userID string, query Query) ([]*Hit, error) {
+	filter := bson.M{
+		"user_id": userID,
+		"$text":   bson.M{"$search": query.Text},
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}})
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit))
+	}
+
+	cursor, err := b.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, apperrors.NewInternalError("タスクの検索に失敗しました", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []*scoredTask
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, apperrors.NewInternalError("タスクの検索に失敗しました", err)
+	}
+
+	terms := Terms(query.Text)
+	hits := make([]*Hit, len(results))
+	for i, r := range results {
+		task := r.Task
+		hits[i] = &Hit{Task: &task, Score: r.Score, Highlights: Highlights(&task, terms)}
+	}
+	return hits, nil
+}
+
+// Index はテキストインデックスが MongoDB 側で更新されるため何もしません
+func (b *mongoBackend) Index(context.Context, *model.Task) error {
+	return nil
+}
+
+// Remove はテキストインデックスが MongoDB 側で更新されるため何もしません
+func (b *mongoBackend) Remove(context.Context, string) error {
+	return nil
+}
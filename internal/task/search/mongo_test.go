@@ -0,0 +1,56 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoBackend_Search(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		backend := &mongoBackend{collection: mt.Coll}
+		taskID := primitive.NewObjectID()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: taskID},
+			{Key: "user_id", Value: "user1"},
+			{Key: "title", Value: "Deploy release"},
+			{Key: "score", Value: 2.25},
+		}))
+
+		hits, err := backend.Search(context.Background(), "user1", Query{Text: "deploy", Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, hits, 1)
+		assert.Equal(t, taskID, hits[0].Task.ID)
+		assert.Equal(t, 2.25, hits[0].Score)
+		assert.Equal(t, []Highlight{{Field: FieldTitle, Snippet: "<em>Deploy</em> release"}}, hits[0].Highlights)
+
+		find := mt.GetStartedEvent()
+		filter := find.Command.Lookup("filter").Document()
+		assert.Equal(t, "user1", filter.Lookup("user_id").StringValue())
+		assert.Equal(t, "deploy", filter.Lookup("$text", "$search").StringValue())
+		assert.Equal(t, int64(10), find.Command.Lookup("limit").Int64())
+	})
+
+	mt.Run("database_error", func(mt *mtest.T) {
+		backend := &mongoBackend{collection: mt.Coll}
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    27,
+			Message: "text index required for $text query",
+		}))
+
+		hits, err := backend.Search(context.Background(), "user1", Query{Text: "deploy"})
+		assert.Error(t, err)
+		assert.Nil(t, hits)
+		assert.True(t, apperrors.IsInternal(err))
+	})
+}
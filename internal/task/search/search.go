@@ -0,0 +1,172 @@
+package search
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/my-backend-project/internal/task/model"
+)
+
+const (
+	// FieldTitle はタイトルフィールドの名前です
+	FieldTitle = "title"
+	// FieldDescription は説明フィールドの名前です
+	FieldDescription = "description"
+
+	// HighlightPre と HighlightPost は一致箇所を囲むマーカーです
+	HighlightPre  = "<em>"
+	HighlightPost = "</em>"
+
+	// snippetRadius は一致箇所の前後に含める文字数です
+	snippetRadius = 40
+)
+
+// FieldBoosts はフィールドごとの関連度の重みです
+type FieldBoosts struct {
+	Title       float64
+	Description float64
+}
+
+// DefaultBoosts はタイトルの一致を説明の一致より重視する既定の重みです
+var DefaultBoosts = FieldBoosts{Title: 3, Description: 1}
+
+// Query は検索条件です
+type Query struct {
+	Text  string
+	Limit int32
+}
+
+// Highlight は一致箇所をマーカーで囲んだフィールドの抜粋です。
+// Snippet はそのまま HTML として表示できるよう、マーカー以外の本文をエスケープしています
+type Highlight struct {
+	Field   string
+	Snippet string
+}
+
+// Hit は検索結果の1件です
+type Hit struct {
+	Task       *model.Task
+	Score      float64
+	Highlights []Highlight
+}
+
+// Backend はタスクの全文検索を行うインターフェースです
+type Backend interface {
+	// Search はユーザーのタスクを関連度の高い順に返します
+	Search(ctx context.Context, userID string, query Query) ([]*Hit, error)
+	// Index はタスクを検索対象に追加・更新します
+	Index(ctx context.Context, task *model.Task) error
+	// Remove はタスクを検索対象から削除します
+	Remove(ctx context.Context, taskID string) error
+}
+
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize は文字と数字の連続を1語として小文字に正規化し、バイト位置とともに返します
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// Terms は検索文字列を重複のない検索語に分割します
+func Terms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(text) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
+
+// matches は語が検索語のいずれかに一致するかを返します。活用形も拾えるよう前方一致で判定します
+func matches(term string, terms []string) bool {
+	for _, t := range terms {
+		if strings.HasPrefix(term, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Highlights はタスクの各フィールドから検索語の一致箇所を含む抜粋を作成します
+func Highlights(task *model.Task, terms []string) []Highlight {
+	var highlights []Highlight
+	if snippet, ok := snippet(task.Title, terms); ok {
+		highlights = append(highlights, Highlight{Field: FieldTitle, Snippet: snippet})
+	}
+	if snippet, ok := snippet(task.Description, terms); ok {
+		highlights = append(highlights, Highlight{Field: FieldDescription, Snippet: snippet})
+	}
+	return highlights
+}
+
+// snippet は最初の一致箇所の前後を切り出し、範囲内の一致箇所をマーカーで囲みます。
+// タイトルや説明に含まれるタグがマーカーと同じように解釈されないよう、本文は HTML エスケープしてからマーカーを加えます
+func snippet(text string, terms []string) (string, bool) {
+	var hits []token
+	for _, t := range tokenize(text) {
+		if matches(t.term, terms) {
+			hits = append(hits, t)
+		}
+	}
+	if len(hits) == 0 {
+		return "", false
+	}
+
+	from := hits[0].start
+	for n := 0; from > 0 && n < snippetRadius; n++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+	to := hits[0].end
+	for n := 0; to < len(text) && n < snippetRadius; n++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, h := range hits {
+		if h.start < from || h.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:h.start]))
+		b.WriteString(HighlightPre)
+		b.WriteString(html.EscapeString(text[h.start:h.end]))
+		b.WriteString(HighlightPost)
+		pos = h.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
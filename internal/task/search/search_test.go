@@ -0,0 +1,52 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"fix", "login", "bug", "2024"}, Terms("Fix login-bug, fix 2024!"))
+	assert.Empty(t, Terms("  ... "))
+	assert.Equal(t, []string{"資料作成"}, Terms("資料作成"))
+}
+
+func TestHighlights(t *testing.T) {
+	task := &model.Task{
+		Title:       "Write the Report",
+		Description: strings.Repeat("a ", 40) + "reporting deadline is near" + strings.Repeat(" b", 40),
+	}
+
+	highlights := Highlights(task, Terms("report"))
+	assert.Len(t, highlights, 2)
+	assert.Equal(t, Highlight{Field: FieldTitle, Snippet: "Write the <em>Report</em>"}, highlights[0])
+
+	desc := highlights[1]
+	assert.Equal(t, FieldDescription, desc.Field)
+	assert.True(t, strings.HasPrefix(desc.Snippet, "…"))
+	assert.True(t, strings.HasSuffix(desc.Snippet, "…"))
+	assert.Contains(t, desc.Snippet, "<em>reporting</em> deadline")
+
+	assert.Empty(t, Highlights(task, Terms("missing")))
+}
+
+func TestHighlights_MultiByte(t *testing.T) {
+	task := &model.Task{Title: "週次 レポート 作成"}
+
+	highlights := Highlights(task, Terms("レポート"))
+	assert.Equal(t, []Highlight{{Field: FieldTitle, Snippet: "週次 <em>レポート</em> 作成"}}, highlights)
+}
+
+func TestHighlights_EscapesHTML(t *testing.T) {
+	task := &model.Task{Title: `<script>alert("report")</script> & report <em>`}
+
+	highlights := Highlights(task, Terms("report"))
+	assert.Equal(t, []Highlight{{
+		Field:   FieldTitle,
+		Snippet: `&lt;script&gt;alert(&#34;<em>report</em>&#34;)&lt;/script&gt; &amp; <em>report</em> &lt;em&gt;`,
+	}}, highlights)
+}
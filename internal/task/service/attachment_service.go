@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/blob"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AttachmentService はタスクの添付ファイルを扱います。添付ファイルを扱えるのはタスクの所有者に限ります
+type AttachmentService interface {
+	// UploadAttachment は content を受信しながら保存し、サイズとチェックサムを記録します
+	UploadAttachment(ctx context.Context, userID string, upload *model.AttachmentUpload, content io.Reader) (*model.Attachment, error)
+	ListAttachments(ctx context.Context, taskID string, userID string) ([]*model.Attachment, error)
+	// OpenAttachment は添付ファイルのメタデータと本体のリーダーを返します。リーダーは呼び出し側で閉じてください
+	OpenAttachment(ctx context.Context, attachmentID string, userID string) (*model.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, attachmentID string, userID string) error
+	// GetQuota はユーザーの添付ファイルの使用量と上限を返します
+	GetQuota(ctx context.Context, userID string) (*model.AttachmentQuota, error)
+	AttachmentCleaner
+}
+
+// AttachmentCleaner はタスクの削除時にタスクの添付ファイルを削除します
+type AttachmentCleaner interface {
+	DeleteTaskAttachments(ctx context.Context, taskID primitive.ObjectID) error
+}
+
+// AttachmentLimits は添付ファイルのサイズの上限です。ゼロ値の項目には既定値を使います
+type AttachmentLimits struct {
+	// MaxSize は1ファイルの最大バイト数です
+	MaxSize int64
+	// Quota はユーザーごとの添付ファイルの合計バイト数の上限です
+	Quota int64
+}
+
+type attachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	taskRepo       repository.TaskRepository
+	blobs          blob.Store
+	limits         AttachmentLimits
+}
+
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, taskRepo repository.TaskRepository, blobs blob.Store, limits AttachmentLimits) AttachmentService {
+	if limits.MaxSize <= 0 {
+		limits.MaxSize = model.DefaultMaxAttachmentSize
+	}
+	if limits.Quota <= 0 {
+		limits.Quota = model.DefaultAttachmentQuota
+	}
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		taskRepo:       taskRepo,
+		blobs:          blobs,
+		limits:         limits,
+	}
+}
+
+var (
+	errAttachmentTooLarge = apperrors.NewInvalidInputError("ファイルサイズが上限を超えています", nil).WithReason("ATTACHMENT_TOO_LARGE")
+	errQuotaExceeded      = apperrors.NewResourceExhaustedError("添付ファイルの容量の上限を超えています", nil).WithReason("ATTACHMENT_QUOTA_EXCEEDED")
+)
+
+func (s *attachmentService) UploadAttachment(ctx context.Context, userID string, upload *model.AttachmentUpload, content io.Reader) (*model.Attachment, error) {
+	task, err := s.authorizeTask(ctx, upload.TaskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	upload.Normalize()
+	if err := upload.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("添付ファイルが不正です", err)
+	}
+
+	quota, err := s.GetQuota(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Size > s.limits.MaxSize {
+		return nil, errAttachmentTooLarge
+	}
+	if upload.Size > quota.Remaining() {
+		return nil, errQuotaExceeded
+	}
+
+	// 受信したバイト数が上限を超えた時点で保存を中断する
+	limited := &limitedReader{r: content, remaining: s.limits.MaxSize, err: errAttachmentTooLarge}
+	if quota.Remaining() < limited.remaining {
+		limited.remaining, limited.err = quota.Remaining(), errQuotaExceeded
+	}
+	hash := sha256.New()
+	attachment := &model.Attachment{
+		ID:          primitive.NewObjectID(),
+		TaskID:      task.ID,
+		UserID:      userID,
+		Name:        upload.Name,
+		ContentType: upload.ContentType,
+	}
+	attachment.StorageKey = attachment.ID.Hex()
+
+	size, err := s.blobs.Put(ctx, attachment.StorageKey, io.TeeReader(limited, hash))
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("添付ファイルの保存に失敗しました", err)
+	}
+	attachment.Size = size
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if upload.Size > 0 && upload.Size != size {
+		s.discardBlob(attachment)
+		return nil, apperrors.NewInvalidInputError(fmt.Sprintf("受信したサイズ（%dバイト）が指定されたサイズ（%dバイト）と一致しません", size, upload.Size), nil)
+	}
+	if upload.Checksum != "" && upload.Checksum != attachment.Checksum {
+		s.discardBlob(attachment)
+		return nil, apperrors.NewInvalidInputError("受信した内容のチェックサムが一致しません", nil)
+	}
+
+	if _, err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		s.discardBlob(attachment)
+		return nil, err
+	}
+	// 同時にアップロードされた他のファイルと合わせて上限を超えた場合は、後から保存した側を取り消す
+	used, err := s.attachmentRepo.UsageByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if used > s.limits.Quota {
+		if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+			return nil, err
+		}
+		s.discardBlob(attachment)
+		return nil, errQuotaExceeded
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) ListAttachments(ctx context.Context, taskID string, userID string) ([]*model.Attachment, error) {
+	task, err := s.authorizeTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attachmentRepo.FindByTask(ctx, task.ID)
+}
+
+func (s *attachmentService) OpenAttachment(ctx context.Context, attachmentID string, userID string) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.authorizeAttachment(ctx, attachmentID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.blobs.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("添付ファイルの読み込みに失敗しました", err)
+	}
+	return attachment, content, nil
+}
+
+func (s *attachmentService) DeleteAttachment(ctx context.Context, attachmentID string, userID string) error {
+	attachment, err := s.authorizeAttachment(ctx, attachmentID, userID)
+	if err != nil {
+		return err
+	}
+	// 本体の削除に失敗しても参照できなくなるよう、メタデータを先に削除する
+	if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+		return err
+	}
+	if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+		return apperrors.NewInternalError("添付ファイルの本体の削除に失敗しました", err)
+	}
+	return nil
+}
+
+func (s *attachmentService) GetQuota(ctx context.Context, userID string) (*model.AttachmentQuota, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	used, err := s.attachmentRepo.UsageByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AttachmentQuota{UsedBytes: used, LimitBytes: s.limits.Quota}, nil
+}
+
+func (s *attachmentService) DeleteTaskAttachments(ctx context.Context, taskID primitive.ObjectID) error {
+	attachments, err := s.attachmentRepo.FindByTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	// 失敗しても再実行で残りを削除できるよう、本体を削除してからメタデータを削除する
+	for _, attachment := range attachments {
+		if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			return apperrors.NewInternalError("添付ファイルの本体の削除に失敗しました", err)
+		}
+		if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil && !apperrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorizeTask はタスクを取得し、userID のユーザーがタスクの添付ファイルを扱えるかを確認します
+func (s *attachmentService) authorizeTask(ctx context.Context, taskID string, userID string) (*model.Task, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.UserID != userID {
+		return nil, apperrors.NewPermissionDeniedError("このタスクの添付ファイルを操作する権限がありません", nil)
+	}
+	return task, nil
+}
+
+func (s *attachmentService) authorizeAttachment(ctx context.Context, attachmentID string, userID string) (*model.Attachment, error) {
+	attachment, err := s.attachmentRepo.FindByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizeTask(ctx, attachment.TaskID.Hex(), userID); err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, repository.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// discardBlob はメタデータを保存しなかったファイルの本体を削除します。
+// 失敗しても参照されない本体が残るだけのため、アップロードのエラーを優先して返します
+func (s *attachmentService) discardBlob(attachment *model.Attachment) {
+	_ = s.blobs.Delete(context.Background(), attachment.StorageKey)
+}
+
+// limitedReader は remaining バイトを超えて読み込むと err を返すリーダーです
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	err       error
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	// 上限を超えたことを検出するため、上限より1バイト多く読み込む
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, l.err
+	}
+	return n, err
+}
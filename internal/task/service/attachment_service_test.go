@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/blob"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryAttachmentRepository はテスト用のインメモリ実装です
+type memoryAttachmentRepository struct {
+	mu          sync.Mutex
+	attachments []*model.Attachment
+}
+
+func (r *memoryAttachmentRepository) Create(_ context.Context, attachment *model.Attachment) (*model.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if attachment.ID.IsZero() {
+		attachment.ID = primitive.NewObjectID()
+	}
+	attachment.CreatedAt = time.Now()
+	stored := *attachment
+	r.attachments = append(r.attachments, &stored)
+	return attachment, nil
+}
+
+func (r *memoryAttachmentRepository) FindByID(_ context.Context, id string) (*model.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, attachment := range r.attachments {
+		if attachment.ID.Hex() == id {
+			copied := *attachment
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrAttachmentNotFound
+}
+
+func (r *memoryAttachmentRepository) FindByTask(_ context.Context, taskID primitive.ObjectID) ([]*model.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.Attachment
+	for _, attachment := range r.attachments {
+		if attachment.TaskID == taskID {
+			result = append(result, attachment)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryAttachmentRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, attachment := range r.attachments {
+		if attachment.ID == id {
+			r.attachments = append(r.attachments[:i], r.attachments[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrAttachmentNotFound
+}
+
+func (r *memoryAttachmentRepository) UsageByUser(_ context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total int64
+	for _, attachment := range r.attachments {
+		if attachment.UserID == userID {
+			total += attachment.Size
+		}
+	}
+	return total, nil
+}
+
+type attachmentFixture struct {
+	svc     AttachmentService
+	tasks   *memoryTaskRepository
+	repo    *memoryAttachmentRepository
+	blobDir string
+	task    *model.Task
+}
+
+func newAttachmentFixture(t *testing.T, limits AttachmentLimits) *attachmentFixture {
+	t.Helper()
+	f := &attachmentFixture{tasks: &memoryTaskRepository{}, repo: &memoryAttachmentRepository{}, blobDir: t.TempDir()}
+	store, err := blob.NewFileStore(f.blobDir)
+	require.NoError(t, err)
+	f.task, err = f.tasks.Create(context.Background(), &model.Task{UserID: "owner", Title: "資料の整理"})
+	require.NoError(t, err)
+	f.svc = NewAttachmentService(f.repo, f.tasks, store, limits)
+	return f
+}
+
+func (f *attachmentFixture) upload(data []byte, checksum string) (*model.Attachment, error) {
+	return f.svc.UploadAttachment(context.Background(), "owner", &model.AttachmentUpload{
+		TaskID:      f.task.ID.Hex(),
+		Name:        "report.pdf",
+		ContentType: "application/pdf",
+		Checksum:    checksum,
+	}, bytes.NewReader(data))
+}
+
+func (f *attachmentFixture) storedBlobs(t *testing.T) int {
+	t.Helper()
+	entries, err := os.ReadDir(f.blobDir)
+	require.NoError(t, err)
+	return len(entries)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAttachmentService_UploadAttachment(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+
+	t.Run("success", func(t *testing.T) {
+		f := newAttachmentFixture(t, AttachmentLimits{})
+		attachment, err := f.upload(data, sha256Hex(data))
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), attachment.Size)
+		assert.Equal(t, sha256Hex(data), attachment.Checksum)
+		assert.Equal(t, "application/pdf", attachment.ContentType)
+
+		got, content, err := f.svc.OpenAttachment(ctx, attachment.ID.Hex(), "owner")
+		require.NoError(t, err)
+		defer content.Close()
+		body, err := io.ReadAll(content)
+		require.NoError(t, err)
+		assert.Equal(t, data, body)
+		assert.Equal(t, attachment.Name, got.Name)
+	})
+
+	t.Run("checksum_mismatch", func(t *testing.T) {
+		f := newAttachmentFixture(t, AttachmentLimits{})
+		_, err := f.upload(data, sha256Hex([]byte("other")))
+		assert.True(t, apperrors.IsInvalidInput(err))
+		assert.Empty(t, f.repo.attachments)
+		assert.Zero(t, f.storedBlobs(t), "保存しなかったファイルの本体を残さない")
+	})
+
+	t.Run("too_large", func(t *testing.T) {
+		f := newAttachmentFixture(t, AttachmentLimits{MaxSize: 1000})
+		_, err := f.upload(data, "")
+		assert.True(t, apperrors.IsInvalidInput(err))
+		assert.Zero(t, f.storedBlobs(t))
+	})
+
+	t.Run("quota_exceeded_while_receiving", func(t *testing.T) {
+		f := newAttachmentFixture(t, AttachmentLimits{Quota: 15000})
+		_, err := f.upload(data, "")
+		require.NoError(t, err)
+
+		_, err = f.upload(data, "")
+		assert.True(t, apperrors.IsResourceExhausted(err))
+		assert.Len(t, f.repo.attachments, 1)
+		assert.Equal(t, 1, f.storedBlobs(t))
+
+		quota, err := f.svc.GetQuota(ctx, "owner")
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), quota.UsedBytes)
+		assert.Equal(t, int64(15000), quota.LimitBytes)
+	})
+
+	t.Run("declared_size_over_quota", func(t *testing.T) {
+		f := newAttachmentFixture(t, AttachmentLimits{Quota: 100})
+		_, err := f.svc.UploadAttachment(ctx, "owner", &model.AttachmentUpload{TaskID: f.task.ID.Hex(), Name: "a.bin", Size: 101}, bytes.NewReader(nil))
+		assert.True(t, apperrors.IsResourceExhausted(err))
+	})
+
+	t.Run("invalid_name", func(t *testing.T) {
+		f := newAttachmentFixture(t, AttachmentLimits{})
+		for _, name := range []string{"", "../etc/passwd", "a\nb"} {
+			_, err := f.svc.UploadAttachment(ctx, "owner", &model.AttachmentUpload{TaskID: f.task.ID.Hex(), Name: name}, bytes.NewReader(data))
+			assert.True(t, apperrors.IsInvalidInput(err), name)
+		}
+	})
+
+	t.Run("permission_denied", func(t *testing.T) {
+		f := newAttachmentFixture(t, AttachmentLimits{})
+		_, err := f.svc.UploadAttachment(ctx, "other", &model.AttachmentUpload{TaskID: f.task.ID.Hex(), Name: "a.txt"}, bytes.NewReader(data))
+		assert.True(t, apperrors.IsPermissionDenied(err))
+	})
+}
+
+func TestAttachmentService_DeleteAttachment(t *testing.T) {
+	ctx := context.Background()
+	f := newAttachmentFixture(t, AttachmentLimits{})
+	attachment, err := f.upload([]byte("data"), "")
+	require.NoError(t, err)
+
+	assert.True(t, apperrors.IsPermissionDenied(f.svc.DeleteAttachment(ctx, attachment.ID.Hex(), "other")))
+	require.NoError(t, f.svc.DeleteAttachment(ctx, attachment.ID.Hex(), "owner"))
+	assert.Zero(t, f.storedBlobs(t))
+	assert.True(t, apperrors.IsNotFound(f.svc.DeleteAttachment(ctx, attachment.ID.Hex(), "owner")))
+}
+
+func TestTaskService_DeleteTaskRemovesAttachments(t *testing.T) {
+	ctx := context.Background()
+	f := newAttachmentFixture(t, AttachmentLimits{})
+	_, err := f.upload([]byte("first"), "")
+	require.NoError(t, err)
+	_, err = f.upload([]byte("second"), "")
+	require.NoError(t, err)
+	require.Equal(t, 2, f.storedBlobs(t))
+
+	svc := NewTaskService(f.tasks, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, f.svc, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	require.NoError(t, svc.DeleteTask(ctx, f.task.ID.Hex()))
+	assert.Empty(t, f.repo.attachments)
+	assert.Zero(t, f.storedBlobs(t))
+}
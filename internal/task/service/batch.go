@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// errBatchAborted はアトミックな一括操作で、他の項目が失敗したために取り消された項目のエラーです
+	errBatchAborted = apperrors.NewFailedPreconditionError("他の項目の失敗により取り消されました", nil).WithReason("BATCH_ABORTED")
+	// errBatchItemFailed はアトミックな一括操作のトランザクションを中止するためのエラーです
+	errBatchItemFailed = apperrors.NewFailedPreconditionError("一括操作の一部の項目が失敗しました", nil).WithReason("BATCH_ITEM_FAILED")
+)
+
+func (s *taskService) BatchCreateTasks(ctx context.Context, userID string, tasks []*model.Task, atomic bool) (*model.BatchResult, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	if len(tasks) == 0 {
+		return nil, apperrors.NewInvalidInputError("作成するタスクを指定してください", nil)
+	}
+	if len(tasks) > model.MaxBatchSize {
+		return nil, apperrors.NewInvalidInputError(fmt.Sprintf("一度に操作できるタスクは%d件までです", model.MaxBatchSize), nil)
+	}
+
+	return s.runBatch(ctx, atomic, func(ctx context.Context) (*model.BatchResult, error) {
+		workflow, err := s.workflowFor(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := newBatchResult(len(tasks))
+		var pending []*model.BatchItemResult
+		var writes []*model.Task
+		for i, task := range tasks {
+			// トランザクションの再実行に備え、リクエストのタスクは書き換えない
+			next := *task
+			next.UserID = userID
+			if err := s.validateTask(ctx, &next); err != nil {
+				result.Items[i].Err = err
+				continue
+			}
+			if err := s.prepareCreate(ctx, workflow, &next); err != nil {
+				result.Items[i].Err = err
+				continue
+			}
+			pending = append(pending, result.Items[i])
+			writes = append(writes, &next)
+		}
+		if atomic && result.FailedCount() > 0 {
+			return result, nil
+		}
+
+		errs, err := s.taskRepo.BulkCreate(ctx, writes)
+		if err != nil {
+			return nil, apperrors.NewInternalError("タスクの作成に失敗しました", err)
+		}
+		for j, item := range pending {
+			if errs[j] != nil {
+				item.Err = errs[j]
+				continue
+			}
+			created := writes[j]
+			if err := s.syncTask(ctx, created); err != nil {
+				item.Err = err
+				continue
+			}
+			item.TaskID = created.ID
+			item.Task = created
+		}
+		return result, nil
+	})
+}
+
+func (s *taskService) BatchUpdateTasks(ctx context.Context, userID string, selector *model.TaskSelector, patch *model.TaskPatch, atomic bool) (*model.BatchResult, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	if err := selector.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("一括操作の対象が不正です", err)
+	}
+	if err := patch.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("変更内容が不正です", err)
+	}
+
+	return s.runBatch(ctx, atomic, func(ctx context.Context) (*model.BatchResult, error) {
+		result, targets, err := s.selectTasks(ctx, userID, selector)
+		if err != nil {
+			return nil, err
+		}
+		workflow, err := s.workflowFor(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		var pending []*model.BatchItemResult
+		var writes []*model.Task
+		var changes []*statusChange
+		for i, current := range targets {
+			if current == nil {
+				continue
+			}
+			next, err := patch.Apply(current)
+			if err != nil {
+				result.Items[i].Err = apperrors.NewInvalidInputError("ラベルが不正です", err)
+				continue
+			}
+			change, err := s.prepareUpdate(ctx, workflow, current, next)
+			if err != nil {
+				result.Items[i].Err = err
+				continue
+			}
+			pending = append(pending, result.Items[i])
+			writes = append(writes, next)
+			changes = append(changes, change)
+		}
+		if atomic && result.FailedCount() > 0 {
+			return result, nil
+		}
+
+		errs, err := s.taskRepo.BulkUpdate(ctx, writes)
+		if err != nil {
+			return nil, apperrors.NewInternalError("タスクの更新に失敗しました", err)
+		}
+		var updatedTasks []*model.Task
+		for j, item := range pending {
+			if errs[j] != nil {
+				item.Err = errs[j]
+				continue
+			}
+			updated, err := s.finishUpdate(ctx, changes[j], writes[j])
+			if err != nil {
+				item.Err = err
+				continue
+			}
+			item.Task = updated
+			updatedTasks = append(updatedTasks, updated)
+		}
+		if err := s.attachProgress(ctx, updatedTasks...); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+}
+
+func (s *taskService) BatchDeleteTasks(ctx context.Context, userID string, selector *model.TaskSelector, atomic bool) (*model.BatchResult, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	if err := selector.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("一括操作の対象が不正です", err)
+	}
+
+	return s.runBatch(ctx, atomic, func(ctx context.Context) (*model.BatchResult, error) {
+		result, targets, err := s.selectTasks(ctx, userID, selector)
+		if err != nil {
+			return nil, err
+		}
+		if atomic && result.FailedCount() > 0 {
+			return result, nil
+		}
+
+		var pending []*model.BatchItemResult
+		var deletes []*model.Task
+		var ids []primitive.ObjectID
+		for i, task := range targets {
+			if task == nil {
+				continue
+			}
+			pending = append(pending, result.Items[i])
+			deletes = append(deletes, task)
+			ids = append(ids, task.ID)
+		}
+
+		errs, err := s.taskRepo.BulkDelete(ctx, ids)
+		if err != nil {
+			return nil, apperrors.NewInternalError("タスクの削除に失敗しました", err)
+		}
+		deleted := make(map[primitive.ObjectID]*model.Task, len(deletes))
+		for j, task := range deletes {
+			if errs[j] != nil {
+				pending[j].Err = errs[j]
+				continue
+			}
+			deleted[task.ID] = task
+		}
+		for j, task := range deletes {
+			if errs[j] != nil {
+				continue
+			}
+			// サブタスクは削除しなかった最も近い祖先の直下へ引き上げるため、削除の順序によらず同じ結果になる
+			if err := s.finishDelete(ctx, task, survivingAncestor(task, deleted)); err != nil {
+				pending[j].Err = err
+				continue
+			}
+			if err := s.cleanupDeleted(ctx, task); err != nil {
+				pending[j].Err = err
+			}
+		}
+		return result, nil
+	})
+}
+
+// runBatch は一括操作の本体 fn を実行します。atomic の場合はトランザクション内で実行し、
+// いずれかの項目が失敗したらすべて取り消して、成功していた項目を errBatchAborted にします。
+// 検索インデックスなどトランザクションに参加しない反映は afterCommit で確定後に行うため、取り消した場合は残りません
+func (s *taskService) runBatch(ctx context.Context, atomic bool, fn func(ctx context.Context) (*model.BatchResult, error)) (*model.BatchResult, error) {
+	if !atomic {
+		return fn(ctx)
+	}
+	if s.tx == nil {
+		return nil, apperrors.NewFailedPreconditionError("アトミックな一括操作は利用できません", nil)
+	}
+
+	var result *model.BatchResult
+	err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if result, err = fn(ctx); err != nil {
+			return err
+		}
+		if result.FailedCount() > 0 {
+			return errBatchItemFailed
+		}
+		return nil
+	})
+	if errors.Is(err, errBatchItemFailed) {
+		for _, item := range result.Items {
+			if item.Err == nil {
+				item.Err = errBatchAborted
+				item.Task = nil
+			}
+		}
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// selectTasks は一括操作の対象のタスクを取得し、対象ごとの結果とタスクを同じ位置に並べて返します。
+// ID で指定した場合、取得できなかったタスクは項目のエラーにし、タスクは nil にします
+func (s *taskService) selectTasks(ctx context.Context, userID string, selector *model.TaskSelector) (*model.BatchResult, []*model.Task, error) {
+	if selector.Filter != nil {
+		filter, err := s.resolveFilter(ctx, userID, selector.Filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		// 上限を超えたことを判定するため1件多く取得する
+		tasks, _, err := s.taskRepo.FindByUserID(ctx, userID, filter, model.MaxBatchSize+1, nil)
+		if err != nil {
+			if apperrors.IsInvalidInput(err) {
+				return nil, nil, err
+			}
+			return nil, nil, apperrors.NewInternalError("タスク一覧の取得に失敗しました", err)
+		}
+		if len(tasks) > model.MaxBatchSize {
+			return nil, nil, apperrors.NewInvalidInputError(
+				fmt.Sprintf("条件に一致するタスクが%d件を超えています。条件を絞り込んでください", model.MaxBatchSize), nil)
+		}
+		result := newBatchResult(len(tasks))
+		for i, task := range tasks {
+			result.Items[i].TaskID = task.ID
+		}
+		return result, tasks, nil
+	}
+
+	result := newBatchResult(len(selector.TaskIDs))
+	ids := make([]primitive.ObjectID, 0, len(selector.TaskIDs))
+	for i, id := range selector.TaskIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			result.Items[i].Err = apperrors.NewInvalidInputError("無効なIDです", err)
+			continue
+		}
+		result.Items[i].TaskID = objectID
+		ids = append(ids, objectID)
+	}
+	found, err := s.taskRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("タスクの取得に失敗しました", err)
+	}
+	byID := make(map[primitive.ObjectID]*model.Task, len(found))
+	for _, task := range found {
+		byID[task.ID] = task
+	}
+
+	targets := make([]*model.Task, len(result.Items))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for i, item := range result.Items {
+		if item.Err != nil {
+			continue
+		}
+		task, ok := byID[item.TaskID]
+		switch {
+		case seen[item.TaskID]:
+			item.Err = apperrors.NewInvalidInputError("タスクIDが重複しています", nil)
+		case !ok:
+			item.Err = apperrors.NewNotFoundError("タスクが見つかりません", nil)
+		case task.UserID != userID:
+			item.Err = apperrors.NewPermissionDeniedError("このタスクを操作する権限がありません", nil)
+		default:
+			// 同じタスクが複数の位置から同じ値を参照しないよう複製する
+			copied := *task
+			targets[i] = &copied
+		}
+		seen[item.TaskID] = true
+	}
+	return result, targets, nil
+}
+
+// survivingAncestor は task の祖先のうち deleted に含まれない最も近いものの ID を返します。なければルートです
+func survivingAncestor(task *model.Task, deleted map[primitive.ObjectID]*model.Task) primitive.ObjectID {
+	parentID := task.ParentID
+	for i := 0; i <= model.MaxSubtaskDepth; i++ {
+		parent, ok := deleted[parentID]
+		if !ok {
+			return parentID
+		}
+		parentID = parent.ParentID
+	}
+	return parentID
+}
+
+func newBatchResult(n int) *model.BatchResult {
+	result := &model.BatchResult{Items: make([]*model.BatchItemResult, n)}
+	for i := range result.Items {
+		result.Items[i] = &model.BatchItemResult{Index: i}
+	}
+	return result
+}
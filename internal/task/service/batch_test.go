@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 以下は memoryTaskRepository のうち、一括操作に必要なメソッドです
+
+func (r *memoryTaskRepository) FindByIDs(_ context.Context, ids []primitive.ObjectID) ([]*model.Task, error) {
+	var tasks []*model.Task
+	for _, id := range ids {
+		if task := r.find(id.Hex()); task != nil {
+			copied := *task
+			tasks = append(tasks, &copied)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *memoryTaskRepository) BulkCreate(ctx context.Context, tasks []*model.Task) ([]error, error) {
+	for _, task := range tasks {
+		created, _ := r.Create(ctx, task)
+		task.ID = created.ID
+	}
+	return make([]error, len(tasks)), nil
+}
+
+func (r *memoryTaskRepository) BulkUpdate(_ context.Context, tasks []*model.Task) ([]error, error) {
+	for _, task := range tasks {
+		if stored := r.find(task.ID.Hex()); stored != nil {
+			*stored = *task
+		}
+	}
+	return make([]error, len(tasks)), nil
+}
+
+func (r *memoryTaskRepository) BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]error, error) {
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		errs[i] = r.Delete(ctx, id.Hex())
+	}
+	return errs, nil
+}
+
+// memoryTransactor は fn が失敗したらタスクを実行前の状態に戻し、成功したら確定後の処理を実行するテスト用のトランザクションです
+type memoryTransactor struct {
+	repo *memoryTaskRepository
+}
+
+func (t *memoryTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := make([]*model.Task, len(t.repo.tasks))
+	for i, task := range t.repo.tasks {
+		copied := *task
+		snapshot[i] = &copied
+	}
+	txCtx, hooks := repository.WithCommitHooks(ctx)
+	if err := fn(txCtx); err != nil {
+		t.repo.tasks = snapshot
+		return err
+	}
+	hooks.Run(ctx)
+	return nil
+}
+
+func newBatchTestService() (TaskService, *memoryTaskRepository) {
+	repo := &memoryTaskRepository{}
+	return NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, &memoryTransactor{repo: repo}, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn), repo
+}
+
+func TestTaskService_BatchCreateTasks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("partial_failure", func(t *testing.T) {
+		svc, repo := newBatchTestService()
+
+		result, err := svc.BatchCreateTasks(ctx, "user1", []*model.Task{
+			{UserID: "user2", Title: "a", DueDate: testDueDate},
+			{Title: "b", DueDate: testDueDate, Checklist: []model.ChecklistItem{{Text: " "}}},
+			{Title: "c", DueDate: testDueDate},
+		}, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedCount())
+		assert.True(t, apperrors.IsInvalidInput(result.Items[1].Err))
+		assert.Equal(t, "user1", result.Items[0].Task.UserID)
+		assert.Equal(t, model.TaskStatusPending, result.Items[2].Task.Status)
+		assert.Len(t, repo.tasks, 2)
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		svc, repo := newBatchTestService()
+
+		result, err := svc.BatchCreateTasks(ctx, "user1", []*model.Task{
+			{Title: "a", DueDate: testDueDate},
+			{Title: "b", DueDate: testDueDate, ParentID: primitive.NewObjectID()},
+		}, true)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.FailedCount())
+		assert.Equal(t, errBatchAborted, result.Items[0].Err)
+		assert.True(t, apperrors.IsInvalidInput(result.Items[1].Err))
+		assert.Empty(t, repo.tasks)
+	})
+
+	t.Run("atomic_indexes_after_commit", func(t *testing.T) {
+		repo := &memoryTaskRepository{}
+		searcher := search.NewMemoryBackend(search.DefaultBoosts)
+		svc := NewTaskService(&failingBulkCreateRepository{repo}, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, &memoryTransactor{repo: repo}, testCodec, searcher, nil, CompletionPolicyWarn)
+
+		// 2件目の書き込みが失敗して取り消された1件目は検索インデックスに残らない
+		result, err := svc.BatchCreateTasks(ctx, "user1", []*model.Task{
+			{Title: "alpha", DueDate: testDueDate},
+			{Title: "beta", DueDate: testDueDate},
+		}, true)
+		require.NoError(t, err)
+		assert.Equal(t, errBatchAborted, result.Items[0].Err)
+		assert.Empty(t, repo.tasks)
+		hits, err := searcher.Search(ctx, "user1", search.Query{Text: "alpha", Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+
+		result, err = svc.BatchCreateTasks(ctx, "user1", []*model.Task{{Title: "alpha", DueDate: testDueDate}}, true)
+		require.NoError(t, err)
+		assert.Zero(t, result.FailedCount())
+		hits, err = searcher.Search(ctx, "user1", search.Query{Text: "alpha", Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, hits, 1)
+	})
+
+	t.Run("size_limit", func(t *testing.T) {
+		svc, _ := newBatchTestService()
+
+		tasks := make([]*model.Task, model.MaxBatchSize+1)
+		_, err := svc.BatchCreateTasks(ctx, "user1", tasks, false)
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+}
+
+func TestTaskService_BatchUpdateTasks(t *testing.T) {
+	ctx := context.Background()
+	complete := &model.TaskPatch{Status: model.TaskStatusComplete, AddLabels: []string{"done"}}
+
+	t.Run("by_ids", func(t *testing.T) {
+		svc, repo := newBatchTestService()
+		own := createChain(t, svc, 1)[0]
+		other, err := svc.CreateTask(ctx, &model.Task{UserID: "user2", Title: "other", DueDate: testDueDate})
+		require.NoError(t, err)
+
+		result, err := svc.BatchUpdateTasks(ctx, "user1", &model.TaskSelector{
+			TaskIDs: []string{own.ID.Hex(), other.ID.Hex(), primitive.NewObjectID().Hex(), "invalid"},
+		}, complete, false)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.FailedCount())
+		assert.Equal(t, model.TaskStatusComplete, result.Items[0].Task.Status)
+		assert.True(t, apperrors.IsPermissionDenied(result.Items[1].Err))
+		assert.True(t, apperrors.IsNotFound(result.Items[2].Err))
+		assert.True(t, apperrors.IsInvalidInput(result.Items[3].Err))
+
+		stored := repo.find(own.ID.Hex())
+		assert.Equal(t, model.TaskStatusComplete, stored.Status)
+		assert.Equal(t, []string{"done"}, stored.Labels)
+		assert.Equal(t, model.TaskStatusPending, repo.find(other.ID.Hex()).Status)
+	})
+
+	t.Run("by_filter", func(t *testing.T) {
+		svc, repo := newBatchTestService()
+		chain := createChain(t, svc, 3)
+		done, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "done", Status: model.TaskStatusComplete, DueDate: testDueDate})
+		require.NoError(t, err)
+
+		result, err := svc.BatchUpdateTasks(ctx, "user1", &model.TaskSelector{
+			Filter: &model.TaskFilter{Statuses: []model.TaskStatus{model.TaskStatusPending}},
+		}, complete, false)
+		require.NoError(t, err)
+		assert.Len(t, result.Items, 3)
+		assert.Zero(t, result.FailedCount())
+		for _, task := range chain {
+			assert.Equal(t, model.TaskStatusComplete, repo.find(task.ID.Hex()).Status)
+		}
+		assert.Empty(t, repo.find(done.ID.Hex()).Labels)
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		svc, repo := newBatchTestService()
+		own := createChain(t, svc, 1)[0]
+
+		result, err := svc.BatchUpdateTasks(ctx, "user1", &model.TaskSelector{
+			TaskIDs: []string{own.ID.Hex(), primitive.NewObjectID().Hex()},
+		}, complete, true)
+		require.NoError(t, err)
+		assert.Equal(t, errBatchAborted, result.Items[0].Err)
+		assert.Nil(t, result.Items[0].Task)
+		assert.Equal(t, model.TaskStatusPending, repo.find(own.ID.Hex()).Status)
+	})
+
+	t.Run("invalid_request", func(t *testing.T) {
+		svc, _ := newBatchTestService()
+
+		_, err := svc.BatchUpdateTasks(ctx, "user1", &model.TaskSelector{}, complete, false)
+		assert.True(t, apperrors.IsInvalidInput(err))
+		_, err = svc.BatchUpdateTasks(ctx, "user1", &model.TaskSelector{TaskIDs: []string{"a"}}, &model.TaskPatch{}, false)
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("atomic_unavailable", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+
+		_, err := svc.BatchUpdateTasks(ctx, "user1", &model.TaskSelector{TaskIDs: []string{primitive.NewObjectID().Hex()}}, complete, true)
+		assert.True(t, apperrors.IsFailedPrecondition(err))
+	})
+}
+
+func TestTaskService_BatchDeleteTasks(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newBatchTestService()
+	chain := createChain(t, svc, 4)
+
+	// 子を親より先に指定しても、残ったサブタスクは削除しなかった最も近い祖先へ移る
+	result, err := svc.BatchDeleteTasks(ctx, "user1", &model.TaskSelector{
+		TaskIDs: []string{chain[2].ID.Hex(), chain[1].ID.Hex()},
+	}, false)
+	require.NoError(t, err)
+	assert.Zero(t, result.FailedCount())
+	assert.Nil(t, repo.find(chain[1].ID.Hex()))
+	assert.Nil(t, repo.find(chain[2].ID.Hex()))
+	assert.Equal(t, chain[0].ID, repo.find(chain[3].ID.Hex()).ParentID)
+
+	result, err = svc.BatchDeleteTasks(ctx, "user1", &model.TaskSelector{
+		TaskIDs: []string{chain[0].ID.Hex(), chain[0].ID.Hex()},
+	}, false)
+	require.NoError(t, err)
+	assert.NoError(t, result.Items[0].Err)
+	assert.True(t, apperrors.IsInvalidInput(result.Items[1].Err))
+	assert.False(t, repo.find(chain[3].ID.Hex()).HasParent())
+}
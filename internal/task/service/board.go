@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/pkg/logger"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"go.uber.org/zap"
+)
+
+// maxRankAttempts は同時に移動した他のタスクとランクが重なったときや、ランクを並べ直したときにやり直す回数です
+const maxRankAttempts = 5
+
+func (s *taskService) MoveTaskOnBoard(ctx context.Context, id string, userID string, move model.BoardMove) (*model.Task, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	if move.BeforeID == id || move.AfterID == id {
+		return nil, apperrors.NewInvalidInputError("移動するタスク自身を前後のタスクに指定することはできません", nil)
+	}
+	task, err := s.findTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.UserID != userID {
+		return nil, apperrors.NewNotFoundError("タスクが見つかりません", nil)
+	}
+	workflow, err := s.workflowFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	column := move.WorkflowStatus
+	if column == "" {
+		column = workflow.CurrentStatus(task)
+	}
+	if _, ok := workflow.Status(column); !ok {
+		return nil, apperrors.NewInvalidInputError("無効なステータスです", nil)
+	}
+
+	// 前後のタスクを先に確認し、列の移動だけが行われることのないようにする
+	before, err := s.findBoardNeighbour(ctx, workflow, userID, column, move.BeforeID)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.findBoardNeighbour(ctx, workflow, userID, column, move.AfterID)
+	if err != nil {
+		return nil, err
+	}
+	if before != nil && after != nil && before.Rank >= after.Rank {
+		return nil, apperrors.NewInvalidInputError("前後のタスクの順序が正しくありません", nil)
+	}
+
+	// 列を移す場合は遷移できるかを先に確認し、並び順を書き込んでから遷移を反映する。
+	// 遷移の書き込みに失敗した場合は元の並び順に戻し、並び順だけが変わったままにしない
+	var next *model.Task
+	if column != workflow.CurrentStatus(task) {
+		if next, err = s.prepareTransition(ctx, workflow, task, column, ""); err != nil {
+			return nil, err
+		}
+	}
+	placed, err := s.placeOnBoard(ctx, task, workflow, column, move, before, after)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		if err := s.attachProgress(ctx, placed); err != nil {
+			return nil, err
+		}
+		return placed, nil
+	}
+
+	moved, _, err := s.applyTransition(ctx, workflow, task, next, "")
+	if err != nil {
+		s.revertRank(ctx, workflow, task, placed)
+		return nil, err
+	}
+	return moved, nil
+}
+
+// placeOnBoard は task に before と after の間のランクを書き込み、書き込み後のタスクを返します。
+// 他の移動とランクが重なった場合や並べ直しが必要な場合は、前後のタスクを取得し直してやり直します
+func (s *taskService) placeOnBoard(ctx context.Context, task *model.Task, workflow *model.Workflow, column string, move model.BoardMove, before, after *model.Task) (*model.Task, error) {
+	id := task.ID.Hex()
+	var err error
+
+	for attempt := 1; attempt <= maxRankAttempts; attempt++ {
+		if attempt > 1 {
+			// 並べ直しや他の移動でランクが変わっているため取得し直す
+			if task, err = s.findTask(ctx, id); err != nil {
+				return nil, err
+			}
+			if before, err = s.findBoardNeighbour(ctx, workflow, task.UserID, column, move.BeforeID); err != nil {
+				return nil, err
+			}
+			if after, err = s.findBoardNeighbour(ctx, workflow, task.UserID, column, move.AfterID); err != nil {
+				return nil, err
+			}
+		}
+
+		rank, ok, err := s.boardRank(ctx, task, column, before, after)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			// ランクが長くなりすぎたか間にランクを作れない場合は、並べ直してから求め直す
+			if _, err := s.taskRepo.RebalanceRanks(ctx, task.UserID); err != nil {
+				return nil, apperrors.NewInternalError("タスクの並び順の変更に失敗しました", err)
+			}
+			continue
+		}
+		if rank == "" {
+			return task, nil
+		}
+		moved, err := s.taskRepo.SetRank(ctx, id, rank)
+		if apperrors.IsAlreadyExists(err) {
+			continue
+		}
+		if err != nil {
+			if apperrors.IsNotFound(err) {
+				return nil, apperrors.NewNotFoundError("タスクが見つかりません", err)
+			}
+			return nil, apperrors.NewInternalError("タスクの並び順の変更に失敗しました", err)
+		}
+		return moved, nil
+	}
+	return nil, repository.ErrRankConflict
+}
+
+// revertRank は列の移動を反映できなかった task のランクを移動前に戻します。
+// 遷移の書き込み自体は済んでいる場合は、移動後の並び順のまま残します
+func (s *taskService) revertRank(ctx context.Context, workflow *model.Workflow, original *model.Task, placed *model.Task) {
+	if placed.Rank == original.Rank {
+		return
+	}
+	id := original.ID.Hex()
+	current, err := s.taskRepo.FindByID(ctx, id)
+	if err == nil && workflow.CurrentStatus(current) != workflow.CurrentStatus(original) {
+		return
+	}
+	if _, err := s.taskRepo.SetRank(ctx, id, original.Rank); err != nil {
+		logger.Error("Failed to revert task rank",
+			zap.String("task_id", id),
+			zap.Error(err),
+		)
+	}
+}
+
+// boardRank は task を before の直後、before がなければ after の直前、どちらもなければ列の末尾に置くランクを返します。
+// 既にその位置にある場合は空文字を返し、並べ直しが必要な場合は false を返します
+func (s *taskService) boardRank(ctx context.Context, task *model.Task, column string, before, after *model.Task) (string, bool, error) {
+	if task.Rank == "" || (before != nil && before.Rank == "") || (after != nil && after.Rank == "") {
+		return "", false, nil
+	}
+
+	var lo, hi string
+	var err error
+	switch {
+	case before != nil:
+		// ランクはユーザーのタスク全体で一意なので、全体での直後のタスクとの間に置けば列の中でも before の直後になる
+		lo = before.Rank
+		hi, err = s.taskRepo.FindAdjacentRank(ctx, task.UserID, lo, true)
+	case after != nil:
+		hi = after.Rank
+		lo, err = s.taskRepo.FindAdjacentRank(ctx, task.UserID, hi, false)
+	default:
+		if lo, err = s.taskRepo.FindLastRank(ctx, task.UserID, column); err == nil && lo != "" {
+			hi, err = s.taskRepo.FindAdjacentRank(ctx, task.UserID, lo, true)
+		}
+	}
+	if err != nil {
+		return "", false, apperrors.NewInternalError("タスクの並び順の取得に失敗しました", err)
+	}
+	if lo == task.Rank || hi == task.Rank || (lo == "" && hi == "") {
+		return "", true, nil
+	}
+
+	rank, ok := model.RankBetween(lo, hi)
+	if !ok || len(rank) > model.MaxRankLength {
+		return "", false, nil
+	}
+	return rank, true, nil
+}
+
+// findBoardNeighbour は移動先の前後に指定されたタスクを取得し、同じユーザーの移動先の列のタスクかを確認します。id が空の場合は nil を返します
+func (s *taskService) findBoardNeighbour(ctx context.Context, workflow *model.Workflow, userID string, column string, id string) (*model.Task, error) {
+	if id == "" {
+		return nil, nil
+	}
+	neighbour, err := s.taskRepo.FindByID(ctx, id)
+	if err != nil {
+		if apperrors.IsNotFound(err) || apperrors.IsInvalidInput(err) {
+			return nil, apperrors.NewInvalidInputError("前後のタスクが見つかりません", err)
+		}
+		return nil, apperrors.NewInternalError("タスクの取得に失敗しました", err)
+	}
+	if neighbour.UserID != userID {
+		return nil, apperrors.NewInvalidInputError("前後のタスクが見つかりません", nil)
+	}
+	if workflow.CurrentStatus(neighbour) != column {
+		return nil, apperrors.NewInvalidInputError("前後のタスクが移動先の列にありません", nil)
+	}
+	return neighbour, nil
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 以下は memoryTaskRepository のうち、ボード上の並び順に必要なメソッドです
+
+func (r *memoryTaskRepository) SetRank(_ context.Context, id string, rank string) (*model.Task, error) {
+	stored := r.find(id)
+	if stored == nil {
+		return nil, repository.ErrTaskNotFound
+	}
+	for _, task := range r.tasks {
+		if task != stored && task.UserID == stored.UserID && task.Rank == rank {
+			return nil, repository.ErrRankConflict
+		}
+	}
+	stored.Rank = rank
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *memoryTaskRepository) FindAdjacentRank(_ context.Context, userID string, rank string, after bool) (string, error) {
+	var found string
+	for _, task := range r.tasks {
+		if task.UserID != userID || task.Rank == "" {
+			continue
+		}
+		if after && task.Rank > rank && (found == "" || task.Rank < found) {
+			found = task.Rank
+		}
+		if !after && task.Rank < rank && task.Rank > found {
+			found = task.Rank
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryTaskRepository) FindLastRank(_ context.Context, userID string, workflowStatus string) (string, error) {
+	var last string
+	for _, task := range r.tasks {
+		if task.UserID == userID && task.WorkflowStatus == workflowStatus && task.Rank > last {
+			last = task.Rank
+		}
+	}
+	return last, nil
+}
+
+func (r *memoryTaskRepository) RebalanceRanks(_ context.Context, userID string) ([]primitive.ObjectID, error) {
+	var tasks []*model.Task
+	taken := make(map[string]bool)
+	for _, task := range r.tasks {
+		if task.UserID == userID {
+			tasks = append(tasks, task)
+			taken[task.Rank] = true
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Rank != tasks[j].Rank {
+			return tasks[i].Rank < tasks[j].Rank
+		}
+		return tasks[i].ID.Hex() < tasks[j].ID.Hex()
+	})
+	var changed []primitive.ObjectID
+	for i, rank := range model.RebalanceRanks(len(tasks), taken) {
+		if tasks[i].Rank != rank {
+			tasks[i].Rank = rank
+			changed = append(changed, tasks[i].ID)
+		}
+	}
+	return changed, nil
+}
+
+// conflictingRankRepository は最初の SetRank の直前に、他のリクエストが同じランクへタスクを移動したように振る舞います
+type conflictingRankRepository struct {
+	*memoryTaskRepository
+	other     string
+	conflicts int
+}
+
+func (r *conflictingRankRepository) SetRank(ctx context.Context, id string, rank string) (*model.Task, error) {
+	if r.conflicts > 0 {
+		r.conflicts--
+		if _, err := r.memoryTaskRepository.SetRank(ctx, r.other, rank); err != nil {
+			return nil, err
+		}
+	}
+	return r.memoryTaskRepository.SetRank(ctx, id, rank)
+}
+
+// failingUpdateRepository は fail が立っている間、Update を失敗させます
+type failingUpdateRepository struct {
+	*memoryTaskRepository
+	fail bool
+}
+
+func (r *failingUpdateRepository) Update(ctx context.Context, id string, task *model.Task) (*model.Task, error) {
+	if r.fail {
+		return nil, errors.New("update failed")
+	}
+	return r.memoryTaskRepository.Update(ctx, id, task)
+}
+
+// boardColumn は列のタスクのタイトルをボード上の並び順で返します
+func boardColumn(t *testing.T, repo *memoryTaskRepository, status model.TaskStatus) string {
+	t.Helper()
+	tasks, _, err := repo.FindByUserID(context.Background(), "user1", &model.TaskFilter{
+		WorkflowStatuses: []string{string(status)},
+		OrderBy:          model.TaskSortByRank,
+	}, 0, nil)
+	require.NoError(t, err)
+	titles := make([]string, len(tasks))
+	for i, task := range tasks {
+		titles[i] = task.Title
+	}
+	return strings.Join(titles, ",")
+}
+
+func createBoardTasks(t *testing.T, svc TaskService, titles ...string) map[string]string {
+	t.Helper()
+	ids := make(map[string]string, len(titles))
+	for _, title := range titles {
+		task, err := svc.CreateTask(context.Background(), &model.Task{
+			UserID:  "user1",
+			Title:   title,
+			Status:  model.TaskStatusPending,
+			DueDate: time.Now().Add(24 * time.Hour),
+		})
+		require.NoError(t, err)
+		ids[title] = task.ID.Hex()
+	}
+	return ids
+}
+
+func TestTaskService_MoveTaskOnBoard(t *testing.T) {
+	ctx := context.Background()
+	pending, active := model.TaskStatusPending, model.TaskStatusActive
+
+	t.Run("reorder_within_column", func(t *testing.T) {
+		svc, repo := newBatchTestService()
+		ids := createBoardTasks(t, svc, "a", "b", "c", "d")
+		assert.Equal(t, "a,b,c,d", boardColumn(t, repo, pending))
+
+		_, err := svc.MoveTaskOnBoard(ctx, ids["d"], "user1", model.BoardMove{AfterID: ids["a"]})
+		require.NoError(t, err)
+		assert.Equal(t, "d,a,b,c", boardColumn(t, repo, pending))
+
+		_, err = svc.MoveTaskOnBoard(ctx, ids["d"], "user1", model.BoardMove{BeforeID: ids["b"], AfterID: ids["c"]})
+		require.NoError(t, err)
+		assert.Equal(t, "a,b,d,c", boardColumn(t, repo, pending))
+
+		_, err = svc.MoveTaskOnBoard(ctx, ids["a"], "user1", model.BoardMove{})
+		require.NoError(t, err)
+		assert.Equal(t, "b,d,c,a", boardColumn(t, repo, pending))
+
+		// 作成したタスクは移動したタスクより後ろに並ぶ
+		createBoardTasks(t, svc, "e")
+		assert.Equal(t, "b,d,c,a,e", boardColumn(t, repo, pending))
+	})
+
+	t.Run("move_to_another_column", func(t *testing.T) {
+		svc, repo := newBatchTestService()
+		ids := createBoardTasks(t, svc, "a", "b", "c")
+		_, err := svc.MoveTaskOnBoard(ctx, ids["a"], "user1", model.BoardMove{WorkflowStatus: string(active)})
+		require.NoError(t, err)
+
+		moved, err := svc.MoveTaskOnBoard(ctx, ids["c"], "user1", model.BoardMove{WorkflowStatus: string(active), AfterID: ids["a"]})
+		require.NoError(t, err)
+		assert.Equal(t, active, moved.Status)
+		assert.Equal(t, "b", boardColumn(t, repo, pending))
+		assert.Equal(t, "c,a", boardColumn(t, repo, active))
+	})
+
+	t.Run("invalid_neighbour", func(t *testing.T) {
+		svc, _ := newBatchTestService()
+		ids := createBoardTasks(t, svc, "a", "b", "c")
+
+		_, err := svc.MoveTaskOnBoard(ctx, ids["a"], "user1", model.BoardMove{WorkflowStatus: string(active), BeforeID: ids["b"]})
+		assert.True(t, apperrors.IsInvalidInput(err))
+		_, err = svc.MoveTaskOnBoard(ctx, ids["a"], "user1", model.BoardMove{BeforeID: ids["c"], AfterID: ids["b"]})
+		assert.True(t, apperrors.IsInvalidInput(err))
+		_, err = svc.MoveTaskOnBoard(ctx, ids["a"], "user1", model.BoardMove{BeforeID: ids["a"]})
+		assert.True(t, apperrors.IsInvalidInput(err))
+		_, err = svc.MoveTaskOnBoard(ctx, ids["a"], "user2", model.BoardMove{})
+		assert.True(t, apperrors.IsNotFound(err))
+
+		// 列の移動が失敗した場合はステータスも変えない
+		task, err := svc.GetTask(ctx, ids["a"])
+		require.NoError(t, err)
+		assert.Equal(t, pending, task.Status)
+	})
+
+	t.Run("retry_on_conflict", func(t *testing.T) {
+		_, memory := newBatchTestService()
+		repo := &conflictingRankRepository{memoryTaskRepository: memory, conflicts: 1}
+		svc := NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, &memoryTransactor{repo: memory}, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+		ids := createBoardTasks(t, svc, "a", "b", "c", "d")
+		repo.other = ids["d"]
+
+		_, err := svc.MoveTaskOnBoard(ctx, ids["c"], "user1", model.BoardMove{BeforeID: ids["a"]})
+		require.NoError(t, err)
+		assert.Equal(t, "a,c,d,b", boardColumn(t, memory, pending))
+
+		ranks := make(map[string]bool)
+		for _, task := range memory.tasks {
+			assert.False(t, ranks[task.Rank], "ランクが重複しています: %s", task.Rank)
+			ranks[task.Rank] = true
+		}
+	})
+
+	t.Run("rank_failure_keeps_column", func(t *testing.T) {
+		memory := &memoryTaskRepository{}
+		repo := &conflictingRankRepository{memoryTaskRepository: memory}
+		svc := NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, &memoryTransactor{repo: memory}, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+		ids := createBoardTasks(t, svc, "a", "b", "c")
+		_, err := svc.MoveTaskOnBoard(ctx, ids["a"], "user1", model.BoardMove{WorkflowStatus: string(active)})
+		require.NoError(t, err)
+
+		// 毎回他のリクエストにランクを取られ、やり直しても書き込めない
+		repo.other = ids["b"]
+		repo.conflicts = maxRankAttempts
+		_, err = svc.MoveTaskOnBoard(ctx, ids["c"], "user1", model.BoardMove{WorkflowStatus: string(active), BeforeID: ids["a"]})
+		assert.ErrorIs(t, err, repository.ErrRankConflict)
+
+		task := memory.find(ids["c"])
+		assert.Equal(t, pending, task.Status)
+		assert.Equal(t, string(pending), task.WorkflowStatus)
+		assert.Equal(t, "a", boardColumn(t, memory, active))
+	})
+
+	t.Run("transition_failure_reverts_rank", func(t *testing.T) {
+		memory := &memoryTaskRepository{}
+		repo := &failingUpdateRepository{memoryTaskRepository: memory}
+		svc := NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, &memoryTransactor{repo: memory}, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+		ids := createBoardTasks(t, svc, "a", "b", "c")
+		_, err := svc.MoveTaskOnBoard(ctx, ids["a"], "user1", model.BoardMove{WorkflowStatus: string(active)})
+		require.NoError(t, err)
+		rank := memory.find(ids["c"]).Rank
+
+		repo.fail = true
+		_, err = svc.MoveTaskOnBoard(ctx, ids["c"], "user1", model.BoardMove{WorkflowStatus: string(active), AfterID: ids["a"]})
+		assert.True(t, apperrors.IsInternal(err))
+
+		task := memory.find(ids["c"])
+		assert.Equal(t, pending, task.Status)
+		assert.Equal(t, rank, task.Rank)
+		assert.Equal(t, "b,c", boardColumn(t, memory, pending))
+	})
+
+	t.Run("rebalance_long_ranks", func(t *testing.T) {
+		svc, repo := newBatchTestService()
+		ids := createBoardTasks(t, svc, "a", "b", "c")
+		// a と c の間に置くランクは MaxRankLength より長くなる
+		long := "k" + strings.Repeat("0", model.MaxRankLength-1)
+		repo.find(ids["a"]).Rank = long + "1"
+		repo.find(ids["c"]).Rank = long + "2"
+		assert.Equal(t, "a,c,b", boardColumn(t, repo, pending))
+
+		_, err := svc.MoveTaskOnBoard(ctx, ids["b"], "user1", model.BoardMove{BeforeID: ids["a"]})
+		require.NoError(t, err)
+		assert.Equal(t, "a,b,c", boardColumn(t, repo, pending))
+		for _, task := range repo.tasks {
+			assert.LessOrEqual(t, len(task.Rank), 3)
+		}
+	})
+}
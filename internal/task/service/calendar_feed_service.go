@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/exporter"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+// calendarFeedTokenBytes はフィードのトークンの乱数のバイト数です
+const calendarFeedTokenBytes = 32
+
+type CalendarFeedService interface {
+	// GetCalendarFeed はユーザーのフィードを返します。トークンはハッシュしか保存していないため返せません
+	GetCalendarFeed(ctx context.Context, userID string) (*model.CalendarFeed, error)
+	// RotateCalendarFeedToken は新しいトークンを発行して返します。フィードがない場合は作成し、ある場合はそれまでのトークンを使えなくします
+	RotateCalendarFeedToken(ctx context.Context, userID string) (*model.CalendarFeed, string, error)
+	// RevokeCalendarFeed はフィードを削除し、トークンを使えなくします
+	RevokeCalendarFeed(ctx context.Context, userID string) error
+	// WriteCalendarFeed は token のフィードの持ち主の期限のあるタスクを期限の順に iCalendar で w に書き出します。
+	// JWT を使えないカレンダーアプリから取得するため、token だけで認証します
+	WriteCalendarFeed(ctx context.Context, token string, opts exporter.ICalendarOptions, w io.Writer) error
+}
+
+type calendarFeedService struct {
+	feedRepo repository.CalendarFeedRepository
+	taskRepo repository.TaskRepository
+}
+
+func NewCalendarFeedService(feedRepo repository.CalendarFeedRepository, taskRepo repository.TaskRepository) CalendarFeedService {
+	return &calendarFeedService{
+		feedRepo: feedRepo,
+		taskRepo: taskRepo,
+	}
+}
+
+func (s *calendarFeedService) GetCalendarFeed(ctx context.Context, userID string) (*model.CalendarFeed, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	return s.feedRepo.FindByUserID(ctx, userID)
+}
+
+func (s *calendarFeedService) RotateCalendarFeedToken(ctx context.Context, userID string) (*model.CalendarFeed, string, error) {
+	if userID == "" {
+		return nil, "", apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	b := make([]byte, calendarFeedTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", apperrors.NewInternalError("カレンダーフィードのトークンの生成に失敗しました", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	feed, err := s.feedRepo.Rotate(ctx, userID, model.HashCalendarFeedToken(token))
+	if err != nil {
+		return nil, "", err
+	}
+	return feed, token, nil
+}
+
+func (s *calendarFeedService) RevokeCalendarFeed(ctx context.Context, userID string) error {
+	if userID == "" {
+		return apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	return s.feedRepo.Delete(ctx, userID)
+}
+
+func (s *calendarFeedService) WriteCalendarFeed(ctx context.Context, token string, opts exporter.ICalendarOptions, w io.Writer) error {
+	if token == "" {
+		return repository.ErrCalendarFeedNotFound
+	}
+	feed, err := s.feedRepo.FindByTokenHash(ctx, model.HashCalendarFeedToken(token))
+	if err != nil {
+		return err
+	}
+
+	encoder := exporter.NewICalendarEncoder(w, opts)
+	filter := &model.TaskFilter{OrderBy: model.TaskSortByDueDate}
+	err = s.taskRepo.ForEachByUserID(ctx, feed.UserID, filter, func(task *model.Task) error {
+		if task.DueDate.IsZero() {
+			return nil
+		}
+		return encoder.Encode(task)
+	})
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/exporter"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryCalendarFeedRepository はテスト用のインメモリ実装です
+type memoryCalendarFeedRepository struct {
+	feeds map[string]*model.CalendarFeed
+}
+
+func (r *memoryCalendarFeedRepository) Rotate(_ context.Context, userID string, tokenHash string) (*model.CalendarFeed, error) {
+	feed, ok := r.feeds[userID]
+	if !ok {
+		feed = &model.CalendarFeed{ID: primitive.NewObjectID(), UserID: userID, CreatedAt: time.Now()}
+		r.feeds[userID] = feed
+	}
+	feed.TokenHash = tokenHash
+	feed.RotatedAt = time.Now()
+	copied := *feed
+	return &copied, nil
+}
+
+func (r *memoryCalendarFeedRepository) FindByUserID(_ context.Context, userID string) (*model.CalendarFeed, error) {
+	if feed, ok := r.feeds[userID]; ok {
+		copied := *feed
+		return &copied, nil
+	}
+	return nil, repository.ErrCalendarFeedNotFound
+}
+
+func (r *memoryCalendarFeedRepository) FindByTokenHash(_ context.Context, tokenHash string) (*model.CalendarFeed, error) {
+	for _, feed := range r.feeds {
+		if feed.TokenHash == tokenHash {
+			copied := *feed
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrCalendarFeedNotFound
+}
+
+func (r *memoryCalendarFeedRepository) Delete(_ context.Context, userID string) error {
+	if _, ok := r.feeds[userID]; !ok {
+		return repository.ErrCalendarFeedNotFound
+	}
+	delete(r.feeds, userID)
+	return nil
+}
+
+func newCalendarFeedTestService() (CalendarFeedService, *memoryTaskRepository) {
+	tasks := &memoryTaskRepository{}
+	return NewCalendarFeedService(&memoryCalendarFeedRepository{feeds: make(map[string]*model.CalendarFeed)}, tasks), tasks
+}
+
+func TestCalendarFeedService_RotateAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newCalendarFeedTestService()
+
+	_, err := svc.GetCalendarFeed(ctx, "user1")
+	assert.True(t, apperrors.IsNotFound(err))
+
+	feed, first, err := svc.RotateCalendarFeedToken(ctx, "user1")
+	require.NoError(t, err)
+	assert.Len(t, first, 43)
+	assert.Equal(t, model.HashCalendarFeedToken(first), feed.TokenHash)
+	assert.NotContains(t, feed.TokenHash, first)
+
+	_, second, err := svc.RotateCalendarFeedToken(ctx, "user1")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	// 以前のトークンは使えない
+	err = svc.WriteCalendarFeed(ctx, first, exporter.ICalendarOptions{}, &bytes.Buffer{})
+	assert.True(t, apperrors.IsNotFound(err))
+	require.NoError(t, svc.WriteCalendarFeed(ctx, second, exporter.ICalendarOptions{}, &bytes.Buffer{}))
+
+	require.NoError(t, svc.RevokeCalendarFeed(ctx, "user1"))
+	err = svc.WriteCalendarFeed(ctx, second, exporter.ICalendarOptions{}, &bytes.Buffer{})
+	assert.True(t, apperrors.IsNotFound(err))
+	assert.True(t, apperrors.IsNotFound(svc.RevokeCalendarFeed(ctx, "user1")))
+}
+
+func TestCalendarFeedService_WriteCalendarFeed(t *testing.T) {
+	ctx := context.Background()
+	svc, tasks := newCalendarFeedTestService()
+	due := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tasks.tasks = []*model.Task{
+		{ID: primitive.NewObjectID(), UserID: "user1", Title: "later", Status: model.TaskStatusPending, DueDate: due.Add(48 * time.Hour)},
+		{ID: primitive.NewObjectID(), UserID: "user1", Title: "no due date", Status: model.TaskStatusPending},
+		{ID: primitive.NewObjectID(), UserID: "user1", Title: "sooner", Status: model.TaskStatusComplete, DueDate: due},
+		{ID: primitive.NewObjectID(), UserID: "user2", Title: "other user", Status: model.TaskStatusPending, DueDate: due},
+	}
+	_, token, err := svc.RotateCalendarFeedToken(ctx, "user1")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.WriteCalendarFeed(ctx, token, exporter.ICalendarOptions{Component: exporter.ComponentTodo}, &buf))
+	out := buf.String()
+
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VTODO"))
+	assert.NotContains(t, out, "no due date")
+	assert.NotContains(t, out, "other user")
+	assert.Less(t, strings.Index(out, "SUMMARY:sooner"), strings.Index(out, "SUMMARY:later"))
+	assert.Contains(t, out, "STATUS:COMPLETED")
+
+	err = svc.WriteCalendarFeed(ctx, "", exporter.ICalendarOptions{}, &buf)
+	assert.True(t, apperrors.IsNotFound(err))
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/pkg/pagination"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+// CommentService はタスクのコメントを扱います。コメントの閲覧と投稿はタスクの所有者に限ります
+type CommentService interface {
+	// AddComment は authorID のユーザーとしてコメントを投稿し、メンションされたユーザーへの通知を登録します
+	AddComment(ctx context.Context, taskID string, authorID string, body string) (*model.Comment, error)
+	ListComments(ctx context.Context, taskID string, userID string, pageSize int32, pageToken string) (*model.CommentPage, error)
+	// EditComment はコメントの本文を編集します。編集できるのは投稿者だけです
+	EditComment(ctx context.Context, commentID string, userID string, body string) (*model.Comment, error)
+	DeleteComment(ctx context.Context, commentID string, userID string) error
+}
+
+type commentService struct {
+	commentRepo repository.CommentRepository
+	taskRepo    repository.TaskRepository
+	mentionRepo repository.MentionRepository
+	users       repository.UserDirectory
+	pageTokens  *pagination.Codec
+}
+
+func NewCommentService(commentRepo repository.CommentRepository, taskRepo repository.TaskRepository, mentionRepo repository.MentionRepository, users repository.UserDirectory, pageTokens *pagination.Codec) CommentService {
+	return &commentService{
+		commentRepo: commentRepo,
+		taskRepo:    taskRepo,
+		mentionRepo: mentionRepo,
+		users:       users,
+		pageTokens:  pageTokens,
+	}
+}
+
+func (s *commentService) AddComment(ctx context.Context, taskID string, authorID string, body string) (*model.Comment, error) {
+	task, err := s.authorizeTask(ctx, taskID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := model.ValidateCommentBody(body); err != nil {
+		return nil, apperrors.NewInvalidInputError("コメントが不正です", err)
+	}
+	mentions, err := s.resolveMentions(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepo.Create(ctx, &model.Comment{
+		TaskID:   task.ID,
+		AuthorID: authorID,
+		Body:     body,
+		Mentions: mentions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.notifyMentions(ctx, task, comment, nil); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) ListComments(ctx context.Context, taskID string, userID string, pageSize int32, pageToken string) (*model.CommentPage, error) {
+	task, err := s.authorizeTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor model.CommentCursor
+	if pageToken != "" {
+		if err := s.pageTokens.Decode(pageToken, &cursor); err != nil {
+			return nil, apperrors.NewInvalidInputError("無効なページトークンです", err)
+		}
+		if cursor.TaskID != task.ID {
+			return nil, apperrors.NewInvalidInputError("ページトークンがタスクと一致しません", nil)
+		}
+	}
+
+	// 次のページの有無を判定するため1件多く取得する
+	limit := pagination.PageSize(pageSize)
+	comments, err := s.commentRepo.FindByTask(ctx, task.ID, cursor.LastID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.CommentPage{Comments: comments}
+	if len(comments) > int(limit) {
+		page.Comments = comments[:limit]
+		token, err := s.pageTokens.Encode(model.CommentCursor{TaskID: task.ID, LastID: page.Comments[limit-1].ID})
+		if err != nil {
+			return nil, apperrors.NewInternalError("ページトークンの生成に失敗しました", err)
+		}
+		page.NextPageToken = token
+	}
+	return page, nil
+}
+
+func (s *commentService) EditComment(ctx context.Context, commentID string, userID string, body string) (*model.Comment, error) {
+	comment, task, err := s.authorizeComment(ctx, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, apperrors.NewPermissionDeniedError("コメントを編集できるのは投稿者だけです", nil)
+	}
+	if err := model.ValidateCommentBody(body); err != nil {
+		return nil, apperrors.NewInvalidInputError("コメントが不正です", err)
+	}
+	if !comment.Edit(body, time.Now()) {
+		return comment, nil
+	}
+
+	previous := comment.Mentions
+	comment.Mentions, err = s.resolveMentions(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.commentRepo.Update(ctx, comment); err != nil {
+		return nil, err
+	}
+	// 編集で新たにメンションされたユーザーにだけ通知する
+	if err := s.notifyMentions(ctx, task, comment, previous); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) DeleteComment(ctx context.Context, commentID string, userID string) error {
+	comment, _, err := s.authorizeComment(ctx, commentID, userID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID {
+		return apperrors.NewPermissionDeniedError("コメントを削除できるのは投稿者だけです", nil)
+	}
+
+	if err := s.commentRepo.Delete(ctx, comment.ID); err != nil {
+		return err
+	}
+	return s.mentionRepo.DeleteByComment(ctx, comment.ID)
+}
+
+// authorizeTask はタスクを取得し、userID のユーザーがタスクのコメントを扱えるかを確認します
+func (s *commentService) authorizeTask(ctx context.Context, taskID string, userID string) (*model.Task, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.UserID != userID {
+		return nil, apperrors.NewPermissionDeniedError("このタスクのコメントを操作する権限がありません", nil)
+	}
+	return task, nil
+}
+
+func (s *commentService) authorizeComment(ctx context.Context, commentID string, userID string) (*model.Comment, *model.Task, error) {
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	task, err := s.authorizeTask(ctx, comment.TaskID.Hex(), userID)
+	if err != nil {
+		// タスクが削除されていればコメントも存在しないものとして扱う
+		if apperrors.IsNotFound(err) {
+			return nil, nil, repository.ErrCommentNotFound
+		}
+		return nil, nil, err
+	}
+	return comment, task, nil
+}
+
+// resolveMentions は本文のメンションを存在するユーザーのIDに変換します。見つからないメンションは無視します
+func (s *commentService) resolveMentions(ctx context.Context, body string) ([]string, error) {
+	handles := model.ParseMentions(body)
+	if len(handles) == 0 {
+		return nil, nil
+	}
+	if len(handles) > model.MaxMentionsPerComment {
+		return nil, apperrors.NewInvalidInputError(fmt.Sprintf("メンションは%d人までです", model.MaxMentionsPerComment), nil)
+	}
+
+	resolved, err := s.users.ResolveUsers(ctx, handles)
+	if err != nil {
+		return nil, err
+	}
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, handle := range handles {
+		userID, ok := resolved[handle]
+		if ok && !seen[userID] {
+			seen[userID] = true
+			mentions = append(mentions, userID)
+		}
+	}
+	return mentions, nil
+}
+
+// notifyMentions は already に含まれないメンションされたユーザーへの通知を登録します。投稿者自身には通知しません。
+// タスクを見られないユーザーへの通知にはタスクのタイトルを含めません
+func (s *commentService) notifyMentions(ctx context.Context, task *model.Task, comment *model.Comment, already []string) error {
+	skip := map[string]bool{comment.AuthorID: true}
+	for _, userID := range already {
+		skip[userID] = true
+	}
+
+	var jobs []*model.MentionJob
+	for _, userID := range comment.Mentions {
+		if skip[userID] {
+			continue
+		}
+		job := &model.MentionJob{
+			CommentID: comment.ID,
+			TaskID:    task.ID,
+			UserID:    userID,
+			AuthorID:  comment.AuthorID,
+			Excerpt:   comment.Excerpt(),
+		}
+		// タスクを見られないユーザーにはタスクのタイトルを知らせない
+		if canViewTask(task, userID) {
+			job.TaskTitle = task.Title
+		}
+		jobs = append(jobs, job)
+	}
+	return s.mentionRepo.Create(ctx, jobs)
+}
+
+// canViewTask は userID のユーザーがタスクを見られるかを返します。タスクを見られるのは所有者だけです
+func canViewTask(task *model.Task, userID string) bool {
+	return task.UserID == userID
+}
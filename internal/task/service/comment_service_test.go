@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryCommentRepository はテスト用のインメモリ実装です
+type memoryCommentRepository struct {
+	comments []*model.Comment
+}
+
+func (r *memoryCommentRepository) Create(_ context.Context, comment *model.Comment) (*model.Comment, error) {
+	comment.ID = primitive.NewObjectID()
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+	stored := *comment
+	r.comments = append(r.comments, &stored)
+	return comment, nil
+}
+
+func (r *memoryCommentRepository) FindByID(_ context.Context, id string) (*model.Comment, error) {
+	for _, comment := range r.comments {
+		if comment.ID.Hex() == id {
+			copied := *comment
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrCommentNotFound
+}
+
+func (r *memoryCommentRepository) FindByTask(_ context.Context, taskID primitive.ObjectID, after primitive.ObjectID, limit int32) ([]*model.Comment, error) {
+	var result []*model.Comment
+	for _, comment := range r.comments {
+		if comment.TaskID == taskID && (after.IsZero() || comment.ID.Hex() > after.Hex()) && len(result) < int(limit) {
+			result = append(result, comment)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryCommentRepository) Update(_ context.Context, comment *model.Comment) error {
+	for i, stored := range r.comments {
+		if stored.ID == comment.ID {
+			copied := *comment
+			r.comments[i] = &copied
+			return nil
+		}
+	}
+	return repository.ErrCommentNotFound
+}
+
+func (r *memoryCommentRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	for i, comment := range r.comments {
+		if comment.ID == id {
+			r.comments = append(r.comments[:i], r.comments[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrCommentNotFound
+}
+
+// memoryMentionRepository はテスト用のインメモリ実装です。送信はテストしないため Claim は常に空を返します
+type memoryMentionRepository struct {
+	jobs []*model.MentionJob
+}
+
+func (r *memoryMentionRepository) Create(_ context.Context, jobs []*model.MentionJob) error {
+	r.jobs = append(r.jobs, jobs...)
+	return nil
+}
+
+func (r *memoryMentionRepository) DeleteByComment(_ context.Context, commentID primitive.ObjectID) error {
+	var kept []*model.MentionJob
+	for _, job := range r.jobs {
+		if job.CommentID != commentID {
+			kept = append(kept, job)
+		}
+	}
+	r.jobs = kept
+	return nil
+}
+
+func (r *memoryMentionRepository) Claim(context.Context, string, time.Time, time.Duration) (*model.MentionJob, error) {
+	return nil, repository.ErrNoMentionDue
+}
+
+func (r *memoryMentionRepository) Retry(context.Context, primitive.ObjectID, string, time.Time, string) error {
+	return nil
+}
+
+func (r *memoryMentionRepository) Finish(context.Context, primitive.ObjectID, string, model.ReminderState, string) error {
+	return nil
+}
+
+func (r *memoryMentionRepository) recipients() []string {
+	var userIDs []string
+	for _, job := range r.jobs {
+		userIDs = append(userIDs, job.UserID)
+	}
+	return userIDs
+}
+
+// staticUserDirectory はメールアドレスからユーザーIDへの対応表です
+type staticUserDirectory map[string]string
+
+func (d staticUserDirectory) TimeZone(context.Context, string) (string, error) {
+	return "", nil
+}
+
+func (d staticUserDirectory) ResolveUsers(_ context.Context, handles []string) (map[string]string, error) {
+	resolved := make(map[string]string)
+	for _, handle := range handles {
+		for email, userID := range d {
+			if handle == email || handle == userID {
+				resolved[handle] = userID
+			}
+		}
+	}
+	return resolved, nil
+}
+
+var (
+	aliceID = primitive.NewObjectID().Hex()
+	bobID   = primitive.NewObjectID().Hex()
+)
+
+func newCommentTestService(t *testing.T) (CommentService, *model.Task, *memoryMentionRepository) {
+	t.Helper()
+	tasks := &memoryTaskRepository{}
+	task, err := tasks.Create(context.Background(), &model.Task{UserID: "owner", Title: "設計レビュー"})
+	require.NoError(t, err)
+	mentions := &memoryMentionRepository{}
+	users := staticUserDirectory{"alice@example.com": aliceID, "bob@example.com": bobID, "owner@example.com": "owner"}
+	return NewCommentService(&memoryCommentRepository{}, tasks, mentions, users, testCodec), task, mentions
+}
+
+func TestCommentService_AddComment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("mentions", func(t *testing.T) {
+		svc, task, mentions := newCommentTestService(t)
+		body := fmt.Sprintf("@alice@example.com と @%s に確認をお願いします。@owner@example.com @unknown@example.com\n"+
+			"`@bob@example.com` はコードなので対象外\n"+
+			"メール alice@example.com もメンションではありません", bobID)
+
+		comment, err := svc.AddComment(ctx, task.ID.Hex(), "owner", body)
+		require.NoError(t, err)
+		assert.Equal(t, "owner", comment.AuthorID)
+		assert.Equal(t, []string{aliceID, bobID, "owner"}, comment.Mentions)
+		assert.Equal(t, []string{aliceID, bobID}, mentions.recipients(), "投稿者自身には通知しない")
+		assert.Empty(t, mentions.jobs[0].TaskTitle, "タスクを見られないユーザーにはタイトルを知らせない")
+		assert.Equal(t, comment.ID, mentions.jobs[0].CommentID)
+	})
+
+	t.Run("code_block_is_ignored", func(t *testing.T) {
+		svc, task, mentions := newCommentTestService(t)
+		comment, err := svc.AddComment(ctx, task.ID.Hex(), "owner", "```\n@alice@example.com\n```")
+		require.NoError(t, err)
+		assert.Empty(t, comment.Mentions)
+		assert.Empty(t, mentions.jobs)
+	})
+
+	t.Run("invalid_body", func(t *testing.T) {
+		svc, task, _ := newCommentTestService(t)
+		for name, body := range map[string]string{
+			"empty":    "  \n",
+			"too_long": strings.Repeat("あ", model.MaxCommentBodyLength+1),
+		} {
+			_, err := svc.AddComment(ctx, task.ID.Hex(), "owner", body)
+			assert.True(t, apperrors.IsInvalidInput(err), name)
+		}
+	})
+
+	t.Run("too_many_mentions", func(t *testing.T) {
+		svc, task, _ := newCommentTestService(t)
+		var body strings.Builder
+		for i := 0; i <= model.MaxMentionsPerComment; i++ {
+			fmt.Fprintf(&body, "@user%d@example.com ", i)
+		}
+		_, err := svc.AddComment(ctx, task.ID.Hex(), "owner", body.String())
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("authorization", func(t *testing.T) {
+		svc, task, _ := newCommentTestService(t)
+		_, err := svc.AddComment(ctx, task.ID.Hex(), aliceID, "コメント")
+		assert.True(t, apperrors.IsPermissionDenied(err))
+
+		_, err = svc.AddComment(ctx, primitive.NewObjectID().Hex(), "owner", "コメント")
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestCommentService_ListComments(t *testing.T) {
+	ctx := context.Background()
+	svc, task, _ := newCommentTestService(t)
+	for i := 0; i < 3; i++ {
+		_, err := svc.AddComment(ctx, task.ID.Hex(), "owner", fmt.Sprintf("コメント%d", i))
+		require.NoError(t, err)
+	}
+
+	page, err := svc.ListComments(ctx, task.ID.Hex(), "owner", 2, "")
+	require.NoError(t, err)
+	require.Len(t, page.Comments, 2)
+	assert.Equal(t, "コメント0", page.Comments[0].Body)
+	require.NotEmpty(t, page.NextPageToken)
+
+	page, err = svc.ListComments(ctx, task.ID.Hex(), "owner", 2, page.NextPageToken)
+	require.NoError(t, err)
+	require.Len(t, page.Comments, 1)
+	assert.Equal(t, "コメント2", page.Comments[0].Body)
+	assert.Empty(t, page.NextPageToken)
+
+	_, err = svc.ListComments(ctx, task.ID.Hex(), aliceID, 0, "")
+	assert.True(t, apperrors.IsPermissionDenied(err))
+
+	_, err = svc.ListComments(ctx, task.ID.Hex(), "owner", 0, "invalid")
+	assert.True(t, apperrors.IsInvalidInput(err))
+}
+
+func TestCommentService_EditComment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps_history_and_notifies_new_mentions", func(t *testing.T) {
+		svc, task, mentions := newCommentTestService(t)
+		comment, err := svc.AddComment(ctx, task.ID.Hex(), "owner", "@alice@example.com 確認お願いします")
+		require.NoError(t, err)
+
+		edited, err := svc.EditComment(ctx, comment.ID.Hex(), "owner", "@alice@example.com @bob@example.com 確認お願いします")
+		require.NoError(t, err)
+		assert.Equal(t, []string{aliceID, bobID}, edited.Mentions)
+		require.Len(t, edited.Revisions, 1)
+		assert.Equal(t, "@alice@example.com 確認お願いします", edited.Revisions[0].Body)
+		assert.False(t, edited.EditedAt.IsZero())
+		assert.Equal(t, []string{aliceID, bobID}, mentions.recipients(), "既にメンションされていたユーザーには再通知しない")
+	})
+
+	t.Run("unchanged_body_is_not_a_revision", func(t *testing.T) {
+		svc, task, _ := newCommentTestService(t)
+		comment, err := svc.AddComment(ctx, task.ID.Hex(), "owner", "コメント")
+		require.NoError(t, err)
+
+		edited, err := svc.EditComment(ctx, comment.ID.Hex(), "owner", "コメント")
+		require.NoError(t, err)
+		assert.Empty(t, edited.Revisions)
+		assert.True(t, edited.EditedAt.IsZero())
+	})
+
+	t.Run("history_is_capped", func(t *testing.T) {
+		svc, task, _ := newCommentTestService(t)
+		comment, err := svc.AddComment(ctx, task.ID.Hex(), "owner", "版0")
+		require.NoError(t, err)
+		for i := 1; i <= model.MaxCommentRevisions+2; i++ {
+			comment, err = svc.EditComment(ctx, comment.ID.Hex(), "owner", fmt.Sprintf("版%d", i))
+			require.NoError(t, err)
+		}
+		require.Len(t, comment.Revisions, model.MaxCommentRevisions)
+		assert.Equal(t, "版2", comment.Revisions[0].Body)
+	})
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+	ctx := context.Background()
+	svc, task, mentions := newCommentTestService(t)
+	comment, err := svc.AddComment(ctx, task.ID.Hex(), "owner", "@alice@example.com 確認お願いします")
+	require.NoError(t, err)
+	require.Len(t, mentions.jobs, 1)
+
+	assert.True(t, apperrors.IsPermissionDenied(svc.DeleteComment(ctx, comment.ID.Hex(), aliceID)))
+
+	require.NoError(t, svc.DeleteComment(ctx, comment.ID.Hex(), "owner"))
+	assert.Empty(t, mentions.jobs, "未送信のメンションも取り消す")
+	assert.True(t, apperrors.IsNotFound(svc.DeleteComment(ctx, comment.ID.Hex(), "owner")))
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+type CustomFieldService interface {
+	CreateCustomField(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error)
+	ListCustomFields(ctx context.Context, ownerID string) ([]*model.CustomFieldDefinition, error)
+	// UpdateCustomField は名前・選択肢・必須かどうかを更新します。フィールドの種類は変更できません
+	UpdateCustomField(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error)
+	DeleteCustomField(ctx context.Context, ownerID string, id string) error
+}
+
+type customFieldService struct {
+	fieldRepo repository.CustomFieldRepository
+}
+
+func NewCustomFieldService(fieldRepo repository.CustomFieldRepository) CustomFieldService {
+	return &customFieldService{
+		fieldRepo: fieldRepo,
+	}
+}
+
+func (s *customFieldService) CreateCustomField(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error) {
+	if err := field.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("カスタムフィールドが不正です", err)
+	}
+
+	created, err := s.fieldRepo.Create(ctx, field)
+	if err != nil {
+		if apperrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("カスタムフィールドの作成に失敗しました", err)
+	}
+	return created, nil
+}
+
+func (s *customFieldService) ListCustomFields(ctx context.Context, ownerID string) ([]*model.CustomFieldDefinition, error) {
+	fields, err := s.fieldRepo.FindByOwnerID(ctx, ownerID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("カスタムフィールド一覧の取得に失敗しました", err)
+	}
+	return fields, nil
+}
+
+func (s *customFieldService) UpdateCustomField(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error) {
+	existing, err := s.fieldRepo.FindByID(ctx, field.OwnerID, field.ID.Hex())
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("カスタムフィールドが見つかりません", err)
+		}
+		return nil, apperrors.NewInternalError("カスタムフィールドの取得に失敗しました", err)
+	}
+
+	existing.Name = field.Name
+	existing.Options = field.Options
+	existing.Required = field.Required
+	if err := existing.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("カスタムフィールドが不正です", err)
+	}
+
+	updated, err := s.fieldRepo.Update(ctx, existing)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("カスタムフィールドが見つかりません", err)
+		}
+		if apperrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("カスタムフィールドの更新に失敗しました", err)
+	}
+	return updated, nil
+}
+
+func (s *customFieldService) DeleteCustomField(ctx context.Context, ownerID string, id string) error {
+	if err := s.fieldRepo.Delete(ctx, ownerID, id); err != nil {
+		if apperrors.IsNotFound(err) {
+			return apperrors.NewNotFoundError("カスタムフィールドが見つかりません", err)
+		}
+		if apperrors.IsInvalidInput(err) {
+			return err
+		}
+		return apperrors.NewInternalError("カスタムフィールドの削除に失敗しました", err)
+	}
+	return nil
+}
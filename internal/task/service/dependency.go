@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/pkg/pagination"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *taskService) AddDependency(ctx context.Context, taskID string, userID string, blockerID string) (*model.TaskDependency, error) {
+	task, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	blocker, err := s.ownedTask(ctx, blockerID, userID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("ブロッカーのタスクが見つかりません", err)
+		}
+		return nil, err
+	}
+
+	deps, err := s.depRepo.FindByUserID(ctx, task.UserID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("依存関係の取得に失敗しました", err)
+	}
+	if model.WouldCreateCycle(deps, task.ID, blocker.ID) {
+		return nil, apperrors.NewInvalidInputError("依存関係が循環します", nil)
+	}
+
+	created, err := s.depRepo.Create(ctx, &model.TaskDependency{
+		UserID:    task.UserID,
+		TaskID:    task.ID,
+		BlockerID: blocker.ID,
+	})
+	if err != nil {
+		if apperrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("依存関係の作成に失敗しました", err)
+	}
+	return created, nil
+}
+
+func (s *taskService) RemoveDependency(ctx context.Context, taskID string, userID string, blockerID string) error {
+	task, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	blockerObjectID, err := primitive.ObjectIDFromHex(blockerID)
+	if err != nil {
+		return apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+
+	if err := s.depRepo.Delete(ctx, task.ID, blockerObjectID); err != nil {
+		if apperrors.IsNotFound(err) {
+			return apperrors.NewNotFoundError("依存関係が見つかりません", err)
+		}
+		return apperrors.NewInternalError("依存関係の削除に失敗しました", err)
+	}
+	return nil
+}
+
+func (s *taskService) ListDependencies(ctx context.Context, taskID string, userID string) (*model.TaskDependencies, error) {
+	task, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	blockers, err := s.depRepo.FindBlockers(ctx, task.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("依存関係の取得に失敗しました", err)
+	}
+	blocked, err := s.depRepo.FindBlocked(ctx, task.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("依存関係の取得に失敗しました", err)
+	}
+
+	result := &model.TaskDependencies{}
+	for _, dep := range blockers {
+		if result.BlockedBy, err = s.appendExistingTask(ctx, result.BlockedBy, dep.BlockerID); err != nil {
+			return nil, err
+		}
+	}
+	for _, dep := range blocked {
+		if result.Blocks, err = s.appendExistingTask(ctx, result.Blocks, dep.TaskID); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *taskService) ListNextTasks(ctx context.Context, userID string, pageSize int32) ([]*model.NextTask, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	limit := int(pagination.PageSize(pageSize))
+
+	deps, err := s.depRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("依存関係の取得に失敗しました", err)
+	}
+	// 依存関係のあるタスクは着手順がブロッカーに左右されるため、ID で取得する
+	linked := make(map[primitive.ObjectID]bool)
+	var linkedIDs []primitive.ObjectID
+	for _, dep := range deps {
+		for _, id := range []primitive.ObjectID{dep.TaskID, dep.BlockerID} {
+			if !linked[id] {
+				linked[id] = true
+				linkedIDs = append(linkedIDs, id)
+			}
+		}
+	}
+	var tasks []*model.Task
+	if len(linkedIDs) > 0 {
+		if tasks, err = s.taskRepo.FindByIDs(ctx, linkedIDs); err != nil {
+			return nil, apperrors.NewInternalError("タスクの取得に失敗しました", err)
+		}
+	}
+
+	free, err := s.topFreeTasks(ctx, userID, linked, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := model.OrderByDependencies(append(tasks, free...), deps)
+	if len(ordered) > limit {
+		ordered = ordered[:limit]
+	}
+	return ordered, nil
+}
+
+// topFreeTasks は依存関係のない未完了のタスクを着手順の基準で上位 limit 件まで返します。
+// 依存関係のないタスクは基準だけで並ぶため、優先度の高い順にページ単位で読み、
+// 残りのタスクの優先度が上位 limit 件の最後のタスクより低くなった時点で読むのをやめます
+func (s *taskService) topFreeTasks(ctx context.Context, userID string, linked map[primitive.ObjectID]bool, limit int) ([]*model.Task, error) {
+	filter := &model.TaskFilter{
+		Statuses:   []model.TaskStatus{model.TaskStatusPending, model.TaskStatusActive},
+		OrderBy:    model.TaskSortByPriority,
+		Descending: true,
+	}
+	pageSize := int32(limit)
+
+	var free []*model.Task
+	var after *model.TaskCursor
+	for {
+		page, _, err := s.taskRepo.FindByUserID(ctx, userID, filter, pageSize, after)
+		if err != nil {
+			return nil, apperrors.NewInternalError("タスク一覧の取得に失敗しました", err)
+		}
+		for _, task := range page {
+			if !linked[task.ID] {
+				free = append(free, task)
+			}
+		}
+		free = model.TopByWorkOrder(free, limit)
+		if int32(len(page)) < pageSize {
+			return free, nil
+		}
+
+		last := page[len(page)-1]
+		if len(free) == limit && last.Priority < free[limit-1].Priority {
+			return free, nil
+		}
+		after = &model.TaskCursor{LastValue: last.SortValue(filter.SortField()), LastID: last.ID}
+	}
+}
+
+// ownedTask はタスクを取得し、userID のユーザーのタスクかを確認します
+func (s *taskService) ownedTask(ctx context.Context, id string, userID string) (*model.Task, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	task, err := s.findTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.UserID != userID {
+		return nil, apperrors.NewPermissionDeniedError("このタスクを操作する権限がありません", nil)
+	}
+	return task, nil
+}
+
+// ensureUnblocked は未完了のブロッカーがあるタスクを進行中・完了へ変更できないようにします
+func (s *taskService) ensureUnblocked(ctx context.Context, task *model.Task) error {
+	deps, err := s.depRepo.FindBlockers(ctx, task.ID)
+	if err != nil {
+		return apperrors.NewInternalError("依存関係の取得に失敗しました", err)
+	}
+
+	var open int
+	for _, dep := range deps {
+		blocker, err := s.taskRepo.FindByID(ctx, dep.BlockerID.Hex())
+		if err != nil {
+			if apperrors.IsNotFound(err) {
+				continue
+			}
+			return apperrors.NewInternalError("タスクの取得に失敗しました", err)
+		}
+		if blocker.IsOpen() {
+			open++
+		}
+	}
+	if open > 0 {
+		return apperrors.NewFailedPreconditionError(fmt.Sprintf("未完了のブロッカーが%d件あるため開始・完了にできません", open), nil)
+	}
+	return nil
+}
+
+// appendExistingTask は ID のタスクを tasks に追加します。削除済みのタスクは無視します
+func (s *taskService) appendExistingTask(ctx context.Context, tasks []*model.Task, id primitive.ObjectID) ([]*model.Task, error) {
+	task, err := s.taskRepo.FindByID(ctx, id.Hex())
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return tasks, nil
+		}
+		return nil, apperrors.NewInternalError("タスクの取得に失敗しました", err)
+	}
+	return append(tasks, task), nil
+}
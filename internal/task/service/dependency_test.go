@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryDependencyRepository は依存関係をメモリ上で保持するテスト用のリポジトリです
+type memoryDependencyRepository struct {
+	deps []*model.TaskDependency
+}
+
+func (r *memoryDependencyRepository) Create(_ context.Context, dep *model.TaskDependency) (*model.TaskDependency, error) {
+	for _, d := range r.deps {
+		if d.TaskID == dep.TaskID && d.BlockerID == dep.BlockerID {
+			return nil, repository.ErrDependencyAlreadyExists
+		}
+	}
+	dep.ID = primitive.NewObjectID()
+	r.deps = append(r.deps, dep)
+	return dep, nil
+}
+
+func (r *memoryDependencyRepository) Delete(_ context.Context, taskID primitive.ObjectID, blockerID primitive.ObjectID) error {
+	for i, d := range r.deps {
+		if d.TaskID == taskID && d.BlockerID == blockerID {
+			r.deps = append(r.deps[:i], r.deps[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrDependencyNotFound
+}
+
+func (r *memoryDependencyRepository) filter(match func(*model.TaskDependency) bool) []*model.TaskDependency {
+	result := []*model.TaskDependency{}
+	for _, d := range r.deps {
+		if match(d) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func (r *memoryDependencyRepository) FindBlockers(_ context.Context, taskID primitive.ObjectID) ([]*model.TaskDependency, error) {
+	return r.filter(func(d *model.TaskDependency) bool { return d.TaskID == taskID }), nil
+}
+
+func (r *memoryDependencyRepository) FindBlocked(_ context.Context, blockerID primitive.ObjectID) ([]*model.TaskDependency, error) {
+	return r.filter(func(d *model.TaskDependency) bool { return d.BlockerID == blockerID }), nil
+}
+
+func (r *memoryDependencyRepository) FindByUserID(_ context.Context, userID string) ([]*model.TaskDependency, error) {
+	return r.filter(func(d *model.TaskDependency) bool { return d.UserID == userID }), nil
+}
+
+func (r *memoryDependencyRepository) DeleteByTask(_ context.Context, taskID primitive.ObjectID) error {
+	r.deps = r.filter(func(d *model.TaskDependency) bool { return d.TaskID != taskID && d.BlockerID != taskID })
+	return nil
+}
+
+func newDependencyTestService() (TaskService, *memoryTaskRepository, *memoryDependencyRepository) {
+	repo := &memoryTaskRepository{}
+	deps := &memoryDependencyRepository{}
+	svc := NewTaskService(repo, newNoFieldsRepository(), deps, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	return svc, repo, deps
+}
+
+func createTasks(t *testing.T, svc TaskService, titles ...string) []*model.Task {
+	t.Helper()
+	tasks := make([]*model.Task, len(titles))
+	for i, title := range titles {
+		task, err := svc.CreateTask(context.Background(), &model.Task{UserID: "user1", Title: title, Status: model.TaskStatusPending, DueDate: testDueDate})
+		require.NoError(t, err)
+		tasks[i] = task
+	}
+	return tasks
+}
+
+func TestTaskService_AddDependency(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cycle", func(t *testing.T) {
+		svc, _, _ := newDependencyTestService()
+		tasks := createTasks(t, svc, "a", "b", "c")
+
+		// a -> b -> c の順にブロックする
+		_, err := svc.AddDependency(ctx, tasks[1].ID.Hex(), "user1", tasks[0].ID.Hex())
+		require.NoError(t, err)
+		_, err = svc.AddDependency(ctx, tasks[2].ID.Hex(), "user1", tasks[1].ID.Hex())
+		require.NoError(t, err)
+
+		_, err = svc.AddDependency(ctx, tasks[0].ID.Hex(), "user1", tasks[2].ID.Hex())
+		assert.True(t, apperrors.IsInvalidInput(err))
+		_, err = svc.AddDependency(ctx, tasks[0].ID.Hex(), "user1", tasks[0].ID.Hex())
+		assert.True(t, apperrors.IsInvalidInput(err))
+
+		// 推移的に既に成り立つ依存関係を直接追加するのは循環ではない
+		_, err = svc.AddDependency(ctx, tasks[2].ID.Hex(), "user1", tasks[0].ID.Hex())
+		assert.NoError(t, err)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		svc, _, _ := newDependencyTestService()
+		tasks := createTasks(t, svc, "a", "b")
+
+		_, err := svc.AddDependency(ctx, tasks[1].ID.Hex(), "user1", tasks[0].ID.Hex())
+		require.NoError(t, err)
+		_, err = svc.AddDependency(ctx, tasks[1].ID.Hex(), "user1", tasks[0].ID.Hex())
+		assert.True(t, apperrors.IsAlreadyExists(err))
+	})
+
+	t.Run("blocker_of_other_user", func(t *testing.T) {
+		svc, _, _ := newDependencyTestService()
+		tasks := createTasks(t, svc, "a")
+		other, err := svc.CreateTask(ctx, &model.Task{UserID: "user2", Title: "other", DueDate: testDueDate})
+		require.NoError(t, err)
+
+		_, err = svc.AddDependency(ctx, tasks[0].ID.Hex(), "user1", other.ID.Hex())
+		assert.True(t, apperrors.IsPermissionDenied(err))
+		_, err = svc.AddDependency(ctx, other.ID.Hex(), "user1", tasks[0].ID.Hex())
+		assert.True(t, apperrors.IsPermissionDenied(err))
+		_, err = svc.AddDependency(ctx, tasks[0].ID.Hex(), "user1", primitive.NewObjectID().Hex())
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestTaskService_DependenciesOfOtherUser(t *testing.T) {
+	ctx := context.Background()
+	svc, _, deps := newDependencyTestService()
+	tasks := createTasks(t, svc, "a", "b")
+	_, err := svc.AddDependency(ctx, tasks[1].ID.Hex(), "user1", tasks[0].ID.Hex())
+	require.NoError(t, err)
+
+	_, err = svc.ListDependencies(ctx, tasks[1].ID.Hex(), "user2")
+	assert.True(t, apperrors.IsPermissionDenied(err))
+	err = svc.RemoveDependency(ctx, tasks[1].ID.Hex(), "user2", tasks[0].ID.Hex())
+	assert.True(t, apperrors.IsPermissionDenied(err))
+	assert.Len(t, deps.deps, 1)
+
+	err = svc.RemoveDependency(ctx, primitive.NewObjectID().Hex(), "user1", tasks[0].ID.Hex())
+	assert.True(t, apperrors.IsNotFound(err))
+}
+
+func TestTaskService_BlockedStatusChange(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newDependencyTestService()
+	tasks := createTasks(t, svc, "blocker", "blocked")
+	blocker, blocked := tasks[0], tasks[1]
+	_, err := svc.AddDependency(ctx, blocked.ID.Hex(), "user1", blocker.ID.Hex())
+	require.NoError(t, err)
+
+	setStatus := func(task *model.Task, status model.TaskStatus) error {
+		_, err := svc.UpdateTask(ctx, task.ID.Hex(), &model.Task{UserID: "user1", Title: task.Title, Status: status, DueDate: testDueDate})
+		return err
+	}
+
+	for _, status := range []model.TaskStatus{model.TaskStatusActive, model.TaskStatusComplete} {
+		err := setStatus(blocked, status)
+		assert.True(t, apperrors.IsFailedPrecondition(err), status)
+	}
+	assert.Equal(t, model.TaskStatusPending, repo.find(blocked.ID.Hex()).Status)
+	assert.NoError(t, setStatus(blocked, model.TaskStatusPending))
+
+	require.NoError(t, setStatus(blocker, model.TaskStatusComplete))
+	assert.NoError(t, setStatus(blocked, model.TaskStatusActive))
+}
+
+func TestTaskService_ListDependencies(t *testing.T) {
+	ctx := context.Background()
+	svc, _, deps := newDependencyTestService()
+	tasks := createTasks(t, svc, "a", "b", "c")
+	_, err := svc.AddDependency(ctx, tasks[1].ID.Hex(), "user1", tasks[0].ID.Hex())
+	require.NoError(t, err)
+	_, err = svc.AddDependency(ctx, tasks[2].ID.Hex(), "user1", tasks[1].ID.Hex())
+	require.NoError(t, err)
+
+	result, err := svc.ListDependencies(ctx, tasks[1].ID.Hex(), "user1")
+	require.NoError(t, err)
+	require.Len(t, result.BlockedBy, 1)
+	require.Len(t, result.Blocks, 1)
+	assert.Equal(t, tasks[0].ID, result.BlockedBy[0].ID)
+	assert.Equal(t, tasks[2].ID, result.Blocks[0].ID)
+
+	require.NoError(t, svc.DeleteTask(ctx, tasks[1].ID.Hex()))
+	assert.Empty(t, deps.deps)
+
+	err = svc.RemoveDependency(ctx, tasks[2].ID.Hex(), "user1", tasks[1].ID.Hex())
+	assert.True(t, apperrors.IsNotFound(err))
+}
+
+func TestTaskService_ListNextTasks(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newDependencyTestService()
+	tasks := createTasks(t, svc, "design", "build", "release", "docs", "done")
+	design, build, release, docs, done := tasks[0], tasks[1], tasks[2], tasks[3], tasks[4]
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.find(docs.ID.Hex()).Priority = model.TaskPriorityLow
+	repo.find(design.ID.Hex()).Priority = model.TaskPriorityHigh
+	repo.find(build.ID.Hex()).Priority = model.TaskPriorityUrgent
+	repo.find(release.ID.Hex()).Priority = model.TaskPriorityLow
+	repo.find(release.ID.Hex()).DueDate = base
+	repo.find(done.ID.Hex()).Status = model.TaskStatusComplete
+
+	for _, dep := range [][2]*model.Task{{build, design}, {release, build}, {release, done}} {
+		_, err := svc.AddDependency(ctx, dep[0].ID.Hex(), "user1", dep[1].ID.Hex())
+		require.NoError(t, err)
+	}
+
+	next, err := svc.ListNextTasks(ctx, "user1", 10)
+	require.NoError(t, err)
+
+	var order []string
+	for _, n := range next {
+		order = append(order, n.Task.Title)
+	}
+	// build は緊急だが design の完了待ちのため後になる。release と docs は同じ優先度のため期限のある release が先。
+	// 完了済みの done はブロッカーとして扱わない
+	assert.Equal(t, []string{"design", "build", "release", "docs"}, order)
+	assert.Empty(t, next[0].OpenBlockerIDs)
+	assert.Equal(t, []primitive.ObjectID{design.ID}, next[1].OpenBlockerIDs)
+	assert.Equal(t, []primitive.ObjectID{build.ID}, next[2].OpenBlockerIDs)
+
+	limited, err := svc.ListNextTasks(ctx, "user1", 2)
+	require.NoError(t, err)
+	assert.Len(t, limited, 2)
+}
+
+func TestTaskService_ListNextTasksReadsPages(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newDependencyTestService()
+	tasks := createTasks(t, svc, "low", "medium1", "medium2", "medium3", "high")
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.find(tasks[0].ID.Hex()).Priority = model.TaskPriorityLow
+	for i, task := range tasks[1:4] {
+		repo.find(task.ID.Hex()).Priority = model.TaskPriorityMedium
+		repo.find(task.ID.Hex()).DueDate = base.AddDate(0, 0, i)
+	}
+	repo.find(tasks[4].ID.Hex()).Priority = model.TaskPriorityHigh
+
+	// 同じ優先度のタスクは後のページにあっても期限の近い順に並ぶ
+	next, err := svc.ListNextTasks(ctx, "user1", 2)
+	require.NoError(t, err)
+	var order []string
+	for _, n := range next {
+		order = append(order, n.Task.Title)
+	}
+	assert.Equal(t, []string{"high", "medium1"}, order)
+
+	_, err = svc.ListNextTasks(ctx, "", 2)
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperrors.Unauthorized, appErr.Type)
+}
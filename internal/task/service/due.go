@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/recurrence"
+)
+
+// userLocation はユーザーのタイムゾーンを返します。ユーザーディレクトリがない場合と、設定していない場合は UTC です
+func (s *taskService) userLocation(ctx context.Context, userID string) (*time.Location, error) {
+	name, err := s.userTimeZone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := recurrence.LoadLocation(name)
+	if err != nil {
+		// ユーザーサービスで検証しているため、読み込めないのはこのサーバーが知らないタイムゾーンの場合だけ
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+func (s *taskService) userTimeZone(ctx context.Context, userID string) (string, error) {
+	if s.users == nil {
+		return "", nil
+	}
+	name, err := s.users.TimeZone(ctx, userID)
+	if err != nil {
+		return "", apperrors.NewInternalError("ユーザーのタイムゾーンの取得に失敗しました", err)
+	}
+	return name, nil
+}
+
+// prepareDue は期限を検証してそろえます。タイムゾーンを指定していない終日のタスクは、期限日をユーザーのタイムゾーンで区切ります
+func (s *taskService) prepareDue(ctx context.Context, task *model.Task) error {
+	var loc *time.Location
+	if task.AllDay && task.TimeZone == "" {
+		var err error
+		if loc, err = s.userLocation(ctx, task.UserID); err != nil {
+			return err
+		}
+	}
+	if err := task.NormalizeDue(loc); err != nil {
+		return apperrors.NewInvalidInputError("期限が不正です", err)
+	}
+	return nil
+}
+
+// applyUserTimeZone は期限の定型条件でタイムゾーンを指定していない場合に、ユーザーのタイムゾーンを設定した filter の複製を返します
+func (s *taskService) applyUserTimeZone(ctx context.Context, userID string, filter *model.TaskFilter) (*model.TaskFilter, error) {
+	if filter == nil || filter.DuePreset == "" || filter.TimeZone != "" {
+		return filter, nil
+	}
+	name, err := s.userTimeZone(ctx, userID)
+	if err != nil || name == "" {
+		return filter, err
+	}
+
+	zoned := *filter
+	zoned.TimeZone = name
+	return &zoned, nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *memoryTaskRepository) matchesDuePreset(task *model.Task, filter *model.TaskFilter) bool {
+	if filter == nil || filter.DuePreset == "" {
+		return true
+	}
+	now := r.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return task.MatchesDuePreset(filter.DuePreset, now.In(filter.Location()))
+}
+
+// zoneDirectory はユーザーIDからタイムゾーンへの対応表です
+type zoneDirectory map[string]string
+
+func (d zoneDirectory) ResolveUsers(context.Context, []string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (d zoneDirectory) TimeZone(_ context.Context, userID string) (string, error) {
+	return d[userID], nil
+}
+
+func newDueTestService(users zoneDirectory) (TaskService, *memoryTaskRepository) {
+	repo := &memoryTaskRepository{}
+	svc := NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, users, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	return svc, repo
+}
+
+func utc(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestTaskService_AllDayDue(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newDueTestService(zoneDirectory{"user1": "America/New_York"})
+
+	create := func(t *testing.T, task *model.Task) (*model.Task, error) {
+		t.Helper()
+		task.UserID = "user1"
+		task.Title = "締め切り"
+		task.Status = model.TaskStatusPending
+		return svc.CreateTask(ctx, task)
+	}
+
+	t.Run("floating_day_uses_user_time_zone", func(t *testing.T) {
+		task, err := create(t, &model.Task{AllDay: true, DueDay: "2024-03-10"})
+		require.NoError(t, err)
+		assert.True(t, task.DueDate.Equal(utc(2024, 3, 10, 5, 0)), "ニューヨークの0時（EST）")
+		assert.True(t, task.DueEnd.IsZero(), "タイムゾーンのない期限日は期限切れを日付で判定する")
+	})
+
+	t.Run("task_time_zone_wins", func(t *testing.T) {
+		task, err := create(t, &model.Task{AllDay: true, DueDay: "2024-03-10", TimeZone: "Asia/Tokyo"})
+		require.NoError(t, err)
+		assert.True(t, task.DueDate.Equal(utc(2024, 3, 9, 15, 0)))
+		assert.True(t, task.DueEnd.Equal(utc(2024, 3, 10, 15, 0)))
+	})
+
+	t.Run("dst_days_are_not_24_hours", func(t *testing.T) {
+		// 夏時間の開始日は23時間、終了日は25時間
+		spring, err := create(t, &model.Task{AllDay: true, DueDay: "2024-03-10", TimeZone: "America/New_York"})
+		require.NoError(t, err)
+		assert.Equal(t, 23*time.Hour, spring.DueEnd.Sub(spring.DueDate))
+
+		fall, err := create(t, &model.Task{AllDay: true, DueDay: "2024-11-03", TimeZone: "America/New_York"})
+		require.NoError(t, err)
+		assert.Equal(t, 25*time.Hour, fall.DueEnd.Sub(fall.DueDate))
+
+		london, err := create(t, &model.Task{AllDay: true, DueDay: "2024-03-31", TimeZone: "Europe/London"})
+		require.NoError(t, err)
+		assert.True(t, london.DueDate.Equal(utc(2024, 3, 31, 0, 0)))
+		assert.True(t, london.DueEnd.Equal(utc(2024, 3, 31, 23, 0)), "翌日0時は夏時間（BST）")
+	})
+
+	t.Run("day_derived_from_due_date", func(t *testing.T) {
+		// 東京の3月10日8時は UTC ではまだ9日
+		task, err := create(t, &model.Task{AllDay: true, DueDate: utc(2024, 3, 9, 23, 0), TimeZone: "Asia/Tokyo"})
+		require.NoError(t, err)
+		assert.Equal(t, "2024-03-10", task.DueDay)
+	})
+
+	t.Run("timed_task_keeps_instant", func(t *testing.T) {
+		due := utc(2024, 3, 10, 7, 30)
+		task, err := create(t, &model.Task{DueDate: due, DueDay: "2024-03-10", TimeZone: "Europe/Paris"})
+		require.NoError(t, err)
+		assert.True(t, task.DueDate.Equal(due))
+		assert.Empty(t, task.DueDay)
+		assert.Equal(t, "Europe/Paris", task.TimeZone)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, task := range map[string]*model.Task{
+			"nonexistent_day": {AllDay: true, DueDay: "2024-02-30"},
+			"malformed_day":   {AllDay: true, DueDay: "10/03/2024"},
+			"missing_day":     {AllDay: true},
+			"unknown_zone":    {DueDate: utc(2024, 3, 10, 0, 0), TimeZone: "Mars/Olympus_Mons"},
+			"local_zone":      {AllDay: true, DueDay: "2024-03-10", TimeZone: "Local"},
+		} {
+			_, err := create(t, task)
+			assert.True(t, apperrors.IsInvalidInput(err), name)
+		}
+	})
+}
+
+func TestTaskService_DuePresetsInUserTimeZone(t *testing.T) {
+	ctx := context.Background()
+	// ニューヨークでは3月9日22時30分（EST）、UTC では3月10日3時30分
+	now := utc(2024, 3, 10, 3, 30)
+
+	seed := func(t *testing.T, users zoneDirectory) TaskService {
+		t.Helper()
+		svc, repo := newDueTestService(users)
+		repo.now = now
+		for _, task := range []*model.Task{
+			{Title: "floating-08", AllDay: true, DueDay: "2024-03-08"},
+			{Title: "floating-09", AllDay: true, DueDay: "2024-03-09"},
+			{Title: "floating-10", AllDay: true, DueDay: "2024-03-10"},
+			{Title: "tokyo-09", AllDay: true, DueDay: "2024-03-09", TimeZone: "Asia/Tokyo"},
+			{Title: "tokyo-10", AllDay: true, DueDay: "2024-03-10", TimeZone: "Asia/Tokyo"},
+			{Title: "timed-0200", DueDate: utc(2024, 3, 10, 2, 0)},
+			{Title: "timed-0600", DueDate: utc(2024, 3, 10, 6, 0)},
+		} {
+			task.UserID = "user1"
+			task.Status = model.TaskStatusPending
+			_, err := svc.CreateTask(ctx, task)
+			require.NoError(t, err)
+		}
+		// 期限のないタスクはサービスでは作成できないため、リポジトリに直接保存する
+		_, err := repo.Create(ctx, &model.Task{UserID: "user1", Title: "no-due", Status: model.TaskStatusPending})
+		require.NoError(t, err)
+		return svc
+	}
+	list := func(t *testing.T, svc TaskService, filter *model.TaskFilter) []string {
+		t.Helper()
+		filter.OrderBy = model.TaskSortByTitle
+		return listTitles(t, svc, filter)
+	}
+
+	t.Run("user_time_zone", func(t *testing.T) {
+		svc := seed(t, zoneDirectory{"user1": "America/New_York"})
+		assert.Equal(t, []string{"floating-09", "timed-0200", "tokyo-09"},
+			list(t, svc, &model.TaskFilter{DuePreset: model.DueDateDueToday}))
+		// 東京の3月9日はもう終わっているため、ニューヨークでは今日でも期限切れ
+		assert.Equal(t, []string{"floating-08", "timed-0200", "tokyo-09"},
+			list(t, svc, &model.TaskFilter{DuePreset: model.DueDateOverdue}))
+	})
+
+	t.Run("request_time_zone_overrides_user", func(t *testing.T) {
+		svc := seed(t, zoneDirectory{"user1": "America/New_York"})
+		assert.Equal(t, []string{"floating-10", "timed-0200", "timed-0600", "tokyo-10"},
+			list(t, svc, &model.TaskFilter{DuePreset: model.DueDateDueToday, TimeZone: "UTC"}))
+	})
+
+	t.Run("defaults_to_utc", func(t *testing.T) {
+		svc := seed(t, zoneDirectory{})
+		assert.Equal(t, []string{"floating-08", "floating-09", "timed-0200", "tokyo-09"},
+			list(t, svc, &model.TaskFilter{DuePreset: model.DueDateOverdue}))
+	})
+}
+
+func TestTaskService_AllDayRecurrenceAcrossDST(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newDueTestService(zoneDirectory{"user1": "America/New_York"})
+
+	task, err := svc.CreateTask(ctx, &model.Task{
+		UserID:     "user1",
+		Title:      "日報",
+		Status:     model.TaskStatusPending,
+		AllDay:     true,
+		DueDay:     "2024-03-10",
+		Recurrence: &model.Recurrence{RRule: "FREQ=DAILY", TimeZone: "America/New_York"},
+	})
+	require.NoError(t, err)
+
+	completed := completeTask(t, svc, task)
+	next, err := svc.GetTask(ctx, completed.Recurrence.NextTaskID.Hex())
+	require.NoError(t, err)
+	assert.True(t, next.AllDay)
+	assert.Equal(t, "2024-03-11", next.DueDay)
+	assert.True(t, next.DueDate.Equal(utc(2024, 3, 11, 4, 0)), "夏時間（EDT）の0時")
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// ExportTasks はページングせずにカーソルで読み進めるため、件数が多くても一度にメモリへ載せません。
+// fn が返したエラーはそのまま返します
+func (s *taskService) ExportTasks(ctx context.Context, userID string, filter *model.TaskFilter, fn func(*model.Task) error) error {
+	if userID == "" {
+		return apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	filter, err := s.resolveFilter(ctx, userID, filter)
+	if err != nil {
+		return err
+	}
+	return s.taskRepo.ForEachByUserID(ctx, userID, filter, fn)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaskService_ExportTasks(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newBatchTestService()
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i, status := range []model.TaskStatus{model.TaskStatusPending, model.TaskStatusComplete, model.TaskStatusPending} {
+		repo.tasks = append(repo.tasks, &model.Task{ID: primitive.NewObjectID(), UserID: "user1", Title: "task", Status: status, CreatedAt: base.Add(time.Duration(i) * time.Hour)})
+	}
+	repo.tasks = append(repo.tasks, &model.Task{ID: primitive.NewObjectID(), UserID: "user2", Status: model.TaskStatusPending, CreatedAt: base})
+
+	t.Run("filter_and_order", func(t *testing.T) {
+		var exported []*model.Task
+		filter := &model.TaskFilter{Statuses: []model.TaskStatus{model.TaskStatusPending}, Descending: true}
+		err := svc.ExportTasks(ctx, "user1", filter, func(task *model.Task) error {
+			exported = append(exported, task)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, exported, 2)
+		assert.Equal(t, repo.tasks[2].ID, exported[0].ID)
+		assert.Equal(t, repo.tasks[0].ID, exported[1].ID)
+	})
+
+	t.Run("callback_error", func(t *testing.T) {
+		stop := errors.New("stream closed")
+		calls := 0
+		err := svc.ExportTasks(ctx, "user1", nil, func(*model.Task) error {
+			calls++
+			return stop
+		})
+		assert.Equal(t, stop, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.ExportTasks(ctx, "", nil, func(*model.Task) error { return nil })
+		var appErr *apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperrors.Unauthorized, appErr.Type)
+	})
+}
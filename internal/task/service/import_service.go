@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/pkg/logger"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+
+	"go.uber.org/zap"
+)
+
+// ImportRowSource は取り込む行を順に返します。すべて返し終えたら io.EOF を返します
+type ImportRowSource interface {
+	Next() (*model.ImportRow, error)
+}
+
+type ImportService interface {
+	// ImportTasks は rows の各行を検証し、userID のユーザーのタスクとして opts.BatchSize 件ずつ作成します。
+	// 同じジョブIDで再開した場合は、前回までに書き込みを終えた行を読み飛ばします。
+	// バッチの書き込み中に中断した場合、そのバッチの行は再開時にもう一度取り込まれることがあります
+	ImportTasks(ctx context.Context, userID string, opts model.ImportOptions, rows ImportRowSource) (*model.ImportResult, error)
+	GetImportJob(ctx context.Context, userID string, jobID string) (*model.ImportJob, error)
+}
+
+type importService struct {
+	jobRepo repository.ImportJobRepository
+	// workflowRepo はステータスを指定していない行を検証する前に、通常の作成と同じくワークフローの初期ステータスを設定するのに使います
+	workflowRepo repository.WorkflowRepository
+	// tasks は一括作成に使います。ワークフローや検索インデックスへの反映は通常の作成と同じです
+	tasks TaskService
+}
+
+func NewImportService(jobRepo repository.ImportJobRepository, workflowRepo repository.WorkflowRepository, tasks TaskService) ImportService {
+	return &importService{
+		jobRepo:      jobRepo,
+		workflowRepo: workflowRepo,
+		tasks:        tasks,
+	}
+}
+
+func (s *importService) ImportTasks(ctx context.Context, userID string, opts model.ImportOptions, rows ImportRowSource) (*model.ImportResult, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("取り込みの設定が不正です", err)
+	}
+
+	workflow, err := findWorkflow(ctx, s.workflowRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ImportResult{JobID: opts.JobID, DryRun: opts.DryRun}
+	var job *model.ImportJob
+	if !opts.DryRun {
+		var err error
+		if job, err = s.jobRepo.Start(ctx, userID, opts.JobID); err != nil {
+			return nil, err
+		}
+		result.LastRow = job.LastRow
+	}
+
+	var batch []*model.ImportRow
+	var received int64
+	// flush は溜めた行を書き込み、前回の flush 以降に失敗した行も含めて lastRow までの進捗を記録します
+	var importedBefore, failedBefore int64
+	flush := func() error {
+		if len(batch) > 0 {
+			tasks := make([]*model.Task, len(batch))
+			for i, row := range batch {
+				tasks[i] = row.Task
+			}
+			created, err := s.tasks.BatchCreateTasks(ctx, userID, tasks, false)
+			if err != nil {
+				return err
+			}
+			for i, item := range created.Items {
+				if item.Err != nil {
+					result.AddError(batch[i].Number, rowErrorMessage(opts.JobID, batch[i].Number, item.Err))
+					continue
+				}
+				result.Imported++
+			}
+			batch = batch[:0]
+		}
+		if received <= result.LastRow {
+			return nil
+		}
+		if err := s.jobRepo.Advance(ctx, job.ID, received, result.Imported-importedBefore, result.Failed-failedBefore); err != nil {
+			return err
+		}
+		result.LastRow = received
+		importedBefore, failedBefore = result.Imported, result.Failed
+		return nil
+	}
+
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var appErr *apperrors.AppError
+			if errors.As(err, &appErr) {
+				return nil, err
+			}
+			return nil, apperrors.NewInternalError("取り込む行の受信に失敗しました", err)
+		}
+		if row.Number <= received {
+			return nil, apperrors.NewInvalidInputError(
+				fmt.Sprintf("行番号は昇順に送ってください（%d行目の後に%d行目を受信しました）", received, row.Number), nil)
+		}
+		received = row.Number
+		result.Received++
+
+		if job != nil && row.Number <= job.LastRow {
+			result.Skipped++
+			continue
+		}
+		if row.Err != nil {
+			result.AddError(row.Number, rowErrorMessage(opts.JobID, row.Number, row.Err))
+			continue
+		}
+		row.Task.UserID = userID
+		if err := resolveInitialStatus(workflow, row.Task); err != nil {
+			result.AddError(row.Number, rowErrorMessage(opts.JobID, row.Number, err))
+			continue
+		}
+		if err := row.Task.Validate(); err != nil {
+			result.AddError(row.Number, rowErrorMessage(opts.JobID, row.Number, err))
+			continue
+		}
+		if opts.DryRun {
+			result.Imported++
+			continue
+		}
+
+		batch = append(batch, row)
+		if len(batch) >= opts.EffectiveBatchSize() {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if err := s.jobRepo.Complete(ctx, job.ID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// rowErrorMessage は行のエラーのうちクライアントに返すメッセージです。AppError は gRPC のステータスと同じく Message だけを返し、
+// データベースのエラーなどの内部の原因はログに記録します。AppError でないエラーは行の解析と検証のエラーで、そのまま返します
+func rowErrorMessage(jobID string, row int64, err error) string {
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		return err.Error()
+	}
+	if appErr.Type == apperrors.Internal && appErr.Err != nil {
+		logger.Error("import row failed",
+			zap.String("job_id", jobID),
+			zap.Int64("row", row),
+			zap.String("reason", appErr.ReasonCode()),
+			zap.Error(appErr.Err),
+		)
+	}
+	return appErr.Message
+}
+
+func (s *importService) GetImportJob(ctx context.Context, userID string, jobID string) (*model.ImportJob, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	if err := model.ValidateImportJobID(jobID); err != nil {
+		return nil, apperrors.NewInvalidInputError("取り込みジョブIDが不正です", err)
+	}
+	job, err := s.jobRepo.FindByJobID(ctx, userID, jobID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("取り込みジョブが見つかりません", err)
+		}
+		return nil, apperrors.NewInternalError("取り込みジョブの取得に失敗しました", err)
+	}
+	return job, nil
+}
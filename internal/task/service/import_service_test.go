@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryImportJobRepository はテスト用のインメモリ実装です
+type memoryImportJobRepository struct {
+	jobs []*model.ImportJob
+}
+
+func (r *memoryImportJobRepository) Start(ctx context.Context, userID string, jobID string) (*model.ImportJob, error) {
+	if job, err := r.FindByJobID(ctx, userID, jobID); err == nil {
+		return job, nil
+	}
+	job := &model.ImportJob{ID: primitive.NewObjectID(), JobID: jobID, UserID: userID, Status: model.ImportJobRunning}
+	r.jobs = append(r.jobs, job)
+	copied := *job
+	return &copied, nil
+}
+
+func (r *memoryImportJobRepository) FindByJobID(_ context.Context, userID string, jobID string) (*model.ImportJob, error) {
+	for _, job := range r.jobs {
+		if job.UserID == userID && job.JobID == jobID {
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrImportJobNotFound
+}
+
+func (r *memoryImportJobRepository) Advance(_ context.Context, id primitive.ObjectID, lastRow int64, imported int64, failed int64) error {
+	for _, job := range r.jobs {
+		if job.ID == id {
+			job.LastRow = lastRow
+			job.Imported += imported
+			job.Failed += failed
+		}
+	}
+	return nil
+}
+
+func (r *memoryImportJobRepository) Complete(_ context.Context, id primitive.ObjectID) error {
+	for _, job := range r.jobs {
+		if job.ID == id {
+			job.Status = model.ImportJobCompleted
+		}
+	}
+	return nil
+}
+
+// sliceRowSource は rows を順に返し、err が設定されていれば最後に返します
+type sliceRowSource struct {
+	rows []*model.ImportRow
+	err  error
+}
+
+func (s *sliceRowSource) Next() (*model.ImportRow, error) {
+	if len(s.rows) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+// importRows は行番号 from から to までの取り込む行を返します。bad に含まれる行番号は期限のない不正な行にします
+func importRows(from, to int64, bad ...int64) []*model.ImportRow {
+	var rows []*model.ImportRow
+	for n := from; n <= to; n++ {
+		task := &model.Task{Title: "task", Status: model.TaskStatusPending, DueDate: time.Now()}
+		for _, b := range bad {
+			if n == b {
+				task.DueDate = time.Time{}
+			}
+		}
+		rows = append(rows, &model.ImportRow{Number: n, Task: task})
+	}
+	return rows
+}
+
+// failingBulkCreateRepository は2件目以降のタスクの書き込みをデータベースのエラーで失敗させるテスト用のリポジトリです
+type failingBulkCreateRepository struct {
+	*memoryTaskRepository
+}
+
+func (r *failingBulkCreateRepository) BulkCreate(ctx context.Context, tasks []*model.Task) ([]error, error) {
+	errs, err := r.memoryTaskRepository.BulkCreate(ctx, tasks[:1])
+	if err != nil {
+		return nil, err
+	}
+	for range tasks[1:] {
+		errs = append(errs, apperrors.NewInternalError("タスクの作成に失敗しました",
+			errors.New("E11000 duplicate key error collection: task.tasks index: user_id_1_rank_1")))
+	}
+	return errs, nil
+}
+
+func newImportTestService() (ImportService, *memoryTaskRepository, *memoryImportJobRepository) {
+	tasks, repo := newBatchTestService()
+	jobs := &memoryImportJobRepository{}
+	return NewImportService(jobs, &memoryWorkflowRepository{}, tasks), repo, jobs
+}
+
+func TestImportService_ImportTasks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("batches_and_row_errors", func(t *testing.T) {
+		svc, repo, jobs := newImportTestService()
+		rows := importRows(1, 5, 2)
+		rows = append(rows, &model.ImportRow{Number: 7, Err: errors.New("期限の形式が不正です")})
+
+		result, err := svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "job1", BatchSize: 2}, &sliceRowSource{rows: rows})
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), result.Received)
+		assert.Equal(t, int64(4), result.Imported)
+		assert.Equal(t, int64(2), result.Failed)
+		assert.Equal(t, []int64{2, 7}, []int64{result.Errors[0].Row, result.Errors[1].Row})
+		assert.Equal(t, int64(7), result.LastRow)
+		assert.Len(t, repo.tasks, 4)
+		assert.Equal(t, "user1", repo.tasks[0].UserID)
+
+		job, err := jobs.FindByJobID(ctx, "user1", "job1")
+		require.NoError(t, err)
+		assert.Equal(t, model.ImportJobCompleted, job.Status)
+		assert.Equal(t, int64(4), job.Imported)
+		assert.Equal(t, int64(2), job.Failed)
+	})
+
+	t.Run("row_without_status", func(t *testing.T) {
+		repo := &memoryTaskRepository{}
+		workflows := &memoryWorkflowRepository{}
+		_, err := workflows.Save(ctx, reviewWorkflow("user1"))
+		require.NoError(t, err)
+		tasks := NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, workflows, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, &memoryTransactor{repo: repo}, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+		svc := NewImportService(&memoryImportJobRepository{}, workflows, tasks)
+
+		// ステータスの列がない CSV の行は、通常の作成と同じくワークフローの初期ステータスで取り込む
+		for _, dryRun := range []bool{true, false} {
+			rows := []*model.ImportRow{{Number: 1, Task: &model.Task{Title: "task", DueDate: time.Now()}}}
+			result, err := svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "job1", DryRun: dryRun}, &sliceRowSource{rows: rows})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), result.Imported)
+			assert.Empty(t, result.Errors)
+		}
+		require.Len(t, repo.tasks, 1)
+		assert.Equal(t, "BACKLOG", repo.tasks[0].WorkflowStatus)
+		assert.Equal(t, model.TaskStatusPending, repo.tasks[0].Status)
+	})
+
+	t.Run("write_errors_hide_cause", func(t *testing.T) {
+		repo := &memoryTaskRepository{}
+		tasks := NewTaskService(&failingBulkCreateRepository{repo}, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, &memoryTransactor{repo: repo}, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+		svc := NewImportService(&memoryImportJobRepository{}, &memoryWorkflowRepository{}, tasks)
+
+		result, err := svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "job1", BatchSize: 2}, &sliceRowSource{rows: importRows(1, 2)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Imported)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, model.ImportRowError{Row: 2, Message: "タスクの作成に失敗しました"}, result.Errors[0], "データベースのエラーはクライアントに返さない")
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		svc, repo, jobs := newImportTestService()
+
+		// 3行目を書き込んだ後に接続が切れた
+		_, err := svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "job1", BatchSize: 3},
+			&sliceRowSource{rows: importRows(1, 4), err: errors.New("connection reset")})
+		assert.True(t, apperrors.IsInternal(err))
+		assert.Len(t, repo.tasks, 3)
+
+		result, err := svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "job1", BatchSize: 3}, &sliceRowSource{rows: importRows(1, 5)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), result.Skipped)
+		assert.Equal(t, int64(2), result.Imported)
+		assert.Len(t, repo.tasks, 5)
+
+		job, err := jobs.FindByJobID(ctx, "user1", "job1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), job.LastRow)
+		assert.Equal(t, int64(5), job.Imported)
+	})
+
+	t.Run("dry_run", func(t *testing.T) {
+		svc, repo, jobs := newImportTestService()
+
+		result, err := svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "job1", DryRun: true}, &sliceRowSource{rows: importRows(1, 3, 3)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Imported)
+		assert.Equal(t, int64(1), result.Failed)
+		assert.Empty(t, repo.tasks)
+		assert.Empty(t, jobs.jobs)
+	})
+
+	t.Run("rows_out_of_order", func(t *testing.T) {
+		svc, _, _ := newImportTestService()
+		rows := append(importRows(2, 2), importRows(1, 1)...)
+
+		_, err := svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "job1"}, &sliceRowSource{rows: rows})
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("invalid_options", func(t *testing.T) {
+		svc, _, _ := newImportTestService()
+
+		_, err := svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "a/b"}, &sliceRowSource{})
+		assert.True(t, apperrors.IsInvalidInput(err))
+		_, err = svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "job1", BatchSize: model.MaxBatchSize + 1}, &sliceRowSource{})
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+}
+
+func TestImportService_GetImportJob(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newImportTestService()
+
+	_, err := svc.ImportTasks(ctx, "user1", model.ImportOptions{JobID: "job1"}, &sliceRowSource{rows: importRows(1, 2)})
+	require.NoError(t, err)
+
+	job, err := svc.GetImportJob(ctx, "user1", "job1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), job.LastRow)
+
+	_, err = svc.GetImportJob(ctx, "user2", "job1")
+	assert.True(t, apperrors.IsNotFound(err))
+}
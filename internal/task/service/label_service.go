@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+type LabelService interface {
+	CreateLabel(ctx context.Context, label *model.Label) (*model.Label, error)
+	ListLabels(ctx context.Context, userID string) ([]*model.Label, error)
+	// UpdateLabel は空でない項目だけを更新します。名前を変えるとタスクのラベルも置き換わります
+	UpdateLabel(ctx context.Context, userID string, id string, name string, color string) (*model.Label, error)
+	DeleteLabel(ctx context.Context, userID string, id string) error
+}
+
+type labelService struct {
+	labelRepo repository.LabelRepository
+}
+
+func NewLabelService(labelRepo repository.LabelRepository) LabelService {
+	return &labelService{
+		labelRepo: labelRepo,
+	}
+}
+
+func (s *labelService) CreateLabel(ctx context.Context, label *model.Label) (*model.Label, error) {
+	if err := label.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("ラベルが不正です", err)
+	}
+
+	created, err := s.labelRepo.Create(ctx, label)
+	if err != nil {
+		if apperrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("ラベルの作成に失敗しました", err)
+	}
+	return created, nil
+}
+
+func (s *labelService) ListLabels(ctx context.Context, userID string) ([]*model.Label, error) {
+	labels, err := s.labelRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("ラベル一覧の取得に失敗しました", err)
+	}
+	return labels, nil
+}
+
+func (s *labelService) UpdateLabel(ctx context.Context, userID string, id string, name string, color string) (*model.Label, error) {
+	label, err := s.findLabel(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := label.Name
+	if name != "" {
+		label.Name = name
+	}
+	if color != "" {
+		label.Color = color
+	}
+	if err := label.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("ラベルが不正です", err)
+	}
+
+	updated, err := s.labelRepo.Update(ctx, label, oldName)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("ラベルが見つかりません", err)
+		}
+		if apperrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("ラベルの更新に失敗しました", err)
+	}
+	return updated, nil
+}
+
+func (s *labelService) DeleteLabel(ctx context.Context, userID string, id string) error {
+	label, err := s.findLabel(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.labelRepo.Delete(ctx, label); err != nil {
+		if apperrors.IsNotFound(err) {
+			return apperrors.NewNotFoundError("ラベルが見つかりません", err)
+		}
+		return apperrors.NewInternalError("ラベルの削除に失敗しました", err)
+	}
+	return nil
+}
+
+func (s *labelService) findLabel(ctx context.Context, userID string, id string) (*model.Label, error) {
+	label, err := s.labelRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("ラベルが見つかりません", err)
+		}
+		if apperrors.IsInvalidInput(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("ラベルの取得に失敗しました", err)
+	}
+	return label, nil
+}
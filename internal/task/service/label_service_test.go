@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockLabelRepository struct {
+	mock.Mock
+}
+
+func (m *mockLabelRepository) Create(ctx context.Context, label *model.Label) (*model.Label, error) {
+	args := m.Called(ctx, label)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Label), args.Error(1)
+}
+
+func (m *mockLabelRepository) FindByID(ctx context.Context, userID string, id string) (*model.Label, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Label), args.Error(1)
+}
+
+func (m *mockLabelRepository) FindByUserID(ctx context.Context, userID string) ([]*model.Label, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Label), args.Error(1)
+}
+
+func (m *mockLabelRepository) Update(ctx context.Context, label *model.Label, oldName string) (*model.Label, error) {
+	args := m.Called(ctx, label, oldName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Label), args.Error(1)
+}
+
+func (m *mockLabelRepository) Delete(ctx context.Context, label *model.Label) error {
+	args := m.Called(ctx, label)
+	return args.Error(0)
+}
+
+func TestLabelService_CreateLabel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		repo := new(mockLabelRepository)
+		service := NewLabelService(repo)
+		label := &model.Label{UserID: "user1", Name: "backend", Color: "#336699"}
+		repo.On("Create", ctx, label).Return(label, nil)
+
+		created, err := service.CreateLabel(ctx, label)
+		assert.NoError(t, err)
+		assert.Equal(t, label, created)
+	})
+
+	t.Run("invalid_color", func(t *testing.T) {
+		repo := new(mockLabelRepository)
+		service := NewLabelService(repo)
+
+		created, err := service.CreateLabel(ctx, &model.Label{UserID: "user1", Name: "backend", Color: "blue"})
+		assert.Nil(t, created)
+		assert.True(t, apperrors.IsInvalidInput(err))
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("already_exists", func(t *testing.T) {
+		repo := new(mockLabelRepository)
+		service := NewLabelService(repo)
+		label := &model.Label{UserID: "user1", Name: "backend"}
+		repo.On("Create", ctx, label).Return(nil, apperrors.NewAlreadyExistsError("exists", nil))
+
+		_, err := service.CreateLabel(ctx, label)
+		assert.True(t, apperrors.IsAlreadyExists(err))
+	})
+}
+
+func TestLabelService_UpdateLabel(t *testing.T) {
+	ctx := context.Background()
+	labelID := primitive.NewObjectID()
+
+	t.Run("rename_keeps_color", func(t *testing.T) {
+		repo := new(mockLabelRepository)
+		service := NewLabelService(repo)
+		repo.On("FindByID", ctx, "user1", labelID.Hex()).
+			Return(&model.Label{ID: labelID, UserID: "user1", Name: "front", Color: "#336699"}, nil)
+		repo.On("Update", ctx, mock.MatchedBy(func(l *model.Label) bool {
+			return l.Name == "frontend" && l.Color == "#336699"
+		}), "front").Return(&model.Label{ID: labelID, Name: "frontend", Color: "#336699"}, nil)
+
+		updated, err := service.UpdateLabel(ctx, "user1", labelID.Hex(), "frontend", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "frontend", updated.Name)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		repo := new(mockLabelRepository)
+		service := NewLabelService(repo)
+		repo.On("FindByID", ctx, "user1", labelID.Hex()).Return(nil, apperrors.NewNotFoundError("not found", nil))
+
+		_, err := service.UpdateLabel(ctx, "user1", labelID.Hex(), "frontend", "")
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestLabelService_DeleteLabel(t *testing.T) {
+	ctx := context.Background()
+	labelID := primitive.NewObjectID()
+	repo := new(mockLabelRepository)
+	service := NewLabelService(repo)
+	label := &model.Label{ID: labelID, UserID: "user1", Name: "backend"}
+	repo.On("FindByID", ctx, "user1", labelID.Hex()).Return(label, nil)
+	repo.On("Delete", ctx, label).Return(nil)
+
+	assert.NoError(t, service.DeleteLabel(ctx, "user1", labelID.Hex()))
+	repo.AssertExpectations(t)
+}
+
+func TestCustomFieldService_UpdateCustomField(t *testing.T) {
+	ctx := context.Background()
+	fieldID := primitive.NewObjectID()
+
+	t.Run("type_is_immutable", func(t *testing.T) {
+		repo := new(mockCustomFieldRepository)
+		service := NewCustomFieldService(repo)
+		repo.On("FindByID", ctx, "user1", fieldID.Hex()).Return(&model.CustomFieldDefinition{
+			ID: fieldID, OwnerID: "user1", Name: "見積", Type: model.CustomFieldNumber,
+		}, nil)
+		repo.On("Update", ctx, mock.MatchedBy(func(d *model.CustomFieldDefinition) bool {
+			return d.Type == model.CustomFieldNumber && d.Name == "見積時間" && d.Required
+		})).Return(&model.CustomFieldDefinition{ID: fieldID, Name: "見積時間", Type: model.CustomFieldNumber, Required: true}, nil)
+
+		updated, err := service.UpdateCustomField(ctx, &model.CustomFieldDefinition{
+			ID: fieldID, OwnerID: "user1", Name: "見積時間", Type: model.CustomFieldText, Required: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, model.CustomFieldNumber, updated.Type)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("select_without_options", func(t *testing.T) {
+		repo := new(mockCustomFieldRepository)
+		service := NewCustomFieldService(repo)
+		repo.On("FindByID", ctx, "user1", fieldID.Hex()).Return(&model.CustomFieldDefinition{
+			ID: fieldID, OwnerID: "user1", Name: "重要度", Type: model.CustomFieldSingleSelect, Options: []string{"S1"},
+		}, nil)
+
+		_, err := service.UpdateCustomField(ctx, &model.CustomFieldDefinition{ID: fieldID, OwnerID: "user1", Name: "重要度"})
+		assert.True(t, apperrors.IsInvalidInput(err))
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryTaskRepository はMongoDBのソートとキーセット条件を再現するテスト用のリポジトリです
+type memoryTaskRepository struct {
+	repository.TaskRepository
+	tasks []*model.Task
+	// now は期限の定型条件の基準日時です。ゼロ値の場合は現在時刻です
+	now time.Time
+}
+
+func (r *memoryTaskRepository) FindByUserID(_ context.Context, userID string, filter *model.TaskFilter, limit int32, after *model.TaskCursor) ([]*model.Task, int32, error) {
+	field := filter.SortField()
+	desc := filter.IsDescending()
+
+	var matched []*model.Task
+	for _, task := range r.tasks {
+		if task.UserID == userID && matchesStatuses(task, filter) && matchesProject(task, filter) && r.matchesDuePreset(task, filter) {
+			matched = append(matched, task)
+		}
+	}
+
+	less := func(av interface{}, aID primitive.ObjectID, bv interface{}, bID primitive.ObjectID) bool {
+		if c := compareSortValues(av, bv); c != 0 {
+			return (c < 0) != desc
+		}
+		if aID == bID {
+			return false
+		}
+		return (bytes.Compare(aID[:], bID[:]) < 0) != desc
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return less(matched[i].SortValue(field), matched[i].ID, matched[j].SortValue(field), matched[j].ID)
+	})
+
+	var result []*model.Task
+	for _, task := range matched {
+		if after != nil && !less(after.LastValue, after.LastID, task.SortValue(field), task.ID) {
+			continue
+		}
+		result = append(result, task)
+		if int32(len(result)) == limit {
+			break
+		}
+	}
+	return result, int32(len(matched)), nil
+}
+
+func (r *memoryTaskRepository) ForEachByUserID(ctx context.Context, userID string, filter *model.TaskFilter, fn func(*model.Task) error) error {
+	tasks, _, err := r.FindByUserID(ctx, userID, filter, 0, nil)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchesStatuses はフィルターのうちステータスの条件だけを再現します
+func matchesStatuses(task *model.Task, filter *model.TaskFilter) bool {
+	if filter == nil {
+		return true
+	}
+	return (len(filter.Statuses) == 0 || slices.Contains(filter.Statuses, task.Status)) &&
+		(len(filter.WorkflowStatuses) == 0 || slices.Contains(filter.WorkflowStatuses, task.WorkflowStatus))
+}
+
+func compareSortValues(a, b interface{}) int {
+	key := func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case time.Time:
+			return primitive.NewDateTimeFromTime(v)
+		default:
+			return v
+		}
+	}
+	a, b = key(a), key(b)
+	switch a := a.(type) {
+	case nil:
+		if b == nil {
+			return 0
+		}
+		return -1
+	case int32:
+		b := b.(int32)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case primitive.DateTime:
+		b := b.(primitive.DateTime)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case string:
+		b := b.(string)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+	return 0
+}
+
+// TestTaskService_ListTasks_PagesWithoutGapsOrDuplicates はランダムなデータと並び順で
+// 全ページを辿った結果が、一括で並べ替えた結果と一致することを確認します
+func TestTaskService_ListTasks_PagesWithoutGapsOrDuplicates(t *testing.T) {
+	sortFields := []model.TaskSortField{
+		model.TaskSortByCreatedAt,
+		model.TaskSortByUpdatedAt,
+		model.TaskSortByDueDate,
+		model.TaskSortByTitle,
+		model.TaskSortByPriority,
+		model.TaskSortByRank,
+	}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for seed := int64(1); seed <= 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+
+		// 重複するソートキーを多く含めて、_id による順序付けを検証する
+		n := 200 + rng.Intn(800)
+		repo := &memoryTaskRepository{}
+		for i := 0; i < n; i++ {
+			userID := "user1"
+			if rng.Intn(10) == 0 {
+				userID = "user2"
+			}
+			id := primitive.NewObjectID()
+			repo.tasks = append(repo.tasks, &model.Task{
+				ID:        id,
+				UserID:    userID,
+				Rank:      model.AppendRank(id),
+				Title:     fmt.Sprintf("task-%02d", rng.Intn(30)),
+				DueDate:   base.Add(time.Duration(rng.Intn(20)) * time.Hour),
+				CreatedAt: base.Add(time.Duration(rng.Intn(50)) * time.Millisecond),
+				UpdatedAt: base.Add(time.Duration(rng.Intn(5)) * time.Minute),
+				Priority:  model.TaskPriority(rng.Intn(5)),
+			})
+		}
+		rng.Shuffle(len(repo.tasks), func(i, j int) { repo.tasks[i], repo.tasks[j] = repo.tasks[j], repo.tasks[i] })
+
+		svc := NewTaskService(repo, nil, nil, nil, nil, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+		for _, field := range sortFields {
+			for _, desc := range []bool{false, true} {
+				filter := &model.TaskFilter{OrderBy: field, Descending: desc}
+				pageSize := int32(1 + rng.Intn(60))
+
+				all, total, err := repo.FindByUserID(context.Background(), "user1", filter, 0, nil)
+				require.NoError(t, err)
+
+				var paged []*model.Task
+				seen := make(map[primitive.ObjectID]bool)
+				token := ""
+				for {
+					page, err := svc.ListTasks(context.Background(), "user1", filter, pageSize, token)
+					require.NoError(t, err)
+					require.Equal(t, total, page.TotalCount)
+					require.LessOrEqual(t, int32(len(page.Tasks)), pageSize)
+					for _, task := range page.Tasks {
+						require.False(t, seen[task.ID], "seed=%d field=%s desc=%v: duplicate %s", seed, field, desc, task.ID.Hex())
+						seen[task.ID] = true
+					}
+					paged = append(paged, page.Tasks...)
+					if page.NextPageToken == "" {
+						break
+					}
+					token = page.NextPageToken
+				}
+
+				assert.Equal(t, all, paged, "seed=%d field=%s desc=%v page_size=%d", seed, field, desc, pageSize)
+			}
+		}
+	}
+}
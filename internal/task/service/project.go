@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// checkProject はタスクの属するプロジェクトが同じユーザーのものかを確認します。
+// アーカイブしたプロジェクトへはタスクを追加できませんが、既にそのプロジェクトにあるタスクは更新できます
+func (s *taskService) checkProject(ctx context.Context, current, task *model.Task) error {
+	if !task.HasProject() || (current != nil && current.ProjectID == task.ProjectID) {
+		return nil
+	}
+	project, err := s.projectRepo.FindByID(ctx, task.UserID, task.ProjectID.Hex())
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return apperrors.NewInvalidInputError("プロジェクトが見つかりません", err)
+		}
+		return apperrors.NewInternalError("プロジェクトの取得に失敗しました", err)
+	}
+	if project.Archived {
+		return apperrors.NewFailedPreconditionError("アーカイブしたプロジェクトにはタスクを追加できません", nil)
+	}
+	return nil
+}
+
+// hideArchivedProjects はアーカイブしたプロジェクトのタスクを除くよう filter の複製に条件を加えます。
+// プロジェクトやインボックスを指定した場合と、アーカイブしたプロジェクトも含める場合はそのまま返します
+func (s *taskService) hideArchivedProjects(ctx context.Context, userID string, filter *model.TaskFilter) (*model.TaskFilter, error) {
+	if !filter.HidesArchivedProjects() {
+		return filter, nil
+	}
+	ids, err := s.projectRepo.FindArchivedIDs(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("プロジェクトの取得に失敗しました", err)
+	}
+	if len(ids) == 0 {
+		return filter, nil
+	}
+
+	var hidden model.TaskFilter
+	if filter != nil {
+		hidden = *filter
+	}
+	hidden.HiddenProjectIDs = ids
+	return &hidden, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+type ProjectService interface {
+	CreateProject(ctx context.Context, project *model.Project) (*model.Project, error)
+	GetProject(ctx context.Context, userID string, id string) (*model.Project, error)
+	// ListProjects はプロジェクトを名前順に返します。includeArchived が false の場合はアーカイブしたプロジェクトを除きます
+	ListProjects(ctx context.Context, userID string, includeArchived bool) ([]*model.Project, error)
+	// UpdateProject は空でない項目だけを更新します
+	UpdateProject(ctx context.Context, userID string, id string, name string, description string, color string) (*model.Project, error)
+	// SetProjectArchived はプロジェクトをアーカイブ、またはアーカイブから戻します。
+	// アーカイブしたプロジェクトのタスクは、プロジェクトを指定しない限りタスク一覧に表示しません
+	SetProjectArchived(ctx context.Context, userID string, id string, archived bool) (*model.Project, error)
+	// DeleteProject はプロジェクトを削除します。mode が ProjectDeleteCascade の場合はプロジェクトのタスクも削除し、
+	// それ以外の場合はタスクをインボックスへ移します
+	DeleteProject(ctx context.Context, userID string, id string, mode model.ProjectDeleteMode) error
+}
+
+type projectService struct {
+	projectRepo repository.ProjectRepository
+	// tasks はプロジェクトのタスクを削除するときに使います。サブタスクや依存関係などの後始末はタスクの削除と同じです
+	tasks TaskService
+}
+
+func NewProjectService(projectRepo repository.ProjectRepository, tasks TaskService) ProjectService {
+	return &projectService{
+		projectRepo: projectRepo,
+		tasks:       tasks,
+	}
+}
+
+func (s *projectService) CreateProject(ctx context.Context, project *model.Project) (*model.Project, error) {
+	if project.UserID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	project.Archived = false
+	project.ArchivedAt = time.Time{}
+	if err := project.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("プロジェクトが不正です", err)
+	}
+
+	created, err := s.projectRepo.Create(ctx, project)
+	if err != nil {
+		if apperrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("プロジェクトの作成に失敗しました", err)
+	}
+	return created, nil
+}
+
+func (s *projectService) GetProject(ctx context.Context, userID string, id string) (*model.Project, error) {
+	return s.findProject(ctx, userID, id)
+}
+
+func (s *projectService) ListProjects(ctx context.Context, userID string, includeArchived bool) ([]*model.Project, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	projects, err := s.projectRepo.FindByUserID(ctx, userID, includeArchived)
+	if err != nil {
+		return nil, apperrors.NewInternalError("プロジェクト一覧の取得に失敗しました", err)
+	}
+	return projects, nil
+}
+
+func (s *projectService) UpdateProject(ctx context.Context, userID string, id string, name string, description string, color string) (*model.Project, error) {
+	project, err := s.findProject(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != "" {
+		project.Name = name
+	}
+	if description != "" {
+		project.Description = description
+	}
+	if color != "" {
+		project.Color = color
+	}
+	if err := project.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("プロジェクトが不正です", err)
+	}
+	return s.updateProject(ctx, project)
+}
+
+func (s *projectService) SetProjectArchived(ctx context.Context, userID string, id string, archived bool) (*model.Project, error) {
+	project, err := s.findProject(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if project.Archived == archived {
+		return project, nil
+	}
+
+	project.Archived = archived
+	project.ArchivedAt = time.Time{}
+	if archived {
+		project.ArchivedAt = time.Now()
+	}
+	return s.updateProject(ctx, project)
+}
+
+func (s *projectService) DeleteProject(ctx context.Context, userID string, id string, mode model.ProjectDeleteMode) error {
+	project, err := s.findProject(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if mode == model.ProjectDeleteCascade {
+		if err := s.deleteProjectTasks(ctx, project); err != nil {
+			return err
+		}
+	}
+	// 削除中に追加されたタスクなど、残っているタスクはインボックスへ移る
+	if err := s.projectRepo.Delete(ctx, project); err != nil {
+		if apperrors.IsNotFound(err) {
+			return apperrors.NewNotFoundError("プロジェクトが見つかりません", err)
+		}
+		return apperrors.NewInternalError("プロジェクトの削除に失敗しました", err)
+	}
+	return nil
+}
+
+// deleteProjectTasks はプロジェクトのタスクを一括削除の上限ごとに削除します。
+// 削除できなかったタスクがある場合はプロジェクトを残し、最初のエラーを返します
+func (s *projectService) deleteProjectTasks(ctx context.Context, project *model.Project) error {
+	var ids []string
+	err := s.tasks.ExportTasks(ctx, project.UserID, &model.TaskFilter{ProjectID: project.ID}, func(task *model.Task) error {
+		ids = append(ids, task.ID.Hex())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(ids); start += model.MaxBatchSize {
+		end := min(start+model.MaxBatchSize, len(ids))
+		result, err := s.tasks.BatchDeleteTasks(ctx, project.UserID, &model.TaskSelector{TaskIDs: ids[start:end]}, false)
+		if err != nil {
+			return err
+		}
+		for _, item := range result.Items {
+			// 親と一緒に削除済みのタスクなど、既にないタスクは削除できたものとして扱う
+			if item.Err != nil && !apperrors.IsNotFound(item.Err) {
+				return item.Err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *projectService) updateProject(ctx context.Context, project *model.Project) (*model.Project, error) {
+	updated, err := s.projectRepo.Update(ctx, project)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("プロジェクトが見つかりません", err)
+		}
+		if apperrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("プロジェクトの更新に失敗しました", err)
+	}
+	return updated, nil
+}
+
+func (s *projectService) findProject(ctx context.Context, userID string, id string) (*model.Project, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	project, err := s.projectRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("プロジェクトが見つかりません", err)
+		}
+		if apperrors.IsInvalidInput(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("プロジェクトの取得に失敗しました", err)
+	}
+	return project, nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryProjectRepository はテスト用のインメモリ実装です。tasks を指定すると削除時にタスクをインボックスへ移します
+type memoryProjectRepository struct {
+	projects []*model.Project
+	tasks    *memoryTaskRepository
+}
+
+func (r *memoryProjectRepository) Create(_ context.Context, project *model.Project) (*model.Project, error) {
+	for _, p := range r.projects {
+		if p.UserID == project.UserID && p.Name == project.Name {
+			return nil, repository.ErrProjectAlreadyExists
+		}
+	}
+	project.ID = primitive.NewObjectID()
+	project.CreatedAt = time.Now()
+	project.UpdatedAt = project.CreatedAt
+	stored := *project
+	r.projects = append(r.projects, &stored)
+	return project, nil
+}
+
+func (r *memoryProjectRepository) find(userID string, id primitive.ObjectID) (int, *model.Project) {
+	for i, p := range r.projects {
+		if p.UserID == userID && p.ID == id {
+			return i, p
+		}
+	}
+	return -1, nil
+}
+
+func (r *memoryProjectRepository) FindByID(_ context.Context, userID string, id string) (*model.Project, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+	_, project := r.find(userID, objectID)
+	if project == nil {
+		return nil, repository.ErrProjectNotFound
+	}
+	copied := *project
+	return &copied, nil
+}
+
+func (r *memoryProjectRepository) FindByUserID(_ context.Context, userID string, includeArchived bool) ([]*model.Project, error) {
+	projects := []*model.Project{}
+	for _, p := range r.projects {
+		if p.UserID == userID && (includeArchived || !p.Archived) {
+			copied := *p
+			projects = append(projects, &copied)
+		}
+	}
+	return projects, nil
+}
+
+func (r *memoryProjectRepository) FindArchivedIDs(_ context.Context, userID string) ([]primitive.ObjectID, error) {
+	var ids []primitive.ObjectID
+	for _, p := range r.projects {
+		if p.UserID == userID && p.Archived {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *memoryProjectRepository) Update(_ context.Context, project *model.Project) (*model.Project, error) {
+	_, stored := r.find(project.UserID, project.ID)
+	if stored == nil {
+		return nil, repository.ErrProjectNotFound
+	}
+	*stored = *project
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *memoryProjectRepository) Delete(_ context.Context, project *model.Project) error {
+	i, stored := r.find(project.UserID, project.ID)
+	if stored == nil {
+		return repository.ErrProjectNotFound
+	}
+	r.projects = append(r.projects[:i], r.projects[i+1:]...)
+	if r.tasks != nil {
+		for _, task := range r.tasks.tasks {
+			if task.UserID == project.UserID && task.ProjectID == project.ID {
+				task.ProjectID = primitive.NilObjectID
+			}
+		}
+	}
+	return nil
+}
+
+// matchesProject はフィルターのうちプロジェクトの条件を再現します
+func matchesProject(task *model.Task, filter *model.TaskFilter) bool {
+	if filter == nil {
+		return true
+	}
+	switch {
+	case !filter.ProjectID.IsZero():
+		return task.ProjectID == filter.ProjectID
+	case filter.Inbox:
+		return !task.HasProject()
+	default:
+		return !task.HasProject() || !slices.Contains(filter.HiddenProjectIDs, task.ProjectID)
+	}
+}
+
+func newProjectTestServices() (ProjectService, TaskService, *memoryTaskRepository) {
+	tasks := &memoryTaskRepository{}
+	projects := &memoryProjectRepository{tasks: tasks}
+	taskService := NewTaskService(tasks, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, projects, nil, nil, nil, &memoryTransactor{repo: tasks}, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	return NewProjectService(projects, taskService), taskService, tasks
+}
+
+// createProjectTask はプロジェクト projectID のタスクを作成します。projectID がゼロ値の場合はインボックスに作成します
+func createProjectTask(t *testing.T, svc TaskService, title string, projectID primitive.ObjectID) *model.Task {
+	t.Helper()
+	task, err := svc.CreateTask(context.Background(), &model.Task{
+		UserID:    "user1",
+		ProjectID: projectID,
+		Title:     title,
+		Status:    model.TaskStatusPending,
+		DueDate:   time.Now().Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+	return task
+}
+
+func listTitles(t *testing.T, svc TaskService, filter *model.TaskFilter) []string {
+	t.Helper()
+	page, err := svc.ListTasks(context.Background(), "user1", filter, 100, "")
+	require.NoError(t, err)
+	var titles []string
+	for _, task := range page.Tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+func TestProjectService_CreateProject(t *testing.T) {
+	ctx := context.Background()
+	projects, _, _ := newProjectTestServices()
+
+	created, err := projects.CreateProject(ctx, &model.Project{UserID: "user1", Name: "Work", Color: "#336699", Archived: true})
+	require.NoError(t, err)
+	assert.False(t, created.Archived)
+
+	_, err = projects.CreateProject(ctx, &model.Project{UserID: "user1", Name: "Work"})
+	assert.True(t, apperrors.IsAlreadyExists(err))
+
+	_, err = projects.CreateProject(ctx, &model.Project{UserID: "user1", Name: "Home", Color: "red"})
+	assert.True(t, apperrors.IsInvalidInput(err))
+
+	_, err = projects.GetProject(ctx, "user2", created.ID.Hex())
+	assert.True(t, apperrors.IsNotFound(err))
+}
+
+func TestProjectService_ArchiveHidesTasks(t *testing.T) {
+	ctx := context.Background()
+	projects, tasks, _ := newProjectTestServices()
+	work, err := projects.CreateProject(ctx, &model.Project{UserID: "user1", Name: "Work"})
+	require.NoError(t, err)
+
+	createProjectTask(t, tasks, "inbox", primitive.NilObjectID)
+	task := createProjectTask(t, tasks, "work", work.ID)
+	assert.Equal(t, []string{"inbox", "work"}, listTitles(t, tasks, nil))
+	assert.Equal(t, []string{"inbox"}, listTitles(t, tasks, &model.TaskFilter{Inbox: true}))
+
+	archived, err := projects.SetProjectArchived(ctx, "user1", work.ID.Hex(), true)
+	require.NoError(t, err)
+	assert.True(t, archived.Archived)
+	assert.False(t, archived.ArchivedAt.IsZero())
+
+	assert.Equal(t, []string{"inbox"}, listTitles(t, tasks, nil))
+	assert.Equal(t, []string{"work"}, listTitles(t, tasks, &model.TaskFilter{ProjectID: work.ID}))
+	assert.Equal(t, []string{"inbox", "work"}, listTitles(t, tasks, &model.TaskFilter{IncludeArchived: true}))
+	active, err := projects.ListProjects(ctx, "user1", false)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	// アーカイブしたプロジェクトへは追加できないが、既にあるタスクは更新できる
+	_, err = tasks.CreateTask(ctx, &model.Task{UserID: "user1", ProjectID: work.ID, Title: "new", Status: model.TaskStatusPending, DueDate: time.Now()})
+	assert.True(t, apperrors.IsFailedPrecondition(err))
+	task.Title = "renamed"
+	_, err = tasks.UpdateTask(ctx, task.ID.Hex(), task)
+	assert.NoError(t, err)
+
+	_, err = projects.SetProjectArchived(ctx, "user1", work.ID.Hex(), false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inbox", "renamed"}, listTitles(t, tasks, nil))
+}
+
+func TestProjectService_TaskProject(t *testing.T) {
+	ctx := context.Background()
+	projects, tasks, _ := newProjectTestServices()
+	other, err := projects.CreateProject(ctx, &model.Project{UserID: "user2", Name: "Other"})
+	require.NoError(t, err)
+
+	_, err = tasks.CreateTask(ctx, &model.Task{UserID: "user1", ProjectID: other.ID, Title: "task", Status: model.TaskStatusPending, DueDate: time.Now()})
+	assert.True(t, apperrors.IsInvalidInput(err))
+}
+
+func TestProjectService_DeleteProject(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("move_to_inbox", func(t *testing.T) {
+		projects, tasks, repo := newProjectTestServices()
+		work, err := projects.CreateProject(ctx, &model.Project{UserID: "user1", Name: "Work"})
+		require.NoError(t, err)
+		createProjectTask(t, tasks, "work", work.ID)
+
+		require.NoError(t, projects.DeleteProject(ctx, "user1", work.ID.Hex(), model.ProjectDeleteMoveToInbox))
+		require.Len(t, repo.tasks, 1)
+		assert.False(t, repo.tasks[0].HasProject())
+		_, err = projects.GetProject(ctx, "user1", work.ID.Hex())
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		projects, tasks, repo := newProjectTestServices()
+		work, err := projects.CreateProject(ctx, &model.Project{UserID: "user1", Name: "Work"})
+		require.NoError(t, err)
+		createProjectTask(t, tasks, "inbox", primitive.NilObjectID)
+		createProjectTask(t, tasks, "work 1", work.ID)
+		createProjectTask(t, tasks, "work 2", work.ID)
+		// アーカイブしたプロジェクトのタスクも削除する
+		_, err = projects.SetProjectArchived(ctx, "user1", work.ID.Hex(), true)
+		require.NoError(t, err)
+
+		require.NoError(t, projects.DeleteProject(ctx, "user1", work.ID.Hex(), model.ProjectDeleteCascade))
+		require.Len(t, repo.tasks, 1)
+		assert.Equal(t, "inbox", repo.tasks[0].Title)
+	})
+}
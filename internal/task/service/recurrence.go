@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/pkg/pagination"
+	"github.com/my-backend-project/internal/task/model"
+)
+
+func (s *taskService) PreviewRecurrence(ctx context.Context, recurrence *model.Recurrence, after time.Time, limit int32) ([]time.Time, error) {
+	if after.IsZero() {
+		after = time.Now()
+	}
+	if recurrence.Start.IsZero() {
+		recurrence.Start = after
+	}
+	limit = pagination.PageSize(limit)
+	if limit > model.MaxRecurrencePreview {
+		limit = model.MaxRecurrencePreview
+	}
+
+	occurrences, err := recurrence.Preview(after, int(limit))
+	if err != nil {
+		return nil, apperrors.NewInvalidInputError("繰り返しの設定が不正です", err)
+	}
+	return occurrences, nil
+}
+
+// prepareRecurrence は繰り返しの設定を検証します。起点が未指定の場合は、ルールとタイムゾーンが変わらなければ
+// 現在の起点を引き継ぎ、それ以外は期限を起点にします。次の回のタスクは current から引き継ぎます
+func prepareRecurrence(task, current *model.Task) error {
+	if !task.IsRecurring() {
+		return nil
+	}
+	if current != nil && current.IsRecurring() {
+		if task.Recurrence.Start.IsZero() &&
+			task.Recurrence.RRule == current.Recurrence.RRule &&
+			task.Recurrence.TimeZone == current.Recurrence.TimeZone {
+			task.Recurrence.Start = current.Recurrence.Start
+		}
+		task.Recurrence.NextTaskID = current.Recurrence.NextTaskID
+	}
+	if task.Recurrence.Start.IsZero() {
+		task.Recurrence.Start = task.DueDate
+	}
+	if err := task.Recurrence.Validate(); err != nil {
+		return apperrors.NewInvalidInputError("繰り返しの設定が不正です", err)
+	}
+	return nil
+}
+
+// scheduleNextOccurrence は完了した繰り返しタスクの次の回を作成し、完了したタスクに記録して返します。
+// 次の回の期限は完了したタスクの期限より後の最初の発生日時です。
+// 繰り返しが終わっている場合や既に次の回を作成済みの場合は何もしません
+func (s *taskService) scheduleNextOccurrence(ctx context.Context, task *model.Task) (*model.Task, error) {
+	if !task.IsRecurring() || !task.Recurrence.NextTaskID.IsZero() {
+		return task, nil
+	}
+	due, ok, err := task.Recurrence.Next(task.DueDate)
+	if err != nil {
+		return nil, apperrors.NewInternalError("繰り返しの展開に失敗しました", err)
+	}
+	if !ok {
+		return task, nil
+	}
+
+	next := task.NextOccurrence(due)
+	if err := s.prepareDue(ctx, next); err != nil {
+		return nil, err
+	}
+	next.AssignChecklistIDs()
+	next.AssignReminderIDs()
+	workflow, err := s.workflowFor(ctx, next.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveInitialStatus(workflow, next); err != nil {
+		return nil, err
+	}
+	created, err := s.taskRepo.Create(ctx, next)
+	if err != nil {
+		return nil, apperrors.NewInternalError("次の繰り返しタスクの作成に失敗しました", err)
+	}
+	if err := s.syncTask(ctx, created); err != nil {
+		return nil, err
+	}
+
+	task.Recurrence.NextTaskID = created.ID
+	updated, err := s.taskRepo.Update(ctx, task.ID.Hex(), task)
+	if err != nil {
+		return nil, apperrors.NewInternalError("タスクの更新に失敗しました", err)
+	}
+	return updated, nil
+}
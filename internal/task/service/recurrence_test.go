@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokyoTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	ts, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	require.NoError(t, err)
+	return ts
+}
+
+func createRecurringTask(t *testing.T, svc TaskService, rrule string) *model.Task {
+	t.Helper()
+	task, err := svc.CreateTask(context.Background(), &model.Task{
+		UserID:    "user1",
+		Title:     "ゴミ出し",
+		DueDate:   tokyoTime(t, "2025-01-06 09:00"),
+		Priority:  model.TaskPriorityHigh,
+		Checklist: []model.ChecklistItem{{Text: "分別する"}},
+		Recurrence: &model.Recurrence{
+			RRule:    rrule,
+			TimeZone: "Asia/Tokyo",
+		},
+	})
+	require.NoError(t, err)
+	return task
+}
+
+func completeTask(t *testing.T, svc TaskService, task *model.Task) *model.Task {
+	t.Helper()
+	task.Status = model.TaskStatusComplete
+	task.WorkflowStatus = ""
+	updated, err := svc.UpdateTask(context.Background(), task.ID.Hex(), task)
+	require.NoError(t, err)
+	return updated
+}
+
+func TestTaskService_RecurringTask(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("completion_creates_next_occurrence", func(t *testing.T) {
+		svc, repo := newSubtaskTestService(CompletionPolicyWarn)
+		task := createRecurringTask(t, svc, "FREQ=WEEKLY;BYDAY=MO")
+		assert.True(t, task.Recurrence.Start.Equal(task.DueDate), "起点は期限になる")
+
+		task.Checklist[0].Done = true
+		completed := completeTask(t, svc, task)
+		require.False(t, completed.Recurrence.NextTaskID.IsZero())
+		require.Len(t, repo.tasks, 2)
+
+		next, err := svc.GetTask(ctx, completed.Recurrence.NextTaskID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "ゴミ出し", next.Title)
+		assert.Equal(t, model.TaskPriorityHigh, next.Priority)
+		assert.Equal(t, model.TaskStatusPending, next.Status)
+		assert.True(t, next.DueDate.Equal(tokyoTime(t, "2025-01-13 09:00")))
+		assert.True(t, next.Recurrence.Start.Equal(task.Recurrence.Start))
+		assert.True(t, next.Recurrence.NextTaskID.IsZero())
+		require.Len(t, next.Checklist, 1)
+		assert.False(t, next.Checklist[0].Done)
+		assert.NotEqual(t, task.Checklist[0].ID, next.Checklist[0].ID)
+	})
+
+	t.Run("recompletion_does_not_duplicate", func(t *testing.T) {
+		svc, repo := newSubtaskTestService(CompletionPolicyWarn)
+		task := createRecurringTask(t, svc, "FREQ=DAILY")
+		completed := completeTask(t, svc, task)
+
+		_, _, err := svc.TransitionTask(ctx, completed.ID.Hex(), "user1", string(model.TaskStatusActive), "")
+		require.NoError(t, err)
+		reopened, err := svc.GetTask(ctx, completed.ID.Hex())
+		require.NoError(t, err)
+		completeTask(t, svc, reopened)
+
+		assert.Len(t, repo.tasks, 2)
+	})
+
+	t.Run("count_ends_series", func(t *testing.T) {
+		svc, repo := newSubtaskTestService(CompletionPolicyWarn)
+		task := createRecurringTask(t, svc, "FREQ=DAILY;COUNT=2")
+
+		completed := completeTask(t, svc, task)
+		second, err := svc.GetTask(ctx, completed.Recurrence.NextTaskID.Hex())
+		require.NoError(t, err)
+		assert.True(t, second.DueDate.Equal(tokyoTime(t, "2025-01-07 09:00")))
+
+		last := completeTask(t, svc, second)
+		assert.True(t, last.Recurrence.NextTaskID.IsZero())
+		assert.Len(t, repo.tasks, 2)
+	})
+
+	t.Run("transition_to_done_creates_next_occurrence", func(t *testing.T) {
+		svc, repo := newSubtaskTestService(CompletionPolicyWarn)
+		task := createRecurringTask(t, svc, "FREQ=MONTHLY;BYDAY=1MO")
+
+		completed, _, err := svc.TransitionTask(ctx, task.ID.Hex(), "user1", string(model.TaskStatusComplete), "")
+		require.NoError(t, err)
+		require.Len(t, repo.tasks, 2)
+
+		next, err := svc.GetTask(ctx, completed.Recurrence.NextTaskID.Hex())
+		require.NoError(t, err)
+		assert.True(t, next.DueDate.Equal(tokyoTime(t, "2025-02-03 09:00")))
+	})
+
+	t.Run("update_keeps_start_when_rule_unchanged", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+		task := createRecurringTask(t, svc, "FREQ=DAILY;COUNT=3")
+		start := task.Recurrence.Start
+
+		task.DueDate = tokyoTime(t, "2025-01-10 09:00")
+		task.Recurrence = &model.Recurrence{RRule: "FREQ=DAILY;COUNT=3", TimeZone: "Asia/Tokyo"}
+		updated, err := svc.UpdateTask(ctx, task.ID.Hex(), task)
+		require.NoError(t, err)
+		assert.True(t, updated.Recurrence.Start.Equal(start))
+
+		updated.Recurrence = &model.Recurrence{RRule: "FREQ=WEEKLY", TimeZone: "Asia/Tokyo"}
+		updated, err = svc.UpdateTask(ctx, updated.ID.Hex(), updated)
+		require.NoError(t, err)
+		assert.True(t, updated.Recurrence.Start.Equal(updated.DueDate), "ルールを変えると期限が新しい起点になる")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+		for _, recurrence := range []*model.Recurrence{
+			{RRule: "FREQ=YEARLY", TimeZone: "Asia/Tokyo"},
+			{RRule: "FREQ=DAILY", TimeZone: "Asia/Nowhere"},
+		} {
+			_, err := svc.CreateTask(ctx, &model.Task{
+				UserID:     "user1",
+				Title:      "task",
+				DueDate:    tokyoTime(t, "2025-01-06 09:00"),
+				Recurrence: recurrence,
+			})
+			assert.True(t, apperrors.IsInvalidInput(err), recurrence.RRule)
+		}
+	})
+}
+
+func TestTaskService_PreviewRecurrence(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+	start := tokyoTime(t, "2025-01-06 09:00")
+
+	t.Run("after", func(t *testing.T) {
+		occurrences, err := svc.PreviewRecurrence(ctx, &model.Recurrence{RRule: "FREQ=WEEKLY", TimeZone: "Asia/Tokyo", Start: start}, tokyoTime(t, "2025-02-01 00:00"), 3)
+		require.NoError(t, err)
+		require.Len(t, occurrences, 3)
+		assert.True(t, occurrences[0].Equal(tokyoTime(t, "2025-02-03 09:00")))
+		assert.True(t, occurrences[2].Equal(tokyoTime(t, "2025-02-17 09:00")))
+	})
+
+	t.Run("limit_is_capped", func(t *testing.T) {
+		occurrences, err := svc.PreviewRecurrence(ctx, &model.Recurrence{RRule: "FREQ=DAILY", TimeZone: "Asia/Tokyo", Start: start}, start, 1000)
+		require.NoError(t, err)
+		assert.Len(t, occurrences, model.MaxRecurrencePreview)
+	})
+
+	t.Run("defaults_to_upcoming", func(t *testing.T) {
+		occurrences, err := svc.PreviewRecurrence(ctx, &model.Recurrence{RRule: "FREQ=DAILY", TimeZone: "UTC"}, time.Time{}, 0)
+		require.NoError(t, err)
+		require.NotEmpty(t, occurrences)
+		assert.True(t, occurrences[0].After(time.Now()))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := svc.PreviewRecurrence(ctx, &model.Recurrence{RRule: "FREQ=DAILY;COUNT=0", TimeZone: "UTC", Start: start}, start, 10)
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+}
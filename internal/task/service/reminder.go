@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// prepareReminders はリマインダーを検証し、ID のないリマインダーに ID を割り当てます
+func prepareReminders(task *model.Task) error {
+	if err := model.ValidateReminders(task.Reminders); err != nil {
+		return apperrors.NewInvalidInputError("リマインダーが不正です", err)
+	}
+	task.AssignReminderIDs()
+	return nil
+}
+
+// syncReminders はタスクの期限・状態・リマインダーに合わせて未送信の通知を登録し直します
+func (s *taskService) syncReminders(ctx context.Context, task *model.Task) error {
+	if err := s.reminderRepo.Sync(ctx, task.ID, task.ReminderJobs(time.Now())); err != nil {
+		return apperrors.NewInternalError("リマインダーの登録に失敗しました", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryReminderRepository はテスト用のインメモリ実装です。送信はテストしないため Claim は常に空を返します
+type memoryReminderRepository struct {
+	jobs []*model.ReminderJob
+}
+
+func (r *memoryReminderRepository) Sync(_ context.Context, taskID primitive.ObjectID, jobs []*model.ReminderJob) error {
+	keep := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		keep[job.Key] = true
+	}
+	existing := make(map[string]bool)
+	var result []*model.ReminderJob
+	for _, job := range r.jobs {
+		if job.TaskID == taskID && job.State == model.ReminderStatePending && !keep[job.Key] {
+			continue
+		}
+		if job.TaskID == taskID {
+			existing[job.Key] = true
+		}
+		result = append(result, job)
+	}
+	for _, job := range jobs {
+		if !existing[job.Key] {
+			job.ID = primitive.NewObjectID()
+			result = append(result, job)
+		}
+	}
+	r.jobs = result
+	return nil
+}
+
+func (r *memoryReminderRepository) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	return r.Sync(ctx, taskID, nil)
+}
+
+func (r *memoryReminderRepository) Claim(context.Context, string, time.Time, time.Duration) (*model.ReminderJob, error) {
+	return nil, repository.ErrNoReminderDue
+}
+
+func (r *memoryReminderRepository) MarkDelivered(context.Context, primitive.ObjectID, string, model.NotificationChannel) error {
+	return nil
+}
+
+func (r *memoryReminderRepository) Retry(context.Context, primitive.ObjectID, string, time.Time, string) error {
+	return nil
+}
+
+func (r *memoryReminderRepository) Finish(context.Context, primitive.ObjectID, string, model.ReminderState, string) error {
+	return nil
+}
+
+func (r *memoryReminderRepository) pending(taskID primitive.ObjectID) []*model.ReminderJob {
+	var result []*model.ReminderJob
+	for _, job := range r.jobs {
+		if job.TaskID == taskID && job.State == model.ReminderStatePending {
+			result = append(result, job)
+		}
+	}
+	return result
+}
+
+func newReminderTestService() (TaskService, *memoryReminderRepository) {
+	reminders := &memoryReminderRepository{}
+	svc := NewTaskService(&memoryTaskRepository{}, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, reminders, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	return svc, reminders
+}
+
+func TestTaskService_Reminders(t *testing.T) {
+	ctx := context.Background()
+	due := time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+
+	createTask := func(t *testing.T, svc TaskService, reminders ...model.Reminder) *model.Task {
+		t.Helper()
+		task, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "提出", DueDate: due, Reminders: reminders})
+		require.NoError(t, err)
+		return task
+	}
+
+	t.Run("scheduled_on_create", func(t *testing.T) {
+		svc, reminders := newReminderTestService()
+		task := createTask(t, svc,
+			model.Reminder{MinutesBefore: 60},
+			model.Reminder{MinutesBefore: 24 * 60, Channels: []model.NotificationChannel{model.ChannelEmail}},
+		)
+		require.Len(t, task.Reminders, 2)
+		assert.NotEmpty(t, task.Reminders[0].ID)
+
+		jobs := reminders.pending(task.ID)
+		require.Len(t, jobs, 2)
+		assert.True(t, jobs[0].FireAt.Equal(due.Add(-time.Hour)))
+		assert.True(t, jobs[1].FireAt.Equal(due.Add(-24*time.Hour)))
+		assert.Equal(t, []model.NotificationChannel{model.ChannelEmail}, jobs[1].Channels)
+		assert.Equal(t, "user1", jobs[0].UserID)
+	})
+
+	t.Run("rescheduled_when_due_date_changes", func(t *testing.T) {
+		svc, reminders := newReminderTestService()
+		task := createTask(t, svc, model.Reminder{MinutesBefore: 30})
+		before := reminders.pending(task.ID)[0]
+
+		task.Title = "提出（修正）"
+		task, err := svc.UpdateTask(ctx, task.ID.Hex(), task)
+		require.NoError(t, err)
+		require.Len(t, reminders.pending(task.ID), 1)
+		assert.Equal(t, before.ID, reminders.pending(task.ID)[0].ID, "期限が変わらなければ通知は作り直さない")
+
+		task.DueDate = due.Add(time.Hour)
+		_, err = svc.UpdateTask(ctx, task.ID.Hex(), task)
+		require.NoError(t, err)
+		jobs := reminders.pending(task.ID)
+		require.Len(t, jobs, 1)
+		assert.True(t, jobs[0].FireAt.Equal(due.Add(30*time.Minute)))
+	})
+
+	t.Run("cancelled_on_complete_and_delete", func(t *testing.T) {
+		svc, reminders := newReminderTestService()
+		task := createTask(t, svc, model.Reminder{MinutesBefore: 30})
+
+		task.Status = model.TaskStatusComplete
+		task.WorkflowStatus = ""
+		_, err := svc.UpdateTask(ctx, task.ID.Hex(), task)
+		require.NoError(t, err)
+		assert.Empty(t, reminders.pending(task.ID))
+
+		other := createTask(t, svc, model.Reminder{MinutesBefore: 30})
+		require.Len(t, reminders.pending(other.ID), 1)
+		require.NoError(t, svc.DeleteTask(ctx, other.ID.Hex()))
+		assert.Empty(t, reminders.pending(other.ID))
+	})
+
+	t.Run("passed_fire_time_before_due_is_sent_immediately", func(t *testing.T) {
+		svc, reminders := newReminderTestService()
+		task := createTask(t, svc, model.Reminder{MinutesBefore: 7 * 24 * 60})
+
+		jobs := reminders.pending(task.ID)
+		require.Len(t, jobs, 1)
+		assert.True(t, jobs[0].AvailableAt.Before(time.Now()))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		svc, _ := newReminderTestService()
+		for name, reminders := range map[string][]model.Reminder{
+			"negative":      {{MinutesBefore: -1}},
+			"too_far":       {{MinutesBefore: model.MaxReminderMinutesBefore + 1}},
+			"bad_channel":   {{Channels: []model.NotificationChannel{"sms"}}},
+			"duplicate_ids": {{ID: "a"}, {ID: "a", MinutesBefore: 5}},
+			"too_many":      make([]model.Reminder, model.MaxRemindersPerTask+1),
+		} {
+			_, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "task", DueDate: due, Reminders: reminders})
+			assert.True(t, apperrors.IsInvalidInput(err), name)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+)
+
+// GetTaskStats は期限切れを現在時刻で判定します
+func (s *taskService) GetTaskStats(ctx context.Context, userID string, query model.TaskStatsQuery) (*model.TaskStats, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	return s.taskRepo.Stats(ctx, userID, query, time.Now())
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Stats はリポジトリの集計パイプラインの代わりに model.ComputeTaskStats で統計を求めます
+func (r *memoryTaskRepository) Stats(_ context.Context, userID string, query model.TaskStatsQuery, now time.Time) (*model.TaskStats, error) {
+	var tasks []*model.Task
+	for _, task := range r.tasks {
+		if task.UserID == userID {
+			tasks = append(tasks, task)
+		}
+	}
+	return model.ComputeTaskStats(tasks, query, now), nil
+}
+
+func TestTaskService_GetTaskStats(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newBatchTestService()
+	now := time.Now()
+	day := func(n int) time.Time { return now.AddDate(0, 0, n) }
+	add := func(userID string, status model.TaskStatus, created, completed, due time.Time) {
+		repo.tasks = append(repo.tasks, &model.Task{ID: primitive.NewObjectID(), UserID: userID, Status: status, CreatedAt: created, CompletedAt: completed, DueDate: due})
+	}
+	add("user1", model.TaskStatusPending, day(-2), time.Time{}, day(-1))
+	add("user1", model.TaskStatusActive, day(-1), time.Time{}, day(1))
+	add("user1", model.TaskStatusComplete, day(-3), day(-1), day(-5))
+	add("user1", model.TaskStatusComplete, day(-1), day(-1).Add(2*time.Hour), time.Time{})
+	// 完了日時を記録する前に完了したタスクは件数にだけ含む
+	add("user1", model.TaskStatusComplete, day(-60), time.Time{}, time.Time{})
+	add("user2", model.TaskStatusPending, day(-1), time.Time{}, day(-1))
+
+	query, err := model.NewTaskStatsQuery("", "", time.UTC, now)
+	require.NoError(t, err)
+	stats, err := svc.GetTaskStats(ctx, "user1", query)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(5), stats.Total)
+	assert.Equal(t, int64(3), stats.CountsByStatus[model.TaskStatusComplete])
+	assert.Equal(t, int64(1), stats.Overdue)
+	assert.Equal(t, int64(4), stats.Created)
+	assert.Equal(t, 0.5, stats.CompletionRate())
+	assert.Equal(t, int64(2), stats.Completed)
+	assert.Equal(t, 25*time.Hour, stats.AverageLeadTime)
+	require.Len(t, stats.DailyCompletions, model.DefaultStatsDays)
+	assert.Equal(t, model.DailyCount{Date: day(-1).UTC().Format(model.StatsDateLayout), Count: 2}, stats.DailyCompletions[model.DefaultStatsDays-2])
+
+	_, err = svc.GetTaskStats(ctx, "", query)
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperrors.Unauthorized, appErr.Type)
+}
+
+func TestTaskService_CompletedAt(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newBatchTestService()
+
+	created, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "task", Status: model.TaskStatusPending, DueDate: testDueDate})
+	require.NoError(t, err)
+	assert.True(t, created.CompletedAt.IsZero())
+
+	completed, err := svc.UpdateTask(ctx, created.ID.Hex(), &model.Task{UserID: "user1", Title: "task", Status: model.TaskStatusComplete, DueDate: testDueDate})
+	require.NoError(t, err)
+	require.False(t, completed.CompletedAt.IsZero())
+
+	// 完了のまま更新しても完了日時は変わらない
+	renamed, err := svc.UpdateTask(ctx, created.ID.Hex(), &model.Task{UserID: "user1", Title: "renamed", Status: model.TaskStatusComplete, DueDate: testDueDate})
+	require.NoError(t, err)
+	assert.Equal(t, completed.CompletedAt, renamed.CompletedAt)
+
+	reopened, err := svc.UpdateTask(ctx, created.ID.Hex(), &model.Task{UserID: "user1", Title: "renamed", Status: model.TaskStatusActive, DueDate: testDueDate})
+	require.NoError(t, err)
+	assert.True(t, reopened.CompletedAt.IsZero())
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SubtaskCompletionPolicy は未完了のサブタスクを持つタスクを完了にしたときの振る舞いです
+type SubtaskCompletionPolicy string
+
+const (
+	// CompletionPolicyBlock は完了を拒否します
+	CompletionPolicyBlock SubtaskCompletionPolicy = "block"
+	// CompletionPolicyWarn は完了を許可し、レスポンスで警告します
+	CompletionPolicyWarn SubtaskCompletionPolicy = "warn"
+	// CompletionPolicyCascade は配下のサブタスクもすべて完了にします
+	CompletionPolicyCascade SubtaskCompletionPolicy = "cascade"
+)
+
+// ParseSubtaskCompletionPolicy は設定値を完了ポリシーに変換します。空の場合は warn です
+func ParseSubtaskCompletionPolicy(s string) (SubtaskCompletionPolicy, error) {
+	switch policy := SubtaskCompletionPolicy(s); policy {
+	case "":
+		return CompletionPolicyWarn, nil
+	case CompletionPolicyBlock, CompletionPolicyWarn, CompletionPolicyCascade:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown subtask completion policy: %q", s)
+	}
+}
+
+func (s *taskService) ListSubtasks(ctx context.Context, id string, userID string) ([]*model.Task, error) {
+	if _, err := s.ownedTask(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	children, err := s.taskRepo.FindChildren(ctx, id)
+	if err != nil {
+		return nil, apperrors.NewInternalError("サブタスクの取得に失敗しました", err)
+	}
+	if err := s.attachProgress(ctx, children...); err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+func (s *taskService) MoveTask(ctx context.Context, id string, userID string, parentID string) (*model.Task, error) {
+	task, err := s.ownedTask(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var newParentID primitive.ObjectID
+	if parentID != "" {
+		parent, err := s.ownedTask(ctx, parentID, userID)
+		if err != nil {
+			if apperrors.IsNotFound(err) || apperrors.IsInvalidInput(err) {
+				return nil, apperrors.NewInvalidInputError("親タスクが見つかりません", err)
+			}
+			return nil, err
+		}
+		chain, err := s.ancestry(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		for _, ancestorID := range chain {
+			if ancestorID == task.ID {
+				return nil, apperrors.NewInvalidInputError("タスクを自身またはサブタスクの下へ移動することはできません", nil)
+			}
+		}
+		height, err := s.subtreeHeight(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		if len(chain)+height > model.MaxSubtaskDepth {
+			return nil, apperrors.NewInvalidInputError(fmt.Sprintf("サブタスクの階層は%d段までです", model.MaxSubtaskDepth), nil)
+		}
+		newParentID = parent.ID
+	}
+
+	moved, err := s.taskRepo.SetParent(ctx, id, newParentID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("タスクが見つかりません", err)
+		}
+		return nil, apperrors.NewInternalError("タスクの移動に失敗しました", err)
+	}
+	if err := s.attachProgress(ctx, moved); err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+// prepareSubtask はサブタスクとして作成するタスクの親を検証します
+func (s *taskService) prepareSubtask(ctx context.Context, task *model.Task) error {
+	parent, err := s.findParent(ctx, task.UserID, task.ParentID.Hex())
+	if err != nil {
+		return err
+	}
+	chain, err := s.ancestry(ctx, parent)
+	if err != nil {
+		return err
+	}
+	if len(chain)+1 > model.MaxSubtaskDepth {
+		return apperrors.NewInvalidInputError(fmt.Sprintf("サブタスクの階層は%d段までです", model.MaxSubtaskDepth), nil)
+	}
+	return nil
+}
+
+// findParent は親として指定されたタスクを取得し、同じユーザーのタスクかを確認します
+func (s *taskService) findParent(ctx context.Context, userID string, parentID string) (*model.Task, error) {
+	parent, err := s.taskRepo.FindByID(ctx, parentID)
+	if err != nil {
+		if apperrors.IsNotFound(err) || apperrors.IsInvalidInput(err) {
+			return nil, apperrors.NewInvalidInputError("親タスクが見つかりません", err)
+		}
+		return nil, apperrors.NewInternalError("親タスクの取得に失敗しました", err)
+	}
+	if parent.UserID != userID {
+		return nil, apperrors.NewInvalidInputError("親タスクが見つかりません", nil)
+	}
+	return parent, nil
+}
+
+// ancestry は task 自身から順にルートまでの ID を返します。返す要素数がそのタスクの階層になります
+func (s *taskService) ancestry(ctx context.Context, task *model.Task) ([]primitive.ObjectID, error) {
+	chain := []primitive.ObjectID{task.ID}
+	current := task
+	for current.HasParent() {
+		// 既存データに循環があっても止まるよう、上限を超えたら打ち切る
+		if len(chain) > model.MaxSubtaskDepth {
+			return nil, apperrors.NewInternalError("サブタスクの階層が上限を超えています", nil)
+		}
+		parent, err := s.taskRepo.FindByID(ctx, current.ParentID.Hex())
+		if err != nil {
+			if apperrors.IsNotFound(err) {
+				break
+			}
+			return nil, apperrors.NewInternalError("親タスクの取得に失敗しました", err)
+		}
+		chain = append(chain, parent.ID)
+		current = parent
+	}
+	return chain, nil
+}
+
+// subtreeHeight は task をルートとするサブツリーの段数を返します。サブタスクがなければ 1 です
+func (s *taskService) subtreeHeight(ctx context.Context, task *model.Task) (int, error) {
+	height := 1
+	level := []*model.Task{task}
+	for height <= model.MaxSubtaskDepth {
+		var next []*model.Task
+		for _, t := range level {
+			children, err := s.taskRepo.FindChildren(ctx, t.ID.Hex())
+			if err != nil {
+				return 0, apperrors.NewInternalError("サブタスクの取得に失敗しました", err)
+			}
+			next = append(next, children...)
+		}
+		if len(next) == 0 {
+			break
+		}
+		height++
+		level = next
+	}
+	return height, nil
+}
+
+// applyCompletionPolicy は未完了のサブタスクを持つタスクを完了にする前に、設定されたポリシーを適用します
+func (s *taskService) applyCompletionPolicy(ctx context.Context, workflow *model.Workflow, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewInvalidInputError("無効なIDです", err)
+	}
+	counts, err := s.taskRepo.CountSubtasks(ctx, []primitive.ObjectID{objectID})
+	if err != nil {
+		return apperrors.NewInternalError("サブタスクの集計に失敗しました", err)
+	}
+	count := counts[objectID]
+	if count.Completed >= count.Total {
+		return nil
+	}
+
+	switch s.completionPolicy {
+	case CompletionPolicyBlock:
+		return apperrors.NewFailedPreconditionError(
+			fmt.Sprintf("未完了のサブタスクが%d件あるため完了にできません", count.Total-count.Completed), nil)
+	case CompletionPolicyCascade:
+		return s.completeDescendants(ctx, workflow, id)
+	default:
+		return nil
+	}
+}
+
+// cascadeReason は親タスクの完了に伴ってサブタスクを完了にしたときに履歴に記録する理由です
+const cascadeReason = "親タスクの完了に伴う自動完了"
+
+// completeDescendants は配下の未完了のサブタスクを、直接完了にする場合と同じ確認と後処理を通してすべて完了にします。
+// 各サブタスクは完了の分類の最初のステータスへ移し、ブロッカーとその遷移の条件を確認します。
+// 完了にできないサブタスクが1件でもあれば、どのサブタスクも変更せずにエラーを返します。
+// 完了にした親の下に未完了のサブタスクが残らないよう、繰り返しのサブタスクの次の回は作成しません
+func (s *taskService) completeDescendants(ctx context.Context, workflow *model.Workflow, id string) error {
+	done := workflow.StatusForCategory(model.StatusCategoryDone)
+	open, err := s.openDescendants(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// 途中まで完了にしたまま失敗しないよう、先にすべてのサブタスクを確認する
+	for _, child := range open {
+		if err := s.checkCascadeCompletion(ctx, workflow, child, done); err != nil {
+			return err
+		}
+	}
+
+	// 親より先にサブタスクを完了にするよう、下の階層から処理する
+	now := time.Now()
+	for i := len(open) - 1; i >= 0; i-- {
+		child := open[i]
+		next := *child
+		next.Status = model.TaskStatusComplete
+		next.WorkflowStatus = done
+		next.StampCompletion(child, now)
+		updated, err := s.taskRepo.Update(ctx, child.ID.Hex(), &next)
+		if err != nil {
+			return apperrors.NewInternalError("サブタスクの更新に失敗しました", err)
+		}
+		_, err = s.workflowRepo.RecordTransition(ctx, &model.TaskTransition{
+			TaskID: child.ID,
+			UserID: child.UserID,
+			From:   workflow.CurrentStatus(child),
+			To:     done,
+			Reason: cascadeReason,
+		})
+		if err != nil {
+			return apperrors.NewInternalError("ステータス遷移の記録に失敗しました", err)
+		}
+		if updated, err = s.completeTask(ctx, updated, false); err != nil {
+			return err
+		}
+		if err := s.syncTask(ctx, updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openDescendants は配下の未完了のサブタスクを上の階層から順に返します
+func (s *taskService) openDescendants(ctx context.Context, id string) ([]*model.Task, error) {
+	var open []*model.Task
+	queue := []string{id}
+	for depth := 0; len(queue) > 0 && depth < model.MaxSubtaskDepth; depth++ {
+		var next []string
+		for _, parentID := range queue {
+			children, err := s.taskRepo.FindChildren(ctx, parentID)
+			if err != nil {
+				return nil, apperrors.NewInternalError("サブタスクの取得に失敗しました", err)
+			}
+			for _, child := range children {
+				next = append(next, child.ID.Hex())
+				if child.Status != model.TaskStatusComplete {
+					open = append(open, child)
+				}
+			}
+		}
+		queue = next
+	}
+	return open, nil
+}
+
+// checkCascadeCompletion はサブタスクを完了にできるかを確認します。ワークフローに完了への遷移が定義されていない場合も完了にしますが、
+// 定義されている場合はその条件を確認します。配下のサブタスクはまとめて完了にするため、未完了のサブタスクの条件は確認しません
+func (s *taskService) checkCascadeCompletion(ctx context.Context, workflow *model.Workflow, child *model.Task, done string) error {
+	next := *child
+	next.Status = model.TaskStatusComplete
+	next.WorkflowStatus = done
+
+	err := s.ensureUnblocked(ctx, child)
+	if err == nil {
+		if transition := workflow.Transition(workflow.CurrentStatus(child), done); transition != nil {
+			guarded := *transition
+			guarded.Guards = nil
+			for _, guard := range transition.Guards {
+				if guard != model.GuardNoOpenSubtasks {
+					guarded.Guards = append(guarded.Guards, guard)
+				}
+			}
+			err = s.checkGuards(ctx, &guarded, child, &next, cascadeReason)
+		}
+	}
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) && appErr.Type == apperrors.FailedPrecondition {
+		return apperrors.NewFailedPreconditionError(fmt.Sprintf("サブタスク「%s」を完了にできません: %s", child.Title, appErr.Message), nil)
+	}
+	return err
+}
+
+// attachProgress は各タスクに直下のサブタスクとチェックリストの進捗を設定します
+func (s *taskService) attachProgress(ctx context.Context, tasks ...*model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]primitive.ObjectID, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	counts, err := s.taskRepo.CountSubtasks(ctx, ids)
+	if err != nil {
+		return apperrors.NewInternalError("サブタスクの集計に失敗しました", err)
+	}
+	for _, task := range tasks {
+		task.ApplyProgress(counts[task.ID])
+	}
+	return nil
+}
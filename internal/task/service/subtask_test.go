@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 以下は memoryTaskRepository のうち、サブタスクの操作に必要なメソッドです
+
+func (r *memoryTaskRepository) Create(_ context.Context, task *model.Task) (*model.Task, error) {
+	task.ID = primitive.NewObjectID()
+	task.Rank = model.AppendRank(task.ID)
+	stored := *task
+	r.tasks = append(r.tasks, &stored)
+	copied := stored
+	return &copied, nil
+}
+
+func (r *memoryTaskRepository) find(id string) *model.Task {
+	for _, task := range r.tasks {
+		if task.ID.Hex() == id {
+			return task
+		}
+	}
+	return nil
+}
+
+func (r *memoryTaskRepository) FindByID(_ context.Context, id string) (*model.Task, error) {
+	task := r.find(id)
+	if task == nil {
+		return nil, repository.ErrTaskNotFound
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (r *memoryTaskRepository) Update(_ context.Context, id string, task *model.Task) (*model.Task, error) {
+	stored := r.find(id)
+	if stored == nil {
+		return nil, repository.ErrTaskNotFound
+	}
+	stored.Title = task.Title
+	stored.Status = task.Status
+	stored.WorkflowStatus = task.WorkflowStatus
+	stored.ProjectID = task.ProjectID
+	stored.DueDate = task.DueDate
+	stored.Recurrence = task.Recurrence
+	stored.Reminders = task.Reminders
+	stored.Checklist = task.Checklist
+	stored.CompletedAt = task.CompletedAt
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *memoryTaskRepository) Delete(_ context.Context, id string) error {
+	for i, task := range r.tasks {
+		if task.ID.Hex() == id {
+			r.tasks = append(r.tasks[:i], r.tasks[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrTaskNotFound
+}
+
+func (r *memoryTaskRepository) FindChildren(_ context.Context, parentID string) ([]*model.Task, error) {
+	children := []*model.Task{}
+	for _, task := range r.tasks {
+		if task.HasParent() && task.ParentID.Hex() == parentID {
+			copied := *task
+			children = append(children, &copied)
+		}
+	}
+	return children, nil
+}
+
+func (r *memoryTaskRepository) CountSubtasks(_ context.Context, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]model.SubtaskCount, error) {
+	counts := make(map[primitive.ObjectID]model.SubtaskCount)
+	for _, id := range parentIDs {
+		for _, task := range r.tasks {
+			if task.ParentID != id || !task.HasParent() {
+				continue
+			}
+			count := counts[id]
+			count.Total++
+			if task.Status == model.TaskStatusComplete {
+				count.Completed++
+			}
+			counts[id] = count
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryTaskRepository) SetParent(_ context.Context, id string, parentID primitive.ObjectID) (*model.Task, error) {
+	stored := r.find(id)
+	if stored == nil {
+		return nil, repository.ErrTaskNotFound
+	}
+	stored.ParentID = parentID
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *memoryTaskRepository) ReparentChildren(_ context.Context, fromID primitive.ObjectID, toID primitive.ObjectID) error {
+	for _, task := range r.tasks {
+		if task.HasParent() && task.ParentID == fromID {
+			task.ParentID = toID
+		}
+	}
+	return nil
+}
+
+func newSubtaskTestService(policy SubtaskCompletionPolicy) (TaskService, *memoryTaskRepository) {
+	repo := &memoryTaskRepository{}
+	return NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, policy), repo
+}
+
+// createChain は深さ n のタスクの列を作成し、ルートから順に返します
+func createChain(t *testing.T, svc TaskService, n int) []*model.Task {
+	t.Helper()
+	var chain []*model.Task
+	var parentID primitive.ObjectID
+	for i := 0; i < n; i++ {
+		task, err := svc.CreateTask(context.Background(), &model.Task{
+			UserID:   "user1",
+			Title:    "task",
+			Status:   model.TaskStatusPending,
+			DueDate:  testDueDate,
+			ParentID: parentID,
+		})
+		require.NoError(t, err)
+		chain = append(chain, task)
+		parentID = task.ID
+	}
+	return chain
+}
+
+func TestTaskService_CreateSubtask(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("depth_limit", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+		chain := createChain(t, svc, model.MaxSubtaskDepth)
+
+		_, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "too deep", ParentID: chain[len(chain)-1].ID, DueDate: testDueDate})
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("parent_of_other_user", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+		chain := createChain(t, svc, 1)
+
+		_, err := svc.CreateTask(ctx, &model.Task{UserID: "user2", Title: "child", ParentID: chain[0].ID, DueDate: testDueDate})
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("parent_not_found", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+
+		_, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "child", ParentID: primitive.NewObjectID(), DueDate: testDueDate})
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("checklist", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+
+		created, err := svc.CreateTask(ctx, &model.Task{
+			UserID:    "user1",
+			Title:     "with checklist",
+			DueDate:   testDueDate,
+			Checklist: []model.ChecklistItem{{Text: "a", Done: true}, {Text: "b"}},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.Checklist[0].ID)
+		assert.NotEqual(t, created.Checklist[0].ID, created.Checklist[1].ID)
+
+		fetched, err := svc.GetTask(ctx, created.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, model.SubtaskCount{Total: 2, Completed: 1}, fetched.Progress.Checklist)
+
+		_, err = svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "blank", Checklist: []model.ChecklistItem{{Text: " "}}})
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+}
+
+func TestTaskService_ListSubtasks(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+	chain := createChain(t, svc, 2)
+	done, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "done", Status: model.TaskStatusComplete, ParentID: chain[0].ID, DueDate: testDueDate})
+	require.NoError(t, err)
+
+	children, err := svc.ListSubtasks(ctx, chain[0].ID.Hex(), "user1")
+	require.NoError(t, err)
+	assert.Len(t, children, 2)
+	assert.Equal(t, chain[1].ID, children[0].ID)
+	assert.Equal(t, done.ID, children[1].ID)
+
+	parent, err := svc.GetTask(ctx, chain[0].ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, model.SubtaskCount{Total: 2, Completed: 1}, parent.Progress.Subtasks)
+
+	_, err = svc.ListSubtasks(ctx, primitive.NewObjectID().Hex(), "user1")
+	assert.True(t, apperrors.IsNotFound(err))
+	_, err = svc.ListSubtasks(ctx, chain[0].ID.Hex(), "user2")
+	assert.True(t, apperrors.IsPermissionDenied(err))
+}
+
+func TestTaskService_MoveTask(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cycle", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+		chain := createChain(t, svc, 3)
+
+		_, err := svc.MoveTask(ctx, chain[0].ID.Hex(), "user1", chain[2].ID.Hex())
+		assert.True(t, apperrors.IsInvalidInput(err))
+		_, err = svc.MoveTask(ctx, chain[1].ID.Hex(), "user1", chain[1].ID.Hex())
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("depth_includes_subtree", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+		deep := createChain(t, svc, model.MaxSubtaskDepth-1)
+		subtree := createChain(t, svc, 2)
+
+		_, err := svc.MoveTask(ctx, subtree[0].ID.Hex(), "user1", deep[len(deep)-1].ID.Hex())
+		assert.True(t, apperrors.IsInvalidInput(err))
+
+		moved, err := svc.MoveTask(ctx, subtree[1].ID.Hex(), "user1", deep[len(deep)-1].ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, deep[len(deep)-1].ID, moved.ParentID)
+	})
+
+	t.Run("other_owner", func(t *testing.T) {
+		svc, repo := newSubtaskTestService(CompletionPolicyWarn)
+		mine := createChain(t, svc, 2)
+		theirs, err := svc.CreateTask(ctx, &model.Task{UserID: "user2", Title: "theirs", DueDate: testDueDate})
+		require.NoError(t, err)
+
+		// 他人のタスクは移動できない
+		_, err = svc.MoveTask(ctx, theirs.ID.Hex(), "user1", mine[0].ID.Hex())
+		assert.True(t, apperrors.IsPermissionDenied(err))
+		_, err = svc.MoveTask(ctx, mine[1].ID.Hex(), "user2", "")
+		assert.True(t, apperrors.IsPermissionDenied(err))
+
+		// 他人のタスクの下へは移動できない
+		_, err = svc.MoveTask(ctx, mine[1].ID.Hex(), "user1", theirs.ID.Hex())
+		assert.True(t, apperrors.IsPermissionDenied(err))
+		stored, err := repo.FindByID(ctx, mine[1].ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, mine[0].ID, stored.ParentID)
+
+		_, err = svc.MoveTask(ctx, mine[1].ID.Hex(), "user1", primitive.NewObjectID().Hex())
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("to_root", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+		chain := createChain(t, svc, 2)
+
+		moved, err := svc.MoveTask(ctx, chain[1].ID.Hex(), "user1", "")
+		require.NoError(t, err)
+		assert.False(t, moved.HasParent())
+	})
+}
+
+func TestTaskService_CompleteParent(t *testing.T) {
+	ctx := context.Background()
+	complete := func(svc TaskService, task *model.Task) (*model.Task, error) {
+		return svc.UpdateTask(ctx, task.ID.Hex(), &model.Task{UserID: task.UserID, Title: task.Title, Status: model.TaskStatusComplete, DueDate: testDueDate})
+	}
+
+	t.Run("block", func(t *testing.T) {
+		svc, repo := newSubtaskTestService(CompletionPolicyBlock)
+		chain := createChain(t, svc, 2)
+
+		_, err := complete(svc, chain[0])
+		assert.True(t, apperrors.IsFailedPrecondition(err))
+		assert.Equal(t, model.TaskStatusPending, repo.find(chain[0].ID.Hex()).Status)
+
+		_, err = complete(svc, chain[1])
+		require.NoError(t, err)
+		_, err = complete(svc, chain[0])
+		assert.NoError(t, err)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		svc, _ := newSubtaskTestService(CompletionPolicyWarn)
+		chain := createChain(t, svc, 2)
+
+		updated, err := complete(svc, chain[0])
+		require.NoError(t, err)
+		assert.Equal(t, model.TaskStatusComplete, updated.Status)
+		assert.True(t, updated.Progress.HasOpenSubtasks())
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		svc, repo := newSubtaskTestService(CompletionPolicyCascade)
+		chain := createChain(t, svc, 4)
+
+		updated, err := complete(svc, chain[0])
+		require.NoError(t, err)
+		assert.False(t, updated.Progress.HasOpenSubtasks())
+		for _, task := range chain {
+			assert.Equal(t, model.TaskStatusComplete, repo.find(task.ID.Hex()).Status)
+		}
+	})
+
+	t.Run("cascade_uses_completion_path", func(t *testing.T) {
+		repo := &memoryTaskRepository{}
+		timers := &recordingTimers{}
+		svc := NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, timers, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyCascade)
+		chain := createChain(t, svc, 2)
+		recurring, err := svc.CreateTask(ctx, &model.Task{
+			UserID:     "user1",
+			Title:      "daily",
+			DueDate:    testDueDate,
+			ParentID:   chain[0].ID,
+			Recurrence: &model.Recurrence{RRule: "FREQ=DAILY", TimeZone: "Asia/Tokyo"},
+		})
+		require.NoError(t, err)
+
+		updated, err := complete(svc, chain[0])
+		require.NoError(t, err)
+		assert.False(t, updated.Progress.HasOpenSubtasks(), "繰り返しのサブタスクの次の回は作成しない")
+		assert.ElementsMatch(t, []primitive.ObjectID{chain[0].ID, chain[1].ID, recurring.ID}, timers.stopped, "サブタスクのタイマーも止める")
+		assert.False(t, repo.find(chain[1].ID.Hex()).CompletedAt.IsZero())
+	})
+
+	t.Run("cascade_blocked_subtask", func(t *testing.T) {
+		repo := &memoryTaskRepository{}
+		deps := &memoryDependencyRepository{}
+		svc := NewTaskService(repo, newNoFieldsRepository(), deps, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyCascade)
+		chain := createChain(t, svc, 3)
+		blocker, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "blocker", DueDate: testDueDate})
+		require.NoError(t, err)
+		_, err = deps.Create(ctx, &model.TaskDependency{TaskID: chain[2].ID, BlockerID: blocker.ID, UserID: "user1"})
+		require.NoError(t, err)
+
+		_, err = complete(svc, chain[0])
+		assert.True(t, apperrors.IsFailedPrecondition(err))
+		for _, task := range chain {
+			assert.Equal(t, model.TaskStatusPending, repo.find(task.ID.Hex()).Status, "どのタスクも完了にしない")
+		}
+	})
+}
+
+// recordingTimers はタイマーを止めたタスクを記録するテスト用の TimerStopper です
+type recordingTimers struct {
+	stopped []primitive.ObjectID
+}
+
+func (r *recordingTimers) StopTaskTimer(_ context.Context, taskID primitive.ObjectID) error {
+	r.stopped = append(r.stopped, taskID)
+	return nil
+}
+
+func TestTaskService_DeleteTask_PromotesSubtasks(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newSubtaskTestService(CompletionPolicyWarn)
+	chain := createChain(t, svc, 3)
+
+	require.NoError(t, svc.DeleteTask(ctx, chain[1].ID.Hex()))
+	assert.Equal(t, chain[0].ID, repo.find(chain[2].ID.Hex()).ParentID)
+}
+
+func TestParseSubtaskCompletionPolicy(t *testing.T) {
+	policy, err := ParseSubtaskCompletionPolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, CompletionPolicyWarn, policy)
+
+	policy, err = ParseSubtaskCompletionPolicy("cascade")
+	assert.NoError(t, err)
+	assert.Equal(t, CompletionPolicyCascade, policy)
+
+	_, err = ParseSubtaskCompletionPolicy("ignore")
+	assert.Error(t, err)
+}
@@ -2,38 +2,127 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/my-backend-project/internal/pb"
 	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/pkg/logger"
+	"github.com/my-backend-project/internal/pkg/pagination"
 	"github.com/my-backend-project/internal/task/model"
 	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+	"github.com/my-backend-project/internal/task/watch"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
 )
 
 type TaskService interface {
 	CreateTask(ctx context.Context, task *model.Task) (*model.Task, error)
 	GetTask(ctx context.Context, id string) (*model.Task, error)
-	ListTasks(ctx context.Context, userID string, status *model.TaskStatus, limit int32, offset string) ([]*model.Task, int32, error)
+	ListTasks(ctx context.Context, userID string, filter *model.TaskFilter, pageSize int32, pageToken string) (*model.TaskPage, error)
 	UpdateTask(ctx context.Context, id string, task *model.Task) (*model.Task, error)
 	DeleteTask(ctx context.Context, id string) error
+	SearchTasks(ctx context.Context, userID string, query string, pageSize int32) ([]*search.Hit, error)
+	// ListSubtasks は userID のユーザーのタスクの直下のサブタスクを返します
+	ListSubtasks(ctx context.Context, id string, userID string) ([]*model.Task, error)
+	// MoveTask は userID のユーザーのタスクを同じユーザーの別の親の下へ移動します。parentID が空の場合はルートのタスクにします
+	MoveTask(ctx context.Context, id string, userID string, parentID string) (*model.Task, error)
+	// MoveTaskOnBoard はタスクをボードの列 move.WorkflowStatus の中の指定した位置へ移動します。
+	// 列が変わる場合は TransitionTask と同じくワークフローに従ってステータスを変更します
+	MoveTaskOnBoard(ctx context.Context, id string, userID string, move model.BoardMove) (*model.Task, error)
+	// AddDependency は blockerID のタスクが完了するまで taskID のタスクを開始できないようにします。
+	// 依存関係の操作では、どちらのタスクも userID のユーザーのタスクである必要があります
+	AddDependency(ctx context.Context, taskID string, userID string, blockerID string) (*model.TaskDependency, error)
+	RemoveDependency(ctx context.Context, taskID string, userID string, blockerID string) error
+	ListDependencies(ctx context.Context, taskID string, userID string) (*model.TaskDependencies, error)
+	// ListNextTasks は未完了のタスクを依存関係を踏まえた着手順に並べて返します
+	ListNextTasks(ctx context.Context, userID string, pageSize int32) ([]*model.NextTask, error)
+	// TransitionTask はワークフローに従ってタスクのステータスを変更し、理由とともに履歴に記録します
+	TransitionTask(ctx context.Context, id string, userID string, to string, reason string) (*model.Task, *model.TaskTransition, error)
+	// ListTaskTransitions は userID のユーザーのタスクのステータス遷移の履歴を返します
+	ListTaskTransitions(ctx context.Context, id string, userID string) ([]*model.TaskTransition, error)
+	// PreviewRecurrence は繰り返しの設定から after より後の発生日時を最大 limit 件返します
+	PreviewRecurrence(ctx context.Context, recurrence *model.Recurrence, after time.Time, limit int32) ([]time.Time, error)
+	// WatchTasks は userID のユーザーのタスクの変更の購読を開始します。resumeToken を指定するとそのイベントの次から配信します
+	WatchTasks(ctx context.Context, userID string, resumeToken string) (*watch.Subscription, error)
+	// BatchCreateTasks は userID のユーザーのタスクをまとめて作成し、項目ごとの結果を返します。
+	// atomic の場合は1件でも失敗するとすべて取り消します
+	BatchCreateTasks(ctx context.Context, userID string, tasks []*model.Task, atomic bool) (*model.BatchResult, error)
+	// BatchUpdateTasks は selector に一致する userID のユーザーのタスクに patch を適用します
+	BatchUpdateTasks(ctx context.Context, userID string, selector *model.TaskSelector, patch *model.TaskPatch, atomic bool) (*model.BatchResult, error)
+	// BatchDeleteTasks は selector に一致する userID のユーザーのタスクを削除します
+	BatchDeleteTasks(ctx context.Context, userID string, selector *model.TaskSelector, atomic bool) (*model.BatchResult, error)
+	// ExportTasks は filter に一致する userID のユーザーのタスクを ListTasks と同じ並び順で1件ずつ fn に渡します
+	ExportTasks(ctx context.Context, userID string, filter *model.TaskFilter, fn func(*model.Task) error) error
+	// GetTaskStats は userID のユーザーのタスクの件数・完了率・リードタイム・日ごとの完了件数を query の期間について返します
+	GetTaskStats(ctx context.Context, userID string, query model.TaskStatsQuery) (*model.TaskStats, error)
 }
 
 type taskService struct {
-	taskRepo repository.TaskRepository
+	taskRepo  repository.TaskRepository
+	fieldRepo repository.CustomFieldRepository
+	depRepo   repository.DependencyRepository
+	// workflowRepo はステータスの遷移規則と遷移の履歴を扱います
+	workflowRepo repository.WorkflowRepository
+	// reminderRepo はリマインダーの送信予定を扱います。送信は通知ワーカーが行います
+	reminderRepo repository.ReminderRepository
+	// projectRepo はタスクの属するプロジェクトの確認と、アーカイブしたプロジェクトのタスクを一覧から除くために使います
+	projectRepo repository.ProjectRepository
+	// attachments はタスクの削除時に添付ファイルを削除します。nil の場合は添付ファイルを扱いません
+	attachments AttachmentCleaner
+	// timers はタスクの完了時に実行中のタイマーを止めます。nil の場合は止めません
+	timers TimerStopper
+	// users は終日のタスクの期限日と期限の絞り込みに使うユーザーのタイムゾーンを参照します。nil の場合は UTC とします
+	users repository.UserDirectory
+	// tx は一括操作をアトミックに行うためのトランザクションです。nil の場合はアトミックな一括操作を使えません
+	tx         repository.Transactor
+	pageTokens *pagination.Codec
+	searcher   search.Backend
+	// watcher はタスクの変更の配信元です。nil の場合は WatchTasks を使えません
+	watcher watch.Source
+	// completionPolicy は未完了のサブタスクを持つタスクを完了にしたときの振る舞いです
+	completionPolicy SubtaskCompletionPolicy
 }
 
-func NewTaskService(taskRepo repository.TaskRepository) TaskService {
+func NewTaskService(taskRepo repository.TaskRepository, fieldRepo repository.CustomFieldRepository, depRepo repository.DependencyRepository, workflowRepo repository.WorkflowRepository, reminderRepo repository.ReminderRepository, projectRepo repository.ProjectRepository, attachments AttachmentCleaner, timers TimerStopper, users repository.UserDirectory, tx repository.Transactor, pageTokens *pagination.Codec, searcher search.Backend, watcher watch.Source, completionPolicy SubtaskCompletionPolicy) TaskService {
 	return &taskService{
-		taskRepo: taskRepo,
+		taskRepo:         taskRepo,
+		fieldRepo:        fieldRepo,
+		depRepo:          depRepo,
+		workflowRepo:     workflowRepo,
+		reminderRepo:     reminderRepo,
+		projectRepo:      projectRepo,
+		attachments:      attachments,
+		timers:           timers,
+		users:            users,
+		tx:               tx,
+		pageTokens:       pageTokens,
+		searcher:         searcher,
+		watcher:          watcher,
+		completionPolicy: completionPolicy,
 	}
 }
 
 func (s *taskService) CreateTask(ctx context.Context, task *model.Task) (*model.Task, error) {
+	if err := s.validateTask(ctx, task); err != nil {
+		return nil, err
+	}
+	workflow, err := s.workflowFor(ctx, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prepareCreate(ctx, workflow, task); err != nil {
+		return nil, err
+	}
+
 	createdTask, err := s.taskRepo.Create(ctx, task)
 	if err != nil {
 		return nil, apperrors.NewInternalError("タスクの作成に失敗しました", err)
 	}
+	if err := s.syncTask(ctx, createdTask); err != nil {
+		return nil, err
+	}
 	return createdTask, nil
 }
 
@@ -45,18 +134,81 @@ func (s *taskService) GetTask(ctx context.Context, id string) (*model.Task, erro
 		}
 		return nil, apperrors.NewInternalError("タスクの取得に失敗しました", err)
 	}
+	if err := s.attachProgress(ctx, task); err != nil {
+		return nil, err
+	}
 	return task, nil
 }
 
-func (s *taskService) ListTasks(ctx context.Context, userID string, status *model.TaskStatus, limit int32, offset string) ([]*model.Task, int32, error) {
-	tasks, total, err := s.taskRepo.FindByUserID(ctx, userID, status, limit, offset)
+func (s *taskService) ListTasks(ctx context.Context, userID string, filter *model.TaskFilter, pageSize int32, pageToken string) (*model.TaskPage, error) {
+	filterHash := filter.Fingerprint(userID)
+
+	var after *model.TaskCursor
+	if pageToken != "" {
+		var cursor model.TaskCursor
+		if err := s.pageTokens.Decode(pageToken, &cursor); err != nil {
+			return nil, apperrors.NewInvalidInputError("無効なページトークンです", err)
+		}
+		if cursor.FilterHash != filterHash {
+			return nil, apperrors.NewInvalidInputError("ページトークンが検索条件と一致しません", nil)
+		}
+		after = &cursor
+	}
+
+	query, err := s.resolveFilter(ctx, userID, filter)
 	if err != nil {
-		return nil, 0, apperrors.NewInternalError("タスク一覧の取得に失敗しました", err)
+		return nil, err
 	}
-	return tasks, total, nil
+
+	// 次のページの有無を判定するため1件多く取得する
+	limit := pagination.PageSize(pageSize)
+	tasks, total, err := s.taskRepo.FindByUserID(ctx, userID, query, limit+1, after)
+	if err != nil {
+		if apperrors.IsInvalidInput(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("タスク一覧の取得に失敗しました", err)
+	}
+
+	page := &model.TaskPage{Tasks: tasks, TotalCount: total}
+	if int32(len(tasks)) > limit {
+		page.Tasks = tasks[:limit]
+		last := page.Tasks[limit-1]
+		page.NextPageToken, err = s.pageTokens.Encode(&model.TaskCursor{
+			FilterHash: filterHash,
+			LastValue:  last.SortValue(filter.SortField()),
+			LastID:     last.ID,
+		})
+		if err != nil {
+			return nil, apperrors.NewInternalError("ページトークンの生成に失敗しました", err)
+		}
+	}
+	if err := s.attachProgress(ctx, page.Tasks...); err != nil {
+		return nil, err
+	}
+
+	return page, nil
 }
 
 func (s *taskService) UpdateTask(ctx context.Context, id string, task *model.Task) (*model.Task, error) {
+	if err := s.validateTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	// 更新するユーザーは task.UserID で渡されるため、他のユーザーのタスクは書き換えさせない
+	current, err := s.ownedTask(ctx, id, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+	workflow, err := s.workflowFor(ctx, current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	change, err := s.prepareUpdate(ctx, workflow, current, task)
+	if err != nil {
+		return nil, err
+	}
+
 	updatedTask, err := s.taskRepo.Update(ctx, id, task)
 	if err != nil {
 		if apperrors.IsNotFound(err) {
@@ -64,17 +216,244 @@ func (s *taskService) UpdateTask(ctx context.Context, id string, task *model.Tas
 		}
 		return nil, apperrors.NewInternalError("タスクの更新に失敗しました", err)
 	}
+	if updatedTask, err = s.finishUpdate(ctx, change, updatedTask); err != nil {
+		return nil, err
+	}
+	if err := s.attachProgress(ctx, updatedTask); err != nil {
+		return nil, err
+	}
 	return updatedTask, nil
 }
 
 func (s *taskService) DeleteTask(ctx context.Context, id string) error {
-	err := s.taskRepo.Delete(ctx, id)
+	task, err := s.findTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.taskRepo.Delete(ctx, id)
 	if err != nil {
 		if apperrors.IsNotFound(err) {
 			return apperrors.NewNotFoundError("タスクが見つかりません", err)
 		}
 		return apperrors.NewInternalError("タスクの削除に失敗しました", err)
 	}
+	// 削除したタスクのサブタスクは一つ上の階層へ引き上げる
+	if err := s.finishDelete(ctx, task, task.ParentID); err != nil {
+		return err
+	}
+	return s.cleanupDeleted(ctx, task)
+}
+
+// resolveFilter はアーカイブしたプロジェクトのタスクを除き、期限の定型条件にユーザーのタイムゾーンを補った filter を返します
+func (s *taskService) resolveFilter(ctx context.Context, userID string, filter *model.TaskFilter) (*model.TaskFilter, error) {
+	filter, err := s.hideArchivedProjects(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyUserTimeZone(ctx, userID, filter)
+}
+
+// validateTask は作成・更新するタスクのうち、既存のタスクに依存しない項目を検証します
+func (s *taskService) validateTask(ctx context.Context, task *model.Task) error {
+	if err := s.validateCustomFields(ctx, task); err != nil {
+		return err
+	}
+	if err := model.ValidateChecklist(task.Checklist); err != nil {
+		return apperrors.NewInvalidInputError("チェックリストが不正です", err)
+	}
+	task.AssignChecklistIDs()
+	return prepareReminders(task)
+}
+
+// validateModel は期限とステータスを決めたタスクをモデルの規則で検証します
+func validateModel(task *model.Task) error {
+	if err := task.Validate(); err != nil {
+		return apperrors.NewInvalidInputError(err.Error(), nil)
+	}
+	return nil
+}
+
+// prepareCreate は作成するタスクのプロジェクトを確認し、期限・繰り返し・ステータス・完了日時・親を決めます
+func (s *taskService) prepareCreate(ctx context.Context, workflow *model.Workflow, task *model.Task) error {
+	if err := s.checkProject(ctx, nil, task); err != nil {
+		return err
+	}
+	if err := s.prepareDue(ctx, task); err != nil {
+		return err
+	}
+	if err := prepareRecurrence(task, nil); err != nil {
+		return err
+	}
+	if err := resolveInitialStatus(workflow, task); err != nil {
+		return err
+	}
+	if err := validateModel(task); err != nil {
+		return err
+	}
+	task.StampCompletion(nil, time.Now())
+	if task.HasParent() {
+		return s.prepareSubtask(ctx, task)
+	}
+	return nil
+}
+
+// syncTask は作成・更新したタスクを検索インデックスとリマインダーに反映します。トランザクション内では確定後に反映します
+func (s *taskService) syncTask(ctx context.Context, task *model.Task) error {
+	return afterCommit(ctx, func(ctx context.Context) error {
+		if err := s.searcher.Index(ctx, task); err != nil {
+			return apperrors.NewInternalError("検索インデックスの更新に失敗しました", err)
+		}
+		return s.syncReminders(ctx, task)
+	})
+}
+
+// afterCommit は ctx がトランザクション内であれば確定後に fn を実行し、そうでなければ直ちに実行してエラーを返します。
+// 検索インデックス・リマインダー・添付ファイルはトランザクションに参加しないため、取り消された書き込みを反映しないよう確定後に実行します。
+// 確定後の失敗は書き込み自体を取り消せないため、ログに残します
+func afterCommit(ctx context.Context, fn func(ctx context.Context) error) error {
+	var err error
+	deferred := false
+	repository.AfterCommit(ctx, func(ctx context.Context) {
+		err = fn(ctx)
+		if deferred && err != nil {
+			logger.Error("Failed to apply task changes after commit", zap.Error(err))
+		}
+	})
+	deferred = true
+	return err
+}
+
+// statusChange は更新によるステータスの変化です
+type statusChange struct {
+	current  *model.Task
+	from, to string
+}
+
+// prepareUpdate は移動先のプロジェクトを確認し、期限をそろえ、更新後のステータスと完了日時を決め、ステータスが変わる場合はワークフローで許可されているかを確認します
+func (s *taskService) prepareUpdate(ctx context.Context, workflow *model.Workflow, current, task *model.Task) (*statusChange, error) {
+	if err := s.checkProject(ctx, current, task); err != nil {
+		return nil, err
+	}
+	if err := s.prepareDue(ctx, task); err != nil {
+		return nil, err
+	}
+	if err := prepareRecurrence(task, current); err != nil {
+		return nil, err
+	}
+	from := workflow.CurrentStatus(current)
+	to, err := resolveTargetStatus(workflow, current, task, from)
+	if err != nil {
+		return nil, err
+	}
+	// 所有者は更新で変わらないため、リクエストで省略していても検証を通す
+	task.UserID = current.UserID
+	if err := validateModel(task); err != nil {
+		return nil, err
+	}
+	if to != from {
+		if err := s.checkStatusChange(ctx, workflow, current, task, from, to, ""); err != nil {
+			return nil, err
+		}
+	}
+	task.StampCompletion(current, time.Now())
+	return &statusChange{current: current, from: from, to: to}, nil
+}
+
+// finishUpdate はステータスの遷移を記録し、タスクの完了を処理して、検索インデックスとリマインダーに反映します
+func (s *taskService) finishUpdate(ctx context.Context, change *statusChange, updated *model.Task) (*model.Task, error) {
+	updated, err := s.recordUpdate(ctx, change, updated)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.syncTask(ctx, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// recordUpdate はステータスの遷移を記録し、タスクの完了を処理します
+func (s *taskService) recordUpdate(ctx context.Context, change *statusChange, updated *model.Task) (*model.Task, error) {
+	if change.to != change.from {
+		_, err := s.workflowRepo.RecordTransition(ctx, &model.TaskTransition{
+			TaskID: change.current.ID,
+			UserID: updated.UserID,
+			From:   change.from,
+			To:     change.to,
+		})
+		if err != nil {
+			return nil, apperrors.NewInternalError("ステータス遷移の記録に失敗しました", err)
+		}
+	}
+	if updated.Status == model.TaskStatusComplete && change.current.Status != model.TaskStatusComplete {
+		return s.completeTask(ctx, updated, true)
+	}
+	return updated, nil
+}
+
+// completeTask は完了したタスクの実行中のタイマーを止め、scheduleNext の場合は繰り返しタスクの次の回を作成します
+func (s *taskService) completeTask(ctx context.Context, task *model.Task, scheduleNext bool) (*model.Task, error) {
+	if s.timers != nil {
+		if err := s.timers.StopTaskTimer(ctx, task.ID); err != nil {
+			return nil, err
+		}
+	}
+	if !scheduleNext {
+		return task, nil
+	}
+	return s.scheduleNextOccurrence(ctx, task)
+}
+
+// finishDelete は削除したタスクのサブタスクを newParentID の直下へ移し、依存関係とリマインダーから取り除きます
+func (s *taskService) finishDelete(ctx context.Context, task *model.Task, newParentID primitive.ObjectID) error {
+	if err := s.taskRepo.ReparentChildren(ctx, task.ID, newParentID); err != nil {
+		return apperrors.NewInternalError("サブタスクの移動に失敗しました", err)
+	}
+	if err := s.depRepo.DeleteByTask(ctx, task.ID); err != nil {
+		return apperrors.NewInternalError("依存関係の削除に失敗しました", err)
+	}
+	if err := s.reminderRepo.DeleteByTask(ctx, task.ID); err != nil {
+		return apperrors.NewInternalError("リマインダーの削除に失敗しました", err)
+	}
+	return nil
+}
+
+// cleanupDeleted は削除したタスクの添付ファイルを削除し、検索インデックスから取り除きます。トランザクション内では確定後に行います
+func (s *taskService) cleanupDeleted(ctx context.Context, task *model.Task) error {
+	return afterCommit(ctx, func(ctx context.Context) error {
+		if s.attachments != nil {
+			if err := s.attachments.DeleteTaskAttachments(ctx, task.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.searcher.Remove(ctx, task.ID.Hex()); err != nil {
+			return apperrors.NewInternalError("検索インデックスの更新に失敗しました", err)
+		}
+		return nil
+	})
+}
+
+func (s *taskService) SearchTasks(ctx context.Context, userID string, query string, pageSize int32) ([]*search.Hit, error) {
+	if len(search.Terms(query)) == 0 {
+		return nil, apperrors.NewInvalidInputError("検索語は必須です", nil)
+	}
+
+	hits, err := s.searcher.Search(ctx, userID, search.Query{Text: query, Limit: pagination.PageSize(pageSize)})
+	if err != nil {
+		return nil, apperrors.NewInternalError("タスクの検索に失敗しました", err)
+	}
+	return hits, nil
+}
+
+// validateCustomFields はタスクのカスタムフィールドの値を所有者の定義と照合します
+func (s *taskService) validateCustomFields(ctx context.Context, task *model.Task) error {
+	definitions, err := s.fieldRepo.FindByOwnerID(ctx, task.UserID)
+	if err != nil {
+		return apperrors.NewInternalError("カスタムフィールドの取得に失敗しました", err)
+	}
+	if err := model.ValidateCustomFields(task.CustomFields, definitions); err != nil {
+		return apperrors.NewInvalidInputError("カスタムフィールドが不正です", err)
+	}
 	return nil
 }
 
@@ -85,6 +464,8 @@ func ModelToProto(task *model.Task) *pb.Task {
 		Title:       task.Title,
 		Description: task.Description,
 		Status:      pb.TaskStatus(pb.TaskStatus_value[string(task.Status)]),
+		Priority:    pb.TaskPriority(task.Priority),
+		Labels:      task.Labels,
 		UserId:      task.UserID,
 		CreatedAt:   model.TimeToProtoTimestamp(task.CreatedAt),
 		UpdatedAt:   model.TimeToProtoTimestamp(task.UpdatedAt),
@@ -103,8 +484,20 @@ func ProtoToModel(task *pb.Task) (*model.Task, error) {
 		Title:       task.Title,
 		Description: task.Description,
 		Status:      model.TaskStatus(pb.TaskStatus_name[int32(task.Status)]),
+		Priority:    model.TaskPriority(task.Priority),
+		Labels:      task.Labels,
 		UserID:      task.UserId,
 		CreatedAt:   model.ProtoTimestampToTime(task.CreatedAt),
 		UpdatedAt:   model.ProtoTimestampToTime(task.UpdatedAt),
 	}, nil
 }
+
+func (s *taskService) WatchTasks(ctx context.Context, userID string, resumeToken string) (*watch.Subscription, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	if s.watcher == nil {
+		return nil, apperrors.NewFailedPreconditionError("タスクの変更の配信は利用できません", nil)
+	}
+	return s.watcher.Subscribe(ctx, userID, resumeToken)
+}
@@ -7,13 +7,21 @@ import (
 
 	"github.com/my-backend-project/internal/pb"
 	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/pkg/pagination"
 	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/search"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var testCodec = pagination.NewCodec([]byte("test-secret"))
+
+// testDueDate はテストで作成するタスクの期限です
+var testDueDate = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
 type mockTaskRepository struct {
 	mock.Mock
 }
@@ -34,8 +42,8 @@ func (m *mockTaskRepository) FindByID(ctx context.Context, id string) (*model.Ta
 	return args.Get(0).(*model.Task), args.Error(1)
 }
 
-func (m *mockTaskRepository) FindByUserID(ctx context.Context, userID string, status *model.TaskStatus, limit int32, offset string) ([]*model.Task, int32, error) {
-	args := m.Called(ctx, userID, status, limit, offset)
+func (m *mockTaskRepository) FindByUserID(ctx context.Context, userID string, filter *model.TaskFilter, limit int32, after *model.TaskCursor) ([]*model.Task, int32, error) {
+	args := m.Called(ctx, userID, filter, limit, after)
 	if args.Get(0) == nil {
 		return nil, 0, args.Error(2)
 	}
@@ -55,9 +63,165 @@ func (m *mockTaskRepository) Delete(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
+func (m *mockTaskRepository) FindChildren(ctx context.Context, parentID string) ([]*model.Task, error) {
+	args := m.Called(ctx, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Task), args.Error(1)
+}
+
+func (m *mockTaskRepository) CountSubtasks(ctx context.Context, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]model.SubtaskCount, error) {
+	args := m.Called(ctx, parentIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[primitive.ObjectID]model.SubtaskCount), args.Error(1)
+}
+
+func (m *mockTaskRepository) SetParent(ctx context.Context, id string, parentID primitive.ObjectID) (*model.Task, error) {
+	args := m.Called(ctx, id, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Task), args.Error(1)
+}
+
+func (m *mockTaskRepository) ReparentChildren(ctx context.Context, fromID primitive.ObjectID, toID primitive.ObjectID) error {
+	args := m.Called(ctx, fromID, toID)
+	return args.Error(0)
+}
+
+func (m *mockTaskRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*model.Task, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Task), args.Error(1)
+}
+
+func (m *mockTaskRepository) ForEachByUserID(ctx context.Context, userID string, filter *model.TaskFilter, fn func(*model.Task) error) error {
+	args := m.Called(ctx, userID, filter, fn)
+	return args.Error(0)
+}
+
+func (m *mockTaskRepository) BulkCreate(ctx context.Context, tasks []*model.Task) ([]error, error) {
+	args := m.Called(ctx, tasks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *mockTaskRepository) BulkUpdate(ctx context.Context, tasks []*model.Task) ([]error, error) {
+	args := m.Called(ctx, tasks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *mockTaskRepository) BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]error, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *mockTaskRepository) SetRank(ctx context.Context, id string, rank string) (*model.Task, error) {
+	args := m.Called(ctx, id, rank)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Task), args.Error(1)
+}
+
+func (m *mockTaskRepository) FindAdjacentRank(ctx context.Context, userID string, rank string, after bool) (string, error) {
+	args := m.Called(ctx, userID, rank, after)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockTaskRepository) FindLastRank(ctx context.Context, userID string, workflowStatus string) (string, error) {
+	args := m.Called(ctx, userID, workflowStatus)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockTaskRepository) RebalanceRanks(ctx context.Context, userID string) ([]primitive.ObjectID, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]primitive.ObjectID), args.Error(1)
+}
+
+func (m *mockTaskRepository) Stats(ctx context.Context, userID string, query model.TaskStatsQuery, now time.Time) (*model.TaskStats, error) {
+	args := m.Called(ctx, userID, query, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TaskStats), args.Error(1)
+}
+
+// newMockTaskRepository はサブタスクを持たないタスクだけを扱うモックを返します
+func newMockTaskRepository() *mockTaskRepository {
+	repo := new(mockTaskRepository)
+	repo.On("CountSubtasks", mock.Anything, mock.Anything).Return(map[primitive.ObjectID]model.SubtaskCount{}, nil).Maybe()
+	repo.On("ReparentChildren", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return repo
+}
+
+type mockCustomFieldRepository struct {
+	mock.Mock
+}
+
+func (m *mockCustomFieldRepository) Create(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error) {
+	args := m.Called(ctx, field)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomFieldDefinition), args.Error(1)
+}
+
+func (m *mockCustomFieldRepository) FindByID(ctx context.Context, ownerID string, id string) (*model.CustomFieldDefinition, error) {
+	args := m.Called(ctx, ownerID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomFieldDefinition), args.Error(1)
+}
+
+func (m *mockCustomFieldRepository) FindByOwnerID(ctx context.Context, ownerID string) ([]*model.CustomFieldDefinition, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.CustomFieldDefinition), args.Error(1)
+}
+
+func (m *mockCustomFieldRepository) Update(ctx context.Context, field *model.CustomFieldDefinition) (*model.CustomFieldDefinition, error) {
+	args := m.Called(ctx, field)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomFieldDefinition), args.Error(1)
+}
+
+func (m *mockCustomFieldRepository) Delete(ctx context.Context, ownerID string, id string) error {
+	args := m.Called(ctx, ownerID, id)
+	return args.Error(0)
+}
+
+// newNoFieldsRepository はカスタムフィールドが1つも定義されていない状態を返すモックです
+func newNoFieldsRepository() *mockCustomFieldRepository {
+	repo := new(mockCustomFieldRepository)
+	repo.On("FindByOwnerID", mock.Anything, mock.Anything).Return([]*model.CustomFieldDefinition{}, nil)
+	return repo
+}
+
 func TestTaskService_CreateTask(t *testing.T) {
-	mockRepo := new(mockTaskRepository)
-	service := NewTaskService(mockRepo)
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
 
 	t.Run("success", func(t *testing.T) {
 		ctx := context.Background()
@@ -108,9 +272,91 @@ func TestTaskService_CreateTask(t *testing.T) {
 	})
 }
 
+func TestTaskService_CreateTask_CustomFields(t *testing.T) {
+	ctx := context.Background()
+	severity := &model.CustomFieldDefinition{
+		ID:       primitive.NewObjectID(),
+		OwnerID:  "user1",
+		Name:     "重要度",
+		Type:     model.CustomFieldSingleSelect,
+		Options:  []string{"S1", "S2"},
+		Required: true,
+	}
+	fieldRepo := new(mockCustomFieldRepository)
+	fieldRepo.On("FindByOwnerID", ctx, "user1").Return([]*model.CustomFieldDefinition{severity}, nil)
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, fieldRepo, &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+
+	tests := []struct {
+		name   string
+		values map[string]model.CustomFieldValue
+	}{
+		{name: "missing_required", values: nil},
+		{name: "unknown_field", values: map[string]model.CustomFieldValue{
+			severity.ID.Hex():             {Type: model.CustomFieldSingleSelect, Option: "S1"},
+			primitive.NewObjectID().Hex(): {Type: model.CustomFieldText, Text: "x"},
+		}},
+		{name: "wrong_type", values: map[string]model.CustomFieldValue{
+			severity.ID.Hex(): {Type: model.CustomFieldText, Text: "S1"},
+		}},
+		{name: "unknown_option", values: map[string]model.CustomFieldValue{
+			severity.ID.Hex(): {Type: model.CustomFieldSingleSelect, Option: "S9"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &model.Task{UserID: "user1", Title: "Task", DueDate: testDueDate, CustomFields: tt.values}
+			created, err := service.CreateTask(ctx, task)
+			assert.Error(t, err)
+			assert.Nil(t, created)
+			assert.True(t, apperrors.IsInvalidInput(err))
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		task := &model.Task{
+			UserID:       "user1",
+			Title:        "Task",
+			DueDate:      testDueDate,
+			CustomFields: map[string]model.CustomFieldValue{severity.ID.Hex(): {Type: model.CustomFieldSingleSelect, Option: "S2"}},
+		}
+		expected := *task
+		expected.ID = primitive.NewObjectID()
+		mockRepo.On("Create", ctx, task).Return(&expected, nil).Once()
+
+		created, err := service.CreateTask(ctx, task)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.ID, created.ID)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskService_ValidatesTaskModel(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newBatchTestService()
+
+	for name, task := range map[string]*model.Task{
+		"no_due":           {UserID: "user1", Title: "task"},
+		"invalid_priority": {UserID: "user1", Title: "task", DueDate: testDueDate, Priority: model.TaskPriority(9)},
+		"blank_label":      {UserID: "user1", Title: "task", DueDate: testDueDate, Labels: []string{""}},
+	} {
+		_, err := svc.CreateTask(ctx, task)
+		assert.True(t, apperrors.IsInvalidInput(err), name)
+	}
+	assert.Empty(t, repo.tasks)
+
+	created, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "task", DueDate: testDueDate})
+	require.NoError(t, err)
+	_, err = svc.UpdateTask(ctx, created.ID.Hex(), &model.Task{UserID: "user1", Title: "", Status: model.TaskStatusActive, DueDate: testDueDate})
+	assert.True(t, apperrors.IsInvalidInput(err))
+	_, err = svc.UpdateTask(ctx, created.ID.Hex(), &model.Task{UserID: "user1", Title: "task", Status: model.TaskStatusActive})
+	assert.True(t, apperrors.IsInvalidInput(err))
+	assert.Equal(t, "task", repo.find(created.ID.Hex()).Title)
+}
+
 func TestTaskService_GetTask(t *testing.T) {
-	mockRepo := new(mockTaskRepository)
-	service := NewTaskService(mockRepo)
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
 
 	t.Run("success", func(t *testing.T) {
 		ctx := context.Background()
@@ -151,69 +397,116 @@ func TestTaskService_GetTask(t *testing.T) {
 }
 
 func TestTaskService_ListTasks(t *testing.T) {
-	mockRepo := new(mockTaskRepository)
-	service := NewTaskService(mockRepo)
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	nilCursor := (*model.TaskCursor)(nil)
+
+	newTasks := func(n int) []*model.Task {
+		tasks := make([]*model.Task, n)
+		for i := range tasks {
+			tasks[i] = &model.Task{
+				ID:        primitive.NewObjectID(),
+				UserID:    "user1",
+				Title:     "Task",
+				Status:    model.TaskStatusPending,
+				CreatedAt: time.Now(),
+			}
+		}
+		return tasks
+	}
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("last_page", func(t *testing.T) {
 		ctx := context.Background()
-		userID := "user1"
-		status := model.TaskStatusPending
-		limit := int32(10)
-		offset := ""
-
-		expectedTasks := []*model.Task{
-			{
-				ID:          primitive.NewObjectID(),
-				UserID:      userID,
-				Title:       "Task 1",
-				Description: "Description 1",
-				Status:      status,
-				DueDate:     time.Now(),
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
-			},
-			{
-				ID:          primitive.NewObjectID(),
-				UserID:      userID,
-				Title:       "Task 2",
-				Description: "Description 2",
-				Status:      status,
-				DueDate:     time.Now(),
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
-			},
-		}
+		filter := &model.TaskFilter{Statuses: []model.TaskStatus{model.TaskStatusPending}}
+
+		mockRepo.On("FindByUserID", ctx, "user1", filter, int32(11), nilCursor).Return(newTasks(2), int32(2), nil).Once()
+
+		page, err := service.ListTasks(ctx, "user1", filter, 10, "")
+		assert.NoError(t, err)
+		assert.Len(t, page.Tasks, 2)
+		assert.Equal(t, int32(2), page.TotalCount)
+		assert.Empty(t, page.NextPageToken)
+		mockRepo.AssertExpectations(t)
+	})
 
-		mockRepo.On("FindByUserID", ctx, userID, &status, limit, offset).Return(expectedTasks, int32(2), nil).Once()
+	t.Run("has_next_page", func(t *testing.T) {
+		ctx := context.Background()
+		filter := &model.TaskFilter{}
+		tasks := newTasks(4)
+
+		mockRepo.On("FindByUserID", ctx, "user1", filter, int32(4), nilCursor).Return(tasks, int32(7), nil).Once()
 
-		tasks, total, err := service.ListTasks(ctx, userID, &status, limit, offset)
+		page, err := service.ListTasks(ctx, "user1", filter, 3, "")
 		assert.NoError(t, err)
-		assert.NotNil(t, tasks)
-		assert.Equal(t, int32(2), total)
-		assert.Len(t, tasks, 2)
+		assert.Len(t, page.Tasks, 3)
+		assert.Equal(t, int32(7), page.TotalCount)
+		assert.NotEmpty(t, page.NextPageToken)
+
+		var cursor model.TaskCursor
+		assert.NoError(t, testCodec.Decode(page.NextPageToken, &cursor))
+		assert.Equal(t, tasks[2].ID, cursor.LastID)
+		assert.Equal(t, filter.Fingerprint("user1"), cursor.FilterHash)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("default_and_max_page_size", func(t *testing.T) {
+		ctx := context.Background()
+		filter := &model.TaskFilter{}
+
+		mockRepo.On("FindByUserID", ctx, "user1", filter, pagination.DefaultPageSize+1, nilCursor).Return(newTasks(0), int32(0), nil).Once()
+		mockRepo.On("FindByUserID", ctx, "user1", filter, pagination.MaxPageSize+1, nilCursor).Return(newTasks(0), int32(0), nil).Once()
+
+		_, err := service.ListTasks(ctx, "user1", filter, 0, "")
+		assert.NoError(t, err)
+		_, err = service.ListTasks(ctx, "user1", filter, 100000, "")
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid_token", func(t *testing.T) {
+		ctx := context.Background()
+
+		page, err := service.ListTasks(ctx, "user1", &model.TaskFilter{}, 10, primitive.NewObjectID().Hex())
+		assert.Error(t, err)
+		assert.Nil(t, page)
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("token_for_other_filter", func(t *testing.T) {
+		ctx := context.Background()
+		token, err := testCodec.Encode(&model.TaskCursor{
+			FilterHash: (&model.TaskFilter{}).Fingerprint("user1"),
+			LastID:     primitive.NewObjectID(),
+		})
+		assert.NoError(t, err)
+
+		page, err := service.ListTasks(ctx, "user1", &model.TaskFilter{Query: "other"}, 10, token)
+		assert.Error(t, err)
+		assert.Nil(t, page)
+		assert.True(t, apperrors.IsInvalidInput(err))
+
+		page, err = service.ListTasks(ctx, "user2", &model.TaskFilter{}, 10, token)
+		assert.Error(t, err)
+		assert.Nil(t, page)
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
 	t.Run("repository_error", func(t *testing.T) {
 		ctx := context.Background()
-		userID := "user1"
-		status := model.TaskStatusPending
-		limit := int32(10)
-		offset := ""
+		filter := &model.TaskFilter{}
 
-		mockRepo.On("FindByUserID", ctx, userID, &status, limit, offset).Return(nil, int32(0), apperrors.NewInternalError("repository error", nil)).Once()
+		mockRepo.On("FindByUserID", ctx, "user1", filter, int32(11), nilCursor).Return(nil, int32(0), apperrors.NewInternalError("repository error", nil)).Once()
 
-		tasks, total, err := service.ListTasks(ctx, userID, &status, limit, offset)
+		page, err := service.ListTasks(ctx, "user1", filter, 10, "")
 		assert.Error(t, err)
-		assert.Nil(t, tasks)
-		assert.Equal(t, int32(0), total)
+		assert.Nil(t, page)
 		mockRepo.AssertExpectations(t)
 	})
 }
 
 func TestTaskService_UpdateTask(t *testing.T) {
-	mockRepo := new(mockTaskRepository)
-	service := NewTaskService(mockRepo)
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
 
 	t.Run("success", func(t *testing.T) {
 		ctx := context.Background()
@@ -237,6 +530,7 @@ func TestTaskService_UpdateTask(t *testing.T) {
 			UpdatedAt:   time.Now(),
 		}
 
+		mockRepo.On("FindByID", ctx, taskID.Hex()).Return(&model.Task{ID: taskID, UserID: "user1", Status: model.TaskStatusActive}, nil).Once()
 		mockRepo.On("Update", ctx, taskID.Hex(), task).Return(expectedTask, nil).Once()
 
 		updatedTask, err := service.UpdateTask(ctx, taskID.Hex(), task)
@@ -257,7 +551,7 @@ func TestTaskService_UpdateTask(t *testing.T) {
 			DueDate:     time.Now(),
 		}
 
-		mockRepo.On("Update", ctx, taskID.Hex(), task).Return(nil, apperrors.NewNotFoundError("タスクが見つかりません", nil)).Once()
+		mockRepo.On("FindByID", ctx, taskID.Hex()).Return(nil, apperrors.NewNotFoundError("タスクが見つかりません", nil)).Once()
 
 		updatedTask, err := service.UpdateTask(ctx, taskID.Hex(), task)
 		assert.Error(t, err)
@@ -265,16 +559,35 @@ func TestTaskService_UpdateTask(t *testing.T) {
 		assert.True(t, apperrors.IsNotFound(err))
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("other_owner", func(t *testing.T) {
+		ctx := context.Background()
+		taskID := primitive.NewObjectID()
+		task := &model.Task{
+			UserID:  "user2",
+			Title:   "Updated Task",
+			Status:  model.TaskStatusActive,
+			DueDate: time.Now(),
+		}
+
+		mockRepo.On("FindByID", ctx, taskID.Hex()).Return(&model.Task{ID: taskID, UserID: "user1", Status: model.TaskStatusActive}, nil).Once()
+
+		updatedTask, err := service.UpdateTask(ctx, taskID.Hex(), task)
+		assert.Nil(t, updatedTask)
+		assert.True(t, apperrors.IsPermissionDenied(err))
+		mockRepo.AssertNotCalled(t, "Update", ctx, taskID.Hex(), task)
+	})
 }
 
 func TestTaskService_DeleteTask(t *testing.T) {
-	mockRepo := new(mockTaskRepository)
-	service := NewTaskService(mockRepo)
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
 
 	t.Run("success", func(t *testing.T) {
 		ctx := context.Background()
 		taskID := primitive.NewObjectID()
 
+		mockRepo.On("FindByID", ctx, taskID.Hex()).Return(&model.Task{ID: taskID, UserID: "user1"}, nil).Once()
 		mockRepo.On("Delete", ctx, taskID.Hex()).Return(nil).Once()
 
 		err := service.DeleteTask(ctx, taskID.Hex())
@@ -286,7 +599,7 @@ func TestTaskService_DeleteTask(t *testing.T) {
 		ctx := context.Background()
 		taskID := primitive.NewObjectID()
 
-		mockRepo.On("Delete", ctx, taskID.Hex()).Return(apperrors.NewNotFoundError("タスクが見つかりません", nil)).Once()
+		mockRepo.On("FindByID", ctx, taskID.Hex()).Return(nil, apperrors.NewNotFoundError("タスクが見つかりません", nil)).Once()
 
 		err := service.DeleteTask(ctx, taskID.Hex())
 		assert.Error(t, err)
@@ -295,6 +608,70 @@ func TestTaskService_DeleteTask(t *testing.T) {
 	})
 }
 
+func TestTaskService_SearchTasks(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	ctx := context.Background()
+
+	created := &model.Task{
+		ID:          primitive.NewObjectID(),
+		UserID:      "user1",
+		Title:       "Quarterly report",
+		Description: "Collect numbers",
+		Status:      model.TaskStatusPending,
+		DueDate:     testDueDate,
+	}
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Task")).Return(created, nil).Once()
+	_, err := service.CreateTask(ctx, &model.Task{UserID: "user1", Title: "Quarterly report", DueDate: testDueDate})
+	assert.NoError(t, err)
+
+	t.Run("indexed_on_create", func(t *testing.T) {
+		hits, err := service.SearchTasks(ctx, "user1", "report", 10)
+		assert.NoError(t, err)
+		assert.Len(t, hits, 1)
+		assert.Equal(t, created.ID, hits[0].Task.ID)
+	})
+
+	t.Run("other_user", func(t *testing.T) {
+		hits, err := service.SearchTasks(ctx, "user2", "report", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("reindexed_on_update", func(t *testing.T) {
+		updated := *created
+		updated.Title = "Quarterly summary"
+		mockRepo.On("FindByID", ctx, created.ID.Hex()).Return(created, nil).Once()
+		mockRepo.On("Update", ctx, created.ID.Hex(), mock.AnythingOfType("*model.Task")).Return(&updated, nil).Once()
+		_, err := service.UpdateTask(ctx, created.ID.Hex(), &updated)
+		assert.NoError(t, err)
+
+		hits, err := service.SearchTasks(ctx, "user1", "report", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, hits)
+		hits, err = service.SearchTasks(ctx, "user1", "summary", 10)
+		assert.NoError(t, err)
+		assert.Len(t, hits, 1)
+	})
+
+	t.Run("removed_on_delete", func(t *testing.T) {
+		mockRepo.On("FindByID", ctx, created.ID.Hex()).Return(created, nil).Once()
+		mockRepo.On("Delete", ctx, created.ID.Hex()).Return(nil).Once()
+		assert.NoError(t, service.DeleteTask(ctx, created.ID.Hex()))
+
+		hits, err := service.SearchTasks(ctx, "user1", "summary", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("empty_query", func(t *testing.T) {
+		hits, err := service.SearchTasks(ctx, "user1", " ! ", 10)
+		assert.Error(t, err)
+		assert.Nil(t, hits)
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+}
+
 func TestModelToProto(t *testing.T) {
 	now := time.Now()
 	task := &model.Task{
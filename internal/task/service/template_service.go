@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+type TemplateService interface {
+	CreateTemplate(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error)
+	GetTemplate(ctx context.Context, userID string, id string) (*model.TaskTemplate, error)
+	// ListTemplates はテンプレートを名前順に返します
+	ListTemplates(ctx context.Context, userID string) ([]*model.TaskTemplate, error)
+	// UpdateTemplate はテンプレートの内容を template で置き換えます
+	UpdateTemplate(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error)
+	DeleteTemplate(ctx context.Context, userID string, id string) error
+	// InstantiateTemplate はテンプレートからタスクとそのサブタスクを作成します。プレースホルダーは variables の値で置き換え、
+	// 期限は base からテンプレートの日数だけずらした日時にします。途中で失敗した場合は作成したタスクを削除します
+	InstantiateTemplate(ctx context.Context, userID string, id string, variables map[string]string, base time.Time) (*model.Task, []*model.Task, error)
+}
+
+type templateService struct {
+	templateRepo repository.TemplateRepository
+	// tasks はテンプレートからタスクを作成するときに使います。検証やワークフローの扱いは通常の作成と同じです
+	tasks TaskService
+}
+
+func NewTemplateService(templateRepo repository.TemplateRepository, tasks TaskService) TemplateService {
+	return &templateService{
+		templateRepo: templateRepo,
+		tasks:        tasks,
+	}
+}
+
+func (s *templateService) CreateTemplate(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error) {
+	if template.UserID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	if err := template.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("テンプレートが不正です", err)
+	}
+
+	created, err := s.templateRepo.Create(ctx, template)
+	if err != nil {
+		if apperrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("テンプレートの作成に失敗しました", err)
+	}
+	return created, nil
+}
+
+func (s *templateService) GetTemplate(ctx context.Context, userID string, id string) (*model.TaskTemplate, error) {
+	return s.findTemplate(ctx, userID, id)
+}
+
+func (s *templateService) ListTemplates(ctx context.Context, userID string) ([]*model.TaskTemplate, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	templates, err := s.templateRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("テンプレート一覧の取得に失敗しました", err)
+	}
+	return templates, nil
+}
+
+func (s *templateService) UpdateTemplate(ctx context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error) {
+	if _, err := s.findTemplate(ctx, template.UserID, template.ID.Hex()); err != nil {
+		return nil, err
+	}
+	if err := template.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("テンプレートが不正です", err)
+	}
+
+	updated, err := s.templateRepo.Update(ctx, template)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("テンプレートが見つかりません", err)
+		}
+		if apperrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("テンプレートの更新に失敗しました", err)
+	}
+	return updated, nil
+}
+
+func (s *templateService) DeleteTemplate(ctx context.Context, userID string, id string) error {
+	if userID == "" {
+		return apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	if err := s.templateRepo.Delete(ctx, userID, id); err != nil {
+		if apperrors.IsNotFound(err) || apperrors.IsInvalidInput(err) {
+			return err
+		}
+		return apperrors.NewInternalError("テンプレートの削除に失敗しました", err)
+	}
+	return nil
+}
+
+func (s *templateService) InstantiateTemplate(ctx context.Context, userID string, id string, variables map[string]string, base time.Time) (*model.Task, []*model.Task, error) {
+	template, err := s.findTemplate(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	task, subtasks, err := template.Instantiate(variables, base)
+	if err != nil {
+		return nil, nil, apperrors.NewInvalidInputError("テンプレートの変数が不足しています", err)
+	}
+
+	parent, err := s.tasks.CreateTask(ctx, task)
+	if err != nil {
+		return nil, nil, err
+	}
+	created := make([]*model.Task, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		subtask.ParentID = parent.ID
+		child, err := s.tasks.CreateTask(ctx, subtask)
+		if err != nil {
+			s.discardTasks(ctx, parent, created)
+			return nil, nil, err
+		}
+		created = append(created, child)
+	}
+
+	// サブタスクの進捗を含めて返す
+	if parent, err = s.tasks.GetTask(ctx, parent.ID.Hex()); err != nil {
+		return nil, nil, err
+	}
+	return parent, created, nil
+}
+
+// discardTasks は途中まで作成したタスクを削除します。親を先に削除するとサブタスクがルートへ移るため、サブタスクから削除します。
+// 削除の失敗は作成の失敗より優先しないため無視します
+func (s *templateService) discardTasks(ctx context.Context, parent *model.Task, subtasks []*model.Task) {
+	for _, subtask := range subtasks {
+		_ = s.tasks.DeleteTask(ctx, subtask.ID.Hex())
+	}
+	_ = s.tasks.DeleteTask(ctx, parent.ID.Hex())
+}
+
+func (s *templateService) findTemplate(ctx context.Context, userID string, id string) (*model.TaskTemplate, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	template, err := s.templateRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("テンプレートが見つかりません", err)
+		}
+		if apperrors.IsInvalidInput(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("テンプレートの取得に失敗しました", err)
+	}
+	return template, nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryTemplateRepository はテスト用のインメモリ実装です
+type memoryTemplateRepository struct {
+	templates []*model.TaskTemplate
+}
+
+func (r *memoryTemplateRepository) Create(_ context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error) {
+	for _, t := range r.templates {
+		if t.UserID == template.UserID && t.Name == template.Name {
+			return nil, repository.ErrTemplateAlreadyExists
+		}
+	}
+	template.ID = primitive.NewObjectID()
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = template.CreatedAt
+	stored := *template
+	r.templates = append(r.templates, &stored)
+	return template, nil
+}
+
+func (r *memoryTemplateRepository) find(userID string, id string) (int, *model.TaskTemplate) {
+	for i, t := range r.templates {
+		if t.UserID == userID && t.ID.Hex() == id {
+			return i, t
+		}
+	}
+	return -1, nil
+}
+
+func (r *memoryTemplateRepository) FindByID(_ context.Context, userID string, id string) (*model.TaskTemplate, error) {
+	_, template := r.find(userID, id)
+	if template == nil {
+		return nil, repository.ErrTemplateNotFound
+	}
+	copied := *template
+	return &copied, nil
+}
+
+func (r *memoryTemplateRepository) FindByUserID(_ context.Context, userID string) ([]*model.TaskTemplate, error) {
+	templates := []*model.TaskTemplate{}
+	for _, t := range r.templates {
+		if t.UserID == userID {
+			copied := *t
+			templates = append(templates, &copied)
+		}
+	}
+	return templates, nil
+}
+
+func (r *memoryTemplateRepository) Update(_ context.Context, template *model.TaskTemplate) (*model.TaskTemplate, error) {
+	_, stored := r.find(template.UserID, template.ID.Hex())
+	if stored == nil {
+		return nil, repository.ErrTemplateNotFound
+	}
+	template.CreatedAt = stored.CreatedAt
+	template.UpdatedAt = time.Now()
+	*stored = *template
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *memoryTemplateRepository) Delete(_ context.Context, userID string, id string) error {
+	i, stored := r.find(userID, id)
+	if stored == nil {
+		return repository.ErrTemplateNotFound
+	}
+	r.templates = append(r.templates[:i], r.templates[i+1:]...)
+	return nil
+}
+
+func newTemplateTestServices() (TemplateService, *memoryTaskRepository) {
+	tasks := &memoryTaskRepository{}
+	taskService := NewTaskService(tasks, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	return NewTemplateService(&memoryTemplateRepository{}, taskService), tasks
+}
+
+func onboardingTemplate() *model.TaskTemplate {
+	return &model.TaskTemplate{
+		UserID:        "user1",
+		Name:          "onboarding",
+		Title:         "{{name}} さんのオンボーディング",
+		Description:   "{{ name }} さん（{{team}}）の受け入れ",
+		Priority:      model.TaskPriorityHigh,
+		Labels:        []string{"onboarding"},
+		Checklist:     []string{"{{team}} の Slack に招待"},
+		DueOffsetDays: 14,
+		Subtasks: []model.TemplateSubtask{
+			{Title: "アカウントを作成", DueOffsetDays: -1},
+			{Title: "{{name}} さんと面談", DueOffsetDays: 7},
+		},
+	}
+}
+
+func TestTemplateService_CreateTemplate(t *testing.T) {
+	ctx := context.Background()
+	templates, _ := newTemplateTestServices()
+
+	created, err := templates.CreateTemplate(ctx, onboardingTemplate())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "team"}, created.Placeholders())
+
+	_, err = templates.CreateTemplate(ctx, onboardingTemplate())
+	assert.True(t, apperrors.IsAlreadyExists(err))
+
+	invalid := onboardingTemplate()
+	invalid.Name = "invalid"
+	invalid.Subtasks = append(invalid.Subtasks, model.TemplateSubtask{Title: " "})
+	_, err = templates.CreateTemplate(ctx, invalid)
+	assert.True(t, apperrors.IsInvalidInput(err))
+
+	_, err = templates.GetTemplate(ctx, "user2", created.ID.Hex())
+	assert.True(t, apperrors.IsNotFound(err))
+}
+
+func TestTemplateService_InstantiateTemplate(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		templates, tasks := newTemplateTestServices()
+		template, err := templates.CreateTemplate(ctx, onboardingTemplate())
+		require.NoError(t, err)
+
+		task, subtasks, err := templates.InstantiateTemplate(ctx, "user1", template.ID.Hex(), map[string]string{"name": "佐藤", "team": "{{name}}"}, base)
+		require.NoError(t, err)
+		assert.Equal(t, "佐藤 さんのオンボーディング", task.Title)
+		// 値に含まれるプレースホルダーは置き換えない
+		assert.Equal(t, "佐藤 さん（{{name}}）の受け入れ", task.Description)
+		assert.Equal(t, model.TaskStatusPending, task.Status)
+		assert.Equal(t, model.TaskPriorityHigh, task.Priority)
+		assert.Equal(t, base.AddDate(0, 0, 14), task.DueDate)
+		require.Len(t, task.Checklist, 1)
+		assert.Equal(t, "{{name}} の Slack に招待", task.Checklist[0].Text)
+		assert.NotEmpty(t, task.Checklist[0].ID)
+
+		require.Len(t, subtasks, 2)
+		assert.Equal(t, task.ID, subtasks[0].ParentID)
+		assert.Equal(t, base.AddDate(0, 0, -1), subtasks[0].DueDate)
+		assert.Equal(t, "佐藤 さんと面談", subtasks[1].Title)
+		assert.Len(t, tasks.tasks, 3)
+	})
+
+	t.Run("missing_variables", func(t *testing.T) {
+		templates, tasks := newTemplateTestServices()
+		template, err := templates.CreateTemplate(ctx, onboardingTemplate())
+		require.NoError(t, err)
+
+		_, _, err = templates.InstantiateTemplate(ctx, "user1", template.ID.Hex(), map[string]string{"name": "佐藤"}, base)
+		assert.True(t, apperrors.IsInvalidInput(err))
+		assert.Contains(t, err.Error(), "team")
+		assert.Empty(t, tasks.tasks)
+	})
+
+	t.Run("other_user", func(t *testing.T) {
+		templates, _ := newTemplateTestServices()
+		template, err := templates.CreateTemplate(ctx, onboardingTemplate())
+		require.NoError(t, err)
+
+		_, _, err = templates.InstantiateTemplate(ctx, "user2", template.ID.Hex(), nil, base)
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestTemplateService_UpdateTemplate(t *testing.T) {
+	ctx := context.Background()
+	templates, _ := newTemplateTestServices()
+	created, err := templates.CreateTemplate(ctx, onboardingTemplate())
+	require.NoError(t, err)
+
+	replacement := &model.TaskTemplate{ID: created.ID, UserID: "user1", Name: "offboarding", Title: "退職手続き"}
+	updated, err := templates.UpdateTemplate(ctx, replacement)
+	require.NoError(t, err)
+	assert.Equal(t, "offboarding", updated.Name)
+	assert.Empty(t, updated.Subtasks)
+	assert.Empty(t, updated.Placeholders())
+
+	replacement.UserID = "user2"
+	_, err = templates.UpdateTemplate(ctx, replacement)
+	assert.True(t, apperrors.IsNotFound(err))
+
+	require.NoError(t, templates.DeleteTemplate(ctx, "user1", created.ID.Hex()))
+	list, err := templates.ListTemplates(ctx, "user1")
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
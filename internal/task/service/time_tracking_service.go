@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TimeTrackingService interface {
+	// StartTimer はタスクのタイマーを開始します。ユーザーごとに実行中のタイマーは1つまでです
+	StartTimer(ctx context.Context, userID string, taskID string, note string) (*model.TimeEntry, error)
+	// StopTimer はユーザーの実行中のタイマーを止め、作業時間として返します
+	StopTimer(ctx context.Context, userID string) (*model.TimeEntry, error)
+	GetRunningTimer(ctx context.Context, userID string) (*model.TimeEntry, error)
+	// AddTimeEntry は開始日時と終了日時を指定して作業時間を手動で記録します
+	AddTimeEntry(ctx context.Context, entry *model.TimeEntry) (*model.TimeEntry, error)
+	// UpdateTimeEntry は終了した作業時間の開始日時・終了日時・メモを更新します
+	UpdateTimeEntry(ctx context.Context, userID string, id string, start time.Time, end time.Time, note string) (*model.TimeEntry, error)
+	DeleteTimeEntry(ctx context.Context, userID string, id string) error
+	// ListTimeEntries はタスクの作業時間を開始日時順に、終了した作業時間の合計とともに返します
+	ListTimeEntries(ctx context.Context, userID string, taskID string) ([]*model.TimeEntry, time.Duration, error)
+	// GetTimeReport は query の期間の作業時間をタスクごと・日ごとに集計します
+	GetTimeReport(ctx context.Context, userID string, query model.TaskStatsQuery) (*model.TimeReport, error)
+	// ExportTimesheet は query の期間の作業時間を開始日時順にタスク名とともに1件ずつ fn に渡します
+	ExportTimesheet(ctx context.Context, userID string, query model.TaskStatsQuery, fn func(*model.TimesheetRow) error) error
+	TimerStopper
+}
+
+// TimerStopper はタスクの完了時にタスクの実行中のタイマーを止めます
+type TimerStopper interface {
+	StopTaskTimer(ctx context.Context, taskID primitive.ObjectID) error
+}
+
+type timeTrackingService struct {
+	entryRepo repository.TimeEntryRepository
+	taskRepo  repository.TaskRepository
+}
+
+func NewTimeTrackingService(entryRepo repository.TimeEntryRepository, taskRepo repository.TaskRepository) TimeTrackingService {
+	return &timeTrackingService{
+		entryRepo: entryRepo,
+		taskRepo:  taskRepo,
+	}
+}
+
+func (s *timeTrackingService) StartTimer(ctx context.Context, userID string, taskID string, note string) (*model.TimeEntry, error) {
+	task, err := s.authorizeTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status == model.TaskStatusComplete {
+		return nil, apperrors.NewFailedPreconditionError("完了したタスクのタイマーは開始できません", nil)
+	}
+	if err := model.ValidateTimeEntryNote(note); err != nil {
+		return nil, apperrors.NewInvalidInputError("作業時間が不正です", err)
+	}
+
+	return s.entryRepo.Create(ctx, &model.TimeEntry{
+		UserID:  userID,
+		TaskID:  task.ID,
+		Start:   time.Now(),
+		Running: true,
+		Note:    note,
+	})
+}
+
+func (s *timeTrackingService) StopTimer(ctx context.Context, userID string) (*model.TimeEntry, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	return s.entryRepo.StopRunning(ctx, userID, time.Now())
+}
+
+func (s *timeTrackingService) GetRunningTimer(ctx context.Context, userID string) (*model.TimeEntry, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	return s.entryRepo.FindRunning(ctx, userID)
+}
+
+func (s *timeTrackingService) AddTimeEntry(ctx context.Context, entry *model.TimeEntry) (*model.TimeEntry, error) {
+	if entry.TaskID.IsZero() {
+		return nil, apperrors.NewInvalidInputError("タスクIDは必須です", nil)
+	}
+	task, err := s.authorizeTask(ctx, entry.TaskID.Hex(), entry.UserID)
+	if err != nil {
+		return nil, err
+	}
+	entry.TaskID = task.ID
+	entry.Running = false
+	if err := entry.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("作業時間が不正です", err)
+	}
+	return s.entryRepo.Create(ctx, entry)
+}
+
+func (s *timeTrackingService) UpdateTimeEntry(ctx context.Context, userID string, id string, start time.Time, end time.Time, note string) (*model.TimeEntry, error) {
+	entry, err := s.findEntry(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Running {
+		return nil, apperrors.NewFailedPreconditionError("実行中のタイマーは編集できません", nil)
+	}
+
+	entry.Start = start
+	entry.End = end
+	entry.Note = note
+	if err := entry.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("作業時間が不正です", err)
+	}
+	return s.entryRepo.Update(ctx, entry)
+}
+
+func (s *timeTrackingService) DeleteTimeEntry(ctx context.Context, userID string, id string) error {
+	if userID == "" {
+		return apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	return s.entryRepo.Delete(ctx, userID, id)
+}
+
+func (s *timeTrackingService) ListTimeEntries(ctx context.Context, userID string, taskID string) ([]*model.TimeEntry, time.Duration, error) {
+	task, err := s.authorizeTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries, err := s.entryRepo.FindByTask(ctx, task.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total time.Duration
+	for _, entry := range entries {
+		total += entry.Duration()
+	}
+	return entries, total, nil
+}
+
+func (s *timeTrackingService) GetTimeReport(ctx context.Context, userID string, query model.TaskStatsQuery) (*model.TimeReport, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	return s.entryRepo.Report(ctx, userID, query)
+}
+
+// ExportTimesheet はタスク名をタスクごとに1回だけ取得します。削除したタスクの作業時間はタスク名を空にして渡します
+func (s *timeTrackingService) ExportTimesheet(ctx context.Context, userID string, query model.TaskStatsQuery, fn func(*model.TimesheetRow) error) error {
+	if userID == "" {
+		return apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+
+	titles := make(map[primitive.ObjectID]string)
+	return s.entryRepo.ForEachInRange(ctx, userID, query, func(entry *model.TimeEntry) error {
+		title, ok := titles[entry.TaskID]
+		if !ok {
+			task, err := s.taskRepo.FindByID(ctx, entry.TaskID.Hex())
+			switch {
+			case err == nil:
+				title = task.Title
+			case !apperrors.IsNotFound(err):
+				return err
+			}
+			titles[entry.TaskID] = title
+		}
+		return fn(&model.TimesheetRow{Entry: entry, TaskTitle: title})
+	})
+}
+
+// StopTaskTimer はタスクに実行中のタイマーがない場合は何もしません
+func (s *timeTrackingService) StopTaskTimer(ctx context.Context, taskID primitive.ObjectID) error {
+	_, err := s.entryRepo.StopByTask(ctx, taskID, time.Now())
+	if err != nil && !apperrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *timeTrackingService) authorizeTask(ctx context.Context, taskID string, userID string) (*model.Task, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.UserID != userID {
+		return nil, apperrors.NewPermissionDeniedError("このタスクの作業時間を操作する権限がありません", nil)
+	}
+	return task, nil
+}
+
+func (s *timeTrackingService) findEntry(ctx context.Context, userID string, id string) (*model.TimeEntry, error) {
+	if userID == "" {
+		return nil, apperrors.NewUnauthorizedError("認証が必要です", nil)
+	}
+	return s.entryRepo.FindByID(ctx, userID, id)
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryTimeEntryRepository はテスト用のインメモリ実装です
+type memoryTimeEntryRepository struct {
+	entries []*model.TimeEntry
+}
+
+func (r *memoryTimeEntryRepository) Create(_ context.Context, entry *model.TimeEntry) (*model.TimeEntry, error) {
+	if entry.Running {
+		for _, e := range r.entries {
+			if e.UserID == entry.UserID && e.Running {
+				return nil, repository.ErrTimerAlreadyRunning
+			}
+		}
+	}
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = entry.CreatedAt
+	stored := *entry
+	r.entries = append(r.entries, &stored)
+	return entry, nil
+}
+
+func (r *memoryTimeEntryRepository) FindByID(_ context.Context, userID string, id string) (*model.TimeEntry, error) {
+	for _, e := range r.entries {
+		if e.UserID == userID && e.ID.Hex() == id {
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrTimeEntryNotFound
+}
+
+func (r *memoryTimeEntryRepository) FindRunning(_ context.Context, userID string) (*model.TimeEntry, error) {
+	for _, e := range r.entries {
+		if e.UserID == userID && e.Running {
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrTimerNotRunning
+}
+
+func (r *memoryTimeEntryRepository) stop(match func(*model.TimeEntry) bool, end time.Time) (*model.TimeEntry, error) {
+	for _, e := range r.entries {
+		if e.Running && match(e) {
+			e.Running = false
+			e.End = end
+			e.UpdatedAt = end
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrTimerNotRunning
+}
+
+func (r *memoryTimeEntryRepository) StopRunning(_ context.Context, userID string, end time.Time) (*model.TimeEntry, error) {
+	return r.stop(func(e *model.TimeEntry) bool { return e.UserID == userID }, end)
+}
+
+func (r *memoryTimeEntryRepository) StopByTask(_ context.Context, taskID primitive.ObjectID, end time.Time) (*model.TimeEntry, error) {
+	return r.stop(func(e *model.TimeEntry) bool { return e.TaskID == taskID }, end)
+}
+
+func (r *memoryTimeEntryRepository) Update(_ context.Context, entry *model.TimeEntry) (*model.TimeEntry, error) {
+	for _, e := range r.entries {
+		if e.UserID == entry.UserID && e.ID == entry.ID && !e.Running {
+			e.Start, e.End, e.Note, e.UpdatedAt = entry.Start, entry.End, entry.Note, time.Now()
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrTimeEntryNotFound
+}
+
+func (r *memoryTimeEntryRepository) Delete(_ context.Context, userID string, id string) error {
+	for i, e := range r.entries {
+		if e.UserID == userID && e.ID.Hex() == id {
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrTimeEntryNotFound
+}
+
+func (r *memoryTimeEntryRepository) FindByTask(_ context.Context, taskID primitive.ObjectID) ([]*model.TimeEntry, error) {
+	entries := []*model.TimeEntry{}
+	for _, e := range r.entries {
+		if e.TaskID == taskID {
+			copied := *e
+			entries = append(entries, &copied)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Start.Before(entries[j].Start) })
+	return entries, nil
+}
+
+func (r *memoryTimeEntryRepository) userEntries(userID string) []*model.TimeEntry {
+	var entries []*model.TimeEntry
+	for _, e := range r.entries {
+		if e.UserID == userID {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func (r *memoryTimeEntryRepository) Report(_ context.Context, userID string, query model.TaskStatsQuery) (*model.TimeReport, error) {
+	return model.ComputeTimeReport(r.userEntries(userID), query), nil
+}
+
+func (r *memoryTimeEntryRepository) ForEachInRange(_ context.Context, userID string, query model.TaskStatsQuery, fn func(*model.TimeEntry) error) error {
+	entries := r.userEntries(userID)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Start.Before(entries[j].Start) })
+	for _, e := range entries {
+		if e.Running || !query.Contains(e.Start) {
+			continue
+		}
+		copied := *e
+		if err := fn(&copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTimeTrackingTestServices は完了時にタイマーを止めるタスクサービスとともに作業時間のサービスを返します
+func newTimeTrackingTestServices(stopOnComplete bool) (TimeTrackingService, TaskService, *memoryTimeEntryRepository) {
+	tasks := &memoryTaskRepository{}
+	entries := &memoryTimeEntryRepository{}
+	timeService := NewTimeTrackingService(entries, tasks)
+	var timers TimerStopper
+	if stopOnComplete {
+		timers = timeService
+	}
+	taskService := NewTaskService(tasks, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, timers, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	return timeService, taskService, entries
+}
+
+func TestTimeTrackingService_Timer(t *testing.T) {
+	ctx := context.Background()
+	timeService, taskService, _ := newTimeTrackingTestServices(true)
+	first := createProjectTask(t, taskService, "first", primitive.NilObjectID)
+	second := createProjectTask(t, taskService, "second", primitive.NilObjectID)
+
+	running, err := timeService.StartTimer(ctx, "user1", first.ID.Hex(), "設計")
+	require.NoError(t, err)
+	assert.True(t, running.Running)
+
+	// 実行中のタイマーはユーザーごとに1つまで
+	_, err = timeService.StartTimer(ctx, "user1", second.ID.Hex(), "")
+	assert.True(t, apperrors.IsFailedPrecondition(err))
+	_, err = timeService.StartTimer(ctx, "user2", second.ID.Hex(), "")
+	assert.True(t, apperrors.IsPermissionDenied(err))
+
+	current, err := timeService.GetRunningTimer(ctx, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, running.ID, current.ID)
+
+	stopped, err := timeService.StopTimer(ctx, "user1")
+	require.NoError(t, err)
+	assert.False(t, stopped.Running)
+	assert.False(t, stopped.End.Before(stopped.Start))
+
+	_, err = timeService.StopTimer(ctx, "user1")
+	assert.True(t, apperrors.IsNotFound(err))
+	_, err = timeService.StartTimer(ctx, "user1", second.ID.Hex(), "")
+	assert.NoError(t, err)
+}
+
+func TestTimeTrackingService_StopOnComplete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("enabled", func(t *testing.T) {
+		timeService, taskService, _ := newTimeTrackingTestServices(true)
+		task := createProjectTask(t, taskService, "task", primitive.NilObjectID)
+		_, err := timeService.StartTimer(ctx, "user1", task.ID.Hex(), "")
+		require.NoError(t, err)
+
+		task.Status = model.TaskStatusComplete
+		task.WorkflowStatus = ""
+		_, err = taskService.UpdateTask(ctx, task.ID.Hex(), task)
+		require.NoError(t, err)
+
+		_, err = timeService.GetRunningTimer(ctx, "user1")
+		assert.True(t, apperrors.IsNotFound(err))
+		_, err = timeService.StartTimer(ctx, "user1", task.ID.Hex(), "")
+		assert.True(t, apperrors.IsFailedPrecondition(err), "完了したタスクのタイマーは開始できない")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		timeService, taskService, _ := newTimeTrackingTestServices(false)
+		task := createProjectTask(t, taskService, "task", primitive.NilObjectID)
+		_, err := timeService.StartTimer(ctx, "user1", task.ID.Hex(), "")
+		require.NoError(t, err)
+
+		task.Status = model.TaskStatusComplete
+		task.WorkflowStatus = ""
+		_, err = taskService.UpdateTask(ctx, task.ID.Hex(), task)
+		require.NoError(t, err)
+
+		_, err = timeService.GetRunningTimer(ctx, "user1")
+		assert.NoError(t, err)
+	})
+}
+
+func TestTimeTrackingService_TimeEntries(t *testing.T) {
+	ctx := context.Background()
+	timeService, taskService, _ := newTimeTrackingTestServices(true)
+	task := createProjectTask(t, taskService, "task", primitive.NilObjectID)
+	start := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := timeService.AddTimeEntry(ctx, &model.TimeEntry{UserID: "user1", TaskID: task.ID, Start: start, End: start.Add(-time.Hour)})
+	assert.True(t, apperrors.IsInvalidInput(err))
+	_, err = timeService.AddTimeEntry(ctx, &model.TimeEntry{UserID: "user1", TaskID: task.ID, Start: start, End: start.Add(25 * time.Hour)})
+	assert.True(t, apperrors.IsInvalidInput(err))
+
+	entry, err := timeService.AddTimeEntry(ctx, &model.TimeEntry{UserID: "user1", TaskID: task.ID, Start: start, End: start.Add(time.Hour), Note: "レビュー"})
+	require.NoError(t, err)
+	_, err = timeService.AddTimeEntry(ctx, &model.TimeEntry{UserID: "user1", TaskID: task.ID, Start: start.Add(2 * time.Hour), End: start.Add(150 * time.Minute)})
+	require.NoError(t, err)
+	// 実行中のタイマーは合計に含めない
+	_, err = timeService.StartTimer(ctx, "user1", task.ID.Hex(), "")
+	require.NoError(t, err)
+
+	entries, total, err := timeService.ListTimeEntries(ctx, "user1", task.ID.Hex())
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, 90*time.Minute, total)
+
+	updated, err := timeService.UpdateTimeEntry(ctx, "user1", entry.ID.Hex(), start, start.Add(2*time.Hour), "")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, updated.Duration())
+
+	_, err = timeService.UpdateTimeEntry(ctx, "user2", entry.ID.Hex(), start, start.Add(time.Hour), "")
+	assert.True(t, apperrors.IsNotFound(err))
+	running, err := timeService.GetRunningTimer(ctx, "user1")
+	require.NoError(t, err)
+	_, err = timeService.UpdateTimeEntry(ctx, "user1", running.ID.Hex(), start, start.Add(time.Hour), "")
+	assert.True(t, apperrors.IsFailedPrecondition(err))
+
+	require.NoError(t, timeService.DeleteTimeEntry(ctx, "user1", entry.ID.Hex()))
+	_, total, err = timeService.ListTimeEntries(ctx, "user1", task.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, total)
+}
+
+func TestTimeTrackingService_Report(t *testing.T) {
+	ctx := context.Background()
+	timeService, taskService, entries := newTimeTrackingTestServices(true)
+	design := createProjectTask(t, taskService, "design", primitive.NilObjectID)
+	review := createProjectTask(t, taskService, "review", primitive.NilObjectID)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	now := time.Date(2024, 4, 10, 12, 0, 0, 0, tokyo)
+
+	add := func(task *model.Task, start time.Time, d time.Duration) {
+		_, err := timeService.AddTimeEntry(ctx, &model.TimeEntry{UserID: "user1", TaskID: task.ID, Start: start, End: start.Add(d)})
+		require.NoError(t, err)
+	}
+	// 東京の 4/9 0:30 は UTC では 4/8 のため、タイムゾーンの日付で数える
+	add(design, time.Date(2024, 4, 9, 0, 30, 0, 0, tokyo), time.Hour)
+	add(design, time.Date(2024, 4, 10, 9, 0, 0, 0, tokyo), 2*time.Hour)
+	add(review, time.Date(2024, 4, 10, 14, 0, 0, 0, tokyo), 30*time.Minute)
+	// 期間外の作業時間は含めない
+	add(review, time.Date(2024, 4, 1, 9, 0, 0, 0, tokyo), time.Hour)
+
+	query, err := model.NewTaskStatsQuery("2024-04-09", "2024-04-10", tokyo, now)
+	require.NoError(t, err)
+	report, err := timeService.GetTimeReport(ctx, "user1", query)
+	require.NoError(t, err)
+	assert.Equal(t, 210*time.Minute, report.Total)
+	require.Len(t, report.ByTask, 2)
+	assert.Equal(t, model.TaskTimeTotal{TaskID: design.ID, Duration: 3 * time.Hour, Entries: 2}, report.ByTask[0])
+	assert.Equal(t, []model.DailyDuration{
+		{Date: "2024-04-09", Duration: time.Hour},
+		{Date: "2024-04-10", Duration: 150 * time.Minute},
+	}, report.Daily)
+
+	// 削除したタスクの作業時間はタスク名を空にして書き出す
+	require.NoError(t, taskService.DeleteTask(ctx, review.ID.Hex()))
+	var rows []*model.TimesheetRow
+	err = timeService.ExportTimesheet(ctx, "user1", query, func(row *model.TimesheetRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "design", rows[0].TaskTitle)
+	assert.Equal(t, "", rows[2].TaskTitle)
+	assert.Len(t, entries.entries, 4)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/search"
+	"github.com/my-backend-project/internal/task/watch"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_WatchTasks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("publishes_writes", func(t *testing.T) {
+		bus := watch.NewBus(0)
+		repo := watch.NewPublishingRepository(&memoryTaskRepository{}, bus)
+		svc := NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), bus, CompletionPolicyWarn)
+
+		sub, err := svc.WatchTasks(ctx, "user1", "")
+		require.NoError(t, err)
+
+		task, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "監視対象", Status: model.TaskStatusPending, DueDate: testDueDate})
+		require.NoError(t, err)
+		_, err = svc.CreateTask(ctx, &model.Task{UserID: "user2", Title: "他のユーザー", Status: model.TaskStatusPending, DueDate: testDueDate})
+		require.NoError(t, err)
+		require.NoError(t, svc.DeleteTask(ctx, task.ID.Hex()))
+
+		for _, want := range []watch.EventType{watch.EventCreated, watch.EventDeleted} {
+			select {
+			case event := <-sub.Events():
+				assert.Equal(t, want, event.Type)
+				assert.Equal(t, task.ID, event.TaskID)
+			case <-time.After(time.Second):
+				t.Fatalf("%s のイベントを受信できませんでした", want)
+			}
+		}
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		svc := NewTaskService(&memoryTaskRepository{}, newNoFieldsRepository(), &memoryDependencyRepository{}, &memoryWorkflowRepository{}, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+		_, err := svc.WatchTasks(ctx, "user1", "")
+		assert.True(t, apperrors.IsFailedPrecondition(err))
+	})
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+func (s *taskService) TransitionTask(ctx context.Context, id string, userID string, to string, reason string) (*model.Task, *model.TaskTransition, error) {
+	current, err := s.ownedTask(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	workflow, err := s.workflowFor(ctx, current.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next, err := s.prepareTransition(ctx, workflow, current, to, reason)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.applyTransition(ctx, workflow, current, next, reason)
+}
+
+// prepareTransition は current を to へ遷移できるかを確認し、遷移後のタスクを返します。タスクはまだ書き込みません
+func (s *taskService) prepareTransition(ctx context.Context, workflow *model.Workflow, current *model.Task, to string, reason string) (*model.Task, error) {
+	target, ok := workflow.Status(to)
+	if !ok {
+		return nil, apperrors.NewInvalidInputError("無効なステータスです", nil)
+	}
+	from := workflow.CurrentStatus(current)
+	if from == to {
+		return nil, apperrors.NewFailedPreconditionError("タスクは既にこのステータスです", nil)
+	}
+
+	next := *current
+	next.WorkflowStatus = target.Key
+	next.Status = target.Category.TaskStatus()
+	if err := s.checkStatusChange(ctx, workflow, current, &next, from, to, reason); err != nil {
+		return nil, err
+	}
+	next.StampCompletion(current, time.Now())
+	return &next, nil
+}
+
+// applyTransition は prepareTransition で確認した遷移を書き込み、遷移の履歴を記録します
+func (s *taskService) applyTransition(ctx context.Context, workflow *model.Workflow, current *model.Task, next *model.Task, reason string) (*model.Task, *model.TaskTransition, error) {
+	updated, err := s.taskRepo.Update(ctx, current.ID.Hex(), next)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, nil, apperrors.NewNotFoundError("タスクが見つかりません", err)
+		}
+		return nil, nil, apperrors.NewInternalError("タスクの更新に失敗しました", err)
+	}
+	transition, err := s.workflowRepo.RecordTransition(ctx, &model.TaskTransition{
+		TaskID: current.ID,
+		UserID: current.UserID,
+		From:   workflow.CurrentStatus(current),
+		To:     workflow.CurrentStatus(next),
+		Reason: strings.TrimSpace(reason),
+	})
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("ステータス遷移の記録に失敗しました", err)
+	}
+	if updated.Status == model.TaskStatusComplete && current.Status != model.TaskStatusComplete {
+		if updated, err = s.completeTask(ctx, updated, true); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.syncTask(ctx, updated); err != nil {
+		return nil, nil, err
+	}
+	if err := s.attachProgress(ctx, updated); err != nil {
+		return nil, nil, err
+	}
+	return updated, transition, nil
+}
+
+func (s *taskService) ListTaskTransitions(ctx context.Context, id string, userID string) ([]*model.TaskTransition, error) {
+	task, err := s.ownedTask(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.workflowRepo.FindTransitions(ctx, task.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("ステータス遷移の取得に失敗しました", err)
+	}
+	return transitions, nil
+}
+
+// workflowFor は所有者のワークフローを返します。定義されていない場合は既定のワークフローです
+func (s *taskService) workflowFor(ctx context.Context, ownerID string) (*model.Workflow, error) {
+	return findWorkflow(ctx, s.workflowRepo, ownerID)
+}
+
+// findWorkflow は workflowRepo から所有者のワークフローを取得します。定義されていない場合は既定のワークフローです
+func findWorkflow(ctx context.Context, workflowRepo repository.WorkflowRepository, ownerID string) (*model.Workflow, error) {
+	workflow, err := workflowRepo.FindByOwnerID(ctx, ownerID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return model.DefaultWorkflow(ownerID), nil
+		}
+		return nil, apperrors.NewInternalError("ワークフローの取得に失敗しました", err)
+	}
+	return workflow, nil
+}
+
+// resolveInitialStatus は作成するタスクのステータスを決めます。
+// ワークフローのステータスの指定を優先し、組み込みのステータスだけが指定された場合はその分類の最初のステータス、
+// どちらもなければワークフローの初期ステータスにします
+func resolveInitialStatus(workflow *model.Workflow, task *model.Task) error {
+	key := task.WorkflowStatus
+	switch {
+	case key != "":
+		if _, ok := workflow.Status(key); !ok {
+			return apperrors.NewInvalidInputError("無効なステータスです", nil)
+		}
+	case model.CategoryOf(task.Status) != "":
+		key = workflow.StatusForCategory(model.CategoryOf(task.Status))
+		if key == "" {
+			return apperrors.NewInvalidInputError("ワークフローに対応するステータスがありません", nil)
+		}
+	default:
+		key = workflow.InitialStatus
+	}
+
+	status, _ := workflow.Status(key)
+	task.WorkflowStatus = status.Key
+	task.Status = status.Category.TaskStatus()
+	return nil
+}
+
+// resolveTargetStatus は更新後のステータスの key を決めて next に設定します。
+// 組み込みのステータスの分類が変わらない場合は現在のステータスを維持します
+func resolveTargetStatus(workflow *model.Workflow, current, next *model.Task, from string) (string, error) {
+	key := next.WorkflowStatus
+	switch {
+	case key != "":
+		if _, ok := workflow.Status(key); !ok {
+			return "", apperrors.NewInvalidInputError("無効なステータスです", nil)
+		}
+	case model.CategoryOf(next.Status) == "" || next.Status == current.Status:
+		key = from
+	default:
+		key = workflow.StatusForCategory(model.CategoryOf(next.Status))
+		if key == "" {
+			return "", apperrors.NewInvalidInputError("ワークフローに対応するステータスがありません", nil)
+		}
+	}
+
+	if status, ok := workflow.Status(key); ok {
+		next.WorkflowStatus = status.Key
+		next.Status = status.Category.TaskStatus()
+	} else {
+		next.WorkflowStatus = current.WorkflowStatus
+		next.Status = current.Status
+	}
+	return key, nil
+}
+
+// checkStatusChange はステータスの変更がワークフローで許可されているか、遷移の条件・ブロッカー・サブタスクの完了ポリシーを満たすかを確認します
+func (s *taskService) checkStatusChange(ctx context.Context, workflow *model.Workflow, current, next *model.Task, from, to, reason string) error {
+	// ワークフローの変更で現在のステータスが無くなったタスクは、どのステータスへも移せるようにする
+	if from != "" {
+		transition := workflow.Transition(from, to)
+		if transition == nil {
+			return apperrors.NewFailedPreconditionError(
+				fmt.Sprintf("ステータスを「%s」から「%s」へ変更することはできません", statusName(workflow, from), statusName(workflow, to)), nil)
+		}
+		if err := s.checkGuards(ctx, transition, current, next, reason); err != nil {
+			return err
+		}
+	}
+
+	if next.Status != current.Status && (next.Status == model.TaskStatusActive || next.Status == model.TaskStatusComplete) {
+		if err := s.ensureUnblocked(ctx, current); err != nil {
+			return err
+		}
+	}
+	if next.Status == model.TaskStatusComplete && current.Status != model.TaskStatusComplete {
+		if err := s.applyCompletionPolicy(ctx, workflow, current.ID.Hex()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *taskService) checkGuards(ctx context.Context, transition *model.WorkflowTransition, current, next *model.Task, reason string) error {
+	for _, guard := range transition.Guards {
+		switch guard {
+		case model.GuardReasonRequired:
+			if strings.TrimSpace(reason) == "" {
+				return apperrors.NewFailedPreconditionError("この遷移には理由の入力が必要です", nil)
+			}
+		case model.GuardChecklistComplete:
+			for _, item := range next.Checklist {
+				if !item.Done {
+					return apperrors.NewFailedPreconditionError("チェックリストに未完了の項目があります", nil)
+				}
+			}
+		case model.GuardNoOpenSubtasks:
+			if err := s.attachProgress(ctx, current); err != nil {
+				return err
+			}
+			if current.Progress.HasOpenSubtasks() {
+				return apperrors.NewFailedPreconditionError("未完了のサブタスクがあります", nil)
+			}
+		case model.GuardNoOpenBlockers:
+			if err := s.ensureUnblocked(ctx, current); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func statusName(workflow *model.Workflow, key string) string {
+	if status, ok := workflow.Status(key); ok {
+		return status.Name
+	}
+	return key
+}
+
+// findTask はタスクを取得します。GetTask と異なり進捗は集計しません
+func (s *taskService) findTask(ctx context.Context, id string) (*model.Task, error) {
+	task, err := s.taskRepo.FindByID(ctx, id)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewNotFoundError("タスクが見つかりません", err)
+		}
+		if apperrors.IsInvalidInput(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewInternalError("タスクの取得に失敗しました", err)
+	}
+	return task, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+)
+
+type WorkflowService interface {
+	// GetWorkflow は所有者のワークフローを返します。定義されていない場合は既定のワークフローです
+	GetWorkflow(ctx context.Context, ownerID string) (*model.Workflow, error)
+	// PutWorkflow は所有者のワークフローを作成または置き換えます
+	PutWorkflow(ctx context.Context, workflow *model.Workflow) (*model.Workflow, error)
+	// ResetWorkflow は所有者のワークフローを削除し、既定のワークフローに戻します
+	ResetWorkflow(ctx context.Context, ownerID string) (*model.Workflow, error)
+}
+
+type workflowService struct {
+	workflowRepo repository.WorkflowRepository
+}
+
+func NewWorkflowService(workflowRepo repository.WorkflowRepository) WorkflowService {
+	return &workflowService{
+		workflowRepo: workflowRepo,
+	}
+}
+
+func (s *workflowService) GetWorkflow(ctx context.Context, ownerID string) (*model.Workflow, error) {
+	workflow, err := s.workflowRepo.FindByOwnerID(ctx, ownerID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return model.DefaultWorkflow(ownerID), nil
+		}
+		return nil, apperrors.NewInternalError("ワークフローの取得に失敗しました", err)
+	}
+	return workflow, nil
+}
+
+func (s *workflowService) PutWorkflow(ctx context.Context, workflow *model.Workflow) (*model.Workflow, error) {
+	if err := workflow.Validate(); err != nil {
+		return nil, apperrors.NewInvalidInputError("ワークフローが不正です", err)
+	}
+
+	saved, err := s.workflowRepo.Save(ctx, workflow)
+	if err != nil {
+		return nil, apperrors.NewInternalError("ワークフローの保存に失敗しました", err)
+	}
+	return saved, nil
+}
+
+func (s *workflowService) ResetWorkflow(ctx context.Context, ownerID string) (*model.Workflow, error) {
+	if err := s.workflowRepo.Delete(ctx, ownerID); err != nil && !apperrors.IsNotFound(err) {
+		return nil, apperrors.NewInternalError("ワークフローの削除に失敗しました", err)
+	}
+	return model.DefaultWorkflow(ownerID), nil
+}
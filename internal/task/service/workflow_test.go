@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+	"github.com/my-backend-project/internal/task/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryWorkflowRepository はテスト用のインメモリ実装です
+type memoryWorkflowRepository struct {
+	workflows   map[string]*model.Workflow
+	transitions []*model.TaskTransition
+}
+
+func (r *memoryWorkflowRepository) FindByOwnerID(_ context.Context, ownerID string) (*model.Workflow, error) {
+	if w, ok := r.workflows[ownerID]; ok {
+		return w, nil
+	}
+	return nil, repository.ErrWorkflowNotFound
+}
+
+func (r *memoryWorkflowRepository) Save(_ context.Context, workflow *model.Workflow) (*model.Workflow, error) {
+	if r.workflows == nil {
+		r.workflows = make(map[string]*model.Workflow)
+	}
+	if existing, ok := r.workflows[workflow.OwnerID]; ok {
+		workflow.ID = existing.ID
+	} else {
+		workflow.ID = primitive.NewObjectID()
+	}
+	r.workflows[workflow.OwnerID] = workflow
+	return workflow, nil
+}
+
+func (r *memoryWorkflowRepository) Delete(_ context.Context, ownerID string) error {
+	if _, ok := r.workflows[ownerID]; !ok {
+		return repository.ErrWorkflowNotFound
+	}
+	delete(r.workflows, ownerID)
+	return nil
+}
+
+func (r *memoryWorkflowRepository) RecordTransition(_ context.Context, transition *model.TaskTransition) (*model.TaskTransition, error) {
+	transition.ID = primitive.NewObjectID()
+	r.transitions = append(r.transitions, transition)
+	return transition, nil
+}
+
+func (r *memoryWorkflowRepository) FindTransitions(_ context.Context, taskID primitive.ObjectID) ([]*model.TaskTransition, error) {
+	result := []*model.TaskTransition{}
+	for _, t := range r.transitions {
+		if t.TaskID == taskID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// reviewWorkflow はレビュー工程を挟むワークフローです。完了にはレビューを経由し、差し戻しには理由が必要です
+func reviewWorkflow(ownerID string) *model.Workflow {
+	return &model.Workflow{
+		OwnerID: ownerID,
+		Statuses: []model.WorkflowStatus{
+			{Key: "BACKLOG", Name: "バックログ", Category: model.StatusCategoryTodo},
+			{Key: "DOING", Name: "作業中", Category: model.StatusCategoryInProgress},
+			{Key: "REVIEW", Name: "レビュー中", Category: model.StatusCategoryInProgress},
+			{Key: "DONE", Name: "完了", Category: model.StatusCategoryDone},
+		},
+		Transitions: []model.WorkflowTransition{
+			{From: "BACKLOG", To: "DOING"},
+			{From: "DOING", To: "REVIEW", Guards: []model.TransitionGuard{model.GuardChecklistComplete}},
+			{From: "REVIEW", To: "DOING", Guards: []model.TransitionGuard{model.GuardReasonRequired}},
+			{From: "REVIEW", To: "DONE"},
+		},
+		InitialStatus: "BACKLOG",
+	}
+}
+
+func newWorkflowTestService() (TaskService, *memoryTaskRepository, *memoryWorkflowRepository) {
+	repo := &memoryTaskRepository{}
+	workflows := &memoryWorkflowRepository{}
+	svc := NewTaskService(repo, newNoFieldsRepository(), &memoryDependencyRepository{}, workflows, &memoryReminderRepository{}, &memoryProjectRepository{}, nil, nil, nil, nil, testCodec, search.NewMemoryBackend(search.DefaultBoosts), nil, CompletionPolicyWarn)
+	return svc, repo, workflows
+}
+
+func TestTaskService_DefaultWorkflow(t *testing.T) {
+	ctx := context.Background()
+	svc, _, workflows := newWorkflowTestService()
+
+	task, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "task", DueDate: testDueDate})
+	require.NoError(t, err)
+	assert.Equal(t, model.TaskStatusPending, task.Status)
+	assert.Equal(t, string(model.TaskStatusPending), task.WorkflowStatus)
+
+	task.Status = model.TaskStatusComplete
+	task.WorkflowStatus = ""
+	task, err = svc.UpdateTask(ctx, task.ID.Hex(), task)
+	require.NoError(t, err)
+	assert.Equal(t, string(model.TaskStatusComplete), task.WorkflowStatus)
+
+	// 既定のワークフローでは完了から未着手へ直接戻せない
+	task.Status = model.TaskStatusPending
+	task.WorkflowStatus = ""
+	_, err = svc.UpdateTask(ctx, task.ID.Hex(), task)
+	assert.True(t, apperrors.IsFailedPrecondition(err))
+
+	_, transition, err := svc.TransitionTask(ctx, task.ID.Hex(), "user1", string(model.TaskStatusActive), "")
+	require.NoError(t, err)
+	assert.Equal(t, string(model.TaskStatusComplete), transition.From)
+	assert.Equal(t, string(model.TaskStatusActive), transition.To)
+
+	history, err := svc.ListTaskTransitions(ctx, task.ID.Hex(), "user1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, string(model.TaskStatusPending), history[0].From)
+	assert.Len(t, workflows.transitions, 2)
+}
+
+func TestTaskService_TransitionTask(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("custom_workflow_with_guards", func(t *testing.T) {
+		svc, _, workflows := newWorkflowTestService()
+		_, err := workflows.Save(ctx, reviewWorkflow("user1"))
+		require.NoError(t, err)
+
+		task, err := svc.CreateTask(ctx, &model.Task{
+			UserID:    "user1",
+			Title:     "task",
+			DueDate:   testDueDate,
+			Checklist: []model.ChecklistItem{{Text: "テストを書く"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "BACKLOG", task.WorkflowStatus)
+		assert.Equal(t, model.TaskStatusPending, task.Status)
+
+		_, _, err = svc.TransitionTask(ctx, task.ID.Hex(), "user1", "DONE", "")
+		assert.True(t, apperrors.IsFailedPrecondition(err))
+
+		task, _, err = svc.TransitionTask(ctx, task.ID.Hex(), "user1", "DOING", "")
+		require.NoError(t, err)
+		assert.Equal(t, model.TaskStatusActive, task.Status)
+
+		_, _, err = svc.TransitionTask(ctx, task.ID.Hex(), "user1", "REVIEW", "")
+		assert.True(t, apperrors.IsFailedPrecondition(err), "チェックリストが未完了のためレビューに移せない")
+
+		task.Checklist[0].Done = true
+		task, err = svc.UpdateTask(ctx, task.ID.Hex(), task)
+		require.NoError(t, err)
+		task, _, err = svc.TransitionTask(ctx, task.ID.Hex(), "user1", "REVIEW", "")
+		require.NoError(t, err)
+		assert.Equal(t, model.TaskStatusActive, task.Status)
+
+		_, _, err = svc.TransitionTask(ctx, task.ID.Hex(), "user1", "DOING", "  ")
+		assert.True(t, apperrors.IsFailedPrecondition(err), "差し戻しには理由が必要")
+
+		_, transition, err := svc.TransitionTask(ctx, task.ID.Hex(), "user1", "DOING", "テスト不足")
+		require.NoError(t, err)
+		assert.Equal(t, "テスト不足", transition.Reason)
+	})
+
+	t.Run("unknown_status", func(t *testing.T) {
+		svc, _, _ := newWorkflowTestService()
+		task, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "task", DueDate: testDueDate})
+		require.NoError(t, err)
+
+		_, _, err = svc.TransitionTask(ctx, task.ID.Hex(), "user1", "UNKNOWN", "")
+		assert.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("same_status", func(t *testing.T) {
+		svc, _, _ := newWorkflowTestService()
+		task, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "task", DueDate: testDueDate})
+		require.NoError(t, err)
+
+		_, _, err = svc.TransitionTask(ctx, task.ID.Hex(), "user1", string(model.TaskStatusPending), "")
+		assert.True(t, apperrors.IsFailedPrecondition(err))
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		svc, _, _ := newWorkflowTestService()
+		_, _, err := svc.TransitionTask(ctx, primitive.NewObjectID().Hex(), "user1", string(model.TaskStatusActive), "")
+		assert.True(t, apperrors.IsNotFound(err))
+	})
+
+	t.Run("other_owner", func(t *testing.T) {
+		svc, repo, workflows := newWorkflowTestService()
+		task, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "task", DueDate: testDueDate})
+		require.NoError(t, err)
+
+		_, _, err = svc.TransitionTask(ctx, task.ID.Hex(), "user2", string(model.TaskStatusActive), "")
+		assert.True(t, apperrors.IsPermissionDenied(err))
+		stored, err := repo.FindByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, model.TaskStatusPending, stored.Status)
+		assert.Empty(t, workflows.transitions, "他人のタスクの遷移は記録しない")
+
+		_, err = svc.ListTaskTransitions(ctx, task.ID.Hex(), "user2")
+		assert.True(t, apperrors.IsPermissionDenied(err))
+	})
+}
+
+func TestTaskService_UpdateTask_BuiltinStatusWithCustomWorkflow(t *testing.T) {
+	ctx := context.Background()
+	svc, _, workflows := newWorkflowTestService()
+	_, err := workflows.Save(ctx, reviewWorkflow("user1"))
+	require.NoError(t, err)
+
+	// 組み込みのステータスで作成すると分類の最初のステータスになる
+	task, err := svc.CreateTask(ctx, &model.Task{UserID: "user1", Title: "task", Status: model.TaskStatusActive, DueDate: testDueDate})
+	require.NoError(t, err)
+	assert.Equal(t, "DOING", task.WorkflowStatus)
+
+	// 組み込みのステータスが変わらない更新は現在のステータスを維持する
+	task, _, err = svc.TransitionTask(ctx, task.ID.Hex(), "user1", "REVIEW", "")
+	require.NoError(t, err)
+	task.Title = "renamed"
+	task.WorkflowStatus = ""
+	task, err = svc.UpdateTask(ctx, task.ID.Hex(), task)
+	require.NoError(t, err)
+	assert.Equal(t, "REVIEW", task.WorkflowStatus)
+
+	task.Status = model.TaskStatusComplete
+	task.WorkflowStatus = ""
+	task, err = svc.UpdateTask(ctx, task.ID.Hex(), task)
+	require.NoError(t, err)
+	assert.Equal(t, "DONE", task.WorkflowStatus)
+}
+
+func TestWorkflowService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("default_when_undefined", func(t *testing.T) {
+		svc := NewWorkflowService(&memoryWorkflowRepository{})
+		workflow, err := svc.GetWorkflow(ctx, "user1")
+		require.NoError(t, err)
+		assert.True(t, workflow.IsDefault())
+		assert.Nil(t, workflow.Transition(string(model.TaskStatusComplete), string(model.TaskStatusPending)))
+	})
+
+	t.Run("put_and_reset", func(t *testing.T) {
+		svc := NewWorkflowService(&memoryWorkflowRepository{})
+		saved, err := svc.PutWorkflow(ctx, reviewWorkflow("user1"))
+		require.NoError(t, err)
+		assert.False(t, saved.IsDefault())
+
+		workflow, err := svc.GetWorkflow(ctx, "user1")
+		require.NoError(t, err)
+		assert.Equal(t, "BACKLOG", workflow.InitialStatus)
+
+		workflow, err = svc.ResetWorkflow(ctx, "user1")
+		require.NoError(t, err)
+		assert.True(t, workflow.IsDefault())
+
+		_, err = svc.ResetWorkflow(ctx, "user1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cases := map[string]func(w *model.Workflow){
+			"lowercase_key":      func(w *model.Workflow) { w.Statuses[0].Key = "backlog" },
+			"duplicate_key":      func(w *model.Workflow) { w.Statuses[1].Key = "BACKLOG" },
+			"unknown_initial":    func(w *model.Workflow) { w.InitialStatus = "NONE" },
+			"unknown_transition": func(w *model.Workflow) { w.Transitions[0].To = "NONE" },
+			"invalid_category":   func(w *model.Workflow) { w.Statuses[0].Category = "blocked" },
+			"invalid_guard": func(w *model.Workflow) {
+				w.Transitions[0].Guards = []model.TransitionGuard{"approved"}
+			},
+		}
+		for name, mutate := range cases {
+			t.Run(name, func(t *testing.T) {
+				svc := NewWorkflowService(&memoryWorkflowRepository{})
+				workflow := reviewWorkflow("user1")
+				mutate(workflow)
+				_, err := svc.PutWorkflow(ctx, workflow)
+				assert.True(t, apperrors.IsInvalidInput(err))
+			})
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHistorySize は再開のために保持する直近のイベントの件数です
+	DefaultHistorySize = 1024
+	// subscriberBuffer は購読者ごとに溜めておけるイベントの件数です。超えた購読者は打ち切ります
+	subscriberBuffer = 256
+)
+
+// Bus はプロセス内でタスクの変更を配信するイベントバスです。
+// 再開トークンは直近 DefaultHistorySize 件のイベントの範囲で有効で、プロセスを再起動すると無効になります
+type Bus struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	history     []busEntry
+	historySize int
+	subscribers map[*busSubscriber]struct{}
+	now         func() time.Time
+}
+
+type busEntry struct {
+	seq   uint64
+	event Event
+}
+
+type busSubscriber struct {
+	userID string
+	sub    *Subscription
+}
+
+// NewBus は直近 historySize 件のイベントを保持する Bus を作成します。0 以下の場合は DefaultHistorySize を使います
+func NewBus(historySize int) *Bus {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	return &Bus{
+		// 再起動前のプロセスが発行したトークンを区別するため、起動ごとに異なる値をトークンに含める
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: historySize,
+		subscribers: make(map[*busSubscriber]struct{}),
+		now:         time.Now,
+	}
+}
+
+// Publish はイベントに再開トークンを付けて購読者へ配信します。受信が追いついていない購読者は打ち切ります
+func (b *Bus) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.Token = b.token(b.seq)
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = b.now()
+	}
+	b.history = append(b.history, busEntry{seq: b.seq, event: event})
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for s := range b.subscribers {
+		if s.userID != event.UserID {
+			continue
+		}
+		select {
+		case s.sub.events <- event:
+		default:
+			delete(b.subscribers, s)
+			s.sub.finish(ErrSubscriberTooSlow)
+		}
+	}
+}
+
+func (b *Bus) Subscribe(ctx context.Context, userID string, resumeToken string) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if resumeToken != "" {
+		seq, err := b.parseToken(resumeToken)
+		if err != nil {
+			return nil, err
+		}
+		// トークンの次のイベントが履歴から消えている場合は取りこぼしが生じるため再開できない
+		if seq < b.seq && (len(b.history) == 0 || b.history[0].seq > seq+1) {
+			return nil, ErrResumeTokenExpired
+		}
+		for _, entry := range b.history {
+			if entry.seq > seq && entry.event.UserID == userID {
+				replay = append(replay, entry.event)
+			}
+		}
+	}
+
+	s := &busSubscriber{userID: userID, sub: newSubscription(subscriberBuffer + len(replay))}
+	for _, event := range replay {
+		s.sub.events <- event
+	}
+	b.subscribers[s] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// 受信が追いつかず既に打ち切った購読者は登録されていない
+		if _, ok := b.subscribers[s]; ok {
+			delete(b.subscribers, s)
+			s.sub.finish(nil)
+		}
+	}()
+	return s.sub, nil
+}
+
+func (b *Bus) token(seq uint64) string {
+	return fmt.Sprintf("%s.%d", b.epoch, seq)
+}
+
+func (b *Bus) parseToken(token string) (uint64, error) {
+	epoch, seqText, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, ErrInvalidResumeToken
+	}
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidResumeToken
+	}
+	// 再起動前のプロセスのイベントは保持していない
+	if epoch != b.epoch {
+		return 0, ErrResumeTokenExpired
+	}
+	if seq > b.seq {
+		return 0, ErrInvalidResumeToken
+	}
+	return seq, nil
+}
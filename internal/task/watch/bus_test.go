@@ -0,0 +1,119 @@
+package watch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		require.True(t, ok, "購読が終了しています: %v", sub.Err())
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("イベントを受信できませんでした")
+		return Event{}
+	}
+}
+
+func assertNoEvent(t *testing.T, sub *Subscription) {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		t.Fatalf("予期しないイベントを受信しました: %+v", event)
+	default:
+	}
+}
+
+func TestBus_PublishDeliversOnlyOwnTasks(t *testing.T) {
+	bus := NewBus(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub, err := bus.Subscribe(ctx, "user1", "")
+	require.NoError(t, err)
+
+	taskID := primitive.NewObjectID()
+	bus.Publish(Event{Type: EventCreated, TaskID: primitive.NewObjectID(), UserID: "user2"})
+	bus.Publish(Event{Type: EventCreated, TaskID: taskID, UserID: "user1"})
+
+	event := receive(t, sub)
+	assert.Equal(t, EventCreated, event.Type)
+	assert.Equal(t, taskID, event.TaskID)
+	assert.NotEmpty(t, event.Token)
+	assert.False(t, event.OccurredAt.IsZero())
+	assertNoEvent(t, sub)
+
+	cancel()
+	_, ok := <-sub.Events()
+	for ok {
+		_, ok = <-sub.Events()
+	}
+	assert.NoError(t, sub.Err())
+}
+
+func TestBus_Resume(t *testing.T) {
+	bus := NewBus(4)
+	ctx := context.Background()
+
+	sub, err := bus.Subscribe(ctx, "user1", "")
+	require.NoError(t, err)
+	first := primitive.NewObjectID()
+	bus.Publish(Event{Type: EventCreated, TaskID: first, UserID: "user1"})
+	token := receive(t, sub).Token
+
+	second := primitive.NewObjectID()
+	bus.Publish(Event{Type: EventUpdated, TaskID: first, UserID: "user1"})
+	bus.Publish(Event{Type: EventCreated, TaskID: second, UserID: "user2"})
+	bus.Publish(Event{Type: EventDeleted, TaskID: first, UserID: "user1"})
+
+	t.Run("replays_missed_events", func(t *testing.T) {
+		resumed, err := bus.Subscribe(ctx, "user1", token)
+		require.NoError(t, err)
+		assert.Equal(t, EventUpdated, receive(t, resumed).Type)
+		assert.Equal(t, EventDeleted, receive(t, resumed).Type)
+		assertNoEvent(t, resumed)
+	})
+
+	t.Run("expired_after_history_is_trimmed", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			bus.Publish(Event{Type: EventUpdated, TaskID: second, UserID: "user2"})
+		}
+		_, err := bus.Subscribe(ctx, "user1", token)
+		assert.ErrorIs(t, err, ErrResumeTokenExpired)
+	})
+
+	t.Run("token_from_previous_process", func(t *testing.T) {
+		_, err := NewBus(0).Subscribe(ctx, "user1", token)
+		assert.ErrorIs(t, err, ErrResumeTokenExpired)
+	})
+
+	t.Run("invalid_token", func(t *testing.T) {
+		for _, token := range []string{"garbage", bus.epoch + ".x", bus.epoch + ".999"} {
+			_, err := bus.Subscribe(ctx, "user1", token)
+			assert.ErrorIs(t, err, ErrInvalidResumeToken, token)
+		}
+	})
+}
+
+func TestBus_SlowSubscriberIsDropped(t *testing.T) {
+	bus := NewBus(0)
+	sub, err := bus.Subscribe(context.Background(), "user1", "")
+	require.NoError(t, err)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		bus.Publish(Event{Type: EventUpdated, TaskID: primitive.NewObjectID(), UserID: "user1"})
+	}
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+	assert.ErrorIs(t, sub.Err(), ErrSubscriberTooSlow)
+}
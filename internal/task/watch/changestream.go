@@ -0,0 +1,154 @@
+package watch
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 再開トークンの位置の変更履歴が oplog から消えていることを表すサーバーのエラーコードです
+const (
+	errCodeChangeStreamHistoryLost = 286
+	errCodeChangeStreamFatal       = 280
+)
+
+// ChangeStreamSource は MongoDB の変更ストリームでタスクの変更を配信します。
+// 削除されたタスクの所有者を判定するため、tasks コレクションの変更前イメージ（MongoDB 6.0 以降）を有効にしておく必要があります
+type ChangeStreamSource struct {
+	collection *mongo.Collection
+}
+
+func NewChangeStreamSource(db *mongo.Database) *ChangeStreamSource {
+	return &ChangeStreamSource{collection: db.Collection("tasks")}
+}
+
+// SupportsChangeStreams は接続先が変更ストリームを使えるか（レプリカセットまたはシャードクラスタか）を返します
+func SupportsChangeStreams(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
+
+// EnablePreImages は tasks コレクションの変更前イメージを有効にします
+func EnablePreImages(ctx context.Context, db *mongo.Database) error {
+	return db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: "tasks"},
+		{Key: "changeStreamPreAndPostImages", Value: bson.M{"enabled": true}},
+	}).Err()
+}
+
+// changeDocument は変更ストリームのイベントです
+type changeDocument struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument             *model.Task `bson:"fullDocument"`
+	FullDocumentBeforeChange *model.Task `bson:"fullDocumentBeforeChange"`
+	WallTime                 time.Time   `bson:"wallTime"`
+}
+
+func (s *ChangeStreamSource) Subscribe(ctx context.Context, userID string, resumeToken string) (*Subscription, error) {
+	opts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+	if resumeToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(resumeToken)
+		if err != nil || bson.Raw(raw).Validate() != nil {
+			return nil, ErrInvalidResumeToken
+		}
+		opts.SetResumeAfter(bson.Raw(raw))
+	}
+
+	stream, err := s.collection.Watch(ctx, changePipeline(userID), opts)
+	if err != nil {
+		return nil, convertChangeStreamError(err)
+	}
+
+	sub := newSubscription(subscriberBuffer)
+	go func() {
+		defer stream.Close(context.Background())
+		for stream.Next(ctx) {
+			var change changeDocument
+			if err := stream.Decode(&change); err != nil {
+				sub.finish(apperrors.NewInternalError("変更ストリームのデコードに失敗しました", err))
+				return
+			}
+			event, ok := changeToEvent(&change, userID)
+			if !ok {
+				continue
+			}
+			event.Token = base64.RawURLEncoding.EncodeToString(stream.ResumeToken())
+			select {
+			case sub.events <- event:
+			case <-ctx.Done():
+			}
+		}
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			sub.finish(convertChangeStreamError(err))
+			return
+		}
+		sub.finish(nil)
+	}()
+	return sub, nil
+}
+
+// changePipeline は userID のユーザーのタスクの変更だけを取り出すパイプラインです。
+// 削除のイベントには変更後のドキュメントがないため、変更前イメージの所有者で判定します
+func changePipeline(userID string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}},
+			"$or": bson.A{
+				bson.M{"fullDocument.user_id": userID},
+				bson.M{"fullDocumentBeforeChange.user_id": userID},
+			},
+		}}},
+	}
+}
+
+func changeToEvent(change *changeDocument, userID string) (Event, bool) {
+	event := Event{TaskID: change.DocumentKey.ID, UserID: userID, OccurredAt: change.WallTime}
+	switch change.OperationType {
+	case "insert":
+		event.Type = EventCreated
+		event.Task = change.FullDocument
+	case "update", "replace":
+		// 更新の直後に削除されたタスクは変更後のドキュメントを取得できないため、削除のイベントで配信する
+		if change.FullDocument == nil {
+			return Event{}, false
+		}
+		event.Type = EventUpdated
+		event.Task = change.FullDocument
+	case "delete":
+		event.Type = EventDeleted
+	default:
+		return Event{}, false
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	return event, true
+}
+
+func convertChangeStreamError(err error) error {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && (serverErr.HasErrorCode(errCodeChangeStreamHistoryLost) || serverErr.HasErrorCode(errCodeChangeStreamFatal)) {
+		return ErrResumeTokenExpired
+	}
+	return apperrors.NewInternalError("変更ストリームの購読に失敗しました", err)
+}
@@ -0,0 +1,63 @@
+package watch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestChangeToEvent(t *testing.T) {
+	taskID := primitive.NewObjectID()
+	task := &model.Task{ID: taskID, UserID: "user1"}
+	newChange := func(op string, full *model.Task) *changeDocument {
+		change := &changeDocument{OperationType: op, FullDocument: full}
+		change.DocumentKey.ID = taskID
+		return change
+	}
+
+	tests := []struct {
+		name     string
+		change   *changeDocument
+		wantType EventType
+		wantOK   bool
+	}{
+		{name: "insert", change: newChange("insert", task), wantType: EventCreated, wantOK: true},
+		{name: "update", change: newChange("update", task), wantType: EventUpdated, wantOK: true},
+		{name: "replace", change: newChange("replace", task), wantType: EventUpdated, wantOK: true},
+		{name: "update_of_deleted_task", change: newChange("update", nil), wantOK: false},
+		{name: "delete", change: newChange("delete", nil), wantType: EventDeleted, wantOK: true},
+		{name: "drop", change: newChange("drop", nil), wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, ok := changeToEvent(tt.change, "user1")
+			assert.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.wantType, event.Type)
+			assert.Equal(t, taskID, event.TaskID)
+			assert.Equal(t, "user1", event.UserID)
+			assert.Equal(t, tt.wantType == EventDeleted, event.Task == nil)
+		})
+	}
+}
+
+func TestChangePipeline(t *testing.T) {
+	pipeline := changePipeline("user1")
+	match := pipeline[0][0].Value.(bson.M)
+	assert.Len(t, match["$or"], 2, "削除は変更前イメージの所有者で判定する")
+}
+
+func TestConvertChangeStreamError(t *testing.T) {
+	lost := mongo.CommandError{Code: errCodeChangeStreamHistoryLost, Message: "history lost"}
+	assert.ErrorIs(t, convertChangeStreamError(lost), ErrResumeTokenExpired)
+	assert.True(t, apperrors.IsInternal(convertChangeStreamError(errors.New("connection reset"))))
+}
@@ -0,0 +1,170 @@
+package watch
+
+import (
+	"context"
+
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// publishingRepository はタスクを書き込んだ後に変更を Bus へ配信する TaskRepository です
+type publishingRepository struct {
+	repository.TaskRepository
+	bus *Bus
+}
+
+// NewPublishingRepository は repo への書き込みを bus へ配信する TaskRepository を返します。
+// 変更ストリームを使わない場合に、タスクを書き込むすべての処理でこのリポジトリを使います
+func NewPublishingRepository(repo repository.TaskRepository, bus *Bus) repository.TaskRepository {
+	return &publishingRepository{TaskRepository: repo, bus: bus}
+}
+
+func (r *publishingRepository) Create(ctx context.Context, task *model.Task) (*model.Task, error) {
+	created, err := r.TaskRepository.Create(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	r.publish(ctx, EventCreated, created)
+	return created, nil
+}
+
+func (r *publishingRepository) Update(ctx context.Context, id string, task *model.Task) (*model.Task, error) {
+	updated, err := r.TaskRepository.Update(ctx, id, task)
+	if err != nil {
+		return nil, err
+	}
+	r.publish(ctx, EventUpdated, updated)
+	return updated, nil
+}
+
+func (r *publishingRepository) Delete(ctx context.Context, id string) error {
+	// 削除したタスクの所有者へ配信するため、削除前に取得しておく
+	task, err := r.TaskRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.TaskRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.send(ctx, Event{Type: EventDeleted, TaskID: task.ID, UserID: task.UserID})
+	return nil
+}
+
+func (r *publishingRepository) SetParent(ctx context.Context, id string, parentID primitive.ObjectID) (*model.Task, error) {
+	updated, err := r.TaskRepository.SetParent(ctx, id, parentID)
+	if err != nil {
+		return nil, err
+	}
+	r.publish(ctx, EventUpdated, updated)
+	return updated, nil
+}
+
+func (r *publishingRepository) ReparentChildren(ctx context.Context, fromID primitive.ObjectID, toID primitive.ObjectID) error {
+	children, err := r.TaskRepository.FindChildren(ctx, fromID.Hex())
+	if err != nil {
+		return err
+	}
+	if err := r.TaskRepository.ReparentChildren(ctx, fromID, toID); err != nil {
+		return err
+	}
+	for _, child := range children {
+		updated, err := r.TaskRepository.FindByID(ctx, child.ID.Hex())
+		if err != nil {
+			// 移動の直後に削除されたサブタスクは削除のイベントで配信される
+			continue
+		}
+		r.publish(ctx, EventUpdated, updated)
+	}
+	return nil
+}
+
+func (r *publishingRepository) SetRank(ctx context.Context, id string, rank string) (*model.Task, error) {
+	updated, err := r.TaskRepository.SetRank(ctx, id, rank)
+	if err != nil {
+		return nil, err
+	}
+	r.publish(ctx, EventUpdated, updated)
+	return updated, nil
+}
+
+// RebalanceRanks はランクを書き換えたタスクをそれぞれ更新として配信します
+func (r *publishingRepository) RebalanceRanks(ctx context.Context, userID string) ([]primitive.ObjectID, error) {
+	changed, err := r.TaskRepository.RebalanceRanks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		return changed, nil
+	}
+	tasks, err := r.TaskRepository.FindByIDs(ctx, changed)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		r.publish(ctx, EventUpdated, task)
+	}
+	return changed, nil
+}
+
+// BulkCreate などの一括操作は成功した項目だけを配信します。
+// アトミックな一括操作の中ではトランザクションの確定後に配信し、取り消された場合は配信しません
+func (r *publishingRepository) BulkCreate(ctx context.Context, tasks []*model.Task) ([]error, error) {
+	errs, err := r.TaskRepository.BulkCreate(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	for i, task := range tasks {
+		if errs[i] == nil {
+			r.publish(ctx, EventCreated, task)
+		}
+	}
+	return errs, nil
+}
+
+func (r *publishingRepository) BulkUpdate(ctx context.Context, tasks []*model.Task) ([]error, error) {
+	errs, err := r.TaskRepository.BulkUpdate(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	for i, task := range tasks {
+		if errs[i] == nil {
+			r.publish(ctx, EventUpdated, task)
+		}
+	}
+	return errs, nil
+}
+
+func (r *publishingRepository) BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]error, error) {
+	// 削除したタスクの所有者へ配信するため、削除前に取得しておく
+	tasks, err := r.TaskRepository.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[primitive.ObjectID]string, len(tasks))
+	for _, task := range tasks {
+		owners[task.ID] = task.UserID
+	}
+	errs, err := r.TaskRepository.BulkDelete(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range ids {
+		if userID, ok := owners[id]; ok && errs[i] == nil {
+			r.send(ctx, Event{Type: EventDeleted, TaskID: id, UserID: userID})
+		}
+	}
+	return errs, nil
+}
+
+func (r *publishingRepository) publish(ctx context.Context, eventType EventType, task *model.Task) {
+	r.send(ctx, Event{Type: eventType, TaskID: task.ID, UserID: task.UserID, Task: task, OccurredAt: task.UpdatedAt})
+}
+
+// send は event を bus へ配信します。Bus はトランザクションに参加しないため、トランザクション内の書き込みは確定してから配信します
+func (r *publishingRepository) send(ctx context.Context, event Event) {
+	repository.AfterCommit(ctx, func(context.Context) {
+		r.bus.Publish(event)
+	})
+}
@@ -0,0 +1,213 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/my-backend-project/internal/task/model"
+	"github.com/my-backend-project/internal/task/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeTaskRepository はテスト用のインメモリ実装です
+type fakeTaskRepository struct {
+	repository.TaskRepository
+	tasks map[string]*model.Task
+}
+
+func (r *fakeTaskRepository) Create(_ context.Context, task *model.Task) (*model.Task, error) {
+	task.ID = primitive.NewObjectID()
+	r.tasks[task.ID.Hex()] = task
+	return task, nil
+}
+
+func (r *fakeTaskRepository) FindByID(_ context.Context, id string) (*model.Task, error) {
+	task, ok := r.tasks[id]
+	if !ok {
+		return nil, repository.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (r *fakeTaskRepository) Update(_ context.Context, id string, task *model.Task) (*model.Task, error) {
+	if _, ok := r.tasks[id]; !ok {
+		return nil, repository.ErrTaskNotFound
+	}
+	r.tasks[id] = task
+	return task, nil
+}
+
+func (r *fakeTaskRepository) Delete(_ context.Context, id string) error {
+	if _, ok := r.tasks[id]; !ok {
+		return repository.ErrTaskNotFound
+	}
+	delete(r.tasks, id)
+	return nil
+}
+
+func (r *fakeTaskRepository) FindByIDs(_ context.Context, ids []primitive.ObjectID) ([]*model.Task, error) {
+	var tasks []*model.Task
+	for _, id := range ids {
+		if task, ok := r.tasks[id.Hex()]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *fakeTaskRepository) BulkCreate(ctx context.Context, tasks []*model.Task) ([]error, error) {
+	errs := make([]error, len(tasks))
+	for i, task := range tasks {
+		if task.Title == "" {
+			errs[i] = errors.New("タイトルは必須です")
+			continue
+		}
+		_, _ = r.Create(ctx, task)
+	}
+	return errs, nil
+}
+
+func (r *fakeTaskRepository) BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]error, error) {
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		errs[i] = r.Delete(ctx, id.Hex())
+	}
+	return errs, nil
+}
+
+func TestPublishingRepository(t *testing.T) {
+	ctx := context.Background()
+	bus := NewBus(0)
+	repo := NewPublishingRepository(&fakeTaskRepository{tasks: make(map[string]*model.Task)}, bus)
+	sub, err := bus.Subscribe(ctx, "user1", "")
+	require.NoError(t, err)
+
+	task, err := repo.Create(ctx, &model.Task{UserID: "user1", Title: "作成"})
+	require.NoError(t, err)
+	event := receive(t, sub)
+	assert.Equal(t, EventCreated, event.Type)
+	assert.Equal(t, "作成", event.Task.Title)
+
+	task.Title = "更新"
+	_, err = repo.Update(ctx, task.ID.Hex(), task)
+	require.NoError(t, err)
+	assert.Equal(t, EventUpdated, receive(t, sub).Type)
+
+	require.NoError(t, repo.Delete(ctx, task.ID.Hex()))
+	event = receive(t, sub)
+	assert.Equal(t, EventDeleted, event.Type)
+	assert.Equal(t, task.ID, event.TaskID)
+	assert.Nil(t, event.Task)
+
+	// 失敗した書き込みは配信しない
+	assert.Error(t, repo.Delete(ctx, task.ID.Hex()))
+	assertNoEvent(t, sub)
+}
+
+func (r *fakeTaskRepository) SetRank(_ context.Context, id string, rank string) (*model.Task, error) {
+	task, ok := r.tasks[id]
+	if !ok {
+		return nil, repository.ErrTaskNotFound
+	}
+	task.Rank = rank
+	return task, nil
+}
+
+func (r *fakeTaskRepository) RebalanceRanks(_ context.Context, userID string) ([]primitive.ObjectID, error) {
+	var changed []primitive.ObjectID
+	for _, task := range r.tasks {
+		if task.UserID == userID && task.Rank != "V" {
+			task.Rank = "V"
+			changed = append(changed, task.ID)
+		}
+	}
+	return changed, nil
+}
+
+func TestPublishingRepository_Ranks(t *testing.T) {
+	ctx := context.Background()
+	bus := NewBus(0)
+	repo := NewPublishingRepository(&fakeTaskRepository{tasks: make(map[string]*model.Task)}, bus)
+	first, err := repo.Create(ctx, &model.Task{UserID: "user1", Title: "first", Rank: "a"})
+	require.NoError(t, err)
+	second, err := repo.Create(ctx, &model.Task{UserID: "user1", Title: "second", Rank: "V"})
+	require.NoError(t, err)
+	sub, err := bus.Subscribe(ctx, "user1", "")
+	require.NoError(t, err)
+
+	_, err = repo.SetRank(ctx, second.ID.Hex(), "b")
+	require.NoError(t, err)
+	event := receive(t, sub)
+	assert.Equal(t, EventUpdated, event.Type)
+	assert.Equal(t, "b", event.Task.Rank)
+
+	// 並べ直しはランクを書き換えたタスクごとに配信する
+	changed, err := repo.RebalanceRanks(ctx, "user1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []primitive.ObjectID{first.ID, second.ID}, changed)
+	received := []primitive.ObjectID{receive(t, sub).TaskID, receive(t, sub).TaskID}
+	assert.ElementsMatch(t, changed, received)
+
+	changed, err = repo.RebalanceRanks(ctx, "user1")
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+	assertNoEvent(t, sub)
+
+	_, err = repo.SetRank(ctx, primitive.NewObjectID().Hex(), "c")
+	assert.Error(t, err)
+	assertNoEvent(t, sub)
+}
+
+func TestPublishingRepository_Bulk(t *testing.T) {
+	ctx := context.Background()
+	bus := NewBus(0)
+	repo := NewPublishingRepository(&fakeTaskRepository{tasks: make(map[string]*model.Task)}, bus)
+	sub, err := bus.Subscribe(ctx, "user1", "")
+	require.NoError(t, err)
+
+	tasks := []*model.Task{{UserID: "user1", Title: "作成"}, {UserID: "user1"}}
+	errs, err := repo.BulkCreate(ctx, tasks)
+	require.NoError(t, err)
+	assert.Error(t, errs[1])
+	assert.Equal(t, EventCreated, receive(t, sub).Type)
+	assertNoEvent(t, sub)
+
+	errs, err = repo.BulkDelete(ctx, []primitive.ObjectID{tasks[0].ID, primitive.NewObjectID()})
+	require.NoError(t, err)
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1])
+	event := receive(t, sub)
+	assert.Equal(t, EventDeleted, event.Type)
+	assert.Equal(t, tasks[0].ID, event.TaskID)
+	assertNoEvent(t, sub)
+}
+
+func TestPublishingRepository_Transaction(t *testing.T) {
+	ctx := context.Background()
+	bus := NewBus(0)
+	repo := NewPublishingRepository(&fakeTaskRepository{tasks: make(map[string]*model.Task)}, bus)
+	sub, err := bus.Subscribe(ctx, "user1", "")
+	require.NoError(t, err)
+
+	// 取り消されたトランザクションの書き込みは配信しない
+	txCtx, hooks := repository.WithCommitHooks(ctx)
+	_, err = repo.BulkCreate(txCtx, []*model.Task{{UserID: "user1", Title: "取り消し"}})
+	require.NoError(t, err)
+	hooks.Reset()
+	hooks.Run(ctx)
+	assertNoEvent(t, sub)
+
+	// 確定したトランザクションの書き込みは確定後に配信する
+	tasks := []*model.Task{{UserID: "user1", Title: "確定"}}
+	_, err = repo.BulkCreate(txCtx, tasks)
+	require.NoError(t, err)
+	assertNoEvent(t, sub)
+	hooks.Run(ctx)
+	event := receive(t, sub)
+	assert.Equal(t, EventCreated, event.Type)
+	assert.Equal(t, tasks[0].ID, event.TaskID)
+}
@@ -0,0 +1,84 @@
+// Package watch はユーザーのタスクの作成・更新・削除をイベントとして配信します。
+//
+// レプリカセットの MongoDB では変更ストリーム（ChangeStreamSource）を使い、どのレプリカで行った変更も配信します。
+// それ以外の環境ではプロセス内のイベントバス（Bus）を使い、同じプロセスのリポジトリで行った変更だけを配信します。
+// どちらもイベントごとに再開トークンを付けるため、再接続したクライアントは最後に受け取ったイベントの次から受信を再開できます。
+package watch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/my-backend-project/internal/pkg/apperrors"
+	"github.com/my-backend-project/internal/task/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EventType はタスクに対する変更の種類です
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event はタスクの変更です。Task は変更後のタスクで、削除の場合は nil です
+type Event struct {
+	Type   EventType
+	TaskID primitive.ObjectID
+	UserID string
+	Task   *model.Task
+	// Token はこのイベントの次から受信を再開するための再開トークンです
+	Token      string
+	OccurredAt time.Time
+}
+
+var (
+	// ErrInvalidResumeToken は再開トークンの形式が不正なことを表します
+	ErrInvalidResumeToken = apperrors.NewInvalidInputError("無効な再開トークンです", nil).WithReason("INVALID_RESUME_TOKEN")
+	// ErrResumeTokenExpired は再開トークン以降のイベントを配信できないことを表します。クライアントはタスク一覧を取得し直します
+	ErrResumeTokenExpired = apperrors.NewFailedPreconditionError("再開トークンの有効期限が切れています。タスク一覧を取得し直してください", nil).WithReason("RESUME_TOKEN_EXPIRED")
+	// ErrSubscriberTooSlow は購読者の受信が追いつかず購読を打ち切ったことを表します
+	ErrSubscriberTooSlow = apperrors.NewResourceExhaustedError("イベントの受信が追いついていません。再開トークンを指定して再接続してください", nil).WithReason("SUBSCRIBER_TOO_SLOW").WithRetryAfter(time.Second)
+)
+
+// Source はタスクの変更の配信元です
+type Source interface {
+	// Subscribe は userID のユーザーのタスクの変更の購読を開始します。
+	// resumeToken を指定するとそのトークンのイベントの次から配信します。購読は ctx がキャンセルされるまで続きます
+	Subscribe(ctx context.Context, userID string, resumeToken string) (*Subscription, error)
+}
+
+// Subscription はタスクの変更の購読です。購読が終わると Events のチャネルを閉じます
+type Subscription struct {
+	events chan Event
+	mu     sync.Mutex
+	err    error
+}
+
+func newSubscription(buffer int) *Subscription {
+	return &Subscription{events: make(chan Event, buffer)}
+}
+
+// Events は配信されたイベントのチャネルです
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err は購読が終わった理由です。コンテキストのキャンセルで終わった場合は nil です
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// finish は購読を err で終了し、チャネルを閉じます
+func (s *Subscription) finish(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+	close(s.events)
+}
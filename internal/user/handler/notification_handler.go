@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/my-backend-project/internal/user/model"
+	"github.com/my-backend-project/internal/user/service"
+)
+
+// HeaderInternalToken は内部APIの呼び出し元を認証するトークンのヘッダーです
+const HeaderInternalToken = "X-Internal-Token"
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// List は GET /api/notifications?unread=true&page_size=20&page_token=... を処理します
+func (h *NotificationHandler) List(c echo.Context) error {
+	var req model.ListNotificationsRequest
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	resp, err := h.notificationService.List(c.Request().Context(), currentUserID(c), &req)
+	if err != nil {
+		return notificationError(err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// MarkRead は POST /api/notifications/:id/read を処理します
+func (h *NotificationHandler) MarkRead(c echo.Context) error {
+	if err := h.notificationService.MarkRead(c.Request().Context(), currentUserID(c), c.Param("id")); err != nil {
+		return notificationError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// MarkAllRead は POST /api/notifications/read-all を処理します
+func (h *NotificationHandler) MarkAllRead(c echo.Context) error {
+	updated, err := h.notificationService.MarkAllRead(c.Request().Context(), currentUserID(c))
+	if err != nil {
+		return notificationError(err)
+	}
+	return c.JSON(http.StatusOK, map[string]int64{"updated": updated})
+}
+
+// Delete は DELETE /api/notifications/:id を処理します
+func (h *NotificationHandler) Delete(c echo.Context) error {
+	if err := h.notificationService.Delete(c.Request().Context(), currentUserID(c), c.Param("id")); err != nil {
+		return notificationError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetPreferences は GET /api/notification-preferences を処理します
+func (h *NotificationHandler) GetPreferences(c echo.Context) error {
+	preferences, err := h.notificationService.GetPreferences(c.Request().Context(), currentUserID(c))
+	if err != nil {
+		return notificationError(err)
+	}
+	return c.JSON(http.StatusOK, preferences)
+}
+
+// UpdatePreferences は PUT /api/notification-preferences を処理します
+func (h *NotificationHandler) UpdatePreferences(c echo.Context) error {
+	var req model.UpdateNotificationPreferencesRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(c.Request().Context(), currentUserID(c), &req)
+	if err != nil {
+		return notificationError(err)
+	}
+	return c.JSON(http.StatusOK, preferences)
+}
+
+// Create は内部API POST /internal/notifications を処理します。
+// ユーザーがイベントの通知を受け取らない設定の場合は登録せずに 204 を返します
+func (h *NotificationHandler) Create(c echo.Context) error {
+	var req model.CreateNotificationRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	notification, err := h.notificationService.Create(c.Request().Context(), &req)
+	if err != nil {
+		return notificationError(err)
+	}
+	if notification == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return c.JSON(http.StatusCreated, notification)
+}
+
+// InternalAuthMiddleware は内部APIのトークンを検証するミドルウェアを返します
+func InternalAuthMiddleware(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			given := c.Request().Header.Get(HeaderInternalToken)
+			if given == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid internal token")
+			}
+			return next(c)
+		}
+	}
+}
+
+// currentUserID は AuthMiddleware が設定したユーザーIDを返します
+func currentUserID(c echo.Context) string {
+	userID, _ := c.Get("user_id").(string)
+	return userID
+}
+
+func notificationError(err error) error {
+	switch err {
+	case service.ErrNotificationNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case service.ErrInvalidPageToken, service.ErrInvalidEvent:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "Internal server error")
+	}
+}
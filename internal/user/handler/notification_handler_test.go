@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/my-backend-project/internal/user/model"
+	"github.com/my-backend-project/internal/user/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockNotificationService はNotificationServiceのモック実装です
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) Create(ctx context.Context, req *model.CreateNotificationRequest) (*model.Notification, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationService) List(ctx context.Context, userID string, req *model.ListNotificationsRequest) (*model.ListNotificationsResponse, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ListNotificationsResponse), args.Error(1)
+}
+
+func (m *MockNotificationService) MarkRead(ctx context.Context, userID, id string) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationService) Delete(ctx context.Context, userID, id string) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) GetPreferences(ctx context.Context, userID string) (*model.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationService) UpdatePreferences(ctx context.Context, userID string, req *model.UpdateNotificationPreferencesRequest) (*model.NotificationPreferences, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.NotificationPreferences), args.Error(1)
+}
+
+// newAuthenticatedContext は AuthMiddleware を通過した状態のコンテキストを作成します
+func newAuthenticatedContext(e *echo.Echo, method, target, body string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", "user1")
+	return c, rec
+}
+
+func TestNotificationHandler_List(t *testing.T) {
+	e := echo.New()
+	mockService := new(MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+
+	t.Run("success", func(t *testing.T) {
+		resp := &model.ListNotificationsResponse{
+			Notifications: []*model.Notification{{ID: primitive.NewObjectID(), UserID: "user1", Title: "期限が近づいています"}},
+			UnreadCount:   1,
+		}
+		mockService.On("List", mock.Anything, "user1", &model.ListNotificationsRequest{UnreadOnly: true, PageSize: 10}).Return(resp, nil).Once()
+
+		c, rec := newAuthenticatedContext(e, http.MethodGet, "/api/notifications?unread=true&page_size=10", "")
+		assert.NoError(t, handler.List(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var body model.ListNotificationsResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Len(t, body.Notifications, 1)
+		assert.Equal(t, int64(1), body.UnreadCount)
+	})
+
+	t.Run("invalid_page_token", func(t *testing.T) {
+		mockService.On("List", mock.Anything, "user1", mock.Anything).Return(nil, service.ErrInvalidPageToken).Once()
+
+		c, _ := newAuthenticatedContext(e, http.MethodGet, "/api/notifications?page_token=invalid", "")
+		err := handler.List(c)
+		he, ok := err.(*echo.HTTPError)
+		if assert.True(t, ok) {
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		}
+	})
+}
+
+func TestNotificationHandler_MarkRead(t *testing.T) {
+	e := echo.New()
+	mockService := new(MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+
+	mockService.On("MarkRead", mock.Anything, "user1", "n1").Return(nil).Once()
+	c, rec := newAuthenticatedContext(e, http.MethodPost, "/api/notifications/n1/read", "")
+	c.SetParamNames("id")
+	c.SetParamValues("n1")
+	assert.NoError(t, handler.MarkRead(c))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	mockService.On("MarkRead", mock.Anything, "user1", "n2").Return(service.ErrNotificationNotFound).Once()
+	c, _ = newAuthenticatedContext(e, http.MethodPost, "/api/notifications/n2/read", "")
+	c.SetParamNames("id")
+	c.SetParamValues("n2")
+	he, ok := handler.MarkRead(c).(*echo.HTTPError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusNotFound, he.Code)
+	}
+}
+
+func TestNotificationHandler_Create(t *testing.T) {
+	e := echo.New()
+	mockValidator := new(MockValidator)
+	e.Validator = mockValidator
+	mockService := new(MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+	req := &model.CreateNotificationRequest{UserID: "user1", Event: model.EventDueSoon, Title: "期限が近づいています"}
+
+	t.Run("created", func(t *testing.T) {
+		mockValidator.On("Validate", mock.AnythingOfType("*model.CreateNotificationRequest")).Return(nil).Once()
+		mockService.On("Create", mock.Anything, req).Return(&model.Notification{ID: primitive.NewObjectID(), UserID: "user1"}, nil).Once()
+
+		jsonBytes, _ := json.Marshal(req)
+		httpReq := httptest.NewRequest(http.MethodPost, "/internal/notifications", bytes.NewReader(jsonBytes))
+		httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, handler.Create(e.NewContext(httpReq, rec)))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("disabled_by_preferences", func(t *testing.T) {
+		mockValidator.On("Validate", mock.AnythingOfType("*model.CreateNotificationRequest")).Return(nil).Once()
+		mockService.On("Create", mock.Anything, req).Return(nil, nil).Once()
+
+		jsonBytes, _ := json.Marshal(req)
+		httpReq := httptest.NewRequest(http.MethodPost, "/internal/notifications", bytes.NewReader(jsonBytes))
+		httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, handler.Create(e.NewContext(httpReq, rec)))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+}
+
+func TestInternalAuthMiddleware(t *testing.T) {
+	e := echo.New()
+	next := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	middleware := InternalAuthMiddleware("secret")
+
+	for name, tt := range map[string]struct {
+		token    string
+		wantCode int
+	}{
+		"valid":   {token: "secret", wantCode: http.StatusOK},
+		"invalid": {token: "wrong", wantCode: http.StatusUnauthorized},
+		"missing": {token: "", wantCode: http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/internal/notifications", nil)
+			if tt.token != "" {
+				req.Header.Set(HeaderInternalToken, tt.token)
+			}
+			rec := httptest.NewRecorder()
+			err := middleware(next)(e.NewContext(req, rec))
+			if tt.wantCode == http.StatusOK {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else if he, ok := err.(*echo.HTTPError); assert.True(t, ok) {
+				assert.Equal(t, tt.wantCode, he.Code)
+			}
+		})
+	}
+}
@@ -71,6 +71,40 @@ func (h *UserHandler) Login(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// GetProfile は GET /api/profile を処理します
+func (h *UserHandler) GetProfile(c echo.Context) error {
+	user, err := h.userService.GetProfile(c.Request().Context(), currentUserID(c))
+	if err != nil {
+		return profileError(err)
+	}
+	return c.JSON(http.StatusOK, user)
+}
+
+// UpdateProfile は PUT /api/profile を処理します
+func (h *UserHandler) UpdateProfile(c echo.Context) error {
+	var req model.UpdateProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := h.userService.UpdateProfile(c.Request().Context(), currentUserID(c), &req)
+	if err != nil {
+		return profileError(err)
+	}
+	return c.JSON(http.StatusOK, user)
+}
+
+func profileError(err error) error {
+	switch err {
+	case service.ErrUserNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case service.ErrInvalidTimeZone:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "Internal server error")
+	}
+}
+
 // JWT認証ミドルウェア
 func (h *UserHandler) AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -48,6 +48,22 @@ func (m *MockUserService) Login(ctx context.Context, req *model.LoginRequest) (*
 	return args.Get(0).(*model.AuthResponse), args.Error(1)
 }
 
+func (m *MockUserService) GetProfile(ctx context.Context, userID string) (*model.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserService) UpdateProfile(ctx context.Context, userID string, req *model.UpdateProfileRequest) (*model.User, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
 // MockJWTService はJWTServiceのモック実装です
 type MockJWTService struct {
 	mock.Mock
@@ -271,6 +287,77 @@ func TestUserHandler_Login(t *testing.T) {
 	}
 }
 
+func TestUserHandler_UpdateProfile(t *testing.T) {
+	e, mockService, mockJWT, _ := setupTest(t)
+	handler := NewUserHandler(mockService, mockJWT)
+
+	tests := []struct {
+		name         string
+		request      *model.UpdateProfileRequest
+		setup        func()
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:    "successful update",
+			request: &model.UpdateProfileRequest{TimeZone: "Asia/Tokyo"},
+			setup: func() {
+				user := &model.User{Email: "test@example.com", TimeZone: "Asia/Tokyo"}
+				mockService.On("UpdateProfile", mock.Anything, "user123", &model.UpdateProfileRequest{TimeZone: "Asia/Tokyo"}).Return(user, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:    "invalid time zone",
+			request: &model.UpdateProfileRequest{TimeZone: "Nowhere/City"},
+			setup: func() {
+				mockService.On("UpdateProfile", mock.Anything, "user123", mock.AnythingOfType("*model.UpdateProfileRequest")).Return(nil, service.ErrInvalidTimeZone).Once()
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  service.ErrInvalidTimeZone.Error(),
+		},
+		{
+			name:    "user not found",
+			request: &model.UpdateProfileRequest{TimeZone: "Asia/Tokyo"},
+			setup: func() {
+				mockService.On("UpdateProfile", mock.Anything, "user123", mock.AnythingOfType("*model.UpdateProfileRequest")).Return(nil, service.ErrUserNotFound).Once()
+			},
+			expectedCode: http.StatusNotFound,
+			expectedErr:  service.ErrUserNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.ExpectedCalls = nil
+			tt.setup()
+
+			jsonBytes, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest(http.MethodPut, "/api/profile", bytes.NewReader(jsonBytes))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", "user123")
+
+			err := handler.UpdateProfile(c)
+			if tt.expectedErr != "" {
+				if assert.Error(t, err) {
+					he, ok := err.(*echo.HTTPError)
+					if assert.True(t, ok, "expected HTTP error") {
+						assert.Equal(t, tt.expectedCode, he.Code)
+						assert.Equal(t, tt.expectedErr, he.Message)
+					}
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedCode, rec.Code)
+				assert.Contains(t, rec.Body.String(), `"time_zone":"Asia/Tokyo"`)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUserHandler_AuthMiddleware(t *testing.T) {
 	e, mockService, mockJWT, _ := setupTest(t)
 	handler := NewUserHandler(mockService, mockJWT)
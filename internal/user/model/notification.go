@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationEvent は通知のきっかけになったイベントの種類です
+type NotificationEvent string
+
+const (
+	EventTaskAssigned  NotificationEvent = "task_assigned"
+	EventTaskCommented NotificationEvent = "task_commented"
+	EventMentioned     NotificationEvent = "mentioned"
+	EventDueSoon       NotificationEvent = "due_soon"
+)
+
+// NotificationEvents は通知の設定で指定できるイベントの一覧です
+var NotificationEvents = []NotificationEvent{EventTaskAssigned, EventTaskCommented, EventMentioned, EventDueSoon}
+
+// IsValid は既知のイベントかを返します
+func (e NotificationEvent) IsValid() bool {
+	for _, event := range NotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type Notification struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"user_id"`
+	Event  NotificationEvent  `bson:"event" json:"event"`
+	Title  string             `bson:"title" json:"title"`
+	Body   string             `bson:"body,omitempty" json:"body,omitempty"`
+	TaskID string             `bson:"task_id,omitempty" json:"task_id,omitempty"`
+	// DeliveryKey は送信元が指定する一意なキーです。同じキーの通知は一度しか登録しません
+	DeliveryKey string     `bson:"delivery_key,omitempty" json:"-"`
+	ReadAt      *time.Time `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
+}
+
+// NotificationPreferences はユーザーごとの通知の設定です。DisabledEvents に含まれないイベントは通知します
+type NotificationPreferences struct {
+	UserID         string              `bson:"_id" json:"user_id"`
+	DisabledEvents []NotificationEvent `bson:"disabled_events" json:"disabled_events"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// Allows はイベントの通知を受け取る設定かを返します
+func (p *NotificationPreferences) Allows(event NotificationEvent) bool {
+	if p == nil {
+		return true
+	}
+	for _, disabled := range p.DisabledEvents {
+		if disabled == event {
+			return false
+		}
+	}
+	return true
+}
+
+type CreateNotificationRequest struct {
+	UserID      string            `json:"user_id" validate:"required"`
+	Event       NotificationEvent `json:"event" validate:"required"`
+	Title       string            `json:"title" validate:"required,max=200"`
+	Body        string            `json:"body" validate:"max=2000"`
+	TaskID      string            `json:"task_id"`
+	DeliveryKey string            `json:"delivery_key" validate:"max=200"`
+}
+
+type ListNotificationsRequest struct {
+	UnreadOnly bool   `query:"unread"`
+	PageSize   int32  `query:"page_size"`
+	PageToken  string `query:"page_token"`
+}
+
+type ListNotificationsResponse struct {
+	Notifications []*Notification `json:"notifications"`
+	UnreadCount   int64           `json:"unread_count"`
+	NextPageToken string          `json:"next_page_token,omitempty"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	DisabledEvents []NotificationEvent `json:"disabled_events"`
+}
@@ -7,11 +7,13 @@ import (
 )
 
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Email     string             `bson:"email" json:"email"`
-	Password  string             `bson:"password" json:"-"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Email    string             `bson:"email" json:"email"`
+	Password string             `bson:"password" json:"-"`
+	// TimeZone はユーザーのタイムゾーン（IANA）です。タスクサービスが「今日」や期限切れを判定するのに使い、空の場合は UTC です
+	TimeZone  string    `bson:"time_zone,omitempty" json:"time_zone,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
 }
 
 type SignUpRequest struct {
@@ -28,3 +30,8 @@ type AuthResponse struct {
 	Token string `json:"token"`
 	User  User   `json:"user"`
 }
+
+type UpdateProfileRequest struct {
+	// TimeZone は IANA のタイムゾーン名です。空の場合は設定を消します
+	TimeZone string `json:"time_zone"`
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// notificationIndexes は受信箱の一覧と未読件数の取得を支え、送信元のキーによる重複登録を防ぐインデックスです
+var notificationIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "_id", Value: -1}}},
+	{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "read_at", Value: 1}}},
+	{
+		Keys: bson.D{{Key: "delivery_key", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"delivery_key": bson.M{"$type": "string"}}),
+	},
+}
+
+// EnsureIndexes はユーザーサービスが使用するコレクションのインデックスを作成します
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	collections := map[string][]mongo.IndexModel{
+		"notifications": notificationIndexes,
+	}
+	for name, indexes := range collections {
+		if _, err := db.Collection(name).Indexes().CreateMany(ctx, indexes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/my-backend-project/internal/user/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository interface {
+	// Create は通知を登録します。DeliveryKey が登録済みの場合は既存の通知を返します
+	Create(ctx context.Context, notification *model.Notification) (*model.Notification, error)
+	// List はユーザーの通知を新しい順に返します。after を指定するとそれより古い通知を返します
+	List(ctx context.Context, userID string, unreadOnly bool, after primitive.ObjectID, limit int64) ([]*model.Notification, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// MarkRead は通知を既読にし、通知が見つかったかを返します
+	MarkRead(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	// Delete は通知を削除し、通知が見つかったかを返します
+	Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+}
+
+type mongoNotificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(db *mongo.Database) NotificationRepository {
+	return &mongoNotificationRepository{
+		collection: db.Collection("notifications"),
+	}
+}
+
+func (r *mongoNotificationRepository) Create(ctx context.Context, notification *model.Notification) (*model.Notification, error) {
+	notification.ID = primitive.NewObjectID()
+	notification.CreatedAt = time.Now()
+
+	if notification.DeliveryKey == "" {
+		if _, err := r.collection.InsertOne(ctx, notification); err != nil {
+			return nil, err
+		}
+		return notification, nil
+	}
+
+	var stored model.Notification
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"delivery_key": notification.DeliveryKey},
+		bson.M{"$setOnInsert": notification},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&stored)
+	if mongo.IsDuplicateKeyError(err) {
+		// 同じキーの通知が同時に登録された場合は先に登録された通知を返す
+		err = r.collection.FindOne(ctx, bson.M{"delivery_key": notification.DeliveryKey}).Decode(&stored)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (r *mongoNotificationRepository) List(ctx context.Context, userID string, unreadOnly bool, after primitive.ObjectID, limit int64) ([]*model.Notification, error) {
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read_at"] = nil
+	}
+	if !after.IsZero() {
+		filter["_id"] = bson.M{"$lt": after}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []*model.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *mongoNotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": nil})
+}
+
+func (r *mongoNotificationRepository) MarkRead(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "user_id": userID},
+		// 既読の日時は最初に既読にしたときのまま残す
+		bson.A{bson.M{"$set": bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", time.Now()}}}}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *mongoNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"user_id": userID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *mongoNotificationRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+type NotificationPreferenceRepository interface {
+	// Get はユーザーの通知の設定を返します。未設定の場合は nil を返します
+	Get(ctx context.Context, userID string) (*model.NotificationPreferences, error)
+	Save(ctx context.Context, preferences *model.NotificationPreferences) error
+}
+
+type mongoNotificationPreferenceRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationPreferenceRepository(db *mongo.Database) NotificationPreferenceRepository {
+	return &mongoNotificationPreferenceRepository{
+		collection: db.Collection("notification_preferences"),
+	}
+}
+
+func (r *mongoNotificationPreferenceRepository) Get(ctx context.Context, userID string) (*model.NotificationPreferences, error) {
+	var preferences model.NotificationPreferences
+	err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&preferences)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+func (r *mongoNotificationPreferenceRepository) Save(ctx context.Context, preferences *model.NotificationPreferences) error {
+	preferences.UpdatedAt = time.Now()
+	_, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"_id": preferences.UserID},
+		preferences,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
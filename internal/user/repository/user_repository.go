@@ -15,6 +15,8 @@ type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
 	FindByID(ctx context.Context, id string) (*model.User, error)
+	// UpdateTimeZone はユーザーのタイムゾーンを更新します。空の場合は設定を消します
+	UpdateTimeZone(ctx context.Context, id string, timeZone string) error
 }
 
 type mongoUserRepository struct {
@@ -64,3 +66,17 @@ func (r *mongoUserRepository) FindByID(ctx context.Context, id string) (*model.U
 	}
 	return &user, nil
 }
+
+func (r *mongoUserRepository) UpdateTimeZone(ctx context.Context, id string, timeZone string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"time_zone": timeZone, "updated_at": time.Now()}}
+	if timeZone == "" {
+		update = bson.M{"$set": bson.M{"updated_at": time.Now()}, "$unset": bson.M{"time_zone": ""}}
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	return err
+}
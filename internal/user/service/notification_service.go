@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/my-backend-project/internal/pkg/pagination"
+	"github.com/my-backend-project/internal/user/model"
+	"github.com/my-backend-project/internal/user/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrInvalidPageToken     = errors.New("invalid page token")
+	ErrInvalidEvent         = errors.New("unknown notification event")
+)
+
+type NotificationService interface {
+	// Create は通知を登録します。ユーザーがイベントの通知を受け取らない設定の場合は nil を返します
+	Create(ctx context.Context, req *model.CreateNotificationRequest) (*model.Notification, error)
+	List(ctx context.Context, userID string, req *model.ListNotificationsRequest) (*model.ListNotificationsResponse, error)
+	MarkRead(ctx context.Context, userID, id string) error
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	Delete(ctx context.Context, userID, id string) error
+	GetPreferences(ctx context.Context, userID string) (*model.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID string, req *model.UpdateNotificationPreferencesRequest) (*model.NotificationPreferences, error)
+}
+
+type notificationService struct {
+	repo        repository.NotificationRepository
+	preferences repository.NotificationPreferenceRepository
+	pageTokens  *pagination.Codec
+}
+
+func NewNotificationService(repo repository.NotificationRepository, preferences repository.NotificationPreferenceRepository, pageTokens *pagination.Codec) NotificationService {
+	return &notificationService{
+		repo:        repo,
+		preferences: preferences,
+		pageTokens:  pageTokens,
+	}
+}
+
+// notificationCursor は前のページの最後の通知です
+type notificationCursor struct {
+	LastID primitive.ObjectID `bson:"last_id"`
+}
+
+func (s *notificationService) Create(ctx context.Context, req *model.CreateNotificationRequest) (*model.Notification, error) {
+	if !req.Event.IsValid() {
+		return nil, ErrInvalidEvent
+	}
+
+	preferences, err := s.preferences.Get(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !preferences.Allows(req.Event) {
+		return nil, nil
+	}
+
+	return s.repo.Create(ctx, &model.Notification{
+		UserID:      req.UserID,
+		Event:       req.Event,
+		Title:       req.Title,
+		Body:        req.Body,
+		TaskID:      req.TaskID,
+		DeliveryKey: req.DeliveryKey,
+	})
+}
+
+func (s *notificationService) List(ctx context.Context, userID string, req *model.ListNotificationsRequest) (*model.ListNotificationsResponse, error) {
+	var cursor notificationCursor
+	if req.PageToken != "" {
+		if err := s.pageTokens.Decode(req.PageToken, &cursor); err != nil {
+			return nil, ErrInvalidPageToken
+		}
+	}
+
+	// 次のページの有無を判定するために1件多く取得する
+	pageSize := pagination.PageSize(req.PageSize)
+	notifications, err := s.repo.List(ctx, userID, req.UnreadOnly, cursor.LastID, int64(pageSize)+1)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.ListNotificationsResponse{Notifications: notifications}
+	if len(notifications) > int(pageSize) {
+		resp.Notifications = notifications[:pageSize]
+		token, err := s.pageTokens.Encode(notificationCursor{LastID: resp.Notifications[pageSize-1].ID})
+		if err != nil {
+			return nil, err
+		}
+		resp.NextPageToken = token
+	}
+
+	resp.UnreadCount, err = s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrNotificationNotFound
+	}
+	found, err := s.repo.MarkRead(ctx, userID, objectID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+func (s *notificationService) Delete(ctx context.Context, userID, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrNotificationNotFound
+	}
+	found, err := s.repo.Delete(ctx, userID, objectID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context, userID string) (*model.NotificationPreferences, error) {
+	preferences, err := s.preferences.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if preferences == nil {
+		preferences = &model.NotificationPreferences{UserID: userID}
+	}
+	if preferences.DisabledEvents == nil {
+		preferences.DisabledEvents = []model.NotificationEvent{}
+	}
+	return preferences, nil
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID string, req *model.UpdateNotificationPreferencesRequest) (*model.NotificationPreferences, error) {
+	disabled := []model.NotificationEvent{}
+	seen := make(map[model.NotificationEvent]bool)
+	for _, event := range req.DisabledEvents {
+		if !event.IsValid() {
+			return nil, ErrInvalidEvent
+		}
+		if !seen[event] {
+			seen[event] = true
+			disabled = append(disabled, event)
+		}
+	}
+
+	preferences := &model.NotificationPreferences{UserID: userID, DisabledEvents: disabled}
+	if err := s.preferences.Save(ctx, preferences); err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/my-backend-project/internal/pkg/pagination"
+	"github.com/my-backend-project/internal/user/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockNotificationRepository はNotificationRepositoryのモック実装です
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(ctx context.Context, notification *model.Notification) (*model.Notification, error) {
+	args := m.Called(ctx, notification)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) List(ctx context.Context, userID string, unreadOnly bool, after primitive.ObjectID, limit int64) ([]*model.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockNotificationPreferenceRepository はNotificationPreferenceRepositoryのモック実装です
+type MockNotificationPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepository) Get(ctx context.Context, userID string) (*model.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) Save(ctx context.Context, preferences *model.NotificationPreferences) error {
+	args := m.Called(ctx, preferences)
+	return args.Error(0)
+}
+
+func setupNotificationService() (NotificationService, *MockNotificationRepository, *MockNotificationPreferenceRepository) {
+	repo := new(MockNotificationRepository)
+	preferences := new(MockNotificationPreferenceRepository)
+	return NewNotificationService(repo, preferences, pagination.NewCodec([]byte("secret"))), repo, preferences
+}
+
+func TestNotificationService_Create(t *testing.T) {
+	ctx := context.Background()
+	req := &model.CreateNotificationRequest{UserID: "user1", Event: model.EventDueSoon, Title: "期限が近づいています", TaskID: "task1", DeliveryKey: "job1:inbox"}
+
+	t.Run("created", func(t *testing.T) {
+		service, repo, preferences := setupNotificationService()
+		preferences.On("Get", ctx, "user1").Return(nil, nil)
+		repo.On("Create", ctx, mock.MatchedBy(func(n *model.Notification) bool {
+			return n.UserID == "user1" && n.Event == model.EventDueSoon && n.DeliveryKey == "job1:inbox"
+		})).Return(&model.Notification{ID: primitive.NewObjectID(), UserID: "user1"}, nil)
+
+		notification, err := service.Create(ctx, req)
+		assert.NoError(t, err)
+		assert.NotNil(t, notification)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("disabled_by_preferences", func(t *testing.T) {
+		service, repo, preferences := setupNotificationService()
+		preferences.On("Get", ctx, "user1").Return(&model.NotificationPreferences{UserID: "user1", DisabledEvents: []model.NotificationEvent{model.EventDueSoon}}, nil)
+
+		notification, err := service.Create(ctx, req)
+		assert.NoError(t, err)
+		assert.Nil(t, notification)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown_event", func(t *testing.T) {
+		service, _, _ := setupNotificationService()
+		_, err := service.Create(ctx, &model.CreateNotificationRequest{UserID: "user1", Event: "unknown", Title: "title"})
+		assert.Equal(t, ErrInvalidEvent, err)
+	})
+}
+
+func TestNotificationService_List(t *testing.T) {
+	ctx := context.Background()
+	notifications := make([]*model.Notification, 3)
+	for i := range notifications {
+		notifications[i] = &model.Notification{ID: primitive.NewObjectID(), UserID: "user1"}
+	}
+
+	service, repo, _ := setupNotificationService()
+	repo.On("List", ctx, "user1", true, primitive.NilObjectID, int64(3)).Return(notifications, nil).Once()
+	repo.On("CountUnread", ctx, "user1").Return(int64(5), nil)
+
+	resp, err := service.List(ctx, "user1", &model.ListNotificationsRequest{UnreadOnly: true, PageSize: 2})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Notifications, 2)
+	assert.Equal(t, int64(5), resp.UnreadCount)
+	assert.NotEmpty(t, resp.NextPageToken)
+
+	repo.On("List", ctx, "user1", true, notifications[1].ID, int64(3)).Return(notifications[2:], nil).Once()
+	resp, err = service.List(ctx, "user1", &model.ListNotificationsRequest{UnreadOnly: true, PageSize: 2, PageToken: resp.NextPageToken})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Notifications, 1)
+	assert.Empty(t, resp.NextPageToken)
+
+	_, err = service.List(ctx, "user1", &model.ListNotificationsRequest{PageToken: "invalid"})
+	assert.Equal(t, ErrInvalidPageToken, err)
+}
+
+func TestNotificationService_MarkReadAndDelete(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := setupNotificationService()
+	id := primitive.NewObjectID()
+
+	repo.On("MarkRead", ctx, "user1", id).Return(true, nil)
+	assert.NoError(t, service.MarkRead(ctx, "user1", id.Hex()))
+
+	repo.On("Delete", ctx, "user2", id).Return(false, nil)
+	assert.Equal(t, ErrNotificationNotFound, service.Delete(ctx, "user2", id.Hex()))
+
+	assert.Equal(t, ErrNotificationNotFound, service.MarkRead(ctx, "user1", "invalid"))
+}
+
+func TestNotificationService_Preferences(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("defaults", func(t *testing.T) {
+		service, _, preferences := setupNotificationService()
+		preferences.On("Get", ctx, "user1").Return(nil, nil)
+
+		resp, err := service.GetPreferences(ctx, "user1")
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", resp.UserID)
+		assert.Empty(t, resp.DisabledEvents)
+		assert.True(t, resp.Allows(model.EventMentioned))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		service, _, preferences := setupNotificationService()
+		preferences.On("Save", ctx, mock.AnythingOfType("*model.NotificationPreferences")).Return(nil)
+
+		resp, err := service.UpdatePreferences(ctx, "user1", &model.UpdateNotificationPreferencesRequest{
+			DisabledEvents: []model.NotificationEvent{model.EventDueSoon, model.EventDueSoon},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.NotificationEvent{model.EventDueSoon}, resp.DisabledEvents)
+		assert.False(t, resp.Allows(model.EventDueSoon))
+
+		_, err = service.UpdatePreferences(ctx, "user1", &model.UpdateNotificationPreferencesRequest{
+			DisabledEvents: []model.NotificationEvent{"unknown"},
+		})
+		assert.Equal(t, ErrInvalidEvent, err)
+	})
+}
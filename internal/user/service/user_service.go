@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/my-backend-project/internal/user/auth"
 	"github.com/my-backend-project/internal/user/model"
@@ -18,11 +19,16 @@ var (
 	ErrInvalidEmail       = errors.New("invalid email format")
 	ErrPasswordTooShort   = errors.New("password must be at least 6 characters")
 	ErrInternalServer     = errors.New("internal server error")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidTimeZone    = errors.New("invalid time zone")
 )
 
 type UserService interface {
 	SignUp(ctx context.Context, req *model.SignUpRequest) (*model.AuthResponse, error)
 	Login(ctx context.Context, req *model.LoginRequest) (*model.AuthResponse, error)
+	GetProfile(ctx context.Context, userID string) (*model.User, error)
+	// UpdateProfile はユーザーのタイムゾーンを更新します
+	UpdateProfile(ctx context.Context, userID string, req *model.UpdateProfileRequest) (*model.User, error)
 }
 
 type userService struct {
@@ -95,3 +101,33 @@ func (s *userService) Login(ctx context.Context, req *model.LoginRequest) (*mode
 		User:  *user,
 	}, nil
 }
+
+func (s *userService) GetProfile(ctx context.Context, userID string) (*model.User, error) {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, userID string, req *model.UpdateProfileRequest) (*model.User, error) {
+	if req.TimeZone != "" {
+		// サーバーの設定に依存する Local は受け付けない
+		if _, err := time.LoadLocation(req.TimeZone); err != nil || req.TimeZone == "Local" {
+			return nil, ErrInvalidTimeZone
+		}
+	}
+
+	user, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTimeZone(ctx, userID, req.TimeZone); err != nil {
+		return nil, err
+	}
+	user.TimeZone = req.TimeZone
+	return user, nil
+}
@@ -38,6 +38,11 @@ func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*model.Us
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+func (m *MockUserRepository) UpdateTimeZone(ctx context.Context, id string, timeZone string) error {
+	args := m.Called(ctx, id, timeZone)
+	return args.Error(0)
+}
+
 // MockJWTService はJWTServiceのモック実装です
 type MockJWTService struct {
 	mock.Mock
@@ -187,3 +192,70 @@ func TestUserService_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	userID := primitive.NewObjectID().Hex()
+
+	tests := []struct {
+		name     string
+		timeZone string
+		setup    func(repo *MockUserRepository)
+		wantErr  error
+	}{
+		{
+			name:     "set time zone",
+			timeZone: "Asia/Tokyo",
+			setup: func(repo *MockUserRepository) {
+				repo.On("FindByID", ctx, userID).Return(&model.User{Email: "test@example.com"}, nil)
+				repo.On("UpdateTimeZone", ctx, userID, "Asia/Tokyo").Return(nil)
+			},
+		},
+		{
+			name:     "clear time zone",
+			timeZone: "",
+			setup: func(repo *MockUserRepository) {
+				repo.On("FindByID", ctx, userID).Return(&model.User{Email: "test@example.com", TimeZone: "Asia/Tokyo"}, nil)
+				repo.On("UpdateTimeZone", ctx, userID, "").Return(nil)
+			},
+		},
+		{
+			name:     "unknown time zone",
+			timeZone: "Mars/Olympus_Mons",
+			setup:    func(repo *MockUserRepository) {},
+			wantErr:  ErrInvalidTimeZone,
+		},
+		{
+			name:     "local time zone",
+			timeZone: "Local",
+			setup:    func(repo *MockUserRepository) {},
+			wantErr:  ErrInvalidTimeZone,
+		},
+		{
+			name:     "user not found",
+			timeZone: "Asia/Tokyo",
+			setup: func(repo *MockUserRepository) {
+				repo.On("FindByID", ctx, userID).Return(nil, nil)
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			tt.setup(mockRepo)
+			service := NewUserService(mockRepo, new(MockJWTService))
+
+			user, err := service.UpdateProfile(ctx, userID, &model.UpdateProfileRequest{TimeZone: tt.timeZone})
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, user)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.timeZone, user.TimeZone)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}